@@ -8,18 +8,18 @@ import (
 )
 
 // BuildURL creates the endpoint to be queried based on the data in the AccountsRequest struct.
-// Either "Signer" or "Asset" fields should be set when retrieving Accounts.
-// At the moment, you can't use both filters at the same time.
+// One of the "Signer", "Asset" or "Sponsor" fields should be set when retrieving Accounts.
+// At the moment, you can't use several filters at the same time.
 func (r AccountsRequest) BuildURL() (endpoint string, err error) {
 
-	nParams := countParams(r.Signer, r.Asset)
+	nParams := countParams(r.Signer, r.Asset, r.Sponsor)
 
 	if nParams <= 0 {
-		err = errors.New("invalid request: no parameters - Signer or Asset must be provided")
+		err = errors.New("invalid request: no parameters - Signer, Asset or Sponsor must be provided")
 	}
 
 	if nParams >= 2 {
-		err = errors.New("invalid request: too many parameters - Signer and Asset provided, provide a single filter")
+		err = errors.New("invalid request: too many parameters - Signer, Asset and Sponsor are exclusive, provide a single filter")
 	}
 
 	if err != nil {
@@ -32,6 +32,9 @@ func (r AccountsRequest) BuildURL() (endpoint string, err error) {
 
 	case len(r.Asset) > 0:
 		query.Add("asset", r.Asset)
+
+	case len(r.Sponsor) > 0:
+		query.Add("sponsor", r.Sponsor)
 	}
 
 	endpoint = fmt.Sprintf(
@@ -0,0 +1,136 @@
+package horizonclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+)
+
+// DefaultBatchConcurrency is the default maximum number of requests sent
+// concurrently to horizon by the batch methods, e.g. LoadAccounts.
+const DefaultBatchConcurrency = 10
+
+// BatchError is the error returned by the batch methods, e.g. LoadAccounts,
+// when some of their requests failed. The results of the requests which
+// succeeded are returned along with it.
+type BatchError struct {
+	// Errors maps the keys of the requests which failed, e.g. account IDs,
+	// to their error.
+	Errors map[string]error
+}
+
+func (e *BatchError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Sprintf("%d batch requests failed, first %s: %v", len(keys), keys[0], e.Errors[keys[0]])
+}
+
+// LoadAccounts returns the accounts with the given IDs, mapped by account ID,
+// sending at most BatchConcurrency requests to horizon at the same time. When
+// some accounts cannot be loaded, e.g. because they do not exist, the accounts
+// which were loaded are returned along with a *BatchError.
+func (c *Client) LoadAccounts(ctx context.Context, accountIDs []string) (map[string]hProtocol.Account, error) {
+	accounts := make([]hProtocol.Account, len(accountIDs))
+	err := c.batch(ctx, accountIDs, func(ctx context.Context, i int) (err error) {
+		accounts[i], err = c.AccountDetailContext(ctx, AccountRequest{AccountID: accountIDs[i]})
+		return
+	})
+
+	loaded := make(map[string]hProtocol.Account, len(accountIDs))
+	for i, accountID := range accountIDs {
+		if _, failed := batchErrors(err)[accountID]; !failed {
+			loaded[accountID] = accounts[i]
+		}
+	}
+	return loaded, err
+}
+
+// AssetStatsForIssuers returns the stats of all the assets issued by the
+// given issuers, mapped by issuer, sending at most BatchConcurrency requests
+// to horizon at the same time. When the assets of some issuers cannot be
+// loaded, the assets of the other issuers are returned along with a
+// *BatchError.
+func (c *Client) AssetStatsForIssuers(ctx context.Context, issuers []string) (map[string][]hProtocol.AssetStat, error) {
+	assets := make([][]hProtocol.AssetStat, len(issuers))
+	err := c.batch(ctx, issuers, func(ctx context.Context, i int) error {
+		return c.IterateAssets(ctx, AssetRequest{ForAssetIssuer: issuers[i]}, func(asset hProtocol.AssetStat) error {
+			assets[i] = append(assets[i], asset)
+			return nil
+		})
+	})
+
+	loaded := make(map[string][]hProtocol.AssetStat, len(issuers))
+	for i, issuer := range issuers {
+		if _, failed := batchErrors(err)[issuer]; !failed {
+			loaded[issuer] = assets[i]
+		}
+	}
+	return loaded, err
+}
+
+// batch calls fn with the index of every key in `keys`, at most
+// BatchConcurrency calls at the same time, and returns a *BatchError mapping
+// the keys for which fn failed to their error.
+func (c *Client) batch(ctx context.Context, keys []string, fn func(ctx context.Context, i int) error) error {
+	concurrency := c.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		errs      = map[string]error{}
+		semaphore = make(chan struct{}, concurrency)
+	)
+	for i := range keys {
+		acquired := false
+		if ctx.Err() == nil {
+			select {
+			case semaphore <- struct{}{}:
+				acquired = true
+			case <-ctx.Done():
+			}
+		}
+		if !acquired {
+			mutex.Lock()
+			errs[keys[i]] = ctx.Err()
+			mutex.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			if err := fn(ctx, i); err != nil {
+				mutex.Lock()
+				errs[keys[i]] = err
+				mutex.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &BatchError{Errors: errs}
+	}
+	return nil
+}
+
+// batchErrors returns the errors of the requests of a batch which failed
+// with `err`.
+func batchErrors(err error) map[string]error {
+	if batchErr, ok := err.(*BatchError); ok {
+		return batchErr.Errors
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package horizonclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAccounts(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:       "https://localhost/",
+		HTTP:             hmock,
+		BatchConcurrency: 1,
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG",
+	).ReturnString(404, notFoundResponse)
+
+	accounts, err := client.LoadAccounts(context.Background(), []string{
+		"GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+		"GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG",
+	})
+	if assert.Len(t, accounts, 1) {
+		account := accounts["GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"]
+		assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.ID)
+	}
+	if assert.IsType(t, &BatchError{}, err) {
+		errs := err.(*BatchError).Errors
+		assert.Len(t, errs, 1)
+		assert.True(t, IsNotFoundError(errs["GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG"]))
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	accounts, err = client.LoadAccounts(context.Background(), []string{
+		"GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+
+	// the requests are not sent once the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	accounts, err = client.LoadAccounts(ctx, []string{
+		"GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+		"GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG",
+	})
+	assert.Empty(t, accounts)
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package horizonclient
+
+import (
+	"context"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// buildTransactionFeePercentile is the percentile of the fee stats used by
+// BuildTransaction to suggest base fees.
+const buildTransactionFeePercentile = 70
+
+// BuildTransaction builds a transaction like txnbuild.NewTransaction, after
+// fetching from horizon the parameters which are otherwise fetched before
+// building every transaction:
+//
+//   - The source account is loaded from horizon and the transaction uses the
+//     sequence number following its current sequence number. The sequence
+//     number of params.SourceAccount and params.IncrementSequenceNum are
+//     ignored. The error of horizon is returned as is when the source account
+//     cannot be loaded, so that IsNotFoundError can be used.
+//   - When params.BaseFee is 0, the base fee is suggested by SuggestFee from
+//     the 70th percentile of the max fees of the last ledgers.
+func (c *Client) BuildTransaction(ctx context.Context, params txnbuild.TransactionParams) (*txnbuild.Transaction, error) {
+	if params.SourceAccount == nil {
+		return nil, errors.New("transaction has no source account")
+	}
+
+	// the source account may be a muxed account, whose sequence number is
+	// the one of the underlying account
+	sourceAccountID := params.SourceAccount.GetAccountID()
+	muxedAccount, err := xdr.AddressToMuxedAccount(sourceAccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "source account id is not valid")
+	}
+	accountID := muxedAccount.ToAccountId()
+
+	sequence, err := c.SequenceNumber(ctx, accountID.Address())
+	if err != nil {
+		return nil, err
+	}
+	params.SourceAccount = &txnbuild.SimpleAccount{AccountID: sourceAccountID, Sequence: sequence}
+	params.IncrementSequenceNum = true
+
+	if params.BaseFee == 0 {
+		params.BaseFee, err = c.SuggestFee(ctx, buildTransactionFeePercentile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not suggest base fee")
+		}
+	}
+
+	return txnbuild.NewTransaction(params)
+}
@@ -0,0 +1,70 @@
+package horizonclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTransaction(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	hmock.On(
+		"GET",
+		"https://localhost/fee_stats",
+	).ReturnString(200, feesResponse)
+
+	params := txnbuild.TransactionParams{
+		SourceAccount: &txnbuild.SimpleAccount{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"},
+		Operations:    []txnbuild.Operation{&txnbuild.Inflation{}},
+		Timebounds:    txnbuild.NewInfiniteTimeout(),
+	}
+	tx, err := client.BuildTransaction(context.Background(), params)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", tx.SourceAccount().AccountID)
+		assert.Equal(t, int64(9865509814140930), tx.SourceAccount().Sequence)
+		assert.Equal(t, int64(2000), tx.BaseFee())
+	}
+
+	// the base fee given is kept
+	params.BaseFee = 300
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	tx, err = client.BuildTransaction(context.Background(), params)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(300), tx.BaseFee())
+	}
+
+	// the sequence number of muxed accounts is the one of their account
+	params.SourceAccount = &txnbuild.SimpleAccount{AccountID: "MCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6AAAAAAAAAAAMR62E"}
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	tx, err = client.BuildTransaction(context.Background(), params)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "MCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6AAAAAAAAAAAMR62E", tx.SourceAccount().AccountID)
+		assert.Equal(t, int64(9865509814140930), tx.SourceAccount().Sequence)
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(404, notFoundResponse)
+	params.SourceAccount = &txnbuild.SimpleAccount{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+	_, err = client.BuildTransaction(context.Background(), params)
+	assert.True(t, IsNotFoundError(err))
+}
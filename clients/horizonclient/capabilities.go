@@ -0,0 +1,61 @@
+package horizonclient
+
+import (
+	"context"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+)
+
+// feeBumpProtocolVersion is the first protocol version supporting fee bump
+// transactions, see CAP-15.
+const feeBumpProtocolVersion = 13
+
+// capabilitiesCacheTTL is how long the root resource fetched by Capabilities
+// is reused. Protocol upgrades are rare and announced well in advance.
+var capabilitiesCacheTTL = 5 * time.Minute
+
+// Capabilities describes the features supported by a horizon instance and the
+// network it is connected to, as advertised by its root resource.
+type Capabilities struct {
+	Root hProtocol.Root
+}
+
+// ProtocolVersion returns the protocol version of the network.
+func (c Capabilities) ProtocolVersion() int32 {
+	return c.Root.CurrentProtocolVersion
+}
+
+// SupportsFeeBump returns true if fee bump transactions can be submitted to the
+// network.
+func (c Capabilities) SupportsFeeBump() bool {
+	return c.Root.CurrentProtocolVersion >= feeBumpProtocolVersion
+}
+
+// ExperimentalIngestion returns true if the experimental ingestion is enabled
+// on the horizon instance, in which case the endpoints built on the state of
+// the ledger, like /accounts, /offers and /paths, are available.
+func (c Capabilities) ExperimentalIngestion() bool {
+	return c.Root.IngestSequence > 0 || c.Root.Links.Accounts != nil
+}
+
+// Capabilities returns the capabilities of horizon, so that features can be
+// detected instead of assumed. The root resource of horizon is fetched at most
+// once every 5 minutes.
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	c.capabilitiesMutex.Lock()
+	defer c.capabilitiesMutex.Unlock()
+
+	now := c.clock.Now()
+	if !c.capabilitiesFetchedAt.IsZero() && now.Sub(c.capabilitiesFetchedAt) < capabilitiesCacheTTL {
+		return c.capabilities, nil
+	}
+
+	root, err := c.RootContext(ctx)
+	if err != nil {
+		return Capabilities{}, err
+	}
+	c.capabilities = Capabilities{Root: root}
+	c.capabilitiesFetchedAt = now
+	return c.capabilities, nil
+}
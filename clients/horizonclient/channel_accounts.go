@@ -0,0 +1,135 @@
+package horizonclient
+
+import (
+	"context"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// SequenceProvider provides the current sequence numbers of accounts, which
+// transactions built with txnbuild increment. Client is a SequenceProvider
+// loading them from horizon.
+type SequenceProvider interface {
+	SequenceNumber(ctx context.Context, accountID string) (int64, error)
+}
+
+// SequenceNumber returns the current sequence number of the account
+// `accountID`, loaded from horizon.
+func (c *Client) SequenceNumber(ctx context.Context, accountID string) (int64, error) {
+	account, err := c.AccountDetailContext(ctx, AccountRequest{AccountID: accountID})
+	if err != nil {
+		return 0, err
+	}
+	sequence, err := account.GetSequenceNumber()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not parse sequence number of account")
+	}
+	return sequence, nil
+}
+
+// ChannelAccountPool leases channel accounts, so that transactions can be
+// submitted concurrently without sequence number conflicts. A channel account
+// is the source account of a single transaction at a time, consuming its
+// sequence number and paying its fee, while the operations use the account
+// sending the payments as source account. Transactions must be signed by both
+// accounts:
+//
+//	channel, err := pool.Lease(ctx)
+//	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+//		SourceAccount:        channel,
+//		IncrementSequenceNum: true,
+//		Operations:           []txnbuild.Operation{&txnbuild.Payment{..., SourceAccount: &sender}},
+//		...
+//	})
+//	tx, err = tx.Sign(network.PublicNetworkPassphrase, channel.Keypair, senderKeypair)
+//	_, err = client.SubmitTransactionAndWait(ctx, tx, horizonclient.SubmitTxOpts{})
+//	channel.Release(err)
+type ChannelAccountPool struct {
+	sequences SequenceProvider
+	available chan *ChannelAccount
+}
+
+// NewChannelAccountPool returns a pool of the channel accounts `channels`,
+// which must exist on the network. Their sequence numbers are loaded from
+// `sequences` when they are first leased.
+func NewChannelAccountPool(sequences SequenceProvider, channels ...*keypair.Full) *ChannelAccountPool {
+	pool := &ChannelAccountPool{
+		sequences: sequences,
+		available: make(chan *ChannelAccount, len(channels)),
+	}
+	for _, kp := range channels {
+		pool.available <- &ChannelAccount{Keypair: kp, pool: pool}
+	}
+	return pool
+}
+
+// Lease waits until a channel account is available, or ctx is done, and
+// returns it. The channel account must be given back to the pool with Release
+// once the transaction it is the source account of is submitted.
+func (p *ChannelAccountPool) Lease(ctx context.Context) (*ChannelAccount, error) {
+	var channel *ChannelAccount
+	select {
+	case channel = <-p.available:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if !channel.loaded {
+		sequence, err := p.sequences.SequenceNumber(ctx, channel.Keypair.Address())
+		if err != nil {
+			p.available <- channel
+			return nil, errors.Wrapf(err, "could not load sequence number of channel account %s", channel.Keypair.Address())
+		}
+		channel.sequence = sequence
+		channel.loaded = true
+	}
+	channel.released = false
+	return channel, nil
+}
+
+// ChannelAccount is a channel account leased from a ChannelAccountPool. It
+// implements txnbuild.Account, so that it can be the source account of a
+// transaction.
+type ChannelAccount struct {
+	Keypair *keypair.Full
+
+	pool     *ChannelAccountPool
+	sequence int64
+	loaded   bool
+	released bool
+}
+
+// GetAccountID returns the address of the channel account.
+func (ch *ChannelAccount) GetAccountID() string {
+	return ch.Keypair.Address()
+}
+
+// GetSequenceNumber returns the sequence number of the channel account.
+func (ch *ChannelAccount) GetSequenceNumber() (int64, error) {
+	return ch.sequence, nil
+}
+
+// IncrementSequenceNumber increments the sequence number of the channel
+// account and returns it.
+func (ch *ChannelAccount) IncrementSequenceNumber() (int64, error) {
+	ch.sequence++
+	return ch.sequence, nil
+}
+
+// Release gives the channel account back to its pool. `err` is the error of
+// the submission of the transaction built with the channel account, if any.
+// When it is not nil, it is unknown whether the sequence number was consumed,
+// so it is loaded again when the channel account is leased next, and the
+// transaction can be built again with another channel account and retried.
+// Releasing a channel account twice has no effect.
+func (ch *ChannelAccount) Release(err error) {
+	if ch.released {
+		return
+	}
+	ch.released = true
+	if err != nil {
+		ch.loaded = false
+	}
+	ch.pool.available <- ch
+}
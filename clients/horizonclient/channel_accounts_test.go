@@ -0,0 +1,123 @@
+package horizonclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSequenceProvider struct {
+	sequences map[string]int64
+	err       error
+	calls     int
+}
+
+func (p *fakeSequenceProvider) SequenceNumber(ctx context.Context, accountID string) (int64, error) {
+	p.calls++
+	if p.err != nil {
+		return 0, p.err
+	}
+	return p.sequences[accountID], nil
+}
+
+func TestSequenceNumber(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	sequence, err := client.SequenceNumber(context.Background(), "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU")
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(9865509814140929), sequence)
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(404, notFoundResponse)
+	_, err = client.SequenceNumber(context.Background(), "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU")
+	assert.True(t, IsNotFoundError(err))
+}
+
+func TestChannelAccountPool(t *testing.T) {
+	channel0 := keypair.MustRandom()
+	channel1 := keypair.MustRandom()
+	provider := &fakeSequenceProvider{sequences: map[string]int64{
+		channel0.Address(): 100,
+		channel1.Address(): 200,
+	}}
+	pool := NewChannelAccountPool(provider, channel0, channel1)
+	ctx := context.Background()
+
+	first, err := pool.Lease(ctx)
+	require.NoError(t, err)
+	second, err := pool.Lease(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, channel0.Address(), first.GetAccountID())
+	assert.Equal(t, channel1.Address(), second.GetAccountID())
+	assert.Equal(t, 2, provider.calls)
+
+	// leasing waits until a channel account is released
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.Lease(timeoutCtx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the sequence number of a channel account released after a successful
+	// submission is not loaded again
+	sequence, err := first.IncrementSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, int64(101), sequence)
+	first.Release(nil)
+	first.Release(nil)
+	leased, err := pool.Lease(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, leased)
+	sequence, err = leased.GetSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, int64(101), sequence)
+	assert.Equal(t, 2, provider.calls)
+
+	// the sequence number of a channel account released after a failed
+	// submission is loaded again
+	_, err = second.IncrementSequenceNumber()
+	require.NoError(t, err)
+	second.Release(errors.New("submission failed"))
+	leased, err = pool.Lease(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second, leased)
+	sequence, err = leased.GetSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), sequence)
+	assert.Equal(t, 3, provider.calls)
+}
+
+func TestChannelAccountPoolSequenceError(t *testing.T) {
+	channel := keypair.MustRandom()
+	provider := &fakeSequenceProvider{err: errors.New("horizon is down")}
+	pool := NewChannelAccountPool(provider, channel)
+	ctx := context.Background()
+
+	_, err := pool.Lease(ctx)
+	assert.EqualError(t, err, "could not load sequence number of channel account "+channel.Address()+": horizon is down")
+
+	// the channel account is back in the pool
+	provider.err = nil
+	provider.sequences = map[string]int64{channel.Address(): 300}
+	leased, err := pool.Lease(ctx)
+	require.NoError(t, err)
+	sequence, err := leased.GetSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), sequence)
+}
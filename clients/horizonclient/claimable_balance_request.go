@@ -0,0 +1,46 @@
+package horizonclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// BuildURL creates the endpoint to be queried based on the data in the ClaimableBalanceRequest struct.
+func (cbr ClaimableBalanceRequest) BuildURL() (endpoint string, err error) {
+	if len(cbr.ID) > 0 {
+		endpoint = fmt.Sprintf("claimable_balances/%s", cbr.ID)
+	} else {
+		query := url.Values{}
+		if len(cbr.Asset) > 0 {
+			query.Add("asset", cbr.Asset)
+		}
+		if len(cbr.Sponsor) > 0 {
+			query.Add("sponsor", cbr.Sponsor)
+		}
+		if len(cbr.Claimant) > 0 {
+			query.Add("claimant", cbr.Claimant)
+		}
+
+		endpoint = "claimable_balances"
+		if params := query.Encode(); params != "" {
+			endpoint = fmt.Sprintf("%s?%s", endpoint, params)
+		}
+		pageParams := addQueryParams(cursor(cbr.Cursor), limit(cbr.Limit), cbr.Order)
+		if pageParams != "" {
+			if len(query) > 0 {
+				endpoint = fmt.Sprintf("%s&%s", endpoint, pageParams)
+			} else {
+				endpoint = fmt.Sprintf("%s?%s", endpoint, pageParams)
+			}
+		}
+	}
+
+	_, err = url.Parse(endpoint)
+	if err != nil {
+		err = errors.Wrap(err, "failed to parse endpoint")
+	}
+
+	return endpoint, err
+}
@@ -0,0 +1,132 @@
+package horizonclient
+
+import (
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimableBalanceRequestBuildUrl(t *testing.T) {
+	cbr := ClaimableBalanceRequest{}
+	endpoint, err := cbr.BuildURL()
+
+	// It should return valid all claimable balances endpoint and no errors
+	require.NoError(t, err)
+	assert.Equal(t, "claimable_balances", endpoint)
+
+	cbr = ClaimableBalanceRequest{Limit: 10, Order: OrderDesc}
+	endpoint, err = cbr.BuildURL()
+
+	require.NoError(t, err)
+	assert.Equal(t, "claimable_balances?limit=10&order=desc", endpoint)
+
+	cbr = ClaimableBalanceRequest{
+		Claimant: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+		Asset:    "native",
+		Cursor:   "now",
+	}
+	endpoint, err = cbr.BuildURL()
+
+	require.NoError(t, err)
+	assert.Equal(t, "claimable_balances?asset=native&claimant=GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU&cursor=now", endpoint)
+
+	cbr = ClaimableBalanceRequest{ID: "00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072"}
+	endpoint, err = cbr.BuildURL()
+
+	require.NoError(t, err)
+	assert.Equal(t, "claimable_balances/00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072", endpoint)
+}
+
+func TestClaimableBalances(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/claimable_balances?sponsor=GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+	).ReturnString(200, claimableBalancesResponse)
+
+	balances, err := client.ClaimableBalances(ClaimableBalanceRequest{
+		Sponsor: "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+	})
+	if assert.NoError(t, err) && assert.Len(t, balances.Embedded.Records, 1) {
+		balance := balances.Embedded.Records[0]
+		assert.Equal(t, "00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072", balance.BalanceID)
+		assert.Equal(t, "native", balance.Asset)
+		assert.Equal(t, "10.0000000", balance.Amount)
+		assert.Equal(t, "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML", balance.Sponsor)
+		if assert.Len(t, balance.Claimants, 2) {
+			assert.True(t, balance.Claimants[0].Predicate.Unconditional)
+			predicate := balance.Claimants[1].Predicate
+			if assert.Len(t, predicate.And, 2) {
+				assert.Equal(t, "2020-08-26T11:15:39Z", predicate.And[0].AbsBefore)
+				assert.Equal(t, "3600", predicate.And[1].Not.RelBefore)
+			}
+		}
+	}
+
+	_, err = client.ClaimableBalances(ClaimableBalanceRequest{ID: "00000000"})
+	assert.EqualError(t, err, "invalid request: use ClaimableBalance to get a single claimable balance")
+
+	_, err = client.ClaimableBalance("")
+	assert.EqualError(t, err, "no claimable balance ID provided")
+}
+
+var claimableBalancesResponse = `{
+  "_links": {
+    "self": {
+      "href": "https://localhost/claimable_balances?cursor=&limit=10&order=asc&sponsor=GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"
+    },
+    "next": {
+      "href": "https://localhost/claimable_balances?cursor=1000-00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072&limit=10&order=asc&sponsor=GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"
+    },
+    "prev": {
+      "href": "https://localhost/claimable_balances?cursor=1000-00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072&limit=10&order=desc&sponsor=GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"
+    }
+  },
+  "_embedded": {
+    "records": [
+      {
+        "_links": {
+          "self": {
+            "href": "https://localhost/claimable_balances/00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072"
+          }
+        },
+        "id": "00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072",
+        "asset": "native",
+        "amount": "10.0000000",
+        "sponsor": "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+        "last_modified_ledger": 1000,
+        "claimants": [
+          {
+            "destination": "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+            "predicate": {
+              "unconditional": true
+            }
+          },
+          {
+            "destination": "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+            "predicate": {
+              "and": [
+                {
+                  "abs_before": "2020-08-26T11:15:39Z"
+                },
+                {
+                  "not": {
+                    "rel_before": "3600"
+                  }
+                }
+              ]
+            }
+          }
+        ],
+        "paging_token": "1000-00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072"
+      }
+    ]
+  }
+}`
@@ -21,10 +21,11 @@ import (
 	"github.com/stellar/go/protocols/horizon/effects"
 	"github.com/stellar/go/protocols/horizon/operations"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
 )
 
 // sendRequest builds the URL for the given horizon request and sends the url to a horizon server
-func (c *Client) sendRequest(hr HorizonRequest, resp interface{}) (err error) {
+func (c *Client) sendRequest(ctx context.Context, hr HorizonRequest, resp interface{}) (err error) {
 	endpoint, err := hr.BuildURL()
 	if err != nil {
 		return
@@ -33,15 +34,15 @@ func (c *Client) sendRequest(hr HorizonRequest, resp interface{}) (err error) {
 	c.HorizonURL = c.fixHorizonURL()
 	_, ok := hr.(submitRequest)
 	if ok {
-		return c.sendRequestURL(c.HorizonURL+endpoint, "post", resp)
+		return c.sendRequestURL(ctx, c.HorizonURL+endpoint, "post", resp)
 	}
 
-	return c.sendRequestURL(c.HorizonURL+endpoint, "get", resp)
+	return c.sendRequestURL(ctx, c.HorizonURL+endpoint, "get", resp)
 }
 
 // checkMemoRequired implements a memo required check as defined in
 // https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0029.md
-func (c *Client) checkMemoRequired(transaction *txnbuild.Transaction) error {
+func (c *Client) checkMemoRequired(ctx context.Context, transaction *txnbuild.Transaction) error {
 	destinations := map[string]bool{}
 
 	for i, op := range transaction.Operations() {
@@ -64,8 +65,11 @@ func (c *Client) checkMemoRequired(transaction *txnbuild.Transaction) error {
 			continue
 		}
 
-		// TODO: once we support M-strkeys (SEP23), also check whether the destination
-		//       is a muxed account with a memo ID.
+		// muxed accounts (SEP23) carry their own ID, so they never require a memo
+		if muxedAccount, err := xdr.AddressToMuxedAccount(destination); err == nil &&
+			muxedAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+			continue
+		}
 
 		if destinations[destination] {
 			continue
@@ -77,7 +81,7 @@ func (c *Client) checkMemoRequired(transaction *txnbuild.Transaction) error {
 			DataKey:   "config.memo_required",
 		}
 
-		data, err := c.AccountData(request)
+		data, err := c.AccountDataContext(ctx, request)
 		if err != nil {
 			horizonError := GetError(err)
 
@@ -99,10 +103,36 @@ func (c *Client) checkMemoRequired(transaction *txnbuild.Transaction) error {
 	return nil
 }
 
-// sendRequestURL sends a url to a horizon server.
+// sendRequestURL sends a url to a horizon server, retrying according to the
+// RetryPolicy of the client.
 // It can be used for requests that do not implement the HorizonRequest interface.
-func (c *Client) sendRequestURL(requestURL string, method string, a interface{}) (err error) {
+func (c *Client) sendRequestURL(ctx context.Context, requestURL string, method string, a interface{}) error {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendRequestURLOnce(ctx, requestURL, method, a)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		wait, retry := c.RetryPolicy.backoff(attempt, resp, err)
+		if !retry {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// sendRequestURLOnce sends a url to a horizon server once. It returns the
+// response of horizon, whose body is closed, when the request failed with an
+// unsuccessful status code.
+func (c *Client) sendRequestURLOnce(ctx context.Context, requestURL string, method string, a interface{}) (*http.Response, error) {
 	var req *http.Request
+	var err error
 
 	if method == "post" || method == "POST" {
 		req, err = http.NewRequest("POST", requestURL, nil)
@@ -112,26 +142,38 @@ func (c *Client) sendRequestURL(requestURL string, method string, a interface{})
 	}
 
 	if err != nil {
-		return errors.Wrap(err, "error creating HTTP request")
+		return nil, errors.Wrap(err, "error creating HTTP request")
 	}
 	c.setClientAppHeaders(req)
+	c.setAcceptEncoding(req)
+	c.setHeaders(ctx, req)
+	if err = c.runRequestHooks(req); err != nil {
+		return nil, err
+	}
+	if err = c.RateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	c.setDefaultClient()
 	if c.horizonTimeout == 0 {
 		c.horizonTimeout = HorizonTimeout
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*c.horizonTimeout)
-	resp, err := c.HTTP.Do(req.WithContext(ctx))
+	ctx, cancel := context.WithTimeout(ctx, time.Second*c.horizonTimeout)
+	defer cancel()
+	resp, err := c.do(req.WithContext(ctx))
 	if err != nil {
-		cancel()
-		return
+		return nil, err
 	}
 
 	err = decodeResponse(resp, &a, c)
-	cancel()
-	return
+	if err != nil && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		return resp, err
+	}
+	return nil, err
 }
 
-// stream handles connections to endpoints that support streaming on a horizon server
+// stream handles connections to endpoints that support streaming on a horizon server.
+// The stream is resumed from the last event received when the connection is closed
+// or fails with a network error, until ctx is cancelled.
 func (c *Client) stream(
 	ctx context.Context,
 	streamURL string,
@@ -147,115 +189,199 @@ func (c *Client) stream(
 		query.Set("cursor", "now")
 	}
 
+	backoff := streamReconnectMinBackoff
 	for {
 		// updates the url with new cursor
 		su.RawQuery = query.Encode()
-		req, err := http.NewRequest("GET", su.String(), nil)
-		if err != nil {
-			return errors.Wrap(err, "error creating HTTP request")
+		received := false
+		transient, err := c.readStream(ctx, su.String(), func(event sse.Event) error {
+			received = true
+
+			// Update cursor with event ID
+			if event.Id != "" {
+				query.Set("cursor", event.Id)
+			}
+
+			switch data := event.Data.(type) {
+			case string:
+				return errors.Wrap(handler([]byte(data)), "handler error")
+			case []byte:
+				return errors.Wrap(handler(data), "handler error")
+			default:
+				return errors.New("invalid event.Data type")
+			}
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil && !transient {
+			return err
 		}
-		req.Header.Set("Accept", "text/event-stream")
-		c.setDefaultClient()
-		c.setClientAppHeaders(req)
 
-		// We can use c.HTTP here because we set Timeout per request not on the client. See sendRequest()
-		resp, err := c.HTTP.Do(req)
-		if err != nil {
-			return errors.Wrap(err, "error sending HTTP request")
+		if c.StreamReconnectHandler != nil {
+			c.StreamReconnectHandler(query.Get("cursor"), err)
+		}
+		// The stream was closed by the server/proxy, typically because the
+		// connection was idle, so it can be resumed right away.
+		if err == nil {
+			backoff = streamReconnectMinBackoff
+			continue
 		}
 
-		// Expected statusCode are 200-299
-		if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
-			return fmt.Errorf("got bad HTTP status code %d", resp.StatusCode)
+		if received {
+			backoff = streamReconnectMinBackoff
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > streamReconnectMaxBackoff {
+			backoff = streamReconnectMaxBackoff
 		}
-		defer resp.Body.Close()
+	}
+}
 
-		reader := bufio.NewReader(resp.Body)
+// readStream reads the events of a single connection to a streaming endpoint
+// until it is closed, calling onEvent for every message. It returns a nil error
+// when the connection was closed by the server, and true along with the error
+// when the connection failed with a network error, so that the stream can be
+// resumed.
+func (c *Client) readStream(
+	ctx context.Context,
+	streamURL string,
+	onEvent func(event sse.Event) error,
+) (bool, error) {
+	req, err := http.NewRequest("GET", streamURL, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "error creating HTTP request")
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.setDefaultClient()
+	c.setClientAppHeaders(req)
+	c.setHeaders(ctx, req)
+	if err = c.runRequestHooks(req); err != nil {
+		return false, err
+	}
+	if err = c.RateLimiter.Wait(ctx); err != nil {
+		return false, err
+	}
 
-		// Read events one by one. Break this loop when there is no more data to be
-		// read from resp.Body (io.EOF).
-	Events:
-		for {
-			// Read until empty line = event delimiter. The perfect solution would be to read
-			// as many bytes as possible and forward them to sse.Decode. However this
-			// requires much more complicated code.
-			// We could also write our own `sse` package that works fine with streams directly
-			// (github.com/manucorporat/sse is just using io/ioutils.ReadAll).
-			var buffer bytes.Buffer
-			nonEmptylinesRead := 0
-			for {
-				// Check if ctx is not cancelled
-				select {
-				case <-ctx.Done():
-					return nil
-				default:
-					// Continue
-				}
+	// We can use c.HTTP here because we set Timeout per request not on the client. See sendRequest()
+	// The request is bound to ctx so that cancelling ctx interrupts
+	// the wait for the next event.
+	resp, err := c.do(req.WithContext(ctx))
+	if err != nil {
+		return true, errors.Wrap(err, "error sending HTTP request")
+	}
+	defer resp.Body.Close()
 
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					if err == io.EOF || err == io.ErrUnexpectedEOF {
-						// We catch EOF errors to handle two possible situations:
-						// - The last line before closing the stream was not empty. This should never
-						//   happen in Horizon as it always sends an empty line after each event.
-						// - The stream was closed by the server/proxy because the connection was idle.
-						//
-						// In the former case, that (again) should never happen in Horizon, we need to
-						// check if there are any events we need to decode. We do this in the `if`
-						// statement below just in case if Horizon behaviour changes in a future.
-						//
-						// From spec:
-						// > Once the end of the file is reached, the user agent must dispatch the
-						// > event one final time, as defined below.
-						if nonEmptylinesRead == 0 {
-							break Events
-						}
-					} else {
-						return errors.Wrap(err, "error reading line")
-					}
-				}
-				buffer.WriteString(line)
+	// Expected statusCode are 200-299
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return false, fmt.Errorf("got bad HTTP status code %d", resp.StatusCode)
+	}
 
-				if strings.TrimRight(line, "\n\r") == "" {
-					break
-				}
+	reader := bufio.NewReader(resp.Body)
 
-				nonEmptylinesRead++
+	// Read events one by one. Return when there is no more data to be
+	// read from resp.Body (io.EOF).
+	for {
+		// Read until empty line = event delimiter. The perfect solution would be to read
+		// as many bytes as possible and forward them to sse.Decode. However this
+		// requires much more complicated code.
+		// We could also write our own `sse` package that works fine with streams directly
+		// (github.com/manucorporat/sse is just using io/ioutils.ReadAll).
+		var buffer bytes.Buffer
+		nonEmptylinesRead := 0
+		for {
+			// Check if ctx is not cancelled
+			select {
+			case <-ctx.Done():
+				return false, nil
+			default:
+				// Continue
 			}
 
-			events, err := sse.Decode(strings.NewReader(buffer.String()))
+			line, err := reader.ReadString('\n')
 			if err != nil {
-				return errors.Wrap(err, "error decoding event")
+				if ctx.Err() != nil {
+					return false, nil
+				}
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					// We catch EOF errors to handle two possible situations:
+					// - The last line before closing the stream was not empty. This should never
+					//   happen in Horizon as it always sends an empty line after each event.
+					// - The stream was closed by the server/proxy because the connection was idle.
+					//
+					// In the former case, that (again) should never happen in Horizon, we need to
+					// check if there are any events we need to decode. We do this in the `if`
+					// statement below just in case if Horizon behaviour changes in a future.
+					//
+					// From spec:
+					// > Once the end of the file is reached, the user agent must dispatch the
+					// > event one final time, as defined below.
+					if nonEmptylinesRead == 0 {
+						return false, nil
+					}
+				} else {
+					return true, errors.Wrap(err, "error reading line")
+				}
+			}
+			buffer.WriteString(line)
+			if c.MaxResponseSize > 0 && int64(buffer.Len()) > c.MaxResponseSize {
+				return false, ErrResponseTooLarge
 			}
 
-			// Right now len(events) should always be 1. This loop will be helpful after writing
-			// new SSE decoder that can handle io.Reader without using ioutils.ReadAll().
-			for _, event := range events {
-				if event.Event != "message" {
-					continue
-				}
+			if strings.TrimRight(line, "\n\r") == "" {
+				break
+			}
 
-				// Update cursor with event ID
-				if event.Id != "" {
-					query.Set("cursor", event.Id)
-				}
+			nonEmptylinesRead++
+		}
 
-				switch data := event.Data.(type) {
-				case string:
-					err = handler([]byte(data))
-					err = errors.Wrap(err, "handler error")
-				case []byte:
-					err = handler(data)
-					err = errors.Wrap(err, "handler error")
-				default:
-					err = errors.New("invalid event.Data type")
-				}
-				if err != nil {
-					return err
-				}
+		events, err := sse.Decode(strings.NewReader(buffer.String()))
+		if err != nil {
+			return false, errors.Wrap(err, "error decoding event")
+		}
+
+		// Right now len(events) should always be 1. This loop will be helpful after writing
+		// new SSE decoder that can handle io.Reader without using ioutils.ReadAll().
+		for _, event := range events {
+			if event.Event != "message" {
+				continue
 			}
+
+			if err := onEvent(event); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+// runRequestHooks calls the request hooks of c with req.
+func (c *Client) runRequestHooks(req *http.Request) error {
+	for _, hook := range c.RequestHooks {
+		if err := hook(req); err != nil {
+			return errors.Wrap(err, "request hook error")
 		}
 	}
+	return nil
+}
+
+// do sends req to horizon with the HTTP client of c, updates the rate limiter
+// of c and calls the response hooks of c.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	startTime := time.Now()
+	resp, err := c.HTTP.Do(req)
+	duration := time.Since(startTime)
+	c.RateLimiter.update(resp)
+	for _, hook := range c.ResponseHooks {
+		hook(req, resp, err, duration)
+	}
+	return resp, err
 }
 
 func (c *Client) setClientAppHeaders(req *http.Request) {
@@ -289,17 +415,27 @@ func (c *Client) HorizonTimeout() time.Duration {
 	return c.horizonTimeout
 }
 
-// Accounts returns accounts who have a given signer or
-// have a trustline to an asset.
+// Accounts returns accounts who have a given signer, have a
+// trustline to an asset or are sponsored by an account.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/accounts.html
 func (c *Client) Accounts(request AccountsRequest) (accounts hProtocol.AccountsPage, err error) {
-	err = c.sendRequest(request, &accounts)
+	return c.AccountsContext(context.Background(), request)
+}
+
+// AccountsContext is like Accounts but uses ctx for the requests to horizon.
+func (c *Client) AccountsContext(ctx context.Context, request AccountsRequest) (accounts hProtocol.AccountsPage, err error) {
+	err = c.sendRequest(ctx, request, &accounts)
 	return
 }
 
 // AccountDetail returns information for a single account.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/accounts-single.html
 func (c *Client) AccountDetail(request AccountRequest) (account hProtocol.Account, err error) {
+	return c.AccountDetailContext(context.Background(), request)
+}
+
+// AccountDetailContext is like AccountDetail but uses ctx for the requests to horizon.
+func (c *Client) AccountDetailContext(ctx context.Context, request AccountRequest) (account hProtocol.Account, err error) {
 	if request.AccountID == "" {
 		err = errors.New("no account ID provided")
 	}
@@ -308,13 +444,18 @@ func (c *Client) AccountDetail(request AccountRequest) (account hProtocol.Accoun
 		return
 	}
 
-	err = c.sendRequest(request, &account)
+	err = c.sendRequest(ctx, request, &account)
 	return
 }
 
 // AccountData returns a single data associated with a given account
 // See https://www.stellar.org/developers/horizon/reference/endpoints/data-for-account.html
 func (c *Client) AccountData(request AccountRequest) (accountData hProtocol.AccountData, err error) {
+	return c.AccountDataContext(context.Background(), request)
+}
+
+// AccountDataContext is like AccountData but uses ctx for the requests to horizon.
+func (c *Client) AccountDataContext(ctx context.Context, request AccountRequest) (accountData hProtocol.AccountData, err error) {
 	if request.AccountID == "" || request.DataKey == "" {
 		err = errors.New("too few parameters")
 	}
@@ -323,34 +464,68 @@ func (c *Client) AccountData(request AccountRequest) (accountData hProtocol.Acco
 		return
 	}
 
-	err = c.sendRequest(request, &accountData)
+	err = c.sendRequest(ctx, request, &accountData)
 	return
 }
 
 // Effects returns effects(https://www.stellar.org/developers/horizon/reference/resources/effect.html)
 // It can be used to return effects for an account, a ledger, an operation, a transaction and all effects on the network.
 func (c *Client) Effects(request EffectRequest) (effects effects.EffectsPage, err error) {
-	err = c.sendRequest(request, &effects)
+	return c.EffectsContext(context.Background(), request)
+}
+
+// EffectsContext is like Effects but uses ctx for the requests to horizon.
+func (c *Client) EffectsContext(ctx context.Context, request EffectRequest) (effects effects.EffectsPage, err error) {
+	err = c.sendRequest(ctx, request, &effects)
 	return
 }
 
 // Assets returns asset information.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/assets-all.html
 func (c *Client) Assets(request AssetRequest) (assets hProtocol.AssetsPage, err error) {
-	err = c.sendRequest(request, &assets)
+	return c.AssetsContext(context.Background(), request)
+}
+
+// AssetsContext is like Assets but uses ctx for the requests to horizon.
+func (c *Client) AssetsContext(ctx context.Context, request AssetRequest) (assets hProtocol.AssetsPage, err error) {
+	err = c.sendRequest(ctx, request, &assets)
 	return
 }
 
 // Ledgers returns information about all ledgers.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/ledgers-all.html
 func (c *Client) Ledgers(request LedgerRequest) (ledgers hProtocol.LedgersPage, err error) {
-	err = c.sendRequest(request, &ledgers)
+	return c.LedgersContext(context.Background(), request)
+}
+
+// LedgersContext is like Ledgers but uses ctx for the requests to horizon.
+func (c *Client) LedgersContext(ctx context.Context, request LedgerRequest) (ledgers hProtocol.LedgersPage, err error) {
+	err = c.sendRequest(ctx, request, &ledgers)
 	return
 }
 
+// LatestLedgerCloseTime returns the close time of the latest ledger ingested by horizon. It is
+// the time of the network, which can be used to build the time bounds of transactions with
+// txnbuild.ValidFromLedgerCloseTime when the system clock is not accurate.
+func (c *Client) LatestLedgerCloseTime(ctx context.Context) (time.Time, error) {
+	ledgers, err := c.LedgersContext(ctx, LedgerRequest{Order: OrderDesc, Limit: 1})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(ledgers.Embedded.Records) == 0 {
+		return time.Time{}, errors.New("no ledger found")
+	}
+	return ledgers.Embedded.Records[0].ClosedAt, nil
+}
+
 // LedgerDetail returns information about a particular ledger for a given sequence number
 // See https://www.stellar.org/developers/horizon/reference/endpoints/ledgers-single.html
 func (c *Client) LedgerDetail(sequence uint32) (ledger hProtocol.Ledger, err error) {
+	return c.LedgerDetailContext(context.Background(), sequence)
+}
+
+// LedgerDetailContext is like LedgerDetail but uses ctx for the requests to horizon.
+func (c *Client) LedgerDetailContext(ctx context.Context, sequence uint32) (ledger hProtocol.Ledger, err error) {
 	if sequence == 0 {
 		err = errors.New("invalid sequence number provided")
 	}
@@ -360,28 +535,43 @@ func (c *Client) LedgerDetail(sequence uint32) (ledger hProtocol.Ledger, err err
 	}
 
 	request := LedgerRequest{forSequence: sequence}
-	err = c.sendRequest(request, &ledger)
+	err = c.sendRequest(ctx, request, &ledger)
 	return
 }
 
 // FeeStats returns information about fees in the last 5 ledgers.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/fee-stats.html
 func (c *Client) FeeStats() (feestats hProtocol.FeeStats, err error) {
+	return c.FeeStatsContext(context.Background())
+}
+
+// FeeStatsContext is like FeeStats but uses ctx for the requests to horizon.
+func (c *Client) FeeStatsContext(ctx context.Context) (feestats hProtocol.FeeStats, err error) {
 	request := feeStatsRequest{endpoint: "fee_stats"}
-	err = c.sendRequest(request, &feestats)
+	err = c.sendRequest(ctx, request, &feestats)
 	return
 }
 
 // Offers returns information about offers made on the SDEX.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/offers-for-account.html
 func (c *Client) Offers(request OfferRequest) (offers hProtocol.OffersPage, err error) {
-	err = c.sendRequest(request, &offers)
+	return c.OffersContext(context.Background(), request)
+}
+
+// OffersContext is like Offers but uses ctx for the requests to horizon.
+func (c *Client) OffersContext(ctx context.Context, request OfferRequest) (offers hProtocol.OffersPage, err error) {
+	err = c.sendRequest(ctx, request, &offers)
 	return
 }
 
 // OfferDetails returns information for a single offer.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/offer-details.html
 func (c *Client) OfferDetails(offerID string) (offer hProtocol.Offer, err error) {
+	return c.OfferDetailsContext(context.Background(), offerID)
+}
+
+// OfferDetailsContext is like OfferDetails but uses ctx for the requests to horizon.
+func (c *Client) OfferDetailsContext(ctx context.Context, offerID string) (offer hProtocol.Offer, err error) {
 	if len(offerID) == 0 {
 		err = errors.New("no offer ID provided")
 		return
@@ -392,20 +582,63 @@ func (c *Client) OfferDetails(offerID string) (offer hProtocol.Offer, err error)
 		return
 	}
 
-	err = c.sendRequest(OfferRequest{OfferID: offerID}, &offer)
+	err = c.sendRequest(ctx, OfferRequest{OfferID: offerID}, &offer)
+	return
+}
+
+// ClaimableBalances returns the claimable balances matching request, e.g. the
+// claimable balances an account can claim.
+func (c *Client) ClaimableBalances(request ClaimableBalanceRequest) (balances hProtocol.ClaimableBalancesPage, err error) {
+	return c.ClaimableBalancesContext(context.Background(), request)
+}
+
+// ClaimableBalancesContext is like ClaimableBalances but uses ctx for the requests to horizon.
+func (c *Client) ClaimableBalancesContext(ctx context.Context, request ClaimableBalanceRequest) (balances hProtocol.ClaimableBalancesPage, err error) {
+	if len(request.ID) > 0 {
+		err = errors.New("invalid request: use ClaimableBalance to get a single claimable balance")
+		return
+	}
+
+	err = c.sendRequest(ctx, request, &balances)
+	return
+}
+
+// ClaimableBalance returns the claimable balance with the given ID.
+func (c *Client) ClaimableBalance(id string) (balance hProtocol.ClaimableBalance, err error) {
+	return c.ClaimableBalanceContext(context.Background(), id)
+}
+
+// ClaimableBalanceContext is like ClaimableBalance but uses ctx for the requests to horizon.
+func (c *Client) ClaimableBalanceContext(ctx context.Context, id string) (balance hProtocol.ClaimableBalance, err error) {
+	if len(id) == 0 {
+		err = errors.New("no claimable balance ID provided")
+		return
+	}
+
+	err = c.sendRequest(ctx, ClaimableBalanceRequest{ID: id}, &balance)
 	return
 }
 
 // Operations returns stellar operations (https://www.stellar.org/developers/horizon/reference/resources/operation.html)
 // It can be used to return operations for an account, a ledger, a transaction and all operations on the network.
 func (c *Client) Operations(request OperationRequest) (ops operations.OperationsPage, err error) {
-	err = c.sendRequest(request.SetOperationsEndpoint(), &ops)
+	return c.OperationsContext(context.Background(), request)
+}
+
+// OperationsContext is like Operations but uses ctx for the requests to horizon.
+func (c *Client) OperationsContext(ctx context.Context, request OperationRequest) (ops operations.OperationsPage, err error) {
+	err = c.sendRequest(ctx, request.SetOperationsEndpoint(), &ops)
 	return
 }
 
 // OperationDetail returns a single stellar operations (https://www.stellar.org/developers/horizon/reference/resources/operation.html)
 // for a given operation id
 func (c *Client) OperationDetail(id string) (ops operations.Operation, err error) {
+	return c.OperationDetailContext(context.Background(), id)
+}
+
+// OperationDetailContext is like OperationDetail but uses ctx for the requests to horizon.
+func (c *Client) OperationDetailContext(ctx context.Context, id string) (ops operations.Operation, err error) {
 	if id == "" {
 		return ops, errors.New("invalid operation id provided")
 	}
@@ -414,7 +647,7 @@ func (c *Client) OperationDetail(id string) (ops operations.Operation, err error
 
 	var record interface{}
 
-	err = c.sendRequest(request, &record)
+	err = c.sendRequest(ctx, request, &record)
 	if err != nil {
 		return ops, errors.Wrap(err, "sending request to horizon")
 	}
@@ -438,15 +671,26 @@ func (c *Client) OperationDetail(id string) (ops operations.Operation, err error
 // SubmitTransactionXDR submits a transaction represented as a base64 XDR string to the network. err can be either error object or horizon.Error object.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-create.html
 func (c *Client) SubmitTransactionXDR(transactionXdr string) (tx hProtocol.Transaction,
+	err error) {
+	return c.SubmitTransactionXDRContext(context.Background(), transactionXdr)
+}
+
+// SubmitTransactionXDRContext is like SubmitTransactionXDR but uses ctx for the requests to horizon.
+func (c *Client) SubmitTransactionXDRContext(ctx context.Context, transactionXdr string) (tx hProtocol.Transaction,
 	err error) {
 	request := submitRequest{endpoint: "transactions", transactionXdr: transactionXdr}
-	err = c.sendRequest(request, &tx)
+	err = c.sendRequest(ctx, request, &tx)
 	return
 }
 
 // SubmitFeeBumpTransaction submits a fee bump transaction to the network. err can be either an
 // error object or a horizon.Error object.
 //
+// When the transaction succeeds, the hashes of the fee bump transaction and of its inner
+// transaction are in the FeeBumpTransaction and InnerTransaction fields of tx. When the
+// inner transaction fails, Error.IsFeeBumpInnerFailed returns true and the result of the
+// inner transaction is returned by Error.InnerTransactionResult.
+//
 // This function will always check if the destination account requires a memo in the transaction as
 // defined in SEP0029: https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0029.md
 //
@@ -454,7 +698,12 @@ func (c *Client) SubmitTransactionXDR(transactionXdr string) (tx hProtocol.Trans
 //
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-create.html
 func (c *Client) SubmitFeeBumpTransaction(transaction *txnbuild.FeeBumpTransaction) (tx hProtocol.Transaction, err error) {
-	return c.SubmitFeeBumpTransactionWithOptions(transaction, SubmitTxOpts{})
+	return c.SubmitFeeBumpTransactionContext(context.Background(), transaction)
+}
+
+// SubmitFeeBumpTransactionContext is like SubmitFeeBumpTransaction but uses ctx for the requests to horizon.
+func (c *Client) SubmitFeeBumpTransactionContext(ctx context.Context, transaction *txnbuild.FeeBumpTransaction) (tx hProtocol.Transaction, err error) {
+	return c.SubmitFeeBumpTransactionWithOptionsContext(ctx, transaction, SubmitTxOpts{})
 }
 
 // SubmitFeeBumpTransactionWithOptions submits a fee bump transaction to the network, allowing
@@ -462,10 +711,15 @@ func (c *Client) SubmitFeeBumpTransaction(transaction *txnbuild.FeeBumpTransacti
 //
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-create.html
 func (c *Client) SubmitFeeBumpTransactionWithOptions(transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (tx hProtocol.Transaction, err error) {
+	return c.SubmitFeeBumpTransactionWithOptionsContext(context.Background(), transaction, opts)
+}
+
+// SubmitFeeBumpTransactionWithOptionsContext is like SubmitFeeBumpTransactionWithOptions but uses ctx for the requests to horizon.
+func (c *Client) SubmitFeeBumpTransactionWithOptionsContext(ctx context.Context, transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (tx hProtocol.Transaction, err error) {
 	// only check if memo is required if skip is false and the inner transaction
 	// doesn't have a memo.
 	if inner := transaction.InnerTransaction(); !opts.SkipMemoRequiredCheck && inner.Memo() == nil {
-		err = c.checkMemoRequired(inner)
+		err = c.checkMemoRequired(ctx, inner)
 		if err != nil {
 			return
 		}
@@ -477,7 +731,7 @@ func (c *Client) SubmitFeeBumpTransactionWithOptions(transaction *txnbuild.FeeBu
 		return
 	}
 
-	return c.SubmitTransactionXDR(txeBase64)
+	return c.SubmitTransactionXDRContext(ctx, txeBase64)
 }
 
 // SubmitTransaction submits a transaction to the network. err can be either an
@@ -490,7 +744,12 @@ func (c *Client) SubmitFeeBumpTransactionWithOptions(transaction *txnbuild.FeeBu
 //
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-create.html
 func (c *Client) SubmitTransaction(transaction *txnbuild.Transaction) (tx hProtocol.Transaction, err error) {
-	return c.SubmitTransactionWithOptions(transaction, SubmitTxOpts{})
+	return c.SubmitTransactionContext(context.Background(), transaction)
+}
+
+// SubmitTransactionContext is like SubmitTransaction but uses ctx for the requests to horizon.
+func (c *Client) SubmitTransactionContext(ctx context.Context, transaction *txnbuild.Transaction) (tx hProtocol.Transaction, err error) {
+	return c.SubmitTransactionWithOptionsContext(ctx, transaction, SubmitTxOpts{})
 }
 
 // SubmitTransactionWithOptions submits a transaction to the network, allowing
@@ -498,10 +757,15 @@ func (c *Client) SubmitTransaction(transaction *txnbuild.Transaction) (tx hProto
 //
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-create.html
 func (c *Client) SubmitTransactionWithOptions(transaction *txnbuild.Transaction, opts SubmitTxOpts) (tx hProtocol.Transaction, err error) {
+	return c.SubmitTransactionWithOptionsContext(context.Background(), transaction, opts)
+}
+
+// SubmitTransactionWithOptionsContext is like SubmitTransactionWithOptions but uses ctx for the requests to horizon.
+func (c *Client) SubmitTransactionWithOptionsContext(ctx context.Context, transaction *txnbuild.Transaction, opts SubmitTxOpts) (tx hProtocol.Transaction, err error) {
 	// only check if memo is required if skip is false and the transaction
 	// doesn't have a memo.
 	if !opts.SkipMemoRequiredCheck && transaction.Memo() == nil {
-		err = c.checkMemoRequired(transaction)
+		err = c.checkMemoRequired(ctx, transaction)
 		if err != nil {
 			return
 		}
@@ -513,75 +777,120 @@ func (c *Client) SubmitTransactionWithOptions(transaction *txnbuild.Transaction,
 		return
 	}
 
-	return c.SubmitTransactionXDR(txeBase64)
+	return c.SubmitTransactionXDRContext(ctx, txeBase64)
 }
 
 // Transactions returns stellar transactions (https://www.stellar.org/developers/horizon/reference/resources/transaction.html)
 // It can be used to return transactions for an account, a ledger,and all transactions on the network.
 func (c *Client) Transactions(request TransactionRequest) (txs hProtocol.TransactionsPage, err error) {
-	err = c.sendRequest(request, &txs)
+	return c.TransactionsContext(context.Background(), request)
+}
+
+// TransactionsContext is like Transactions but uses ctx for the requests to horizon.
+func (c *Client) TransactionsContext(ctx context.Context, request TransactionRequest) (txs hProtocol.TransactionsPage, err error) {
+	err = c.sendRequest(ctx, request, &txs)
 	return
 }
 
 // TransactionDetail returns information about a particular transaction for a given transaction hash
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-single.html
 func (c *Client) TransactionDetail(txHash string) (tx hProtocol.Transaction, err error) {
+	return c.TransactionDetailContext(context.Background(), txHash)
+}
+
+// TransactionDetailContext is like TransactionDetail but uses ctx for the requests to horizon.
+func (c *Client) TransactionDetailContext(ctx context.Context, txHash string) (tx hProtocol.Transaction, err error) {
 	if txHash == "" {
 		return tx, errors.New("no transaction hash provided")
 	}
 
 	request := TransactionRequest{forTransactionHash: txHash}
-	err = c.sendRequest(request, &tx)
+	err = c.sendRequest(ctx, request, &tx)
 	return
 }
 
 // OrderBook returns the orderbook for an asset pair (https://www.stellar.org/developers/horizon/reference/resources/orderbook.html)
 func (c *Client) OrderBook(request OrderBookRequest) (obs hProtocol.OrderBookSummary, err error) {
-	err = c.sendRequest(request, &obs)
+	return c.OrderBookContext(context.Background(), request)
+}
+
+// OrderBookContext is like OrderBook but uses ctx for the requests to horizon.
+func (c *Client) OrderBookContext(ctx context.Context, request OrderBookRequest) (obs hProtocol.OrderBookSummary, err error) {
+	err = c.sendRequest(ctx, request, &obs)
 	return
 }
 
 // Paths returns the available paths to make a strict receive path payment. See https://www.stellar.org/developers/horizon/reference/endpoints/path-finding-strict-receive.html
 // This function is an alias for `client.StrictReceivePaths` and will be deprecated, use `client.StrictReceivePaths` instead.
 func (c *Client) Paths(request PathsRequest) (paths hProtocol.PathsPage, err error) {
-	paths, err = c.StrictReceivePaths(request)
+	return c.PathsContext(context.Background(), request)
+}
+
+// PathsContext is like Paths but uses ctx for the requests to horizon.
+func (c *Client) PathsContext(ctx context.Context, request PathsRequest) (paths hProtocol.PathsPage, err error) {
+	paths, err = c.StrictReceivePathsContext(ctx, request)
 	return
 }
 
 // StrictReceivePaths returns the available paths to make a strict receive path payment. See https://www.stellar.org/developers/horizon/reference/endpoints/path-finding-strict-receive.html
 func (c *Client) StrictReceivePaths(request PathsRequest) (paths hProtocol.PathsPage, err error) {
-	err = c.sendRequest(request, &paths)
+	return c.StrictReceivePathsContext(context.Background(), request)
+}
+
+// StrictReceivePathsContext is like StrictReceivePaths but uses ctx for the requests to horizon.
+func (c *Client) StrictReceivePathsContext(ctx context.Context, request PathsRequest) (paths hProtocol.PathsPage, err error) {
+	err = c.sendRequest(ctx, request, &paths)
 	return
 }
 
 // StrictSendPaths returns the available paths to make a strict send path payment. See https://www.stellar.org/developers/horizon/reference/endpoints/path-finding-strict-send.html
 func (c *Client) StrictSendPaths(request StrictSendPathsRequest) (paths hProtocol.PathsPage, err error) {
-	err = c.sendRequest(request, &paths)
+	return c.StrictSendPathsContext(context.Background(), request)
+}
+
+// StrictSendPathsContext is like StrictSendPaths but uses ctx for the requests to horizon.
+func (c *Client) StrictSendPathsContext(ctx context.Context, request StrictSendPathsRequest) (paths hProtocol.PathsPage, err error) {
+	err = c.sendRequest(ctx, request, &paths)
 	return
 }
 
 // Payments returns stellar account_merge, create_account, path payment and payment operations.
 // It can be used to return payments for an account, a ledger, a transaction and all payments on the network.
 func (c *Client) Payments(request OperationRequest) (ops operations.OperationsPage, err error) {
-	err = c.sendRequest(request.SetPaymentsEndpoint(), &ops)
+	return c.PaymentsContext(context.Background(), request)
+}
+
+// PaymentsContext is like Payments but uses ctx for the requests to horizon.
+func (c *Client) PaymentsContext(ctx context.Context, request OperationRequest) (ops operations.OperationsPage, err error) {
+	err = c.sendRequest(ctx, request.SetPaymentsEndpoint(), &ops)
 	return
 }
 
 // Trades returns stellar trades (https://www.stellar.org/developers/horizon/reference/resources/trade.html)
 // It can be used to return trades for an account, an offer and all trades on the network.
 func (c *Client) Trades(request TradeRequest) (tds hProtocol.TradesPage, err error) {
-	err = c.sendRequest(request, &tds)
+	return c.TradesContext(context.Background(), request)
+}
+
+// TradesContext is like Trades but uses ctx for the requests to horizon.
+func (c *Client) TradesContext(ctx context.Context, request TradeRequest) (tds hProtocol.TradesPage, err error) {
+	err = c.sendRequest(ctx, request, &tds)
 	return
 }
 
 // Fund creates a new account funded from friendbot. It only works on test networks. See
 // https://www.stellar.org/developers/guides/get-started/create-account.html for more information.
 func (c *Client) Fund(addr string) (tx hProtocol.Transaction, err error) {
+	return c.FundContext(context.Background(), addr)
+}
+
+// FundContext is like Fund but uses ctx for the requests to horizon.
+func (c *Client) FundContext(ctx context.Context, addr string) (tx hProtocol.Transaction, err error) {
 	if !c.isTestNet {
 		return tx, errors.New("can't fund account from friendbot on production network")
 	}
 	friendbotURL := fmt.Sprintf("%sfriendbot?addr=%s", c.fixHorizonURL(), addr)
-	err = c.sendRequestURL(friendbotURL, "get", &tx)
+	err = c.sendRequestURL(ctx, friendbotURL, "get", &tx)
 	return
 }
 
@@ -595,7 +904,12 @@ func (c *Client) StreamTrades(ctx context.Context, request TradeRequest, handler
 
 // TradeAggregations returns stellar trade aggregations (https://www.stellar.org/developers/horizon/reference/resources/trade_aggregation.html)
 func (c *Client) TradeAggregations(request TradeAggregationRequest) (tds hProtocol.TradeAggregationsPage, err error) {
-	err = c.sendRequest(request, &tds)
+	return c.TradeAggregationsContext(context.Background(), request)
+}
+
+// TradeAggregationsContext is like TradeAggregations but uses ctx for the requests to horizon.
+func (c *Client) TradeAggregationsContext(ctx context.Context, request TradeAggregationRequest) (tds hProtocol.TradeAggregationsPage, err error) {
+	err = c.sendRequest(ctx, request, &tds)
 	return
 }
 
@@ -672,7 +986,12 @@ func (c *Client) FetchTimebounds(seconds int64) (txnbuild.Timebounds, error) {
 
 // Root loads the root endpoint of horizon
 func (c *Client) Root() (root hProtocol.Root, err error) {
-	err = c.sendRequestURL(c.fixHorizonURL(), "get", &root)
+	return c.RootContext(context.Background())
+}
+
+// RootContext is like Root but uses ctx for the requests to horizon.
+func (c *Client) RootContext(ctx context.Context) (root hProtocol.Root, err error) {
+	err = c.sendRequestURL(ctx, c.fixHorizonURL(), "get", &root)
 	return
 }
 
@@ -683,105 +1002,212 @@ func (c *Client) Version() string {
 
 // NextAssetsPage returns the next page of assets.
 func (c *Client) NextAssetsPage(page hProtocol.AssetsPage) (assets hProtocol.AssetsPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &assets)
+	return c.NextAssetsPageContext(context.Background(), page)
+}
+
+// NextAssetsPageContext is like NextAssetsPage but uses ctx for the requests to horizon.
+func (c *Client) NextAssetsPageContext(ctx context.Context, page hProtocol.AssetsPage) (assets hProtocol.AssetsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &assets)
 	return
 }
 
 // PrevAssetsPage returns the previous page of assets.
 func (c *Client) PrevAssetsPage(page hProtocol.AssetsPage) (assets hProtocol.AssetsPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &assets)
+	return c.PrevAssetsPageContext(context.Background(), page)
+}
+
+// PrevAssetsPageContext is like PrevAssetsPage but uses ctx for the requests to horizon.
+func (c *Client) PrevAssetsPageContext(ctx context.Context, page hProtocol.AssetsPage) (assets hProtocol.AssetsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &assets)
 	return
 }
 
 // NextLedgersPage returns the next page of ledgers.
 func (c *Client) NextLedgersPage(page hProtocol.LedgersPage) (ledgers hProtocol.LedgersPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &ledgers)
+	return c.NextLedgersPageContext(context.Background(), page)
+}
+
+// NextLedgersPageContext is like NextLedgersPage but uses ctx for the requests to horizon.
+func (c *Client) NextLedgersPageContext(ctx context.Context, page hProtocol.LedgersPage) (ledgers hProtocol.LedgersPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &ledgers)
 	return
 }
 
 // PrevLedgersPage returns the previous page of ledgers.
 func (c *Client) PrevLedgersPage(page hProtocol.LedgersPage) (ledgers hProtocol.LedgersPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &ledgers)
+	return c.PrevLedgersPageContext(context.Background(), page)
+}
+
+// PrevLedgersPageContext is like PrevLedgersPage but uses ctx for the requests to horizon.
+func (c *Client) PrevLedgersPageContext(ctx context.Context, page hProtocol.LedgersPage) (ledgers hProtocol.LedgersPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &ledgers)
 	return
 }
 
 // NextEffectsPage returns the next page of effects.
 func (c *Client) NextEffectsPage(page effects.EffectsPage) (efp effects.EffectsPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &efp)
+	return c.NextEffectsPageContext(context.Background(), page)
+}
+
+// NextEffectsPageContext is like NextEffectsPage but uses ctx for the requests to horizon.
+func (c *Client) NextEffectsPageContext(ctx context.Context, page effects.EffectsPage) (efp effects.EffectsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &efp)
 	return
 }
 
 // PrevEffectsPage returns the previous page of effects.
 func (c *Client) PrevEffectsPage(page effects.EffectsPage) (efp effects.EffectsPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &efp)
+	return c.PrevEffectsPageContext(context.Background(), page)
+}
+
+// PrevEffectsPageContext is like PrevEffectsPage but uses ctx for the requests to horizon.
+func (c *Client) PrevEffectsPageContext(ctx context.Context, page effects.EffectsPage) (efp effects.EffectsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &efp)
 	return
 }
 
 // NextTransactionsPage returns the next page of transactions.
 func (c *Client) NextTransactionsPage(page hProtocol.TransactionsPage) (transactions hProtocol.TransactionsPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &transactions)
+	return c.NextTransactionsPageContext(context.Background(), page)
+}
+
+// NextTransactionsPageContext is like NextTransactionsPage but uses ctx for the requests to horizon.
+func (c *Client) NextTransactionsPageContext(ctx context.Context, page hProtocol.TransactionsPage) (transactions hProtocol.TransactionsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &transactions)
 	return
 }
 
 // PrevTransactionsPage returns the previous page of transactions.
 func (c *Client) PrevTransactionsPage(page hProtocol.TransactionsPage) (transactions hProtocol.TransactionsPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &transactions)
+	return c.PrevTransactionsPageContext(context.Background(), page)
+}
+
+// PrevTransactionsPageContext is like PrevTransactionsPage but uses ctx for the requests to horizon.
+func (c *Client) PrevTransactionsPageContext(ctx context.Context, page hProtocol.TransactionsPage) (transactions hProtocol.TransactionsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &transactions)
 	return
 }
 
 // NextOperationsPage returns the next page of operations.
 func (c *Client) NextOperationsPage(page operations.OperationsPage) (operations operations.OperationsPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &operations)
+	return c.NextOperationsPageContext(context.Background(), page)
+}
+
+// NextOperationsPageContext is like NextOperationsPage but uses ctx for the requests to horizon.
+func (c *Client) NextOperationsPageContext(ctx context.Context, page operations.OperationsPage) (operations operations.OperationsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &operations)
 	return
 }
 
 // PrevOperationsPage returns the previous page of operations.
 func (c *Client) PrevOperationsPage(page operations.OperationsPage) (operations operations.OperationsPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &operations)
+	return c.PrevOperationsPageContext(context.Background(), page)
+}
+
+// PrevOperationsPageContext is like PrevOperationsPage but uses ctx for the requests to horizon.
+func (c *Client) PrevOperationsPageContext(ctx context.Context, page operations.OperationsPage) (operations operations.OperationsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &operations)
 	return
 }
 
 // NextPaymentsPage returns the next page of payments.
 func (c *Client) NextPaymentsPage(page operations.OperationsPage) (operations.OperationsPage, error) {
-	return c.NextOperationsPage(page)
+	return c.NextPaymentsPageContext(context.Background(), page)
+}
+
+// NextPaymentsPageContext is like NextPaymentsPage but uses ctx for the requests to horizon.
+func (c *Client) NextPaymentsPageContext(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	return c.NextOperationsPageContext(ctx, page)
 }
 
 // PrevPaymentsPage returns the previous page of payments.
 func (c *Client) PrevPaymentsPage(page operations.OperationsPage) (operations.OperationsPage, error) {
-	return c.PrevOperationsPage(page)
+	return c.PrevPaymentsPageContext(context.Background(), page)
+}
+
+// PrevPaymentsPageContext is like PrevPaymentsPage but uses ctx for the requests to horizon.
+func (c *Client) PrevPaymentsPageContext(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	return c.PrevOperationsPageContext(ctx, page)
 }
 
 // NextOffersPage returns the next page of offers.
 func (c *Client) NextOffersPage(page hProtocol.OffersPage) (offers hProtocol.OffersPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &offers)
+	return c.NextOffersPageContext(context.Background(), page)
+}
+
+// NextOffersPageContext is like NextOffersPage but uses ctx for the requests to horizon.
+func (c *Client) NextOffersPageContext(ctx context.Context, page hProtocol.OffersPage) (offers hProtocol.OffersPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &offers)
 	return
 }
 
 // PrevOffersPage returns the previous page of offers.
 func (c *Client) PrevOffersPage(page hProtocol.OffersPage) (offers hProtocol.OffersPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &offers)
+	return c.PrevOffersPageContext(context.Background(), page)
+}
+
+// PrevOffersPageContext is like PrevOffersPage but uses ctx for the requests to horizon.
+func (c *Client) PrevOffersPageContext(ctx context.Context, page hProtocol.OffersPage) (offers hProtocol.OffersPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &offers)
+	return
+}
+
+// NextClaimableBalancesPage returns the next page of claimable balances.
+func (c *Client) NextClaimableBalancesPage(page hProtocol.ClaimableBalancesPage) (balances hProtocol.ClaimableBalancesPage, err error) {
+	return c.NextClaimableBalancesPageContext(context.Background(), page)
+}
+
+// NextClaimableBalancesPageContext is like NextClaimableBalancesPage but uses ctx for the requests to horizon.
+func (c *Client) NextClaimableBalancesPageContext(ctx context.Context, page hProtocol.ClaimableBalancesPage) (balances hProtocol.ClaimableBalancesPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &balances)
+	return
+}
+
+// PrevClaimableBalancesPage returns the previous page of claimable balances.
+func (c *Client) PrevClaimableBalancesPage(page hProtocol.ClaimableBalancesPage) (balances hProtocol.ClaimableBalancesPage, err error) {
+	return c.PrevClaimableBalancesPageContext(context.Background(), page)
+}
+
+// PrevClaimableBalancesPageContext is like PrevClaimableBalancesPage but uses ctx for the requests to horizon.
+func (c *Client) PrevClaimableBalancesPageContext(ctx context.Context, page hProtocol.ClaimableBalancesPage) (balances hProtocol.ClaimableBalancesPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &balances)
 	return
 }
 
 // NextTradesPage returns the next page of trades.
 func (c *Client) NextTradesPage(page hProtocol.TradesPage) (trades hProtocol.TradesPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &trades)
+	return c.NextTradesPageContext(context.Background(), page)
+}
+
+// NextTradesPageContext is like NextTradesPage but uses ctx for the requests to horizon.
+func (c *Client) NextTradesPageContext(ctx context.Context, page hProtocol.TradesPage) (trades hProtocol.TradesPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &trades)
 	return
 }
 
 // PrevTradesPage returns the previous page of trades.
 func (c *Client) PrevTradesPage(page hProtocol.TradesPage) (trades hProtocol.TradesPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &trades)
+	return c.PrevTradesPageContext(context.Background(), page)
+}
+
+// PrevTradesPageContext is like PrevTradesPage but uses ctx for the requests to horizon.
+func (c *Client) PrevTradesPageContext(ctx context.Context, page hProtocol.TradesPage) (trades hProtocol.TradesPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &trades)
 	return
 }
 
 // HomeDomainForAccount returns the home domain for a single account.
 func (c *Client) HomeDomainForAccount(aid string) (string, error) {
+	return c.HomeDomainForAccountContext(context.Background(), aid)
+}
+
+// HomeDomainForAccountContext is like HomeDomainForAccount but uses ctx for the requests to horizon.
+func (c *Client) HomeDomainForAccountContext(ctx context.Context, aid string) (string, error) {
 	if aid == "" {
 		return "", errors.New("no account ID provided")
 	}
 
-	accountDetail, err := c.AccountDetail(AccountRequest{AccountID: aid})
+	accountDetail, err := c.AccountDetailContext(ctx, AccountRequest{AccountID: aid})
 	if err != nil {
 		return "", errors.Wrap(err, "get account detail failed")
 	}
@@ -792,14 +1218,24 @@ func (c *Client) HomeDomainForAccount(aid string) (string, error) {
 // NextTradeAggregationsPage returns the next page of trade aggregations from the current
 // trade aggregations response.
 func (c *Client) NextTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (ta hProtocol.TradeAggregationsPage, err error) {
-	err = c.sendRequestURL(page.Links.Next.Href, "get", &ta)
+	return c.NextTradeAggregationsPageContext(context.Background(), page)
+}
+
+// NextTradeAggregationsPageContext is like NextTradeAggregationsPage but uses ctx for the requests to horizon.
+func (c *Client) NextTradeAggregationsPageContext(ctx context.Context, page hProtocol.TradeAggregationsPage) (ta hProtocol.TradeAggregationsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Next.Href, "get", &ta)
 	return
 }
 
 // PrevTradeAggregationsPage returns the previous page of trade aggregations from the current
 // trade aggregations response.
 func (c *Client) PrevTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (ta hProtocol.TradeAggregationsPage, err error) {
-	err = c.sendRequestURL(page.Links.Prev.Href, "get", &ta)
+	return c.PrevTradeAggregationsPageContext(context.Background(), page)
+}
+
+// PrevTradeAggregationsPageContext is like PrevTradeAggregationsPage but uses ctx for the requests to horizon.
+func (c *Client) PrevTradeAggregationsPageContext(ctx context.Context, page hProtocol.TradeAggregationsPage) (ta hProtocol.TradeAggregationsPage, err error) {
+	err = c.sendRequestURL(ctx, page.Links.Prev.Href, "get", &ta)
 	return
 }
 
@@ -1,6 +1,7 @@
 package horizonclient
 
 import (
+	"encoding/hex"
 	"encoding/json"
 
 	hProtocol "github.com/stellar/go/protocols/horizon"
@@ -78,3 +79,134 @@ func (herr *Error) ResultCodes() (*hProtocol.TransactionResultCodes, error) {
 
 	return &result, nil
 }
+
+// Result extracts the transaction result that triggered this error from the
+// extra fields.
+func (herr *Error) Result() (*xdr.TransactionResult, error) {
+	b64, err := herr.ResultString()
+	if err != nil {
+		return nil, err
+	}
+
+	var result xdr.TransactionResult
+	if err = xdr.SafeUnmarshalBase64(b64, &result); err != nil {
+		return nil, errors.Wrap(err, "xdr decode failed")
+	}
+	return &result, nil
+}
+
+// TransactionResultCode extracts the result code of the transaction that
+// triggered this error. For fee bump transactions whose inner transaction
+// failed, the result code of the inner transaction is returned.
+func (herr *Error) TransactionResultCode() (xdr.TransactionResultCode, error) {
+	result, err := herr.Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if innerResultPair, ok := result.Result.GetInnerResultPair(); ok {
+		return innerResultPair.Result.Result.Code, nil
+	}
+	return result.Result.Code, nil
+}
+
+// InnerTransactionResult extracts the hash and the result of the inner
+// transaction of the fee bump transaction that triggered this error. It returns
+// ErrNotFeeBumpResult if the transaction is not a fee bump transaction or was
+// rejected before its inner transaction was applied, e.g. because the fee
+// account cannot pay the fee.
+func (herr *Error) InnerTransactionResult() (*xdr.InnerTransactionResultPair, error) {
+	result, err := herr.Result()
+	if err != nil {
+		return nil, err
+	}
+
+	innerResultPair, ok := result.Result.GetInnerResultPair()
+	if !ok {
+		return nil, ErrNotFeeBumpResult
+	}
+	return &innerResultPair, nil
+}
+
+// InnerTransactionHash extracts the hex encoded hash of the inner transaction
+// of the fee bump transaction that triggered this error, which identifies the
+// transaction of the user whose fee was bumped.
+func (herr *Error) InnerTransactionHash() (string, error) {
+	innerResultPair, err := herr.InnerTransactionResult()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(innerResultPair.TransactionHash[:]), nil
+}
+
+// IsFeeBumpInnerFailed returns true if the fee bump transaction that triggered
+// this error was applied but its inner transaction failed, in which case the
+// fee was charged to the fee account of the fee bump transaction.
+func (herr *Error) IsFeeBumpInnerFailed() bool {
+	result, err := herr.Result()
+	return err == nil && result.Result.Code == xdr.TransactionResultCodeTxFeeBumpInnerFailed
+}
+
+// OperationResults extracts the results of the operations of the transaction
+// that triggered this error. There are no results when the transaction was
+// rejected before its operations were applied.
+func (herr *Error) OperationResults() ([]xdr.OperationResult, error) {
+	result, err := herr.Result()
+	if err != nil {
+		return nil, err
+	}
+
+	results, _ := result.OperationResults()
+	return results, nil
+}
+
+// IsBadSeq returns true if the transaction that triggered this error was
+// rejected because its sequence number is not the next sequence number of its
+// source account.
+func (herr *Error) IsBadSeq() bool {
+	code, err := herr.TransactionResultCode()
+	return err == nil && code == xdr.TransactionResultCodeTxBadSeq
+}
+
+// IsUnderfunded returns true if an operation of the transaction that triggered
+// this error failed because its source account does not have enough of the
+// asset it sends or sells.
+func (herr *Error) IsUnderfunded() bool {
+	results, err := herr.OperationResults()
+	if err != nil {
+		return false
+	}
+
+	for _, result := range results {
+		if isUnderfunded(result) {
+			return true
+		}
+	}
+	return false
+}
+
+func isUnderfunded(result xdr.OperationResult) bool {
+	if result.Code != xdr.OperationResultCodeOpInner || result.Tr == nil {
+		return false
+	}
+
+	tr := result.Tr
+	switch tr.Type {
+	case xdr.OperationTypeCreateAccount:
+		return tr.CreateAccountResult.Code == xdr.CreateAccountResultCodeCreateAccountUnderfunded
+	case xdr.OperationTypePayment:
+		return tr.PaymentResult.Code == xdr.PaymentResultCodePaymentUnderfunded
+	case xdr.OperationTypePathPaymentStrictReceive:
+		return tr.PathPaymentStrictReceiveResult.Code == xdr.PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveUnderfunded
+	case xdr.OperationTypePathPaymentStrictSend:
+		return tr.PathPaymentStrictSendResult.Code == xdr.PathPaymentStrictSendResultCodePathPaymentStrictSendUnderfunded
+	case xdr.OperationTypeManageSellOffer:
+		return tr.ManageSellOfferResult.Code == xdr.ManageSellOfferResultCodeManageSellOfferUnderfunded
+	case xdr.OperationTypeCreatePassiveSellOffer:
+		return tr.CreatePassiveSellOfferResult.Code == xdr.ManageSellOfferResultCodeManageSellOfferUnderfunded
+	case xdr.OperationTypeManageBuyOffer:
+		return tr.ManageBuyOfferResult.Code == xdr.ManageBuyOfferResultCodeManageBuyOfferUnderfunded
+	default:
+		return false
+	}
+}
@@ -3,6 +3,7 @@ package horizonclient
 import (
 	"testing"
 
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -92,3 +93,145 @@ func TestError_Envelope(t *testing.T) {
 		assert.Contains(t, err.Error(), "xdr decode")
 	}
 }
+
+func TestError_TransactionResultCode(t *testing.T) {
+	var herr Error
+	herr.Problem.Type = "transaction_failed"
+
+	// sad path: missing result_xdr extra
+	herr.Problem.Extras = make(map[string]interface{})
+	_, err := herr.TransactionResultCode()
+	assert.Equal(t, ErrResultNotPopulated, err)
+	assert.False(t, herr.IsBadSeq())
+
+	herr.Problem.Extras["result_xdr"] = mustMarshalResult(t, xdr.TransactionResult{
+		FeeCharged: 100,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxBadSeq,
+		},
+	})
+	code, err := herr.TransactionResultCode()
+	if assert.NoError(t, err) {
+		assert.Equal(t, xdr.TransactionResultCodeTxBadSeq, code)
+	}
+	assert.True(t, herr.IsBadSeq())
+	assert.False(t, herr.IsUnderfunded())
+
+	// the result code of the inner transaction of fee bump transactions
+	herr.Problem.Extras["result_xdr"] = mustMarshalResult(t, xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFeeBumpInnerFailed,
+			InnerResultPair: &xdr.InnerTransactionResultPair{
+				Result: xdr.InnerTransactionResult{
+					FeeCharged: 100,
+					Result: xdr.InnerTransactionResultResult{
+						Code: xdr.TransactionResultCodeTxBadSeq,
+					},
+				},
+			},
+		},
+	})
+	code, err = herr.TransactionResultCode()
+	if assert.NoError(t, err) {
+		assert.Equal(t, xdr.TransactionResultCodeTxBadSeq, code)
+	}
+	assert.True(t, herr.IsBadSeq())
+}
+
+func TestError_InnerTransactionResult(t *testing.T) {
+	var herr Error
+	herr.Problem.Type = "transaction_failed"
+	herr.Problem.Extras = make(map[string]interface{})
+
+	// sad path: not a fee bump transaction
+	herr.Problem.Extras["result_xdr"] = mustMarshalResult(t, xdr.TransactionResult{
+		FeeCharged: 100,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxBadSeq,
+		},
+	})
+	_, err := herr.InnerTransactionResult()
+	assert.Equal(t, ErrNotFeeBumpResult, err)
+	_, err = herr.InnerTransactionHash()
+	assert.Equal(t, ErrNotFeeBumpResult, err)
+	assert.False(t, herr.IsFeeBumpInnerFailed())
+
+	innerHash := xdr.Hash{1, 2, 3}
+	herr.Problem.Extras["result_xdr"] = mustMarshalResult(t, xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFeeBumpInnerFailed,
+			InnerResultPair: &xdr.InnerTransactionResultPair{
+				TransactionHash: innerHash,
+				Result: xdr.InnerTransactionResult{
+					FeeCharged: 100,
+					Result: xdr.InnerTransactionResultResult{
+						Code: xdr.TransactionResultCodeTxTooLate,
+					},
+				},
+			},
+		},
+	})
+	innerResultPair, err := herr.InnerTransactionResult()
+	if assert.NoError(t, err) {
+		assert.Equal(t, innerHash, innerResultPair.TransactionHash)
+		assert.Equal(t, xdr.TransactionResultCodeTxTooLate, innerResultPair.Result.Result.Code)
+	}
+	hash, err := herr.InnerTransactionHash()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0102030000000000000000000000000000000000000000000000000000000000", hash)
+	}
+	assert.True(t, herr.IsFeeBumpInnerFailed())
+}
+
+func TestError_OperationResults(t *testing.T) {
+	var herr Error
+	herr.Problem.Type = "transaction_failed"
+	herr.Problem.Extras = make(map[string]interface{})
+	herr.Problem.Extras["result_xdr"] = mustMarshalResult(t, xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFailed,
+			Results: &[]xdr.OperationResult{
+				{
+					Code: xdr.OperationResultCodeOpInner,
+					Tr: &xdr.OperationResultTr{
+						Type:          xdr.OperationTypeBumpSequence,
+						BumpSeqResult: &xdr.BumpSequenceResult{Code: xdr.BumpSequenceResultCodeBumpSequenceSuccess},
+					},
+				},
+				{
+					Code: xdr.OperationResultCodeOpInner,
+					Tr: &xdr.OperationResultTr{
+						Type:          xdr.OperationTypePayment,
+						PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentUnderfunded},
+					},
+				},
+			},
+		},
+	})
+
+	results, err := herr.OperationResults()
+	if assert.NoError(t, err) && assert.Len(t, results, 2) {
+		assert.Equal(t, xdr.PaymentResultCodePaymentUnderfunded, results[1].Tr.PaymentResult.Code)
+	}
+	assert.True(t, herr.IsUnderfunded())
+	assert.False(t, herr.IsBadSeq())
+
+	// sad path: unparseable result_xdr extra
+	herr.Problem.Extras["result_xdr"] = "AAAAAAAAAMj"
+	_, err = herr.OperationResults()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "xdr decode")
+	}
+	assert.False(t, herr.IsUnderfunded())
+}
+
+func mustMarshalResult(t *testing.T, result xdr.TransactionResult) string {
+	b64, err := xdr.MarshalBase64(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b64
+}
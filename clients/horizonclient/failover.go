@@ -0,0 +1,187 @@
+package horizonclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// DefaultMaxIngestionLag is the default number of ledgers a horizon
+	// instance can be behind stellar-core and still be considered healthy by
+	// FailoverClient.
+	DefaultMaxIngestionLag = 10
+	// DefaultHealthCheckInterval is the default time between two health checks
+	// of the horizon instances of FailoverClient.
+	DefaultHealthCheckInterval = 30 * time.Second
+)
+
+// FailoverClient routes requests to one of several horizon instances, for
+// example of different providers, failing over to the next instance when a
+// request fails with an error another instance may not have: a network error,
+// a 429 or a 5xx response. Instances which are unhealthy, because they do not
+// respond or their ingestion is behind stellar-core, are skipped until they
+// are healthy again.
+//
+// Instances are tried in the order of Clients, so the preferred instance comes
+// first. When no instance is healthy, all the instances are tried.
+type FailoverClient struct {
+	// Clients are the clients of the horizon instances.
+	Clients []*Client
+	// MaxIngestionLag is the number of ledgers a horizon instance can be
+	// behind stellar-core and still be considered healthy.
+	MaxIngestionLag int32
+	// HealthCheckInterval is the time between two health checks of the
+	// instances by Run.
+	HealthCheckInterval time.Duration
+
+	mutex     sync.RWMutex
+	unhealthy map[*Client]bool
+}
+
+// NewFailoverClient returns a FailoverClient routing requests to the horizon
+// instances at `horizonURLs`, in order of preference.
+func NewFailoverClient(horizonURLs ...string) *FailoverClient {
+	clients := make([]*Client, 0, len(horizonURLs))
+	for _, horizonURL := range horizonURLs {
+		clients = append(clients, &Client{
+			HorizonURL:     horizonURL,
+			HTTP:           http.DefaultClient,
+			horizonTimeout: HorizonTimeout,
+		})
+	}
+	return &FailoverClient{
+		Clients:             clients,
+		MaxIngestionLag:     DefaultMaxIngestionLag,
+		HealthCheckInterval: DefaultHealthCheckInterval,
+	}
+}
+
+// Do calls fn with the client of the first healthy horizon instance, then with
+// the clients of the next instances while fn fails with an error another
+// instance may not have, until ctx is done. The instances for which fn failed
+// are considered unhealthy until their next health check. It returns the error
+// of the last call to fn.
+func (f *FailoverClient) Do(ctx context.Context, fn func(client *Client) error) error {
+	if len(f.Clients) == 0 {
+		return errors.New("no horizon instances")
+	}
+
+	var err error
+	for _, client := range f.candidates() {
+		err = fn(client)
+		if err == nil || ctx.Err() != nil || !isFailoverError(err) {
+			return err
+		}
+		f.setHealthy(client, false)
+	}
+	return err
+}
+
+// CheckHealth checks the health of all the horizon instances concurrently.
+func (f *FailoverClient) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, client := range f.Clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			f.setHealthy(client, f.isHealthy(ctx, client))
+		}(client)
+	}
+	wg.Wait()
+}
+
+// Run checks the health of the horizon instances every HealthCheckInterval
+// until ctx is done.
+func (f *FailoverClient) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		f.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Healthy returns the clients of the horizon instances which are healthy.
+func (f *FailoverClient) Healthy() []*Client {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	var healthy []*Client
+	for _, client := range f.Clients {
+		if !f.unhealthy[client] {
+			healthy = append(healthy, client)
+		}
+	}
+	return healthy
+}
+
+// candidates returns the clients of the healthy instances followed by the
+// clients of the unhealthy instances.
+func (f *FailoverClient) candidates() []*Client {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	candidates := make([]*Client, 0, len(f.Clients))
+	for _, client := range f.Clients {
+		if !f.unhealthy[client] {
+			candidates = append(candidates, client)
+		}
+	}
+	for _, client := range f.Clients {
+		if f.unhealthy[client] {
+			candidates = append(candidates, client)
+		}
+	}
+	return candidates
+}
+
+func (f *FailoverClient) setHealthy(client *Client, healthy bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.unhealthy == nil {
+		f.unhealthy = map[*Client]bool{}
+	}
+	if healthy {
+		delete(f.unhealthy, client)
+	} else {
+		f.unhealthy[client] = true
+	}
+}
+
+// isHealthy returns true if the horizon instance of `client` responds and its
+// ingestion is at most MaxIngestionLag ledgers behind stellar-core.
+func (f *FailoverClient) isHealthy(ctx context.Context, client *Client) bool {
+	root, err := client.RootContext(ctx)
+	if err != nil {
+		return false
+	}
+	if root.CoreSequence == 0 {
+		// stellar-core is not synced, so transactions cannot be submitted
+		return false
+	}
+	return root.CoreSequence-root.HorizonSequence <= f.MaxIngestionLag
+}
+
+// isFailoverError returns true if `err` may not happen when sending the same
+// request to another horizon instance.
+func isFailoverError(err error) bool {
+	if horizonError := GetError(err); horizonError != nil {
+		if horizonError.Response == nil {
+			return false
+		}
+		status := horizonError.Response.StatusCode
+		return status == http.StatusTooManyRequests || status >= 500
+	}
+	_, ok := errors.Cause(err).(net.Error)
+	return ok
+}
@@ -0,0 +1,109 @@
+package horizonclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailoverClient(t *testing.T) {
+	hmock := httptest.NewClient()
+	failover := NewFailoverClient("https://a/", "https://b/")
+	for _, client := range failover.Clients {
+		client.HTTP = hmock
+	}
+	a, b := failover.Clients[0], failover.Clients[1]
+	accountRequest := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+
+	// requests are sent to the first instance
+	hmock.On(
+		"GET",
+		"https://a/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	var used []*Client
+	err := failover.Do(context.Background(), func(client *Client) error {
+		used = append(used, client)
+		_, err := client.AccountDetail(accountRequest)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Client{a}, used)
+
+	// requests fail over to the next instance on 5xx responses
+	hmock.On(
+		"GET",
+		"https://a/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(503, notFoundResponse)
+	hmock.On(
+		"GET",
+		"https://b/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	used = nil
+	err = failover.Do(context.Background(), func(client *Client) error {
+		used = append(used, client)
+		_, err := client.AccountDetail(accountRequest)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Client{a, b}, used)
+	assert.Equal(t, []*Client{b}, failover.Healthy())
+
+	// unhealthy instances are tried last
+	hmock.On(
+		"GET",
+		"https://b/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	used = nil
+	err = failover.Do(context.Background(), func(client *Client) error {
+		used = append(used, client)
+		_, err := client.AccountDetail(accountRequest)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Client{b}, used)
+
+	// other errors are returned
+	hmock.On(
+		"GET",
+		"https://b/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(404, notFoundResponse)
+	used = nil
+	err = failover.Do(context.Background(), func(client *Client) error {
+		used = append(used, client)
+		_, err := client.AccountDetail(accountRequest)
+		return err
+	})
+	assert.True(t, IsNotFoundError(err))
+	assert.Equal(t, []*Client{b}, used)
+}
+
+func TestFailoverClientCheckHealth(t *testing.T) {
+	hmock := httptest.NewClient()
+	failover := NewFailoverClient("https://a/", "https://b/", "https://c/")
+	for _, client := range failover.Clients {
+		client.HTTP = hmock
+	}
+
+	hmock.On("GET", "https://a/").ReturnString(200, rootResponse)
+	// the ingestion of b is 11 ledgers behind stellar-core
+	hmock.On("GET", "https://b/").ReturnString(
+		200,
+		strings.Replace(rootResponse, `"history_latest_ledger": 84959`, `"history_latest_ledger": 84948`, 1),
+	)
+	hmock.On("GET", "https://c/").ReturnError("connection refused")
+
+	failover.CheckHealth(context.Background())
+	assert.Equal(t, []*Client{failover.Clients[0]}, failover.Healthy())
+
+	hmock.On("GET", "https://a/").ReturnString(200, rootResponse)
+	hmock.On("GET", "https://b/").ReturnString(
+		200,
+		strings.Replace(rootResponse, `"history_latest_ledger": 84959`, `"history_latest_ledger": 84948`, 1),
+	)
+	hmock.On("GET", "https://c/").ReturnString(200, rootResponse)
+	failover.CheckHealth(context.Background())
+	assert.Equal(t, []*Client{failover.Clients[0], failover.Clients[2]}, failover.Healthy())
+}
@@ -0,0 +1,108 @@
+package horizonclient
+
+import (
+	"context"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// DefaultMaxSuggestedFee is the default maximum per-operation fee, in stroops,
+// suggested by SuggestFee.
+const DefaultMaxSuggestedFee int64 = 10000
+
+// feeStatsCacheTTL is how long the fee stats fetched by SuggestFee are reused,
+// about the time between two ledgers.
+var feeStatsCacheTTL = 5 * time.Second
+
+// SuggestFee returns a per-operation fee, in stroops, to set as the base fee of
+// transactions built with txnbuild. The fee is the given `percentile` of the
+// max fees of the transactions included in the last ledgers, which must be one
+// of 10, 20, 30, 40, 50, 60, 70, 80, 90, 95 or 99.
+//
+// The fee suggested is at least the base fee of the last ledger and at most
+// MaxSuggestedFee, or DefaultMaxSuggestedFee when it is not set, so that a fee
+// surge cannot drain the accounts paying the fees. The fee stats of horizon are
+// fetched at most once every 5 seconds.
+func (c *Client) SuggestFee(ctx context.Context, percentile int) (int64, error) {
+	if _, err := feeStatsPercentile(hProtocol.FeeDistribution{}, percentile); err != nil {
+		return 0, err
+	}
+
+	stats, err := c.cachedFeeStats(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get fee stats")
+	}
+
+	fee, err := feeStatsPercentile(stats.MaxFee, percentile)
+	if err != nil {
+		return 0, err
+	}
+	if fee < stats.LastLedgerBaseFee {
+		fee = stats.LastLedgerBaseFee
+	}
+	if fee < txnbuild.MinBaseFee {
+		fee = txnbuild.MinBaseFee
+	}
+
+	maxFee := c.MaxSuggestedFee
+	if maxFee <= 0 {
+		maxFee = DefaultMaxSuggestedFee
+	}
+	if fee > maxFee {
+		fee = maxFee
+	}
+	return fee, nil
+}
+
+// cachedFeeStats returns the fee stats of horizon, fetching them again once
+// they are older than feeStatsCacheTTL.
+func (c *Client) cachedFeeStats(ctx context.Context) (hProtocol.FeeStats, error) {
+	c.feeStatsMutex.Lock()
+	defer c.feeStatsMutex.Unlock()
+
+	now := c.clock.Now()
+	if !c.feeStatsFetchedAt.IsZero() && now.Sub(c.feeStatsFetchedAt) < feeStatsCacheTTL {
+		return c.feeStats, nil
+	}
+
+	stats, err := c.FeeStatsContext(ctx)
+	if err != nil {
+		return stats, err
+	}
+	c.feeStats = stats
+	c.feeStatsFetchedAt = now
+	return stats, nil
+}
+
+// feeStatsPercentile returns the given `percentile` of `distribution`.
+func feeStatsPercentile(distribution hProtocol.FeeDistribution, percentile int) (int64, error) {
+	switch percentile {
+	case 10:
+		return distribution.P10, nil
+	case 20:
+		return distribution.P20, nil
+	case 30:
+		return distribution.P30, nil
+	case 40:
+		return distribution.P40, nil
+	case 50:
+		return distribution.P50, nil
+	case 60:
+		return distribution.P60, nil
+	case 70:
+		return distribution.P70, nil
+	case 80:
+		return distribution.P80, nil
+	case 90:
+		return distribution.P90, nil
+	case 95:
+		return distribution.P95, nil
+	case 99:
+		return distribution.P99, nil
+	default:
+		return 0, errors.Errorf("invalid percentile %d", percentile)
+	}
+}
@@ -0,0 +1,33 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+)
+
+type headersContextKey struct{}
+
+// WithHeaders returns a copy of ctx carrying `headers`, which are set on the
+// requests sent to horizon with the returned context, e.g. by AccountDetailContext.
+// They override the headers of Client.Headers with the same names.
+func WithHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, headersContextKey{}, headers)
+}
+
+// setHeaders sets the headers of the client and the headers carried by ctx
+// on req.
+func (c *Client) setHeaders(ctx context.Context, req *http.Request) {
+	setHeaders(req, c.Headers)
+	if headers, ok := ctx.Value(headersContextKey{}).(http.Header); ok {
+		setHeaders(req, headers)
+	}
+}
+
+func setHeaders(req *http.Request, headers http.Header) {
+	for name, values := range headers {
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+}
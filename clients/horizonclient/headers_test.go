@@ -0,0 +1,50 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaders(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		Headers: http.Header{
+			"X-Api-Key":     []string{"client-key"},
+			"Authorization": []string{"Bearer client"},
+		},
+	}
+
+	var header http.Header
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).Return(func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		return httpmock.NewStringResponse(200, accountResponse), nil
+	})
+	accountRequest := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+
+	_, err := client.AccountDetail(accountRequest)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "client-key", header.Get("X-Api-Key"))
+		assert.Equal(t, "Bearer client", header.Get("Authorization"))
+		assert.Equal(t, "go-stellar-sdk", header.Get("X-Client-Name"))
+	}
+
+	// headers of the context override the headers of the client
+	ctx := WithHeaders(context.Background(), http.Header{
+		"Authorization": []string{"Bearer request"},
+	})
+	_, err = client.AccountDetailContext(ctx, accountRequest)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "client-key", header.Get("X-Api-Key"))
+		assert.Equal(t, []string{"Bearer request"}, header["Authorization"])
+	}
+}
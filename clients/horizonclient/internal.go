@@ -13,7 +13,11 @@ import (
 // decodeResponse decodes the response from a request to a horizon server
 func decodeResponse(resp *http.Response, object interface{}, hc *Client) (err error) {
 	defer resp.Body.Close()
-	decoder := json.NewDecoder(resp.Body)
+	body, err := hc.responseBody(resp)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(body)
 
 	u, err := url.Parse(hc.HorizonURL)
 	if err != nil {
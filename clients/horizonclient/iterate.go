@@ -0,0 +1,370 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/effects"
+	"github.com/stellar/go/protocols/horizon/operations"
+)
+
+// iterateRateLimitBackoff is the time waited before fetching a page again
+// after horizon rejected the request because of its rate limit, when horizon
+// does not specify when the rate limit is reset.
+var iterateRateLimitBackoff = time.Second
+
+// iterate fetches a page with `first` and the pages following it with `next`,
+// calling `handlePage` with every page until a page has no records,
+// `handlePage` returns an error or ctx is done. `handlePage` returns the number
+// of records of the page.
+//
+// Pages rejected because of the rate limit of horizon are fetched again once
+// the rate limit is reset, so `next` must keep the current page when it fails.
+func (c *Client) iterate(
+	ctx context.Context,
+	first func(ctx context.Context) error,
+	next func(ctx context.Context) error,
+	handlePage func() (int, error),
+) error {
+	fetch := first
+	for {
+		if err := c.fetchPage(ctx, fetch); err != nil {
+			return err
+		}
+		records, err := handlePage()
+		if err != nil || records == 0 {
+			return err
+		}
+		fetch = next
+	}
+}
+
+// fetchPage calls fetch until it does not fail because of the rate limit of
+// horizon, waiting for the rate limit to be reset between the attempts.
+func (c *Client) fetchPage(ctx context.Context, fetch func(ctx context.Context) error) error {
+	for {
+		err := fetch(ctx)
+		horizonError := GetError(err)
+		if horizonError == nil || horizonError.Response.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+
+		wait := rateLimitReset(horizonError.Response)
+		if wait <= 0 {
+			wait = iterateRateLimitBackoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimitReset returns how long to wait before the rate limit of horizon is
+// reset, according to the Retry-After or X-RateLimit-Reset headers of `resp`.
+func rateLimitReset(resp *http.Response) time.Duration {
+	if wait := retryAfter(resp); wait > 0 {
+		return wait
+	}
+	seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// IterateAssets calls handler with every asset matching request, following the
+// next links of the pages returned by horizon, until there are no more assets,
+// handler returns an error or ctx is done.
+func (c *Client) IterateAssets(ctx context.Context, request AssetRequest, handler func(hProtocol.AssetStat) error) error {
+	var page hProtocol.AssetsPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.AssetsContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextAssetsPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateLedgers calls handler with every ledger matching request, following the
+// next links of the pages returned by horizon, until there are no more ledgers,
+// handler returns an error or ctx is done.
+func (c *Client) IterateLedgers(ctx context.Context, request LedgerRequest, handler func(hProtocol.Ledger) error) error {
+	var page hProtocol.LedgersPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.LedgersContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextLedgersPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateEffects calls handler with every effect matching request, following the
+// next links of the pages returned by horizon, until there are no more effects,
+// handler returns an error or ctx is done.
+func (c *Client) IterateEffects(ctx context.Context, request EffectRequest, handler func(effects.Effect) error) error {
+	var page effects.EffectsPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.EffectsContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextEffectsPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateClaimableBalances calls handler with every claimable balance matching request,
+// following the next links of the pages returned by horizon, until there are no more
+// claimable balances, handler returns an error or ctx is done.
+func (c *Client) IterateClaimableBalances(ctx context.Context, request ClaimableBalanceRequest, handler func(hProtocol.ClaimableBalance) error) error {
+	var page hProtocol.ClaimableBalancesPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.ClaimableBalancesContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextClaimableBalancesPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateOffers calls handler with every offer matching request, following the
+// next links of the pages returned by horizon, until there are no more offers,
+// handler returns an error or ctx is done.
+func (c *Client) IterateOffers(ctx context.Context, request OfferRequest, handler func(hProtocol.Offer) error) error {
+	var page hProtocol.OffersPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.OffersContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextOffersPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateOperations calls handler with every operation matching request, following the
+// next links of the pages returned by horizon, until there are no more operations,
+// handler returns an error or ctx is done.
+func (c *Client) IterateOperations(ctx context.Context, request OperationRequest, handler func(operations.Operation) error) error {
+	var page operations.OperationsPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.OperationsContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextOperationsPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IteratePayments calls handler with every payment matching request, following the
+// next links of the pages returned by horizon, until there are no more payments,
+// handler returns an error or ctx is done.
+func (c *Client) IteratePayments(ctx context.Context, request OperationRequest, handler func(operations.Operation) error) error {
+	var page operations.OperationsPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.PaymentsContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextPaymentsPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateTransactions calls handler with every transaction matching request, following the
+// next links of the pages returned by horizon, until there are no more transactions,
+// handler returns an error or ctx is done.
+func (c *Client) IterateTransactions(ctx context.Context, request TransactionRequest, handler func(hProtocol.Transaction) error) error {
+	var page hProtocol.TransactionsPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.TransactionsContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextTransactionsPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateTrades calls handler with every trade matching request, following the
+// next links of the pages returned by horizon, until there are no more trades,
+// handler returns an error or ctx is done.
+func (c *Client) IterateTrades(ctx context.Context, request TradeRequest, handler func(hProtocol.Trade) error) error {
+	var page hProtocol.TradesPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.TradesContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextTradesPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
+
+// IterateTradeAggregations calls handler with every trade aggregation matching request, following the
+// next links of the pages returned by horizon, until there are no more trade aggregations,
+// handler returns an error or ctx is done.
+func (c *Client) IterateTradeAggregations(ctx context.Context, request TradeAggregationRequest, handler func(hProtocol.TradeAggregation) error) error {
+	var page hProtocol.TradeAggregationsPage
+	return c.iterate(
+		ctx,
+		func(ctx context.Context) (err error) {
+			page, err = c.TradeAggregationsContext(ctx, request)
+			return
+		},
+		func(ctx context.Context) error {
+			next, err := c.NextTradeAggregationsPageContext(ctx, page)
+			if err == nil {
+				page = next
+			}
+			return err
+		},
+		func() (int, error) {
+			for _, record := range page.Embedded.Records {
+				if err := handler(record); err != nil {
+					return 0, err
+				}
+			}
+			return len(page.Embedded.Records), nil
+		},
+	)
+}
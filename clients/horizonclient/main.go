@@ -92,6 +92,28 @@ var (
 	// when any of the destination accounts required a memo in the transaction.
 	ErrAccountRequiresMemo = errors.New("destination account requires a memo in the transaction")
 
+	// ErrTransactionFailed is the error returned from a call to SubmitTransactionAndWait
+	// when the transaction was included in a ledger but failed.
+	ErrTransactionFailed = errors.New("transaction failed")
+
+	// ErrTransactionExpired is the error returned from a call to SubmitTransactionAndWait
+	// when the time bounds of the transaction expired before it was included in a ledger.
+	ErrTransactionExpired = errors.New("transaction expired before it was included in a ledger")
+
+	// ErrNotFeeBumpResult is the error returned when getting the inner transaction result
+	// of a transaction which is not a fee bump transaction or whose inner transaction was
+	// not applied.
+	ErrNotFeeBumpResult = errors.New("result is not the result of an applied fee bump transaction")
+
+	// ErrResponseTooLarge is the error returned when the body of a response of horizon,
+	// or an event of a stream, is larger than the MaxResponseSize of the client.
+	ErrResponseTooLarge = errors.New("horizon response is too large")
+
+	// ErrNoPathFound is the error returned by PopulateStrictReceivePath and
+	// PopulateStrictSendPath when horizon does not find any path matching the
+	// payment.
+	ErrNoPathFound = errors.New("no path found for the payment")
+
 	// HorizonTimeout is the default number of nanoseconds before a request to horizon times out.
 	HorizonTimeout = 60 * time.Second
 
@@ -114,6 +136,14 @@ var (
 	WeekResolution = time.Duration(168 * time.Hour)
 )
 
+var (
+	// streamReconnectMinBackoff is the time waited before reconnecting a stream
+	// after a network error, doubled after every consecutive failure up to
+	// streamReconnectMaxBackoff.
+	streamReconnectMinBackoff = time.Second
+	streamReconnectMaxBackoff = 30 * time.Second
+)
+
 // HTTP represents the HTTP client that a horizon client uses to communicate
 type HTTP interface {
 	Do(req *http.Request) (resp *http.Response, err error)
@@ -126,6 +156,21 @@ type HTTP interface {
 // transaction timebounds.
 type UniversalTimeHandler func() int64
 
+// RequestHook is a function that is called with every request before it is sent to horizon,
+// e.g. to set authentication or tracing headers. An error returned by the hook aborts the request.
+type RequestHook func(req *http.Request) error
+
+// ResponseHook is a function that is called after every request sent to horizon, e.g. to log
+// or time the requests, with the response or the error and the duration of the request. For
+// streams, the hook is called once the response headers are received. The hook must not read
+// or close the body of the response.
+type ResponseHook func(req *http.Request, resp *http.Response, err error, duration time.Duration)
+
+// StreamReconnectHandler is a function that is called when a stream reconnects to horizon,
+// with the cursor the stream resumes from and the network error which interrupted it, or nil
+// if the connection was closed by horizon.
+type StreamReconnectHandler func(cursor string, err error)
+
 // Client struct contains data for creating a horizon client that connects to the stellar network.
 type Client struct {
 	// URL of Horizon server to connect
@@ -138,12 +183,61 @@ type Client struct {
 	AppName string
 
 	// AppVersion is the version of the application using the horizonclient package
-	AppVersion     string
+	AppVersion string
+
+	// RetryPolicy configures the retries of the requests which failed with a
+	// transient error. Requests are not retried when it is nil.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter, if set, delays the requests which would exceed the rate
+	// limit of horizon.
+	RateLimiter *RateLimiter
+
+	// StreamReconnectHandler, if set, is called every time a stream reconnects to horizon.
+	StreamReconnectHandler StreamReconnectHandler
+
+	// Headers are set on every request sent to horizon, e.g. to pass the
+	// credentials of an authenticated horizon deployment. Use WithHeaders to
+	// set headers on some requests only.
+	Headers http.Header
+
+	// RequestHooks are called, in order, with every request before it is sent to horizon.
+	RequestHooks []RequestHook
+
+	// ResponseHooks are called, in order, after every request sent to horizon.
+	ResponseHooks []ResponseHook
+
+	// MaxSuggestedFee caps the per-operation fees, in stroops, suggested by
+	// SuggestFee. DefaultMaxSuggestedFee is used when it is 0.
+	MaxSuggestedFee int64
+
+	// BatchConcurrency is the maximum number of requests sent concurrently to
+	// horizon by the batch methods, e.g. LoadAccounts. DefaultBatchConcurrency
+	// is used when it is 0.
+	BatchConcurrency int
+
+	// MaxResponseSize caps the size in bytes of the decompressed responses of
+	// horizon, and of every event of a stream. Larger responses fail with
+	// ErrResponseTooLarge. There is no limit when it is 0.
+	MaxResponseSize int64
+
+	// DisableCompression stops the client from asking horizon to compress its
+	// responses with gzip.
+	DisableCompression bool
+
 	horizonTimeout time.Duration
 	isTestNet      bool
 
 	// clock is a Clock returning the current time.
 	clock *clock.Clock
+
+	feeStatsMutex     sync.Mutex
+	feeStats          hProtocol.FeeStats
+	feeStatsFetchedAt time.Time
+
+	capabilitiesMutex     sync.Mutex
+	capabilities          Capabilities
+	capabilitiesFetchedAt time.Time
 }
 
 // SubmitTxOpts represents the submit transaction options
@@ -151,61 +245,210 @@ type SubmitTxOpts struct {
 	SkipMemoRequiredCheck bool
 }
 
-// ClientInterface contains methods implemented by the horizon client
+// ClientInterface contains methods implemented by the horizon client. It is made of
+// smaller interfaces, so that consumers can depend on and mock only the methods they use.
 type ClientInterface interface {
+	AccountsClient
+	AssetsClient
+	EffectsClient
+	LedgersClient
+	FeeStatsClient
+	ClaimableBalancesClient
+	OffersClient
+	OperationsClient
+	TransactionsClient
+	SubmitterClient
+	OrderBookClient
+	PathsClient
+	TradesClient
+	RootClient
+}
+
+// AccountsClient contains the methods of the horizon client about accounts.
+type AccountsClient interface {
 	Accounts(request AccountsRequest) (hProtocol.AccountsPage, error)
+	AccountsContext(ctx context.Context, request AccountsRequest) (hProtocol.AccountsPage, error)
 	AccountDetail(request AccountRequest) (hProtocol.Account, error)
+	AccountDetailContext(ctx context.Context, request AccountRequest) (hProtocol.Account, error)
 	AccountData(request AccountRequest) (hProtocol.AccountData, error)
-	Effects(request EffectRequest) (effects.EffectsPage, error)
+	AccountDataContext(ctx context.Context, request AccountRequest) (hProtocol.AccountData, error)
+	HomeDomainForAccount(aid string) (string, error)
+	HomeDomainForAccountContext(ctx context.Context, aid string) (string, error)
+	Fund(addr string) (hProtocol.Transaction, error)
+	FundContext(ctx context.Context, addr string) (hProtocol.Transaction, error)
+	LoadAccounts(ctx context.Context, accountIDs []string) (map[string]hProtocol.Account, error)
+	SequenceNumber(ctx context.Context, accountID string) (int64, error)
+}
+
+// AssetsClient contains the methods of the horizon client about assets.
+type AssetsClient interface {
 	Assets(request AssetRequest) (hProtocol.AssetsPage, error)
+	AssetsContext(ctx context.Context, request AssetRequest) (hProtocol.AssetsPage, error)
+	NextAssetsPage(hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	NextAssetsPageContext(context.Context, hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	PrevAssetsPage(hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	PrevAssetsPageContext(context.Context, hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	IterateAssets(ctx context.Context, request AssetRequest, handler func(hProtocol.AssetStat) error) error
+	AssetStatsForIssuers(ctx context.Context, issuers []string) (map[string][]hProtocol.AssetStat, error)
+}
+
+// EffectsClient contains the methods of the horizon client about effects.
+type EffectsClient interface {
+	Effects(request EffectRequest) (effects.EffectsPage, error)
+	EffectsContext(ctx context.Context, request EffectRequest) (effects.EffectsPage, error)
+	NextEffectsPage(effects.EffectsPage) (effects.EffectsPage, error)
+	NextEffectsPageContext(context.Context, effects.EffectsPage) (effects.EffectsPage, error)
+	PrevEffectsPage(effects.EffectsPage) (effects.EffectsPage, error)
+	PrevEffectsPageContext(context.Context, effects.EffectsPage) (effects.EffectsPage, error)
+	StreamEffects(ctx context.Context, request EffectRequest, handler EffectHandler) error
+	IterateEffects(ctx context.Context, request EffectRequest, handler func(effects.Effect) error) error
+}
+
+// LedgersClient contains the methods of the horizon client about ledgers.
+type LedgersClient interface {
 	Ledgers(request LedgerRequest) (hProtocol.LedgersPage, error)
+	LedgersContext(ctx context.Context, request LedgerRequest) (hProtocol.LedgersPage, error)
 	LedgerDetail(sequence uint32) (hProtocol.Ledger, error)
+	LatestLedgerCloseTime(ctx context.Context) (time.Time, error)
+	LedgerDetailContext(ctx context.Context, sequence uint32) (hProtocol.Ledger, error)
+	NextLedgersPage(hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	NextLedgersPageContext(context.Context, hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	PrevLedgersPage(hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	PrevLedgersPageContext(context.Context, hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	StreamLedgers(ctx context.Context, request LedgerRequest, handler LedgerHandler) error
+	IterateLedgers(ctx context.Context, request LedgerRequest, handler func(hProtocol.Ledger) error) error
+}
+
+// FeeStatsClient contains the methods of the horizon client about fees.
+type FeeStatsClient interface {
 	FeeStats() (hProtocol.FeeStats, error)
+	FeeStatsContext(ctx context.Context) (hProtocol.FeeStats, error)
+	SuggestFee(ctx context.Context, percentile int) (int64, error)
+}
+
+// ClaimableBalancesClient contains the methods of the horizon client about claimable balances.
+type ClaimableBalancesClient interface {
+	ClaimableBalances(request ClaimableBalanceRequest) (hProtocol.ClaimableBalancesPage, error)
+	ClaimableBalancesContext(ctx context.Context, request ClaimableBalanceRequest) (hProtocol.ClaimableBalancesPage, error)
+	ClaimableBalance(id string) (hProtocol.ClaimableBalance, error)
+	ClaimableBalanceContext(ctx context.Context, id string) (hProtocol.ClaimableBalance, error)
+	NextClaimableBalancesPage(hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error)
+	NextClaimableBalancesPageContext(context.Context, hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error)
+	PrevClaimableBalancesPage(hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error)
+	PrevClaimableBalancesPageContext(context.Context, hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error)
+	IterateClaimableBalances(ctx context.Context, request ClaimableBalanceRequest, handler func(hProtocol.ClaimableBalance) error) error
+}
+
+// OffersClient contains the methods of the horizon client about offers.
+type OffersClient interface {
 	Offers(request OfferRequest) (hProtocol.OffersPage, error)
+	OffersContext(ctx context.Context, request OfferRequest) (hProtocol.OffersPage, error)
 	OfferDetails(offerID string) (offer hProtocol.Offer, err error)
+	OfferDetailsContext(ctx context.Context, offerID string) (offer hProtocol.Offer, err error)
+	NextOffersPage(hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	NextOffersPageContext(context.Context, hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	PrevOffersPage(hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	PrevOffersPageContext(context.Context, hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	StreamOffers(ctx context.Context, request OfferRequest, handler OfferHandler) error
+	IterateOffers(ctx context.Context, request OfferRequest, handler func(hProtocol.Offer) error) error
+}
+
+// OperationsClient contains the methods of the horizon client about operations and payments.
+type OperationsClient interface {
 	Operations(request OperationRequest) (operations.OperationsPage, error)
+	OperationsContext(ctx context.Context, request OperationRequest) (operations.OperationsPage, error)
 	OperationDetail(id string) (operations.Operation, error)
+	OperationDetailContext(ctx context.Context, id string) (operations.Operation, error)
+	NextOperationsPage(operations.OperationsPage) (operations.OperationsPage, error)
+	NextOperationsPageContext(context.Context, operations.OperationsPage) (operations.OperationsPage, error)
+	PrevOperationsPage(operations.OperationsPage) (operations.OperationsPage, error)
+	PrevOperationsPageContext(context.Context, operations.OperationsPage) (operations.OperationsPage, error)
+	StreamOperations(ctx context.Context, request OperationRequest, handler OperationHandler) error
+	IterateOperations(ctx context.Context, request OperationRequest, handler func(operations.Operation) error) error
+	Payments(request OperationRequest) (operations.OperationsPage, error)
+	PaymentsContext(ctx context.Context, request OperationRequest) (operations.OperationsPage, error)
+	NextPaymentsPage(operations.OperationsPage) (operations.OperationsPage, error)
+	NextPaymentsPageContext(context.Context, operations.OperationsPage) (operations.OperationsPage, error)
+	PrevPaymentsPage(operations.OperationsPage) (operations.OperationsPage, error)
+	PrevPaymentsPageContext(context.Context, operations.OperationsPage) (operations.OperationsPage, error)
+	StreamPayments(ctx context.Context, request OperationRequest, handler OperationHandler) error
+	IteratePayments(ctx context.Context, request OperationRequest, handler func(operations.Operation) error) error
+}
+
+// TransactionsClient contains the methods of the horizon client about transactions.
+type TransactionsClient interface {
+	Transactions(request TransactionRequest) (hProtocol.TransactionsPage, error)
+	TransactionsContext(ctx context.Context, request TransactionRequest) (hProtocol.TransactionsPage, error)
+	TransactionDetail(txHash string) (hProtocol.Transaction, error)
+	TransactionDetailContext(ctx context.Context, txHash string) (hProtocol.Transaction, error)
+	NextTransactionsPage(hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	NextTransactionsPageContext(context.Context, hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	PrevTransactionsPage(hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	PrevTransactionsPageContext(context.Context, hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	StreamTransactions(ctx context.Context, request TransactionRequest, handler TransactionHandler) error
+	IterateTransactions(ctx context.Context, request TransactionRequest, handler func(hProtocol.Transaction) error) error
+}
+
+// SubmitterClient contains the methods of the horizon client submitting transactions.
+type SubmitterClient interface {
+	BuildTransaction(ctx context.Context, params txnbuild.TransactionParams) (*txnbuild.Transaction, error)
 	SubmitTransactionXDR(transactionXdr string) (hProtocol.Transaction, error)
-	SubmitFeeBumpTransactionWithOptions(transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
+	SubmitTransactionXDRContext(ctx context.Context, transactionXdr string) (hProtocol.Transaction, error)
+	SubmitTransaction(transaction *txnbuild.Transaction) (hProtocol.Transaction, error)
+	SubmitTransactionContext(ctx context.Context, transaction *txnbuild.Transaction) (hProtocol.Transaction, error)
 	SubmitTransactionWithOptions(transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
+	SubmitTransactionWithOptionsContext(ctx context.Context, transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
+	SubmitTransactionAndWait(ctx context.Context, transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
 	SubmitFeeBumpTransaction(transaction *txnbuild.FeeBumpTransaction) (hProtocol.Transaction, error)
-	SubmitTransaction(transaction *txnbuild.Transaction) (hProtocol.Transaction, error)
-	Transactions(request TransactionRequest) (hProtocol.TransactionsPage, error)
-	TransactionDetail(txHash string) (hProtocol.Transaction, error)
+	SubmitFeeBumpTransactionContext(ctx context.Context, transaction *txnbuild.FeeBumpTransaction) (hProtocol.Transaction, error)
+	SubmitFeeBumpTransactionWithOptions(transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
+	SubmitFeeBumpTransactionWithOptionsContext(ctx context.Context, transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
+	SubmitFeeBumpTransactionAndWait(ctx context.Context, transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
+}
+
+// OrderBookClient contains the methods of the horizon client about order books.
+type OrderBookClient interface {
 	OrderBook(request OrderBookRequest) (hProtocol.OrderBookSummary, error)
+	OrderBookContext(ctx context.Context, request OrderBookRequest) (hProtocol.OrderBookSummary, error)
+	StreamOrderBooks(ctx context.Context, request OrderBookRequest, handler OrderBookHandler) error
+}
+
+// PathsClient contains the methods of the horizon client finding payment paths.
+type PathsClient interface {
 	Paths(request PathsRequest) (hProtocol.PathsPage, error)
-	Payments(request OperationRequest) (operations.OperationsPage, error)
-	TradeAggregations(request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error)
+	PathsContext(ctx context.Context, request PathsRequest) (hProtocol.PathsPage, error)
+	StrictReceivePaths(request PathsRequest) (hProtocol.PathsPage, error)
+	StrictReceivePathsContext(ctx context.Context, request PathsRequest) (hProtocol.PathsPage, error)
+	StrictSendPaths(request StrictSendPathsRequest) (hProtocol.PathsPage, error)
+	StrictSendPathsContext(ctx context.Context, request StrictSendPathsRequest) (hProtocol.PathsPage, error)
+	PopulateStrictReceivePath(ctx context.Context, op *txnbuild.PathPaymentStrictReceive, maxPathLength int) (hProtocol.Path, error)
+	PopulateStrictSendPath(ctx context.Context, op *txnbuild.PathPaymentStrictSend, maxPathLength int) (hProtocol.Path, error)
+}
+
+// TradesClient contains the methods of the horizon client about trades and trade aggregations.
+type TradesClient interface {
 	Trades(request TradeRequest) (hProtocol.TradesPage, error)
-	Fund(addr string) (hProtocol.Transaction, error)
-	StreamTransactions(ctx context.Context, request TransactionRequest, handler TransactionHandler) error
-	StreamTrades(ctx context.Context, request TradeRequest, handler TradeHandler) error
-	StreamEffects(ctx context.Context, request EffectRequest, handler EffectHandler) error
-	StreamOperations(ctx context.Context, request OperationRequest, handler OperationHandler) error
-	StreamPayments(ctx context.Context, request OperationRequest, handler OperationHandler) error
-	StreamOffers(ctx context.Context, request OfferRequest, handler OfferHandler) error
-	StreamLedgers(ctx context.Context, request LedgerRequest, handler LedgerHandler) error
-	StreamOrderBooks(ctx context.Context, request OrderBookRequest, handler OrderBookHandler) error
-	Root() (hProtocol.Root, error)
-	NextAssetsPage(hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
-	PrevAssetsPage(hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
-	NextLedgersPage(hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
-	PrevLedgersPage(hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
-	NextEffectsPage(effects.EffectsPage) (effects.EffectsPage, error)
-	PrevEffectsPage(effects.EffectsPage) (effects.EffectsPage, error)
-	NextTransactionsPage(hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
-	PrevTransactionsPage(hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
-	NextOperationsPage(operations.OperationsPage) (operations.OperationsPage, error)
-	PrevOperationsPage(operations.OperationsPage) (operations.OperationsPage, error)
-	NextPaymentsPage(operations.OperationsPage) (operations.OperationsPage, error)
-	PrevPaymentsPage(operations.OperationsPage) (operations.OperationsPage, error)
-	NextOffersPage(hProtocol.OffersPage) (hProtocol.OffersPage, error)
-	PrevOffersPage(hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	TradesContext(ctx context.Context, request TradeRequest) (hProtocol.TradesPage, error)
 	NextTradesPage(hProtocol.TradesPage) (hProtocol.TradesPage, error)
+	NextTradesPageContext(context.Context, hProtocol.TradesPage) (hProtocol.TradesPage, error)
 	PrevTradesPage(hProtocol.TradesPage) (hProtocol.TradesPage, error)
-	HomeDomainForAccount(aid string) (string, error)
+	PrevTradesPageContext(context.Context, hProtocol.TradesPage) (hProtocol.TradesPage, error)
+	StreamTrades(ctx context.Context, request TradeRequest, handler TradeHandler) error
+	IterateTrades(ctx context.Context, request TradeRequest, handler func(hProtocol.Trade) error) error
+	TradeAggregations(request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error)
+	TradeAggregationsContext(ctx context.Context, request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error)
 	NextTradeAggregationsPage(hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	NextTradeAggregationsPageContext(context.Context, hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
 	PrevTradeAggregationsPage(hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	PrevTradeAggregationsPageContext(context.Context, hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	IterateTradeAggregations(ctx context.Context, request TradeAggregationRequest, handler func(hProtocol.TradeAggregation) error) error
+}
+
+// RootClient contains the methods of the horizon client about the horizon server.
+type RootClient interface {
+	Root() (hProtocol.Root, error)
+	RootContext(ctx context.Context) (hProtocol.Root, error)
+	Capabilities(ctx context.Context) (Capabilities, error)
 }
 
 // DefaultTestNetClient is a default client to connect to test network.
@@ -232,11 +475,12 @@ type HorizonRequest interface {
 // Either "Signer" or "Asset" fields should be set when retrieving Accounts.
 // At the moment, you can't use both filters at the same time.
 type AccountsRequest struct {
-	Signer string
-	Asset  string
-	Order  Order
-	Cursor string
-	Limit  uint
+	Signer  string
+	Asset   string
+	Sponsor string
+	Order   Order
+	Cursor  string
+	Limit   uint
 }
 
 // AccountRequest struct contains data for making requests to the show account endpoint of a horizon server.
@@ -285,6 +529,20 @@ type feeStatsRequest struct {
 	endpoint string
 }
 
+// ClaimableBalanceRequest struct contains data for getting claimable balances from a horizon server.
+// When "ID" is set, only the claimable balance with this ID is returned. Otherwise "Asset",
+// "Sponsor" and "Claimant" filter the claimable balances, all of them if none are set.
+// The query parameters (Order, Cursor and Limit) are optional. All or none can be set.
+type ClaimableBalanceRequest struct {
+	ID       string
+	Asset    string
+	Sponsor  string
+	Claimant string
+	Order    Order
+	Cursor   string
+	Limit    uint
+}
+
 // OfferRequest struct contains data for getting offers made by an account from a horizon server.
 // The query parameters (Order, Cursor and Limit) are optional. All or none can be set.
 type OfferRequest struct {
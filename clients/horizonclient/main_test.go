@@ -1,12 +1,14 @@
 package horizonclient
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	hProtocol "github.com/stellar/go/protocols/horizon"
@@ -164,7 +166,7 @@ func TestCheckMemoRequired(t *testing.T) {
 				).ReturnString(404, notFoundResponse)
 			}
 
-			err = client.checkMemoRequired(tx)
+			err = client.checkMemoRequired(context.Background(), tx)
 
 			if len(tc.expected) > 0 {
 				tt.Error(err)
@@ -188,7 +190,7 @@ func TestAccounts(t *testing.T) {
 	accountRequest := AccountsRequest{}
 	_, err := client.Accounts(accountRequest)
 	if tt.Error(err) {
-		tt.Contains(err.Error(), "invalid request: no parameters - Signer or Asset must be provided")
+		tt.Contains(err.Error(), "invalid request: no parameters - Signer, Asset or Sponsor must be provided")
 	}
 
 	accountRequest = AccountsRequest{
@@ -197,7 +199,7 @@ func TestAccounts(t *testing.T) {
 	}
 	_, err = client.Accounts(accountRequest)
 	if tt.Error(err) {
-		tt.Contains(err.Error(), "invalid request: too many parameters - Signer and Asset provided, provide a single filter")
+		tt.Contains(err.Error(), "invalid request: too many parameters - Signer, Asset and Sponsor are exclusive, provide a single filter")
 	}
 
 	var accounts hProtocol.AccountsPage
@@ -226,6 +228,18 @@ func TestAccounts(t *testing.T) {
 	tt.NoError(err)
 	tt.Len(accounts.Embedded.Records, 1)
 
+	hmock.On(
+		"GET",
+		"https://localhost/accounts?sponsor=GAI3SO3S4E67HAUZPZ2D3VBFXY4AT6N7WQI7K5WFGRXWENTZJG2B6CYP",
+	).ReturnString(200, accountsResponse)
+
+	accountRequest = AccountsRequest{
+		Sponsor: "GAI3SO3S4E67HAUZPZ2D3VBFXY4AT6N7WQI7K5WFGRXWENTZJG2B6CYP",
+	}
+	accounts, err = client.Accounts(accountRequest)
+	tt.NoError(err)
+	tt.Len(accounts.Embedded.Records, 1)
+
 	hmock.On(
 		"GET",
 		"https://localhost/accounts?signer=GAI3SO3S4E67HAUZPZ2D3VBFXY4AT6N7WQI7K5WFGRXWENTZJG2B6CYP&cursor=GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H&limit=200&order=desc",
@@ -341,6 +355,66 @@ func TestAccountDetail(t *testing.T) {
 	}
 }
 
+func TestAccountDetailContext(t *testing.T) {
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       http.DefaultClient,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	accountRequest := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+	_, err := client.AccountDetailContext(ctx, accountRequest)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), context.Canceled.Error())
+	}
+}
+
+func TestHooks(t *testing.T) {
+	hmock := httptest.NewClient()
+	var statuses []int
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		RequestHooks: []RequestHook{
+			func(req *http.Request) error {
+				req.Header.Set("Authorization", "Bearer token")
+				return nil
+			},
+		},
+		ResponseHooks: []ResponseHook{
+			func(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+				assert.NoError(t, err)
+				statuses = append(statuses, resp.StatusCode)
+			},
+		},
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).Return(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		return httpmock.NewStringResponse(200, accountResponse), nil
+	})
+
+	accountRequest := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+	_, err := client.AccountDetail(accountRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{200}, statuses)
+
+	// errors returned by request hooks abort the request
+	client.RequestHooks = append(client.RequestHooks, func(req *http.Request) error {
+		return errors.New("no credentials")
+	})
+	_, err = client.AccountDetail(accountRequest)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no credentials")
+	}
+	assert.Equal(t, []int{200}, statuses)
+}
+
 func TestAccountData(t *testing.T) {
 	hmock := httptest.NewClient()
 	client := &Client{
@@ -537,6 +611,56 @@ func TestFeeStats(t *testing.T) {
 	}
 }
 
+func TestSuggestFee(t *testing.T) {
+	hmock := httptest.NewClient()
+	now := time.Unix(1560947096, 0)
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		clock: &clock.Clock{
+			Source: clocktest.FixedSource(now),
+		},
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/fee_stats",
+	).ReturnString(200, feesResponse)
+
+	fee, err := client.SuggestFee(context.Background(), 50)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(500), fee)
+	}
+
+	// fees are capped at MaxSuggestedFee
+	client.MaxSuggestedFee = 3000
+	fee, err = client.SuggestFee(context.Background(), 99)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(3000), fee)
+	}
+
+	_, err = client.SuggestFee(context.Background(), 42)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid percentile 42")
+	}
+
+	// fee stats are cached
+	hmock.On(
+		"GET",
+		"https://localhost/fee_stats",
+	).ReturnString(500, "")
+	fee, err = client.SuggestFee(context.Background(), 10)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(150), fee)
+	}
+
+	client.clock = &clock.Clock{
+		Source: clocktest.FixedSource(now.Add(feeStatsCacheTTL)),
+	}
+	_, err = client.SuggestFee(context.Background(), 10)
+	assert.Error(t, err)
+}
+
 func TestOfferRequest(t *testing.T) {
 	hmock := httptest.NewClient()
 	client := &Client{
@@ -931,6 +1055,108 @@ func convertToV1Tx(t *testing.T, tx *txnbuild.Transaction) *txnbuild.Transaction
 	return tx
 }
 
+func TestSubmitTransactionAndWait(t *testing.T) {
+	defer func(interval time.Duration) { submitPollInterval = interval }(submitPollInterval)
+	submitPollInterval = time.Millisecond
+
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	kp := keypair.MustParseFull("SA26PHIKZM6CXDGR472SSGUQQRYXM6S437ZNHZGRM6QA4FOPLLLFRGDX")
+	sourceAccount := txnbuild.NewSimpleAccount(kp.Address(), int64(0))
+
+	payment := txnbuild.Payment{
+		Destination: kp.Address(),
+		Amount:      "10",
+		Asset:       txnbuild.NativeAsset{},
+	}
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           []txnbuild.Operation{&payment},
+			BaseFee:              txnbuild.MinBaseFee,
+			Timebounds:           txnbuild.NewInfiniteTimeout(),
+		},
+	)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp)
+	require.NoError(t, err)
+	txeBase64, err := tx.Base64()
+	require.NoError(t, err)
+	hash, err := tx.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	opts := SubmitTxOpts{SkipMemoRequiredCheck: true}
+
+	// the transaction is polled when the submission times out
+	hmock.On(
+		"POST",
+		"https://localhost/transactions?tx="+url.QueryEscape(txeBase64),
+	).ReturnString(504, timeoutResponse)
+	hmock.On(
+		"GET",
+		"https://localhost/",
+	).ReturnString(200, rootResponse)
+	polls := 0
+	hmock.On(
+		"GET",
+		"https://localhost/transactions/"+hash,
+	).Return(func(*http.Request) (*http.Response, error) {
+		polls++
+		if polls == 1 {
+			return httpmock.NewStringResponse(404, notFoundResponse), nil
+		}
+		return httpmock.NewStringResponse(200, txSuccess), nil
+	})
+
+	result, err := client.SubmitTransactionAndWait(context.Background(), tx, opts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "bcc7a97264dca0a51a63f7ea971b5e7458e334489673078bb2a34eb0cce910ca", result.Hash)
+		assert.Equal(t, 2, polls)
+	}
+
+	// other submission errors are returned right away
+	hmock.On(
+		"POST",
+		"https://localhost/transactions?tx="+url.QueryEscape(txeBase64),
+	).ReturnString(400, transactionFailure)
+	polls = 0
+	_, err = client.SubmitTransactionAndWait(context.Background(), tx, opts)
+	if assert.Error(t, err) {
+		assert.NotNil(t, GetError(err))
+		assert.Equal(t, 0, polls)
+	}
+
+	// polling stops when ctx is done
+	hmock.On(
+		"POST",
+		"https://localhost/transactions?tx="+url.QueryEscape(txeBase64),
+	).ReturnString(504, timeoutResponse)
+	hmock.On(
+		"GET",
+		"https://localhost/",
+	).ReturnString(200, rootResponse)
+	ctx, cancel := context.WithCancel(context.Background())
+	polls = 0
+	hmock.On(
+		"GET",
+		"https://localhost/transactions/"+hash,
+	).Return(func(*http.Request) (*http.Response, error) {
+		polls++
+		if polls == 3 {
+			cancel()
+		}
+		return httpmock.NewStringResponse(404, notFoundResponse), nil
+	})
+	_, err = client.SubmitTransactionAndWait(ctx, tx, opts)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 3, polls)
+}
+
 func TestSubmitFeeBumpTransaction(t *testing.T) {
 	hmock := httptest.NewClient()
 	client := &Client{
@@ -2193,6 +2419,13 @@ var opsResponse = `{
   "trustor": "GBMVGXJXJ7ZBHIWMXHKR6IVPDTYKHJPXC2DHZDPJBEZWZYAC7NKII7IB"
 }`
 
+var timeoutResponse = `{
+  "type": "https://stellar.org/horizon-errors/timeout",
+  "title": "Timeout",
+  "status": 504,
+  "detail": "Your request timed out before completing."
+}`
+
 var txSuccess = `{
 	"_links": {
 		"self": {
@@ -0,0 +1,100 @@
+package horizonclient
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics measures the requests sent to horizon by a Client, by endpoint: the
+// number of requests by status code, and their durations. Metrics is a
+// prometheus.Collector, so it can be registered in a Prometheus registry.
+//
+//	metrics := horizonclient.NewMetrics("myapp")
+//	prometheus.MustRegister(metrics)
+//	client.ResponseHooks = append(client.ResponseHooks, metrics.ResponseHook)
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics returns Metrics whose names are prefixed with `namespace`.
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "horizonclient",
+				Name:      "requests_total",
+				Help:      "Number of requests sent to horizon, by endpoint and status code. The status is \"error\" for requests which failed without a response.",
+			},
+			[]string{"method", "endpoint", "status"},
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: "horizonclient",
+				Name:      "request_duration_seconds",
+				Help:      "Duration of the requests sent to horizon, by endpoint, until the response headers are received.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"method", "endpoint"},
+		),
+	}
+}
+
+// ResponseHook records the request `req` in the metrics. Add it to the
+// ResponseHooks of the clients to measure.
+func (m *Metrics) ResponseHook(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	endpoint := endpointLabel(req.URL)
+	status := "error"
+	if err == nil && resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	m.requests.WithLabelValues(req.Method, endpoint, status).Inc()
+	m.duration.WithLabelValues(req.Method, endpoint).Observe(duration.Seconds())
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+var (
+	accountIDSegment = regexp.MustCompile(`^G[A-Z2-7]{55}$`)
+	hashSegment      = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	balanceIDSegment = regexp.MustCompile(`^[0-9a-f]{72}$`)
+	numberSegment    = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// endpointLabel returns the path of `u` where the segments identifying a
+// resource, like account IDs, hashes and numeric IDs, are replaced with
+// placeholders, so that the number of endpoint labels stays small.
+func endpointLabel(u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		switch {
+		case accountIDSegment.MatchString(segment):
+			segments[i] = "{account_id}"
+		case hashSegment.MatchString(segment):
+			segments[i] = "{hash}"
+		case balanceIDSegment.MatchString(segment), numberSegment.MatchString(segment):
+			segments[i] = "{id}"
+		case i > 0 && segments[i-1] == "data":
+			segments[i] = "{key}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
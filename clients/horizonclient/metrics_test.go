@@ -0,0 +1,66 @@
+package horizonclient
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics("test")
+	registry := prometheus.NewRegistry()
+	assert.NoError(t, registry.Register(metrics))
+
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:    "https://localhost/",
+		HTTP:          hmock,
+		ResponseHooks: []ResponseHook{metrics.ResponseHook},
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG",
+	).ReturnString(404, notFoundResponse)
+	hmock.On(
+		"GET",
+		"https://localhost/ledgers/1234",
+	).ReturnError("http.Client error")
+
+	_, err := client.AccountDetail(AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"})
+	assert.NoError(t, err)
+	_, err = client.AccountDetail(AccountRequest{AccountID: "GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG"})
+	assert.Error(t, err)
+	_, err = client.LedgerDetail(1234)
+	assert.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.requests.WithLabelValues("GET", "/accounts/{account_id}", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.requests.WithLabelValues("GET", "/accounts/{account_id}", "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.requests.WithLabelValues("GET", "/ledgers/{id}", "error")))
+
+	families, err := registry.Gather()
+	if assert.NoError(t, err) {
+		assert.Len(t, families, 2)
+	}
+}
+
+func TestEndpointLabel(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/":        "/",
+		"/ledgers": "/ledgers",
+		"/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU/data/config.memo_required": "/accounts/{account_id}/data/{key}",
+		"/transactions/3389e9f0f1a65f19736cacf544c2e825313e8447f569233bb8db39aa607c8889":               "/transactions/{hash}",
+		"/operations/77309415424/effects": "/operations/{id}/effects",
+		"/claimable_balances/00000000929b20b72e5890ab51c24f1cc46fa01c4f318d8d33367d24dd614cfdf5491072": "/claimable_balances/{id}",
+	} {
+		assert.Equal(t, expected, endpointLabel(&url.URL{Path: path}), path)
+	}
+}
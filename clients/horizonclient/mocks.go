@@ -2,6 +2,7 @@ package horizonclient
 
 import (
 	"context"
+	"time"
 
 	hProtocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/protocols/horizon/effects"
@@ -51,6 +52,12 @@ func (m *MockClient) Ledgers(request LedgerRequest) (hProtocol.LedgersPage, erro
 	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
 }
 
+// LatestLedgerCloseTime is a mocking method
+func (m *MockClient) LatestLedgerCloseTime(ctx context.Context) (time.Time, error) {
+	a := m.Called(ctx)
+	return a.Get(0).(time.Time), a.Error(1)
+}
+
 // LedgerDetail is a mocking method
 func (m *MockClient) LedgerDetail(sequence uint32) (hProtocol.Ledger, error) {
 	a := m.Called(sequence)
@@ -63,6 +70,24 @@ func (m *MockClient) FeeStats() (hProtocol.FeeStats, error) {
 	return a.Get(0).(hProtocol.FeeStats), a.Error(1)
 }
 
+// SuggestFee is a mocking method
+func (m *MockClient) SuggestFee(ctx context.Context, percentile int) (int64, error) {
+	a := m.Called(ctx, percentile)
+	return a.Get(0).(int64), a.Error(1)
+}
+
+// ClaimableBalances is a mocking method
+func (m *MockClient) ClaimableBalances(request ClaimableBalanceRequest) (hProtocol.ClaimableBalancesPage, error) {
+	a := m.Called(request)
+	return a.Get(0).(hProtocol.ClaimableBalancesPage), a.Error(1)
+}
+
+// ClaimableBalance is a mocking method
+func (m *MockClient) ClaimableBalance(id string) (hProtocol.ClaimableBalance, error) {
+	a := m.Called(id)
+	return a.Get(0).(hProtocol.ClaimableBalance), a.Error(1)
+}
+
 // Offers is a mocking method
 func (m *MockClient) Offers(request OfferRequest) (hProtocol.OffersPage, error) {
 	a := m.Called(request)
@@ -117,6 +142,24 @@ func (m *MockClient) SubmitTransactionWithOptions(transaction *txnbuild.Transact
 	return a.Get(0).(hProtocol.Transaction), a.Error(1)
 }
 
+// BuildTransaction is a mocking method
+func (m *MockClient) BuildTransaction(ctx context.Context, params txnbuild.TransactionParams) (*txnbuild.Transaction, error) {
+	a := m.Called(ctx, params)
+	return a.Get(0).(*txnbuild.Transaction), a.Error(1)
+}
+
+// SubmitTransactionAndWait is a mocking method
+func (m *MockClient) SubmitTransactionAndWait(ctx context.Context, transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, transaction, opts)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// SubmitFeeBumpTransactionAndWait is a mocking method
+func (m *MockClient) SubmitFeeBumpTransactionAndWait(ctx context.Context, transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, transaction, opts)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
 // Transactions is a mocking method
 func (m *MockClient) Transactions(request TransactionRequest) (hProtocol.TransactionsPage, error) {
 	a := m.Called(request)
@@ -141,6 +184,18 @@ func (m *MockClient) Paths(request PathsRequest) (hProtocol.PathsPage, error) {
 	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
 }
 
+// StrictReceivePaths is a mocking method
+func (m *MockClient) StrictReceivePaths(request PathsRequest) (hProtocol.PathsPage, error) {
+	a := m.Called(request)
+	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
+}
+
+// StrictSendPaths is a mocking method
+func (m *MockClient) StrictSendPaths(request StrictSendPathsRequest) (hProtocol.PathsPage, error) {
+	a := m.Called(request)
+	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
+}
+
 // Payments is a mocking method
 func (m *MockClient) Payments(request OperationRequest) (operations.OperationsPage, error) {
 	a := m.Called(request)
@@ -205,6 +260,74 @@ func (m *MockClient) StreamOrderBooks(ctx context.Context, request OrderBookRequ
 	return m.Called(ctx, request, handler).Error(0)
 }
 
+// LoadAccounts is a mocking method
+func (m *MockClient) LoadAccounts(ctx context.Context, accountIDs []string) (map[string]hProtocol.Account, error) {
+	a := m.Called(ctx, accountIDs)
+	return a.Get(0).(map[string]hProtocol.Account), a.Error(1)
+}
+
+// SequenceNumber is a mocking method
+func (m *MockClient) SequenceNumber(ctx context.Context, accountID string) (int64, error) {
+	a := m.Called(ctx, accountID)
+	return a.Get(0).(int64), a.Error(1)
+}
+
+// AssetStatsForIssuers is a mocking method
+func (m *MockClient) AssetStatsForIssuers(ctx context.Context, issuers []string) (map[string][]hProtocol.AssetStat, error) {
+	a := m.Called(ctx, issuers)
+	return a.Get(0).(map[string][]hProtocol.AssetStat), a.Error(1)
+}
+
+// IterateAssets is a mocking method
+func (m *MockClient) IterateAssets(ctx context.Context, request AssetRequest, handler func(hProtocol.AssetStat) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateLedgers is a mocking method
+func (m *MockClient) IterateLedgers(ctx context.Context, request LedgerRequest, handler func(hProtocol.Ledger) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateEffects is a mocking method
+func (m *MockClient) IterateEffects(ctx context.Context, request EffectRequest, handler func(effects.Effect) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateClaimableBalances is a mocking method
+func (m *MockClient) IterateClaimableBalances(ctx context.Context, request ClaimableBalanceRequest, handler func(hProtocol.ClaimableBalance) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateOffers is a mocking method
+func (m *MockClient) IterateOffers(ctx context.Context, request OfferRequest, handler func(hProtocol.Offer) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateOperations is a mocking method
+func (m *MockClient) IterateOperations(ctx context.Context, request OperationRequest, handler func(operations.Operation) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IteratePayments is a mocking method
+func (m *MockClient) IteratePayments(ctx context.Context, request OperationRequest, handler func(operations.Operation) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateTransactions is a mocking method
+func (m *MockClient) IterateTransactions(ctx context.Context, request TransactionRequest, handler func(hProtocol.Transaction) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateTrades is a mocking method
+func (m *MockClient) IterateTrades(ctx context.Context, request TradeRequest, handler func(hProtocol.Trade) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
+// IterateTradeAggregations is a mocking method
+func (m *MockClient) IterateTradeAggregations(ctx context.Context, request TradeAggregationRequest, handler func(hProtocol.TradeAggregation) error) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
 // Root is a mocking method
 func (m *MockClient) Root() (hProtocol.Root, error) {
 	a := m.Called()
@@ -281,6 +404,18 @@ func (m *MockClient) PrevPaymentsPage(page operations.OperationsPage) (operation
 	return m.PrevOperationsPage(page)
 }
 
+// NextClaimableBalancesPage is a mocking method
+func (m *MockClient) NextClaimableBalancesPage(page hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.ClaimableBalancesPage), a.Error(1)
+}
+
+// PrevClaimableBalancesPage is a mocking method
+func (m *MockClient) PrevClaimableBalancesPage(page hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.ClaimableBalancesPage), a.Error(1)
+}
+
 // NextOffersPage is a mocking method
 func (m *MockClient) NextOffersPage(page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
 	a := m.Called(page)
@@ -323,5 +458,327 @@ func (m *MockClient) PrevTradeAggregationsPage(page hProtocol.TradeAggregationsP
 	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
 }
 
+// AccountsContext is a mocking method
+func (m *MockClient) AccountsContext(ctx context.Context, request AccountsRequest) (hProtocol.AccountsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.AccountsPage), a.Error(1)
+}
+
+// AccountDetailContext is a mocking method
+func (m *MockClient) AccountDetailContext(ctx context.Context, request AccountRequest) (hProtocol.Account, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.Account), a.Error(1)
+}
+
+// AccountDataContext is a mocking method
+func (m *MockClient) AccountDataContext(ctx context.Context, request AccountRequest) (hProtocol.AccountData, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.AccountData), a.Error(1)
+}
+
+// EffectsContext is a mocking method
+func (m *MockClient) EffectsContext(ctx context.Context, request EffectRequest) (effects.EffectsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(effects.EffectsPage), a.Error(1)
+}
+
+// AssetsContext is a mocking method
+func (m *MockClient) AssetsContext(ctx context.Context, request AssetRequest) (hProtocol.AssetsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
+// LedgersContext is a mocking method
+func (m *MockClient) LedgersContext(ctx context.Context, request LedgerRequest) (hProtocol.LedgersPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
+// LedgerDetailContext is a mocking method
+func (m *MockClient) LedgerDetailContext(ctx context.Context, sequence uint32) (hProtocol.Ledger, error) {
+	a := m.Called(ctx, sequence)
+	return a.Get(0).(hProtocol.Ledger), a.Error(1)
+}
+
+// FeeStatsContext is a mocking method
+func (m *MockClient) FeeStatsContext(ctx context.Context) (hProtocol.FeeStats, error) {
+	a := m.Called(ctx)
+	return a.Get(0).(hProtocol.FeeStats), a.Error(1)
+}
+
+// ClaimableBalancesContext is a mocking method
+func (m *MockClient) ClaimableBalancesContext(ctx context.Context, request ClaimableBalanceRequest) (hProtocol.ClaimableBalancesPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.ClaimableBalancesPage), a.Error(1)
+}
+
+// ClaimableBalanceContext is a mocking method
+func (m *MockClient) ClaimableBalanceContext(ctx context.Context, id string) (hProtocol.ClaimableBalance, error) {
+	a := m.Called(ctx, id)
+	return a.Get(0).(hProtocol.ClaimableBalance), a.Error(1)
+}
+
+// OffersContext is a mocking method
+func (m *MockClient) OffersContext(ctx context.Context, request OfferRequest) (hProtocol.OffersPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
+// OfferDetailsContext is a mocking method
+func (m *MockClient) OfferDetailsContext(ctx context.Context, offerID string) (hProtocol.Offer, error) {
+	a := m.Called(ctx, offerID)
+	return a.Get(0).(hProtocol.Offer), a.Error(1)
+}
+
+// OperationsContext is a mocking method
+func (m *MockClient) OperationsContext(ctx context.Context, request OperationRequest) (operations.OperationsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// OperationDetailContext is a mocking method
+func (m *MockClient) OperationDetailContext(ctx context.Context, id string) (operations.Operation, error) {
+	a := m.Called(ctx, id)
+	return a.Get(0).(operations.Operation), a.Error(1)
+}
+
+// SubmitTransactionXDRContext is a mocking method
+func (m *MockClient) SubmitTransactionXDRContext(ctx context.Context, transactionXdr string) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, transactionXdr)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// SubmitFeeBumpTransactionWithOptionsContext is a mocking method
+func (m *MockClient) SubmitFeeBumpTransactionWithOptionsContext(ctx context.Context, transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, transaction, opts)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// SubmitTransactionWithOptionsContext is a mocking method
+func (m *MockClient) SubmitTransactionWithOptionsContext(ctx context.Context, transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, transaction, opts)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// SubmitFeeBumpTransactionContext is a mocking method
+func (m *MockClient) SubmitFeeBumpTransactionContext(ctx context.Context, transaction *txnbuild.FeeBumpTransaction) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, transaction)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// SubmitTransactionContext is a mocking method
+func (m *MockClient) SubmitTransactionContext(ctx context.Context, transaction *txnbuild.Transaction) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, transaction)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// TransactionsContext is a mocking method
+func (m *MockClient) TransactionsContext(ctx context.Context, request TransactionRequest) (hProtocol.TransactionsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
+// TransactionDetailContext is a mocking method
+func (m *MockClient) TransactionDetailContext(ctx context.Context, txHash string) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, txHash)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// OrderBookContext is a mocking method
+func (m *MockClient) OrderBookContext(ctx context.Context, request OrderBookRequest) (hProtocol.OrderBookSummary, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.OrderBookSummary), a.Error(1)
+}
+
+// PathsContext is a mocking method
+func (m *MockClient) PathsContext(ctx context.Context, request PathsRequest) (hProtocol.PathsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
+}
+
+// PaymentsContext is a mocking method
+func (m *MockClient) PaymentsContext(ctx context.Context, request OperationRequest) (operations.OperationsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// TradeAggregationsContext is a mocking method
+func (m *MockClient) TradeAggregationsContext(ctx context.Context, request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
+// TradesContext is a mocking method
+func (m *MockClient) TradesContext(ctx context.Context, request TradeRequest) (hProtocol.TradesPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
+// FundContext is a mocking method
+func (m *MockClient) FundContext(ctx context.Context, addr string) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, addr)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
+// RootContext is a mocking method
+func (m *MockClient) RootContext(ctx context.Context) (hProtocol.Root, error) {
+	a := m.Called(ctx)
+	return a.Get(0).(hProtocol.Root), a.Error(1)
+}
+
+// Capabilities is a mocking method
+func (m *MockClient) Capabilities(ctx context.Context) (Capabilities, error) {
+	a := m.Called(ctx)
+	return a.Get(0).(Capabilities), a.Error(1)
+}
+
+// NextAssetsPageContext is a mocking method
+func (m *MockClient) NextAssetsPageContext(ctx context.Context, page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
+// PrevAssetsPageContext is a mocking method
+func (m *MockClient) PrevAssetsPageContext(ctx context.Context, page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
+// NextLedgersPageContext is a mocking method
+func (m *MockClient) NextLedgersPageContext(ctx context.Context, page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
+// PrevLedgersPageContext is a mocking method
+func (m *MockClient) PrevLedgersPageContext(ctx context.Context, page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
+// NextEffectsPageContext is a mocking method
+func (m *MockClient) NextEffectsPageContext(ctx context.Context, page effects.EffectsPage) (effects.EffectsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(effects.EffectsPage), a.Error(1)
+}
+
+// PrevEffectsPageContext is a mocking method
+func (m *MockClient) PrevEffectsPageContext(ctx context.Context, page effects.EffectsPage) (effects.EffectsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(effects.EffectsPage), a.Error(1)
+}
+
+// NextTransactionsPageContext is a mocking method
+func (m *MockClient) NextTransactionsPageContext(ctx context.Context, page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
+// PrevTransactionsPageContext is a mocking method
+func (m *MockClient) PrevTransactionsPageContext(ctx context.Context, page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
+// NextOperationsPageContext is a mocking method
+func (m *MockClient) NextOperationsPageContext(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// PrevOperationsPageContext is a mocking method
+func (m *MockClient) PrevOperationsPageContext(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// NextPaymentsPageContext is a mocking method
+func (m *MockClient) NextPaymentsPageContext(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	return m.NextOperationsPageContext(ctx, page)
+}
+
+// PrevPaymentsPageContext is a mocking method
+func (m *MockClient) PrevPaymentsPageContext(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	return m.PrevOperationsPageContext(ctx, page)
+}
+
+// NextClaimableBalancesPageContext is a mocking method
+func (m *MockClient) NextClaimableBalancesPageContext(ctx context.Context, page hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.ClaimableBalancesPage), a.Error(1)
+}
+
+// PrevClaimableBalancesPageContext is a mocking method
+func (m *MockClient) PrevClaimableBalancesPageContext(ctx context.Context, page hProtocol.ClaimableBalancesPage) (hProtocol.ClaimableBalancesPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.ClaimableBalancesPage), a.Error(1)
+}
+
+// NextOffersPageContext is a mocking method
+func (m *MockClient) NextOffersPageContext(ctx context.Context, page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
+// PrevOffersPageContext is a mocking method
+func (m *MockClient) PrevOffersPageContext(ctx context.Context, page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
+// NextTradesPageContext is a mocking method
+func (m *MockClient) NextTradesPageContext(ctx context.Context, page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
+// PrevTradesPageContext is a mocking method
+func (m *MockClient) PrevTradesPageContext(ctx context.Context, page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
+// HomeDomainForAccountContext is a mocking method
+func (m *MockClient) HomeDomainForAccountContext(ctx context.Context, aid string) (string, error) {
+	a := m.Called(ctx, aid)
+	return a.Get(0).(string), a.Error(1)
+}
+
+// NextTradeAggregationsPageContext is a mocking method
+func (m *MockClient) NextTradeAggregationsPageContext(ctx context.Context, page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
+// PrevTradeAggregationsPageContext is a mocking method
+func (m *MockClient) PrevTradeAggregationsPageContext(ctx context.Context, page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
+// StrictReceivePathsContext is a mocking method
+func (m *MockClient) StrictReceivePathsContext(ctx context.Context, request PathsRequest) (hProtocol.PathsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
+}
+
+// StrictSendPathsContext is a mocking method
+func (m *MockClient) StrictSendPathsContext(ctx context.Context, request StrictSendPathsRequest) (hProtocol.PathsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
+}
+
+// PopulateStrictReceivePath is a mocking method
+func (m *MockClient) PopulateStrictReceivePath(ctx context.Context, op *txnbuild.PathPaymentStrictReceive, maxPathLength int) (hProtocol.Path, error) {
+	a := m.Called(ctx, op, maxPathLength)
+	return a.Get(0).(hProtocol.Path), a.Error(1)
+}
+
+// PopulateStrictSendPath is a mocking method
+func (m *MockClient) PopulateStrictSendPath(ctx context.Context, op *txnbuild.PathPaymentStrictSend, maxPathLength int) (hProtocol.Path, error) {
+	a := m.Called(ctx, op, maxPathLength)
+	return a.Get(0).(hProtocol.Path), a.Error(1)
+}
+
 // ensure that the MockClient implements ClientInterface
 var _ ClientInterface = &MockClient{}
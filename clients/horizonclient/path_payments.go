@@ -0,0 +1,189 @@
+package horizonclient
+
+import (
+	"context"
+
+	"github.com/stellar/go/amount"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// PopulateStrictReceivePath finds with horizon the paths from op.SendAsset to
+// op.DestAmount of op.DestAsset, and sets op.Path to the cheapest one, which
+// requires the smallest amount of op.SendAsset. When `maxPathLength` is not 0,
+// only the paths with at most `maxPathLength` intermediary assets are
+// considered. The path found is returned, so that op.SendMax can be set from
+// its source amount, e.g. with a slippage tolerance. ErrNoPathFound is returned
+// when horizon does not find any path.
+func (c *Client) PopulateStrictReceivePath(ctx context.Context, op *txnbuild.PathPaymentStrictReceive, maxPathLength int) (hProtocol.Path, error) {
+	destination, err := pathDestinationAccount(op.Destination)
+	if err != nil {
+		return hProtocol.Path{}, err
+	}
+	destAssetType, err := pathAssetType(op.DestAsset)
+	if err != nil {
+		return hProtocol.Path{}, errors.Wrap(err, "invalid destination asset")
+	}
+	sendAsset, err := pathAssetString(op.SendAsset)
+	if err != nil {
+		return hProtocol.Path{}, errors.Wrap(err, "invalid send asset")
+	}
+
+	paths, err := c.StrictReceivePathsContext(ctx, PathsRequest{
+		DestinationAccount:     destination,
+		DestinationAssetType:   destAssetType,
+		DestinationAssetCode:   op.DestAsset.GetCode(),
+		DestinationAssetIssuer: op.DestAsset.GetIssuer(),
+		DestinationAmount:      op.DestAmount,
+		SourceAssets:           sendAsset,
+	})
+	if err != nil {
+		return hProtocol.Path{}, err
+	}
+
+	var best *hProtocol.Path
+	var bestAmount int64
+	for i, path := range paths.Embedded.Records {
+		if !pathAssetMatches(op.SendAsset, path.SourceAssetType, path.SourceAssetCode, path.SourceAssetIssuer) ||
+			(maxPathLength > 0 && len(path.Path) > maxPathLength) {
+			continue
+		}
+		sourceAmount, err := amount.ParseInt64(path.SourceAmount)
+		if err != nil {
+			return hProtocol.Path{}, errors.Wrap(err, "invalid source amount of path")
+		}
+		if best == nil || sourceAmount < bestAmount ||
+			(sourceAmount == bestAmount && len(path.Path) < len(best.Path)) {
+			best = &paths.Embedded.Records[i]
+			bestAmount = sourceAmount
+		}
+	}
+	if best == nil {
+		return hProtocol.Path{}, ErrNoPathFound
+	}
+
+	op.Path = pathAssets(best.Path)
+	return *best, nil
+}
+
+// PopulateStrictSendPath finds with horizon the paths from op.SendAmount of
+// op.SendAsset to op.DestAsset, and sets op.Path to the best one, which
+// delivers the largest amount of op.DestAsset. When `maxPathLength` is not 0,
+// only the paths with at most `maxPathLength` intermediary assets are
+// considered. The path found is returned, so that op.DestMin can be set from
+// its destination amount, e.g. with a slippage tolerance. ErrNoPathFound is
+// returned when horizon does not find any path.
+func (c *Client) PopulateStrictSendPath(ctx context.Context, op *txnbuild.PathPaymentStrictSend, maxPathLength int) (hProtocol.Path, error) {
+	sendAssetType, err := pathAssetType(op.SendAsset)
+	if err != nil {
+		return hProtocol.Path{}, errors.Wrap(err, "invalid send asset")
+	}
+	destAsset, err := pathAssetString(op.DestAsset)
+	if err != nil {
+		return hProtocol.Path{}, errors.Wrap(err, "invalid destination asset")
+	}
+
+	paths, err := c.StrictSendPathsContext(ctx, StrictSendPathsRequest{
+		DestinationAssets: destAsset,
+		SourceAssetType:   sendAssetType,
+		SourceAssetCode:   op.SendAsset.GetCode(),
+		SourceAssetIssuer: op.SendAsset.GetIssuer(),
+		SourceAmount:      op.SendAmount,
+	})
+	if err != nil {
+		return hProtocol.Path{}, err
+	}
+
+	var best *hProtocol.Path
+	var bestAmount int64
+	for i, path := range paths.Embedded.Records {
+		if !pathAssetMatches(op.DestAsset, path.DestinationAssetType, path.DestinationAssetCode, path.DestinationAssetIssuer) ||
+			(maxPathLength > 0 && len(path.Path) > maxPathLength) {
+			continue
+		}
+		destAmount, err := amount.ParseInt64(path.DestinationAmount)
+		if err != nil {
+			return hProtocol.Path{}, errors.Wrap(err, "invalid destination amount of path")
+		}
+		if best == nil || destAmount > bestAmount ||
+			(destAmount == bestAmount && len(path.Path) < len(best.Path)) {
+			best = &paths.Embedded.Records[i]
+			bestAmount = destAmount
+		}
+	}
+	if best == nil {
+		return hProtocol.Path{}, ErrNoPathFound
+	}
+
+	op.Path = pathAssets(best.Path)
+	return *best, nil
+}
+
+// pathDestinationAccount returns the account ID of the destination of a path
+// payment, which may be a muxed account.
+func pathDestinationAccount(destination string) (string, error) {
+	muxedAccount, err := xdr.AddressToMuxedAccount(destination)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid destination")
+	}
+	accountID := muxedAccount.ToAccountId()
+	return accountID.Address(), nil
+}
+
+// pathAssetType returns the type of `asset` as expected by the path finding
+// endpoints of horizon.
+func pathAssetType(asset txnbuild.Asset) (AssetType, error) {
+	if asset == nil {
+		return "", errors.New("asset is undefined")
+	}
+	assetType, err := asset.GetType()
+	if err != nil {
+		return "", err
+	}
+	switch assetType {
+	case txnbuild.AssetTypeNative:
+		return AssetTypeNative, nil
+	case txnbuild.AssetTypeCreditAlphanum4:
+		return AssetType4, nil
+	default:
+		return AssetType12, nil
+	}
+}
+
+// pathAssetString returns `asset` in the format of the asset lists of the path
+// finding endpoints of horizon: "native" or "CODE:ISSUER".
+func pathAssetString(asset txnbuild.Asset) (string, error) {
+	assetType, err := pathAssetType(asset)
+	if err != nil {
+		return "", err
+	}
+	if assetType == AssetTypeNative {
+		return string(AssetTypeNative), nil
+	}
+	return asset.GetCode() + ":" + asset.GetIssuer(), nil
+}
+
+// pathAssetMatches returns true when `asset` is the asset with the given type,
+// code and issuer in the response of horizon.
+func pathAssetMatches(asset txnbuild.Asset, assetType, code, issuer string) bool {
+	if asset.IsNative() {
+		return assetType == string(AssetTypeNative)
+	}
+	return assetType != string(AssetTypeNative) && asset.GetCode() == code && asset.GetIssuer() == issuer
+}
+
+// pathAssets converts the intermediary assets of a path returned by horizon to
+// the path of a txnbuild path payment operation.
+func pathAssets(assets []hProtocol.Asset) []txnbuild.Asset {
+	path := make([]txnbuild.Asset, 0, len(assets))
+	for _, asset := range assets {
+		if asset.Type == string(AssetTypeNative) {
+			path = append(path, txnbuild.NativeAsset{})
+		} else {
+			path = append(path, txnbuild.CreditAsset{Code: asset.Code, Issuer: asset.Issuer})
+		}
+	}
+	return path
+}
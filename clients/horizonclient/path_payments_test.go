@@ -0,0 +1,190 @@
+package horizonclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopulateStrictReceivePath(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"}
+	eur := txnbuild.CreditAsset{Code: "EUR", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"}
+
+	for _, testCase := range []struct {
+		maxPathLength int
+		sourceAmount  string
+		path          []txnbuild.Asset
+	}{
+		{0, "20.0000000", []txnbuild.Asset{
+			txnbuild.CreditAsset{Code: "21", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"},
+			txnbuild.NativeAsset{},
+		}},
+		{1, "25.0000000", []txnbuild.Asset{
+			txnbuild.CreditAsset{Code: "1", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"},
+		}},
+	} {
+		hmock.On(
+			"GET",
+			"https://localhost/paths?destination_account=GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU&destination_amount=20&destination_asset_code=EUR&destination_asset_issuer=GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN&destination_asset_type=credit_alphanum4&source_assets=USD%3AGDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+		).ReturnString(200, populatePathsResponse)
+		op := txnbuild.PathPaymentStrictReceive{
+			SendAsset: usd,
+			// the paths of a muxed destination are the paths of its account
+			Destination: "MCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6AAAAAAAAAAAMR62E",
+			DestAsset:   eur,
+			DestAmount:  "20",
+		}
+		path, err := client.PopulateStrictReceivePath(context.Background(), &op, testCase.maxPathLength)
+		if assert.NoError(t, err) {
+			assert.Equal(t, testCase.sourceAmount, path.SourceAmount)
+			assert.Equal(t, testCase.path, op.Path)
+		}
+	}
+
+	// the paths from other assets are ignored
+	hmock.On(
+		"GET",
+		"https://localhost/paths?destination_account=GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU&destination_amount=20&destination_asset_code=EUR&destination_asset_issuer=GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN&destination_asset_type=credit_alphanum4&source_assets=native",
+	).ReturnString(200, populatePathsResponse)
+	op := txnbuild.PathPaymentStrictReceive{
+		SendAsset:   txnbuild.NativeAsset{},
+		Destination: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+		DestAsset:   eur,
+		DestAmount:  "20",
+	}
+	_, err := client.PopulateStrictReceivePath(context.Background(), &op, 0)
+	assert.Equal(t, ErrNoPathFound, err)
+	assert.Nil(t, op.Path)
+}
+
+func TestPopulateStrictSendPath(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"}
+	eur := txnbuild.CreditAsset{Code: "EUR", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"}
+
+	for _, testCase := range []struct {
+		maxPathLength     int
+		destinationAmount string
+		path              []txnbuild.Asset
+	}{
+		{0, "30.0000000", []txnbuild.Asset{
+			txnbuild.CreditAsset{Code: "21", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"},
+			txnbuild.NativeAsset{},
+		}},
+		{1, "24.0000000", []txnbuild.Asset{
+			txnbuild.CreditAsset{Code: "1", Issuer: "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN"},
+		}},
+	} {
+		hmock.On(
+			"GET",
+			"https://localhost/paths/strict-send?destination_assets=EUR%3AGDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN&source_amount=20&source_asset_code=USD&source_asset_issuer=GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN&source_asset_type=credit_alphanum4",
+		).ReturnString(200, populatePathsResponse)
+		op := txnbuild.PathPaymentStrictSend{
+			SendAsset:   usd,
+			SendAmount:  "20",
+			Destination: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+			DestAsset:   eur,
+		}
+		path, err := client.PopulateStrictSendPath(context.Background(), &op, testCase.maxPathLength)
+		if assert.NoError(t, err) {
+			assert.Equal(t, testCase.destinationAmount, path.DestinationAmount)
+			assert.Equal(t, testCase.path, op.Path)
+		}
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/paths/strict-send?destination_assets=EUR%3AGDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN&source_amount=20&source_asset_code=USD&source_asset_issuer=GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN&source_asset_type=credit_alphanum4",
+	).ReturnString(200, emptyPathsResponse)
+	op := txnbuild.PathPaymentStrictSend{
+		SendAsset:   usd,
+		SendAmount:  "20",
+		Destination: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+		DestAsset:   eur,
+	}
+	_, err := client.PopulateStrictSendPath(context.Background(), &op, 0)
+	assert.Equal(t, ErrNoPathFound, err)
+}
+
+var populatePathsResponse = `{
+  "_embedded": {
+    "records": [
+      {
+        "destination_amount": "20.0000000",
+        "destination_asset_code": "EUR",
+        "destination_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+        "destination_asset_type": "credit_alphanum4",
+        "path": [],
+        "source_amount": "30.0000000",
+        "source_asset_code": "USD",
+        "source_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+        "source_asset_type": "credit_alphanum4"
+      },
+      {
+        "destination_amount": "24.0000000",
+        "destination_asset_code": "EUR",
+        "destination_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+        "destination_asset_type": "credit_alphanum4",
+        "path": [
+          {
+            "asset_code": "1",
+            "asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+            "asset_type": "credit_alphanum4"
+          }
+        ],
+        "source_amount": "25.0000000",
+        "source_asset_code": "USD",
+        "source_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+        "source_asset_type": "credit_alphanum4"
+      },
+      {
+        "destination_amount": "30.0000000",
+        "destination_asset_code": "EUR",
+        "destination_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+        "destination_asset_type": "credit_alphanum4",
+        "path": [
+          {
+            "asset_code": "21",
+            "asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+            "asset_type": "credit_alphanum4"
+          },
+          {
+            "asset_type": "native"
+          }
+        ],
+        "source_amount": "20.0000000",
+        "source_asset_code": "USD",
+        "source_asset_issuer": "GDSBCQO34HWPGUGQSP3QBFEXVTSR2PW46UIGTHVWGWJGQKH3AFNHXHXN",
+        "source_asset_type": "credit_alphanum4"
+      }
+    ]
+  },
+  "_links": {
+    "self": {
+      "href": "/paths"
+    }
+  }
+}`
+
+var emptyPathsResponse = `{
+  "_embedded": {
+    "records": []
+  },
+  "_links": {
+    "self": {
+      "href": "/paths/strict-send"
+    }
+  }
+}`
@@ -0,0 +1,121 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of the requests a Client
+// sends to horizon, so that bursts of requests, e.g. of batch jobs, are
+// smoothed instead of being rejected with 429 Too Many Requests responses.
+//
+// The size and the refill rate of the bucket are learnt from the
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers of
+// the responses of horizon, so requests are not limited until horizon sent
+// its rate limit. The zero value is ready to use, and a RateLimiter must not
+// be copied after first use. It can be shared by the clients of the same
+// horizon instance.
+type RateLimiter struct {
+	mutex sync.Mutex
+	// limit is the size of the bucket, 0 until horizon sent its rate limit.
+	limit float64
+	// rate is the number of tokens added to the bucket per second.
+	rate float64
+	// tokens is the number of tokens in the bucket at updatedAt. It is
+	// negative when requests are waiting for tokens.
+	tokens    float64
+	updatedAt time.Time
+	now       func() time.Time
+}
+
+// Wait blocks until a request can be sent to horizon without exceeding its
+// rate limit, or until ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	wait := l.reserve()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token from the bucket and returns how long to wait before
+// it is available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.limit == 0 {
+		return 0
+	}
+	l.refill()
+	l.tokens--
+	if l.tokens >= 0 || l.rate == 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// update updates the bucket with the rate limit headers of resp.
+func (l *RateLimiter) update(resp *http.Response) {
+	if l == nil || resp == nil {
+		return
+	}
+	limit, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Limit"), 64)
+	if err != nil || limit <= 0 {
+		return
+	}
+	remaining, err := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Remaining"), 64)
+	if err != nil || remaining < 0 {
+		return
+	}
+	reset, _ := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Reset"), 64)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		remaining = 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.refill()
+	if l.limit == 0 || remaining < l.tokens {
+		// the requests still waiting for tokens keep their place
+		l.tokens = remaining
+	}
+	l.limit = limit
+	if reset > 0 && remaining < limit {
+		// the bucket is full again in `reset` seconds
+		l.rate = (limit - remaining) / reset
+	}
+}
+
+// refill adds the tokens accumulated since the last update to the bucket.
+func (l *RateLimiter) refill() {
+	now := l.currentTime()
+	if !l.updatedAt.IsZero() && now.After(l.updatedAt) {
+		l.tokens += now.Sub(l.updatedAt).Seconds() * l.rate
+		if l.tokens > l.limit {
+			l.tokens = l.limit
+		}
+	}
+	l.updatedAt = now
+}
+
+func (l *RateLimiter) currentTime() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
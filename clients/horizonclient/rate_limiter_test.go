@@ -0,0 +1,111 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/manucorporat/sse"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func rateLimitResponse(status int, limit, remaining, reset string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header: http.Header{
+			"X-Ratelimit-Limit":     []string{limit},
+			"X-Ratelimit-Remaining": []string{remaining},
+			"X-Ratelimit-Reset":     []string{reset},
+		},
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	limiter := &RateLimiter{now: func() time.Time { return now }}
+
+	// requests are not limited until horizon sent its rate limit
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+	}
+
+	// a burst of 10 requests, 2 requests remaining and the bucket full again
+	// in 1s, so 8 requests per second
+	limiter.update(rateLimitResponse(http.StatusOK, "10", "2", "1"))
+	assert.Equal(t, time.Duration(0), limiter.reserve())
+	assert.Equal(t, time.Duration(0), limiter.reserve())
+	assert.Equal(t, 125*time.Millisecond, limiter.reserve())
+	assert.Equal(t, 250*time.Millisecond, limiter.reserve())
+
+	// the bucket is refilled over time
+	now = now.Add(time.Second)
+	for i := 0; i < 6; i++ {
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+	}
+	assert.Equal(t, 125*time.Millisecond, limiter.reserve())
+
+	// the bucket is emptied when horizon rejects a request
+	now = now.Add(time.Second)
+	limiter.update(rateLimitResponse(http.StatusTooManyRequests, "10", "3", "2"))
+	assert.Equal(t, 200*time.Millisecond, limiter.reserve())
+
+	// responses without rate limit headers are ignored
+	limiter.update(&http.Response{StatusCode: http.StatusOK, Header: http.Header{}})
+	assert.Equal(t, 400*time.Millisecond, limiter.reserve())
+
+	var nilLimiter *RateLimiter
+	assert.NoError(t, nilLimiter.Wait(context.Background()))
+	nilLimiter.update(rateLimitResponse(http.StatusOK, "10", "2", "1"))
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:  "https://localhost/",
+		HTTP:        hmock,
+		RateLimiter: &RateLimiter{},
+	}
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).Return(func(*http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(200, accountResponse)
+		resp.Header.Set("X-RateLimit-Limit", "3600")
+		resp.Header.Set("X-RateLimit-Remaining", "0")
+		resp.Header.Set("X-RateLimit-Reset", "3600")
+		return resp, nil
+	})
+	accountRequest := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+
+	_, err := client.AccountDetail(accountRequest)
+	assert.NoError(t, err)
+
+	// the next request must wait a second for a token
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.AccountDetailContext(ctx, accountRequest)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestClientRateLimiterStream(t *testing.T) {
+	limiter := &RateLimiter{}
+	limiter.update(rateLimitResponse(http.StatusOK, "3600", "0", "3600"))
+	client := &Client{
+		HorizonURL:  "https://localhost/",
+		HTTP:        httptest.NewClient(),
+		RateLimiter: limiter,
+	}
+
+	// the error of the rate limiter is returned instead of a clean end of the
+	// stream, which would reconnect right away
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	transient, err := client.readStream(ctx, "https://localhost/ledgers?cursor=now", func(sse.Event) error {
+		return nil
+	})
+	assert.False(t, transient)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
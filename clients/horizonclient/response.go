@@ -0,0 +1,66 @@
+package horizonclient
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// setAcceptEncoding asks horizon to compress its response unless compression
+// is disabled or another encoding was requested. The response is then
+// decompressed by responseBody, so that MaxResponseSize applies to the
+// decompressed body whatever the HTTP client of c.
+func (c *Client) setAcceptEncoding(req *http.Request) {
+	if c.DisableCompression || req.Header.Get("Accept-Encoding") != "" {
+		return
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+}
+
+// responseBody returns a reader of the decompressed body of resp, which fails
+// with ErrResponseTooLarge once more than MaxResponseSize bytes are read.
+// Responses are decoded as they are read, so that large pages of records do
+// not need to be buffered before being decoded.
+func (c *Client) responseBody(resp *http.Response) (io.Reader, error) {
+	var body io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "error decompressing horizon response")
+		}
+		body = gzipReader
+	}
+	if c.MaxResponseSize > 0 {
+		body = &limitedReader{reader: body, remaining: c.MaxResponseSize}
+	}
+	return body, nil
+}
+
+// limitedReader reads from reader until `remaining` bytes were read, then
+// fails with ErrResponseTooLarge if there is more to read. Unlike
+// io.LimitedReader, it does not truncate the body silently.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// check whether the body ends exactly at the limit
+		var b [1]byte
+		n, err := r.reader.Read(b[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,65 @@
+package horizonclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCompressionAndSize(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, err := gzipWriter.Write([]byte(accountResponse))
+	assert.NoError(t, err)
+	assert.NoError(t, gzipWriter.Close())
+
+	var header http.Header
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).Return(func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		if req.Header.Get("Accept-Encoding") != "gzip" {
+			return httpmock.NewStringResponse(200, accountResponse), nil
+		}
+		resp := httpmock.NewBytesResponse(200, compressed.Bytes())
+		resp.Header.Set("Content-Encoding", "gzip")
+		return resp, nil
+	})
+	accountRequest := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+
+	// compressed responses are decompressed
+	account, err := client.AccountDetail(accountRequest)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "gzip", header.Get("Accept-Encoding"))
+		assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.ID)
+	}
+
+	// the size limit applies to the decompressed response
+	client.MaxResponseSize = int64(len(accountResponse)) - 1
+	_, err = client.AccountDetail(accountRequest)
+	assert.Equal(t, ErrResponseTooLarge, errors.Cause(err))
+
+	client.MaxResponseSize = int64(len(accountResponse))
+	_, err = client.AccountDetail(accountRequest)
+	assert.NoError(t, err)
+
+	client.DisableCompression = true
+	account, err = client.AccountDetail(accountRequest)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "", header.Get("Accept-Encoding"))
+		assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.ID)
+	}
+}
@@ -0,0 +1,106 @@
+package horizonclient
+
+import (
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// DefaultRetryPolicy is a retry policy suitable for most applications: a
+// request is sent up to 3 times, waiting up to 1s then up to 2s between attempts
+// unless horizon asks to wait longer.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+}
+
+// RetryPolicy configures how a Client retries the requests to horizon which
+// failed with a transient error: a timeout, a 429 Too Many Requests or a 5xx
+// response. Submitting a transaction again is safe as a transaction can only be
+// included in the ledger once.
+//
+// Requests are retried with an exponential backoff with jitter: before the
+// n-th retry the client waits a random duration between half and all of
+// InitialBackoff * 2^(n-1), capped at MaxBackoff. When horizon responds with a
+// Retry-After header, the client waits at least the duration it specifies.
+// Retries stop as soon as the context of the request is done.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including
+	// the first one. Requests are not retried when it is lower than 2.
+	MaxAttempts int
+	// InitialBackoff is the base duration waited before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the duration waited between two attempts, not including
+	// the durations requested by horizon with Retry-After. There is no cap when
+	// it is 0.
+	MaxBackoff time.Duration
+}
+
+// backoff returns how long to wait before retrying a request which was sent
+// `attempt` times and failed with `resp` and `err`, and false if it must not be
+// retried.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || !isRetryable(resp, err) {
+		return 0, false
+	}
+
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = math.MaxInt64 / 2
+	}
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	if wait > 1 {
+		wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	}
+
+	if retryAfter := retryAfter(resp); retryAfter > wait {
+		wait = retryAfter
+	}
+	return wait, true
+}
+
+// isRetryable returns true if the request which failed with `resp` and `err`
+// may succeed when sent again.
+func isRetryable(resp *http.Response, err error) bool {
+	if resp != nil {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	}
+	netErr, ok := errors.Cause(err).(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// retryAfter returns the duration specified by the Retry-After header of
+// `resp`, either in seconds or as an HTTP date, or 0 if there is none.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
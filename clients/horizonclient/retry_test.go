@@ -0,0 +1,111 @@
+package horizonclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		RetryPolicy: &RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		},
+	}
+	accountRequest := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+
+	respond := func(statuses ...int) *int {
+		attempts := 0
+		hmock.On(
+			"GET",
+			"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+		).Return(func(*http.Request) (*http.Response, error) {
+			status := statuses[attempts]
+			attempts++
+			if status == http.StatusOK {
+				return httpmock.NewStringResponse(status, accountResponse), nil
+			}
+			return httpmock.NewStringResponse(status, notFoundResponse), nil
+		})
+		return &attempts
+	}
+
+	// transient errors are retried
+	attempts := respond(http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK)
+	account, err := client.AccountDetail(accountRequest)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.ID)
+	}
+	assert.Equal(t, 3, *attempts)
+
+	// requests are sent at most MaxAttempts times
+	attempts = respond(http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	_, err = client.AccountDetail(accountRequest)
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusBadGateway, GetError(err).Response.StatusCode)
+	}
+	assert.Equal(t, 3, *attempts)
+
+	// other errors are not retried
+	attempts = respond(http.StatusNotFound)
+	_, err = client.AccountDetail(accountRequest)
+	assert.Error(t, err)
+	assert.Equal(t, 1, *attempts)
+
+	// without a retry policy requests are sent once
+	client.RetryPolicy = nil
+	attempts = respond(http.StatusServiceUnavailable)
+	_, err = client.AccountDetail(accountRequest)
+	assert.Error(t, err)
+	assert.Equal(t, 1, *attempts)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	}
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+
+	wait, retry := policy.backoff(1, unavailable, nil)
+	assert.True(t, retry)
+	assert.True(t, wait >= 500*time.Millisecond && wait <= time.Second, wait)
+
+	wait, retry = policy.backoff(2, unavailable, nil)
+	assert.True(t, retry)
+	assert.True(t, wait >= time.Second && wait <= 2*time.Second, wait)
+
+	// the backoff is capped at MaxBackoff
+	wait, retry = policy.backoff(4, unavailable, nil)
+	assert.True(t, retry)
+	assert.True(t, wait >= 1500*time.Millisecond && wait <= 3*time.Second, wait)
+
+	_, retry = policy.backoff(5, unavailable, nil)
+	assert.False(t, retry)
+
+	// Retry-After is honored
+	tooManyRequests := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"10"}},
+	}
+	wait, retry = policy.backoff(1, tooManyRequests, nil)
+	assert.True(t, retry)
+	assert.Equal(t, 10*time.Second, wait)
+
+	_, retry = policy.backoff(1, &http.Response{StatusCode: http.StatusBadRequest}, nil)
+	assert.False(t, retry)
+
+	var nilPolicy *RetryPolicy
+	_, retry = nilPolicy.backoff(1, unavailable, nil)
+	assert.False(t, retry)
+}
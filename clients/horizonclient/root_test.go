@@ -1,8 +1,12 @@
 package horizonclient
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/clock/clocktest"
 	"github.com/stellar/go/support/http/httptest"
 	"github.com/stretchr/testify/assert"
 )
@@ -56,6 +60,51 @@ func TestRoot(t *testing.T) {
 	}
 }
 
+func TestCapabilities(t *testing.T) {
+	hmock := httptest.NewClient()
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		clock: &clock.Clock{
+			Source: clocktest.FixedSource(now),
+		},
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/",
+	).ReturnString(200, rootResponse)
+
+	capabilities, err := client.Capabilities(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(10), capabilities.ProtocolVersion())
+		assert.False(t, capabilities.SupportsFeeBump())
+		assert.False(t, capabilities.ExperimentalIngestion())
+	}
+
+	// the root resource is cached
+	hmock.On(
+		"GET",
+		"https://localhost/",
+	).ReturnError("http.Client error")
+
+	_, err = client.Capabilities(context.Background())
+	assert.NoError(t, err)
+
+	client.clock = &clock.Clock{
+		Source: clocktest.FixedSource(now.Add(capabilitiesCacheTTL)),
+	}
+	_, err = client.Capabilities(context.Background())
+	assert.Error(t, err)
+
+	capabilities = Capabilities{}
+	capabilities.Root.CurrentProtocolVersion = 13
+	capabilities.Root.IngestSequence = 84959
+	assert.True(t, capabilities.SupportsFeeBump())
+	assert.True(t, capabilities.ExperimentalIngestion())
+}
+
 var rootResponse = `{
   "_links": {
     "account": {
@@ -0,0 +1,129 @@
+package horizonclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+var (
+	// submitPollInterval is the time waited between two polls of a
+	// transaction whose submission timed out.
+	submitPollInterval = time.Second
+	// submitExpirySlack is the time waited after the max time of a transaction
+	// before deciding it will never be included in a ledger, to account for the
+	// difference between the clocks and for the ledger being closed.
+	submitExpirySlack = 10 * time.Second
+)
+
+// SubmitTransactionAndWait submits a transaction to the network like
+// SubmitTransactionWithOptionsContext and waits until it is included in a
+// ledger. When the submission times out, the transaction is polled until it is
+// included in a ledger, its time bounds expire or ctx is done.
+//
+// It returns the transaction included in the ledger on success. If the
+// transaction was included in the ledger but failed, it is returned along with
+// ErrTransactionFailed. If its time bounds expired first, ErrTransactionExpired
+// is returned and the transaction can be built again and submitted safely.
+func (c *Client) SubmitTransactionAndWait(ctx context.Context, transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.Transaction, error) {
+	return c.submitAndWait(
+		ctx,
+		func() (hProtocol.Transaction, error) {
+			return c.SubmitTransactionWithOptionsContext(ctx, transaction, opts)
+		},
+		transaction.HashHex,
+		transaction.Timebounds(),
+	)
+}
+
+// SubmitFeeBumpTransactionAndWait is like SubmitTransactionAndWait but submits
+// a fee bump transaction.
+func (c *Client) SubmitFeeBumpTransactionAndWait(ctx context.Context, transaction *txnbuild.FeeBumpTransaction, opts SubmitTxOpts) (hProtocol.Transaction, error) {
+	return c.submitAndWait(
+		ctx,
+		func() (hProtocol.Transaction, error) {
+			return c.SubmitFeeBumpTransactionWithOptionsContext(ctx, transaction, opts)
+		},
+		transaction.HashHex,
+		transaction.InnerTransaction().Timebounds(),
+	)
+}
+
+func (c *Client) submitAndWait(
+	ctx context.Context,
+	submit func() (hProtocol.Transaction, error),
+	hashHex func(network string) (string, error),
+	timebounds txnbuild.Timebounds,
+) (hProtocol.Transaction, error) {
+	tx, err := submit()
+	if err == nil || !isSubmitTimeout(ctx, err) {
+		return tx, err
+	}
+
+	root, err := c.RootContext(ctx)
+	if err != nil {
+		return tx, errors.Wrap(err, "could not get the network passphrase")
+	}
+	hash, err := hashHex(root.NetworkPassphrase)
+	if err != nil {
+		return tx, errors.Wrap(err, "could not hash the transaction")
+	}
+
+	for {
+		expired := timebounds.MaxTime != 0 &&
+			c.currentTime() > timebounds.MaxTime+int64(submitExpirySlack/time.Second)
+
+		tx, err = c.TransactionDetailContext(ctx, hash)
+		switch {
+		case err == nil && !tx.Successful:
+			return tx, ErrTransactionFailed
+		case err == nil:
+			return tx, nil
+		case ctx.Err() != nil:
+			return tx, ctx.Err()
+		case !IsNotFoundError(err):
+			return tx, err
+		case expired:
+			return tx, ErrTransactionExpired
+		}
+
+		timer := time.NewTimer(submitPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return tx, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isSubmitTimeout returns true if the submission of a transaction failed with
+// `err` because horizon or the client timed out before the transaction was
+// included in a ledger, in which case it may still be included.
+func isSubmitTimeout(ctx context.Context, err error) bool {
+	if horizonError := GetError(err); horizonError != nil {
+		return horizonError.Response != nil && horizonError.Response.StatusCode == http.StatusGatewayTimeout
+	}
+	netErr, ok := errors.Cause(err).(net.Error)
+	return ok && netErr.Timeout() && ctx.Err() == nil
+}
+
+// currentTime returns the current unix time of the horizon server, or the
+// local time when the time of the server is not known.
+func (c *Client) currentTime() int64 {
+	now := c.clock.Now().UTC().Unix()
+	serverURL, err := url.Parse(c.HorizonURL)
+	if err != nil {
+		return now
+	}
+	if serverTime := currentServerTime(serverURL.Hostname(), now); serverTime != 0 {
+		return serverTime
+	}
+	return now
+}
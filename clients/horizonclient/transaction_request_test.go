@@ -2,9 +2,16 @@ package horizonclient
 
 import (
 	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jarcoal/httpmock"
 	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/http/httptest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -86,6 +93,59 @@ func TestNextTransactionsPage(t *testing.T) {
 	}
 }
 
+func TestIterateTransactions(t *testing.T) {
+	defer func(backoff time.Duration) { iterateRateLimitBackoff = backoff }(iterateRateLimitBackoff)
+	iterateRateLimitBackoff = time.Millisecond
+
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	hmock.On(
+		"GET",
+		"https://localhost/transactions?limit=2",
+	).ReturnString(200, firstTransactionsPage)
+	// the next page is fetched again once the rate limit is reset
+	attempts := 0
+	hmock.On(
+		"GET",
+		"https://horizon-testnet.stellar.org/transactions?cursor=1566052450312192&limit=2&order=desc",
+	).Return(func(*http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return httpmock.NewStringResponse(http.StatusTooManyRequests, notFoundResponse), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, emptyTransactionsPage), nil
+	})
+
+	var hashes []string
+	err := client.IterateTransactions(context.Background(), TransactionRequest{Limit: 2}, func(tx hProtocol.Transaction) error {
+		hashes = append(hashes, tx.Hash)
+		return nil
+	})
+	if assert.NoError(t, err) {
+		assert.Len(t, hashes, 2)
+		assert.Equal(t, 2, attempts)
+	}
+
+	// errors returned by the handler stop the iteration
+	hmock.On(
+		"GET",
+		"https://localhost/transactions?limit=2",
+	).ReturnString(200, firstTransactionsPage)
+	hashes = nil
+	err = client.IterateTransactions(context.Background(), TransactionRequest{Limit: 2}, func(tx hProtocol.Transaction) error {
+		hashes = append(hashes, tx.Hash)
+		return errors.New("stop")
+	})
+	if assert.Error(t, err) {
+		assert.Equal(t, "stop", err.Error())
+		assert.Len(t, hashes, 1)
+	}
+}
+
 func TestTransactionRequestStreamTransactions(t *testing.T) {
 	hmock := httptest.NewClient()
 	client := &Client{
@@ -152,6 +212,64 @@ func TestTransactionRequestStreamTransactions(t *testing.T) {
 	}
 }
 
+func TestTransactionRequestStreamTransactionsReconnect(t *testing.T) {
+	defer func(backoff time.Duration) { streamReconnectMinBackoff = backoff }(streamReconnectMinBackoff)
+	streamReconnectMinBackoff = time.Millisecond
+
+	hmock := httptest.NewClient()
+	var cursors []string
+	var reconnectErrors []error
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		StreamReconnectHandler: func(cursor string, err error) {
+			cursors = append(cursors, cursor)
+			reconnectErrors = append(reconnectErrors, err)
+		},
+	}
+
+	// the first connection fails after an event
+	hmock.On(
+		"GET",
+		"https://localhost/transactions?cursor=now",
+	).Return(func(*http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(200, "")
+		resp.Body = ioutil.NopCloser(io.MultiReader(
+			strings.NewReader("id: 2608707301036032\n"+txStreamResponse+"\n"),
+			failingReader{},
+		))
+		return resp, nil
+	})
+	// the stream is resumed from the last event received
+	hmock.On(
+		"GET",
+		"https://localhost/transactions?cursor=2608707301036032",
+	).ReturnString(200, txStreamResponse)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var transactions []hProtocol.Transaction
+	err := client.StreamTransactions(ctx, TransactionRequest{}, func(tr hProtocol.Transaction) {
+		transactions = append(transactions, tr)
+		if len(transactions) == 2 {
+			cancel()
+		}
+	})
+
+	if assert.NoError(t, err) {
+		assert.Len(t, transactions, 2)
+		assert.Equal(t, []string{"2608707301036032"}, cursors)
+		if assert.Len(t, reconnectErrors, 1) {
+			assert.Contains(t, reconnectErrors[0].Error(), "connection reset")
+		}
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
 var txStreamResponse = `data: {"_links":{"self":{"href":"https://horizon-testnet.stellar.org/transactions/1534f6507420c6871b557cc2fc800c29fb1ed1e012e694993ffe7a39c824056e"},"account":{"href":"https://horizon-testnet.stellar.org/accounts/GAIH3ULLFQ4DGSECF2AR555KZ4KNDGEKN4AFI4SU2M7B43MGK3QJZNSR"},"ledger":{"href":"https://horizon-testnet.stellar.org/ledgers/607387"},"operations":{"href":"https://horizon-testnet.stellar.org/transactions/1534f6507420c6871b557cc2fc800c29fb1ed1e012e694993ffe7a39c824056e/operations{?cursor,limit,order}","templated":true},"effects":{"href":"https://horizon-testnet.stellar.org/transactions/1534f6507420c6871b557cc2fc800c29fb1ed1e012e694993ffe7a39c824056e/effects{?cursor,limit,order}","templated":true},"precedes":{"href":"https://horizon-testnet.stellar.org/transactions?order=asc\u0026cursor=2608707301036032"},"succeeds":{"href":"https://horizon-testnet.stellar.org/transactions?order=desc\u0026cursor=2608707301036032"}},"id":"1534f6507420c6871b557cc2fc800c29fb1ed1e012e694993ffe7a39c824056e","paging_token":"2608707301036032","successful":true,"hash":"1534f6507420c6871b557cc2fc800c29fb1ed1e012e694993ffe7a39c824056e","ledger":607387,"created_at":"2019-04-04T12:07:03Z","source_account":"GAIH3ULLFQ4DGSECF2AR555KZ4KNDGEKN4AFI4SU2M7B43MGK3QJZNSR","source_account_sequence":"4660039930473","max_fee":100,"fee_charged":100,"operation_count":1,"envelope_xdr":"AAAAABB90WssODNIgi6BHveqzxTRmIpvAFRyVNM+Hm2GVuCcAAAAZAAABD0ABlJpAAAAAAAAAAAAAAABAAAAAAAAAAAAAAAAmLuzasXDMqsqgFK4xkbLxJLzmQQzkiCF2SnKPD+b1TsAAAAXSHboAAAAAAAAAAABhlbgnAAAAECqxhXduvtzs65keKuTzMtk76cts2WeVB2pZKYdlxlOb1EIbOpFhYizDSXVfQlAvvg18qV6oNRr7ls4nnEm2YIK","result_xdr":"AAAAAAAAAGQAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAA=","result_meta_xdr":"AAAAAQAAAAIAAAADAAlEmwAAAAAAAAAAEH3Rayw4M0iCLoEe96rPFNGYim8AVHJU0z4ebYZW4JwBT3aiixBA2AAABD0ABlJoAAAAAAAAAAAAAAAAAAAAAAEAAAAAAAAAAAAAAAAAAAAAAAABAAlEmwAAAAAAAAAAEH3Rayw4M0iCLoEe96rPFNGYim8AVHJU0z4ebYZW4JwBT3aiixBA2AAABD0ABlJpAAAAAAAAAAAAAAAAAAAAAAEAAAAAAAAAAAAAAAAAAAAAAAABAAAAAwAAAAMACUSbAAAAAAAAAAAQfdFrLDgzSIIugR73qs8U0ZiKbwBUclTTPh5thlbgnAFPdqKLEEDYAAAEPQAGUmkAAAAAAAAAAAAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAEACUSbAAAAAAAAAAAQfdFrLDgzSIIugR73qs8U0ZiKbwBUclTTPh5thlbgnAFPdotCmVjYAAAEPQAGUmkAAAAAAAAAAAAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAAACUSbAAAAAAAAAACYu7NqxcMyqyqAUrjGRsvEkvOZBDOSIIXZKco8P5vVOwAAABdIdugAAAlEmwAAAAAAAAAAAAAAAAAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAA==","fee_meta_xdr":"AAAAAgAAAAMACUSaAAAAAAAAAAAQfdFrLDgzSIIugR73qs8U0ZiKbwBUclTTPh5thlbgnAFPdqKLEEE8AAAEPQAGUmgAAAAAAAAAAAAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAEACUSbAAAAAAAAAAAQfdFrLDgzSIIugR73qs8U0ZiKbwBUclTTPh5thlbgnAFPdqKLEEDYAAAEPQAGUmgAAAAAAAAAAAAAAAAAAAAAAQAAAAAAAAAAAAAAAAAAAA==","memo_type":"none","signatures":["qsYV3br7c7OuZHirk8zLZO+nLbNlnlQdqWSmHZcZTm9RCGzqRYWIsw0l1X0JQL74NfKleqDUa+5bOJ5xJtmCCg=="]}
 `
 
@@ -0,0 +1,246 @@
+package horizonclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Interaction is a request sent to horizon and its response, as saved in
+// transcripts by Recorder and replayed by Replayer.
+type Interaction struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+// Recorder is an HTTP client which sends requests with another HTTP client
+// and records them along with their responses, so that they can be saved to a
+// transcript file and replayed in tests by Replayer:
+//
+//	recorder := horizonclient.NewRecorder(http.DefaultClient)
+//	client := &horizonclient.Client{HorizonURL: "https://horizon-testnet.stellar.org", HTTP: recorder}
+//	// send requests with client
+//	err := recorder.Save("testdata/transcript.json")
+//
+// The responses of streams are recorded when their body is closed.
+type Recorder struct {
+	HTTP HTTP
+
+	mutex        sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder sending requests with `client`.
+func NewRecorder(client HTTP) *Recorder {
+	return &Recorder{HTTP: client}
+}
+
+// Do sends req and records it along with its response.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// let the HTTP client decompress the responses, so that transcripts are
+	// readable
+	req.Header.Del("Accept-Encoding")
+	resp, err := r.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: requestBody,
+		Status:      resp.StatusCode,
+		Header:      resp.Header,
+	}
+	body := &recordingBody{body: resp.Body}
+	body.onClose = func() {
+		interaction.Body = body.recorded.String()
+		r.mutex.Lock()
+		r.interactions = append(r.interactions, interaction)
+		r.mutex.Unlock()
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+// Get sends a GET request to `url` and records it along with its response.
+func (r *Recorder) Get(url string) (*http.Response, error) {
+	return get(r, url)
+}
+
+// PostForm sends a POST request to `url` with `data` as body and records it
+// along with its response.
+func (r *Recorder) PostForm(url string, data url.Values) (*http.Response, error) {
+	return postForm(r, url, data)
+}
+
+// Interactions returns the interactions recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the interactions recorded so far to the transcript file at
+// `path`.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error encoding transcript")
+	}
+	return errors.Wrap(ioutil.WriteFile(path, data, 0644), "error writing transcript")
+}
+
+// recordingBody records the bytes read from the body of a response until it
+// is closed.
+type recordingBody struct {
+	body     io.ReadCloser
+	recorded bytes.Buffer
+	once     sync.Once
+	onClose  func()
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.recorded.Write(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.body.Close()
+	b.once.Do(b.onClose)
+	return err
+}
+
+// Replayer is an HTTP client responding to requests with the responses saved
+// in a transcript by Recorder, without sending them to horizon, so that tests
+// are deterministic. Every interaction of the transcript is replayed once, in
+// order for the requests with the same method, URL and body.
+type Replayer struct {
+	mutex        sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewReplayer returns a Replayer replaying the transcript file at `path`.
+func NewReplayer(path string) (*Replayer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading transcript")
+	}
+	var interactions []Interaction
+	if err = json.Unmarshal(data, &interactions); err != nil {
+		return nil, errors.Wrap(err, "error decoding transcript")
+	}
+	return &Replayer{
+		interactions: interactions,
+		replayed:     make([]bool, len(interactions)),
+	}, nil
+}
+
+// Do responds to req with the response of the first interaction of the
+// transcript matching req which was not replayed yet.
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	requestBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i, interaction := range r.interactions {
+		if r.replayed[i] ||
+			interaction.Method != req.Method ||
+			interaction.URL != req.URL.String() ||
+			interaction.RequestBody != requestBody {
+			continue
+		}
+		r.replayed[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			StatusCode:    interaction.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Header,
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Body)),
+			ContentLength: int64(len(interaction.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, errors.Errorf("no interaction left in transcript for %s %s", req.Method, req.URL)
+}
+
+// Get responds to a GET request to `url` from the transcript.
+func (r *Replayer) Get(url string) (*http.Response, error) {
+	return get(r, url)
+}
+
+// PostForm responds to a POST request to `url` with `data` as body from the
+// transcript.
+func (r *Replayer) PostForm(url string, data url.Values) (*http.Response, error) {
+	return postForm(r, url, data)
+}
+
+// Remaining returns the interactions of the transcript which were not
+// replayed, e.g. to check that a test sent all the requests expected.
+func (r *Replayer) Remaining() []Interaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var remaining []Interaction
+	for i, interaction := range r.interactions {
+		if !r.replayed[i] {
+			remaining = append(remaining, interaction)
+		}
+	}
+	return remaining
+}
+
+// readRequestBody reads the body of req, which is replaced so that it can be
+// read again.
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", errors.Wrap(err, "error reading request body")
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+func get(client HTTP, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+func postForm(client HTTP, url string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return client.Do(req)
+}
@@ -0,0 +1,63 @@
+package horizonclient
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscript(t *testing.T) {
+	dir, err := ioutil.TempDir("", "horizonclient-transcript")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "transcript.json")
+
+	hmock := httptest.NewClient()
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).ReturnString(200, accountResponse)
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG",
+	).ReturnString(404, notFoundResponse)
+
+	recorder := NewRecorder(hmock)
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       recorder,
+	}
+	_, err = client.AccountDetail(AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"})
+	require.NoError(t, err)
+	_, err = client.AccountDetail(AccountRequest{AccountID: "GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG"})
+	require.Error(t, err)
+	assert.Len(t, recorder.Interactions(), 2)
+	require.NoError(t, recorder.Save(path))
+
+	replayer, err := NewReplayer(path)
+	require.NoError(t, err)
+	client = &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       replayer,
+	}
+	account, err := client.AccountDetail(AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.ID)
+	}
+	assert.Len(t, replayer.Remaining(), 1)
+
+	_, err = client.AccountDetail(AccountRequest{AccountID: "GBZ5OD56VRTRQKMNADD6VUZUG3FCILMAMYQY5ZSC3AW3GBXNEPIK76IG"})
+	assert.True(t, IsNotFoundError(err))
+	assert.Empty(t, replayer.Remaining())
+
+	// every interaction is replayed once
+	_, err = client.AccountDetail(AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no interaction left in transcript")
+	}
+}
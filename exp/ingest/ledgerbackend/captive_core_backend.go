@@ -19,10 +19,10 @@ var _ LedgerBackend = (*captiveStellarCore)(nil)
 // stream LedgerCloseMeta data from a "captive" stellar-core: one running as a
 // subprocess and replaying portions of history against an in-memory ledger.
 //
-// A captive stellar-core still needs (and allocates, in os.TempDir()) a
-// temporary directory to run in: one in which its config file is stored, along
-// with temporary files it downloads and decompresses, and its bucket
-// state. Only the ledger will be in-memory (and we might even switch this to
+// A captive stellar-core still needs (and allocates, in os.TempDir() unless a
+// storage path is configured) a temporary directory to run in: one in which its
+// config file is stored, along with temporary files it downloads and
+// decompresses, and its bucket state. Only the ledger will be in-memory (and we might even switch this to
 // SQLite + large buffers in the future if the in-memory ledger gets too big.)
 //
 // Feel free to reorganize this to fit better. It's preliminary!
@@ -72,17 +72,43 @@ type captiveStellarCore struct {
 	nextLedger      uint32 // next ledger expected, error w/ restart if not seen
 }
 
+// CaptiveCoreConfig contains the configuration of a captive stellar-core.
+type CaptiveCoreConfig struct {
+	// ExecutablePath is the path to the stellar-core binary.
+	ExecutablePath string
+	// NetworkPassphrase is the passphrase of the network replayed by core.
+	NetworkPassphrase string
+	// HistoryURLs are the URLs of the history archives core catches up from.
+	HistoryURLs []string
+	// ConfigAppendPath is an optional path to a file whose contents are added
+	// to the generated stellar-core config file.
+	ConfigAppendPath string
+	// StoragePath is an optional directory in which the temporary directories
+	// of the subprocesses are created. Defaults to os.TempDir().
+	StoragePath string
+}
+
 // NewCaptive returns a new captiveStellarCore that is not running. Will lazily start a subprocess
 // to feed it a block of streaming metadata when user calls .GetLedger(), and will kill
 // and restart the subprocess if subsequent calls to .GetLedger() are discontiguous.
 //
 // Platform-specific pipe setup logic is in the .start() methods.
 func NewCaptive(executablePath, networkPassphrase string, historyURLs []string) *captiveStellarCore {
+	return NewCaptiveFromConfig(CaptiveCoreConfig{
+		ExecutablePath:    executablePath,
+		NetworkPassphrase: networkPassphrase,
+		HistoryURLs:       historyURLs,
+	})
+}
+
+// NewCaptiveFromConfig returns a new captiveStellarCore that is not running,
+// configured by `config`. See NewCaptive.
+func NewCaptiveFromConfig(config CaptiveCoreConfig) *captiveStellarCore {
 	return &captiveStellarCore{
-		networkPassphrase: networkPassphrase,
-		historyURLs:       historyURLs,
+		networkPassphrase: config.NetworkPassphrase,
+		historyURLs:       config.HistoryURLs,
 		nextLedger:        0,
-		stellarCoreRunner: newStellarCoreRunner(executablePath, networkPassphrase, historyURLs),
+		stellarCoreRunner: newStellarCoreRunner(config),
 	}
 }
 
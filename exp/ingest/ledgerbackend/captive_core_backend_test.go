@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"encoding/hex"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stellar/go/network"
@@ -108,6 +112,39 @@ func TestCaptiveNew(t *testing.T) {
 	assert.Equal(t, historyURLs, captiveStellarCore.stellarCoreRunner.(*stellarCoreRunner).historyURLs)
 }
 
+func TestCaptiveNewFromConfig(t *testing.T) {
+	appendix, err := ioutil.TempFile("", "captive-core-appendix")
+	require.NoError(t, err)
+	defer os.Remove(appendix.Name())
+	_, err = appendix.WriteString("LOG_FILE_PATH=\"\"\n[HISTORY.local]\nget=\"cp /tmp/{0} {1}\"\n")
+	require.NoError(t, err)
+	require.NoError(t, appendix.Close())
+
+	storagePath, err := ioutil.TempDir("", "captive-core-storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(storagePath)
+
+	captiveStellarCore := NewCaptiveFromConfig(CaptiveCoreConfig{
+		ExecutablePath:    "/etc/stellar-core",
+		NetworkPassphrase: network.PublicNetworkPassphrase,
+		HistoryURLs:       []string{"http://history.stellar.org/prd/core-live/core_live_001"},
+		ConfigAppendPath:  appendix.Name(),
+		StoragePath:       storagePath,
+	})
+	runner := captiveStellarCore.stellarCoreRunner.(*stellarCoreRunner)
+	assert.Equal(t, storagePath, filepath.Dir(runner.getTmpDir()))
+
+	conf, err := runner.getConf()
+	require.NoError(t, err)
+	// the appendix goes between the generated top-level options and tables
+	assert.Contains(t, conf, "\nLOG_FILE_PATH=\"\"\n[HISTORY.local]\nget=\"cp /tmp/{0} {1}\"\n[HISTORY.h0]\n")
+	assert.True(t, strings.Index(conf, "METADATA_OUTPUT_STREAM") < strings.Index(conf, "LOG_FILE_PATH"))
+
+	runner.configAppendPath = filepath.Join(storagePath, "missing.cfg")
+	_, err = runner.getConf()
+	assert.Error(t, err)
+}
+
 func TestCaptivePrepareRange(t *testing.T) {
 	var buf bytes.Buffer
 
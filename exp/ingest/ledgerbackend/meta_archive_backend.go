@@ -0,0 +1,159 @@
+package ledgerbackend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/historyarchive"
+	"github.com/stellar/go/xdr"
+)
+
+// metaArchiveLatestPath is the file holding the sequence of the latest ledger
+// exported to a meta archive.
+const metaArchiveLatestPath = "latest"
+
+// MetaArchiveLedgerPath returns the path of the file holding the
+// LedgerCloseMeta of ledger `sequence` in a meta archive.
+func MetaArchiveLedgerPath(sequence uint32) string {
+	return fmt.Sprintf("ledgers/%d.xdr.gz", sequence)
+}
+
+// MetaArchiveBackend reads ledgers exported to a meta archive: an object store
+// (a directory, an S3 bucket or an HTTP server, see
+// historyarchive.ConnectBackend) holding the gzipped XDR LedgerCloseMeta of
+// every ledger at MetaArchiveLedgerPath, and the sequence of the latest
+// exported ledger in the `latest` file.
+//
+// Unlike history archives, meta archives contain the full meta of the
+// ledgers, and unlike captive stellar-core, any number of readers can read
+// them concurrently without replaying the ledgers. They are written by
+// PutLedger, see the ledger-exporter tool.
+type MetaArchiveBackend struct {
+	backend historyarchive.ArchiveBackend
+}
+
+var _ LedgerBackend = (*MetaArchiveBackend)(nil)
+
+// NewMetaArchiveBackendFromURL builds a new MetaArchiveBackend reading the
+// meta archive at `archiveURL`.
+func NewMetaArchiveBackendFromURL(archiveURL string) (*MetaArchiveBackend, error) {
+	backend, err := historyarchive.ConnectBackend(
+		archiveURL,
+		historyarchive.ConnectOptions{Context: context.Background()},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to meta archive")
+	}
+
+	return NewMetaArchiveBackendFromBackend(backend), nil
+}
+
+// NewMetaArchiveBackendFromBackend builds a new MetaArchiveBackend using
+// historyarchive.ArchiveBackend.
+func NewMetaArchiveBackendFromBackend(backend historyarchive.ArchiveBackend) *MetaArchiveBackend {
+	return &MetaArchiveBackend{backend: backend}
+}
+
+// GetLatestLedgerSequence returns the sequence of the latest ledger exported
+// to the meta archive.
+func (mab *MetaArchiveBackend) GetLatestLedgerSequence() (uint32, error) {
+	file, err := mab.backend.GetFile(metaArchiveLatestPath)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not open latest file")
+	}
+	defer file.Close()
+
+	content, err := ioutil.ReadAll(file)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not read latest file")
+	}
+
+	sequence, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 32)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid latest file")
+	}
+	return uint32(sequence), nil
+}
+
+// PrepareRange does nothing because every ledger of a meta archive is stored
+// in its own file.
+func (mab *MetaArchiveBackend) PrepareRange(from uint32, to uint32) error {
+	return nil
+}
+
+// GetLedger returns the LedgerCloseMeta for the given ledger sequence number.
+// The first returned value is false when the ledger has not been exported to
+// the meta archive.
+func (mab *MetaArchiveBackend) GetLedger(sequence uint32) (bool, xdr.LedgerCloseMeta, error) {
+	path := MetaArchiveLedgerPath(sequence)
+	exists, err := mab.backend.Exists(path)
+	if err != nil {
+		return false, xdr.LedgerCloseMeta{}, errors.Wrapf(err, "error checking if %s exists", path)
+	}
+	if !exists {
+		return false, xdr.LedgerCloseMeta{}, nil
+	}
+
+	file, err := mab.backend.GetFile(path)
+	if err != nil {
+		return false, xdr.LedgerCloseMeta{}, errors.Wrapf(err, "could not open %s", path)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return false, xdr.LedgerCloseMeta{}, errors.Wrapf(err, "could not decompress %s", path)
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return false, xdr.LedgerCloseMeta{}, errors.Wrapf(err, "could not read %s", path)
+	}
+
+	var meta xdr.LedgerCloseMeta
+	if err = xdr.SafeUnmarshal(content, &meta); err != nil {
+		return false, xdr.LedgerCloseMeta{}, errors.Wrapf(err, "could not unmarshal %s", path)
+	}
+	if meta.LedgerSequence() != sequence {
+		return false, xdr.LedgerCloseMeta{}, errors.Errorf(
+			"%s holds ledger %d", path, meta.LedgerSequence(),
+		)
+	}
+	return true, meta, nil
+}
+
+// PutLedger exports `meta` to the meta archive and makes it the latest
+// ledger. Ledgers must be exported in order so that every ledger up to the
+// latest one can be read.
+func (mab *MetaArchiveBackend) PutLedger(meta xdr.LedgerCloseMeta) error {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := xdr.Marshal(writer, meta); err != nil {
+		return errors.Wrap(err, "could not marshal ledger")
+	}
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(err, "could not compress ledger")
+	}
+
+	sequence := meta.LedgerSequence()
+	path := MetaArchiveLedgerPath(sequence)
+	if err := mab.backend.PutFile(path, ioutil.NopCloser(&buf)); err != nil {
+		return errors.Wrapf(err, "could not write %s", path)
+	}
+
+	latest := strings.NewReader(strconv.FormatUint(uint64(sequence), 10))
+	if err := mab.backend.PutFile(metaArchiveLatestPath, ioutil.NopCloser(latest)); err != nil {
+		return errors.Wrap(err, "could not write latest file")
+	}
+	return nil
+}
+
+// Close does nothing, MetaArchiveBackend keeps no state.
+func (mab *MetaArchiveBackend) Close() error {
+	return nil
+}
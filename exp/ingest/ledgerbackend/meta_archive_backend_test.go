@@ -0,0 +1,62 @@
+package ledgerbackend
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLedgerCloseMeta(sequence uint32) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					LedgerSeq: xdr.Uint32(sequence),
+				},
+			},
+		},
+	}
+}
+
+func TestMetaArchiveBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "meta-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	backend, err := NewMetaArchiveBackendFromURL("file://" + dir)
+	require.NoError(t, err)
+	defer backend.Close()
+
+	_, err = backend.GetLatestLedgerSequence()
+	assert.Error(t, err)
+
+	for sequence := uint32(2); sequence <= 4; sequence++ {
+		require.NoError(t, backend.PutLedger(testLedgerCloseMeta(sequence)))
+	}
+
+	latest, err := backend.GetLatestLedgerSequence()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4), latest)
+
+	require.NoError(t, backend.PrepareRange(2, 4))
+	exists, meta, err := backend.GetLedger(3)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, testLedgerCloseMeta(3), meta)
+
+	exists, _, err = backend.GetLedger(5)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// a file holding another ledger is rejected
+	file, err := ioutil.ReadFile(dir + "/" + MetaArchiveLedgerPath(2))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(dir+"/"+MetaArchiveLedgerPath(3), file, 0644))
+	_, _, err = backend.GetLedger(3)
+	assert.EqualError(t, err, "ledgers/3.xdr.gz holds ledger 2")
+}
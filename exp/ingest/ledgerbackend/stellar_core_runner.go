@@ -26,6 +26,8 @@ type stellarCoreRunner struct {
 	executablePath    string
 	networkPassphrase string
 	historyURLs       []string
+	configAppendPath  string
+	storagePath       string
 
 	cmd      *exec.Cmd
 	metaPipe io.Reader
@@ -33,17 +35,22 @@ type stellarCoreRunner struct {
 	nonce    string
 }
 
-func newStellarCoreRunner(executablePath, networkPassphrase string, historyURLs []string) *stellarCoreRunner {
+func newStellarCoreRunner(config CaptiveCoreConfig) *stellarCoreRunner {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	return &stellarCoreRunner{
-		executablePath:    executablePath,
-		networkPassphrase: networkPassphrase,
-		historyURLs:       historyURLs,
+		executablePath:    config.ExecutablePath,
+		networkPassphrase: config.NetworkPassphrase,
+		historyURLs:       config.HistoryURLs,
+		configAppendPath:  config.ConfigAppendPath,
+		storagePath:       config.StoragePath,
 		nonce:             fmt.Sprintf("captive-stellar-core-%x", r.Uint64()),
 	}
 }
 
-func (r *stellarCoreRunner) getConf() string {
+// getConf returns the contents of the stellar-core config file. The contents
+// of the file at configAppendPath are inserted after the generated top-level
+// options, so the file can contain both top-level options and tables.
+func (r *stellarCoreRunner) getConf() (string, error) {
 	lines := []string{
 		"# Generated file -- do not edit",
 		"RUN_STANDALONE=true",
@@ -54,6 +61,17 @@ func (r *stellarCoreRunner) getConf() string {
 		fmt.Sprintf(`BUCKET_DIR_PATH="%s"`, filepath.Join(r.getTmpDir(), "buckets")),
 		fmt.Sprintf(`METADATA_OUTPUT_STREAM="%s"`, r.getPipeName()),
 	}
+	conf := strings.ReplaceAll(strings.Join(lines, "\n"), "\\", "\\\\")
+
+	if r.configAppendPath != "" {
+		appendix, err := ioutil.ReadFile(r.configAppendPath)
+		if err != nil {
+			return "", errors.Wrap(err, "error reading captive core config appendix")
+		}
+		conf += "\n" + strings.TrimRight(string(appendix), "\n")
+	}
+
+	lines = []string{}
 	for i, val := range r.historyURLs {
 		lines = append(lines, fmt.Sprintf("[HISTORY.h%d]", i))
 		lines = append(lines, fmt.Sprintf(`get="curl -sf %s/{0} -o {1}"`, val))
@@ -64,7 +82,8 @@ func (r *stellarCoreRunner) getConf() string {
 		"[QUORUM_SET]",
 		"THRESHOLD_PERCENT=100",
 		`VALIDATORS=["GCZBOIAY4HLKAJVNJORXZOZRAY2BJDBZHKPBHZCRAIUR5IHC2UHBGCQR"]`)
-	return strings.ReplaceAll(strings.Join(lines, "\n"), "\\", "\\\\")
+	conf += "\n" + strings.ReplaceAll(strings.Join(lines, "\n"), "\\", "\\\\")
+	return conf, nil
 }
 
 func (r *stellarCoreRunner) getConfFileName() string {
@@ -93,8 +112,12 @@ func (r *stellarCoreRunner) getTmpDir() string {
 	if r.tempDir != "" {
 		return r.tempDir
 	}
+	storagePath := r.storagePath
+	if storagePath == "" {
+		storagePath = os.TempDir()
+	}
 	random := rand.New(rand.NewSource(time.Now().UnixNano()))
-	r.tempDir = filepath.Join(os.TempDir(), fmt.Sprintf("captive-stellar-core-%x", random.Uint64()))
+	r.tempDir = filepath.Join(storagePath, fmt.Sprintf("captive-stellar-core-%x", random.Uint64()))
 	return r.tempDir
 }
 
@@ -106,7 +129,10 @@ func (r *stellarCoreRunner) writeConf() error {
 	if e != nil {
 		return errors.Wrap(e, "error creating subprocess tmpdir")
 	}
-	conf := r.getConf()
+	conf, err := r.getConf()
+	if err != nil {
+		return err
+	}
 	return ioutil.WriteFile(r.getConfFileName(), []byte(conf), 0644)
 }
 
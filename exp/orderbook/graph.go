@@ -293,6 +293,14 @@ func (graph *OrderBookGraph) IsEmpty() bool {
 	return len(graph.edgesForSellingAsset) == 0
 }
 
+// LastLedger returns the sequence of the last ledger applied to the graph
+func (graph *OrderBookGraph) LastLedger() uint32 {
+	graph.lock.RLock()
+	defer graph.lock.RUnlock()
+
+	return graph.lastLedger
+}
+
 // FindPaths returns a list of payment paths originating from a source account
 // and ending with a given destinaton asset and amount.
 func (graph *OrderBookGraph) FindPaths(
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
 	"github.com/stellar/go/support/errors"
 	supporthttp "github.com/stellar/go/support/http"
 	supportlog "github.com/stellar/go/support/log"
@@ -79,6 +80,10 @@ func handler(opts Options) (http.Handler, error) {
 	}
 	horizonClient.SetHorizonTimeout(horizonTimeout)
 
+	// challenges are signed for the network of the options, which may be a
+	// standalone network
+	network.AllowUnregistered(true)
+
 	mux := supporthttp.NewAPIMux(opts.Logger)
 
 	mux.NotFound(errorHandler{Error: notFound}.ServeHTTP)
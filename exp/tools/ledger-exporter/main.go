@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/stellar/go/exp/ingest/ledgerbackend"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+)
+
+func main() {
+	from := flag.Uint("from", 0, "first ledger to export")
+	to := flag.Uint("to", 0, "last ledger to export")
+	archiveURL := flag.String("archive-url", "", "URL of the meta archive receiving the ledgers, e.g. file:///var/lib/ledgers or s3://bucket/ledgers")
+	corePath := flag.String("stellar-core-binary-path", "stellar-core", "path to the stellar-core binary replaying the ledgers")
+	networkPassphrase := flag.String("network-passphrase", network.PublicNetworkPassphrase, "passphrase of the network")
+	historyArchiveURLs := flag.String("history-archive-urls", "https://history.stellar.org/prd/core-live/core_live_001", "comma-separated list of history archives stellar-core catches up from")
+	flag.Parse()
+
+	log.SetLevel(log.InfoLevel)
+	if *from == 0 || *to < *from {
+		log.Fatal("-from and -to must be a valid range of ledgers")
+	}
+	if *archiveURL == "" {
+		log.Fatal("-archive-url is required")
+	}
+
+	archive, err := ledgerbackend.NewMetaArchiveBackendFromURL(*archiveURL)
+	if err != nil {
+		log.WithField("err", err).Fatal("cannot connect to the meta archive")
+	}
+
+	core := ledgerbackend.NewCaptive(
+		*corePath,
+		*networkPassphrase,
+		strings.Split(*historyArchiveURLs, ","),
+	)
+	defer core.Close()
+
+	if err = core.PrepareRange(uint32(*from), uint32(*to)); err != nil {
+		log.WithField("err", err).Fatal("cannot prepare range")
+	}
+
+	for sequence := uint32(*from); sequence <= uint32(*to); sequence++ {
+		exists, meta, err := core.GetLedger(sequence)
+		if err != nil {
+			log.WithField("ledger", sequence).WithField("err", err).Fatal("cannot get ledger")
+		}
+		if !exists {
+			log.WithField("ledger", sequence).Fatal("ledger not found")
+		}
+
+		if err = archive.PutLedger(meta); err != nil {
+			log.WithField("ledger", sequence).WithField("err", err).Fatal("cannot export ledger")
+		}
+		if sequence%64 == 0 {
+			log.WithField("ledger", sequence).Info("Exported ledgers")
+		}
+	}
+	log.WithField("from", *from).WithField("to", *to).Info("Exported all the ledgers")
+}
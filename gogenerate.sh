@@ -2,13 +2,7 @@
 set -e
 
 # Check if go-bindata is installed, if not install it.
-command -v go-bindata >/dev/null 2>&1 || (
-  dir=$(mktemp -d)
-  pushd $dir
-  go mod init tool
-  go get github.com/kevinburke/go-bindata/go-bindata@v3.18.0+incompatible
-  popd
-)
+command -v go-bindata >/dev/null 2>&1 || go install github.com/kevinburke/go-bindata/go-bindata@v3.18.0+incompatible
 
 printf "Running go generate...\n"
 go generate ./...
@@ -0,0 +1,140 @@
+package network
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/stellar/go/support/errors"
+)
+
+var (
+	registryMutex sync.RWMutex
+	// registry maps the names of the registered networks to their passphrase.
+	registry = map[string]string{
+		"public":  PublicNetworkPassphrase,
+		"testnet": TestNetworkPassphrase,
+	}
+	// allowUnregistered is set with AllowUnregistered.
+	allowUnregistered bool
+)
+
+// Register registers the network with the passphrase `passphrase` under the
+// name `name`, so that it can be looked up with Lookup and transactions can be
+// signed for it with txnbuild. The public network and the test network are
+// registered under the names "public" and "testnet". Registering a name again
+// with the same passphrase has no effect.
+func Register(name, passphrase string) error {
+	if name == "" {
+		return errors.New("empty network name")
+	}
+	if strings.TrimSpace(passphrase) == "" {
+		return errors.New("empty network passphrase")
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if registered, ok := registry[name]; ok && registered != passphrase {
+		return errors.Errorf("network %s is already registered with another passphrase", name)
+	}
+	registry[name] = passphrase
+	return nil
+}
+
+// Lookup returns the passphrase of the network registered under the name
+// `name`, e.g. to select the network with a command line flag.
+func Lookup(name string) (string, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	passphrase, ok := registry[name]
+	return passphrase, ok
+}
+
+// IsRegistered returns true when a network is registered with the passphrase
+// `passphrase`.
+func IsRegistered(passphrase string) bool {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	for _, registered := range registry {
+		if registered == passphrase {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowUnregistered sets whether ValidatePassphrase accepts the passphrases of
+// networks which are not registered, e.g. for programs which sign for the
+// network given in their configuration. Passphrases close to the passphrase of
+// a registered network are rejected either way.
+func AllowUnregistered(allow bool) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	allowUnregistered = allow
+}
+
+// maxPassphraseTypos is the maximum number of edits between an unregistered
+// passphrase and the passphrase of a registered network for ValidatePassphrase
+// to consider the former a typo of the latter.
+const maxPassphraseTypos = 3
+
+// ValidatePassphrase returns an error when `passphrase` is empty or is not the
+// passphrase of a registered network, since the signatures of transactions
+// signed with a mistyped passphrase are not valid on the network they are
+// intended for. Standalone and private networks are allowed by registering
+// them with Register, or by calling AllowUnregistered, which still rejects
+// the passphrases close to the one of a registered network, e.g. because they
+// contain a typo or a trailing space.
+func ValidatePassphrase(passphrase string) error {
+	if strings.TrimSpace(passphrase) == "" {
+		return errors.New("empty network passphrase")
+	}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	for _, registered := range registry {
+		if registered == passphrase {
+			return nil
+		}
+	}
+	for name, registered := range registry {
+		if strings.EqualFold(strings.TrimSpace(passphrase), strings.TrimSpace(registered)) ||
+			editDistance(passphrase, registered) <= maxPassphraseTypos {
+			return errors.Errorf("network passphrase %q is not registered and is close to the passphrase of network %s, it must be registered with network.Register if it is not a typo", passphrase, name)
+		}
+	}
+	if !allowUnregistered {
+		return errors.Errorf("network passphrase %q is not registered, it must be registered with network.Register or allowed with network.AllowUnregistered", passphrase)
+	}
+	return nil
+}
+
+// editDistance returns the Levenshtein distance between `a` and `b`.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
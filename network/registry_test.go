@@ -0,0 +1,79 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	passphrase, ok := Lookup("public")
+	assert.True(t, ok)
+	assert.Equal(t, PublicNetworkPassphrase, passphrase)
+	passphrase, ok = Lookup("testnet")
+	assert.True(t, ok)
+	assert.Equal(t, TestNetworkPassphrase, passphrase)
+	_, ok = Lookup("standalone")
+	assert.False(t, ok)
+
+	assert.NoError(t, ValidatePassphrase(PublicNetworkPassphrase))
+	assert.NoError(t, ValidatePassphrase(TestNetworkPassphrase))
+	assert.EqualError(t, ValidatePassphrase(" "), "empty network passphrase")
+	assert.EqualError(
+		t,
+		ValidatePassphrase("Test SDF Network ; September 2015 "),
+		`network passphrase "Test SDF Network ; September 2015 " is not registered and is close to the passphrase of network testnet, it must be registered with network.Register if it is not a typo`,
+	)
+	assert.Error(t, ValidatePassphrase("test sdf network ; september 2015"))
+	assert.Error(t, ValidatePassphrase("Public Global Stellar Netwrok ; September 2015"))
+
+	standalone := "Standalone Network ; February 2017"
+	assert.False(t, IsRegistered(standalone))
+	assert.EqualError(
+		t,
+		ValidatePassphrase(standalone),
+		`network passphrase "Standalone Network ; February 2017" is not registered, it must be registered with network.Register or allowed with network.AllowUnregistered`,
+	)
+	assert.NoError(t, Register("standalone", standalone))
+	assert.NoError(t, Register("standalone", standalone))
+	assert.True(t, IsRegistered(standalone))
+	assert.NoError(t, ValidatePassphrase(standalone))
+	passphrase, ok = Lookup("standalone")
+	assert.True(t, ok)
+	assert.Equal(t, standalone, passphrase)
+
+	assert.EqualError(t, Register("testnet", standalone), "network testnet is already registered with another passphrase")
+	assert.EqualError(t, Register("", standalone), "empty network name")
+	assert.EqualError(t, Register("other", ""), "empty network passphrase")
+	passphrase, _ = Lookup("testnet")
+	assert.Equal(t, TestNetworkPassphrase, passphrase)
+
+	// registering a network close to another one allows its passphrase
+	similar := "Test SDF Network ; September 2016"
+	assert.Error(t, ValidatePassphrase(similar))
+	assert.NoError(t, Register("testnet-2016", similar))
+	assert.NoError(t, ValidatePassphrase(similar))
+}
+
+func TestAllowUnregistered(t *testing.T) {
+	private := "Private Network ; 2020"
+	assert.Error(t, ValidatePassphrase(private))
+
+	AllowUnregistered(true)
+	defer AllowUnregistered(false)
+	assert.NoError(t, ValidatePassphrase(private))
+	assert.False(t, IsRegistered(private))
+	// typos of registered networks are still rejected
+	assert.Error(t, ValidatePassphrase("Public Global Stellar Netwrok ; September 2015"))
+	assert.EqualError(t, ValidatePassphrase(""), "empty network passphrase")
+
+	AllowUnregistered(false)
+	assert.Error(t, ValidatePassphrase(private))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("", ""))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 1, editDistance("2015", "2016"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
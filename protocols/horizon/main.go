@@ -234,6 +234,141 @@ func (l Ledger) PagingToken() string {
 	return l.PT
 }
 
+// LedgerEstimate is the sequence and close time of a ledger. Ledgers which are
+// not in the history database, e.g. future ledgers, are estimated from the
+// average close time of recent ledgers.
+type LedgerEstimate struct {
+	Links struct {
+		Self hal.Link `json:"self"`
+	} `json:"_links"`
+	Sequence  int32     `json:"sequence"`
+	ClosedAt  time.Time `json:"closed_at"`
+	Estimated bool      `json:"estimated"`
+	// AverageCloseTimeMillis is the average time between the closing of two
+	// recent ledgers which was used to estimate the ledger.
+	AverageCloseTimeMillis int64 `json:"average_close_time_ms"`
+}
+
+// PagingToken implementation for hal.Pageable. Not actually used
+func (res LedgerEstimate) PagingToken() string {
+	return ""
+}
+
+// LedgerEntry is a raw ledger entry from the current state of the ledger. The
+// entry is returned both decoded, in the field matching its type, and as XDR.
+type LedgerEntry struct {
+	Links struct {
+		Self hal.Link `json:"self"`
+	} `json:"_links"`
+
+	Type               string                `json:"type"`
+	LastModifiedLedger uint32                `json:"last_modified_ledger"`
+	Account            *LedgerEntryAccount   `json:"account,omitempty"`
+	TrustLine          *LedgerEntryTrustLine `json:"trustline,omitempty"`
+	Offer              *LedgerEntryOffer     `json:"offer,omitempty"`
+	Data               *LedgerEntryData      `json:"data,omitempty"`
+	KeyXDR             string                `json:"key_xdr"`
+	EntryXDR           string                `json:"entry_xdr"`
+}
+
+// PagingToken implementation for hal.Pageable. Not actually used
+func (res LedgerEntry) PagingToken() string {
+	return ""
+}
+
+// LedgerEntryAccount is the decoded form of an account ledger entry.
+type LedgerEntryAccount struct {
+	AccountID            string            `json:"account_id"`
+	Balance              string            `json:"balance"`
+	Sequence             string            `json:"sequence"`
+	SubentryCount        uint32            `json:"subentry_count"`
+	InflationDestination string            `json:"inflation_destination,omitempty"`
+	HomeDomain           string            `json:"home_domain,omitempty"`
+	MasterKeyWeight      byte              `json:"master_key_weight"`
+	Thresholds           AccountThresholds `json:"thresholds"`
+	Flags                AccountFlags      `json:"flags"`
+	Signers              []Signer          `json:"signers"`
+	BuyingLiabilities    string            `json:"buying_liabilities"`
+	SellingLiabilities   string            `json:"selling_liabilities"`
+}
+
+// LedgerEntryTrustLine is the decoded form of a trust line ledger entry.
+type LedgerEntryTrustLine struct {
+	AccountID                         string `json:"account_id"`
+	Asset                             Asset  `json:"asset"`
+	Balance                           string `json:"balance"`
+	Limit                             string `json:"limit"`
+	IsAuthorized                      bool   `json:"is_authorized"`
+	IsAuthorizedToMaintainLiabilities bool   `json:"is_authorized_to_maintain_liabilities"`
+	BuyingLiabilities                 string `json:"buying_liabilities"`
+	SellingLiabilities                string `json:"selling_liabilities"`
+}
+
+// LedgerEntryOffer is the decoded form of an offer ledger entry.
+type LedgerEntryOffer struct {
+	Seller  string `json:"seller"`
+	OfferID int64  `json:"offer_id,string"`
+	Selling Asset  `json:"selling"`
+	Buying  Asset  `json:"buying"`
+	Amount  string `json:"amount"`
+	PriceR  Price  `json:"price_r"`
+	Price   string `json:"price"`
+	Passive bool   `json:"passive"`
+}
+
+// LedgerEntryData is the decoded form of a data ledger entry. The value is
+// base64 encoded.
+type LedgerEntryData struct {
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+}
+
+// ClaimableBalance is the display form of a claimable balance: an amount of
+// an asset which can be claimed by its claimants while their predicates hold.
+type ClaimableBalance struct {
+	Links struct {
+		Self         hal.Link `json:"self"`
+		Transactions hal.Link `json:"transactions"`
+		Operations   hal.Link `json:"operations"`
+	} `json:"_links"`
+
+	BalanceID          string     `json:"id"`
+	Asset              string     `json:"asset"`
+	Amount             string     `json:"amount"`
+	Sponsor            string     `json:"sponsor,omitempty"`
+	LastModifiedLedger uint32     `json:"last_modified_ledger"`
+	LastModifiedTime   *time.Time `json:"last_modified_time"`
+	Claimants          []Claimant `json:"claimants"`
+	PT                 string     `json:"paging_token"`
+}
+
+func (c ClaimableBalance) PagingToken() string {
+	return c.PT
+}
+
+// Claimant is an account which can claim a claimable balance while its
+// predicate holds.
+type Claimant struct {
+	Destination string         `json:"destination"`
+	Predicate   ClaimPredicate `json:"predicate"`
+}
+
+// ClaimPredicate is the condition under which a claimant can claim a
+// claimable balance. Exactly one of its fields is set.
+type ClaimPredicate struct {
+	Unconditional bool             `json:"unconditional,omitempty"`
+	And           []ClaimPredicate `json:"and,omitempty"`
+	Or            []ClaimPredicate `json:"or,omitempty"`
+	Not           *ClaimPredicate  `json:"not,omitempty"`
+	// AbsBefore is the time, in RFC 3339 format, before which the balance
+	// can be claimed.
+	AbsBefore string `json:"abs_before,omitempty"`
+	// RelBefore is the number of seconds, after the creation of the balance,
+	// during which the balance can be claimed.
+	RelBefore string `json:"rel_before,omitempty"`
+}
+
 // Offer is the display form of an offer to trade currency.
 type Offer struct {
 	Links struct {
@@ -320,15 +455,33 @@ type Root struct {
 		Transactions        hal.Link  `json:"transactions"`
 	} `json:"_links"`
 
-	HorizonVersion               string `json:"horizon_version"`
-	StellarCoreVersion           string `json:"core_version"`
-	IngestSequence               uint32 `json:"ingest_latest_ledger"`
-	HorizonSequence              int32  `json:"history_latest_ledger"`
-	HistoryElderSequence         int32  `json:"history_elder_ledger"`
-	CoreSequence                 int32  `json:"core_latest_ledger"`
-	NetworkPassphrase            string `json:"network_passphrase"`
-	CurrentProtocolVersion       int32  `json:"current_protocol_version"`
-	CoreSupportedProtocolVersion int32  `json:"core_supported_protocol_version"`
+	HorizonVersion               string        `json:"horizon_version"`
+	StellarCoreVersion           string        `json:"core_version"`
+	IngestSequence               uint32        `json:"ingest_latest_ledger"`
+	HorizonSequence              int32         `json:"history_latest_ledger"`
+	HistoryElderSequence         int32         `json:"history_elder_ledger"`
+	CoreSequence                 int32         `json:"core_latest_ledger"`
+	NetworkPassphrase            string        `json:"network_passphrase"`
+	CurrentProtocolVersion       int32         `json:"current_protocol_version"`
+	CoreSupportedProtocolVersion int32         `json:"core_supported_protocol_version"`
+	Ingestion                    RootIngestion `json:"ingestion"`
+}
+
+// RootIngestion summarizes the ingestion status of a horizon instance so that
+// clients can detect instances which are behind the network.
+type RootIngestion struct {
+	CoreLatestLedger      int32  `json:"core_latest_ledger"`
+	HistoryLatestLedger   int32  `json:"history_latest_ledger"`
+	ExpIngestLatestLedger uint32 `json:"exp_ingest_latest_ledger"`
+	// HistoryElderLedger and HistoryRetentionCount describe the window of
+	// ledgers kept in the history database. A retention count of zero means
+	// that history is never reaped.
+	HistoryElderLedger    int32 `json:"history_elder_ledger"`
+	HistoryRetentionCount uint  `json:"history_retention_count"`
+	// CaughtUp is true when both the history and the experimental ingestion
+	// are at most StaleThreshold ledgers behind stellar-core.
+	CaughtUp       bool `json:"caught_up"`
+	StaleThreshold uint `json:"stale_threshold"`
 }
 
 // Signer represents one of an account's signers.
@@ -416,7 +569,7 @@ type TradeAggregation struct {
 
 // PagingToken implementation for hal.Pageable. Not actually used
 func (res TradeAggregation) PagingToken() string {
-	return string(res.Timestamp)
+	return strconv.FormatInt(res.Timestamp, 10)
 }
 
 // Transaction represents a single, successful transaction
@@ -463,6 +616,8 @@ type Transaction struct {
 // FeeBumpTransaction contains information about a fee bump transaction
 type FeeBumpTransaction struct {
 	Hash       string   `json:"hash"`
+	FeeAccount string   `json:"fee_account"`
+	MaxFee     int64    `json:"max_fee,string"`
 	Signatures []string `json:"signatures"`
 }
 
@@ -568,6 +723,34 @@ type AccountData struct {
 	Value string `json:"value"`
 }
 
+// AccountDataEntry is a single data entry of an account as listed by the
+// /accounts/{account_id}/data endpoint.
+type AccountDataEntry struct {
+	Links struct {
+		Self hal.Link `json:"self"`
+	} `json:"_links"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// DecodedValue is the value of the entry as a string. It is omitted when
+	// the value is not valid UTF-8.
+	DecodedValue       *string `json:"decoded_value,omitempty"`
+	LastModifiedLedger uint32  `json:"last_modified_ledger"`
+	PT                 string  `json:"paging_token"`
+}
+
+// PagingToken implementation for hal.Pageable
+func (res AccountDataEntry) PagingToken() string {
+	return res.PT
+}
+
+// AccountDataPage returns a list of data entries of an account
+type AccountDataPage struct {
+	Links    hal.Links `json:"_links"`
+	Embedded struct {
+		Records []AccountDataEntry `json:"records"`
+	} `json:"_embedded"`
+}
+
 // AccountsPage returns a list of account records
 type AccountsPage struct {
 	Links    hal.Links `json:"_links"`
@@ -576,6 +759,14 @@ type AccountsPage struct {
 	} `json:"_embedded"`
 }
 
+// ClaimableBalancesPage returns a list of claimable balance records
+type ClaimableBalancesPage struct {
+	Links    hal.Links `json:"_links"`
+	Embedded struct {
+		Records []ClaimableBalance `json:"records"`
+	} `json:"_embedded"`
+}
+
 // TradeAggregationsPage returns a list of aggregated trade records, aggregated by resolution
 type TradeAggregationsPage struct {
 	Links    hal.Links `json:"_links"`
@@ -44,11 +44,15 @@ type Base struct {
 	PT string `json:"paging_token"`
 	// TransactionSuccessful defines if this operation is part of
 	// successful transaction.
-	TransactionSuccessful bool      `json:"transaction_successful"`
-	SourceAccount         string    `json:"source_account"`
-	Type                  string    `json:"type"`
-	TypeI                 int32     `json:"type_i"`
-	LedgerCloseTime       time.Time `json:"created_at"`
+	TransactionSuccessful bool   `json:"transaction_successful"`
+	SourceAccount         string `json:"source_account"`
+	// SourceAccountMuxed and SourceAccountMuxedID are set when the source
+	// account of the operation is a muxed (M...) account.
+	SourceAccountMuxed   string    `json:"source_account_muxed,omitempty"`
+	SourceAccountMuxedID uint64    `json:"source_account_muxed_id,omitempty,string"`
+	Type                 string    `json:"type"`
+	TypeI                int32     `json:"type_i"`
+	LedgerCloseTime      time.Time `json:"created_at"`
 	// TransactionHash is the hash of the transaction which created the operation
 	// Note that the Transaction field below is not always present in the Operation response.
 	// If the Transaction field is present TransactionHash is redundant since the same information
@@ -84,9 +88,13 @@ type CreateAccount struct {
 type Payment struct {
 	Base
 	base.Asset
-	From   string `json:"from"`
-	To     string `json:"to"`
-	Amount string `json:"amount"`
+	From        string `json:"from"`
+	FromMuxed   string `json:"from_muxed,omitempty"`
+	FromMuxedID uint64 `json:"from_muxed_id,omitempty,string"`
+	To          string `json:"to"`
+	ToMuxed     string `json:"to_muxed,omitempty"`
+	ToMuxedID   uint64 `json:"to_muxed_id,omitempty,string"`
+	Amount      string `json:"amount"`
 }
 
 // PathPayment is the json resource representing a single operation whose type
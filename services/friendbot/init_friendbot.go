@@ -7,6 +7,7 @@ import (
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
 	"github.com/stellar/go/services/friendbot/internal"
 	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
@@ -25,6 +26,10 @@ func initFriendbot(
 		return nil, errors.New("invalid input param(s)")
 	}
 
+	// friendbot signs for the network of its configuration, which may be a
+	// standalone network
+	network.AllowUnregistered(true)
+
 	// Guarantee that friendbotSecret is a seed, if not blank.
 	strkey.MustDecode(strkey.VersionByteSeed, friendbotSecret)
 
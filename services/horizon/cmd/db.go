@@ -7,6 +7,9 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -28,6 +31,7 @@ var dbInitCmd = &cobra.Command{
 	Short: "install schema",
 	Long:  "init initializes the postgres database used by horizon.",
 	Run: func(cmd *cobra.Command, args []string) {
+		loadConfigFile()
 		dbURLConfigOption.Require()
 		dbURLConfigOption.SetValue()
 
@@ -49,6 +53,56 @@ var dbInitCmd = &cobra.Command{
 	},
 }
 
+var skipBackfills bool
+var backfillBatchSize uint
+var backfillBatchDelay uint
+var optionalIndexes string
+var dropOptionalIndexes string
+var dbMigrateCmdOpts = []*support.ConfigOption{
+	&support.ConfigOption{
+		Name:        "skip-backfills",
+		ConfigKey:   &skipBackfills,
+		OptType:     types.Bool,
+		Required:    false,
+		FlagDefault: false,
+		Usage: "[optional] if this flag is set, migrate up only applies the schema " +
+			"migrations and leaves the pending backfills for a later run",
+	},
+	&support.ConfigOption{
+		Name:        "backfill-batch-size",
+		ConfigKey:   &backfillBatchSize,
+		OptType:     types.Uint,
+		Required:    false,
+		FlagDefault: uint(10000),
+		Usage:       "[optional] number of keys (usually ids) of the rows backfilled at once",
+	},
+	&support.ConfigOption{
+		Name:        "backfill-batch-delay",
+		ConfigKey:   &backfillBatchDelay,
+		OptType:     types.Uint,
+		Required:    false,
+		FlagDefault: uint(100),
+		Usage:       "[optional] pause in milliseconds between two batches of a backfill, reduces the load on the database",
+	},
+	&support.ConfigOption{
+		Name:        "optional-indexes",
+		ConfigKey:   &optionalIndexes,
+		OptType:     types.String,
+		Required:    false,
+		FlagDefault: "",
+		Usage: "[optional] comma-separated list of optional indexes migrate up creates, " +
+			"see `horizon db index-report` for the available ones",
+	},
+	&support.ConfigOption{
+		Name:        "drop-optional-indexes",
+		ConfigKey:   &dropOptionalIndexes,
+		OptType:     types.String,
+		Required:    false,
+		FlagDefault: "",
+		Usage:       "[optional] comma-separated list of optional indexes migrate up drops",
+	},
+}
+
 var dbMigrateCmd = &cobra.Command{
 	Use:   "migrate [up|down|redo] [COUNT]",
 	Short: "migrate schema",
@@ -75,6 +129,16 @@ var dbMigrateCmd = &cobra.Command{
 			}
 		}
 
+		for _, co := range dbMigrateCmdOpts {
+			co.SetValue()
+		}
+		if backfillBatchSize == 0 {
+			log.Fatal("--backfill-batch-size must be greater than 0")
+		}
+		toCreate := mustParseOptionalIndexes("optional-indexes", optionalIndexes)
+		toDrop := mustParseOptionalIndexes("drop-optional-indexes", dropOptionalIndexes)
+
+		loadConfigFile()
 		dbURLConfigOption.Require()
 		dbURLConfigOption.SetValue()
 
@@ -94,6 +158,100 @@ var dbMigrateCmd = &cobra.Command{
 		} else {
 			log.Printf("Successfully applied %d migrations.\n", numMigrationsRun)
 		}
+
+		if dir != schema.MigrateUp {
+			return
+		}
+
+		if !skipBackfills {
+			numBackfillsRun, err := schema.RunBackfills(
+				db,
+				schema.Backfills,
+				int64(backfillBatchSize),
+				time.Duration(backfillBatchDelay)*time.Millisecond,
+			)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if numBackfillsRun > 0 {
+				log.Printf("Successfully completed %d backfills.\n", numBackfillsRun)
+			}
+		}
+
+		numIndexesDropped, err := schema.DropOptionalIndexes(db, toDrop)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if numIndexesDropped > 0 {
+			log.Printf("Successfully dropped %d optional indexes.\n", numIndexesDropped)
+		}
+
+		numIndexesCreated, err := schema.CreateOptionalIndexes(db, toCreate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if numIndexesCreated > 0 {
+			log.Printf("Successfully created %d optional indexes.\n", numIndexesCreated)
+		}
+	},
+}
+
+// mustParseOptionalIndexes parses the comma-separated list of optional indexes
+// of the `flag` option.
+func mustParseOptionalIndexes(flag, value string) []schema.OptionalIndex {
+	var indexes []schema.OptionalIndex
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		index, ok := schema.FindOptionalIndex(name)
+		if !ok {
+			log.Fatalf("--%s: unknown optional index %s", flag, name)
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+var dbIndexReportCmd = &cobra.Command{
+	Use:   "index-report",
+	Short: "reports which optional indexes would speed up the queries run against the database",
+	Long: "index-report compares the statements recorded by the pg_stat_statements extension " +
+		"with the optional indexes which can be created with `horizon db migrate up --optional-indexes`.",
+	Run: func(cmd *cobra.Command, args []string) {
+		loadConfigFile()
+		dbURLConfigOption.Require()
+		dbURLConfigOption.SetValue()
+
+		db, err := sql.Open("postgres", viper.GetString("db-url"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		pingDB(db)
+
+		report, err := schema.IndexReport(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "INDEX\tENABLED\tSTATEMENTS\tCALLS\tTOTAL TIME (s)\tRECOMMENDED\tQUERIES")
+		for _, advice := range report {
+			fmt.Fprintf(
+				w,
+				"%s\t%t\t%d\t%d\t%.1f\t%t\t%s\n",
+				advice.Index.Name,
+				advice.Enabled,
+				advice.Statements,
+				advice.Calls,
+				advice.TotalTimeMs/1000,
+				advice.Recommended(),
+				advice.Index.Description,
+			)
+		}
+		w.Flush()
 	},
 }
 
@@ -111,6 +269,39 @@ var dbReapCmd = &cobra.Command{
 	},
 }
 
+var dbRestoreSnapshotCmd = &cobra.Command{
+	Use:   "restore-snapshot [Snapshot URL] [Checkpoint ledger]",
+	Short: "initializes the ingestion state from a snapshot",
+	Long: "restore-snapshot loads the state tables of an empty database from the snapshot of a " +
+		"checkpoint ledger published at a file, http or https URL instead of ingesting the checkpoint " +
+		"from the history archives. Ingestion resumes from the following ledger.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			cmd.Usage()
+			os.Exit(1)
+		}
+
+		checkpoint, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			cmd.Usage()
+			log.Fatalf(`Invalid checkpoint ledger "%s"`, args[1])
+		}
+
+		initRootConfig()
+
+		horizonSession, err := db.Open("postgres", config.DatabaseURL)
+		if err != nil {
+			log.Fatalf("cannot open Horizon DB: %v", err)
+		}
+
+		err = expingest.RestoreSnapshot(horizonSession, args[0], uint32(checkpoint), config.NetworkPassphrase)
+		if err != nil {
+			log.Fatal(err)
+		}
+		hlog.Infof("Restored the state of checkpoint %d", checkpoint)
+	},
+}
+
 var dbReingestCmd = &cobra.Command{
 	Use:   "reingest",
 	Short: "reingest commands",
@@ -123,6 +314,9 @@ var dbReingestCmd = &cobra.Command{
 }
 
 var reingestForce bool
+var parallelWorkers uint
+var parallelJobSize uint32
+var ledgerMetaArchiveURL string
 var reingestRangeCmdOpts = []*support.ConfigOption{
 	&support.ConfigOption{
 		Name:        "force",
@@ -133,6 +327,31 @@ var reingestRangeCmdOpts = []*support.ConfigOption{
 		Usage: "[optional] if this flag is set, horizon will be blocked " +
 			"from ingesting until the reingestion command completes",
 	},
+	&support.ConfigOption{
+		Name:        "parallel-workers",
+		ConfigKey:   &parallelWorkers,
+		OptType:     types.Uint,
+		Required:    false,
+		FlagDefault: uint(1),
+		Usage: "[optional] if this flag is set to > 1, horizon will parallelize reingestion " +
+			"using the supplied number of workers, each with its own ledger backend",
+	},
+	&support.ConfigOption{
+		Name:        "parallel-job-size",
+		ConfigKey:   &parallelJobSize,
+		OptType:     types.Uint32,
+		Required:    false,
+		FlagDefault: uint32(100000),
+		Usage:       "[optional] maximum number of ledgers reingested by a parallel worker at once",
+	},
+	&support.ConfigOption{
+		Name:      "ledger-meta-archive-url",
+		ConfigKey: &ledgerMetaArchiveURL,
+		OptType:   types.String,
+		Required:  false,
+		Usage: "[optional] URL of a directory (file://), S3 bucket (s3://) or HTTP server holding " +
+			"the ledgers exported by ledger-exporter, which are reingested instead of running stellar-core",
+	},
 }
 
 var dbReingestRangeCmd = &cobra.Command{
@@ -160,11 +379,22 @@ var dbReingestRangeCmd = &cobra.Command{
 			argsInt32[i] = uint32(seq)
 		}
 
+		if reingestForce && parallelWorkers > 1 {
+			log.Fatal("--force is incompatible with --parallel-workers > 1")
+		}
+		if parallelJobSize == 0 {
+			log.Fatal("--parallel-job-size must be greater than 0")
+		}
+
 		initRootConfig()
 
-		coreSession, err := db.Open("postgres", config.StellarCoreDatabaseURL)
-		if err != nil {
-			log.Fatalf("cannot open Core DB: %v", err)
+		var coreSession *db.Session
+		if !config.EnableCaptiveCoreIngestion && ledgerMetaArchiveURL == "" {
+			var err error
+			coreSession, err = db.Open("postgres", config.StellarCoreDatabaseURL)
+			if err != nil {
+				log.Fatalf("cannot open Core DB: %v", err)
+			}
 		}
 
 		horizonSession, err := db.Open("postgres", config.DatabaseURL)
@@ -173,25 +403,43 @@ var dbReingestRangeCmd = &cobra.Command{
 		}
 
 		ingestConfig := expingest.Config{
-			CoreSession:       coreSession,
-			NetworkPassphrase: config.NetworkPassphrase,
-			HistorySession:    horizonSession,
-			HistoryArchiveURL: config.HistoryArchiveURLs[0],
+			CoreSession:              coreSession,
+			NetworkPassphrase:        config.NetworkPassphrase,
+			HistorySession:           horizonSession,
+			HistoryArchiveURL:        config.HistoryArchiveURLs[0],
+			HistoryAllowlistAccounts: config.HistoryAllowlistAccounts,
+			HistoryAllowlistAssets:   config.HistoryAllowlistAssets,
+			LedgerMetaArchiveURL:     ledgerMetaArchiveURL,
 		}
-		if config.EnableCaptiveCoreIngestion {
+		if config.EnableCaptiveCoreIngestion && ledgerMetaArchiveURL == "" {
 			ingestConfig.StellarCorePath = config.StellarCoreBinaryPath
+			ingestConfig.CaptiveCoreConfigAppendPath = config.CaptiveCoreConfigAppendPath
+			ingestConfig.CaptiveCoreStoragePath = config.CaptiveCoreStoragePath
 		}
 
-		system, err := expingest.NewSystem(ingestConfig)
-		if err != nil {
-			log.Fatal(err)
-		}
+		if parallelWorkers > 1 {
+			system, systemErr := expingest.NewParallelSystems(ingestConfig, parallelWorkers)
+			if systemErr != nil {
+				log.Fatal(systemErr)
+			}
+
+			err = system.ReingestRange(
+				argsInt32[0],
+				argsInt32[1],
+				parallelJobSize,
+			)
+		} else {
+			system, systemErr := expingest.NewSystem(ingestConfig)
+			if systemErr != nil {
+				log.Fatal(systemErr)
+			}
 
-		err = system.ReingestRange(
-			argsInt32[0],
-			argsInt32[1],
-			reingestForce,
-		)
+			err = system.ReingestRange(
+				argsInt32[0],
+				argsInt32[1],
+				reingestForce,
+			)
+		}
 		if err == nil {
 			hlog.Info("Range run successfully!")
 			return
@@ -214,6 +462,15 @@ var dbReingestRangeCmd = &cobra.Command{
 }
 
 func init() {
+	for _, co := range dbMigrateCmdOpts {
+		err := co.Init(dbMigrateCmd)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
+	viper.BindPFlags(dbMigrateCmd.PersistentFlags())
+
 	for _, co := range reingestRangeCmdOpts {
 		err := co.Init(dbReingestRangeCmd)
 		if err != nil {
@@ -229,6 +486,8 @@ func init() {
 		dbMigrateCmd,
 		dbReapCmd,
 		dbReingestCmd,
+		dbRestoreSnapshotCmd,
+		dbIndexReportCmd,
 	)
 	dbReingestCmd.AddCommand(dbReingestRangeCmd)
 }
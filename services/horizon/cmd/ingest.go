@@ -5,6 +5,9 @@ import (
 	"go/types"
 	"net/http"
 	_ "net/http/pprof"
+	"os"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,7 +24,8 @@ var ingestCmd = &cobra.Command{
 }
 
 var ingestVerifyFrom, ingestVerifyTo, ingestVerifyDebugServerPort uint32
-var ingestVerifyState bool
+var ingestVerifyState, ingestVerifyResume bool
+var ingestVerifyParallelDBURLs []string
 
 var ingestVerifyRangeCmdOpts = []*support.ConfigOption{
 	&support.ConfigOption{
@@ -48,6 +52,23 @@ var ingestVerifyRangeCmdOpts = []*support.ConfigOption{
 		FlagDefault: false,
 		Usage:       "[optional] verifies state at the last ledger of the range when true",
 	},
+	&support.ConfigOption{
+		Name:        "resume",
+		ConfigKey:   &ingestVerifyResume,
+		OptType:     types.Bool,
+		Required:    false,
+		FlagDefault: false,
+		Usage:       "[optional] resumes an interrupted verification of the range from the last ledger ingested in the database",
+	},
+	&support.ConfigOption{
+		Name:           "parallel-db-urls",
+		ConfigKey:      &ingestVerifyParallelDBURLs,
+		OptType:        types.String,
+		Required:       false,
+		FlagDefault:    "",
+		CustomSetValue: setCommaSeparatedList,
+		Usage:          "[optional] comma-separated list of additional clean horizon databases. The range is split at checkpoints into one sub-range per database, verified in parallel (the state of every boundary checkpoint is verified with --verify-state)",
+	},
 	&support.ConfigOption{
 		Name:        "debug-server-port",
 		ConfigKey:   &ingestVerifyDebugServerPort,
@@ -83,14 +104,13 @@ var ingestVerifyRangeCmd = &cobra.Command{
 			}()
 		}
 
-		coreSession, err := db.Open("postgres", config.StellarCoreDatabaseURL)
-		if err != nil {
-			log.Fatalf("cannot open Core DB: %v", err)
-		}
-
-		horizonSession, err := db.Open("postgres", config.DatabaseURL)
-		if err != nil {
-			log.Fatalf("cannot open Horizon DB: %v", err)
+		var coreSession *db.Session
+		if !config.EnableCaptiveCoreIngestion {
+			var err error
+			coreSession, err = db.Open("postgres", config.StellarCoreDatabaseURL)
+			if err != nil {
+				log.Fatalf("cannot open Core DB: %v", err)
+			}
 		}
 
 		if !historyarchive.IsCheckpoint(ingestVerifyFrom) && ingestVerifyFrom != 1 {
@@ -101,25 +121,34 @@ var ingestVerifyRangeCmd = &cobra.Command{
 			log.Fatal("`--to` must be a checkpoint ledger when `--verify-state` is set.")
 		}
 
-		ingestConfig := expingest.Config{
-			CoreSession:       coreSession,
-			NetworkPassphrase: config.NetworkPassphrase,
-			HistorySession:    horizonSession,
-			HistoryArchiveURL: config.HistoryArchiveURLs[0],
-		}
-		if config.EnableCaptiveCoreIngestion {
-			ingestConfig.StellarCorePath = config.StellarCoreBinaryPath
-		}
-
-		system, err := expingest.NewSystem(ingestConfig)
-		if err != nil {
-			log.Fatal(err)
+		// every worker uses its own horizon database
+		var ingestConfigs []expingest.Config
+		for _, url := range append([]string{config.DatabaseURL}, ingestVerifyParallelDBURLs...) {
+			horizonSession, err := db.Open("postgres", url)
+			if err != nil {
+				log.Fatalf("cannot open Horizon DB: %v", err)
+			}
+
+			ingestConfig := expingest.Config{
+				CoreSession:       coreSession,
+				NetworkPassphrase: config.NetworkPassphrase,
+				HistorySession:    horizonSession,
+				HistoryArchiveURL: config.HistoryArchiveURLs[0],
+			}
+			if config.EnableCaptiveCoreIngestion {
+				ingestConfig.StellarCorePath = config.StellarCoreBinaryPath
+				ingestConfig.CaptiveCoreConfigAppendPath = config.CaptiveCoreConfigAppendPath
+				ingestConfig.CaptiveCoreStoragePath = config.CaptiveCoreStoragePath
+			}
+			ingestConfigs = append(ingestConfigs, ingestConfig)
 		}
 
-		err = system.VerifyRange(
+		err := expingest.VerifyRangeParallel(
+			ingestConfigs,
 			ingestVerifyFrom,
 			ingestVerifyTo,
 			ingestVerifyState,
+			ingestVerifyResume,
 		)
 		if err != nil {
 			log.Fatal(err)
@@ -162,9 +191,13 @@ var ingestStressTestCmd = &cobra.Command{
 
 		initRootConfig()
 
-		coreSession, err := db.Open("postgres", config.StellarCoreDatabaseURL)
-		if err != nil {
-			log.Fatalf("cannot open Core DB: %v", err)
+		var coreSession *db.Session
+		if !config.EnableCaptiveCoreIngestion {
+			var err error
+			coreSession, err = db.Open("postgres", config.StellarCoreDatabaseURL)
+			if err != nil {
+				log.Fatalf("cannot open Core DB: %v", err)
+			}
 		}
 
 		horizonSession, err := db.Open("postgres", config.DatabaseURL)
@@ -188,6 +221,8 @@ var ingestStressTestCmd = &cobra.Command{
 		}
 		if config.EnableCaptiveCoreIngestion {
 			ingestConfig.StellarCorePath = config.StellarCoreBinaryPath
+			ingestConfig.CaptiveCoreConfigAppendPath = config.CaptiveCoreConfigAppendPath
+			ingestConfig.CaptiveCoreStoragePath = config.CaptiveCoreStoragePath
 		}
 
 		system, err := expingest.NewSystem(ingestConfig)
@@ -207,6 +242,113 @@ var ingestStressTestCmd = &cobra.Command{
 	},
 }
 
+var dryRunFrom, dryRunTo uint32
+
+var dryRunCmdOpts = []*support.ConfigOption{
+	&support.ConfigOption{
+		Name:        "from",
+		ConfigKey:   &dryRunFrom,
+		OptType:     types.Uint32,
+		Required:    true,
+		FlagDefault: uint32(0),
+		Usage:       "first ledger of the range to ingest",
+	},
+	&support.ConfigOption{
+		Name:        "to",
+		ConfigKey:   &dryRunTo,
+		OptType:     types.Uint32,
+		Required:    true,
+		FlagDefault: uint32(0),
+		Usage:       "last ledger of the range to ingest",
+	},
+}
+
+var ingestDryRunCmd = &cobra.Command{
+	Use:   "dry-run",
+	Short: "[experimental] runs the history processors within a range and discards their writes, reporting timings and row counts.",
+	Long: "runs the history processors between X and Y sequence number (inclusive) in a database " +
+		"transaction which is rolled back, so that a new horizon version can be benchmarked against " +
+		"the production database without modifying it. The history rows of the range which were " +
+		"already ingested are deleted and inserted again within the transaction.",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, co := range dryRunCmdOpts {
+			co.Require()
+			co.SetValue()
+		}
+
+		initRootConfig()
+
+		var coreSession *db.Session
+		if !config.EnableCaptiveCoreIngestion {
+			var err error
+			coreSession, err = db.Open("postgres", config.StellarCoreDatabaseURL)
+			if err != nil {
+				log.Fatalf("cannot open Core DB: %v", err)
+			}
+		}
+
+		horizonSession, err := db.Open("postgres", config.DatabaseURL)
+		if err != nil {
+			log.Fatalf("cannot open Horizon DB: %v", err)
+		}
+
+		ingestConfig := expingest.Config{
+			CoreSession:       coreSession,
+			NetworkPassphrase: config.NetworkPassphrase,
+			HistorySession:    horizonSession,
+			HistoryArchiveURL: config.HistoryArchiveURLs[0],
+		}
+		if config.EnableCaptiveCoreIngestion {
+			ingestConfig.StellarCorePath = config.StellarCoreBinaryPath
+			ingestConfig.CaptiveCoreConfigAppendPath = config.CaptiveCoreConfigAppendPath
+			ingestConfig.CaptiveCoreStoragePath = config.CaptiveCoreStoragePath
+		}
+
+		system, err := expingest.NewSystem(ingestConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		report, err := system.DryRunRange(dryRunFrom, dryRunTo)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf(
+			"Ingested %d ledgers, %d transactions and %d operations in %.3fs (%.1fms per ledger), "+
+				"deleting the rows already ingested took %.3fs\n\n",
+			report.Ledgers,
+			report.Transactions,
+			report.Operations,
+			report.Duration.Seconds(),
+			report.Duration.Seconds()*1000/float64(report.Ledgers),
+			report.DeleteDuration.Seconds(),
+		)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "PROCESSOR\tMEAN TIME PER LEDGER (ms)")
+		var processors []string
+		for name := range report.ProcessorDurations {
+			processors = append(processors, name)
+		}
+		sort.Strings(processors)
+		for _, name := range processors {
+			fmt.Fprintf(w, "%s\t%.2f\n", name, report.ProcessorDurations[name].Seconds()*1000)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "TABLE\tROWS INSERTED")
+		var tables []string
+		for table := range report.InsertedRows {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		for _, table := range tables {
+			fmt.Fprintf(w, "%s\t%d\n", table, report.InsertedRows[table])
+		}
+		w.Flush()
+	},
+}
+
 func init() {
 	for _, co := range ingestVerifyRangeCmdOpts {
 		err := co.Init(ingestVerifyRangeCmd)
@@ -222,8 +364,15 @@ func init() {
 		}
 	}
 
+	for _, co := range dryRunCmdOpts {
+		err := co.Init(ingestDryRunCmd)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+
 	viper.BindPFlags(ingestVerifyRangeCmd.PersistentFlags())
 
 	rootCmd.AddCommand(ingestCmd)
-	ingestCmd.AddCommand(ingestVerifyRangeCmd, ingestStressTestCmd)
+	ingestCmd.AddCommand(ingestVerifyRangeCmd, ingestStressTestCmd, ingestDryRunCmd)
 }
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"go/types"
 	stdLog "log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,9 +16,11 @@ import (
 	"github.com/spf13/viper"
 	horizon "github.com/stellar/go/services/horizon/internal"
 	"github.com/stellar/go/services/horizon/internal/db2/schema"
+	"github.com/stellar/go/services/horizon/internal/maintenance"
 	apkg "github.com/stellar/go/support/app"
 	support "github.com/stellar/go/support/config"
 	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
 	"github.com/stellar/throttled"
 )
 
@@ -60,8 +64,8 @@ func pingDB(db *sql.DB) {
 	stdLog.Fatalf("failed to connect to horizon DB after %v attempts", maxDBPingAttempts)
 }
 
-func applyMigrations() {
-	db, err := sql.Open("postgres", config.DatabaseURL)
+func applyMigrations(databaseURL string) {
+	db, err := sql.Open("postgres", databaseURL)
 	if err != nil {
 		stdLog.Fatalf("could not connect to horizon db: %v", err)
 	}
@@ -77,9 +81,36 @@ func applyMigrations() {
 	}
 }
 
+// initEmptyDB applies all the migrations to the horizon database when it is
+// empty, like `horizon db init`. Databases which are already initialized are
+// left untouched, pending migrations are still reported by checkMigrations.
+func initEmptyDB(databaseURL string) {
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		stdLog.Fatalf("could not connect to horizon db: %v", err)
+	}
+	defer db.Close()
+	pingDB(db)
+
+	empty, err := schema.IsEmpty(db)
+	if err != nil {
+		stdLog.Fatalf("could not check if horizon db is empty: %v", err)
+	}
+	if !empty {
+		return
+	}
+
+	stdLog.Println("horizon db is empty, initializing it")
+	numMigrations, err := schema.Migrate(db, schema.MigrateUp, 0)
+	if err != nil {
+		stdLog.Fatalf("could not initialize horizon db: %v", err)
+	}
+	stdLog.Printf("successfully applied %v horizon migrations, the state will be ingested from the latest checkpoint\n", numMigrations)
+}
+
 // checkMigrations looks for necessary database migrations and fails with a descriptive error if migrations are needed.
-func checkMigrations() {
-	migrationsToApplyUp := schema.GetMigrationsUp(config.DatabaseURL)
+func checkMigrations(databaseURL string) {
+	migrationsToApplyUp := schema.GetMigrationsUp(databaseURL)
 	if len(migrationsToApplyUp) > 0 {
 		stdLog.Printf(`There are %v migrations to apply in the "up" direction.`, len(migrationsToApplyUp))
 		stdLog.Printf("The necessary migrations are: %v", migrationsToApplyUp)
@@ -87,7 +118,7 @@ func checkMigrations() {
 		os.Exit(1)
 	}
 
-	nMigrationsDown := schema.GetNumMigrationsDown(config.DatabaseURL)
+	nMigrationsDown := schema.GetNumMigrationsDown(databaseURL)
 	if nMigrationsDown > 0 {
 		stdLog.Printf("A database migration DOWN to an earlier version of the schema is required to run this version (%v) of Horizon. Consult the Changelog (https://github.com/stellar/go/blob/master/services/horizon/CHANGELOG.md) for more information.", apkg.Version())
 		stdLog.Printf("In order to migrate the database DOWN, using the HIGHEST version number of Horizon you have installed (not this binary), run \"horizon db migrate down %v\".", nMigrationsDown)
@@ -104,9 +135,46 @@ var dbURLConfigOption = &support.ConfigOption{
 	Usage:     "horizon postgres database to connect with",
 }
 
+var configFileConfigOption = &support.ConfigOption{
+	Name:     "config",
+	EnvVar:   "HORIZON_CONFIG",
+	OptType:  types.String,
+	Required: false,
+	Usage:    "TOML file setting the options which are set neither with a flag nor with an environment variable",
+}
+
+// loadConfigFile sets the options of the --config file, if any.
+func loadConfigFile() {
+	if err := readConfigFile(); err != nil {
+		stdLog.Fatalf("Invalid config: %v", err)
+	}
+}
+
+func readConfigFile() error {
+	configFileConfigOption.Bind()
+	path := viper.GetString(configFileConfigOption.Name)
+	if path == "" {
+		return nil
+	}
+	return configOpts.LoadFile(path)
+}
+
+// reloadConfig reads the --config file again and returns the new
+// configuration, of which horizon applies the settings which can be changed
+// while it is running.
+func reloadConfig() (horizon.Config, error) {
+	if err := readConfigFile(); err != nil {
+		return horizon.Config{}, err
+	}
+	configOpts.SetValues()
+	setMaxDBConnections()
+	return config, nil
+}
+
 // configOpts defines the complete flag configuration for horizon.
 // Add a new entry here to connect a new field in the horizon.Config struct
 var configOpts = support.ConfigOptions{
+	configFileConfigOption,
 	dbURLConfigOption,
 	&support.ConfigOption{
 		Name:        "stellar-core-binary-path",
@@ -126,13 +194,31 @@ var configOpts = support.ConfigOptions{
 		Usage:       "[experimental flag!] causes Horizon to ingest from a Stellar Core subprocess instead of a persistent Stellar Core database",
 		ConfigKey:   &config.EnableCaptiveCoreIngestion,
 	},
+	&support.ConfigOption{
+		Name:        "captive-core-config-append-path",
+		EnvVar:      "CAPTIVE_CORE_CONFIG_APPEND_PATH",
+		OptType:     types.String,
+		FlagDefault: "",
+		Required:    false,
+		Usage:       "path to a file appended to the config of the captive stellar-core, e.g. to set its log level",
+		ConfigKey:   &config.CaptiveCoreConfigAppendPath,
+	},
+	&support.ConfigOption{
+		Name:        "captive-core-storage-path",
+		EnvVar:      "CAPTIVE_CORE_STORAGE_PATH",
+		OptType:     types.String,
+		FlagDefault: "",
+		Required:    false,
+		Usage:       "directory in which the captive stellar-core stores its buckets and temporary files, defaults to the system temp directory",
+		ConfigKey:   &config.CaptiveCoreStoragePath,
+	},
 	&support.ConfigOption{
 		Name:      "stellar-core-db-url",
 		EnvVar:    "STELLAR_CORE_DATABASE_URL",
 		ConfigKey: &config.StellarCoreDatabaseURL,
 		OptType:   types.String,
-		Required:  true,
-		Usage:     "stellar-core postgres database to connect with",
+		Required:  false,
+		Usage:     "stellar-core postgres database to ingest from, only required when --ingest is set without --enable-captive-core-ingestion",
 	},
 	&support.ConfigOption{
 		Name:      "stellar-core-url",
@@ -204,6 +290,38 @@ var configOpts = support.ConfigOptions{
 		FlagDefault: 20,
 		Usage:       "max core database idle connections. may need to be set to the same value as core-db-max-open-connections when responses are slow and DB CPU is normal, because it may indicate that a lot of time is spent closing/opening idle connections. This can happen in case of high variance in number of requests. must be equal or lower than max open connections",
 	},
+	&support.ConfigOption{
+		Name:           "db-connection-max-lifetime",
+		ConfigKey:      &config.DBConnectionMaxLifetime,
+		OptType:        types.Int,
+		FlagDefault:    0,
+		CustomSetValue: support.SetDuration,
+		Usage:          "maximum amount of time (in seconds) a database connection is reused, 0 (default) reuses connections forever. may need to be set when connections go through a proxy or load balancer closing old connections",
+	},
+	&support.ConfigOption{
+		Name:           "db-statement-timeout",
+		ConfigKey:      &config.DBStatementTimeout,
+		OptType:        types.Int,
+		FlagDefault:    0,
+		CustomSetValue: support.SetDuration,
+		Usage:          "aborts the database queries of the API and other non-ingestion tasks running longer than this value (in seconds), 0 (default) disables the timeout",
+	},
+	&support.ConfigOption{
+		Name:           "read-replica-db-urls",
+		EnvVar:         "READ_REPLICA_DATABASE_URLS",
+		ConfigKey:      &config.ReadReplicaDatabaseURLs,
+		OptType:        types.String,
+		FlagDefault:    "",
+		CustomSetValue: setCommaSeparatedList,
+		Usage:          "comma-separated list of read-only replicas of the horizon postgres database serving the read queries of API requests, each with a connection pool of the size of the horizon database one",
+	},
+	&support.ConfigOption{
+		Name:        "read-replica-max-lag",
+		ConfigKey:   &config.ReadReplicaMaxLag,
+		OptType:     types.Uint,
+		FlagDefault: uint(1),
+		Usage:       "number of ledgers a read replica can lag behind the horizon database before queries are routed away from it",
+	},
 	&support.ConfigOption{
 		Name:           "sse-update-frequency",
 		ConfigKey:      &config.SSEUpdateFrequency,
@@ -220,6 +338,24 @@ var configOpts = support.ConfigOptions{
 		CustomSetValue: support.SetDuration,
 		Usage:          "defines the timeout of connection after which 504 response will be sent or stream will be closed, if Horizon is behind a load balancer with idle connection timeout, this should be set to a few seconds less that idle timeout",
 	},
+	&support.ConfigOption{
+		Name:           "shutdown-grace-period",
+		ConfigKey:      &config.ShutdownGracePeriod,
+		OptType:        types.Int,
+		FlagDefault:    10,
+		CustomSetValue: support.SetDuration,
+		Usage:          "time (in seconds) Horizon waits on shutdown for in-flight requests, including transaction submissions, to complete before closing their connections",
+	},
+	&support.ConfigOption{
+		Name:        "slow-request-threshold",
+		ConfigKey:   &config.SlowRequestThreshold,
+		OptType:     types.Int,
+		FlagDefault: 0,
+		CustomSetValue: func(co *support.ConfigOption) {
+			*(co.ConfigKey.(*time.Duration)) = time.Duration(viper.GetInt(co.Name)) * time.Millisecond
+		},
+		Usage: "duration (in milliseconds) above which non-streaming requests are logged with their slowest SQL queries, 0 to disable",
+	},
 	&support.ConfigOption{
 		Name:        "per-hour-rate-limit",
 		ConfigKey:   &config.RateQuota,
@@ -238,17 +374,91 @@ var configOpts = support.ConfigOptions{
 		},
 		Usage: "max count of requests allowed in a one hour period, by remote ip address",
 	},
-	&support.ConfigOption{ // Action needed in release: horizon-v2.0.0
-		// remove deprecated flag
-		Name:    "rate-limit-redis-key",
-		OptType: types.String,
-		Usage:   "deprecated, do not use",
+	&support.ConfigOption{
+		Name:      "rate-limit-config",
+		ConfigKey: &config.RouteRateQuotas,
+		OptType:   types.String,
+		CustomSetValue: func(co *support.ConfigOption) {
+			path := viper.GetString(co.Name)
+			if path == "" {
+				return
+			}
+
+			quotas, err := horizon.ReadRateLimitConfig(path)
+			if err != nil {
+				stdLog.Fatalf("Could not read rate-limit-config: %v", err)
+			}
+			*(co.ConfigKey.(*map[string]throttled.RateQuota)) = quotas
+		},
+		Usage: "path to a TOML file with separate rate limits for the submission, path_finding and reads route groups, e.g. [submission] per_hour = 600 burst = 10, groups which are not listed use per-hour-rate-limit",
+	},
+	&support.ConfigOption{
+		Name:        "rate-limit-redis-key",
+		ConfigKey:   &config.RateLimitRedisKey,
+		OptType:     types.String,
+		FlagDefault: "horizon-rate-limits",
+		Usage:       "prefix of the redis keys storing the rate limits, horizon instances sharing a redis server with the same prefix share their rate limits",
+	},
+	&support.ConfigOption{
+		Name:      "redis-url",
+		ConfigKey: &config.RedisURL,
+		OptType:   types.String,
+		Usage:     "redis server storing the rate limits shared by all the horizon instances using it, e.g. redis://:password@localhost:6379/0, rate limits are local to each instance when not set",
+	},
+	&support.ConfigOption{
+		Name:        "usage-accounting",
+		ConfigKey:   &config.UsageAccounting,
+		OptType:     types.Bool,
+		FlagDefault: false,
+		Required:    false,
+		Usage:       "aggregates the requests, bytes sent and endpoints used per API key or remote ip address in the horizon database, queryable on the admin port",
+	},
+	&support.ConfigOption{
+		Name:        "usage-accounting-key-header",
+		ConfigKey:   &config.UsageAccountingKeyHeader,
+		OptType:     types.String,
+		FlagDefault: "",
+		Required:    false,
+		Usage:       "request header holding the API key of the consumers (e.g. X-API-Key), requests without it are accounted to their remote ip address",
+	},
+	&support.ConfigOption{
+		Name:        "usage-accounting-retention-days",
+		ConfigKey:   &config.UsageAccountingRetentionDays,
+		OptType:     types.Uint,
+		FlagDefault: uint(30),
+		Required:    false,
+		Usage:       "number of days the usage recorded by --usage-accounting is kept",
+	},
+	&support.ConfigOption{
+		Name:           "cors-allowed-origins",
+		ConfigKey:      &config.CORSAllowedOrigins,
+		OptType:        types.String,
+		FlagDefault:    "*",
+		CustomSetValue: setCommaSeparatedList,
+		Usage:          "comma-separated list of origins allowed to make cross-origin requests, e.g. https://wallet.example.com, * allows any origin",
+	},
+	&support.ConfigOption{
+		Name:           "cors-allowed-headers",
+		ConfigKey:      &config.CORSAllowedHeaders,
+		OptType:        types.String,
+		FlagDefault:    "*",
+		CustomSetValue: setCommaSeparatedList,
+		Usage:          "comma-separated list of headers allowed in cross-origin requests, * allows any header",
 	},
-	&support.ConfigOption{ // Action needed in release: horizon-v2.0.0
-		// remove deprecated flag
-		Name:    "redis-url",
-		OptType: types.String,
-		Usage:   "deprecated, do not use",
+	&support.ConfigOption{
+		Name:        "cors-allow-credentials",
+		ConfigKey:   &config.CORSAllowCredentials,
+		OptType:     types.Bool,
+		FlagDefault: false,
+		Usage:       "allows cross-origin requests to include credentials like cookies, requires --cors-allowed-origins to list the allowed origins",
+	},
+	&support.ConfigOption{
+		Name:           "cors-max-age",
+		ConfigKey:      &config.CORSMaxAge,
+		OptType:        types.Int,
+		FlagDefault:    0,
+		CustomSetValue: support.SetDuration,
+		Usage:          "how long (in seconds) browsers can cache the response to a CORS preflight request, 0 leaves it to the browser",
 	},
 	&support.ConfigOption{
 		Name:           "friendbot-url",
@@ -284,6 +494,13 @@ var configOpts = support.ConfigOptions{
 		FlagDefault: uint(3),
 		Usage:       "the maximum number of assets on the path in `/paths` endpoint, warning: increasing this value will increase /paths response time",
 	},
+	&support.ConfigOption{
+		Name:        "path-cache-size",
+		ConfigKey:   &config.PathCacheSize,
+		OptType:     types.Uint,
+		FlagDefault: uint(0),
+		Usage:       "number of the most requested path finding queries whose paths are precomputed every time the order book changes, 0 disables the cache",
+	},
 	&support.ConfigOption{
 		Name:      "network-passphrase",
 		ConfigKey: &config.NetworkPassphrase,
@@ -344,6 +561,63 @@ var configOpts = support.ConfigOptions{
 		FlagDefault: uint(0),
 		Usage:       "the minimum number of ledgers to maintain within horizon's history tables.  0 signifies an unlimited number of ledgers will be retained",
 	},
+	&support.ConfigOption{
+		Name:        "history-retention-reap-batch-size",
+		ConfigKey:   &config.HistoryRetentionReapBatchSize,
+		OptType:     types.Uint,
+		FlagDefault: uint(100),
+		Usage:       "number of unretained ledgers deleted at once by the reaper, smaller batches lock the history tables for less time",
+	},
+	&support.ConfigOption{
+		Name:        "history-retention-reap-batch-delay",
+		ConfigKey:   &config.HistoryRetentionReapBatchDelay,
+		OptType:     types.Int,
+		FlagDefault: 100,
+		CustomSetValue: func(co *support.ConfigOption) {
+			*(co.ConfigKey.(*time.Duration)) = time.Duration(viper.GetInt(co.Name)) * time.Millisecond
+		},
+		Usage: "pause (in milliseconds) of the reaper between the deletion of two batches of unretained ledgers, limiting its load on the database",
+	},
+	&support.ConfigOption{
+		Name:      "db-maintenance-window",
+		ConfigKey: &config.DBMaintenanceWindow,
+		OptType:   types.String,
+		CustomSetValue: func(co *support.ConfigOption) {
+			value := viper.GetString(co.Name)
+			if value == "" {
+				return
+			}
+
+			window, err := maintenance.ParseWindow(value)
+			if err != nil {
+				stdLog.Fatal(err)
+			}
+			*(co.ConfigKey.(**maintenance.Window)) = &window
+		},
+		Usage: "daily low-traffic window (HH:MM-HH:MM, in UTC) during which the hot history tables are vacuumed and analyzed and the bloat of their indexes is checked, empty to disable",
+	},
+	&support.ConfigOption{
+		Name:        "db-maintenance-index-bloat-threshold",
+		ConfigKey:   &config.DBMaintenanceIndexBloatThreshold,
+		OptType:     types.String,
+		FlagDefault: "0.5",
+		CustomSetValue: func(co *support.ConfigOption) {
+			threshold, err := strconv.ParseFloat(viper.GetString(co.Name), 64)
+			if err != nil || threshold <= 0 || threshold >= 1 {
+				stdLog.Fatalf("Invalid %s: must be a number between 0 and 1", co.Name)
+			}
+			*(co.ConfigKey.(*float64)) = threshold
+		},
+		Usage: "estimated fraction of wasted space above which the database maintenance reports an index as bloated",
+	},
+	&support.ConfigOption{
+		Name:        "cdc-publish-url",
+		ConfigKey:   &config.CDCPublishURL,
+		OptType:     types.String,
+		FlagDefault: "",
+		Required:    false,
+		Usage:       "URL to which the history rows of every ingested ledger are POSTed as JSON (change-data-capture), empty to disable",
+	},
 	&support.ConfigOption{
 		Name:        "history-stale-threshold",
 		ConfigKey:   &config.StaleThreshold,
@@ -365,6 +639,118 @@ var configOpts = support.ConfigOptions{
 		FlagDefault: false,
 		Usage:       "ingestion system runs a verification routing to compare state in local database with history buckets, this can be disabled however it's not recommended",
 	},
+	&support.ConfigOption{
+		Name:        "ingest-state-verification-checkpoint-frequency",
+		ConfigKey:   &config.IngestStateVerificationCheckpointFrequency,
+		OptType:     types.Uint,
+		FlagDefault: uint(1),
+		Usage:       "runs the state verification every N checkpoints (every N*64 ledgers), higher values reduce the load of the verification on the database and history archives",
+	},
+	&support.ConfigOption{
+		Name:        "ingest-state-verification-entry-types",
+		ConfigKey:   &config.IngestStateVerificationEntryTypes,
+		OptType:     types.String,
+		FlagDefault: "",
+		CustomSetValue: func(co *support.ConfigOption) {
+			var entryTypes []xdr.LedgerEntryType
+			for _, name := range strings.Split(viper.GetString(co.Name), ",") {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				entryType, ok := ledgerEntryTypesByName[name]
+				if !ok {
+					stdLog.Fatalf("Invalid %s: unknown entry type %s", co.Name, name)
+				}
+				entryTypes = append(entryTypes, entryType)
+			}
+			*(co.ConfigKey.(*[]xdr.LedgerEntryType)) = entryTypes
+		},
+		Usage: "comma-separated list of ledger entry types (account, data, offer, trustline) checked by the state verification, all types are checked when empty",
+	},
+	&support.ConfigOption{
+		Name:           "history-allowlist-accounts",
+		ConfigKey:      &config.HistoryAllowlistAccounts,
+		OptType:        types.String,
+		FlagDefault:    "",
+		CustomSetValue: setCommaSeparatedList,
+		Usage:          "comma-separated list of accounts, when set (or when history-allowlist-assets is set) only the history of the transactions involving these accounts is ingested. The ledger state is always ingested in full",
+	},
+	&support.ConfigOption{
+		Name:        "history-allowlist-assets",
+		ConfigKey:   &config.HistoryAllowlistAssets,
+		OptType:     types.String,
+		FlagDefault: "",
+		CustomSetValue: func(co *support.ConfigOption) {
+			assets, err := xdr.BuildAssets(strings.Replace(viper.GetString(co.Name), " ", "", -1))
+			if err != nil {
+				stdLog.Fatalf("Invalid %s: %v", co.Name, err)
+			}
+			*(co.ConfigKey.(*[]xdr.Asset)) = assets
+		},
+		Usage: "comma-separated list of assets (code:issuer), when set (or when history-allowlist-accounts is set) only the history of the transactions changing trust lines or offers of these assets is ingested. The ledger state is always ingested in full",
+	},
+	&support.ConfigOption{
+		Name:        "state-only",
+		ConfigKey:   &config.StateOnly,
+		OptType:     types.Bool,
+		FlagDefault: false,
+		Usage:       "ingest and serve only the current ledger state (accounts, offers, order book, paths) without the history of transactions, operations, effects and trades. Transaction submission results are only available from ingesting instances",
+	},
+	&support.ConfigOption{
+		Name:        "tracing-otlp-url",
+		ConfigKey:   &config.TracingOTLPURL,
+		OptType:     types.String,
+		FlagDefault: "",
+		Required:    false,
+		Usage:       "OTLP/HTTP traces endpoint of an OpenTelemetry collector (e.g. http://localhost:4318/v1/traces) the spans of requests, queries and ingestion are exported to, empty to disable tracing",
+	},
+	&support.ConfigOption{
+		Name:        "tracing-sample-ratio",
+		ConfigKey:   &config.TracingSampleRatio,
+		OptType:     types.String,
+		FlagDefault: "0.01",
+		CustomSetValue: func(co *support.ConfigOption) {
+			ratio, err := strconv.ParseFloat(viper.GetString(co.Name), 64)
+			if err != nil || ratio < 0 || ratio > 1 {
+				stdLog.Fatalf("Invalid %s: must be a number between 0 and 1", co.Name)
+			}
+			*(co.ConfigKey.(*float64)) = ratio
+		},
+		Usage: "ratio of the requests and ledgers traced, requests with a sampled traceparent header are always traced",
+	},
+	&support.ConfigOption{
+		Name:      "networks",
+		ConfigKey: &config.Networks,
+		OptType:   types.String,
+		CustomSetValue: func(co *support.ConfigOption) {
+			value := viper.GetString(co.Name)
+			if value == "" {
+				return
+			}
+
+			var networks []horizon.NetworkConfig
+			if err := json.Unmarshal([]byte(value), &networks); err != nil {
+				stdLog.Fatalf("Could not parse networks: %v", err)
+			}
+			*(co.ConfigKey.(*[]horizon.NetworkConfig)) = networks
+		},
+		Usage: `JSON list of additional networks served under a path prefix, e.g. [{"path_prefix":"/testnet","db_url":"...","stellar_core_db_url":"...","stellar_core_url":"...","network_passphrase":"..."}]`,
+	},
+	&support.ConfigOption{
+		Name:        "check-memo-required",
+		ConfigKey:   &config.CheckMemoRequired,
+		OptType:     types.Bool,
+		FlagDefault: false,
+		Usage:       "rejects submitted transactions without a memo sending funds to accounts which require one with the config.memo_required data entry (SEP-29)",
+	},
+	&support.ConfigOption{
+		Name:        "reject-low-fee-transactions",
+		ConfigKey:   &config.RejectLowFeeTransactions,
+		OptType:     types.Bool,
+		FlagDefault: false,
+		Usage:       "rejects submitted transactions offering a fee per operation below the minimum fee charged in the recent ledgers, instead of letting them time out",
+	},
 	&support.ConfigOption{
 		Name:        "apply-migrations",
 		ConfigKey:   &config.ApplyMigrations,
@@ -373,6 +759,14 @@ var configOpts = support.ConfigOptions{
 		Required:    false,
 		Usage:       "applies pending migrations before starting horizon",
 	},
+	&support.ConfigOption{
+		Name:        "auto-init-db",
+		ConfigKey:   &config.AutoInitDB,
+		OptType:     types.Bool,
+		FlagDefault: false,
+		Required:    false,
+		Usage:       "initializes the horizon database before starting horizon when it is empty, instead of requiring `horizon db init`. The state is then ingested from the latest checkpoint, requires --ingest",
+	},
 }
 
 func init() {
@@ -384,20 +778,44 @@ func init() {
 
 func initApp() *horizon.App {
 	initRootConfig()
-	return horizon.NewApp(config)
+	app := horizon.NewApp(config)
+	app.SetConfigLoader(reloadConfig)
+	return app
 }
 
 func initRootConfig() {
-	// Verify required options and load the config struct
+	// Verify required options and load the config struct, flags and
+	// environment variables override the config file
+	loadConfigFile()
 	configOpts.Require()
 	configOpts.SetValues()
 
+	validateNetworks()
+
+	databaseURLs := []string{config.DatabaseURL}
+	for _, network := range config.Networks {
+		databaseURLs = append(databaseURLs, network.DatabaseURL)
+	}
+
+	if config.AutoInitDB {
+		if !config.Ingest {
+			stdLog.Fatalf("--auto-init-db requires --ingest")
+		}
+		for _, databaseURL := range databaseURLs {
+			initEmptyDB(databaseURL)
+		}
+	}
+
 	if config.ApplyMigrations {
-		applyMigrations()
+		for _, databaseURL := range databaseURLs {
+			applyMigrations(databaseURL)
+		}
 	}
 
 	// Migrations should be checked as early as possible
-	checkMigrations()
+	for _, databaseURL := range databaseURLs {
+		checkMigrations(databaseURL)
+	}
 
 	// Validate options that should be provided together
 	validateBothOrNeither("tls-cert", "tls-key")
@@ -408,10 +826,35 @@ func initRootConfig() {
 		stdLog.Fatalf("--history-archive-urls must be set when --ingest is set")
 	}
 
+	if config.CORSAllowCredentials {
+		for _, origin := range config.CORSAllowedOrigins {
+			if origin == "*" {
+				stdLog.Fatalf("--cors-allowed-origins must list the allowed origins when --cors-allow-credentials is set")
+			}
+		}
+	}
+
+	if config.StateOnly && (len(config.HistoryAllowlistAccounts) > 0 || len(config.HistoryAllowlistAssets) > 0) {
+		stdLog.Fatalf("--history-allowlist-accounts and --history-allowlist-assets cannot be used with --state-only")
+	}
+
+	if config.UsageAccounting && config.UsageAccountingRetentionDays == 0 {
+		stdLog.Fatalf("--usage-accounting-retention-days must be positive")
+	}
+
 	if config.EnableCaptiveCoreIngestion && config.StellarCoreBinaryPath == "" {
 		stdLog.Fatalf("--stellar-core-binary-path must be set when --enable-captive-core-ingestion is set")
 	}
 
+	if config.UsesStellarCoreDB() && config.StellarCoreDatabaseURL == "" {
+		stdLog.Fatalf("--stellar-core-db-url must be set when --ingest is set, unless --enable-captive-core-ingestion is set")
+	}
+
+	if !config.EnableCaptiveCoreIngestion &&
+		(config.CaptiveCoreConfigAppendPath != "" || config.CaptiveCoreStoragePath != "") {
+		stdLog.Fatalf("--captive-core-config-append-path and --captive-core-storage-path require --enable-captive-core-ingestion")
+	}
+
 	// Configure log file
 	if config.LogFile != "" {
 		logFile, err := os.OpenFile(config.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -425,8 +868,12 @@ func initRootConfig() {
 	// Configure log level
 	log.DefaultLogger.Logger.SetLevel(config.LogLevel)
 
-	// Configure DB params. When config.MaxDBConnections is set, set other
-	// DB params to that value for backward compatibility.
+	setMaxDBConnections()
+}
+
+// setMaxDBConnections configures DB params. When config.MaxDBConnections is
+// set, set other DB params to that value for backward compatibility.
+func setMaxDBConnections() {
 	if config.MaxDBConnections != 0 {
 		config.HorizonDBMaxOpenConnections = config.MaxDBConnections
 		config.HorizonDBMaxIdleConnections = config.MaxDBConnections
@@ -435,6 +882,58 @@ func initRootConfig() {
 	}
 }
 
+// ledgerEntryTypesByName are the ledger entry types which can be passed to
+// --ingest-state-verification-entry-types.
+var ledgerEntryTypesByName = map[string]xdr.LedgerEntryType{
+	"account":   xdr.LedgerEntryTypeAccount,
+	"data":      xdr.LedgerEntryTypeData,
+	"offer":     xdr.LedgerEntryTypeOffer,
+	"trustline": xdr.LedgerEntryTypeTrustline,
+}
+
+// setCommaSeparatedList sets a []string config value from a comma-separated
+// list, ignoring blank elements.
+func setCommaSeparatedList(co *support.ConfigOption) {
+	var list []string
+	for _, element := range strings.Split(viper.GetString(co.Name), ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			list = append(list, element)
+		}
+	}
+	*(co.ConfigKey.(*[]string)) = list
+}
+
+// validateNetworks ensures that every additional network is served under a
+// distinct path prefix and has its own databases and passphrase.
+func validateNetworks() {
+	prefixes := map[string]bool{}
+	for i, network := range config.Networks {
+		if !strings.HasPrefix(network.PathPrefix, "/") || network.PathPrefix == "/" {
+			stdLog.Fatalf("Invalid config: networks[%d].path_prefix must start with / and must not be the root path", i)
+		}
+		if strings.HasSuffix(network.PathPrefix, "/") {
+			stdLog.Fatalf("Invalid config: networks[%d].path_prefix must not end with /", i)
+		}
+		if prefixes[network.PathPrefix] {
+			stdLog.Fatalf("Invalid config: networks[%d].path_prefix %s is used more than once", i, network.PathPrefix)
+		}
+		prefixes[network.PathPrefix] = true
+
+		if network.DatabaseURL == "" || network.StellarCoreURL == "" {
+			stdLog.Fatalf("Invalid config: networks[%d] requires db_url and stellar_core_url", i)
+		}
+		if config.ForNetwork(network).UsesStellarCoreDB() && network.StellarCoreDatabaseURL == "" {
+			stdLog.Fatalf("Invalid config: networks[%d].stellar_core_db_url must be set when ingest is set, unless captive core ingestion is enabled", i)
+		}
+		if network.NetworkPassphrase == "" {
+			stdLog.Fatalf("Invalid config: networks[%d].network_passphrase is blank", i)
+		}
+		if network.Ingest && len(network.HistoryArchiveURLs) == 0 {
+			stdLog.Fatalf("Invalid config: networks[%d].history_archive_urls must be set when ingest is set", i)
+		}
+	}
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -11,7 +11,6 @@ import (
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/httpx"
-	"github.com/stellar/go/services/horizon/internal/ledger"
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/render/sse"
 	"github.com/stellar/go/services/horizon/internal/toid"
@@ -48,7 +47,7 @@ func (action *Action) HistoryQ() *history.Q {
 func (action *Action) Prepare(w http.ResponseWriter, r *http.Request) {
 	base := &action.Base
 	action.App = AppFromContext(r.Context())
-	base.Prepare(w, r, action.App.ctx, action.App.config.SSEUpdateFrequency)
+	base.Prepare(w, r, action.App.streamsCtx, action.App.config.SSEUpdateFrequency)
 	if action.R.Context() != nil {
 		action.Log = log.Ctx(action.R.Context())
 	} else {
@@ -109,7 +108,7 @@ func (action *Action) ValidateCursorWithinHistory() {
 		return
 	}
 
-	elder := toid.New(ledger.CurrentState().HistoryElder, 0, 0)
+	elder := toid.New(action.App.ledgerState.CurrentState().HistoryElder, 0, 0)
 
 	if cursor <= elder.ToInt64() {
 		action.Err = &hProblem.BeforeHistory
@@ -123,12 +122,8 @@ func (action *Action) EnsureHistoryFreshness() {
 	}
 
 	if action.App.IsHistoryStale() {
-		ls := ledger.CurrentState()
-		err := hProblem.StaleHistory
-		err.Extras = map[string]interface{}{
-			"history_latest_ledger": ls.HistoryLatest,
-			"core_latest_ledger":    ls.CoreLatest,
-		}
+		ls := action.App.ledgerState.CurrentState()
+		err := hProblem.NewStaleHistory(ls.HistoryLatest, ls.CoreLatest)
 		action.Err = &err
 	}
 }
@@ -154,6 +149,7 @@ type indexActionQueryParams struct {
 	LedgerID         int32
 	PagingParams     db2.PageQuery
 	IncludeFailedTxs bool
+	ClosedWithin     history.TimeRange
 	Signer           string
 }
 
@@ -194,7 +190,7 @@ func (w *web) getTransactionPage(ctx context.Context, qp *indexActionQueryParams
 		return nil, errors.Wrap(err, "getting horizon db session")
 	}
 
-	return actions.TransactionPage(ctx, &history.Q{horizonSession}, qp.AccountID, qp.LedgerID, qp.IncludeFailedTxs, qp.PagingParams)
+	return actions.TransactionPage(ctx, &history.Q{horizonSession}, qp.AccountID, qp.LedgerID, qp.IncludeFailedTxs, qp.ClosedWithin, qp.PagingParams)
 }
 
 // getTransactionResource returns a single transaction resource.
@@ -215,5 +211,5 @@ func (w *web) streamTransactions(ctx context.Context, s *sse.Stream, qp *indexAc
 	}
 
 	return actions.StreamTransactions(ctx, s, &history.Q{horizonSession},
-		qp.AccountID, qp.LedgerID, qp.IncludeFailedTxs, qp.PagingParams)
+		qp.AccountID, qp.LedgerID, qp.IncludeFailedTxs, qp.ClosedWithin, qp.PagingParams)
 }
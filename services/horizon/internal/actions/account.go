@@ -69,6 +69,7 @@ func AccountInfo(ctx context.Context, hq *history.Q, addr string) (*protocol.Acc
 type AccountsQuery struct {
 	Signer      string `schema:"signer" valid:"accountID,optional"`
 	AssetFilter string `schema:"asset" valid:"asset,optional"`
+	HomeDomain  string `schema:"home_domain" valid:"-"`
 }
 
 // URITemplate returns a rfc6570 URI template the query struct
@@ -76,11 +77,15 @@ func (q AccountsQuery) URITemplate() string {
 	return "/accounts{?" + strings.Join(GetURIParams(&q, true), ",") + "}"
 }
 
+// maxHomeDomainLength is the maximum length of the home domain of an account,
+// see the `String32` type of `AccountEntry.homeDomain`.
+const maxHomeDomainLength = 32
+
 var invalidAccountsParams = problem.P{
 	Type:   "invalid_accounts_params",
 	Title:  "Invalid Accounts Parameters",
 	Status: http.StatusBadRequest,
-	Detail: "A filter is required. Please ensure that you are including a signer or an asset.",
+	Detail: "A filter is required. Please ensure that you are including a signer, an asset or a home domain.",
 }
 
 // Validate runs custom validations.
@@ -92,7 +97,14 @@ func (q AccountsQuery) Validate() error {
 		)
 	}
 
-	if len(q.Signer) == 0 && q.Asset() == nil {
+	if len(q.HomeDomain) > maxHomeDomainLength {
+		return problem.MakeInvalidFieldProblem(
+			"home_domain",
+			errors.Errorf("home domain must not be longer than %d characters", maxHomeDomainLength),
+		)
+	}
+
+	if len(q.Signer) == 0 && q.Asset() == nil && len(q.HomeDomain) == 0 {
 		return invalidAccountsParams
 	}
 
@@ -103,6 +115,13 @@ func (q AccountsQuery) Validate() error {
 		)
 	}
 
+	if len(q.HomeDomain) > 0 && (len(q.Signer) > 0 || q.Asset() != nil) {
+		return problem.MakeInvalidFieldProblem(
+			"home_domain",
+			errors.New("you can't filter by home domain and signer or asset at the same time"),
+		)
+	}
+
 	return nil
 }
 
@@ -123,7 +142,8 @@ type GetAccountsHandler struct {
 }
 
 // GetResourcePage returns a page containing the account records that have
-// `signer` as a signer or have a trustline to the given asset.
+// `signer` as a signer, have a trustline to the given asset or have the given
+// home domain.
 func (handler GetAccountsHandler) GetResourcePage(
 	w HeaderWriter,
 	r *http.Request,
@@ -152,6 +172,11 @@ func (handler GetAccountsHandler) GetResourcePage(
 		if err != nil {
 			return nil, errors.Wrap(err, "loading account records")
 		}
+	} else if len(qp.HomeDomain) > 0 {
+		records, err = historyQ.AccountsForHomeDomain(qp.HomeDomain, pq)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading account records")
+		}
 	} else {
 		records, err = historyQ.AccountsForAsset(*qp.Asset(), pq)
 		if err != nil {
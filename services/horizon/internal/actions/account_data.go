@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/support/render/hal"
+)
+
+// AccountDataQuery query struct for the account data end-point
+type AccountDataQuery struct {
+	AccountID string `schema:"account_id" valid:"accountID,required"`
+}
+
+// GetAccountDataHandler is the action handler for the
+// `/accounts/{account_id}/data` endpoint.
+type GetAccountDataHandler struct {
+}
+
+// GetResourcePage returns a page of the data entries of an account, ordered
+// by name.
+func (handler GetAccountDataHandler) GetResourcePage(
+	w HeaderWriter,
+	r *http.Request,
+) ([]hal.Pageable, error) {
+	ctx := r.Context()
+	qp := AccountDataQuery{}
+	err := GetParams(&qp, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// data entries are paged by name
+	pq, err := GetPageQuery(r, DisableCursorValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	historyQ, err := HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := historyQ.GetAccountDataPage(qp.AccountID, pq)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []hal.Pageable
+	for _, record := range records {
+		var entry horizon.AccountDataEntry
+		resourceadapter.PopulateAccountDataEntry(ctx, &entry, record)
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetAccountDataHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetAccountDataHandler{}
+
+	for _, entry := range []xdr.DataEntry{
+		{AccountId: seller, DataName: "b", DataValue: xdr.DataValue("text")},
+		{AccountId: seller, DataName: "a", DataValue: xdr.DataValue([]byte{0xff, 0xfe})},
+		{AccountId: seller, DataName: "c", DataValue: xdr.DataValue("more text")},
+		{AccountId: issuer, DataName: "a", DataValue: xdr.DataValue("other account")},
+	} {
+		_, err := q.InsertAccountData(entry, 3)
+		tt.Assert.NoError(err)
+	}
+
+	getPage := func(queryParams map[string]string) []horizon.AccountDataEntry {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				queryParams,
+				map[string]string{"account_id": seller.Address()},
+				q.Session,
+			),
+		)
+		tt.Assert.NoError(err)
+
+		var entries []horizon.AccountDataEntry
+		for _, record := range records {
+			entries = append(entries, record.(horizon.AccountDataEntry))
+		}
+		return entries
+	}
+
+	entries := getPage(map[string]string{})
+	if tt.Assert.Len(entries, 3) {
+		tt.Assert.Equal("a", entries[0].Name)
+		tt.Assert.Equal("//4=", entries[0].Value)
+		tt.Assert.Nil(entries[0].DecodedValue)
+		tt.Assert.Equal("a", entries[0].PagingToken())
+
+		tt.Assert.Equal("b", entries[1].Name)
+		tt.Assert.Equal("dGV4dA==", entries[1].Value)
+		if tt.Assert.NotNil(entries[1].DecodedValue) {
+			tt.Assert.Equal("text", *entries[1].DecodedValue)
+		}
+		tt.Assert.Equal(uint32(3), entries[1].LastModifiedLedger)
+	}
+
+	entries = getPage(map[string]string{"cursor": "a", "limit": "1"})
+	if tt.Assert.Len(entries, 1) {
+		tt.Assert.Equal("b", entries[0].Name)
+	}
+
+	entries = getPage(map[string]string{"cursor": "c", "order": "desc"})
+	if tt.Assert.Len(entries, 2) {
+		tt.Assert.Equal("b", entries[0].Name)
+		tt.Assert.Equal("a", entries[1].Name)
+	}
+
+	_, err := handler.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{}, q.Session),
+	)
+	tt.Assert.Error(err)
+}
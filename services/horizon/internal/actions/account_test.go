@@ -2,6 +2,7 @@ package actions
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -436,6 +437,48 @@ func TestGetAccountsHandlerPageResultsByAsset(t *testing.T) {
 	tt.Assert.True(ok)
 }
 
+func TestGetAccountsHandlerPageResultsByHomeDomain(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := &GetAccountsHandler{}
+
+	batch := q.NewAccountsBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(account1, 1234))
+	tt.Assert.NoError(batch.Add(account2, 1234))
+	tt.Assert.NoError(batch.Exec())
+
+	for _, tc := range []struct {
+		homeDomain string
+		expected   []string
+	}{
+		{"stellar.org", []string{accountOne}},
+		{"meridian.stellar.org", []string{accountTwo}},
+		{"example.com", []string{}},
+	} {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{"home_domain": tc.homeDomain},
+				map[string]string{},
+				q.Session,
+			),
+		)
+		tt.Assert.NoError(err)
+
+		accountIDs := []string{}
+		for _, record := range records {
+			account := record.(protocol.Account)
+			tt.Assert.Equal(tc.homeDomain, account.HomeDomain)
+			accountIDs = append(accountIDs, account.AccountID)
+		}
+		tt.Assert.Equal(tc.expected, accountIDs)
+	}
+}
+
 func TestGetAccountsHandlerInvalidParams(t *testing.T) {
 	testCases := []struct {
 		desc                    string
@@ -457,6 +500,23 @@ func TestGetAccountsHandlerInvalidParams(t *testing.T) {
 			expectedInvalidField: "signer",
 			expectedErr:          "you can't filter by signer and asset at the same time",
 		},
+		{
+			desc: "home domain and signer",
+			params: map[string]string{
+				"home_domain": "stellar.org",
+				"signer":      accountOne,
+			},
+			expectedInvalidField: "home_domain",
+			expectedErr:          "you can't filter by home domain and signer or asset at the same time",
+		},
+		{
+			desc: "home domain too long",
+			params: map[string]string{
+				"home_domain": strings.Repeat("a", 33),
+			},
+			expectedInvalidField: "home_domain",
+			expectedErr:          "home domain must not be longer than 32 characters",
+		},
 		{
 			desc: "filtering by native asset",
 			params: map[string]string{
@@ -511,7 +571,7 @@ func TestGetAccountsHandlerInvalidParams(t *testing.T) {
 
 func TestAccountQueryURLTemplate(t *testing.T) {
 	tt := assert.New(t)
-	expected := "/accounts{?signer,asset,cursor,limit,order}"
+	expected := "/accounts{?signer,asset,home_domain,cursor,limit,order}"
 	accountsQuery := AccountsQuery{}
 	tt.Equal(expected, accountsQuery.URITemplate())
 }
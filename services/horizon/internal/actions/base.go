@@ -72,7 +72,7 @@ func (base *Base) Execute(action interface{}) {
 
 		var oldHash [32]byte
 		for {
-			lastLedgerState := ledger.CurrentState()
+			lastLedgerState := ledger.FromContext(ctx).CurrentState()
 
 			// Rate limit the request if it's a call to stream since it queries the DB every second. See
 			// https://github.com/stellar/go/issues/715 for more details.
@@ -146,7 +146,7 @@ func (base *Base) Execute(action interface{}) {
 						return
 					}
 
-					currentLedgerState := ledger.CurrentState()
+					currentLedgerState := ledger.FromContext(ctx).CurrentState()
 					if currentLedgerState.HistoryLatest >= lastLedgerState.HistoryLatest+1 {
 						newLedgers <- true
 						return
@@ -3,6 +3,7 @@ package actions
 import (
 	"net/http"
 
+	"github.com/stellar/go/protocols/horizon/effects"
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
@@ -13,10 +14,30 @@ import (
 
 // EffectsQuery query struct for effects end-points
 type EffectsQuery struct {
-	AccountID   string `schema:"account_id" valid:"accountID,optional"`
-	OperationID uint64 `schema:"op_id" valid:"-"`
-	TxHash      string `schema:"tx_id" valid:"transactionHash,optional"`
-	LedgerID    uint32 `schema:"ledger_id" valid:"-"`
+	AccountID   string   `schema:"account_id" valid:"accountID,optional"`
+	OperationID uint64   `schema:"op_id" valid:"-"`
+	TxHash      string   `schema:"tx_id" valid:"transactionHash,optional"`
+	LedgerID    uint32   `schema:"ledger_id" valid:"-"`
+	Types       []string `schema:"type" valid:"-"`
+}
+
+// effectTypesByName maps the names of the effect types, as rendered in the
+// effect resources, to their ids.
+var effectTypesByName = func() map[string]history.EffectType {
+	types := map[string]history.EffectType{}
+	for typ, name := range effects.EffectTypeNames {
+		types[name] = history.EffectType(typ)
+	}
+	return types
+}()
+
+// EffectTypes returns the ids of the effect types given in the type param.
+func (qp EffectsQuery) EffectTypes() []history.EffectType {
+	types := make([]history.EffectType, 0, len(qp.Types))
+	for _, name := range qp.Types {
+		types = append(types, effectTypesByName[name])
+	}
+	return types
 }
 
 // Validate runs extra validations on query parameters
@@ -38,6 +59,15 @@ func (qp EffectsQuery) Validate() error {
 			errors.New("Use a single filter for effects, you can only use one of account_id, op_id, tx_id or ledger_id"),
 		)
 	}
+
+	for _, name := range qp.Types {
+		if _, ok := effectTypesByName[name]; !ok {
+			return problem.MakeInvalidFieldProblem(
+				"type",
+				errors.Errorf("unknown effect type %q", name),
+			)
+		}
+	}
 	return nil
 }
 
@@ -49,7 +79,7 @@ func (handler GetEffectsHandler) GetResourcePage(w HeaderWriter, r *http.Request
 		return nil, err
 	}
 
-	err = ValidateCursorWithinHistory(pq)
+	err = ValidateCursorWithinHistory(r.Context(), pq)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +95,7 @@ func (handler GetEffectsHandler) GetResourcePage(w HeaderWriter, r *http.Request
 		return nil, err
 	}
 
-	records, err := loadEffectRecords(historyQ, qp.AccountID, int64(qp.OperationID), qp.TxHash, qp.LedgerID, pq)
+	records, err := loadEffectRecords(historyQ, qp.AccountID, int64(qp.OperationID), qp.TxHash, qp.LedgerID, qp.EffectTypes(), pq)
 	if err != nil {
 		return nil, errors.Wrap(err, "loading transaction records")
 	}
@@ -88,7 +118,7 @@ func (handler GetEffectsHandler) GetResourcePage(w HeaderWriter, r *http.Request
 }
 
 func loadEffectRecords(hq *history.Q, accountID string, operationID int64, transactionHash string, ledgerID uint32,
-	pq db2.PageQuery) ([]history.Effect, error) {
+	types []history.EffectType, pq db2.PageQuery) ([]history.Effect, error) {
 	effects := hq.Effects()
 
 	switch {
@@ -102,6 +132,10 @@ func loadEffectRecords(hq *history.Q, accountID string, operationID int64, trans
 		effects.ForTransaction(transactionHash)
 	}
 
+	if len(types) > 0 {
+		effects.OfType(types...)
+	}
+
 	var result []history.Effect
 	err := effects.Page(pq).Select(&result)
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/support/http/httptest"
 	"github.com/stellar/go/support/render/problem"
 )
@@ -36,3 +37,34 @@ func TestEffectsQuery_BadOperationID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, called)
 }
+
+func TestEffectsQuery_Types(t *testing.T) {
+	called := false
+	s := httptest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		qp := EffectsQuery{}
+		err := GetParams(&qp, r)
+		if r.URL.Query().Get("type") == "foo" {
+			p, ok := err.(*problem.P)
+			if assert.True(t, ok) {
+				assert.Equal(t, 400, p.Status)
+				assert.Equal(t, "type", p.Extras["invalid_field"])
+			}
+		} else if assert.NoError(t, err) {
+			assert.Equal(t, []history.EffectType{
+				history.EffectTrustlineCreated,
+				history.EffectAccountCredited,
+			}, qp.EffectTypes())
+		}
+		called = true
+	}))
+	defer s.Close()
+
+	_, err := http.Get(s.URL + "/?type=trustline_created&type=account_credited")
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	called = false
+	_, err = http.Get(s.URL + "/?type=foo")
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/toid"
-	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/support/time"
@@ -86,7 +85,7 @@ func GetCursor(r *http.Request, name string) (string, error) {
 	}
 
 	if cursor == "now" {
-		tid := toid.AfterLedger(ledger.CurrentState().HistoryLatest)
+		tid := toid.AfterLedger(ledger.FromContext(r.Context()).CurrentState().HistoryLatest)
 		cursor = tid.String()
 	}
 
@@ -382,12 +381,13 @@ func (base *Base) GetAddress(name string, opts ...Opt) (result string) {
 		return result
 	}
 
-	_, err := strkey.Decode(strkey.VersionByteAccountID, result)
+	address, err := ResolveAccountAddress(result)
 	if err != nil {
 		base.SetInvalidField(name, errors.New("invalid address"))
+		return result
 	}
 
-	return result
+	return address
 }
 
 // GetTransactionID retireves a transaction identifier by attempting to decode an hex-encoded,
@@ -415,15 +415,15 @@ func GetAccountID(r *http.Request, name string) (xdr.AccountId, error) {
 		return xdr.AccountId{}, err
 	}
 
-	result, err := xdr.AddressToAccountId(value)
+	muxed, err := xdr.AddressToMuxedAccount(value)
 	if err != nil {
-		return result, problem.MakeInvalidFieldProblem(
+		return xdr.AccountId{}, problem.MakeInvalidFieldProblem(
 			name,
 			errors.New("invalid address"),
 		)
 	}
 
-	return result, nil
+	return muxed.ToAccountId(), nil
 }
 
 // GetAccountID retireves an xdr.AccountID by attempting to decode a stellar
@@ -758,6 +758,8 @@ func GetParams(dst interface{}, r *http.Request) error {
 		return err
 	}
 
+	resolveMuxedAccountFields(reflect.ValueOf(dst).Elem())
+
 	if v, ok := dst.(Validateable); ok {
 		if err := v.Validate(); err != nil {
 			return err
@@ -767,6 +769,50 @@ func GetParams(dst interface{}, r *http.Request) error {
 	return nil
 }
 
+// resolveMuxedAccountFields replaces the muxed (M...) addresses found in the
+// fields validated as `accountID` with the address of the underlying account
+// so query structs can be used as filters without further processing.
+func resolveMuxedAccountFields(v reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	qt := v.Type()
+	for i := 0; i < qt.NumField(); i++ {
+		f := qt.Field(i)
+		if f.Type.Kind() == reflect.Struct {
+			resolveMuxedAccountFields(v.Field(i))
+			continue
+		}
+
+		valid := strings.Split(f.Tag.Get("valid"), ",")
+		if valid[0] != "accountID" || f.Type.Kind() != reflect.String {
+			continue
+		}
+
+		field := v.Field(i)
+		if !field.CanSet() || field.String() == "" {
+			continue
+		}
+		if address, err := ResolveAccountAddress(field.String()); err == nil {
+			field.SetString(address)
+		}
+	}
+}
+
+// ResolveAccountAddress returns the address of the account backing the
+// provided address. Muxed (M...) addresses are resolved to the G... address of
+// the underlying account, account addresses are returned unchanged.
+func ResolveAccountAddress(address string) (string, error) {
+	muxed, err := xdr.AddressToMuxedAccount(address)
+	if err != nil {
+		return "", err
+	}
+
+	aid := muxed.ToAccountId()
+	return aid.GetAddress()
+}
+
 func getSchemaTag(params interface{}, field string) string {
 	v := reflect.ValueOf(params).Elem()
 	qt := v.Type()
@@ -877,7 +923,7 @@ func ValidateAssetParams(aType, code, issuer, prefix string) error {
 // ValidateCursorWithinHistory compares the requested page of data against the
 // ledger state of the history database.  In the event that the cursor is
 // guaranteed to return no results, we return a 410 GONE http response.
-func ValidateCursorWithinHistory(pq db2.PageQuery) error {
+func ValidateCursorWithinHistory(ctx context.Context, pq db2.PageQuery) error {
 	// an ascending query should never return a gone response:  An ascending query
 	// prior to known history should return results at the beginning of history,
 	// and an ascending query beyond the end of history should not error out but
@@ -902,7 +948,7 @@ func ValidateCursorWithinHistory(pq db2.PageQuery) error {
 		return problem.MakeInvalidFieldProblem("cursor", errors.New("invalid value"))
 	}
 
-	elder := toid.New(ledger.CurrentState().HistoryElder, 0, 0)
+	elder := toid.New(ledger.FromContext(ctx).CurrentState().HistoryElder, 0, 0)
 
 	if cursor <= elder.ToInt64() {
 		return &hProblem.BeforeHistory
@@ -243,7 +243,7 @@ func TestValidateCursorWithinHistory(t *testing.T) {
 		t.Run(fmt.Sprintf("cursor: %s", tc.cursor), func(t *testing.T) {
 			pq, err := db2.NewPageQuery(tc.cursor, false, tc.order, 10)
 			tt.NoError(err)
-			err = ValidateCursorWithinHistory(pq)
+			err = ValidateCursorWithinHistory(context.Background(), pq)
 
 			if tc.valid {
 				tt.NoError(err)
@@ -762,6 +762,41 @@ func (pv ParamsValidator) Validate() error {
 	)
 }
 
+func TestGetParamsResolvesMuxedAccounts(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+
+	type QueryParams struct {
+		Account string `schema:"account_id" valid:"accountID"`
+		Signer  string `schema:"signer" valid:"accountID,optional"`
+		Seller  string `schema:"seller" valid:"-"`
+	}
+
+	muxed := "MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK"
+	urlParams := map[string]string{"account_id": muxed}
+
+	r := makeAction("/transactions?signer="+muxed+"&seller="+muxed, urlParams).R
+	qp := QueryParams{}
+	tt.Assert.NoError(GetParams(&qp, r))
+	tt.Assert.Equal("GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", qp.Account)
+	tt.Assert.Equal("GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", qp.Signer)
+	// fields not validated as accountID are left untouched
+	tt.Assert.Equal(muxed, qp.Seller)
+}
+
+func TestResolveAccountAddress(t *testing.T) {
+	address, err := ResolveAccountAddress("MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK")
+	assert.NoError(t, err)
+	assert.Equal(t, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", address)
+
+	address, err = ResolveAccountAddress("GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2")
+	assert.NoError(t, err)
+	assert.Equal(t, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", address)
+
+	_, err = ResolveAccountAddress("SBU2RRGLXH3E5CQHTD3ODLDF2BWDCYUSSBLLZ5GNW7JXHDIYKXZWHOKR")
+	assert.Error(t, err)
+}
+
 func TestGetParamsCustomValidator(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
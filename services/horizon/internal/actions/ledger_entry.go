@@ -0,0 +1,67 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+// GetLedgerEntryHandler is the action handler for the /ledger_entries endpoint
+type GetLedgerEntryHandler struct {
+}
+
+// GetResource returns the ledger entry matching the base64 encoded LedgerKey
+// given in the `key` query param.
+func (handler GetLedgerEntryHandler) GetResource(
+	w HeaderWriter,
+	r *http.Request,
+) (hal.Pageable, error) {
+	encodedKey, err := GetString(r, "key")
+	if err != nil {
+		return nil, err
+	}
+	if encodedKey == "" {
+		return nil, problem.MakeInvalidFieldProblem("key", errors.New("missing ledger key"))
+	}
+
+	var key xdr.LedgerKey
+	if err = xdr.SafeUnmarshalBase64(encodedKey, &key); err != nil {
+		return nil, problem.MakeInvalidFieldProblem(
+			"key",
+			errors.New("key is not a valid base64 encoded LedgerKey"),
+		)
+	}
+
+	switch key.Type {
+	case xdr.LedgerEntryTypeAccount,
+		xdr.LedgerEntryTypeTrustline,
+		xdr.LedgerEntryTypeOffer,
+		xdr.LedgerEntryTypeData:
+	default:
+		return nil, problem.MakeInvalidFieldProblem(
+			"key",
+			errors.Errorf("unsupported ledger key type %d", key.Type),
+		)
+	}
+
+	historyQ, err := HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := historyQ.GetLedgerEntry(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var response horizon.LedgerEntry
+	if err := resourceadapter.PopulateLedgerEntry(r.Context(), &response, entry); err != nil {
+		return nil, errors.Wrap(err, "populating ledger entry")
+	}
+	return response, nil
+}
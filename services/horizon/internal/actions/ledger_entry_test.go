@@ -0,0 +1,148 @@
+package actions
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetLedgerEntryHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetLedgerEntryHandler{}
+
+	batch := q.NewOffersBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(eurOffer, 3))
+	tt.Assert.NoError(batch.Exec())
+
+	dataEntry := xdr.DataEntry{
+		AccountId: seller,
+		DataName:  "name",
+		DataValue: xdr.DataValue("value"),
+	}
+	_, err := q.InsertAccountData(dataEntry, 4)
+	tt.Assert.NoError(err)
+
+	withoutLiabilities := account2
+	withoutLiabilities.Ext = xdr.AccountEntryExt{}
+	tt.Assert.NoError(q.UpsertAccounts([]xdr.LedgerEntry{
+		{
+			LastModifiedLedgerSeq: 5,
+			Data: xdr.LedgerEntryData{
+				Type:    xdr.LedgerEntryTypeAccount,
+				Account: &account1,
+			},
+		},
+		{
+			LastModifiedLedgerSeq: 5,
+			Data: xdr.LedgerEntryData{
+				Type:    xdr.LedgerEntryTypeAccount,
+				Account: &withoutLiabilities,
+			},
+		},
+	}))
+
+	encodeKey := func(key xdr.LedgerKey) string {
+		encoded, err := xdr.MarshalBase64(key)
+		tt.Assert.NoError(err)
+		return encoded
+	}
+	get := func(key string) (horizon.LedgerEntry, error) {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, map[string]string{"key": key}, map[string]string{}, q.Session),
+		)
+		if err != nil {
+			return horizon.LedgerEntry{}, err
+		}
+		return response.(horizon.LedgerEntry), nil
+	}
+
+	t.Run("invalid key", func(t *testing.T) {
+		// AAAABA== is a key of the unknown type 4
+		for _, key := range []string{"", "not a key", "AAAABA=="} {
+			_, err := get(key)
+			p, ok := err.(*problem.P)
+			if tt.Assert.True(ok) {
+				tt.Assert.Equal("bad_request", p.Type)
+				tt.Assert.Equal("key", p.Extras["invalid_field"])
+			}
+		}
+	})
+
+	t.Run("offer", func(t *testing.T) {
+		var key xdr.LedgerKey
+		tt.Assert.NoError(key.SetOffer(issuer, uint64(eurOffer.OfferId)))
+		entry, err := get(encodeKey(key))
+		tt.Assert.NoError(err)
+
+		tt.Assert.Equal("offer", entry.Type)
+		tt.Assert.Equal(uint32(3), entry.LastModifiedLedger)
+		tt.Assert.Nil(entry.Account)
+		if tt.Assert.NotNil(entry.Offer) {
+			tt.Assert.Equal(issuer.Address(), entry.Offer.Seller)
+			tt.Assert.Equal(int64(4), entry.Offer.OfferID)
+			tt.Assert.Equal("native", entry.Offer.Selling.Type)
+			tt.Assert.Equal("EUR", entry.Offer.Buying.Code)
+			tt.Assert.Equal("0.0000500", entry.Offer.Amount)
+			tt.Assert.Equal("1.0000000", entry.Offer.Price)
+			tt.Assert.True(entry.Offer.Passive)
+		}
+
+		tt.Assert.Equal(encodeKey(key), entry.KeyXDR)
+		var decoded xdr.LedgerEntry
+		tt.Assert.NoError(xdr.SafeUnmarshalBase64(entry.EntryXDR, &decoded))
+		tt.Assert.Equal(eurOffer, decoded.Data.MustOffer())
+	})
+
+	t.Run("offer of another seller", func(t *testing.T) {
+		var key xdr.LedgerKey
+		tt.Assert.NoError(key.SetOffer(seller, uint64(eurOffer.OfferId)))
+		_, err := get(encodeKey(key))
+		tt.Assert.Equal(sql.ErrNoRows, err)
+	})
+
+	t.Run("data", func(t *testing.T) {
+		var key xdr.LedgerKey
+		tt.Assert.NoError(key.SetData(seller, "name"))
+		entry, err := get(encodeKey(key))
+		tt.Assert.NoError(err)
+
+		tt.Assert.Equal("data", entry.Type)
+		tt.Assert.Equal(uint32(4), entry.LastModifiedLedger)
+		if tt.Assert.NotNil(entry.Data) {
+			tt.Assert.Equal(seller.Address(), entry.Data.AccountID)
+			tt.Assert.Equal("name", entry.Data.Name)
+			tt.Assert.Equal("dmFsdWU=", entry.Data.Value)
+		}
+	})
+
+	t.Run("account extension", func(t *testing.T) {
+		for _, account := range []xdr.AccountEntry{account1, withoutLiabilities} {
+			var key xdr.LedgerKey
+			tt.Assert.NoError(key.SetAccount(account.AccountId))
+			entry, err := get(encodeKey(key))
+			tt.Assert.NoError(err)
+
+			var decoded xdr.LedgerEntry
+			tt.Assert.NoError(xdr.SafeUnmarshalBase64(entry.EntryXDR, &decoded))
+			tt.Assert.Equal(account.Ext, decoded.Data.MustAccount().Ext)
+		}
+	})
+
+	t.Run("missing entry", func(t *testing.T) {
+		var key xdr.LedgerKey
+		tt.Assert.NoError(key.SetAccount(seller))
+		_, err := get(encodeKey(key))
+		tt.Assert.Equal(sql.ErrNoRows, err)
+	})
+}
@@ -0,0 +1,262 @@
+package actions
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/httpx"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+	strtime "github.com/stellar/go/support/time"
+)
+
+// closeTimeWindow is the number of recent ledgers whose close times are used
+// to compute the average close time.
+const closeTimeWindow = 100
+
+// defaultCloseTime is the average close time used when the history database
+// does not contain enough ledgers to compute it.
+const defaultCloseTime = 5 * time.Second
+
+// maxEstimateDistance is how far from the ledgers in the history database
+// close times are estimated. It keeps the estimates within the range of
+// time.Duration.
+const maxEstimateDistance = 100 * 365 * 24 * time.Hour
+
+// GetLedgerEstimateHandler is the action handler for the /ledger_estimates
+// endpoint.
+type GetLedgerEstimateHandler struct {
+}
+
+// GetResource returns the ledger with the sequence given in the `sequence`
+// query param or the first ledger closed at or after the time given in the
+// `closed_at` query param. Ledgers outside of the history database are
+// estimated from the average close time of the latest ledgers.
+func (handler GetLedgerEstimateHandler) GetResource(
+	w HeaderWriter,
+	r *http.Request,
+) (hal.Pageable, error) {
+	sequence, err := GetInt64(r, "sequence")
+	if err != nil {
+		return nil, err
+	}
+	closedAtParam, err := GetString(r, "closed_at")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case sequence != 0 && closedAtParam != "":
+		return nil, problem.MakeInvalidFieldProblem(
+			"sequence",
+			errors.New("sequence and closed_at cannot be used together"),
+		)
+	case sequence == 0 && closedAtParam == "":
+		return nil, problem.MakeInvalidFieldProblem(
+			"sequence",
+			errors.New("either sequence or closed_at is required"),
+		)
+	case sequence < 0 || sequence > math.MaxInt32:
+		return nil, problem.MakeInvalidFieldProblem("sequence", errors.New("invalid ledger sequence"))
+	}
+
+	var closedAt time.Time
+	if closedAtParam != "" {
+		closedAt, err = time.Parse(time.RFC3339, closedAtParam)
+		if err != nil {
+			return nil, problem.MakeInvalidFieldProblem(
+				"closed_at",
+				errors.New("closed_at must be a RFC3339 timestamp"),
+			)
+		}
+	}
+
+	historyQ, err := HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := loadCloseTimeModel(historyQ)
+	if err != nil {
+		return nil, err
+	}
+
+	var estimate horizon.LedgerEstimate
+	if closedAtParam != "" {
+		estimate, err = model.estimateFromTime(historyQ, closedAt)
+	} else {
+		estimate, err = model.estimateFromSequence(historyQ, int32(sequence))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lb := hal.LinkBuilder{httpx.BaseURL(r.Context())}
+	estimate.Links.Self = lb.Linkf("/ledger_estimates?sequence=%d", estimate.Sequence)
+	estimate.AverageCloseTimeMillis = int64(model.averageCloseTime / time.Millisecond)
+	return estimate, nil
+}
+
+// closeTimeModel estimates the close times of ledgers from the oldest and the
+// latest ledgers in the history database.
+type closeTimeModel struct {
+	elder            history.Ledger
+	latest           history.Ledger
+	averageCloseTime time.Duration
+}
+
+// loadCloseTimeModel computes the average close time of the latest
+// closeTimeWindow ledgers in the history database.
+func loadCloseTimeModel(q *history.Q) (closeTimeModel, error) {
+	var elderSequence, latestSequence int32
+	if err := q.ElderLedger(&elderSequence); err != nil {
+		return closeTimeModel{}, errors.Wrap(err, "could not load elder ledger")
+	}
+	if err := q.LatestLedger(&latestSequence); err != nil {
+		return closeTimeModel{}, errors.Wrap(err, "could not load latest ledger")
+	}
+	if latestSequence == 0 {
+		return closeTimeModel{}, sql.ErrNoRows
+	}
+
+	// the close time of the genesis ledger is not meaningful
+	referenceSequence := latestSequence - closeTimeWindow
+	if referenceSequence < elderSequence {
+		referenceSequence = elderSequence
+	}
+	if referenceSequence < 2 && latestSequence >= 2 {
+		referenceSequence = 2
+	}
+
+	var ledgers []history.Ledger
+	err := q.LedgersBySequence(&ledgers, elderSequence, referenceSequence, latestSequence)
+	if err != nil {
+		return closeTimeModel{}, errors.Wrap(err, "could not load ledgers")
+	}
+
+	var model closeTimeModel
+	var reference history.Ledger
+	for _, ledger := range ledgers {
+		ledger.ClosedAt = ledger.ClosedAt.UTC()
+		if ledger.Sequence == elderSequence {
+			model.elder = ledger
+		}
+		if ledger.Sequence == referenceSequence {
+			reference = ledger
+		}
+		if ledger.Sequence == latestSequence {
+			model.latest = ledger
+		}
+	}
+
+	model.averageCloseTime = defaultCloseTime
+	if latestSequence > referenceSequence {
+		model.averageCloseTime = model.latest.ClosedAt.Sub(reference.ClosedAt) /
+			time.Duration(latestSequence-referenceSequence)
+	}
+	if model.averageCloseTime <= 0 {
+		model.averageCloseTime = defaultCloseTime
+	}
+	return model, nil
+}
+
+// maxEstimateLedgers returns the number of ledgers closed within
+// maxEstimateDistance.
+func (m closeTimeModel) maxEstimateLedgers() int64 {
+	return int64(maxEstimateDistance / m.averageCloseTime)
+}
+
+// estimate returns the estimated close time of the ledger `sequence`. A
+// problem is returned if the ledger is further than maxEstimateDistance from
+// the history database.
+func (m closeTimeModel) estimate(sequence int32) (horizon.LedgerEstimate, error) {
+	reference := m.latest
+	if sequence < m.elder.Sequence {
+		reference = m.elder
+	}
+	ledgers := int64(sequence) - int64(reference.Sequence)
+	if ledgers > m.maxEstimateLedgers() || -ledgers > m.maxEstimateLedgers() {
+		return horizon.LedgerEstimate{}, problem.MakeInvalidFieldProblem(
+			"sequence",
+			errors.New("ledger sequence is too far from the ingested ledgers to be estimated"),
+		)
+	}
+
+	return horizon.LedgerEstimate{
+		Sequence:  sequence,
+		ClosedAt:  reference.ClosedAt.Add(time.Duration(ledgers) * m.averageCloseTime),
+		Estimated: true,
+	}, nil
+}
+
+// estimateFromSequence returns the ledger `sequence` from the history database
+// or its estimate if the ledger has not been ingested.
+func (m closeTimeModel) estimateFromSequence(q *history.Q, sequence int32) (horizon.LedgerEstimate, error) {
+	if sequence < m.elder.Sequence || sequence > m.latest.Sequence {
+		return m.estimate(sequence)
+	}
+
+	var ledger history.Ledger
+	err := q.LedgerBySequence(&ledger, sequence)
+	if q.NoRows(err) {
+		// a gap in the history database
+		return m.estimate(sequence)
+	} else if err != nil {
+		return horizon.LedgerEstimate{}, errors.Wrap(err, fmt.Sprintf("could not load ledger %d", sequence))
+	}
+
+	return horizon.LedgerEstimate{
+		Sequence: ledger.Sequence,
+		ClosedAt: ledger.ClosedAt.UTC(),
+	}, nil
+}
+
+// estimateFromTime returns the first ledger closed at or after `closedAt`,
+// estimated if it is not in the history database.
+func (m closeTimeModel) estimateFromTime(q *history.Q, closedAt time.Time) (horizon.LedgerEstimate, error) {
+	if closedAt.After(m.latest.ClosedAt) {
+		// Sub saturates, so far future times are rejected below
+		elapsed := closedAt.Sub(m.latest.ClosedAt)
+		ledgers := int64(elapsed / m.averageCloseTime)
+		if elapsed%m.averageCloseTime != 0 {
+			ledgers++
+		}
+		sequence := int64(m.latest.Sequence) + ledgers
+		if ledgers > m.maxEstimateLedgers() || sequence > math.MaxInt32 {
+			return horizon.LedgerEstimate{}, problem.MakeInvalidFieldProblem(
+				"closed_at",
+				errors.New("closed_at is too far in the future to be estimated"),
+			)
+		}
+		return m.estimate(int32(sequence))
+	}
+
+	if !closedAt.After(m.elder.ClosedAt) {
+		ledgers := int64(m.elder.ClosedAt.Sub(closedAt) / m.averageCloseTime)
+		if ledgers == 0 {
+			return m.estimateFromSequence(q, m.elder.Sequence)
+		}
+		sequence := int64(m.elder.Sequence) - ledgers
+		if sequence < 1 {
+			sequence = 1
+		}
+		return m.estimate(int32(sequence))
+	}
+
+	first, _, found, err := q.LedgerSequencesClosedWithin(history.TimeRange{
+		Start: strtime.MillisFromInt64(closedAt.UnixNano() / int64(time.Millisecond)),
+	})
+	if err != nil {
+		return horizon.LedgerEstimate{}, errors.Wrap(err, "could not load ledger sequences")
+	}
+	if !found {
+		return m.estimate(m.latest.Sequence + 1)
+	}
+	return m.estimateFromSequence(q, first)
+}
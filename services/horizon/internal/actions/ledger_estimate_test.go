@@ -0,0 +1,108 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestGetLedgerEstimateHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	tt.Scenario("base")
+
+	q := &history.Q{tt.HorizonSession()}
+	handler := GetLedgerEstimateHandler{}
+	get := func(queryParams map[string]string) (horizon.LedgerEstimate, error) {
+		response, err := handler.GetResource(
+			httptest.NewRecorder(),
+			makeRequest(t, queryParams, map[string]string{}, q.Session),
+		)
+		if err != nil {
+			return horizon.LedgerEstimate{}, err
+		}
+		return response.(horizon.LedgerEstimate), nil
+	}
+	parseTime := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		tt.Assert.NoError(err)
+		return parsed
+	}
+
+	t.Run("invalid params", func(t *testing.T) {
+		for _, queryParams := range []map[string]string{
+			{},
+			{"sequence": "2", "closed_at": "2019-10-31T13:19:45Z"},
+			{"sequence": "-1"},
+			{"closed_at": "yesterday"},
+			// beyond the range of time.Duration
+			{"closed_at": "9999-12-31T23:59:59Z"},
+			// beyond the last ledger sequence
+			{"closed_at": "2099-10-31T13:20:00Z"},
+		} {
+			_, err := get(queryParams)
+			p, ok := err.(*problem.P)
+			if tt.Assert.True(ok, queryParams) {
+				tt.Assert.Equal("bad_request", p.Type)
+			}
+		}
+	})
+
+	// ledgers 2 and 3 of the scenario are closed one second apart
+	t.Run("ingested sequence", func(t *testing.T) {
+		estimate, err := get(map[string]string{"sequence": "2"})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(int32(2), estimate.Sequence)
+		tt.Assert.True(parseTime("2019-10-31T13:19:45Z").Equal(estimate.ClosedAt))
+		tt.Assert.False(estimate.Estimated)
+		tt.Assert.Equal(int64(1000), estimate.AverageCloseTimeMillis)
+	})
+
+	t.Run("future sequence", func(t *testing.T) {
+		estimate, err := get(map[string]string{"sequence": "13"})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(int32(13), estimate.Sequence)
+		tt.Assert.True(parseTime("2019-10-31T13:19:56Z").Equal(estimate.ClosedAt))
+		tt.Assert.True(estimate.Estimated)
+	})
+
+	t.Run("last sequence", func(t *testing.T) {
+		estimate, err := get(map[string]string{"sequence": "2147483647"})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(int32(2147483647), estimate.Sequence)
+		tt.Assert.True(estimate.ClosedAt.After(parseTime("2087-01-01T00:00:00Z")))
+		tt.Assert.True(estimate.Estimated)
+	})
+
+	t.Run("ingested time", func(t *testing.T) {
+		estimate, err := get(map[string]string{"closed_at": "2019-10-31T13:19:45Z"})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(int32(2), estimate.Sequence)
+		tt.Assert.False(estimate.Estimated)
+	})
+
+	t.Run("future time", func(t *testing.T) {
+		estimate, err := get(map[string]string{"closed_at": "2019-10-31T13:20:00Z"})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(int32(17), estimate.Sequence)
+		tt.Assert.True(parseTime("2019-10-31T13:20:00Z").Equal(estimate.ClosedAt))
+		tt.Assert.True(estimate.Estimated)
+
+		// the first ledger closed at or after the time
+		estimate, err = get(map[string]string{"closed_at": "2019-10-31T13:19:46.5Z"})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(int32(4), estimate.Sequence)
+		tt.Assert.True(parseTime("2019-10-31T13:19:47Z").Equal(estimate.ClosedAt))
+	})
+
+	t.Run("distant past time", func(t *testing.T) {
+		estimate, err := get(map[string]string{"closed_at": "0001-01-01T00:00:00Z"})
+		tt.Assert.NoError(err)
+		tt.Assert.Equal(int32(1), estimate.Sequence)
+	})
+}
@@ -3,7 +3,9 @@ package actions
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rcrowley/go-metrics"
@@ -12,6 +14,83 @@ import (
 // MetricsHandler is the action handler for the /metrics endpoint
 type MetricsHandler struct {
 	Metrics metrics.Registry
+	// LabeledTimers are rendered next to the metrics of the registry. They are
+	// kept apart because the registry does not support labels.
+	LabeledTimers []*LabeledTimer
+}
+
+// LabeledTimer is a group of timers measuring the same event for different
+// values of a set of labels, e.g. the duration of requests by route.
+type LabeledTimer struct {
+	name   string
+	labels []string
+
+	lock   sync.Mutex
+	timers map[string]*labeledTimer
+}
+
+type labeledTimer struct {
+	values []string
+	timer  metrics.Timer
+}
+
+// NewLabeledTimer creates a LabeledTimer with the given name and label names.
+func NewLabeledTimer(name string, labels ...string) *LabeledTimer {
+	return &LabeledTimer{
+		name:   name,
+		labels: labels,
+		timers: map[string]*labeledTimer{},
+	}
+}
+
+// With returns the timer for the given label values, creating it if needed.
+// Values must be provided in the order of the label names.
+func (l *LabeledTimer) With(values ...string) metrics.Timer {
+	key := strings.Join(values, "\x00")
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	t, ok := l.timers[key]
+	if !ok {
+		t = &labeledTimer{values: values, timer: metrics.NewTimer()}
+		l.timers[key] = t
+	}
+	return t.timer
+}
+
+// prometheusFormat writes the timers as a Prometheus summary.
+func (l *LabeledTimer) prometheusFormat(w io.Writer) {
+	l.lock.Lock()
+	keys := make([]string, 0, len(l.timers))
+	for key := range l.timers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	timers := make([]*labeledTimer, 0, len(keys))
+	for _, key := range keys {
+		timers = append(timers, l.timers[key])
+	}
+	l.lock.Unlock()
+
+	name := "horizon_" + strings.ReplaceAll(l.name, ".", "_")
+	fmt.Fprintf(w, "# TYPE %s summary\n", name)
+	quantiles := []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+	for _, t := range timers {
+		labels := make([]string, len(l.labels))
+		for i, label := range l.labels {
+			labels[i] = fmt.Sprintf("%s=%q", label, t.values[i])
+		}
+		joined := strings.Join(labels, ",")
+
+		snapshot := t.timer.Snapshot()
+		ps := snapshot.Percentiles(quantiles)
+		for i, q := range quantiles {
+			fmt.Fprintf(w, "%s{%s,quantile=\"%g\"} %f\n", name, joined, q, time.Duration(ps[i]).Seconds())
+		}
+		fmt.Fprintf(w, "%s_sum{%s} %f\n", name, joined, time.Duration(snapshot.Sum()).Seconds())
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, joined, snapshot.Count())
+	}
+	fmt.Fprintf(w, "\n")
 }
 
 // PrometheusFormat is a method for actions.PrometheusResponder
@@ -71,5 +150,9 @@ func (handler *MetricsHandler) PrometheusFormat(w io.Writer) error {
 		fmt.Fprintf(w, "\n")
 	})
 
+	for _, timer := range handler.LabeledTimers {
+		timer.prometheusFormat(w)
+	}
+
 	return nil
 }
@@ -0,0 +1,34 @@
+package actions
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandlerLabeledTimers(t *testing.T) {
+	timer := NewLabeledTimer("requests.duration_seconds", "route", "method")
+	timer.With("/accounts/{account_id}", "GET").Update(2 * time.Second)
+	timer.With("/accounts/{account_id}", "GET").Update(4 * time.Second)
+	timer.With("/ledgers", "GET").Update(time.Second)
+
+	assert.Equal(t, timer.With("/ledgers", "GET"), timer.With("/ledgers", "GET"))
+
+	handler := &MetricsHandler{
+		Metrics:       metrics.NewRegistry(),
+		LabeledTimers: []*LabeledTimer{timer},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, handler.PrometheusFormat(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, "# TYPE horizon_requests_duration_seconds summary\n")
+	assert.Contains(t, out, `horizon_requests_duration_seconds_count{route="/accounts/{account_id}",method="GET"} 2`)
+	assert.Contains(t, out, `horizon_requests_duration_seconds_sum{route="/accounts/{account_id}",method="GET"} 6.000000`)
+	assert.Contains(t, out, `horizon_requests_duration_seconds_count{route="/ledgers",method="GET"} 1`)
+	assert.Contains(t, out, `horizon_requests_duration_seconds{route="/ledgers",method="GET",quantile="0.5"} 1.000000`)
+}
@@ -5,10 +5,12 @@ import (
 	"net/http"
 
 	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
 )
 
 // GetOfferByID is the action handler for the /offers/{id} endpoint
@@ -56,12 +58,16 @@ func (handler GetOfferByID) GetResource(
 type OffersQuery struct {
 	SellingBuyingAssetQueryParams `valid:"-"`
 	Seller                        string `schema:"seller" valid:"accountID,optional"`
+	Sort                          string `schema:"sort" valid:"in(price),optional"`
 }
 
+// offersSortByPrice orders offers by price instead of offer id.
+const offersSortByPrice = "price"
+
 // URITemplate returns a rfc6570 URI template the query struct
 func (q OffersQuery) URITemplate() string {
 	// building this manually since we don't want to include all the params in SellingBuyingAssetQueryParams
-	return "/offers{?selling,buying,seller,cursor,limit,order}"
+	return "/offers{?selling,buying,seller,sort,cursor,limit,order}"
 }
 
 // Validate runs custom validations.
@@ -85,7 +91,19 @@ func (handler GetOffersHandler) GetResourcePage(
 		return nil, err
 	}
 
-	pq, err := GetPageQuery(r)
+	orderByPrice := qp.Sort == offersSortByPrice
+	var pq db2.PageQuery
+	if orderByPrice {
+		// offers sorted by price are paged using a price and offer id cursor
+		pq, err = GetPageQuery(r, DisableCursorValidation)
+		if err == nil && pq.Cursor != "" {
+			if _, _, cursorErr := history.ParseOfferPriceCursor(pq.Cursor); cursorErr != nil {
+				err = problem.MakeInvalidFieldProblem("cursor", cursorErr)
+			}
+		}
+	} else {
+		pq, err = GetPageQuery(r)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -100,10 +118,11 @@ func (handler GetOffersHandler) GetResourcePage(
 	}
 
 	query := history.OffersQuery{
-		PageQuery: pq,
-		SellerID:  qp.Seller,
-		Selling:   selling,
-		Buying:    buying,
+		PageQuery:    pq,
+		SellerID:     qp.Seller,
+		Selling:      selling,
+		Buying:       buying,
+		OrderByPrice: orderByPrice,
 	}
 
 	historyQ, err := HistoryQFromRequest(r)
@@ -198,6 +217,9 @@ func getOffersPage(ctx context.Context, historyQ *history.Q, query history.Offer
 		}
 
 		resourceadapter.PopulateOffer(ctx, &offerResponse, record, ledger)
+		if query.OrderByPrice {
+			offerResponse.PT = record.PricePagingToken()
+		}
 		offers = append(offers, offerResponse)
 	}
 
@@ -418,6 +418,86 @@ func TestGetOffersHandler(t *testing.T) {
 			tt.Assert.Equal(p.Extras["reason"], "Asset code length is invalid")
 		}
 	})
+
+	t.Run("Filter by seller and selling asset sorted by price", func(t *testing.T) {
+		records, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{
+					"selling_asset_type": "native",
+					"sort":               "price",
+					"order":              "desc",
+				},
+				map[string]string{},
+				q.Session,
+			),
+		)
+		tt.Assert.NoError(err)
+		offers := pageableToOffers(t, records)
+		tt.Assert.Len(offers, 2)
+		tt.Assert.Equal(int64(twoEurOffer.OfferId), offers[0].ID)
+		tt.Assert.Equal("2_5", offers[0].PT)
+		tt.Assert.Equal(int64(eurOffer.OfferId), offers[1].ID)
+		tt.Assert.Equal("1_4", offers[1].PT)
+
+		records, err = handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{
+					"seller":             issuer.Address(),
+					"selling_asset_type": "native",
+					"sort":               "price",
+				},
+				map[string]string{},
+				q.Session,
+			),
+		)
+		tt.Assert.NoError(err)
+		offers = pageableToOffers(t, records)
+		tt.Assert.Len(offers, 1)
+		tt.Assert.Equal(int64(eurOffer.OfferId), offers[0].ID)
+
+		records, err = handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{
+					"selling_asset_type": "native",
+					"sort":               "price",
+					"cursor":             "1_4",
+				},
+				map[string]string{},
+				q.Session,
+			),
+		)
+		tt.Assert.NoError(err)
+		offers = pageableToOffers(t, records)
+		tt.Assert.Len(offers, 1)
+		tt.Assert.Equal(int64(twoEurOffer.OfferId), offers[0].ID)
+	})
+
+	t.Run("Invalid price cursor", func(t *testing.T) {
+		_, err := handler.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(
+				t,
+				map[string]string{
+					"sort":   "price",
+					"cursor": "4",
+				},
+				map[string]string{},
+				q.Session,
+			),
+		)
+		tt.Assert.Error(err)
+		p, ok := err.(*problem.P)
+		if tt.Assert.True(ok) {
+			tt.Assert.Equal(400, p.Status)
+			tt.Assert.Equal("cursor", p.Extras["invalid_field"])
+		}
+	})
 }
 
 func TestGetAccountOffersHandler(t *testing.T) {
@@ -476,7 +556,7 @@ func pageableToOffers(t *testing.T, page []hal.Pageable) []horizon.Offer {
 
 func TestOffersQueryURLTemplate(t *testing.T) {
 	tt := assert.New(t)
-	expected := "/offers{?selling,buying,seller,cursor,limit,order}"
+	expected := "/offers{?selling,buying,seller,sort,cursor,limit,order}"
 	offersQuery := OffersQuery{}
 	tt.Equal(expected, offersQuery.URITemplate())
 }
@@ -62,7 +62,7 @@ func (handler GetOperationsHandler) GetResourcePage(w HeaderWriter, r *http.Requ
 		return nil, err
 	}
 
-	err = ValidateCursorWithinHistory(pq)
+	err = ValidateCursorWithinHistory(ctx, pq)
 	if err != nil {
 		return nil, err
 	}
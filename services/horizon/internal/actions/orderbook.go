@@ -1,8 +1,10 @@
 package actions
 
 import (
+	"math/big"
 	"net/http"
 
+	"github.com/stellar/go/amount"
 	protocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
@@ -59,6 +61,17 @@ var invalidOrderBook = problem.P{
 		"as buying_asset_code and buying_asset_issuer if buying_asset_type is not 'native'",
 }
 
+const (
+	// orderBookMaxLimit is the maximum number of price levels of each side of
+	// the order book.
+	orderBookMaxLimit = 200
+	// orderBookAggregatedMaxLimit is the maximum number of price levels of
+	// each side of the order book when the levels are aggregated with the
+	// level_size param. Aggregated levels cover a larger part of the order
+	// book so a larger depth can be served.
+	orderBookAggregatedMaxLimit = 2000
+)
+
 // GetOrderbookHandler is the action handler for the /order_book endpoint
 type GetOrderbookHandler struct {
 }
@@ -89,7 +102,15 @@ func (handler GetOrderbookHandler) GetResource(w HeaderWriter, r *http.Request)
 	if err != nil {
 		return nil, invalidOrderBook
 	}
-	limit, err := GetLimit(r, "limit", 20, 200)
+	levelSize, err := getOrderBookLevelSize(r)
+	if err != nil {
+		return nil, err
+	}
+	maxLimit := uint64(orderBookMaxLimit)
+	if levelSize != nil {
+		maxLimit = orderBookAggregatedMaxLimit
+	}
+	limit, err := GetLimit(r, "limit", 20, maxLimit)
 	if err != nil {
 		return nil, invalidOrderBook
 	}
@@ -99,7 +120,12 @@ func (handler GetOrderbookHandler) GetResource(w HeaderWriter, r *http.Request)
 		return nil, err
 	}
 
-	summary, err := historyQ.GetOrderBookSummary(selling, buying, int(limit))
+	var summary history.OrderBookSummary
+	if levelSize != nil {
+		summary, err = historyQ.GetAggregatedOrderBookSummary(selling, buying, levelSize, int(limit))
+	} else {
+		summary, err = historyQ.GetOrderBookSummary(selling, buying, int(limit))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -116,3 +142,19 @@ func (handler GetOrderbookHandler) GetResource(w HeaderWriter, r *http.Request)
 
 	return response, nil
 }
+
+// getOrderBookLevelSize returns the size of the aggregated price levels given
+// in the level_size param, or nil when the levels should not be aggregated.
+func getOrderBookLevelSize(r *http.Request) (*big.Rat, error) {
+	levelSize, err := GetString(r, "level_size")
+	if err != nil || levelSize == "" {
+		return nil, err
+	}
+
+	parsed, err := GetPositiveAmount(r, "level_size")
+	if err != nil {
+		return nil, err
+	}
+
+	return big.NewRat(int64(parsed), amount.One), nil
+}
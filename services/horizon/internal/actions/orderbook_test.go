@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/xdr"
 )
 
@@ -449,6 +450,19 @@ func TestOrderbookGetResourceValidation(t *testing.T) {
 				"limit":                "20000",
 			},
 		},
+		{
+			"limit is too high for aggregated levels",
+			map[string]string{
+				"buying_asset_type":    eurAssetType,
+				"buying_asset_code":    eurAssetCode,
+				"buying_asset_issuer":  eurAssetIssuer,
+				"selling_asset_type":   usdAssetType,
+				"selling_asset_code":   usdAssetCode,
+				"selling_asset_issuer": usdAssetIssuer,
+				"level_size":           "0.01",
+				"limit":                "20000",
+			},
+		},
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
 			r := makeRequest(t, testCase.queryParams, map[string]string{}, nil)
@@ -464,6 +478,31 @@ func TestOrderbookGetResourceValidation(t *testing.T) {
 	}
 }
 
+func TestOrderbookGetResourceInvalidLevelSize(t *testing.T) {
+	handler := GetOrderbookHandler{}
+
+	for _, levelSize := range []string{"0", "-1", "abc", "0.00000001"} {
+		t.Run(levelSize, func(t *testing.T) {
+			r := makeRequest(
+				t,
+				map[string]string{
+					"buying_asset_type":  "native",
+					"selling_asset_type": "native",
+					"level_size":         levelSize,
+				},
+				map[string]string{},
+				nil,
+			)
+			_, err := handler.GetResource(httptest.NewRecorder(), r)
+			p, ok := err.(*problem.P)
+			if assert.True(t, ok) {
+				assert.Equal(t, 400, p.Status)
+				assert.Equal(t, "level_size", p.Extras["invalid_field"])
+			}
+		})
+	}
+}
+
 func TestOrderbookGetResource(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -584,34 +623,95 @@ func TestOrderbookGetResource(t *testing.T) {
 		},
 	}
 
+	halfLevelsResponse := empty
+	halfLevelsResponse.Asks = []protocol.PriceLevel{
+		{
+			PriceR: protocol.Price{N: 2, D: 1},
+			Price:  "2.0000000",
+			Amount: "922337203685.4776807",
+		},
+		{
+			PriceR: protocol.Price{N: 3, D: 1},
+			Price:  "3.0000000",
+			Amount: "0.0000500",
+		},
+	}
+	halfLevelsResponse.Bids = []protocol.PriceLevel{
+		{
+			PriceR: protocol.Price{N: 1, D: 1},
+			Price:  "1.0000000",
+			Amount: "0.0000500",
+		},
+		{
+			PriceR: protocol.Price{N: 1, D: 2},
+			Price:  "0.5000000",
+			Amount: "0.0000500",
+		},
+	}
+
+	wideLevelsResponse := empty
+	wideLevelsResponse.Asks = []protocol.PriceLevel{
+		{
+			PriceR: protocol.Price{N: 5, D: 1},
+			Price:  "5.0000000",
+			Amount: "922337203685.4777307",
+		},
+	}
+	wideLevelsResponse.Bids = []protocol.PriceLevel{
+		{
+			PriceR: protocol.Price{N: 0, D: 1},
+			Price:  "0.0000000",
+			Amount: "0.0001000",
+		},
+	}
+
 	for _, testCase := range []struct {
-		name     string
-		limit    int
-		expected OrderBookResponse
+		name      string
+		limit     int
+		levelSize string
+		expected  OrderBookResponse
 	}{
 
 		{
 			"full orderbook",
 			10,
+			"",
 			fullResponse,
 		},
 		{
 			"limit request",
 			1,
+			"",
 			limitResponse,
 		},
+		{
+			"aggregated levels",
+			10,
+			"0.5",
+			halfLevelsResponse,
+		},
+		{
+			"aggregated levels covering all offers",
+			1000,
+			"5",
+			wideLevelsResponse,
+		},
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
 			handler := GetOrderbookHandler{}
+			params := map[string]string{
+				"buying_asset_type":   eurAssetType,
+				"buying_asset_code":   eurAssetCode,
+				"buying_asset_issuer": eurAssetIssuer,
+				"selling_asset_type":  "native",
+				"limit":               strconv.Itoa(testCase.limit),
+			}
+			if testCase.levelSize != "" {
+				params["level_size"] = testCase.levelSize
+			}
 			r := makeRequest(
 				t,
-				map[string]string{
-					"buying_asset_type":   eurAssetType,
-					"buying_asset_code":   eurAssetCode,
-					"buying_asset_issuer": eurAssetIssuer,
-					"selling_asset_type":  "native",
-					"limit":               strconv.Itoa(testCase.limit),
-				},
+				params,
 				map[string]string{},
 				q.Session,
 			)
@@ -14,10 +14,10 @@ import (
 )
 
 // TransactionPage returns a page containing the transaction records of an
-// account/ledger identified by accountID/ledgerID into a page based on pq and
-// includeFailedTx.
-func TransactionPage(ctx context.Context, hq *history.Q, accountID string, ledgerID int32, includeFailedTx bool, pq db2.PageQuery) (hal.Page, error) {
-	records, err := loadTransactionRecords(hq, accountID, ledgerID, includeFailedTx, pq)
+// account/ledger identified by accountID/ledgerID into a page based on pq,
+// includeFailedTx and closedWithin.
+func TransactionPage(ctx context.Context, hq *history.Q, accountID string, ledgerID int32, includeFailedTx bool, closedWithin history.TimeRange, pq db2.PageQuery) (hal.Page, error) {
+	records, err := loadTransactionRecords(hq, accountID, ledgerID, includeFailedTx, closedWithin, pq)
 	if err != nil {
 		return hal.Page{}, errors.Wrap(err, "loading transaction records")
 	}
@@ -44,9 +44,9 @@ func TransactionPage(ctx context.Context, hq *history.Q, accountID string, ledge
 }
 
 // loadTransactionRecords returns a slice of transaction records of an
-// account/ledger identified by accountID/ledgerID based on pq and
-// includeFailedTx.
-func loadTransactionRecords(hq *history.Q, accountID string, ledgerID int32, includeFailedTx bool, pq db2.PageQuery) ([]history.Transaction, error) {
+// account/ledger identified by accountID/ledgerID based on pq, includeFailedTx
+// and closedWithin.
+func loadTransactionRecords(hq *history.Q, accountID string, ledgerID int32, includeFailedTx bool, closedWithin history.TimeRange, pq db2.PageQuery) ([]history.Transaction, error) {
 	if accountID != "" && ledgerID != 0 {
 		return nil, errors.New("conflicting exclusive fields are present: account_id and ledger_id")
 	}
@@ -65,7 +65,7 @@ func loadTransactionRecords(hq *history.Q, accountID string, ledgerID int32, inc
 		txs.IncludeFailed()
 	}
 
-	err := txs.Page(pq).Select(&records)
+	err := txs.ClosedWithin(closedWithin).Page(pq).Select(&records)
 	if err != nil {
 		return nil, errors.Wrap(err, "executing transaction records query")
 	}
@@ -92,9 +92,10 @@ func loadTransactionRecords(hq *history.Q, accountID string, ledgerID int32, inc
 }
 
 // StreamTransactions streams transaction records of an account/ledger
-// identified by accountID/ledgerID based on pq and includeFailedTx.
-func StreamTransactions(ctx context.Context, s *sse.Stream, hq *history.Q, accountID string, ledgerID int32, includeFailedTx bool, pq db2.PageQuery) error {
-	allRecords, err := loadTransactionRecords(hq, accountID, ledgerID, includeFailedTx, pq)
+// identified by accountID/ledgerID based on pq, includeFailedTx and
+// closedWithin.
+func StreamTransactions(ctx context.Context, s *sse.Stream, hq *history.Q, accountID string, ledgerID int32, includeFailedTx bool, closedWithin history.TimeRange, pq db2.PageQuery) error {
+	allRecords, err := loadTransactionRecords(hq, accountID, ledgerID, includeFailedTx, closedWithin, pq)
 	if err != nil {
 		return errors.Wrap(err, "loading transaction records")
 	}
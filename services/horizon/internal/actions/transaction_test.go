@@ -8,6 +8,7 @@ import (
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/test"
+	strtime "github.com/stellar/go/support/time"
 )
 
 var defaultPage db2.PageQuery = db2.PageQuery{
@@ -23,33 +24,33 @@ func TestTransactionPage(t *testing.T) {
 	ctx := context.Background()
 
 	// filter by account
-	page, err := TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 0, true, defaultPage)
+	page, err := TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 0, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(3, len(page.Embedded.Records))
 
-	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", 0, true, defaultPage)
+	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", 0, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(1, len(page.Embedded.Records))
 
-	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU", 0, true, defaultPage)
+	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU", 0, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(2, len(page.Embedded.Records))
 
 	// filter by ledger
-	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "", 1, true, defaultPage)
+	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "", 1, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(0, len(page.Embedded.Records))
 
-	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "", 2, true, defaultPage)
+	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "", 2, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(3, len(page.Embedded.Records))
 
-	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "", 3, true, defaultPage)
+	page, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "", 3, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(1, len(page.Embedded.Records))
 
 	// conflict fields
-	_, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 1, true, defaultPage)
+	_, err = TransactionPage(ctx, &history.Q{tt.HorizonSession()}, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 1, true, history.TimeRange{}, defaultPage)
 	tt.Assert.Error(err)
 }
 
@@ -58,36 +59,75 @@ func TestLoadTransactionRecords(t *testing.T) {
 	defer tt.Finish()
 
 	// filter by account
-	records, err := loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 0, true, defaultPage)
+	records, err := loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", 0, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(3, len(records))
 
-	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", 0, true, defaultPage)
+	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", 0, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(1, len(records))
 
-	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU", 0, true, defaultPage)
+	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU", 0, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(2, len(records))
 
 	// filter by ledger
-	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "", 1, true, defaultPage)
+	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "", 1, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(0, len(records))
 
-	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "", 2, true, defaultPage)
+	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "", 2, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(3, len(records))
 
-	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "", 3, true, defaultPage)
+	records, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "", 3, true, history.TimeRange{}, defaultPage)
 	tt.Assert.NoError(err)
 	tt.Assert.Equal(1, len(records))
 
 	// conflict fields
-	_, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", 1, true, defaultPage)
+	_, err = loadTransactionRecords(&history.Q{tt.HorizonSession()}, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", 1, true, history.TimeRange{}, defaultPage)
 	tt.Assert.Error(err)
 }
 
+func TestLoadTransactionRecordsClosedWithin(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &history.Q{tt.HorizonSession()}
+
+	// ledger 2 closed at 2019-10-31T13:19:45Z and ledger 3 one second later
+	ledger2CloseTime := strtime.MillisFromInt64(1572527985000)
+	ledger3CloseTime := strtime.MillisFromInt64(1572527986000)
+
+	records, err := loadTransactionRecords(q, "", 0, true, history.TimeRange{
+		Start: ledger2CloseTime,
+		End:   ledger3CloseTime,
+	}, defaultPage)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 3)
+	for _, record := range records {
+		tt.Assert.Equal(int32(2), record.LedgerSequence)
+	}
+
+	records, err = loadTransactionRecords(q, "", 0, true, history.TimeRange{
+		Start: ledger3CloseTime,
+	}, defaultPage)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+	tt.Assert.Equal(int32(3), records[0].LedgerSequence)
+
+	records, err = loadTransactionRecords(q, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", 0, true, history.TimeRange{
+		End: ledger3CloseTime,
+	}, defaultPage)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 1)
+
+	records, err = loadTransactionRecords(q, "", 0, true, history.TimeRange{
+		Start: ledger3CloseTime + 1000,
+	}, defaultPage)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(records, 0)
+}
+
 func checkOuterHashResponse(
 	tt *test.T,
 	fixture history.FeeBumpFixture,
@@ -137,6 +177,7 @@ func TestFeeBumpTransactionPage(t *testing.T) {
 		"",
 		0,
 		false,
+		history.TimeRange{},
 		db2.PageQuery{Cursor: "", Limit: 10, Order: db2.OrderAscending},
 	)
 	tt.Assert.NoError(err)
@@ -119,7 +119,7 @@ func isAssetType(str string) bool {
 }
 
 func isAccountID(str string) bool {
-	if _, err := xdr.AddressToAccountId(str); err != nil {
+	if _, err := xdr.AddressToMuxedAccount(str); err != nil {
 		return false
 	}
 
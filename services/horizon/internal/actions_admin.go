@@ -0,0 +1,236 @@
+package horizon
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi"
+	"github.com/sirupsen/logrus"
+
+	"github.com/stellar/go/services/horizon/internal/actions"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// IngestionStatus is the response of the admin ingestion endpoints.
+type IngestionStatus struct {
+	Enabled             bool            `json:"enabled"`
+	Paused              bool            `json:"paused"`
+	Reingest            *ReingestStatus `json:"reingest,omitempty"`
+	LogLevel            string          `json:"log_level"`
+	HistoryLatestLedger int32           `json:"history_latest_ledger"`
+	HistoryElderLedger  int32           `json:"history_elder_ledger"`
+	CoreLatestLedger    int32           `json:"core_latest_ledger"`
+	Message             string          `json:"message,omitempty"`
+}
+
+// ReingestStatus describes the last range reingestion triggered using the
+// admin server.
+type ReingestStatus struct {
+	From    uint32 `json:"from"`
+	To      uint32 `json:"to"`
+	Force   bool   `json:"force"`
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ReingestQuery query struct for the admin reingest end-point
+type ReingestQuery struct {
+	From  uint32 `schema:"from" valid:"-"`
+	To    uint32 `schema:"to" valid:"-"`
+	Force bool   `schema:"force" valid:"-"`
+}
+
+// Validate runs custom validations.
+func (q ReingestQuery) Validate() error {
+	if q.From == 0 {
+		return problem.MakeInvalidFieldProblem("from", errors.New("must be greater than 0"))
+	}
+	if q.To < q.From {
+		return problem.MakeInvalidFieldProblem("to", errors.New("must be greater or equal to from"))
+	}
+	return nil
+}
+
+// LogLevelQuery query struct for the admin log level end-point
+type LogLevelQuery struct {
+	Level string `schema:"level" valid:"required"`
+}
+
+// reingestTracker keeps track of the range reingestion triggered using the
+// admin server. Only one reingestion can run at a time.
+type reingestTracker struct {
+	sync.Mutex
+	status *ReingestStatus
+}
+
+var ingestionDisabled = problem.P{
+	Type:   "ingestion_disabled",
+	Title:  "Ingestion Disabled",
+	Status: http.StatusConflict,
+	Detail: "This horizon instance is not ingesting. Start horizon with --ingest " +
+		"to use this endpoint.",
+}
+
+var reingestRunning = problem.P{
+	Type:   "reingest_running",
+	Title:  "Reingestion Running",
+	Status: http.StatusConflict,
+	Detail: "A range reingestion is already running. Wait until it completes " +
+		"before starting a new one.",
+}
+
+// mustInstallAdminActions installs the routes used by operators to control the
+// running horizon instance onto the internal router.
+func (w *web) mustInstallAdminActions(app *App) {
+	if w == nil {
+		log.Fatal("missing web instance for installing admin actions")
+	}
+
+	r := w.internalRouter
+	r.Route("/ingestion", func(r chi.Router) {
+		r.Get("/", app.ingestionStatusHandler)
+		r.Post("/pause", app.pauseIngestionHandler)
+		r.Post("/resume", app.resumeIngestionHandler)
+		r.Post("/reingest", app.reingestHandler)
+	})
+	r.Post("/caches/flush", app.flushCachesHandler)
+	r.Put("/log_level", app.logLevelHandler)
+	r.Post("/config/reload", app.reloadConfigHandler)
+	r.Route("/jobs", func(r chi.Router) {
+		r.Get("/", app.jobsHandler)
+		r.Post("/", app.submitJobHandler)
+		r.Get("/{id}", app.jobHandler)
+		r.Post("/{id}/cancel", app.cancelJobHandler)
+	})
+	r.Get("/usage", app.usageHandler)
+}
+
+func (a *App) ingestionStatus(message string) IngestionStatus {
+	ls := a.ledgerState.CurrentState()
+	status := IngestionStatus{
+		Enabled:             a.expingester != nil,
+		LogLevel:            log.DefaultLogger.Logger.Level.String(),
+		HistoryLatestLedger: ls.HistoryLatest,
+		HistoryElderLedger:  ls.HistoryElder,
+		CoreLatestLedger:    ls.CoreLatest,
+		Message:             message,
+	}
+	if a.expingester != nil {
+		status.Paused = a.expingester.Paused()
+	}
+
+	a.reingest.Lock()
+	if a.reingest.status != nil {
+		reingest := *a.reingest.status
+		status.Reingest = &reingest
+	}
+	a.reingest.Unlock()
+	return status
+}
+
+func (a *App) ingestionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	httpjson.Render(w, a.ingestionStatus(""), httpjson.JSON)
+}
+
+func (a *App) pauseIngestionHandler(w http.ResponseWriter, r *http.Request) {
+	if a.expingester == nil {
+		problem.Render(r.Context(), w, ingestionDisabled)
+		return
+	}
+
+	a.expingester.Pause()
+	httpjson.Render(w, a.ingestionStatus("ingestion paused"), httpjson.JSON)
+}
+
+func (a *App) resumeIngestionHandler(w http.ResponseWriter, r *http.Request) {
+	if a.expingester == nil {
+		problem.Render(r.Context(), w, ingestionDisabled)
+		return
+	}
+
+	a.expingester.Resume()
+	httpjson.Render(w, a.ingestionStatus("ingestion resumed"), httpjson.JSON)
+}
+
+// reingestHandler starts reingesting the requested range of ledgers in the
+// background. The progress can be checked using the ingestion status endpoint.
+func (a *App) reingestHandler(w http.ResponseWriter, r *http.Request) {
+	if len(a.config.HistoryArchiveURLs) == 0 || a.config.HistoryArchiveURLs[0] == "" {
+		problem.Render(r.Context(), w, ingestionDisabled)
+		return
+	}
+
+	qp := ReingestQuery{}
+	if err := actions.GetParams(&qp, r); err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	a.reingest.Lock()
+	if a.reingest.status != nil && a.reingest.status.Running {
+		a.reingest.Unlock()
+		problem.Render(r.Context(), w, reingestRunning)
+		return
+	}
+	status := &ReingestStatus{From: qp.From, To: qp.To, Force: qp.Force, Running: true}
+	a.reingest.status = status
+	a.reingest.Unlock()
+
+	system, closeSystem, err := newExpIngestSystem(a)
+	if err != nil {
+		a.finishReingest(status, err)
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	go func() {
+		defer closeSystem()
+		log.WithFields(log.F{"from": qp.From, "to": qp.To, "force": qp.Force}).
+			Info("Reingesting range requested by admin")
+		a.finishReingest(status, system.ReingestRange(qp.From, qp.To, qp.Force))
+	}()
+
+	httpjson.RenderStatus(w, http.StatusAccepted, a.ingestionStatus("reingestion started"), httpjson.JSON)
+}
+
+func (a *App) finishReingest(status *ReingestStatus, err error) {
+	a.reingest.Lock()
+	defer a.reingest.Unlock()
+	status.Running = false
+	if err != nil {
+		log.WithField("err", err).Error("Error reingesting range requested by admin")
+		status.Error = err.Error()
+	}
+}
+
+// flushCachesHandler drops the cached ledger state, fee stats and
+// stellar-core info and reloads them from their sources.
+func (a *App) flushCachesHandler(w http.ResponseWriter, r *http.Request) {
+	a.feeStatsState.Reset()
+	a.UpdateLedgerState()
+	a.UpdateFeeStatsState()
+	a.UpdateStellarCoreInfo()
+	httpjson.Render(w, a.ingestionStatus("caches flushed"), httpjson.JSON)
+}
+
+// logLevelHandler changes the log level without restarting the process.
+func (a *App) logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	qp := LogLevelQuery{}
+	if err := actions.GetParams(&qp, r); err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	level, err := logrus.ParseLevel(qp.Level)
+	if err != nil {
+		problem.Render(r.Context(), w, problem.MakeInvalidFieldProblem("level", err))
+		return
+	}
+
+	log.DefaultLogger.SetLevel(level)
+	log.WithField("level", level.String()).Info("Log level changed by admin")
+	httpjson.Render(w, a.ingestionStatus("log level changed"), httpjson.JSON)
+}
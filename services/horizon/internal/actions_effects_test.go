@@ -58,6 +58,30 @@ func TestEffectActions_Index(t *testing.T) {
 			ht.Assert.PageOf(3, w.Body)
 		}
 
+		// filtered by type
+		w = ht.Get("/effects?type=account_created")
+		if ht.Assert.Equal(200, w.Code) {
+			ht.Assert.PageOf(3, w.Body)
+		}
+
+		w = ht.Get("/effects?type=account_created&type=account_credited")
+		if ht.Assert.Equal(200, w.Code) {
+			ht.Assert.PageOf(4, w.Body)
+		}
+
+		w = ht.Get("/accounts/GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H/effects?type=account_debited")
+		if ht.Assert.Equal(200, w.Code) {
+			ht.Assert.PageOf(3, w.Body)
+		}
+
+		w = ht.Get("/accounts/GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H/effects?type=account_credited")
+		if ht.Assert.Equal(200, w.Code) {
+			ht.Assert.PageOf(0, w.Body)
+		}
+
+		w = ht.Get("/effects?type=unknown_effect")
+		ht.Assert.Equal(400, w.Code)
+
 		w = ht.Get("/accounts/GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2/effects")
 		if ht.Assert.Equal(200, w.Code) {
 			ht.Assert.PageOf(2, w.Body)
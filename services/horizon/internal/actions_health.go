@@ -0,0 +1,167 @@
+package horizon
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+const (
+	// healthCheckTimeout bounds the time spent pinging each database.
+	healthCheckTimeout = 5 * time.Second
+	// coreInfoMaxAge is the age after which the stellar-core info (refreshed
+	// every tick) is considered stale.
+	coreInfoMaxAge = time.Minute
+	// historyGapsCacheTTL is the time the result of the (expensive) history
+	// gaps query is reused for.
+	historyGapsCacheTTL = time.Minute
+)
+
+// Health is the response of the /health endpoint.
+type Health struct {
+	Healthy     bool              `json:"healthy"`
+	HistoryDB   DatabaseHealth    `json:"history_db"`
+	CoreDB      *DatabaseHealth   `json:"core_db,omitempty"`
+	CoreInfo    CoreInfoHealth    `json:"core_info"`
+	Ingestion   IngestionHealth   `json:"ingestion"`
+	HistoryGaps HistoryGapsHealth `json:"history_gaps"`
+}
+
+// DatabaseHealth reports whether a database is reachable.
+type DatabaseHealth struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// CoreInfoHealth reports the freshness of the stellar-core info horizon
+// serves in its root resource.
+type CoreInfoHealth struct {
+	Healthy     bool       `json:"healthy"`
+	CoreVersion string     `json:"core_version"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	AgeSeconds  float64    `json:"age_seconds"`
+}
+
+// IngestionHealth reports how many ledgers the history database lags behind
+// stellar-core.
+type IngestionHealth struct {
+	Healthy             bool  `json:"healthy"`
+	LagLedgers          int32 `json:"lag_ledgers"`
+	StaleThreshold      uint  `json:"stale_threshold"`
+	HistoryLatestLedger int32 `json:"history_latest_ledger"`
+	CoreLatestLedger    int32 `json:"core_latest_ledger"`
+}
+
+// HistoryGapsHealth lists the ranges of ledgers missing from the history
+// database.
+type HistoryGapsHealth struct {
+	Healthy bool                  `json:"healthy"`
+	Gaps    []history.LedgerRange `json:"gaps"`
+	Error   string                `json:"error,omitempty"`
+}
+
+type historyGapsCache struct {
+	sync.Mutex
+	checkedAt time.Time
+	result    HistoryGapsHealth
+}
+
+func pingDB(ctx context.Context, ping func(ctx context.Context) error) DatabaseHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if err := ping(ctx); err != nil {
+		return DatabaseHealth{Error: err.Error()}
+	}
+	return DatabaseHealth{Healthy: true}
+}
+
+func (a *App) coreInfoHealth(now time.Time) CoreInfoHealth {
+	settings := a.coreSettings.get()
+	result := CoreInfoHealth{CoreVersion: settings.coreVersion}
+	if settings.updatedAt.IsZero() {
+		return result
+	}
+
+	updatedAt := settings.updatedAt
+	result.UpdatedAt = &updatedAt
+	result.AgeSeconds = now.Sub(updatedAt).Seconds()
+	result.Healthy = now.Sub(updatedAt) <= coreInfoMaxAge
+	return result
+}
+
+func (a *App) ingestionHealth() IngestionHealth {
+	ls := a.ledgerState.CurrentState()
+	return IngestionHealth{
+		Healthy:             !isHistoryStale(ls, a.config.StaleThreshold),
+		LagLedgers:          ls.CoreLatest - ls.HistoryLatest,
+		StaleThreshold:      a.config.StaleThreshold,
+		HistoryLatestLedger: ls.HistoryLatest,
+		CoreLatestLedger:    ls.CoreLatest,
+	}
+}
+
+func (a *App) historyGapsHealth(now time.Time) HistoryGapsHealth {
+	a.historyGaps.Lock()
+	defer a.historyGaps.Unlock()
+
+	if !a.historyGaps.checkedAt.IsZero() && now.Sub(a.historyGaps.checkedAt) < historyGapsCacheTTL {
+		return a.historyGaps.result
+	}
+
+	var result HistoryGapsHealth
+	gaps, err := a.HistoryQ().GetLedgerGaps()
+	if err != nil {
+		log.WithField("err", err).Warn("could not load history gaps")
+		result.Error = err.Error()
+	} else {
+		result.Healthy = len(gaps) == 0
+		result.Gaps = gaps
+		if len(gaps) > 0 {
+			log.WithField("gaps", gaps).Warn("history database is missing ledgers")
+		}
+	}
+
+	a.historyGaps.checkedAt = now
+	a.historyGaps.result = result
+	return result
+}
+
+// health runs all the health checks of the instance.
+func (a *App) health(ctx context.Context) Health {
+	now := time.Now()
+	result := Health{
+		HistoryDB:   pingDB(ctx, a.historyQ.Session.DB.PingContext),
+		CoreInfo:    a.coreInfoHealth(now),
+		Ingestion:   a.ingestionHealth(),
+		HistoryGaps: a.historyGapsHealth(now),
+	}
+	// there is no stellar-core database when ingesting from captive core
+	if a.coreQ != nil {
+		coreDB := pingDB(ctx, a.coreQ.Session.DB.PingContext)
+		result.CoreDB = &coreDB
+	}
+	result.Healthy = result.HistoryDB.Healthy &&
+		(result.CoreDB == nil || result.CoreDB.Healthy) &&
+		result.CoreInfo.Healthy &&
+		result.Ingestion.Healthy &&
+		result.HistoryGaps.Healthy
+	return result
+}
+
+// healthHandler serves the /health endpoint. It responds with 503 Service
+// Unavailable when any of the checks fail so it can be used by load balancers.
+func (a *App) healthHandler(w http.ResponseWriter, r *http.Request) {
+	result := a.health(r.Context())
+
+	status := http.StatusOK
+	if !result.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	httpjson.RenderStatus(w, status, result, httpjson.JSON)
+}
@@ -0,0 +1,82 @@
+package horizon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestHealthAction(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	server := test.NewStaticMockServer(`{
+			"info": {
+				"network": "test",
+				"build": "test-core",
+				"ledger": {
+					"version": 3,
+					"num": 3
+				},
+				"protocol_version": 4
+			}
+		}`)
+	defer server.Close()
+
+	ht.App.config.StellarCoreURL = server.URL
+	ht.App.config.NetworkPassphrase = "test"
+	ht.App.UpdateStellarCoreInfo()
+	ht.App.UpdateLedgerState()
+
+	w := ht.Get("/health")
+	ht.Assert.Equal(200, w.Code)
+
+	var actual Health
+	ht.Require.NoError(json.Unmarshal(w.Body.Bytes(), &actual))
+	ht.Assert.True(actual.Healthy)
+	ht.Assert.True(actual.HistoryDB.Healthy)
+	// API only instances do not connect to the stellar-core database
+	ht.Assert.Nil(actual.CoreDB)
+	ht.Assert.True(actual.CoreInfo.Healthy)
+	ht.Assert.Equal("test-core", actual.CoreInfo.CoreVersion)
+	ht.Assert.True(actual.Ingestion.Healthy)
+	ht.Assert.Equal(int32(0), actual.Ingestion.LagLedgers)
+	ht.Assert.True(actual.HistoryGaps.Healthy)
+	ht.Assert.Empty(actual.HistoryGaps.Gaps)
+
+	// stale core info and a gap in history make the instance unhealthy
+	ht.App.coreSettings.Lock()
+	ht.App.coreSettings.updatedAt = time.Now().Add(-2 * coreInfoMaxAge)
+	ht.App.coreSettings.Unlock()
+	_, err := ht.HorizonSession().ExecRaw("DELETE FROM history_ledgers WHERE sequence = 2")
+	ht.Require.NoError(err)
+	ht.App.historyGaps.checkedAt = time.Time{}
+
+	w = ht.Get("/health")
+	ht.Assert.Equal(503, w.Code)
+	ht.Require.NoError(json.Unmarshal(w.Body.Bytes(), &actual))
+	ht.Assert.False(actual.Healthy)
+	ht.Assert.False(actual.CoreInfo.Healthy)
+	ht.Assert.False(actual.HistoryGaps.Healthy)
+	ht.Assert.Equal(
+		[]history.LedgerRange{{StartSequence: 2, EndSequence: 2}},
+		actual.HistoryGaps.Gaps,
+	)
+}
+
+func TestIngestionHealthStaleThreshold(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	ht.App.config.StaleThreshold = 5
+	ht.App.ledgerState.SetState(ledger.State{CoreLatest: 20, HistoryLatest: 10})
+	ht.Assert.False(ht.App.ingestionHealth().Healthy)
+	ht.Assert.Equal(int32(10), ht.App.ingestionHealth().LagLedgers)
+
+	ht.App.ledgerState.SetState(ledger.State{CoreLatest: 13, HistoryLatest: 10})
+	ht.Assert.True(ht.App.ingestionHealth().Healthy)
+}
@@ -5,10 +5,10 @@ import (
 	"github.com/stellar/go/services/horizon/internal/actions"
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
-	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/render/sse"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
 )
 
@@ -26,6 +26,7 @@ var _ actions.EventStreamer = (*LedgerIndexAction)(nil)
 type LedgerIndexAction struct {
 	Action
 	PagingParams db2.PageQuery
+	ClosedWithin history.TimeRange
 	Records      []history.Ledger
 	Page         hal.Page
 }
@@ -69,10 +70,27 @@ func (action *LedgerIndexAction) SSE(stream *sse.Stream) error {
 func (action *LedgerIndexAction) loadParams() {
 	action.ValidateCursorAsDefault()
 	action.PagingParams = action.GetPageQuery()
+	action.ClosedWithin.Start = action.GetTimeMillis("start_time")
+	action.ClosedWithin.End = action.GetTimeMillis("end_time")
+	if action.Err != nil {
+		return
+	}
+
+	start, end := action.ClosedWithin.Start, action.ClosedWithin.End
+	if start < 0 {
+		action.SetInvalidField("start_time", errors.New("invalid time value, expected milliseconds since epoch"))
+	} else if end < 0 {
+		action.SetInvalidField("end_time", errors.New("invalid time value, expected milliseconds since epoch"))
+	} else if !start.IsNil() && !end.IsNil() && end <= start {
+		action.SetInvalidField("end_time", errors.New("end_time must be after start_time"))
+	}
 }
 
 func (action *LedgerIndexAction) loadRecords() {
-	action.Err = action.HistoryQ().Ledgers().Page(action.PagingParams).Select(&action.Records)
+	action.Err = action.HistoryQ().Ledgers().
+		ClosedWithin(action.ClosedWithin).
+		Page(action.PagingParams).
+		Select(&action.Records)
 }
 
 func (action *LedgerIndexAction) loadPage() {
@@ -124,7 +142,7 @@ func (action *LedgerShowAction) loadRecord() {
 }
 
 func (action *LedgerShowAction) verifyWithinHistory() {
-	if action.Sequence < ledger.CurrentState().HistoryElder {
+	if action.Sequence < action.App.ledgerState.CurrentState().HistoryElder {
 		action.Err = &problem.BeforeHistory
 	}
 }
@@ -23,6 +23,20 @@ func TestLedgerActions_Index(t *testing.T) {
 	if ht.Assert.Equal(200, w.Code) {
 		ht.Assert.PageOf(1, w.Body)
 	}
+
+	// filtered by close time (ledger 2 closed at 1572527985000)
+	w = ht.Get("/ledgers?start_time=1572527985000")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(2, w.Body)
+	}
+
+	w = ht.Get("/ledgers?start_time=1572527985000&end_time=1572527986000")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+	}
+
+	w = ht.Get("/ledgers?start_time=1572527986000&end_time=1572527985000")
+	ht.Assert.Equal(400, w.Code)
 }
 
 func TestLedgerActions_Show(t *testing.T) {
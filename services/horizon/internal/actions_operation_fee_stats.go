@@ -5,7 +5,6 @@ import (
 
 	hProtocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/actions"
-	"github.com/stellar/go/services/horizon/internal/operationfeestats"
 	"github.com/stellar/go/support/render/httpjson"
 )
 
@@ -38,7 +37,7 @@ func (action *FeeStatsAction) JSON() error {
 }
 
 func (action *FeeStatsAction) loadRecords() {
-	cur, ok := operationfeestats.CurrentState()
+	cur, ok := action.App.feeStatsState.CurrentState()
 	action.feeStats.LastLedgerBaseFee = cur.LastBaseFee
 	action.feeStats.LastLedger = cur.LastLedger
 
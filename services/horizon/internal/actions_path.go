@@ -18,7 +18,10 @@ import (
 	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/support/render/httpjson"
 	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/support/tracing"
 	"github.com/stellar/go/xdr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // FindPathsHandler is the http handler for the find payment paths endpoint
@@ -116,13 +119,9 @@ var sourceAssetsOrSourceAccount = problem.P{
 // ServeHTTP implements the http.Handler interface
 func (handler FindPathsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	if handler.checkHistoryIsStale && isHistoryStale(handler.staleThreshold) {
-		ls := ledger.CurrentState()
-		err := hProblem.StaleHistory
-		err.Extras = map[string]interface{}{
-			"history_latest_ledger": ls.HistoryLatest,
-			"core_latest_ledger":    ls.CoreLatest,
-		}
+	ls := ledger.FromContext(ctx).CurrentState()
+	if handler.checkHistoryIsStale && isHistoryStale(ls, handler.staleThreshold) {
+		err := hProblem.NewStaleHistory(ls.HistoryLatest, ls.CoreLatest)
 		problem.Render(ctx, w, err)
 		return
 	}
@@ -141,7 +140,7 @@ func (handler FindPathsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	if len(query.SourceAssets) > handler.maxAssetsParamLength {
 		p := problem.MakeInvalidFieldProblem(
 			"source_assets",
-			fmt.Errorf("list of assets exceeds maximum length of %d", handler.maxPathLength),
+			fmt.Errorf("list of assets exceeds maximum length of %d", handler.maxAssetsParamLength),
 		)
 		problem.Render(ctx, w, p)
 		return
@@ -167,7 +166,12 @@ func (handler FindPathsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	records := []paths.Path{}
 	if len(query.SourceAssets) > 0 {
 		var lastIngestedLedger uint32
+		_, span := tracing.Start(ctx, "find paths", trace.SpanKindInternal)
+		span.SetAttributes(attribute.Int("source_assets", len(query.SourceAssets)))
 		records, lastIngestedLedger, err = handler.pathFinder.Find(query, handler.maxPathLength)
+		span.SetAttributes(attribute.Int("paths", len(records)))
+		tracing.SetError(span, err)
+		span.End()
 		if err == simplepath.ErrEmptyInMemoryOrderBook {
 			err = horizonProblem.StillIngesting
 		}
@@ -309,7 +313,7 @@ func (handler FindFixedPathsHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 	if len(destinationAssets) > handler.maxAssetsParamLength {
 		p := problem.MakeInvalidFieldProblem(
 			"destination_assets",
-			fmt.Errorf("list of assets exceeds maximum length of %d", handler.maxPathLength),
+			fmt.Errorf("list of assets exceeds maximum length of %d", handler.maxAssetsParamLength),
 		)
 		problem.Render(ctx, w, p)
 		return
@@ -329,12 +333,17 @@ func (handler FindFixedPathsHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 	records := []paths.Path{}
 	if len(destinationAssets) > 0 {
 		var lastIngestedLedger uint32
+		_, span := tracing.Start(ctx, "find fixed paths", trace.SpanKindInternal)
+		span.SetAttributes(attribute.Int("destination_assets", len(destinationAssets)))
 		records, lastIngestedLedger, err = handler.pathFinder.FindFixedPaths(
 			sourceAsset,
 			amountToSpend,
 			destinationAssets,
 			handler.maxPathLength,
 		)
+		span.SetAttributes(attribute.Int("paths", len(records)))
+		tracing.SetError(span, err)
+		span.End()
 		if err == simplepath.ErrEmptyInMemoryOrderBook {
 			err = horizonProblem.StillIngesting
 		}
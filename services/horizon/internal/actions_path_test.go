@@ -378,7 +378,7 @@ func TestPathActionsSourceAssetsValidation(t *testing.T) {
 			tooManySourceAssets,
 			*problem.MakeInvalidFieldProblem(
 				"source_assets",
-				fmt.Errorf("list of assets exceeds maximum length of 3"),
+				fmt.Errorf("list of assets exceeds maximum length of 2"),
 			),
 		},
 	} {
@@ -461,7 +461,7 @@ func TestPathActionsDestinationAssetsValidation(t *testing.T) {
 			tooManyDestinationAssets,
 			*problem.MakeInvalidFieldProblem(
 				"destination_assets",
-				fmt.Errorf("list of assets exceeds maximum length of 3"),
+				fmt.Errorf("list of assets exceeds maximum length of 2"),
 			),
 		},
 	} {
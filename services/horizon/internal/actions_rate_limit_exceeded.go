@@ -7,7 +7,8 @@ import (
 	"github.com/stellar/go/support/render/problem"
 )
 
-// RateLimitExceededAction renders a 429 response
+// RateLimitExceededAction renders a 429 response including the rate limit
+// of the request in the problem extras.
 type RateLimitExceededAction struct {
 	Action
 }
@@ -15,5 +16,10 @@ type RateLimitExceededAction struct {
 func (action RateLimitExceededAction) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ap := &action.Action
 	ap.Prepare(w, r)
-	problem.Render(action.R.Context(), action.W, hProblem.RateLimitExceeded)
+	if app := AppFromContext(r.Context()); app != nil {
+		app.web.rateLimitedMeter.Mark(1)
+	}
+	p := hProblem.RateLimitExceeded
+	p.Extras = rateLimitExtras(w.Header())
+	problem.Render(action.R.Context(), action.W, p)
 }
@@ -3,7 +3,6 @@ package horizon
 import (
 	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/actions"
-	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
 	"github.com/stellar/go/support/render/hal"
 )
@@ -30,13 +29,15 @@ func (action *RootAction) JSON() error {
 	resourceadapter.PopulateRoot(
 		action.R.Context(),
 		&res,
-		ledger.CurrentState(),
+		action.App.ledgerState.CurrentState(),
+		action.App.config.HistoryRetentionCount,
+		action.App.config.StaleThreshold,
 		action.App.horizonVersion,
 		coreInfo.coreVersion,
 		action.App.config.NetworkPassphrase,
 		coreInfo.currentProtocolVersion,
 		coreInfo.coreSupportedProtocolVersion,
-		action.App.config.FriendbotURL,
+		action.App.web.friendbotURL.get(),
 		templates,
 	)
 
@@ -51,7 +51,7 @@ func TestRootAction(t *testing.T) {
 			actual.Links.Accounts.Href,
 		)
 		ht.Assert.Equal(
-			"http://localhost/offers{?selling,buying,seller,cursor,limit,order}",
+			"http://localhost/offers{?selling,buying,seller,sort,cursor,limit,order}",
 			actual.Links.Offers.Href,
 		)
 
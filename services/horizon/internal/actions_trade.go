@@ -30,6 +30,7 @@ type TradeIndexAction struct {
 	HasCounterAssetFilter bool
 	OfferFilter           int64
 	AccountFilter         string
+	ClosedWithin          history.TimeRange
 	PagingParams          db2.PageQuery
 	Records               []history.Trade
 	Page                  hal.Page
@@ -80,6 +81,9 @@ func (action *TradeIndexAction) loadParams() {
 	action.CounterAssetFilter, action.HasCounterAssetFilter = action.MaybeGetAsset("counter_")
 	action.OfferFilter = action.GetInt64("offer_id")
 	action.AccountFilter = action.GetAddress("account_id")
+	if action.Err == nil {
+		action.ClosedWithin, action.Err = getTimeRange(action.R)
+	}
 
 	if (!action.HasBaseAssetFilter && action.HasCounterAssetFilter) ||
 		(action.HasBaseAssetFilter && !action.HasCounterAssetFilter) {
@@ -91,10 +95,6 @@ func (action *TradeIndexAction) loadParams() {
 func (action *TradeIndexAction) loadRecords() {
 	trades := action.HistoryQ().Trades()
 
-	if action.AccountFilter != "" {
-		trades.ForAccount(action.AccountFilter)
-	}
-
 	if action.HasBaseAssetFilter {
 
 		baseAssetId, err := action.HistoryQ().GetAssetID(action.BaseAssetFilter)
@@ -117,10 +117,18 @@ func (action *TradeIndexAction) loadRecords() {
 		}
 	}
 
+	// the account filter is applied after the asset pair filter because the
+	// latter replaces the query to select the trades in the pair's order.
+	if action.AccountFilter != "" {
+		trades = trades.ForAccount(action.AccountFilter)
+	}
+
 	if action.OfferFilter != 0 {
 		trades = trades.ForOffer(action.OfferFilter)
 	}
 
+	trades = trades.ClosedWithin(action.ClosedWithin)
+
 	err := trades.Page(action.PagingParams).Select(&action.Records)
 	if err != sql.ErrNoRows {
 		action.Err = err
@@ -184,7 +192,7 @@ func (action *TradeAggregateIndexAction) loadParams() {
 	if history.StrictResolutionFiltering {
 		if _, ok := history.AllowedResolutions[resolutionDuration]; !ok {
 			action.SetInvalidField("resolution", errors.New("illegal or missing resolution. "+
-				"allowed resolutions are: 1 minute (60000), 5 minutes (300000), 15 minutes (900000), 1 hour (3600000), "+
+				"allowed resolutions are: 5 seconds (5000), 15 seconds (15000), 1 minute (60000), 5 minutes (300000), 15 minutes (900000), 1 hour (3600000), "+
 				"1 day (86400000) and 1 week (604800000)"))
 		}
 	}
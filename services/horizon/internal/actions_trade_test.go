@@ -120,6 +120,37 @@ func TestTradeActions_Index(t *testing.T) {
 		ht.Assert.PageOf(2, w.Body)
 	}
 
+	// the account is either side of the trade
+	w = ht.Get("/accounts/GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2/trades")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.UnmarshalPage(w.Body, &records)
+		for _, record := range records {
+			ht.Assert.Contains(
+				[]string{record.BaseAccount, record.CounterAccount},
+				"GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2",
+			)
+		}
+	}
+
+	// for an account and an asset pair
+	q = make(url.Values)
+	q.Add("base_asset_type", "credit_alphanum4")
+	q.Add("base_asset_code", "EUR")
+	q.Add("base_asset_issuer", "GCQPYGH4K57XBDENKKX55KDTWOTK5WDWRQOH2LHEDX3EKVIQRLMESGBG")
+	q.Add("counter_asset_type", "credit_alphanum4")
+	q.Add("counter_asset_code", "USD")
+	q.Add("counter_asset_issuer", "GC23QF2HUE52AMXUFUH3AYJAXXGXXV2VHXYYR6EYXETPKDXZSAW67XO4")
+
+	w = ht.GetWithParams("/accounts/GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2/trades", q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(2, w.Body)
+	}
+
+	w = ht.GetWithParams("/accounts/GCQPYGH4K57XBDENKKX55KDTWOTK5WDWRQOH2LHEDX3EKVIQRLMESGBG/trades", q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(0, w.Body)
+	}
+
 	// for other account
 	w = ht.Get("/accounts/GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU/trades")
 	if ht.Assert.Equal(200, w.Code) {
@@ -130,6 +161,29 @@ func TestTradeActions_Index(t *testing.T) {
 		ht.Assert.Contains(records[0], "counter_amount")
 	}
 
+	// for an account, an asset pair and a time range
+	q.Set("start_time", "1559579783000")
+	w = ht.GetWithParams("/accounts/GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2/trades", q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+		ht.UnmarshalPage(w.Body, &records)
+		ht.Assert.Equal("47244644353-0", records[0].PT)
+	}
+
+	q.Set("end_time", "1559579784000")
+	w = ht.GetWithParams("/accounts/GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2/trades", q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(0, w.Body)
+	}
+
+	w = ht.Get("/trades?start_time=1559579782000&end_time=1559579785000")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(2, w.Body)
+	}
+
+	w = ht.Get("/trades?start_time=1559579784000&end_time=1559579782000")
+	ht.Assert.Equal(400, w.Code)
+
 	//test paging from account 1
 	w = ht.Get("/accounts/GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2/trades?order=desc&limit=1")
 	var links hal.Links
@@ -479,6 +533,170 @@ func TestTradeActions_AggregationOrdering(t *testing.T) {
 	}
 }
 
+// TestTradeActions_AggregationRollups checks the sub-minute resolutions, which
+// are served from the trade rollups maintained during ingestion.
+func TestTradeActions_AggregationRollups(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	const start = int64(1510693200000)
+	const second = int64(time.Second / time.Millisecond)
+
+	seller := GetTestAccount()
+	buyer := GetTestAccount()
+	ass1 := GetTestAsset("usd")
+	ass2 := GetTestAsset("euro")
+
+	dbQ := &Q{ht.HorizonSession()}
+	for i, amountBought := range []int64{2, 4, 3} {
+		timestamp := stellarTime.MillisFromInt64(start + int64(i)*5*second)
+		err := IngestTestTrade(dbQ, ass1, ass2, seller, buyer, 1, amountBought, timestamp, int64(i+1))
+		ht.Require.NoError(err)
+	}
+
+	q := make(url.Values)
+	setAssetQuery(&q, "base_", ass1)
+	setAssetQuery(&q, "counter_", ass2)
+	q.Add("start_time", strconv.FormatInt(start, 10))
+	q.Add("end_time", strconv.FormatInt(start+minute, 10))
+	q.Add("order", "asc")
+
+	var records []horizon.TradeAggregation
+	q.Set("resolution", strconv.FormatInt(5*second, 10))
+	w := ht.GetWithParams(aggregationPath, q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(3, w.Body)
+		ht.UnmarshalPage(w.Body, &records)
+		ht.Assert.Equal(start+5*second, records[1].Timestamp)
+		ht.Assert.Equal("4.0000000", records[1].Close)
+	}
+
+	q.Set("resolution", strconv.FormatInt(15*second, 10))
+	w = ht.GetWithParams(aggregationPath, q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+		ht.UnmarshalPage(w.Body, &records)
+		testTradeAggregationPrices(ht, records[0])
+		ht.Assert.Equal(start, records[0].Timestamp)
+		ht.Assert.Equal(int64(3), records[0].TradeCount)
+		ht.Assert.Equal("0.0000003", records[0].BaseVolume)
+		ht.Assert.Equal("0.0000009", records[0].CounterVolume)
+		ht.Assert.Equal("3.0000000", records[0].Average)
+		ht.Assert.Equal("4.0000000", records[0].High)
+		ht.Assert.Equal("2.0000000", records[0].Low)
+		ht.Assert.Equal("2.0000000", records[0].Open)
+		ht.Assert.Equal("3.0000000", records[0].Close)
+	}
+
+	// the reversed asset pair inverts the prices
+	unsetAssetQuery(&q, "base_")
+	unsetAssetQuery(&q, "counter_")
+	setAssetQuery(&q, "base_", ass2)
+	setAssetQuery(&q, "counter_", ass1)
+	w = ht.GetWithParams(aggregationPath, q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+		ht.UnmarshalPage(w.Body, &records)
+		testTradeAggregationPrices(ht, records[0])
+		ht.Assert.Equal("0.0000009", records[0].BaseVolume)
+		ht.Assert.Equal("0.0000003", records[0].CounterVolume)
+		ht.Assert.Equal("0.5000000", records[0].High)
+		ht.Assert.Equal("0.2500000", records[0].Low)
+		ht.Assert.Equal("0.5000000", records[0].Open)
+		ht.Assert.Equal("0.3333333", records[0].Close)
+	}
+
+	// rebuilding the rollups picks up removed trades
+	_, err := dbQ.ExecRaw("DELETE FROM history_trades WHERE history_operation_id = 3")
+	ht.Require.NoError(err)
+	err = dbQ.RebuildTradeRollups(
+		stellarTime.MillisFromInt64(start).ToTime(),
+		stellarTime.MillisFromInt64(start+10*second).ToTime(),
+	)
+	ht.Require.NoError(err)
+
+	w = ht.GetWithParams(aggregationPath, q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+		ht.UnmarshalPage(w.Body, &records)
+		ht.Assert.Equal(int64(2), records[0].TradeCount)
+		ht.Assert.Equal("0.2500000", records[0].Close)
+	}
+
+	q.Set("resolution", strconv.FormatInt(5*second, 10))
+	w = ht.GetWithParams(aggregationPath, q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(2, w.Body)
+	}
+}
+
+// TestTradeActions_AggregationRollupsConcurrentRebuilds checks that
+// transactions rebuilding overlapping ranges of rollups, like parallel
+// reingestion workers, neither fail nor lose buckets.
+func TestTradeActions_AggregationRollupsConcurrentRebuilds(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	const start = int64(1510693200000)
+	const second = int64(time.Second / time.Millisecond)
+
+	seller := GetTestAccount()
+	buyer := GetTestAccount()
+	ass1 := GetTestAsset("usd")
+	ass2 := GetTestAsset("euro")
+
+	dbQ := &Q{ht.HorizonSession()}
+	for i, amountBought := range []int64{2, 4, 3} {
+		timestamp := stellarTime.MillisFromInt64(start + int64(i)*5*second)
+		err := IngestTestTrade(dbQ, ass1, ass2, seller, buyer, 1, amountBought, timestamp, int64(i+1))
+		ht.Require.NoError(err)
+	}
+
+	worker1 := &Q{ht.HorizonSession()}
+	ht.Require.NoError(worker1.Begin())
+	defer worker1.Rollback()
+	worker2 := &Q{ht.HorizonSession()}
+	ht.Require.NoError(worker2.Begin())
+	defer worker2.Rollback()
+
+	from := stellarTime.MillisFromInt64(start).ToTime()
+	ht.Require.NoError(worker1.RebuildTradeRollups(from, from.Add(5*time.Second)))
+
+	// the second rebuild waits for the buckets locked by the first one
+	done := make(chan error, 1)
+	go func() {
+		done <- worker2.RebuildTradeRollups(from.Add(5*time.Second), from.Add(10*time.Second))
+	}()
+	for waiting := 0; waiting == 0; time.Sleep(10 * time.Millisecond) {
+		ht.Require.NoError(dbQ.GetRaw(&waiting, "SELECT count(*) FROM pg_locks WHERE NOT granted"))
+	}
+	ht.Require.NoError(worker1.Commit())
+	ht.Require.NoError(<-done)
+	ht.Require.NoError(worker2.Commit())
+
+	q := make(url.Values)
+	setAssetQuery(&q, "base_", ass1)
+	setAssetQuery(&q, "counter_", ass2)
+	q.Add("start_time", strconv.FormatInt(start, 10))
+	q.Add("end_time", strconv.FormatInt(start+60*second, 10))
+	q.Add("resolution", strconv.FormatInt(15*second, 10))
+
+	var records []horizon.TradeAggregation
+	w := ht.GetWithParams(aggregationPath, q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+		ht.UnmarshalPage(w.Body, &records)
+		ht.Assert.Equal(int64(3), records[0].TradeCount)
+		ht.Assert.Equal("3.0000000", records[0].Close)
+	}
+
+	q.Set("resolution", strconv.FormatInt(5*second, 10))
+	w = ht.GetWithParams(aggregationPath, q)
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(3, w.Body)
+	}
+}
+
 func assertOfferType(ht *HTTPT, offerId string, idType OfferIDType) {
 	offerIdInt64, _ := strconv.ParseInt(offerId, 10, 64)
 	_, offerType := DecodeOfferID(offerIdInt64)
@@ -2,6 +2,7 @@ package horizon
 
 import (
 	"encoding/hex"
+	"math"
 	"net/http"
 
 	"github.com/stellar/go/network"
@@ -53,6 +54,8 @@ type TransactionCreateAction struct {
 func (action *TransactionCreateAction) JSON() error {
 	action.Do(
 		action.loadTX,
+		action.checkMemoRequired,
+		action.checkFee,
 		action.loadResult,
 		action.loadResource,
 		func() { hal.Render(action.W, action.Resource) },
@@ -84,6 +87,138 @@ func (action *TransactionCreateAction) loadTX() {
 	}
 }
 
+// checkMemoRequired rejects memo-less transactions sending funds to accounts
+// which require a memo by setting the `config.memo_required` data entry to
+// "1", see SEP-29. The check is only done when enabled in the config.
+func (action *TransactionCreateAction) checkMemoRequired() {
+	if !action.App.config.CheckMemoRequired ||
+		action.TX.parsed.Memo().Type != xdr.MemoTypeMemoNone {
+		return
+	}
+
+	destinations := map[string]int{}
+	var keys []xdr.LedgerKeyData
+	for i, op := range action.TX.parsed.Operations() {
+		var destination xdr.MuxedAccount
+		switch op.Body.Type {
+		case xdr.OperationTypePayment:
+			destination = op.Body.MustPaymentOp().Destination
+		case xdr.OperationTypePathPaymentStrictReceive:
+			destination = op.Body.MustPathPaymentStrictReceiveOp().Destination
+		case xdr.OperationTypePathPaymentStrictSend:
+			destination = op.Body.MustPathPaymentStrictSendOp().Destination
+		case xdr.OperationTypeAccountMerge:
+			destination = op.Body.MustDestination()
+		default:
+			continue
+		}
+
+		// muxed accounts carry their own identifier of the recipient
+		if destination.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+			continue
+		}
+
+		address := destination.Address()
+		if _, ok := destinations[address]; ok {
+			continue
+		}
+		destinations[address] = i
+		keys = append(keys, xdr.LedgerKeyData{
+			AccountId: destination.ToAccountId(),
+			DataName:  memoRequiredDataName,
+		})
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	data, err := action.HistoryQ().GetAccountDataByKeys(keys)
+	if err != nil {
+		action.Err = err
+		return
+	}
+
+	memoRequired := map[string]bool{}
+	for _, entry := range data {
+		memoRequired[entry.AccountID] = string(entry.Value) == "1"
+	}
+
+	// report the first operation sending to an account requiring a memo
+	for _, key := range keys {
+		accountID := key.AccountId.Address()
+		if !memoRequired[accountID] {
+			continue
+		}
+		action.Err = &problem.P{
+			Type:   "transaction_memo_required",
+			Title:  "Memo Required",
+			Status: http.StatusBadRequest,
+			Detail: "The transaction sends funds to an account which requires a memo, " +
+				"as set in its `config.memo_required` data entry, but the transaction " +
+				"has no memo. The account and the index of the operation are included " +
+				"in the `extras` field of this response. See: " +
+				"https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0029.md",
+			Extras: map[string]interface{}{
+				"envelope_xdr":    action.TX.raw,
+				"account_id":      accountID,
+				"operation_index": destinations[accountID],
+			},
+		}
+		return
+	}
+}
+
+// checkFee rejects transactions offering a fee per operation below the
+// minimum fee charged per operation in the recent ledgers, which would most
+// likely not be included in a ledger before timing out. The check is only done
+// when enabled in the config and once fee stats are available.
+func (action *TransactionCreateAction) checkFee() {
+	if !action.App.config.RejectLowFeeTransactions {
+		return
+	}
+	feeStats, ok := action.App.feeStatsState.CurrentState()
+	if !ok {
+		return
+	}
+
+	minFee := feeStats.FeeChargedMin
+	if minFee < feeStats.LastBaseFee {
+		minFee = feeStats.LastBaseFee
+	}
+
+	// fee bump transactions pay for the inner operations and for themselves
+	operations := int64(len(action.TX.parsed.Operations()))
+	fee := int64(action.TX.parsed.Fee())
+	if action.TX.parsed.IsFeeBump() {
+		operations++
+		fee = action.TX.parsed.FeeBumpFee()
+	}
+	if operations == 0 || fee >= minFee*operations {
+		return
+	}
+
+	action.Err = &problem.P{
+		Type:   "transaction_fee_too_low",
+		Title:  "Transaction Fee Too Low",
+		Status: http.StatusBadRequest,
+		Detail: "The fee of the transaction is below the minimum fee per operation " +
+			"charged in the recent ledgers, so it would most likely not be included " +
+			"in a ledger. Resubmit the transaction with at least the fee given in " +
+			"the `extras.suggested_fee` field of this response.",
+		Extras: map[string]interface{}{
+			"envelope_xdr":          action.TX.raw,
+			"fee":                   fee,
+			"min_fee_per_operation": minFee,
+			"suggested_fee":         minFee * operations,
+			"last_ledger":           feeStats.LastLedger,
+		},
+	}
+}
+
+// memoRequiredDataName is the name of the data entry used by accounts to
+// require a memo on incoming payments, see SEP-29.
+const memoRequiredDataName = "config.memo_required"
+
 func (action *TransactionCreateAction) loadResult() {
 	submission := action.App.submitter.Submit(
 		action.R.Context(),
@@ -122,6 +257,13 @@ func (action *TransactionCreateAction) loadResource() {
 	}
 
 	switch err := action.Result.Err.(type) {
+	case *txsub.CoreUnavailableError:
+		p := hProblem.NewStellarCoreUnavailable(
+			err.CoreState,
+			err.Reason,
+			int(math.Ceil(err.RetryAfter.Seconds())),
+		)
+		action.Err = &p
 	case *txsub.FailedTransactionError:
 		rcr := horizon.TransactionResultCodes{}
 		resourceadapter.PopulateTransactionResultCodes(
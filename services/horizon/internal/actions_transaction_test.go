@@ -2,6 +2,7 @@ package horizon
 
 import (
 	"encoding/json"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/expingest"
+	"github.com/stellar/go/services/horizon/internal/operationfeestats"
 	"github.com/stellar/go/services/horizon/internal/test"
 	"github.com/stellar/go/services/horizon/internal/txsub"
 	"github.com/stellar/go/services/horizon/internal/txsub/sequence"
@@ -193,6 +195,23 @@ func TestTransactionActions_Index(t *testing.T) {
 	w = ht.Get("/ledgers/100/transactions")
 	ht.Assert.Equal(404, w.Code)
 
+	// filtered by ledger close time (ledger 2 closed at 1572527985000)
+	w = ht.Get("/transactions?start_time=1572527985000&end_time=1572527986000")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(3, w.Body)
+	}
+
+	w = ht.Get("/transactions?start_time=1572527986000")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+	}
+
+	w = ht.Get("/transactions?start_time=1572527986000&end_time=1572527985000")
+	ht.Assert.Equal(400, w.Code)
+
+	w = ht.Get("/transactions?start_time=yesterday")
+	ht.Assert.Equal(400, w.Code)
+
 	// Makes StateMiddleware happy
 	q := history.Q{ht.HorizonSession()}
 	err := q.UpdateLastLedgerExpIngest(100)
@@ -339,6 +358,147 @@ func TestTransactionActions_PostSuccessful(t *testing.T) {
 	ht.Assert.Contains(string(w.Body.Bytes()), `"result_xdr": "AAAAAAAAAGQAAAAAAAAAAQAAAAAAAAABAAAAAAAAAAA="`)
 }
 
+func TestTransactionActions_PostMemoRequired(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+	ht.App.config.CheckMemoRequired = true
+
+	destination := xdr.MustAddress("GBXGQJWVLWOYHFLVTKWV5FGHA3LNYY2JQKM7OAJAUEQFU6LPCSEFVXON")
+	q := &history.Q{Session: ht.HorizonSession()}
+	_, err := q.InsertAccountData(xdr.DataEntry{
+		AccountId: destination,
+		DataName:  "config.memo_required",
+		DataValue: xdr.DataValue("1"),
+	}, 1)
+	ht.Require.NoError(err)
+
+	payment := xdr.Operation{
+		Body: xdr.OperationBody{
+			Type: xdr.OperationTypePayment,
+			PaymentOp: &xdr.PaymentOp{
+				Destination: destination.ToMuxedAccount(),
+				Asset:       xdr.MustNewNativeAsset(),
+				Amount:      1000000000,
+			},
+		},
+	}
+	tx := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxV0,
+		V0: &xdr.TransactionV0Envelope{
+			Tx: xdr.TransactionV0{
+				SourceAccountEd25519: *xdr.MustAddress("GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU").Ed25519,
+				Fee:                  200,
+				SeqNum:               8589934593,
+				Operations: []xdr.Operation{
+					{
+						Body: xdr.OperationBody{
+							Type:           xdr.OperationTypeBumpSequence,
+							BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 1},
+						},
+					},
+					payment,
+				},
+			},
+		},
+	}
+
+	txStr, err := xdr.MarshalBase64(tx)
+	ht.Require.NoError(err)
+	w := ht.Post("/transactions", url.Values{"tx": []string{txStr}})
+	if ht.Assert.Equal(400, w.Code) {
+		extras := ht.UnmarshalExtras(w.Body)
+		ht.Assert.Equal(destination.Address(), extras["account_id"])
+		ht.Assert.Equal(float64(1), extras["operation_index"])
+	}
+
+	// transactions passing the check reach the submission system
+	ht.App.submitter.Results = &txsub.MockResultProvider{
+		Results: []txsub.Result{
+			{Err: sequence.ErrNoMoreRoom},
+			{Err: sequence.ErrNoMoreRoom},
+		},
+	}
+
+	// a memo satisfies the requirement
+	memoText := "exchange-user-1"
+	tx.V0.Tx.Memo = xdr.Memo{Type: xdr.MemoTypeMemoText, Text: &memoText}
+	txStr, err = xdr.MarshalBase64(tx)
+	ht.Require.NoError(err)
+	w = ht.Post("/transactions", url.Values{"tx": []string{txStr}})
+	ht.Assert.Equal(503, w.Code)
+
+	// the check is disabled by default
+	ht.App.config.CheckMemoRequired = false
+	tx.V0.Tx.Memo = xdr.Memo{}
+	txStr, err = xdr.MarshalBase64(tx)
+	ht.Require.NoError(err)
+	w = ht.Post("/transactions", url.Values{"tx": []string{txStr}})
+	ht.Assert.Equal(503, w.Code)
+}
+
+func TestTransactionActions_PostLowFee(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+	ht.App.config.RejectLowFeeTransactions = true
+
+	tx := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxV0,
+		V0: &xdr.TransactionV0Envelope{
+			Tx: xdr.TransactionV0{
+				SourceAccountEd25519: *xdr.MustAddress("GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU").Ed25519,
+				Fee:                  200,
+				SeqNum:               8589934593,
+				Operations: []xdr.Operation{
+					{
+						Body: xdr.OperationBody{
+							Type:           xdr.OperationTypeBumpSequence,
+							BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 1},
+						},
+					},
+					{
+						Body: xdr.OperationBody{
+							Type:           xdr.OperationTypeBumpSequence,
+							BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 2},
+						},
+					},
+				},
+			},
+		},
+	}
+	post := func() *httptest.ResponseRecorder {
+		txStr, err := xdr.MarshalBase64(tx)
+		ht.Require.NoError(err)
+		return ht.Post("/transactions", url.Values{"tx": []string{txStr}})
+	}
+	ht.App.submitter.Results = &txsub.MockResultProvider{
+		Results: []txsub.Result{
+			{Err: sequence.ErrNoMoreRoom},
+			{Err: sequence.ErrNoMoreRoom},
+		},
+	}
+
+	// without fee stats the check is skipped
+	w := post()
+	ht.Assert.Equal(503, w.Code)
+
+	ht.App.feeStatsState.SetState(operationfeestats.State{
+		FeeChargedMin: 300,
+		LastBaseFee:   100,
+		LastLedger:    3,
+	})
+	w = post()
+	if ht.Assert.Equal(400, w.Code) {
+		extras := ht.UnmarshalExtras(w.Body)
+		ht.Assert.Equal(float64(200), extras["fee"])
+		ht.Assert.Equal(float64(300), extras["min_fee_per_operation"])
+		ht.Assert.Equal(float64(600), extras["suggested_fee"])
+	}
+
+	tx.V0.Tx.Fee = 600
+	w = post()
+	ht.Assert.Equal(503, w.Code)
+}
+
 func TestTransactionActions_PostFailed(t *testing.T) {
 	ht := StartHTTPTest(t, "failed_transactions")
 	defer ht.Finish()
@@ -352,6 +512,35 @@ func TestTransactionActions_PostFailed(t *testing.T) {
 	ht.Assert.Contains(string(w.Body.Bytes()), `"result_xdr": "AAAAAAAAAGT/////AAAAAQAAAAAAAAAB/////gAAAAA="`)
 }
 
+func TestTransactionActions_ShowFeeBump(t *testing.T) {
+	ht := StartHTTPTestWithoutScenario(t)
+	defer ht.Finish()
+	test.ResetHorizonDB(t, ht.HorizonDB)
+	q := &history.Q{ht.HorizonSession()}
+	fixture := history.FeeBumpScenario(ht.T, q, true)
+
+	var byOuterHash, byInnerHash horizon.Transaction
+	w := ht.Get("/transactions/" + fixture.OuterHash)
+	ht.Assert.Equal(200, w.Code)
+	ht.Require.NoError(json.Unmarshal(w.Body.Bytes(), &byOuterHash))
+
+	w = ht.Get("/transactions/" + fixture.InnerHash)
+	ht.Assert.Equal(200, w.Code)
+	ht.Require.NoError(json.Unmarshal(w.Body.Bytes(), &byInnerHash))
+
+	ht.Assert.Equal(fixture.OuterHash, byOuterHash.Hash)
+	ht.Assert.Equal(fixture.InnerHash, byInnerHash.Hash)
+	for _, tx := range []horizon.Transaction{byOuterHash, byInnerHash} {
+		ht.Require.NotNil(tx.FeeBumpTransaction)
+		ht.Require.NotNil(tx.InnerTransaction)
+		ht.Assert.Equal(fixture.OuterHash, tx.FeeBumpTransaction.Hash)
+		ht.Assert.Equal(fixture.Transaction.FeeAccount.String, tx.FeeBumpTransaction.FeeAccount)
+		ht.Assert.Equal(fixture.Transaction.NewMaxFee.Int64, tx.FeeBumpTransaction.MaxFee)
+		ht.Assert.Equal(fixture.InnerHash, tx.InnerTransaction.Hash)
+		ht.Assert.Equal(fixture.Transaction.MaxFee, tx.InnerTransaction.MaxFee)
+	}
+}
+
 func TestPostFeeBumpTransaction(t *testing.T) {
 	ht := StartHTTPTestWithoutScenario(t)
 	defer ht.Finish()
@@ -0,0 +1,298 @@
+package horizon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/jobs"
+	"github.com/stellar/go/services/horizon/internal/reap"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/httpjson"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// Types of the background jobs which can be submitted on the admin port.
+const (
+	reingestRangeJob            = "reingest_range"
+	rebuildTradeAggregationsJob = "rebuild_trade_aggregations"
+	reapHistoryJob              = "reap_history"
+)
+
+// maxListedJobs is the number of jobs returned by the jobs list endpoint.
+const maxListedJobs = 100
+
+// ReingestRangeJobParams are the parameters of the reingest_range jobs.
+type ReingestRangeJobParams struct {
+	From  uint32 `json:"from"`
+	To    uint32 `json:"to"`
+	Force bool   `json:"force"`
+}
+
+// RebuildTradeAggregationsJobParams are the parameters of the
+// rebuild_trade_aggregations jobs.
+type RebuildTradeAggregationsJobParams struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// ReapHistoryJobParams are the parameters of the reap_history jobs. The
+// configured --history-retention-count is used when RetentionCount is 0.
+type ReapHistoryJobParams struct {
+	RetentionCount uint `json:"retention_count"`
+}
+
+// JobRequest is the body of the requests submitting a job.
+type JobRequest struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params"`
+}
+
+// JobStatus is the response of the admin jobs endpoints.
+type JobStatus struct {
+	ID              int64           `json:"id"`
+	Type            string          `json:"type"`
+	Params          json.RawMessage `json:"params"`
+	Status          string          `json:"status"`
+	Progress        string          `json:"progress,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	CancelRequested bool            `json:"cancel_requested"`
+	CreatedAt       time.Time       `json:"created_at"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	HeartbeatAt     *time.Time      `json:"heartbeat_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+func newJobStatus(job history.Job) JobStatus {
+	status := JobStatus{
+		ID:              job.ID,
+		Type:            job.Type,
+		Params:          json.RawMessage(job.Params),
+		Status:          job.Status,
+		Progress:        job.Progress,
+		Error:           job.Error.String,
+		CancelRequested: job.CancelRequested,
+		CreatedAt:       job.CreatedAt,
+	}
+	if job.StartedAt.Valid {
+		status.StartedAt = &job.StartedAt.Time
+	}
+	if job.HeartbeatAt.Valid {
+		status.HeartbeatAt = &job.HeartbeatAt.Time
+	}
+	if job.FinishedAt.Valid {
+		status.FinishedAt = &job.FinishedAt.Time
+	}
+	return status
+}
+
+// initJobs initializes the background jobs subsystem and registers the job
+// types this instance can run. Range reingestion needs the history archives.
+func initJobs(app *App) {
+	app.jobs = jobs.New(app.HorizonSession(context.Background()))
+	if len(app.config.HistoryArchiveURLs) > 0 && app.config.HistoryArchiveURLs[0] != "" {
+		app.jobs.Register(reingestRangeJob, app.runReingestRangeJob)
+	}
+	app.jobs.Register(rebuildTradeAggregationsJob, app.runRebuildTradeAggregationsJob)
+	app.jobs.Register(reapHistoryJob, app.runReapHistoryJob)
+}
+
+// decodeJobParams decodes and validates the parameters of a job of type
+// `jobType`.
+func decodeJobParams(jobType string, raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+
+	switch jobType {
+	case reingestRangeJob:
+		var params ReingestRangeJobParams
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		if params.From == 0 {
+			return nil, errors.New("from must be greater than 0")
+		}
+		if params.To < params.From {
+			return nil, errors.New("to must be greater or equal to from")
+		}
+		return params, nil
+	case rebuildTradeAggregationsJob:
+		var params RebuildTradeAggregationsJobParams
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		if params.From.IsZero() || params.To.IsZero() {
+			return nil, errors.New("from and to must be set")
+		}
+		if params.To.Before(params.From) {
+			return nil, errors.New("to must be after from")
+		}
+		return params, nil
+	case reapHistoryJob:
+		var params ReapHistoryJobParams
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		return params, nil
+	}
+	return nil, jobs.ErrUnknownType
+}
+
+// runReingestRangeJob reingests a range of ledgers like `horizon db reingest
+// range`.
+func (a *App) runReingestRangeJob(ctx context.Context, rawParams string, progress func(string)) error {
+	params, err := decodeJobParams(reingestRangeJob, json.RawMessage(rawParams))
+	if err != nil {
+		return err
+	}
+	reingest := params.(ReingestRangeJobParams)
+
+	system, closeSystem, err := newExpIngestSystem(a)
+	if err != nil {
+		return err
+	}
+	defer closeSystem()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			system.Shutdown()
+		case <-done:
+		}
+	}()
+
+	progress(fmt.Sprintf("reingesting ledgers %d to %d", reingest.From, reingest.To))
+	return system.ReingestRange(reingest.From, reingest.To, reingest.Force)
+}
+
+// runRebuildTradeAggregationsJob rebuilds the trade aggregation buckets of a
+// time range, one day at a time.
+func (a *App) runRebuildTradeAggregationsJob(ctx context.Context, rawParams string, progress func(string)) error {
+	params, err := decodeJobParams(rebuildTradeAggregationsJob, json.RawMessage(rawParams))
+	if err != nil {
+		return err
+	}
+	rebuild := params.(RebuildTradeAggregationsJobParams)
+
+	q := &history.Q{a.HorizonSession(ctx)}
+	for from := rebuild.From; !from.After(rebuild.To); from = from.Add(24 * time.Hour) {
+		to := from.Add(24*time.Hour - time.Second)
+		if to.After(rebuild.To) {
+			to = rebuild.To
+		}
+		if err := q.RebuildTradeRollups(from, to); err != nil {
+			return err
+		}
+		progress("rebuilt trade aggregations up to " + to.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// runReapHistoryJob deletes the history of the ledgers which are not
+// retained, like `horizon db reap`.
+func (a *App) runReapHistoryJob(ctx context.Context, rawParams string, progress func(string)) error {
+	params, err := decodeJobParams(reapHistoryJob, json.RawMessage(rawParams))
+	if err != nil {
+		return err
+	}
+	retentionCount := params.(ReapHistoryJobParams).RetentionCount
+	if retentionCount == 0 {
+		retentionCount = a.config.HistoryRetentionCount
+	}
+	if retentionCount == 0 {
+		return errors.New("retention_count must be set when --history-retention-count is not")
+	}
+
+	reaper := reap.New(retentionCount, a.HorizonSession(ctx))
+	reaper.LedgerState = a.ledgerState
+	reaper.BatchSize = a.config.HistoryRetentionReapBatchSize
+	reaper.BatchDelay = a.config.HistoryRetentionReapBatchDelay
+	progress(fmt.Sprintf("reaping the history older than %d ledgers", retentionCount))
+	return reaper.DeleteUnretainedHistory()
+}
+
+// jobsHandler lists the latest jobs, the most recent first.
+func (a *App) jobsHandler(w http.ResponseWriter, r *http.Request) {
+	list, err := a.jobs.HistoryQ.GetJobs(maxListedJobs)
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	statuses := make([]JobStatus, 0, len(list))
+	for _, job := range list {
+		statuses = append(statuses, newJobStatus(job))
+	}
+	httpjson.Render(w, statuses, httpjson.JSON)
+}
+
+// submitJobHandler submits the job described in the request body. It is run
+// by the first instance able to run jobs of its type.
+func (a *App) submitJobHandler(w http.ResponseWriter, r *http.Request) {
+	var request JobRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		problem.Render(r.Context(), w, problem.MakeInvalidFieldProblem("body", err))
+		return
+	}
+
+	params, err := decodeJobParams(request.Type, request.Params)
+	if err == jobs.ErrUnknownType {
+		problem.Render(r.Context(), w, problem.MakeInvalidFieldProblem("type", err))
+		return
+	} else if err != nil {
+		problem.Render(r.Context(), w, problem.MakeInvalidFieldProblem("params", err))
+		return
+	}
+
+	job, err := a.jobs.Submit(request.Type, params)
+	if err == jobs.ErrUnknownType {
+		// the job type is known but this instance is not configured to run
+		// it
+		problem.Render(r.Context(), w, ingestionDisabled)
+		return
+	} else if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+	httpjson.RenderStatus(w, http.StatusAccepted, newJobStatus(job), httpjson.JSON)
+}
+
+func (a *App) jobHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		problem.Render(r.Context(), w, problem.NotFound)
+		return
+	}
+
+	job, err := a.jobs.HistoryQ.GetJob(id)
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+	httpjson.Render(w, newJobStatus(job), httpjson.JSON)
+}
+
+// cancelJobHandler requests the cancellation of a job. Pending jobs are
+// cancelled immediately, running jobs within a few seconds.
+func (a *App) cancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		problem.Render(r.Context(), w, problem.NotFound)
+		return
+	}
+
+	job, err := a.jobs.HistoryQ.RequestJobCancellation(id, time.Now())
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+	httpjson.Render(w, newJobStatus(job), httpjson.JSON)
+}
@@ -0,0 +1,43 @@
+package horizon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJobParams(t *testing.T) {
+	params, err := decodeJobParams(reingestRangeJob, json.RawMessage(`{"from": 2, "to": 10, "force": true}`))
+	require.NoError(t, err)
+	assert.Equal(t, ReingestRangeJobParams{From: 2, To: 10, Force: true}, params)
+
+	_, err = decodeJobParams(reingestRangeJob, json.RawMessage(`{"from": 10, "to": 2}`))
+	assert.EqualError(t, err, "to must be greater or equal to from")
+	_, err = decodeJobParams(reingestRangeJob, nil)
+	assert.EqualError(t, err, "from must be greater than 0")
+
+	params, err = decodeJobParams(rebuildTradeAggregationsJob, json.RawMessage(
+		`{"from": "2020-06-01T00:00:00Z", "to": "2020-06-03T00:00:00Z"}`,
+	))
+	require.NoError(t, err)
+	assert.Equal(t, RebuildTradeAggregationsJobParams{
+		From: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2020, 6, 3, 0, 0, 0, 0, time.UTC),
+	}, params)
+	_, err = decodeJobParams(rebuildTradeAggregationsJob, json.RawMessage(`{"from": "2020-06-01T00:00:00Z"}`))
+	assert.EqualError(t, err, "from and to must be set")
+
+	params, err = decodeJobParams(reapHistoryJob, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ReapHistoryJobParams{}, params)
+
+	_, err = decodeJobParams(reapHistoryJob, json.RawMessage(`{"retention_count": "all"}`))
+	assert.Error(t, err)
+
+	_, err = decodeJobParams("unknown", nil)
+	assert.Equal(t, jobs.ErrUnknownType, err)
+}
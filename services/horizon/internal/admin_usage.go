@@ -0,0 +1,135 @@
+package horizon
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/usage"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/httpjson"
+	"github.com/stellar/go/support/render/problem"
+)
+
+const (
+	// defaultUsageLimit is the default number of tenants returned by the
+	// usage endpoint.
+	defaultUsageLimit = 20
+	// maxUsageLimit is the maximum number of tenants returned by the usage
+	// endpoint.
+	maxUsageLimit = 200
+	// topEndpointsLimit is the number of endpoints returned per tenant.
+	topEndpointsLimit = 5
+)
+
+// UsageReport is the response of the admin usage endpoint.
+type UsageReport struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Tenants []TenantReport `json:"tenants"`
+}
+
+// TenantReport is the usage of a tenant, an API key or a remote ip address, in
+// a UsageReport.
+type TenantReport struct {
+	Tenant       string          `json:"tenant"`
+	Requests     int64           `json:"requests"`
+	Bytes        int64           `json:"bytes"`
+	RateLimited  int64           `json:"rate_limited"`
+	TopEndpoints []EndpointUsage `json:"top_endpoints"`
+}
+
+// EndpointUsage is the number of requests of an endpoint, its method and its
+// route pattern.
+type EndpointUsage struct {
+	Endpoint string `json:"endpoint"`
+	Requests int64  `json:"requests"`
+}
+
+func newTenantReport(tenant history.TenantUsage) TenantReport {
+	endpoints := make([]EndpointUsage, 0, len(tenant.Endpoints))
+	for endpoint, requests := range tenant.Endpoints {
+		endpoints = append(endpoints, EndpointUsage{Endpoint: endpoint, Requests: requests})
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Requests != endpoints[j].Requests {
+			return endpoints[i].Requests > endpoints[j].Requests
+		}
+		return endpoints[i].Endpoint < endpoints[j].Endpoint
+	})
+	if len(endpoints) > topEndpointsLimit {
+		endpoints = endpoints[:topEndpointsLimit]
+	}
+
+	return TenantReport{
+		Tenant:       tenant.Tenant,
+		Requests:     tenant.Requests,
+		Bytes:        tenant.Bytes,
+		RateLimited:  tenant.RateLimited,
+		TopEndpoints: endpoints,
+	}
+}
+
+// parseUsageTime parses the RFC 3339 time of the query parameter `name`,
+// returning `defaultValue` when it is not set.
+func parseUsageTime(r *http.Request, name string, defaultValue time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, problem.MakeInvalidFieldProblem(name, errors.New("must be a RFC 3339 time"))
+	}
+	return parsed, nil
+}
+
+// usageHandler returns the tenants which sent the most requests between the
+// `from` and `to` query parameters, the last 24 hours by default. The usage is
+// aggregated per hour so the bounds are rounded down to the hour, and the
+// hour containing `to` is included.
+func (a *App) usageHandler(w http.ResponseWriter, r *http.Request) {
+	to, err := parseUsageTime(r, "to", time.Now())
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+	from, err := parseUsageTime(r, "from", to.Add(-24*time.Hour))
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+	if !from.Before(to) {
+		problem.Render(r.Context(), w, problem.MakeInvalidFieldProblem("from", errors.New("must be before to")))
+		return
+	}
+
+	limit := uint64(defaultUsageLimit)
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil || limit == 0 || limit > maxUsageLimit {
+			problem.Render(r.Context(), w, problem.MakeInvalidFieldProblem(
+				"limit", errors.Errorf("must be between 1 and %d", maxUsageLimit),
+			))
+			return
+		}
+	}
+
+	from = from.UTC().Truncate(usage.Period)
+	to = to.UTC().Truncate(usage.Period)
+	q := &history.Q{a.HorizonSession(r.Context())}
+	// the period containing `to` is included
+	usages, err := q.GetTopUsage(from, to.Add(usage.Period), limit)
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	report := UsageReport{From: from, To: to, Tenants: make([]TenantReport, 0, len(usages))}
+	for _, tenant := range usages {
+		report.Tenants = append(report.Tenants, newTenantReport(tenant))
+	}
+	httpjson.Render(w, report, httpjson.JSON)
+}
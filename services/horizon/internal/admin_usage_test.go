@@ -0,0 +1,39 @@
+package horizon
+
+import (
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTenantReport(t *testing.T) {
+	usage := newTenantReport(history.TenantUsage{
+		Tenant:      "key:abc",
+		Requests:    21,
+		Bytes:       2048,
+		RateLimited: 3,
+		Endpoints: map[string]int64{
+			"GET /ledgers":               2,
+			"GET /accounts/{account_id}": 8,
+			"GET /operations":            2,
+			"POST /transactions":         1,
+			"GET /paths/strict-receive":  5,
+			"GET /order_book":            3,
+		},
+	})
+
+	assert.Equal(t, TenantReport{
+		Tenant:      "key:abc",
+		Requests:    21,
+		Bytes:       2048,
+		RateLimited: 3,
+		TopEndpoints: []EndpointUsage{
+			{Endpoint: "GET /accounts/{account_id}", Requests: 8},
+			{Endpoint: "GET /paths/strict-receive", Requests: 5},
+			{Endpoint: "GET /order_book", Requests: 3},
+			{Endpoint: "GET /ledgers", Requests: 2},
+			{Endpoint: "GET /operations", Requests: 2},
+		},
+	}, usage)
+}
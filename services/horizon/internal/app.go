@@ -10,24 +10,32 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-chi/chi"
 	metrics "github.com/rcrowley/go-metrics"
 	"github.com/stellar/go/clients/stellarcore"
 	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/services/horizon/internal/cdc"
 	horizonContext "github.com/stellar/go/services/horizon/internal/context"
 	"github.com/stellar/go/services/horizon/internal/db2/core"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/expingest"
+	"github.com/stellar/go/services/horizon/internal/jobs"
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/logmetrics"
+	"github.com/stellar/go/services/horizon/internal/maintenance"
 	"github.com/stellar/go/services/horizon/internal/operationfeestats"
 	"github.com/stellar/go/services/horizon/internal/paths"
 	"github.com/stellar/go/services/horizon/internal/reap"
 	"github.com/stellar/go/services/horizon/internal/txsub"
+	results "github.com/stellar/go/services/horizon/internal/txsub/results/db"
+	"github.com/stellar/go/services/horizon/internal/usage"
 	"github.com/stellar/go/support/app"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/redis"
 	"github.com/stellar/throttled"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	graceful "gopkg.in/tylerb/graceful.v1"
 )
 
@@ -35,6 +43,8 @@ type coreSettings struct {
 	currentProtocolVersion       int32
 	coreSupportedProtocolVersion int32
 	coreVersion                  string
+	// updatedAt is the time the settings were last loaded from stellar-core
+	updatedAt time.Time
 }
 
 type coreSettingsStore struct {
@@ -48,6 +58,7 @@ func (c *coreSettingsStore) set(resp *proto.InfoResponse) {
 	c.coreVersion = resp.Info.Build
 	c.currentProtocolVersion = int32(resp.Info.Ledger.Version)
 	c.coreSupportedProtocolVersion = int32(resp.Info.ProtocolVersion)
+	c.updatedAt = time.Now()
 }
 
 func (c *coreSettingsStore) get() coreSettings {
@@ -58,20 +69,43 @@ func (c *coreSettingsStore) get() coreSettings {
 
 // App represents the root of the state of a horizon instance.
 type App struct {
-	config          Config
-	web             *web
-	historyQ        *history.Q
-	coreQ           *core.Q
-	ctx             context.Context
-	cancel          func()
+	config       Config
+	web          *web
+	historyQ     *history.Q
+	readReplicas *readReplicas
+	coreQ        *core.Q
+	ctx          context.Context
+	cancel       func()
+	// streamsCtx is cancelled to close the SSE streams on shutdown, before
+	// ctx is cancelled.
+	streamsCtx      context.Context
+	closeStreams    func()
 	horizonVersion  string
 	coreSettings    coreSettingsStore
 	orderBookStream *expingest.OrderBookStream
 	submitter       *txsub.System
 	paths           paths.Finder
+	pathCache       *paths.CachedFinder
 	expingester     *expingest.System
 	reaper          *reap.System
+	cdc             *cdc.System
+	maintenance     *maintenance.System
+	jobs            *jobs.System
+	usage           *usage.System
+	redis           redis.Client
+	tracerProvider  *sdktrace.TracerProvider
 	ticks           *time.Ticker
+	ledgerState     *ledger.Store
+	feeStatsState   *operationfeestats.Store
+	reingest        reingestTracker
+	configReloader  configReloader
+	historyGaps     historyGapsCache
+	// networks contains the apps serving the additional networks configured
+	// in Config.Networks. They are mounted under their path prefix.
+	networks []*App
+	// recentTransactions holds the results of the recently ingested
+	// transactions when the history is not ingested, see Config.StateOnly.
+	recentTransactions *results.Recent
 
 	// metrics
 	metrics                  metrics.Registry
@@ -81,6 +115,13 @@ type App struct {
 	coreLatestLedgerGauge    metrics.Gauge
 	coreConnGauge            metrics.Gauge
 	goroutineGauge           metrics.Gauge
+	ingestLagGauge           metrics.Gauge
+	ingestLagSecondsGauge    metrics.Gauge
+	historyGapsGauge         metrics.Gauge
+	historyMissingGauge      metrics.Gauge
+	horizonDBPool            dbPoolMetrics
+	coreDBPool               dbPoolMetrics
+	replicaDBPools           []dbPoolMetrics
 }
 
 // NewApp constructs an new App instance from the provided config.
@@ -89,6 +130,31 @@ func NewApp(config Config) *App {
 		config:         config,
 		horizonVersion: app.Version(),
 		ticks:          time.NewTicker(1 * time.Second),
+		ledgerState:    ledger.DefaultStore(),
+		feeStatsState:  operationfeestats.DefaultStore(),
+	}
+
+	// Tracing is process wide, the additional networks share the tracer of
+	// the root app.
+	initTracing(a)
+
+	for _, network := range config.Networks {
+		a.networks = append(a.networks, newNetworkApp(config.ForNetwork(network)))
+	}
+
+	a.init()
+	return a
+}
+
+// newNetworkApp constructs an App serving one of the additional networks. Each
+// of them keeps its own ledger and fee stats state.
+func newNetworkApp(config Config) *App {
+	a := &App{
+		config:         config,
+		horizonVersion: app.Version(),
+		ticks:          time.NewTicker(1 * time.Second),
+		ledgerState:    &ledger.Store{},
+		feeStatsState:  &operationfeestats.Store{},
 	}
 
 	a.init()
@@ -101,17 +167,23 @@ func (a *App) Serve() {
 	addr := fmt.Sprintf(":%d", a.config.Port)
 
 	srv := &graceful.Server{
-		Timeout: 10 * time.Second,
+		Timeout: a.config.ShutdownGracePeriod,
 
 		Server: &http.Server{
 			Addr:        addr,
-			Handler:     a.web.router,
+			Handler:     a.handler(false),
 			ReadTimeout: 5 * time.Second,
 		},
 
+		// The server stops accepting connections and waits for the
+		// in-flight requests to complete. Streams would never complete so
+		// they are closed right away. The background processes, which
+		// resolve the in-flight submissions, are only stopped once the
+		// requests are done.
 		ShutdownInitiated: func() {
-			log.Info("received signal, gracefully stopping")
-			a.Close()
+			log.WithField("grace_period", a.config.ShutdownGracePeriod).
+				Info("received signal, gracefully stopping")
+			a.CloseStreams()
 		},
 	}
 
@@ -124,7 +196,7 @@ func (a *App) Serve() {
 
 			internalSrv := &http.Server{
 				Addr:        adminAddr,
-				Handler:     a.web.internalRouter,
+				Handler:     a.handler(true),
 				ReadTimeout: 5 * time.Second,
 			}
 
@@ -135,19 +207,15 @@ func (a *App) Serve() {
 		}()
 	}
 
-	go a.run()
-	go a.orderBookStream.Run(a.ctx)
+	go a.reloadConfigOnSignal()
 
 	// WaitGroup for all go routines. Makes sure that DB is closed when
 	// all services gracefully shutdown.
 	var wg sync.WaitGroup
 
-	if a.expingester != nil {
-		wg.Add(1)
-		go func() {
-			a.expingester.Run()
-			wg.Done()
-		}()
+	for _, network := range append([]*App{a}, a.networks...) {
+		log.Infof("Serving network %q on %q", network.config.NetworkPassphrase, network.pathPrefix())
+		network.runBackground(&wg)
 	}
 
 	var err error
@@ -161,14 +229,111 @@ func (a *App) Serve() {
 		log.Fatal(err)
 	}
 
+	// Waits for the ingestion system to stop after the ledger it is
+	// ingesting, which is rolled back and ingested again on restart.
+	a.Close()
 	wg.Wait()
 	a.CloseDB()
 
 	log.Info("stopped")
 }
 
+// handler returns the http handler serving the app. When additional networks
+// are configured their routers are mounted under their path prefixes in front
+// of the app's own router, so that every request goes only through the
+// middlewares of the network it is addressed to.
+func (a *App) handler(internal bool) http.Handler {
+	router := a.web.router
+	if internal {
+		router = a.web.internalRouter
+	}
+	if len(a.networks) == 0 {
+		return router
+	}
+
+	mux := chi.NewRouter()
+	for _, network := range a.networks {
+		if internal {
+			mux.Mount(network.config.PathPrefix, network.web.internalRouter)
+		} else {
+			mux.Mount(network.config.PathPrefix, network.web.router)
+		}
+	}
+	mux.Mount("/", router)
+	return mux
+}
+
+// runBackground starts the background processes of the app: the ticker,
+// the order book stream, the path cache, the ingestion system, the
+// change-data-capture publisher, the database maintenance, the background
+// jobs, the request accounting and the tracer.
+func (a *App) runBackground(wg *sync.WaitGroup) {
+	go a.run()
+	go a.orderBookStream.Run(a.ctx)
+	if a.pathCache != nil {
+		go a.pathCache.Run(a.ctx)
+	}
+	if a.cdc != nil {
+		go a.cdc.Run(a.ctx)
+	}
+	if a.maintenance != nil {
+		go a.maintenance.Run(a.ctx)
+	}
+	go a.jobs.Run(a.ctx)
+	if a.tracerProvider != nil {
+		go func() {
+			<-a.ctx.Done()
+			// the export of the last spans must not be cancelled
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := a.tracerProvider.Shutdown(ctx); err != nil {
+				log.WithField("err", err).Warn("Error exporting the last tracing spans")
+			}
+		}()
+	}
+
+	if a.usage != nil {
+		// the usage is flushed one last time on shutdown
+		wg.Add(1)
+		go func() {
+			a.usage.Run(a.ctx)
+			wg.Done()
+		}()
+	}
+
+	if a.expingester != nil {
+		wg.Add(1)
+		go func() {
+			a.expingester.Run()
+			wg.Done()
+		}()
+	}
+}
+
+// pathPrefix returns the path under which the app's routes are served.
+func (a *App) pathPrefix() string {
+	if a.config.PathPrefix == "" {
+		return "/"
+	}
+	return a.config.PathPrefix
+}
+
+// CloseStreams closes the SSE streams of the app, sending them a final event
+// so that clients reconnect.
+func (a *App) CloseStreams() {
+	for _, network := range a.networks {
+		network.CloseStreams()
+	}
+
+	a.closeStreams()
+}
+
 // Close cancels the app. It does not close DB connections - use App.CloseDB().
 func (a *App) Close() {
+	for _, network := range a.networks {
+		network.Close()
+	}
+
 	a.cancel()
 	if a.expingester != nil {
 		a.expingester.Shutdown()
@@ -180,8 +345,18 @@ func (a *App) Close() {
 // sure all requests are first properly finished to avoid "sql: database is
 // closed" errors.
 func (a *App) CloseDB() {
+	for _, network := range a.networks {
+		network.CloseDB()
+	}
+
 	a.historyQ.Session.DB.Close()
-	a.coreQ.Session.DB.Close()
+	a.readReplicas.close()
+	if a.coreQ != nil {
+		a.coreQ.Session.DB.Close()
+	}
+	if a.redis != nil {
+		a.redis.Close()
+	}
 }
 
 // HistoryQ returns a helper object for performing sql queries against the
@@ -197,7 +372,8 @@ func (a *App) HorizonSession(ctx context.Context) *db.Session {
 }
 
 // CoreSession returns a new session that loads data from the stellar core
-// database. The returned session is bound to `ctx`.
+// database. The returned session is bound to `ctx`. It must not be called when
+// ingesting from captive core, which does not use a stellar-core database.
 func (a *App) CoreSession(ctx context.Context) *db.Session {
 	return &db.Session{DB: a.coreQ.Session.DB, Ctx: ctx}
 }
@@ -211,15 +387,14 @@ func (a *App) CoreQ() *core.Q {
 // IsHistoryStale returns true if the latest history ledger is more than
 // `StaleThreshold` ledgers behind the latest core ledger
 func (a *App) IsHistoryStale() bool {
-	return isHistoryStale(a.config.StaleThreshold)
+	return isHistoryStale(a.ledgerState.CurrentState(), a.config.StaleThreshold)
 }
 
-func isHistoryStale(staleThreshold uint) bool {
+func isHistoryStale(ls ledger.State, staleThreshold uint) bool {
 	if staleThreshold == 0 {
 		return false
 	}
 
-	ls := ledger.CurrentState()
 	return (ls.CoreLatest - ls.HistoryLatest) > int32(staleThreshold)
 }
 
@@ -249,6 +424,13 @@ func (a *App) UpdateLedgerState() {
 		logErr(err, "failed to load the latest known ledger state from history DB")
 		return
 	}
+	a.readReplicas.update(a.ctx, next.HistoryLatest)
+
+	next.HistoryLatestClosedAt, err = a.HistoryQ().LatestLedgerClosedAt()
+	if err != nil {
+		logErr(err, "failed to load the close time of the latest known ledger from history DB")
+		return
+	}
 
 	err = a.HistoryQ().ElderLedger(&next.HistoryElder)
 	if err != nil {
@@ -262,7 +444,7 @@ func (a *App) UpdateLedgerState() {
 		return
 	}
 
-	ledger.SetState(next)
+	a.ledgerState.SetState(next)
 }
 
 // UpdateFeeStatsState triggers a refresh of several operation fee metrics.
@@ -283,7 +465,7 @@ func (a *App) UpdateFeeStatsState() {
 		log.WithStack(err).WithField("err", err.Error()).Error(msg)
 	}
 
-	cur, ok := operationfeestats.CurrentState()
+	cur, ok := a.feeStatsState.CurrentState()
 
 	err := a.HistoryQ().LatestLedgerBaseFeeAndSequence(&latest)
 	if err != nil {
@@ -382,7 +564,7 @@ func (a *App) UpdateFeeStatsState() {
 		next.FeeChargedP99 = feeStats.FeeChargedP99.Int64
 	}
 
-	operationfeestats.SetState(next)
+	a.feeStatsState.SetState(next)
 }
 
 // UpdateStellarCoreInfo updates the value of coreVersion,
@@ -400,6 +582,7 @@ func (a *App) UpdateStellarCoreInfo() {
 	resp, err := core.Info(context.Background())
 	if err != nil {
 		log.Warnf("could not load stellar-core info: %s", err)
+		a.updateSubmissionCoreStatus("", false)
 		return
 	}
 
@@ -415,19 +598,55 @@ func (a *App) UpdateStellarCoreInfo() {
 	}
 
 	a.coreSettings.set(resp)
+	a.updateSubmissionCoreStatus(resp.Info.State, resp.IsSynced())
+}
+
+// updateSubmissionCoreStatus reports the state of stellar-core to the circuit
+// breaker of the submission system, once it is initialized.
+func (a *App) updateSubmissionCoreStatus(state string, synced bool) {
+	if a.submitter == nil || a.submitter.CircuitBreaker == nil {
+		return
+	}
+	a.submitter.CircuitBreaker.UpdateCoreStatus(state, synced)
 }
 
 // UpdateMetrics triggers a refresh of several metrics gauges, such as open
 // db connections and ledger state
 func (a *App) UpdateMetrics() {
 	a.goroutineGauge.Update(int64(runtime.NumGoroutine()))
-	ls := ledger.CurrentState()
+	ls := a.ledgerState.CurrentState()
 	a.historyLatestLedgerGauge.Update(int64(ls.HistoryLatest))
 	a.historyElderLedgerGauge.Update(int64(ls.HistoryElder))
 	a.coreLatestLedgerGauge.Update(int64(ls.CoreLatest))
 
 	a.horizonConnGauge.Update(int64(a.historyQ.Session.DB.Stats().OpenConnections))
-	a.coreConnGauge.Update(int64(a.coreQ.Session.DB.Stats().OpenConnections))
+	a.horizonDBPool.update(a.historyQ.Session.DB.Stats())
+	if a.coreQ != nil {
+		a.coreConnGauge.Update(int64(a.coreQ.Session.DB.Stats().OpenConnections))
+		a.coreDBPool.update(a.coreQ.Session.DB.Stats())
+	}
+	for i, pool := range a.replicaDBPools {
+		pool.update(a.readReplicas.replicas[i].session.DB.Stats())
+	}
+
+	// ingestion lag is only known once both core and ingestion reported a
+	// ledger
+	if ls.CoreLatest > 0 && ls.ExpHistoryLatest > 0 {
+		a.ingestLagGauge.Update(int64(ls.CoreLatest) - int64(ls.ExpHistoryLatest))
+	}
+	if !ls.HistoryLatestClosedAt.IsZero() {
+		a.ingestLagSecondsGauge.Update(int64(time.Since(ls.HistoryLatestClosedAt).Seconds()))
+	}
+
+	// the gaps query is expensive, its result is cached by historyGapsHealth
+	if gaps := a.historyGapsHealth(time.Now()); gaps.Error == "" {
+		var missing int64
+		for _, gap := range gaps.Gaps {
+			missing += int64(gap.EndSequence) - int64(gap.StartSequence) + 1
+		}
+		a.historyGapsGauge.Update(int64(len(gaps.Gaps)))
+		a.historyMissingGauge.Update(missing)
+	}
 }
 
 // DeleteUnretainedHistory forwards to the app's reaper.  See
@@ -463,6 +682,7 @@ func (a *App) Tick() {
 func (a *App) init() {
 	// app-context
 	a.ctx, a.cancel = context.WithCancel(context.Background())
+	a.streamsCtx, a.closeStreams = context.WithCancel(a.ctx)
 
 	// log
 	log.DefaultLogger.Logger.Level = a.config.LogLevel
@@ -479,8 +699,14 @@ func (a *App) init() {
 
 	// horizon-db and core-db
 	mustInitHorizonDB(a)
+	mustInitReadReplicas(a)
 	mustInitCoreDB(a)
 
+	// recent transaction results, when the history is not ingested
+	if a.config.StateOnly {
+		a.recentTransactions = results.NewRecent(results.DefaultRecentLedgers)
+	}
+
 	if a.config.Ingest {
 		// expingester
 		initExpIngester(a)
@@ -492,12 +718,63 @@ func (a *App) init() {
 
 	// reaper
 	a.reaper = reap.New(a.config.HistoryRetentionCount, a.HorizonSession(context.Background()))
+	a.reaper.LedgerState = a.ledgerState
+	a.reaper.BatchSize = a.config.HistoryRetentionReapBatchSize
+	a.reaper.BatchDelay = a.config.HistoryRetentionReapBatchDelay
+
+	// background jobs
+	initJobs(a)
+
+	// change-data-capture
+	if a.config.CDCPublishURL != "" {
+		a.cdc = cdc.New(
+			a.HorizonSession(context.Background()),
+			&cdc.HTTPPublisher{URL: a.config.CDCPublishURL, Client: &http.Client{Timeout: 30 * time.Second}},
+		)
+	}
+
+	// database maintenance
+	if a.config.DBMaintenanceWindow != nil {
+		a.maintenance = maintenance.New(a.HorizonSession(context.Background()), *a.config.DBMaintenanceWindow)
+		a.maintenance.IndexBloatThreshold = a.config.DBMaintenanceIndexBloatThreshold
+	}
+
+	// request accounting
+	if a.config.UsageAccounting {
+		a.usage = usage.New(
+			a.HorizonSession(context.Background()),
+			time.Duration(a.config.UsageAccountingRetentionDays)*24*time.Hour,
+		)
+	}
 
 	// web.init
-	a.web = mustInitWeb(a.ctx, a.historyQ, a.config.SSEUpdateFrequency, a.config.StaleThreshold)
+	a.web = mustInitWeb(a.streamsCtx, a.historyQ, a.config.SSEUpdateFrequency, a.config.StaleThreshold)
+	a.web.ledgerState = a.ledgerState
+	a.web.readReplicas = a.readReplicas
+	a.web.pathPrefix = a.config.PathPrefix
+	a.web.friendbotURL.set(a.config.FriendbotURL)
 
 	// web.rate-limiter
-	a.web.rateLimiter = maybeInitWebRateLimiter(a.config.RateQuota)
+	newRateLimiter := rateLimiterFactory(newLocalRateLimiter)
+	if a.config.RedisURL != "" {
+		client, err := redis.NewClient(a.config.RedisURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		a.redis = client
+		newRateLimiter = newRedisRateLimiterFactory(client, a.config.RateLimitRedisKey+a.config.PathPrefix)
+	}
+	a.web.rateLimiter = maybeInitWebRateLimiter(a.config.RateQuota, RateLimitGroupReads, newRateLimiter)
+	a.web.routeRateLimiters = map[string]*throttled.HTTPRateLimiter{}
+	for group, quota := range a.config.RouteRateQuotas {
+		quota := quota
+		if group == RateLimitGroupReads {
+			// streams are rate limited with the quota of reads
+			a.web.rateLimiter = maybeInitWebRateLimiter(&quota, group, newRateLimiter)
+			continue
+		}
+		a.web.routeRateLimiters[group] = maybeInitWebRateLimiter(&quota, group, newRateLimiter)
+	}
 
 	// web.middleware
 	// Note that we passed in `a` here for putting the whole App in the context.
@@ -516,6 +793,12 @@ func (a *App) init() {
 	// web.actions
 	a.web.mustInstallActions(a.config, a.paths, a.historyQ.Session, a.metrics)
 
+	// web.admin
+	a.web.mustInstallAdminActions(a)
+
+	// web.health
+	a.web.router.Get("/health", a.healthHandler)
+
 	// ingest.metrics
 	initIngestMetrics(a)
 
@@ -524,6 +807,13 @@ func (a *App) init() {
 
 	// txsub.metrics
 	initTxSubMetrics(a)
+
+	// reap.metrics
+	initReapMetrics(a)
+	initCDCMetrics(a)
+	initMaintenanceMetrics(a)
+	initJobsMetrics(a)
+	initUsageMetrics(a)
 }
 
 // run is the function that runs in the background that triggers Tick each
@@ -2,9 +2,13 @@ package horizon
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/rs/cors"
 	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGenericHTTPFeatures(t *testing.T) {
@@ -34,6 +38,44 @@ func TestGenericHTTPFeatures(t *testing.T) {
 	w = ht.Get("/ledgers/")
 	ht.Assert.Equal(200, w.Code)
 }
+func TestCORSOptions(t *testing.T) {
+	options := corsOptions(Config{})
+	assert.Equal(t, []string{"*"}, options.AllowedOrigins)
+	assert.Equal(t, []string{"*"}, options.AllowedHeaders)
+	assert.False(t, options.AllowCredentials)
+	assert.Equal(t, 0, options.MaxAge)
+
+	handler := cors.New(corsOptions(Config{
+		CORSAllowedOrigins:   []string{"https://wallet.example.com"},
+		CORSAllowedHeaders:   []string{"Authorization"},
+		CORSAllowCredentials: true,
+		CORSMaxAge:           10 * time.Minute,
+	})).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(method, origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/", nil)
+		r.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			r.Header.Set("Access-Control-Request-Headers", "Authorization")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodGet, "https://wallet.example.com")
+	assert.Equal(t, "https://wallet.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+	w = serve(http.MethodGet, "https://elsewhere.example.com")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = serve(http.MethodOptions, "https://wallet.example.com")
+	assert.Equal(t, "https://wallet.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+}
+
 func TestMetrics(t *testing.T) {
 	ht := StartHTTPTest(t, "base")
 	defer ht.Finish()
@@ -0,0 +1,48 @@
+package cdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// HTTPPublisher publishes the rows of a ledger by POSTing them as JSON to URL,
+// for example to a webhook or to the REST proxy of a message bus.
+type HTTPPublisher struct {
+	URL    string
+	Client *http.Client
+}
+
+// Publish implements Publisher. It succeeds when the server responds with a
+// 2xx status code.
+func (p *HTTPPublisher) Publish(ctx context.Context, rows LedgerRows) error {
+	body, err := json.Marshal(rows)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal rows")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not send request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cdc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPPublisher(t *testing.T) {
+	var received LedgerRows
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	publisher := &HTTPPublisher{URL: server.URL}
+	err := publisher.Publish(context.Background(), LedgerRows{Sequence: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), received.Sequence)
+}
+
+func TestHTTPPublisherErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	publisher := &HTTPPublisher{URL: server.URL}
+	err := publisher.Publish(context.Background(), LedgerRows{Sequence: 10})
+	assert.EqualError(t, err, "unexpected status 503 Service Unavailable")
+}
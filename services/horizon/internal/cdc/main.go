@@ -0,0 +1,73 @@
+// Package cdc contains the change-data-capture subsystem of horizon. It
+// publishes the history rows ingested into the horizon database
+// (transactions, operations, effects and trades) to a message bus, one
+// message per ledger, so that downstream systems can mirror them.
+//
+// Ledgers are published in order once they are committed to the database and
+// the last published ledger is stored in the database, so that publishing
+// resumes where it stopped. A ledger can be published more than once if
+// horizon stops right after publishing it: consumers must deduplicate messages
+// by ledger sequence.
+package cdc
+
+import (
+	"context"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/db"
+)
+
+// DefaultPollInterval is the default time the publisher waits for new ledgers
+// once it has published all the ingested ones.
+const DefaultPollInterval = time.Second
+
+// LedgerRows are the history rows of a ledger, published as a single message.
+type LedgerRows struct {
+	Sequence     uint32                `json:"sequence"`
+	Transactions []history.Transaction `json:"transactions"`
+	Operations   []history.Operation   `json:"operations"`
+	Effects      []history.Effect      `json:"effects"`
+	Trades       []history.Trade       `json:"trades"`
+}
+
+// Publisher delivers the rows of a ledger to a message bus. Publish must
+// return an error unless the message has been accepted by the bus.
+type Publisher interface {
+	Publish(ctx context.Context, rows LedgerRows) error
+}
+
+// System represents the change-data-capture subsystem of horizon.
+type System struct {
+	HistoryQ     *history.Q
+	Publisher    Publisher
+	PollInterval time.Duration
+
+	Metrics struct {
+		// PublishedLedgersCounter counts the ledgers published.
+		PublishedLedgersCounter metrics.Counter
+
+		// LagGauge is the number of ingested ledgers which are still to be
+		// published.
+		LagGauge metrics.Gauge
+
+		// PublishTimer exposes timing metrics about the loading and the
+		// publication of the rows of a ledger.
+		PublishTimer metrics.Timer
+	}
+}
+
+// New initializes the change-data-capture subsystem publishing the history
+// rows of the database of `dbSession` to `publisher`.
+func New(dbSession *db.Session, publisher Publisher) *System {
+	s := &System{
+		HistoryQ:     &history.Q{dbSession},
+		Publisher:    publisher,
+		PollInterval: DefaultPollInterval,
+	}
+	s.Metrics.PublishedLedgersCounter = metrics.NewCounter()
+	s.Metrics.LagGauge = metrics.NewGauge()
+	s.Metrics.PublishTimer = metrics.NewTimer()
+	return s
+}
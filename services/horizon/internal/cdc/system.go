@@ -0,0 +1,151 @@
+package cdc
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// Run publishes the ingested ledgers until `ctx` is cancelled. Errors are
+// logged and the publication of the ledger is retried after PollInterval.
+func (s *System) Run(ctx context.Context) {
+	log.WithField("poll_interval", s.PollInterval).Info("Starting change-data-capture publisher")
+	for {
+		published, err := s.publishNext(ctx)
+		if err != nil {
+			log.WithField("err", err).Error("Error publishing ledger")
+		}
+
+		// The next ledger is published right away while catching up.
+		wait := s.PollInterval
+		if published {
+			wait = 0
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// publishNext publishes the ledger following the last published one, if it
+// has been ingested. It returns true when a ledger was published.
+//
+// The last published ledger is locked for the duration of the publication so
+// that several horizon instances sharing a database do not publish the same
+// ledger concurrently. It is updated only once the publisher has accepted the
+// rows so every ledger is published at least once.
+func (s *System) publishNext(ctx context.Context) (bool, error) {
+	q := &history.Q{s.HistoryQ.Clone()}
+	if err := q.Begin(); err != nil {
+		return false, errors.Wrap(err, "could not begin transaction")
+	}
+	defer q.Rollback()
+
+	last, err := q.GetLastLedgerCDCPublished()
+	if err != nil {
+		return false, errors.Wrap(err, "could not load last published ledger")
+	}
+
+	var latest, elder int32
+	if err = q.LatestLedger(&latest); err != nil {
+		return false, errors.Wrap(err, "could not load latest ledger")
+	}
+	if err = q.ElderLedger(&elder); err != nil {
+		return false, errors.Wrap(err, "could not load elder ledger")
+	}
+
+	if last == 0 {
+		// Publishing starts with the ledgers ingested after it is enabled.
+		if latest == 0 {
+			return false, nil
+		}
+		if err = q.UpdateLastLedgerCDCPublished(uint32(latest)); err != nil {
+			return false, errors.Wrap(err, "could not update last published ledger")
+		}
+		log.WithField("ledger", latest).Info("Change-data-capture publisher initialized")
+		return false, errors.Wrap(q.Commit(), "could not commit transaction")
+	}
+
+	lag := int64(latest) - int64(last)
+	if lag < 0 {
+		lag = 0
+	}
+	s.Metrics.LagGauge.Update(lag)
+	if lag == 0 {
+		return false, nil
+	}
+
+	next := int32(last + 1)
+	if next < elder {
+		// The ledgers were reaped before they could be published.
+		log.WithField("from", next).WithField("to", elder-1).
+			Warn("Skipping reaped ledgers which were not published")
+		next = elder
+	}
+
+	var ledger history.Ledger
+	err = q.LedgerBySequence(&ledger, next)
+	if q.NoRows(err) {
+		// The ledger is missing from the history, see the history.gaps
+		// metric. It is skipped so it does not block publishing.
+		log.WithField("ledger", next).Warn("Skipping ledger missing from the history")
+		if err = q.UpdateLastLedgerCDCPublished(uint32(next)); err != nil {
+			return false, errors.Wrap(err, "could not update last published ledger")
+		}
+		return true, errors.Wrap(q.Commit(), "could not commit transaction")
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "could not load ledger %d", next)
+	}
+
+	startTime := time.Now()
+	rows, err := loadLedgerRows(q, next)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not load rows of ledger %d", next)
+	}
+	if err = s.Publisher.Publish(ctx, rows); err != nil {
+		return false, errors.Wrapf(err, "could not publish ledger %d", next)
+	}
+
+	if err = q.UpdateLastLedgerCDCPublished(uint32(next)); err != nil {
+		return false, errors.Wrap(err, "could not update last published ledger")
+	}
+	if err = q.Commit(); err != nil {
+		return false, errors.Wrap(err, "could not commit transaction")
+	}
+
+	s.Metrics.PublishTimer.UpdateSince(startTime)
+	s.Metrics.PublishedLedgersCounter.Inc(1)
+	s.Metrics.LagGauge.Update(int64(latest - next))
+	log.WithField("ledger", next).Debug("Published ledger")
+	return true, nil
+}
+
+// loadLedgerRows loads the history rows of ledger `seq`, including the rows of
+// failed transactions.
+func loadLedgerRows(q *history.Q, seq int32) (LedgerRows, error) {
+	rows := LedgerRows{Sequence: uint32(seq)}
+
+	if err := q.Transactions().ForLedger(seq).IncludeFailed().Select(&rows.Transactions); err != nil {
+		return rows, errors.Wrap(err, "could not load transactions")
+	}
+
+	operations, _, err := q.Operations().ForLedger(seq).IncludeFailed().Fetch()
+	if err != nil {
+		return rows, errors.Wrap(err, "could not load operations")
+	}
+	rows.Operations = operations
+
+	if err = q.Effects().ForLedger(seq).Select(&rows.Effects); err != nil {
+		return rows, errors.Wrap(err, "could not load effects")
+	}
+	if err = q.Trades().ForLedger(seq, "asc").Select(&rows.Trades); err != nil {
+		return rows, errors.Wrap(err, "could not load trades")
+	}
+	return rows, nil
+}
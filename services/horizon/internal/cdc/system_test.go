@@ -0,0 +1,63 @@
+package cdc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/errors"
+)
+
+type recordingPublisher struct {
+	published []LedgerRows
+	err       error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, rows LedgerRows) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, rows)
+	return nil
+}
+
+func TestPublishNext(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	publisher := &recordingPublisher{}
+	sys := New(tt.HorizonSession(), publisher)
+
+	// The first run only records the latest ledger.
+	published, err := sys.publishNext(context.Background())
+	tt.Require.NoError(err)
+	tt.Assert.False(published)
+	last, err := sys.HistoryQ.GetLastLedgerCDCPublished()
+	tt.Require.NoError(err)
+	tt.Assert.Equal(uint32(3), last)
+
+	tt.Require.NoError(sys.HistoryQ.UpdateLastLedgerCDCPublished(1))
+
+	publisher.err = errors.New("bus unavailable")
+	_, err = sys.publishNext(context.Background())
+	tt.Assert.EqualError(err, "could not publish ledger 2: bus unavailable")
+	last, err = sys.HistoryQ.GetLastLedgerCDCPublished()
+	tt.Require.NoError(err)
+	tt.Assert.Equal(uint32(1), last)
+
+	publisher.err = nil
+	for _, expected := range []uint32{2, 3} {
+		published, err = sys.publishNext(context.Background())
+		tt.Require.NoError(err)
+		tt.Assert.True(published)
+		tt.Assert.Equal(expected, publisher.published[len(publisher.published)-1].Sequence)
+	}
+	tt.Assert.NotEmpty(publisher.published[0].Transactions)
+	tt.Assert.NotEmpty(publisher.published[0].Operations)
+	tt.Assert.Equal(int64(2), sys.Metrics.PublishedLedgersCounter.Count())
+	tt.Assert.Equal(int64(0), sys.Metrics.LagGauge.Value())
+
+	published, err = sys.publishNext(context.Background())
+	tt.Require.NoError(err)
+	tt.Assert.False(published)
+}
@@ -0,0 +1,180 @@
+package horizon
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// compressibleContentTypes are the content types of responses which are
+// compressed by compressMiddleware.
+var compressibleContentTypes = []string{
+	"application/hal+json",
+	"application/json",
+	"application/problem+json",
+	"text/event-stream",
+}
+
+// compressFlusher is implemented by gzip.Writer and zlib.Writer.
+type compressFlusher interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressMiddleware compresses responses with gzip or deflate when the client
+// accepts one of them. The body is compressed while it is written, so large
+// pages are never buffered in memory, and every flush of the response (e.g.
+// after each event of an SSE stream) also flushes the compressor so that
+// clients receive the data immediately.
+func compressMiddleware(level int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				level:          level,
+			}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the compression encoding to use for a request with
+// the given Accept-Encoding header, or an empty string when the response
+// should not be compressed. gzip is preferred over deflate.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		refused := false
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if !strings.HasPrefix(param, "q=") {
+				continue
+			}
+			if q, err := strconv.ParseFloat(param[len("q="):], 64); err == nil && q == 0 {
+				refused = true
+			}
+		}
+		if name != "" {
+			accepted[name] = !refused
+		}
+	}
+
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[encoding]; ok || (!listed && accepted["*"]) {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter compresses the body of a response if its content
+// type is compressible. The decision is made when the header is written.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	encoding    string
+	level       int
+	wroteHeader bool
+	writer      compressFlusher
+}
+
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	if status != http.StatusNoContent &&
+		status != http.StatusNotModified &&
+		header.Get("Content-Encoding") == "" &&
+		isCompressibleContentType(header.Get("Content-Type")) {
+		if writer, err := newCompressWriter(w.ResponseWriter, w.encoding, w.level); err == nil {
+			w.writer = writer
+			header.Set("Content-Encoding", w.encoding)
+			header.Del("Content-Length")
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.writer == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.writer.Write(b)
+}
+
+// Flush writes the data compressed so far to the client.
+func (w *compressResponseWriter) Flush() {
+	if w.writer != nil {
+		w.writer.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close writes the remaining compressed data to the client.
+func (w *compressResponseWriter) Close() error {
+	if w.writer == nil {
+		return nil
+	}
+	return w.writer.Close()
+}
+
+// newCompressWriter returns a writer compressing data written to `w` with
+// `encoding`.
+func newCompressWriter(w io.Writer, encoding string, level int) (compressFlusher, error) {
+	switch encoding {
+	case "gzip":
+		writer, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, err
+		}
+		return writer, nil
+	case "deflate":
+		// the deflate content coding is the zlib format (RFC 7230 section
+		// 4.2.2), not raw deflate
+		writer, err := zlib.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, err
+		}
+		return writer, nil
+	default:
+		return nil, errors.Errorf("unsupported encoding: %s", encoding)
+	}
+}
+
+func isCompressibleContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	for _, compressible := range compressibleContentTypes {
+		if mediaType == compressible {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package horizon
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	for _, testCase := range []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"GZIP;q=0.5, br", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"gzip;q=0.000, deflate;q=0", ""},
+		{"*", "gzip"},
+		{"*, gzip;q=0", "deflate"},
+	} {
+		assert.Equal(t, testCase.expected, negotiateEncoding(testCase.acceptEncoding), testCase.acceptEncoding)
+	}
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	body := `{"_embedded":{"records":[]}}`
+	handler := compressMiddleware(flate.DefaultCompression)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", r.URL.Query().Get("content_type"))
+			w.Write([]byte(body))
+		},
+	))
+	serve := func(acceptEncoding, contentType string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/?content_type="+contentType, nil)
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		w := serve("gzip", "application/hal%2Bjson%3B%20charset=utf-8")
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+		reader, err := gzip.NewReader(w.Body)
+		assert.NoError(t, err)
+		decompressed, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(decompressed))
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		w := serve("deflate", "application/problem%2Bjson")
+		assert.Equal(t, "deflate", w.Header().Get("Content-Encoding"))
+
+		reader, err := zlib.NewReader(w.Body)
+		assert.NoError(t, err)
+		decompressed, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(decompressed))
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		w := serve("", "application/hal%2Bjson")
+		assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, w.Body.String())
+	})
+
+	t.Run("not compressible", func(t *testing.T) {
+		w := serve("gzip", "application/octet-stream")
+		assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, w.Body.String())
+	})
+}
+
+func TestCompressMiddlewareFlush(t *testing.T) {
+	event := "data: \"hello\"\n\n"
+	flushed := make(chan struct{})
+	done := make(chan struct{})
+	handler := compressMiddleware(flate.DefaultCompression)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(event))
+			w.(http.Flusher).Flush()
+			flushed <- struct{}{}
+			<-done
+		},
+	))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(w, r)
+		close(served)
+	}()
+	<-flushed
+
+	// the event can be decompressed before the stream is closed
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	decompressed := make([]byte, len(event))
+	_, err = io.ReadFull(reader, decompressed)
+	assert.NoError(t, err)
+	assert.Equal(t, event, string(decompressed))
+
+	close(done)
+	<-served
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/services/horizon/internal/maintenance"
+	"github.com/stellar/go/xdr"
 	"github.com/stellar/throttled"
 )
 
@@ -16,9 +18,15 @@ type Config struct {
 	StellarCoreDatabaseURL     string
 	StellarCoreURL             string
 	EnableCaptiveCoreIngestion bool
-	HistoryArchiveURLs         []string
-	Port                       uint
-	AdminPort                  uint
+	// CaptiveCoreConfigAppendPath is a file appended to the config of the
+	// captive stellar-core.
+	CaptiveCoreConfigAppendPath string
+	// CaptiveCoreStoragePath is the directory in which captive stellar-core
+	// stores its buckets and temporary files.
+	CaptiveCoreStoragePath string
+	HistoryArchiveURLs     []string
+	Port                   uint
+	AdminPort              uint
 
 	// MaxDBConnections has a priority over all 4 values below.
 	MaxDBConnections            int
@@ -26,15 +34,68 @@ type Config struct {
 	HorizonDBMaxIdleConnections int
 	CoreDBMaxOpenConnections    int
 	CoreDBMaxIdleConnections    int
+	// DBConnectionMaxLifetime is the maximum amount of time a connection to
+	// any database is reused. Zero reuses connections forever.
+	DBConnectionMaxLifetime time.Duration
+	// DBStatementTimeout aborts the queries of the horizon database, its read
+	// replicas and the stellar-core database which run longer. It does not
+	// apply to ingestion. Zero disables the timeout.
+	DBStatementTimeout time.Duration
+
+	// ReadReplicaDatabaseURLs are read-only replicas of the horizon database
+	// which serve the read queries of API requests.
+	ReadReplicaDatabaseURLs []string
+	// ReadReplicaMaxLag is the number of ledgers a replica can lag behind the
+	// horizon database before queries are routed away from it.
+	ReadReplicaMaxLag uint
 
 	SSEUpdateFrequency time.Duration
 	ConnectionTimeout  time.Duration
-	RateQuota          *throttled.RateQuota
-	FriendbotURL       *url.URL
-	LogLevel           logrus.Level
-	LogFile            string
+	// ShutdownGracePeriod is the time in-flight requests are given to
+	// complete when horizon is stopped.
+	ShutdownGracePeriod time.Duration
+	// SlowRequestThreshold is the duration above which requests are logged
+	// with their slowest queries. Zero disables the slow request log.
+	SlowRequestThreshold time.Duration
+	RateQuota            *throttled.RateQuota
+	// RouteRateQuotas overrides RateQuota for the rate limit groups it
+	// contains, see RateLimitGroupSubmission, RateLimitGroupPathFinding and
+	// RateLimitGroupReads.
+	RouteRateQuotas map[string]throttled.RateQuota
+	// RedisURL is the redis server storing the rate limits shared by all the
+	// horizon instances using it. Rate limits are local when empty.
+	RedisURL string
+	// RateLimitRedisKey prefixes the redis keys of the rate limits.
+	RateLimitRedisKey string
+	// UsageAccounting aggregates the requests per tenant and persists them
+	// in the horizon database, see package usage.
+	UsageAccounting bool
+	// UsageAccountingKeyHeader is the request header holding the API key of
+	// the tenants. Requests without it are accounted to their remote ip
+	// address.
+	UsageAccountingKeyHeader string
+	// UsageAccountingRetentionDays is the number of days the usage is kept.
+	UsageAccountingRetentionDays uint
+	// CORSAllowedOrigins are the origins allowed to make cross-origin
+	// requests, "*" allows any origin.
+	CORSAllowedOrigins []string
+	// CORSAllowedHeaders are the headers allowed in cross-origin requests,
+	// "*" allows any header.
+	CORSAllowedHeaders []string
+	// CORSAllowCredentials allows cross-origin requests to include
+	// credentials, like cookies or authorization headers.
+	CORSAllowCredentials bool
+	// CORSMaxAge is how long browsers can cache the result of a preflight
+	// request. Zero leaves it to the browser.
+	CORSMaxAge   time.Duration
+	FriendbotURL *url.URL
+	LogLevel     logrus.Level
+	LogFile      string
 	// MaxPathLength is the maximum length of the path returned by `/paths` endpoint.
-	MaxPathLength     uint
+	MaxPathLength uint
+	// PathCacheSize is the number of the most requested path finding queries
+	// whose paths are precomputed on every ledger. Zero disables the cache.
+	PathCacheSize     uint
 	NetworkPassphrase string
 	SentryDSN         string
 	LogglyToken       string
@@ -55,6 +116,27 @@ type Config struct {
 	// determining a "retention duration", each ledger roughly corresponds to 10
 	// seconds of real time.
 	HistoryRetentionCount uint
+	// HistoryRetentionReapBatchSize is the number of unretained ledgers the
+	// reaper deletes at once.
+	HistoryRetentionReapBatchSize uint
+	// HistoryRetentionReapBatchDelay is the pause of the reaper between the
+	// deletion of two batches of ledgers.
+	HistoryRetentionReapBatchDelay time.Duration
+	// DBMaintenanceWindow is the daily window (in UTC) during which the hot
+	// history tables are vacuumed and analyzed and the bloat of their indexes
+	// is checked. The maintenance is disabled when nil.
+	DBMaintenanceWindow *maintenance.Window
+	// DBMaintenanceIndexBloatThreshold is the estimated fraction of wasted
+	// space above which an index is reported as bloated.
+	DBMaintenanceIndexBloatThreshold float64
+	// TracingOTLPURL is the OTLP/HTTP endpoint spans are exported to.
+	// Tracing is disabled when empty.
+	TracingOTLPURL string
+	// TracingSampleRatio is the ratio of new traces which are sampled.
+	TracingSampleRatio float64
+	// CDCPublishURL is the URL the change-data-capture publisher POSTs the
+	// history rows of ingested ledgers to. Publishing is disabled when empty.
+	CDCPublishURL string
 	// StaleThreshold represents the number of ledgers a history database may be
 	// out-of-date by before horizon begins to respond with an error to history
 	// requests.
@@ -65,7 +147,79 @@ type Config struct {
 	// IngestDisableStateVerification disables state verification
 	// `System.verifyState()` when set to `true`.
 	IngestDisableStateVerification bool
+	// IngestStateVerificationCheckpointFrequency runs the state verification
+	// every N checkpoints.
+	IngestStateVerificationCheckpointFrequency uint
+	// IngestStateVerificationEntryTypes restricts the state verification to
+	// these ledger entry types. All types are verified when empty.
+	IngestStateVerificationEntryTypes []xdr.LedgerEntryType
+	// HistoryAllowlistAccounts and HistoryAllowlistAssets restrict the
+	// ingested history to the transactions involving these accounts and
+	// assets. All the history is ingested when both are empty.
+	HistoryAllowlistAccounts []string
+	HistoryAllowlistAssets   []xdr.Asset
+	// StateOnly ingests and serves the current ledger state (accounts,
+	// offers, order book, paths) but no history: the transactions,
+	// operations, effects, trades and participants are neither ingested nor
+	// served.
+	StateOnly bool
+	// CheckMemoRequired rejects submitted transactions without a memo which
+	// send funds to accounts requiring one, see SEP-29.
+	CheckMemoRequired bool
+	// RejectLowFeeTransactions rejects submitted transactions whose fee per
+	// operation is below the minimum fee charged in the recent ledgers.
+	RejectLowFeeTransactions bool
 	// ApplyMigrations will apply pending migrations to the horizon database
 	// before starting the horizon service
 	ApplyMigrations bool
+	// AutoInitDB applies all the migrations to the horizon database before
+	// starting the horizon service when the database is empty. The ingestion
+	// then builds the state from the latest checkpoint.
+	AutoInitDB bool
+	// PathPrefix is the path under which the routes of this network are
+	// served. It is empty for the network served from the root path.
+	PathPrefix string
+	// Networks are the additional networks served by this horizon process
+	// under their path prefixes.
+	Networks []NetworkConfig
+}
+
+// NetworkConfig is the configuration of an additional network served by the
+// same horizon process. Options which are not network specific are inherited
+// from the main Config.
+type NetworkConfig struct {
+	PathPrefix             string   `json:"path_prefix"`
+	DatabaseURL            string   `json:"db_url"`
+	StellarCoreDatabaseURL string   `json:"stellar_core_db_url"`
+	StellarCoreURL         string   `json:"stellar_core_url"`
+	HistoryArchiveURLs     []string `json:"history_archive_urls"`
+	NetworkPassphrase      string   `json:"network_passphrase"`
+	Ingest                 bool     `json:"ingest"`
+	CursorName             string   `json:"cursor_name"`
+}
+
+// UsesStellarCoreDB returns true when horizon reads ledgers from the
+// stellar-core database, that is when it ingests without captive core. The API
+// only talks to stellar-core over HTTP: the core settings of the root endpoint
+// come from its info endpoint and transactions are submitted to its tx
+// endpoint, so horizon never connects to the stellar-core database otherwise.
+func (c Config) UsesStellarCoreDB() bool {
+	return c.Ingest && !c.EnableCaptiveCoreIngestion
+}
+
+// ForNetwork returns the configuration of the given additional network.
+func (c Config) ForNetwork(network NetworkConfig) Config {
+	config := c
+	config.PathPrefix = network.PathPrefix
+	config.DatabaseURL = network.DatabaseURL
+	config.StellarCoreDatabaseURL = network.StellarCoreDatabaseURL
+	config.StellarCoreURL = network.StellarCoreURL
+	config.HistoryArchiveURLs = network.HistoryArchiveURLs
+	config.NetworkPassphrase = network.NetworkPassphrase
+	config.Ingest = network.Ingest
+	if network.CursorName != "" {
+		config.CursorName = network.CursorName
+	}
+	config.Networks = nil
+	return config
 }
@@ -6,3 +6,4 @@ var AppContextKey = CtxKey("app")
 var RequestContextKey = CtxKey("request")
 var ClientContextKey = CtxKey("client")
 var SessionContextKey = CtxKey("session")
+var PathPrefixContextKey = CtxKey("path_prefix")
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -38,6 +39,22 @@ func (q *Q) GetAccountDataByAccountID(id string) ([]Data, error) {
 	return data, err
 }
 
+// GetAccountDataPage loads a page of the data entries of an account, paged by
+// the names of the entries.
+func (q *Q) GetAccountDataPage(id string, page db2.PageQuery) ([]Data, error) {
+	sql := selectAccountData.Where(sq.Eq{"account_id": id})
+	sql, err := page.ApplyToUsingCursor(sql, "name", page.Cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not apply query to page")
+	}
+
+	var data []Data
+	if err := q.Select(&data, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+	return data, nil
+}
+
 // GetAccountDataByKeys loads a row from the `accounts_data` table, selected by multiple keys.
 func (q *Q) GetAccountDataByKeys(keys []xdr.LedgerKeyData) ([]Data, error) {
 	var data []Data
@@ -228,6 +228,24 @@ func (q *Q) AccountsForAsset(asset xdr.Asset, page db2.PageQuery) ([]AccountEntr
 	return results, nil
 }
 
+// AccountsForHomeDomain returns a list of `AccountEntry` rows whose home
+// domain is `homeDomain`
+func (q *Q) AccountsForHomeDomain(homeDomain string, page db2.PageQuery) ([]AccountEntry, error) {
+	sql := selectAccounts.Where(sq.Eq{"accounts.home_domain": homeDomain})
+
+	sql, err := page.ApplyToUsingCursor(sql, "accounts.account_id", page.Cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not apply query to page")
+	}
+
+	var results []AccountEntry
+	if err := q.Select(&results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	return results, nil
+}
+
 // AccountEntriesForSigner returns a list of `AccountEntry` rows for a given signer
 func (q *Q) AccountEntriesForSigner(signer string, page db2.PageQuery) ([]AccountEntry, error) {
 	sql := sq.
@@ -0,0 +1,17 @@
+package history
+
+// BackfillCompleted returns true when the backfill `id` (see
+// schema.Backfill) is complete. Until then horizon is in the dual-write window
+// of the backfill and must not read the data it populates.
+func (q *Q) BackfillCompleted(id string) (bool, error) {
+	var completed bool
+	err := q.GetRaw(
+		&completed,
+		`SELECT completed_at IS NOT NULL FROM horizon_backfills WHERE id = $1`,
+		id,
+	)
+	if q.NoRows(err) {
+		return false, nil
+	}
+	return completed, err
+}
@@ -131,9 +131,9 @@ func (q *EffectsQ) ForTransaction(hash string) *EffectsQ {
 	return q
 }
 
-// OfType filters the query to only effects of the given type.
-func (q *EffectsQ) OfType(typ EffectType) *EffectsQ {
-	q.sql = q.sql.Where("heff.type = ?", typ)
+// OfType filters the query to only effects of the given types.
+func (q *EffectsQ) OfType(types ...EffectType) *EffectsQ {
+	q.sql = q.sql.Where(sq.Eq{"heff.type": types})
 	return q
 }
 
@@ -1,5 +1,9 @@
 package history
 
+import (
+	"github.com/stellar/go/support/errors"
+)
+
 // TruncateExpingestStateTables clears out ingestion state tables.
 // Ingestion state tables are horizon database tables populated by
 // the ingestion system using history archive snapshots.
@@ -15,3 +19,31 @@ func (q *Q) TruncateExpingestStateTables() error {
 		"trust_lines",
 	})
 }
+
+// GetInsertedRowCounts returns the number of rows inserted by the current
+// transaction, by table. The rows inserted in the partitions of a table are
+// counted in the table. It is used to report the rows written by an
+// ingestion dry run before rolling it back.
+func (q *Q) GetInsertedRowCounts() (map[string]int64, error) {
+	var rows []struct {
+		Table    string `db:"table_name"`
+		Inserted int64  `db:"inserted"`
+	}
+	err := q.SelectRaw(&rows, `
+		SELECT COALESCE(parent.relname, stats.relname) AS table_name, SUM(stats.n_tup_ins) AS inserted
+		FROM pg_stat_xact_user_tables stats
+		LEFT JOIN pg_inherits ON pg_inherits.inhrelid = stats.relid
+		LEFT JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		WHERE stats.n_tup_ins > 0
+		GROUP BY 1`,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get inserted row counts")
+	}
+
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Table] = row.Inserted
+	}
+	return counts, nil
+}
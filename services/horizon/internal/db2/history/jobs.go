@@ -0,0 +1,159 @@
+package history
+
+import (
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+	"github.com/stellar/go/support/errors"
+)
+
+// Statuses of the rows of the `horizon_jobs` table.
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Job is a row of the `horizon_jobs` table, a long-running admin operation
+// run in the background by one of the horizon instances.
+type Job struct {
+	ID   int64  `db:"id"`
+	Type string `db:"type"`
+	// Params are the JSON encoded parameters of the job.
+	Params string `db:"params"`
+	Status string `db:"status"`
+	// Progress is a free form description of the progress of a running job.
+	Progress        string      `db:"progress"`
+	Error           null.String `db:"error"`
+	CancelRequested bool        `db:"cancel_requested"`
+	CreatedAt       time.Time   `db:"created_at"`
+	StartedAt       null.Time   `db:"started_at"`
+	// HeartbeatAt is updated regularly by the instance running the job. A
+	// running job whose heartbeat is too old was abandoned by its instance.
+	HeartbeatAt null.Time `db:"heartbeat_at"`
+	FinishedAt  null.Time `db:"finished_at"`
+}
+
+// Finished returns true when the job will not run anymore.
+func (job Job) Finished() bool {
+	return job.Status == JobSucceeded || job.Status == JobFailed || job.Status == JobCancelled
+}
+
+// InsertJob adds a pending job of type `jobType`.
+func (q *Q) InsertJob(jobType, params string, now time.Time) (Job, error) {
+	var job Job
+	err := q.GetRaw(
+		&job,
+		`INSERT INTO horizon_jobs (type, params, status, created_at)
+		VALUES ($1, $2, $3, $4) RETURNING *`,
+		jobType, params, JobPending, now.UTC(),
+	)
+	return job, errors.Wrap(err, "could not insert job")
+}
+
+// GetJob returns the job `id`.
+func (q *Q) GetJob(id int64) (Job, error) {
+	var job Job
+	err := q.GetRaw(&job, `SELECT * FROM horizon_jobs WHERE id = $1`, id)
+	return job, err
+}
+
+// GetJobs returns the latest `limit` jobs, the most recent first.
+func (q *Q) GetJobs(limit uint64) ([]Job, error) {
+	var jobs []Job
+	err := q.SelectRaw(&jobs, `SELECT * FROM horizon_jobs ORDER BY id DESC LIMIT $1`, limit)
+	return jobs, errors.Wrap(err, "could not get jobs")
+}
+
+// ClaimJob marks the oldest job of one of `jobTypes` which is pending, or
+// running with a heartbeat older than `staleBefore`, as running and returns
+// it. The second returned value is false when there is no such job. Instances
+// claiming jobs concurrently never claim the same job.
+func (q *Q) ClaimJob(jobTypes []string, staleBefore, now time.Time) (Job, bool, error) {
+	var job Job
+	err := q.GetRaw(
+		&job,
+		`UPDATE horizon_jobs SET
+			status = $1,
+			started_at = COALESCE(started_at, $2),
+			heartbeat_at = $2
+		WHERE id = (
+			SELECT id FROM horizon_jobs
+			WHERE type = ANY($3) AND NOT cancel_requested AND (
+				status = $4 OR (status = $1 AND heartbeat_at < $5)
+			)
+			ORDER BY id LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		) RETURNING *`,
+		JobRunning, now.UTC(), pq.Array(jobTypes), JobPending, staleBefore.UTC(),
+	)
+	if q.NoRows(err) {
+		return Job{}, false, nil
+	}
+	if err != nil {
+		return Job{}, false, errors.Wrap(err, "could not claim job")
+	}
+	return job, true, nil
+}
+
+// UpdateJobHeartbeat records that the running job `id` is still running and
+// its progress. It returns true when the cancellation of the job was
+// requested.
+func (q *Q) UpdateJobHeartbeat(id int64, progress string, now time.Time) (bool, error) {
+	var cancelRequested bool
+	err := q.GetRaw(
+		&cancelRequested,
+		`UPDATE horizon_jobs SET heartbeat_at = $1, progress = $2
+		WHERE id = $3 RETURNING cancel_requested`,
+		now.UTC(), progress, id,
+	)
+	return cancelRequested, errors.Wrap(err, "could not update job heartbeat")
+}
+
+// FinishJob records that the job `id` finished with `status`. `jobErr` is
+// the error the job failed with, if any.
+func (q *Q) FinishJob(id int64, status string, jobErr error, now time.Time) error {
+	var errMessage null.String
+	if jobErr != nil {
+		errMessage = null.StringFrom(jobErr.Error())
+	}
+	_, err := q.ExecRaw(
+		`UPDATE horizon_jobs SET status = $1, error = $2, finished_at = $3 WHERE id = $4`,
+		status, errMessage, now.UTC(), id,
+	)
+	return errors.Wrap(err, "could not finish job")
+}
+
+// ReleaseJob makes the running job `id` pending again so that another
+// instance resumes it, used when the instance running it shuts down.
+func (q *Q) ReleaseJob(id int64) error {
+	_, err := q.ExecRaw(
+		`UPDATE horizon_jobs SET status = $1, heartbeat_at = NULL WHERE id = $2 AND status = $3`,
+		JobPending, id, JobRunning,
+	)
+	return errors.Wrap(err, "could not release job")
+}
+
+// RequestJobCancellation requests the cancellation of the job `id`. Pending
+// jobs are cancelled immediately, running jobs are cancelled by their
+// instance at their next heartbeat. Finished jobs are left untouched.
+func (q *Q) RequestJobCancellation(id int64, now time.Time) (Job, error) {
+	var job Job
+	err := q.GetRaw(
+		&job,
+		`UPDATE horizon_jobs SET
+			cancel_requested = true,
+			status = CASE WHEN status = $1 THEN $2 ELSE status END,
+			finished_at = CASE WHEN status = $1 THEN $3 ELSE finished_at END
+		WHERE id = $4 AND status IN ($1, $5) RETURNING *`,
+		JobPending, JobCancelled, now.UTC(), id, JobRunning,
+	)
+	if q.NoRows(err) {
+		// the job is unknown or already finished
+		return q.GetJob(id)
+	}
+	return job, err
+}
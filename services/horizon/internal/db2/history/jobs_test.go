@@ -0,0 +1,89 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestJobs(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	first, err := q.InsertJob("reap_history", "{}", now)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(JobPending, first.Status)
+	second, err := q.InsertJob("reingest_range", `{"from": 2, "to": 10}`, now)
+	tt.Assert.NoError(err)
+
+	// only jobs of the given types are claimed
+	_, ok, err := q.ClaimJob([]string{"unknown"}, now, now)
+	tt.Assert.NoError(err)
+	tt.Assert.False(ok)
+
+	job, ok, err := q.ClaimJob([]string{"reap_history", "reingest_range"}, now, now)
+	tt.Assert.NoError(err)
+	tt.Assert.True(ok)
+	tt.Assert.Equal(first.ID, job.ID)
+	tt.Assert.Equal(JobRunning, job.Status)
+	tt.Assert.True(job.StartedAt.Valid)
+
+	job, ok, err = q.ClaimJob([]string{"reap_history", "reingest_range"}, now, now)
+	tt.Assert.NoError(err)
+	tt.Assert.True(ok)
+	tt.Assert.Equal(second.ID, job.ID)
+	tt.Assert.JSONEq(`{"from": 2, "to": 10}`, job.Params)
+
+	// running jobs with a stale heartbeat are claimed again
+	_, ok, err = q.ClaimJob([]string{"reap_history"}, now, now)
+	tt.Assert.NoError(err)
+	tt.Assert.False(ok)
+	later := now.Add(time.Hour)
+	job, ok, err = q.ClaimJob([]string{"reap_history"}, later.Add(-time.Minute), later)
+	tt.Assert.NoError(err)
+	tt.Assert.True(ok)
+	tt.Assert.Equal(first.ID, job.ID)
+
+	cancelRequested, err := q.UpdateJobHeartbeat(second.ID, "ledger 5", later)
+	tt.Assert.NoError(err)
+	tt.Assert.False(cancelRequested)
+
+	job, err = q.RequestJobCancellation(second.ID, later)
+	tt.Assert.NoError(err)
+	tt.Assert.True(job.CancelRequested)
+	tt.Assert.Equal(JobRunning, job.Status)
+	tt.Assert.Equal("ledger 5", job.Progress)
+
+	cancelRequested, err = q.UpdateJobHeartbeat(second.ID, "ledger 6", later)
+	tt.Assert.NoError(err)
+	tt.Assert.True(cancelRequested)
+	tt.Assert.NoError(q.FinishJob(second.ID, JobCancelled, nil, later))
+
+	tt.Assert.NoError(q.FinishJob(first.ID, JobFailed, errors.New("boom"), later))
+	job, err = q.GetJob(first.ID)
+	tt.Assert.NoError(err)
+	tt.Assert.True(job.Finished())
+	tt.Assert.Equal("boom", job.Error.String)
+
+	// pending jobs are cancelled immediately, finished jobs are untouched
+	third, err := q.InsertJob("reap_history", "{}", later)
+	tt.Assert.NoError(err)
+	job, err = q.RequestJobCancellation(third.ID, later)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(JobCancelled, job.Status)
+	job, err = q.RequestJobCancellation(first.ID, later)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(JobFailed, job.Status)
+	_, err = q.RequestJobCancellation(1000, later)
+	tt.Assert.True(q.NoRows(err))
+
+	jobs, err := q.GetJobs(2)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(jobs, 2)
+	tt.Assert.Equal(third.ID, jobs[0].ID)
+}
@@ -16,6 +16,7 @@ const (
 	lastLedgerKey           = "exp_ingest_last_ledger"
 	stateInvalid            = "exp_state_invalid"
 	offerCompactionSequence = "offer_compaction_sequence"
+	cdcLastPublishedLedger  = "cdc_last_published_ledger"
 )
 
 // GetLastLedgerExpIngestNonBlocking works like GetLastLedgerExpIngest but
@@ -191,3 +192,33 @@ func (q *Q) updateValueInStore(key, value string) error {
 	_, err := q.Exec(query)
 	return err
 }
+
+// GetLastLedgerCDCPublished returns the last ledger whose history rows were
+// published by the change-data-capture publisher, or zero if nothing has been
+// published yet. Like GetLastLedgerExpIngest, it is using `SELECT ... FOR
+// UPDATE` so that a single instance publishes a ledger.
+func (q *Q) GetLastLedgerCDCPublished() (uint32, error) {
+	sequence, err := q.getValueFromStore(cdcLastPublishedLedger, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if sequence == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseUint(sequence, 10, 32)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error converting sequence value")
+	}
+
+	return uint32(parsed), nil
+}
+
+// UpdateLastLedgerCDCPublished updates the last ledger published by the
+// change-data-capture publisher.
+func (q *Q) UpdateLastLedgerCDCPublished(sequence uint32) error {
+	return q.updateValueInStore(
+		cdcLastPublishedLedger,
+		strconv.FormatUint(uint64(sequence), 10),
+	)
+}
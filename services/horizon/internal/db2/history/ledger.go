@@ -1,6 +1,7 @@
 package history
 
 import (
+	"database/sql"
 	"encoding/hex"
 	"fmt"
 	"time"
@@ -65,6 +66,71 @@ func (q *Q) LedgerCapacityUsageStats(currentSeq int32, dest *LedgerCapacityUsage
 	`, currentSeq-ledgers, currentSeq)
 }
 
+// GetLedgerGaps returns the ranges of ledgers missing from the
+// `history_ledgers` table between the oldest and the latest ingested ledgers.
+func (q *Q) GetLedgerGaps() ([]LedgerRange, error) {
+	var gaps []LedgerRange
+	err := q.SelectRaw(&gaps, `
+		SELECT sequence + 1 AS start, next_sequence - 1 AS "end"
+		FROM (
+			SELECT sequence, LEAD(sequence) OVER (ORDER BY sequence) AS next_sequence
+			FROM history_ledgers
+		) AS ledgers
+		WHERE next_sequence > sequence + 1
+		ORDER BY sequence
+	`)
+	return gaps, err
+}
+
+// ClosedWithin filters the query to only ledgers closed within the time
+// range `r`.
+func (q *LedgersQ) ClosedWithin(r TimeRange) *LedgersQ {
+	q.sql = r.apply(q.sql, "hl.closed_at")
+	return q
+}
+
+// LedgerSequencesClosedWithin returns the first and the last sequence of the
+// ledgers closed within the time range `r`. `found` is false when no ledger
+// was closed within the range.
+func (q *Q) LedgerSequencesClosedWithin(r TimeRange) (first, last int32, found bool, err error) {
+	var bounds struct {
+		First sql.NullInt64 `db:"first"`
+		Last  sql.NullInt64 `db:"last"`
+	}
+	query := r.apply(
+		sq.Select("MIN(hl.sequence) AS first", "MAX(hl.sequence) AS last").
+			From("history_ledgers hl"),
+		"hl.closed_at",
+	)
+	err = q.Get(&bounds, query)
+	if err != nil || !bounds.First.Valid {
+		return 0, 0, false, err
+	}
+
+	return int32(bounds.First.Int64), int32(bounds.Last.Int64), true, nil
+}
+
+// closedWithinPredicate returns the predicate on the toid `column` matching
+// the rows of ledgers closed within the time range `r`. The range is first
+// resolved to a range of ledger sequences so the predicate applies to ids.
+func (q *Q) closedWithinPredicate(r TimeRange, column string) (sq.Sqlizer, error) {
+	first, last, found, err := q.LedgerSequencesClosedWithin(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load ledger sequences")
+	}
+	if !found {
+		return sq.Expr("false"), nil
+	}
+
+	start := toid.ID{LedgerSequence: first}
+	end := toid.ID{LedgerSequence: last + 1}
+	return sq.Expr(
+		fmt.Sprintf("%s >= ? AND %s < ?", column, column),
+		start.ToInt64(),
+		end.ToInt64(),
+	), nil
+}
+
 // Page specifies the paging constraints for the query being built by `q`.
 func (q *LedgersQ) Page(page db2.PageQuery) *LedgersQ {
 	if q.Err != nil {
@@ -187,3 +253,15 @@ var selectLedger = sq.Select(
 	"hl.protocol_version",
 	"hl.ledger_header",
 ).From("history_ledgers hl")
+
+// apply adds the bounds of the time range `r` to `query`, comparing them with
+// `column`.
+func (r TimeRange) apply(query sq.SelectBuilder, column string) sq.SelectBuilder {
+	if !r.Start.IsNil() {
+		query = query.Where(column+" >= ?", r.Start.ToTime())
+	}
+	if !r.End.IsNil() {
+		query = query.Where(column+" < ?", r.End.ToTime())
+	}
+	return query
+}
@@ -0,0 +1,180 @@
+package history
+
+import (
+	"database/sql"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// GetLedgerEntry rebuilds the ledger entry identified by `key` from the state
+// tables. sql.ErrNoRows is returned when the entry does not exist. The
+// extensions of accounts and trust lines are only set when they have
+// liabilities, like the entries of stellar-core.
+func (q *Q) GetLedgerEntry(key xdr.LedgerKey) (xdr.LedgerEntry, error) {
+	switch key.Type {
+	case xdr.LedgerEntryTypeAccount:
+		return q.getAccountLedgerEntry(key.MustAccount())
+	case xdr.LedgerEntryTypeTrustline:
+		return q.getTrustLineLedgerEntry(key.MustTrustLine())
+	case xdr.LedgerEntryTypeOffer:
+		return q.getOfferLedgerEntry(key.MustOffer())
+	case xdr.LedgerEntryTypeData:
+		return q.getDataLedgerEntry(key.MustData())
+	default:
+		return xdr.LedgerEntry{}, errors.Errorf("unknown ledger key type: %d", key.Type)
+	}
+}
+
+func (q *Q) getAccountLedgerEntry(key xdr.LedgerKeyAccount) (xdr.LedgerEntry, error) {
+	accountID := key.AccountId.Address()
+	row, err := q.GetAccountByID(accountID)
+	if err != nil {
+		return xdr.LedgerEntry{}, err
+	}
+
+	signerRows, err := q.SignersForAccounts([]string{accountID})
+	if err != nil {
+		return xdr.LedgerEntry{}, errors.Wrap(err, "could not load signers")
+	}
+	var signers []xdr.Signer
+	for _, signer := range signerRows {
+		// the master key is stored as a signer but is part of the thresholds
+		// in the ledger entry
+		if signer.Signer == accountID {
+			continue
+		}
+		signers = append(signers, xdr.Signer{
+			Key:    xdr.MustSigner(signer.Signer),
+			Weight: xdr.Uint32(signer.Weight),
+		})
+	}
+
+	var inflationDest *xdr.AccountId
+	if row.InflationDestination != "" {
+		dest := xdr.MustAddress(row.InflationDestination)
+		inflationDest = &dest
+	}
+
+	entry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(row.LastModifiedLedger),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId:     key.AccountId,
+				Balance:       xdr.Int64(row.Balance),
+				SeqNum:        xdr.SequenceNumber(row.SequenceNumber),
+				NumSubEntries: xdr.Uint32(row.NumSubEntries),
+				InflationDest: inflationDest,
+				Flags:         xdr.Uint32(row.Flags),
+				HomeDomain:    xdr.String32(row.HomeDomain),
+				Thresholds: xdr.Thresholds{
+					row.MasterWeight,
+					row.ThresholdLow,
+					row.ThresholdMedium,
+					row.ThresholdHigh,
+				},
+				Signers: xdr.SortSignersByKey(signers),
+			},
+		},
+	}
+	// stellar-core only adds the extension to entries with liabilities
+	if row.BuyingLiabilities != 0 || row.SellingLiabilities != 0 {
+		entry.Data.Account.Ext = xdr.AccountEntryExt{
+			V: 1,
+			V1: &xdr.AccountEntryV1{
+				Liabilities: xdr.Liabilities{
+					Buying:  xdr.Int64(row.BuyingLiabilities),
+					Selling: xdr.Int64(row.SellingLiabilities),
+				},
+			},
+		}
+	}
+	return entry, nil
+}
+
+func (q *Q) getTrustLineLedgerEntry(key xdr.LedgerKeyTrustLine) (xdr.LedgerEntry, error) {
+	rows, err := q.GetTrustLinesByKeys([]xdr.LedgerKeyTrustLine{key})
+	if err != nil {
+		return xdr.LedgerEntry{}, err
+	}
+	if len(rows) == 0 {
+		return xdr.LedgerEntry{}, sql.ErrNoRows
+	}
+
+	row := rows[0]
+	entry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(row.LastModifiedLedger),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTrustline,
+			TrustLine: &xdr.TrustLineEntry{
+				AccountId: key.AccountId,
+				Asset:     key.Asset,
+				Balance:   xdr.Int64(row.Balance),
+				Limit:     xdr.Int64(row.Limit),
+				Flags:     xdr.Uint32(row.Flags),
+			},
+		},
+	}
+	if row.BuyingLiabilities != 0 || row.SellingLiabilities != 0 {
+		entry.Data.TrustLine.Ext = xdr.TrustLineEntryExt{
+			V: 1,
+			V1: &xdr.TrustLineEntryV1{
+				Liabilities: xdr.Liabilities{
+					Buying:  xdr.Int64(row.BuyingLiabilities),
+					Selling: xdr.Int64(row.SellingLiabilities),
+				},
+			},
+		}
+	}
+	return entry, nil
+}
+
+func (q *Q) getOfferLedgerEntry(key xdr.LedgerKeyOffer) (xdr.LedgerEntry, error) {
+	row, err := q.GetOfferByID(int64(key.OfferId))
+	if err != nil {
+		return xdr.LedgerEntry{}, err
+	}
+	// the key of an offer includes its seller
+	if row.SellerID != key.SellerId.Address() {
+		return xdr.LedgerEntry{}, sql.ErrNoRows
+	}
+
+	return xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(row.LastModifiedLedger),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeOffer,
+			Offer: &xdr.OfferEntry{
+				SellerId: key.SellerId,
+				OfferId:  row.OfferID,
+				Selling:  row.SellingAsset,
+				Buying:   row.BuyingAsset,
+				Amount:   row.Amount,
+				Price: xdr.Price{
+					N: xdr.Int32(row.Pricen),
+					D: xdr.Int32(row.Priced),
+				},
+				Flags: xdr.Uint32(row.Flags),
+			},
+		},
+	}, nil
+}
+
+func (q *Q) getDataLedgerEntry(key xdr.LedgerKeyData) (xdr.LedgerEntry, error) {
+	row, err := q.GetAccountDataByName(key.AccountId.Address(), string(key.DataName))
+	if err != nil {
+		return xdr.LedgerEntry{}, err
+	}
+
+	return xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(row.LastModifiedLedger),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeData,
+			Data: &xdr.DataEntry{
+				AccountId: key.AccountId,
+				DataName:  key.DataName,
+				DataValue: xdr.DataValue(row.Value),
+			},
+		},
+	}, nil
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/guregu/null"
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/services/horizon/internal/test"
 	"github.com/stellar/go/services/horizon/internal/toid"
+	strtime "github.com/stellar/go/support/time"
 	"github.com/stellar/go/xdr"
 )
 
@@ -50,6 +52,54 @@ func TestLedgerQueries(t *testing.T) {
 	}
 }
 
+func TestGetLedgerGaps(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &Q{tt.HorizonSession()}
+
+	gaps, err := q.GetLedgerGaps()
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(gaps)
+
+	_, err = q.ExecRaw("DELETE FROM history_ledgers WHERE sequence = 2")
+	tt.Assert.NoError(err)
+
+	gaps, err = q.GetLedgerGaps()
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]LedgerRange{{StartSequence: 2, EndSequence: 2}}, gaps)
+}
+
+func TestLedgersClosedWithin(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &Q{tt.HorizonSession()}
+
+	// ledger 2 closed at 2019-10-31T13:19:45Z and ledger 3 one second later
+	r := TimeRange{Start: strtime.MillisFromInt64(1572527985000)}
+
+	var ledgers []Ledger
+	err := q.Ledgers().ClosedWithin(r).Page(db2.MustPageQuery("", false, "asc", 10)).Select(&ledgers)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(ledgers, 2)
+
+	first, last, found, err := q.LedgerSequencesClosedWithin(r)
+	tt.Assert.NoError(err)
+	tt.Assert.True(found)
+	tt.Assert.Equal(int32(2), first)
+	tt.Assert.Equal(int32(3), last)
+
+	r.End = r.Start + 1000
+	first, last, found, err = q.LedgerSequencesClosedWithin(r)
+	tt.Assert.NoError(err)
+	tt.Assert.True(found)
+	tt.Assert.Equal(int32(2), first)
+	tt.Assert.Equal(int32(2), last)
+
+	_, _, found, err = q.LedgerSequencesClosedWithin(TimeRange{Start: r.Start + 10000})
+	tt.Assert.NoError(err)
+	tt.Assert.False(found)
+}
+
 func TestInsertLedger(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
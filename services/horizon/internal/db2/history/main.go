@@ -15,6 +15,7 @@ import (
 	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
+	strtime "github.com/stellar/go/support/time"
 	"github.com/stellar/go/xdr"
 )
 
@@ -170,6 +171,7 @@ type IngestionQ interface {
 	//QTrades
 	NewTradeBatchInsertBuilder(maxBatchSize int) TradeBatchInsertBuilder
 	CreateAssets(assets []xdr.Asset, batchSize int) (map[string]Asset, error)
+	RebuildTradeRollups(from, to time.Time) error
 	QTransactions
 	QTrustLines
 
@@ -187,6 +189,7 @@ type IngestionQ interface {
 	GetOfferCompactionSequence() (uint32, error)
 	TruncateExpingestStateTables() error
 	DeleteRangeAll(start, end int64) error
+	GetInsertedRowCounts() (map[string]int64, error)
 }
 
 // QAccounts defines account related queries.
@@ -384,6 +387,12 @@ type LatestLedger struct {
 	Sequence int32 `db:"sequence"`
 }
 
+// LedgerRange represents an inclusive range of ledger sequences.
+type LedgerRange struct {
+	StartSequence uint32 `db:"start" json:"start"`
+	EndSequence   uint32 `db:"end" json:"end"`
+}
+
 // Ledger is a row of data from the `history_ledgers` table
 type Ledger struct {
 	TotalOrderID
@@ -422,6 +431,14 @@ type LedgerCache struct {
 	queued map[int32]struct{}
 }
 
+// TimeRange is a range of ledger close times used to filter ledgers and
+// transactions. Start is inclusive and End is exclusive, a nil bound leaves
+// that side of the range open.
+type TimeRange struct {
+	Start strtime.Millis
+	End   strtime.Millis
+}
+
 // LedgersQ is a helper struct to aid in configuring queries that loads
 // slices of Ledger structs.
 type LedgersQ struct {
@@ -512,6 +529,10 @@ type OffersQuery struct {
 	SellerID  string
 	Selling   *xdr.Asset
 	Buying    *xdr.Asset
+	// OrderByPrice orders the offers by price (and offer id) instead of offer
+	// id. The cursor of PageQuery must then be a paging token returned by
+	// Offer.PricePagingToken.
+	OrderByPrice bool
 }
 
 // TotalOrderID represents the ID portion of rows that are identified by the
@@ -664,6 +685,17 @@ func (q *Q) LatestLedger(dest interface{}) error {
 	return q.GetRaw(dest, `SELECT COALESCE(MAX(sequence), 0) FROM history_ledgers`)
 }
 
+// LatestLedgerClosedAt loads the close time of the latest known ledger. It
+// returns the zero time if there are no ledgers in `history_ledgers` table.
+func (q *Q) LatestLedgerClosedAt() (time.Time, error) {
+	var closedAt time.Time
+	err := q.GetRaw(&closedAt, `SELECT closed_at FROM history_ledgers ORDER BY sequence DESC LIMIT 1`)
+	if q.NoRows(err) {
+		return time.Time{}, nil
+	}
+	return closedAt, err
+}
+
 // LatestLedgerBaseFeeAndSequence loads the latest known ledger's base fee and
 // sequence number.
 func (q *Q) LatestLedgerBaseFeeAndSequence(dest interface{}) error {
@@ -693,7 +725,18 @@ func (q *Q) CloneIngestionQ() IngestionQ {
 // DeleteRangeAll deletes a range of rows from all history tables between
 // `start` and `end` (exclusive).
 func (q *Q) DeleteRangeAll(start, end int64) error {
-	err := q.DeleteRange(start, end, "history_effects", "history_operation_id")
+	var closedAt struct {
+		First *time.Time `db:"first"`
+		Last  *time.Time `db:"last"`
+	}
+	err := q.Get(&closedAt, sq.Select("MIN(closed_at) AS first", "MAX(closed_at) AS last").
+		From("history_ledgers").
+		Where("id >= ? AND id < ?", start, end))
+	if err != nil {
+		return errors.Wrap(err, "Error loading closing times of ledgers")
+	}
+
+	err = q.DeleteRange(start, end, "history_effects", "history_operation_id")
 	if err != nil {
 		return errors.Wrap(err, "Error clearing history_effects")
 	}
@@ -721,6 +764,14 @@ func (q *Q) DeleteRangeAll(start, end int64) error {
 	if err != nil {
 		return errors.Wrap(err, "Error clearing history_trades")
 	}
+	// Trades of the neighbouring ledgers can share buckets with the deleted
+	// ones so the rollups are rebuilt rather than deleted.
+	if closedAt.First != nil {
+		err = q.RebuildTradeRollups(*closedAt.First, *closedAt.Last)
+		if err != nil {
+			return errors.Wrap(err, "Error rebuilding history_trade_rollups")
+		}
+	}
 
 	return nil
 }
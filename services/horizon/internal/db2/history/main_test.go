@@ -30,6 +30,25 @@ func TestGetLatestLedgerEmptyDB(t *testing.T) {
 	tt.Assert.Equal(uint32(0), value)
 }
 
+func TestLatestLedgerClosedAt(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+	q := &Q{tt.HorizonSession()}
+
+	var ledger Ledger
+	tt.Assert.NoError(q.LedgerBySequence(&ledger, 3))
+
+	closedAt, err := q.LatestLedgerClosedAt()
+	if tt.Assert.NoError(err) {
+		tt.Assert.True(ledger.ClosedAt.Equal(closedAt))
+	}
+
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	closedAt, err = q.LatestLedgerClosedAt()
+	tt.Assert.NoError(err)
+	tt.Assert.True(closedAt.IsZero())
+}
+
 func TestElderLedger(t *testing.T) {
 	tt := test.Start(t).Scenario("base")
 	defer tt.Finish()
@@ -0,0 +1,121 @@
+package history
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stellar/go/support/errors"
+)
+
+const maintenanceLastRun = "maintenance_last_run"
+
+// HotHistoryTables are the history tables written with every ingested ledger,
+// which need to be vacuumed and analyzed most often.
+var HotHistoryTables = []string{
+	"history_ledgers",
+	"history_transactions",
+	"history_operations",
+	"history_effects",
+	"history_trades",
+	"history_transaction_participants",
+	"history_operation_participants",
+}
+
+// IndexBloat is the estimated bloat of a btree index.
+type IndexBloat struct {
+	Table string `db:"table_name"`
+	Index string `db:"index_name"`
+	// Size is the size of the index in bytes.
+	Size int64 `db:"size"`
+	// ExpectedSize is the estimated size in bytes of the index once rebuilt,
+	// based on the number of rows of the table and the average width of the
+	// indexed columns.
+	ExpectedSize int64 `db:"expected_size"`
+}
+
+// BloatRatio is the estimated fraction of the index which is wasted space.
+func (b IndexBloat) BloatRatio() float64 {
+	if b.Size <= 0 || b.ExpectedSize >= b.Size {
+		return 0
+	}
+	return float64(b.Size-b.ExpectedSize) / float64(b.Size)
+}
+
+// GetLastMaintenanceRun returns the time the database maintenance last
+// started, or the zero time if it never ran. Like GetLastLedgerExpIngest, it
+// is using `SELECT ... FOR UPDATE` so that a single instance starts the
+// maintenance.
+func (q *Q) GetLastMaintenanceRun() (time.Time, error) {
+	value, err := q.getValueFromStore(maintenanceLastRun, true)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+
+	lastRun, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "Error parsing last maintenance run")
+	}
+	return lastRun, nil
+}
+
+// UpdateLastMaintenanceRun updates the time the database maintenance last
+// started.
+func (q *Q) UpdateLastMaintenanceRun(lastRun time.Time) error {
+	return q.updateValueInStore(maintenanceLastRun, lastRun.UTC().Format(time.RFC3339))
+}
+
+// VacuumAnalyze runs VACUUM ANALYZE on `table`, and on all its partitions
+// when it is partitioned. It must not be called in a transaction.
+func (q *Q) VacuumAnalyze(table string) error {
+	_, err := q.ExecRaw("VACUUM ANALYZE " + pq.QuoteIdentifier(table))
+	return errors.Wrapf(err, "could not vacuum %s", table)
+}
+
+// EstimateIndexBloat estimates the bloat of the btree indexes of `tables` and
+// of their partitions. The estimate relies on the statistics of the tables and
+// is only meaningful once they have been analyzed.
+func (q *Q) EstimateIndexBloat(tables []string) ([]IndexBloat, error) {
+	var bloat []IndexBloat
+	// An index tuple is an 8 bytes header followed by the indexed columns
+	// aligned on 8 bytes, and a 4 bytes line pointer. Pages have a 24 bytes
+	// header, a 16 bytes btree trailer and are filled at 90% when the index
+	// is built.
+	err := q.SelectRaw(&bloat, `
+		WITH indexes AS (
+			SELECT
+				COALESCE(parent.relname, t.relname) AS table_name,
+				c.relname AS index_name,
+				c.relpages,
+				c.reltuples,
+				(
+					SELECT COALESCE(SUM(s.avg_width), 0)
+					FROM pg_attribute a
+					JOIN pg_stats s ON s.schemaname = n.nspname AND s.tablename = t.relname AND s.attname = a.attname
+					WHERE a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+				) AS data_width
+			FROM pg_index i
+			JOIN pg_class c ON c.oid = i.indexrelid
+			JOIN pg_class t ON t.oid = i.indrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_am am ON am.oid = c.relam
+			LEFT JOIN pg_inherits inh ON inh.inhrelid = t.oid
+			LEFT JOIN pg_class parent ON parent.oid = inh.inhparent
+			WHERE am.amname = 'btree'
+			AND n.nspname = current_schema()
+			AND COALESCE(parent.relname, t.relname) = ANY($1)
+			AND c.relpages > 0
+		)
+		SELECT
+			table_name,
+			index_name,
+			relpages::bigint * current_setting('block_size')::bigint AS size,
+			CEIL(
+				reltuples * (12 + CEIL(data_width / 8.0) * 8) /
+				((current_setting('block_size')::numeric - 40) * 0.9)
+			)::bigint * current_setting('block_size')::bigint AS expected_size
+		FROM indexes
+		ORDER BY table_name, index_name`,
+		pq.Array(tables),
+	)
+	return bloat, errors.Wrap(err, "could not estimate index bloat")
+}
@@ -1,6 +1,8 @@
 package history
 
 import (
+	"time"
+
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/mock"
 )
@@ -24,6 +26,11 @@ func (m *MockQTrades) NewTradeBatchInsertBuilder(maxBatchSize int) TradeBatchIns
 	return a.Get(0).(TradeBatchInsertBuilder)
 }
 
+func (m *MockQTrades) RebuildTradeRollups(from, to time.Time) error {
+	a := m.Called(from, to)
+	return a.Error(0)
+}
+
 type MockTradeBatchInsertBuilder struct {
 	mock.Mock
 }
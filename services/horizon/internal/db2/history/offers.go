@@ -1,7 +1,12 @@
 package history
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	sq "github.com/Masterminds/squirrel"
+	"github.com/stellar/go/services/horizon/internal/db2"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -50,7 +55,12 @@ func (q *Q) GetOffersByIDs(ids []int64) ([]Offer, error) {
 // GetOffers loads rows from `offers` by paging query.
 func (q *Q) GetOffers(query OffersQuery) ([]Offer, error) {
 	sql := selectOffers.Where("deleted = ?", false)
-	sql, err := query.PageQuery.ApplyTo(sql, "offers.offer_id")
+	var err error
+	if query.OrderByPrice {
+		sql, err = applyOffersPriceOrder(sql, query.PageQuery)
+	} else {
+		sql, err = query.PageQuery.ApplyTo(sql, "offers.offer_id")
+	}
 
 	if err != nil {
 		return nil, errors.Wrap(err, "could not apply query to page")
@@ -84,6 +94,64 @@ func (q *Q) GetOffers(query OffersQuery) ([]Offer, error) {
 	return offers, nil
 }
 
+// offerPriceCursorSep separates the price from the offer id in the paging
+// tokens of offers ordered by price.
+const offerPriceCursorSep = "_"
+
+// PricePagingToken returns the paging token of the offer when offers are
+// ordered by price. It contains both the price and the offer id so the
+// position of the offer can be found even after it was removed.
+func (o Offer) PricePagingToken() string {
+	return strconv.FormatFloat(o.Price, 'f', -1, 64) + offerPriceCursorSep + strconv.FormatInt(int64(o.OfferID), 10)
+}
+
+// ParseOfferPriceCursor parses a paging token created by
+// Offer.PricePagingToken.
+func ParseOfferPriceCursor(cursor string) (float64, int64, error) {
+	parts := strings.Split(cursor, offerPriceCursorSep)
+	if len(parts) != 2 {
+		return 0, 0, errors.New("invalid price cursor")
+	}
+
+	price, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || price < 0 {
+		return 0, 0, errors.New("invalid price in cursor")
+	}
+	offerID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || offerID < 0 {
+		return 0, 0, errors.New("invalid offer id in cursor")
+	}
+
+	return price, offerID, nil
+}
+
+func applyOffersPriceOrder(sql sq.SelectBuilder, page db2.PageQuery) (sq.SelectBuilder, error) {
+	var op, order string
+	switch page.Order {
+	case db2.OrderAscending:
+		op, order = ">", "asc"
+	case db2.OrderDescending:
+		op, order = "<", "desc"
+	default:
+		return sql, errors.Errorf("invalid order: %s", page.Order)
+	}
+
+	if page.Cursor != "" {
+		price, offerID, err := ParseOfferPriceCursor(page.Cursor)
+		if err != nil {
+			return sql, err
+		}
+		sql = sql.Where(
+			fmt.Sprintf("(offers.price, offers.offer_id) %s (?, ?)", op),
+			price, offerID,
+		)
+	}
+
+	return sql.
+		OrderBy("offers.price "+order, "offers.offer_id "+order).
+		Limit(page.Limit), nil
+}
+
 // GetAllOffers loads all non deleted offers
 func (q *Q) GetAllOffers() ([]Offer, error) {
 	var offers []Offer
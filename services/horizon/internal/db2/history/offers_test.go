@@ -482,3 +482,74 @@ func TestGetOffers(t *testing.T) {
 		assertOfferEntryMatchesDBOffer(t, twoEurOffer, offers[0], 1235)
 	})
 }
+
+func TestGetOffersOrderByPrice(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	halfEurOffer := eurOffer
+	halfEurOffer.OfferId = 60
+	halfEurOffer.Price = xdr.Price{N: 1, D: 2}
+
+	usdOffer := eurOffer
+	usdOffer.OfferId = 70
+	usdOffer.Buying = usdAsset
+
+	for _, offer := range []xdr.OfferEntry{eurOffer, twoEurOffer, halfEurOffer, usdOffer} {
+		tt.Assert.NoError(insertOffer(q, offer, 1234))
+	}
+
+	pageQuery, err := db2.NewPageQuery("", false, "asc", 2)
+	tt.Assert.NoError(err)
+	query := OffersQuery{
+		PageQuery:    pageQuery,
+		SellerID:     issuer.Address(),
+		Selling:      &nativeAsset,
+		Buying:       &eurAsset,
+		OrderByPrice: true,
+	}
+
+	// seller and market filters are combined
+	offers, err := q.GetOffers(query)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(offers, 2)
+	tt.Assert.Equal(halfEurOffer.OfferId, offers[0].OfferID)
+	tt.Assert.Equal(eurOffer.OfferId, offers[1].OfferID)
+	tt.Assert.Equal("0.5_60", offers[0].PricePagingToken())
+
+	query.SellerID = ""
+	query.PageQuery.Cursor = offers[1].PricePagingToken()
+	offers, err = q.GetOffers(query)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(offers, 1)
+	tt.Assert.Equal(twoEurOffer.OfferId, offers[0].OfferID)
+
+	query.PageQuery.Order = db2.OrderDescending
+	query.PageQuery.Cursor = ""
+	offers, err = q.GetOffers(query)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(offers, 2)
+	tt.Assert.Equal(twoEurOffer.OfferId, offers[0].OfferID)
+	tt.Assert.Equal(eurOffer.OfferId, offers[1].OfferID)
+
+	query.PageQuery.Cursor = "invalid"
+	_, err = q.GetOffers(query)
+	tt.Assert.Error(err)
+}
+
+func TestParseOfferPriceCursor(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+
+	price, offerID, err := ParseOfferPriceCursor("0.3333333333333333_12")
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(float64(1)/3, price)
+	tt.Assert.Equal(int64(12), offerID)
+
+	for _, cursor := range []string{"", "12", "a_12", "1_b", "-1_12", "1_2_3"} {
+		_, _, err = ParseOfferPriceCursor(cursor)
+		tt.Assert.Error(err, cursor)
+	}
+}
@@ -3,6 +3,7 @@ package history
 import (
 	"database/sql"
 	"github.com/stellar/go/amount"
+	"math"
 	"math/big"
 
 	"github.com/stellar/go/support/errors"
@@ -16,6 +17,12 @@ type priceLevel struct {
 	Price  float64 `db:"price"`
 }
 
+type aggregatedPriceLevel struct {
+	Type   string `db:"type"`
+	Level  int64  `db:"level"`
+	Amount string `db:"amount"`
+}
+
 type offerSummary struct {
 	Type   string  `db:"type"`
 	Amount string  `db:"amount"`
@@ -153,3 +160,88 @@ func (q *Q) GetOrderBookSummary(sellingAsset, buyingAsset xdr.Asset, maxPriceLev
 
 	return result, nil
 }
+
+// GetAggregatedOrderBookSummary returns an OrderBookSummary for a given
+// trading pair where offers are aggregated into price levels which are
+// multiples of levelSize. Ask prices are rounded up and bid prices rounded
+// down so a level never shows a better price than the offers it aggregates.
+// levelSize must be positive and is used with a precision of 7 decimals.
+func (q *Q) GetAggregatedOrderBookSummary(sellingAsset, buyingAsset xdr.Asset, levelSize *big.Rat, maxPriceLevels int) (OrderBookSummary, error) {
+	var result OrderBookSummary
+
+	if levelSize.Sign() <= 0 {
+		return result, errors.New("level size must be positive")
+	}
+
+	selling, err := xdr.MarshalBase64(sellingAsset)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot marshal selling asset")
+	}
+	buying, err := xdr.MarshalBase64(buyingAsset)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot marshal Buying asset")
+	}
+
+	// The levels are computed with numeric values instead of the float price
+	// column so prices which are exact multiples of the level size are not
+	// moved to the next level because of rounding errors. Bid prices are
+	// inverted, like in GetOrderBookSummary, before being aggregated.
+	selectLevels := `
+		(
+			SELECT
+				'ask' as type,
+				CEIL(pricen::numeric / priced / $3::numeric)::bigint as level,
+				SUM(amount) as amount
+			FROM offers
+			WHERE selling_asset = $1 AND buying_asset = $2 AND deleted = false
+			GROUP BY level
+			ORDER BY level ASC
+			LIMIT $4
+		) UNION ALL (
+			SELECT
+				'bid' as type,
+				FLOOR(priced::numeric / pricen / $3::numeric)::bigint as level,
+				SUM(amount) as amount
+			FROM offers
+			WHERE selling_asset = $2 AND buying_asset = $1 AND deleted = false
+			GROUP BY level
+			ORDER BY level DESC
+			LIMIT $4
+		)
+	`
+
+	var levels []aggregatedPriceLevel
+	err = q.SelectRaw(&levels, selectLevels, selling, buying, levelSize.FloatString(7), maxPriceLevels)
+	if err != nil {
+		return result, errors.Wrap(err, "cannot select aggregated price levels")
+	}
+
+	for _, level := range levels {
+		price := new(big.Rat).Mul(big.NewRat(level.Level, 1), levelSize)
+		if price.Num().Cmp(big.NewInt(math.MaxInt32)) > 0 ||
+			price.Denom().Cmp(big.NewInt(math.MaxInt32)) > 0 {
+			return result, errors.Errorf("price of level %s does not fit in a price fraction", price.FloatString(7))
+		}
+
+		entry := PriceLevel{
+			Pricef: price.FloatString(7),
+			Pricen: int32(price.Num().Int64()),
+			Priced: int32(price.Denom().Int64()),
+		}
+		entry.Amount, err = amount.IntStringToAmount(level.Amount)
+		if err != nil {
+			return result, errors.Wrap(err, "could not determine level amount")
+		}
+
+		switch level.Type {
+		case "ask":
+			result.Asks = append(result.Asks, entry)
+		case "bid":
+			result.Bids = append(result.Bids, entry)
+		default:
+			return result, errors.Errorf("invalid level type %s", level.Type)
+		}
+	}
+
+	return result, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"math"
+	"math/big"
 	"testing"
 )
 
@@ -197,6 +198,69 @@ func TestGetOrderBookSummary(t *testing.T) {
 	}
 }
 
+func TestGetAggregatedOrderBookSummary(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	// 0.03 is an exact multiple of the level size and must not be rounded up
+	exactAsk := twoEurOffer
+	exactAsk.OfferId = 40
+	exactAsk.Price = xdr.Price{N: 3, D: 100}
+
+	thirdAsk := twoEurOffer
+	thirdAsk.OfferId = 41
+	thirdAsk.Price = xdr.Price{N: 1, D: 3}
+
+	otherThirdAsk := thirdAsk
+	otherThirdAsk.OfferId = 42
+	otherThirdAsk.Price = xdr.Price{N: 33, D: 100}
+
+	exactBid := twoEurOffer
+	exactBid.Buying, exactBid.Selling = exactBid.Selling, exactBid.Buying
+	exactBid.OfferId = 43
+	exactBid.Price = xdr.Price{N: 100, D: 3}
+
+	thirdBid := exactBid
+	thirdBid.OfferId = 44
+	thirdBid.Price = xdr.Price{N: 3, D: 1}
+
+	batch := q.NewOffersBatchInsertBuilder(0)
+	for i, offer := range []xdr.OfferEntry{exactAsk, thirdAsk, otherThirdAsk, exactBid, thirdBid} {
+		tt.Assert.NoError(batch.Add(offer, xdr.Uint32(i+1)))
+	}
+	tt.Assert.NoError(batch.Exec())
+
+	result, err := q.GetAggregatedOrderBookSummary(nativeAsset, eurAsset, big.NewRat(1, 100), 10)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(OrderBookSummary{
+		Asks: []PriceLevel{
+			{Pricen: 3, Priced: 100, Pricef: "0.0300000", Amount: "0.0000500"},
+			{Pricen: 33, Priced: 100, Pricef: "0.3300000", Amount: "0.0000500"},
+			{Pricen: 17, Priced: 50, Pricef: "0.3400000", Amount: "0.0000500"},
+		},
+		Bids: []PriceLevel{
+			{Pricen: 33, Priced: 100, Pricef: "0.3300000", Amount: "0.0000500"},
+			{Pricen: 3, Priced: 100, Pricef: "0.0300000", Amount: "0.0000500"},
+		},
+	}, result)
+
+	result, err = q.GetAggregatedOrderBookSummary(nativeAsset, eurAsset, big.NewRat(1, 2), 1)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(OrderBookSummary{
+		Asks: []PriceLevel{
+			{Pricen: 1, Priced: 2, Pricef: "0.5000000", Amount: "0.0001500"},
+		},
+		Bids: []PriceLevel{
+			{Pricen: 0, Priced: 1, Pricef: "0.0000000", Amount: "0.0001000"},
+		},
+	}, result)
+
+	_, err = q.GetAggregatedOrderBookSummary(nativeAsset, eurAsset, big.NewRat(0, 1), 1)
+	tt.Assert.EqualError(err, "level size must be positive")
+}
+
 func TestGetOrderBookSummaryExcludesRemovedOffers(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -0,0 +1,266 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stellar/go/support/errors"
+)
+
+// HistoryPartitionSize is the number of ledgers held by every partition of the
+// history tables.
+const HistoryPartitionSize = 100000
+
+// partitionedHistoryTables are the history tables which are partitioned (by
+// migration 41) on the toid of their rows.
+var partitionedHistoryTables = []string{
+	"history_ledgers",
+	"history_transactions",
+	"history_operations",
+	"history_effects",
+	"history_trades",
+}
+
+// HistoryPartition is a partition of a history table. It holds the rows of
+// the ledgers in [FromLedger, ToLedger).
+type HistoryPartition struct {
+	Name       string
+	FromLedger uint32
+	ToLedger   uint32
+}
+
+var historyPartitionBoundRegexp = regexp.MustCompile(
+	`^FOR VALUES FROM \((MINVALUE|'?-?\d+'?)\) TO \((MAXVALUE|'?-?\d+'?)\)$`,
+)
+
+// parseHistoryPartitionBound returns the range of ledgers of a partition from
+// its bound, as formatted by pg_get_expr.
+func parseHistoryPartitionBound(bound string) (uint32, uint32, error) {
+	matches := historyPartitionBoundRegexp.FindStringSubmatch(bound)
+	if matches == nil {
+		return 0, 0, errors.Errorf("unexpected partition bound: %s", bound)
+	}
+
+	ledgers := [2]uint32{0, math.MaxUint32}
+	for i, value := range matches[1:] {
+		if value == "MINVALUE" || value == "MAXVALUE" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(strings.Trim(value, "'"), 10, 64)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "invalid partition bound: %s", bound)
+		}
+		ledgers[i] = 0
+		if id > 0 {
+			ledgers[i] = uint32(id >> 32)
+		}
+	}
+	return ledgers[0], ledgers[1], nil
+}
+
+// HistoryPartitioned returns true when the history tables are partitioned.
+// They are not on postgres versions older than 11.
+func (q *Q) HistoryPartitioned() (bool, error) {
+	var partitioned bool
+	err := q.GetRaw(&partitioned, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_class
+			WHERE oid = 'history_ledgers'::regclass AND relkind = 'p'
+		)`,
+	)
+	return partitioned, err
+}
+
+// HistoryPartitions returns the partitions of `table` ordered by ledger.
+func (q *Q) HistoryPartitions(table string) ([]HistoryPartition, error) {
+	var rows []struct {
+		Name  string `db:"name"`
+		Bound string `db:"bound"`
+	}
+	err := q.Select(&rows, sq.Select(
+		"c.relname AS name",
+		"pg_get_expr(c.relpartbound, c.oid) AS bound",
+	).
+		From("pg_inherits i").
+		Join("pg_class c ON c.oid = i.inhrelid").
+		Where("i.inhparent = ?::regclass", table))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load partitions of %s", table)
+	}
+
+	partitions := make([]HistoryPartition, 0, len(rows))
+	for _, row := range rows {
+		from, to, err := parseHistoryPartitionBound(row.Bound)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse bound of %s", row.Name)
+		}
+		partitions = append(partitions, HistoryPartition{
+			Name:       row.Name,
+			FromLedger: from,
+			ToLedger:   to,
+		})
+	}
+
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].FromLedger < partitions[j].FromLedger
+	})
+	return partitions, nil
+}
+
+// lockHistoryPartitions serializes the changes to the partitions of the
+// history tables made by concurrent ingesting nodes and the reaper until the
+// end of the current transaction.
+func (q *Q) lockHistoryPartitions() error {
+	_, err := q.ExecRaw("SELECT pg_advisory_xact_lock(hashtext('history_partitions'))")
+	return errors.Wrap(err, "could not lock history partitions")
+}
+
+// EnsureHistoryPartitions creates the partitions of the history tables which
+// are missing to hold the ledgers in [fromLedger, toLedger]. Partitions hold
+// HistoryPartitionSize ledgers, starting at a multiple of it. It does nothing
+// when the history tables are not partitioned.
+//
+// The partitions are created in a separate transaction so it must be called
+// before inserting rows of the ledgers.
+func (q *Q) EnsureHistoryPartitions(fromLedger, toLedger uint32) error {
+	partitioned, err := q.HistoryPartitioned()
+	if err != nil || !partitioned {
+		return err
+	}
+
+	tx := &Q{q.Clone()}
+	if err = tx.Begin(); err != nil {
+		return errors.Wrap(err, "could not start transaction")
+	}
+	defer tx.Rollback()
+
+	if err = tx.lockHistoryPartitions(); err != nil {
+		return err
+	}
+
+	for _, table := range partitionedHistoryTables {
+		partitions, err := tx.HistoryPartitions(table)
+		if err != nil {
+			return err
+		}
+
+		start := uint64(fromLedger) / HistoryPartitionSize * HistoryPartitionSize
+		for ; start <= uint64(toLedger); start += HistoryPartitionSize {
+			if historyPartitionsContain(partitions, uint32(start)) {
+				continue
+			}
+
+			upper := "MAXVALUE"
+			if end := start + HistoryPartitionSize; end <= math.MaxInt32 {
+				upper = strconv.FormatInt(int64(end)<<32, 10)
+			}
+			_, err = tx.ExecRaw(fmt.Sprintf(
+				"CREATE TABLE %s_p%d PARTITION OF %s FOR VALUES FROM (%d) TO (%s)",
+				table, start, table, int64(start)<<32, upper,
+			))
+			if err != nil {
+				return errors.Wrapf(err, "could not create partition of %s for ledger %d", table, start)
+			}
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "could not commit transaction")
+}
+
+func historyPartitionsContain(partitions []HistoryPartition, ledger uint32) bool {
+	for _, partition := range partitions {
+		if partition.FromLedger <= ledger && ledger < partition.ToLedger {
+			return true
+		}
+	}
+	return false
+}
+
+// DropHistoryPartitionsBefore detaches and drops the partitions of the history
+// tables which only hold ledgers older than `ledger`. The rows of the older
+// ledgers held by the remaining partitions are not deleted, see
+// DeleteRangeAll. It does nothing when the history tables are not partitioned.
+//
+// Detaching and dropping partitions takes ACCESS EXCLUSIVE locks on the history
+// tables until the transaction commits, which blocks ingestion and the queries
+// of the tables meanwhile. It is quick since no rows are deleted.
+func (q *Q) DropHistoryPartitionsBefore(ledger uint32) error {
+	partitioned, err := q.HistoryPartitioned()
+	if err != nil || !partitioned {
+		return err
+	}
+
+	tx := &Q{q.Clone()}
+	if err = tx.Begin(); err != nil {
+		return errors.Wrap(err, "could not start transaction")
+	}
+	defer tx.Rollback()
+
+	if err = tx.lockHistoryPartitions(); err != nil {
+		return err
+	}
+
+	dropped := map[string][]string{}
+	var droppedBefore uint32
+	for _, table := range partitionedHistoryTables {
+		partitions, err := tx.HistoryPartitions(table)
+		if err != nil {
+			return err
+		}
+
+		for _, partition := range partitions {
+			if partition.ToLedger > ledger {
+				continue
+			}
+			dropped[table] = append(dropped[table], partition.Name)
+			if partition.ToLedger > droppedBefore {
+				droppedBefore = partition.ToLedger
+			}
+		}
+	}
+
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	var closedAt struct {
+		First *time.Time `db:"first"`
+		Last  *time.Time `db:"last"`
+	}
+	err = tx.Get(&closedAt, sq.Select("MIN(closed_at) AS first", "MAX(closed_at) AS last").
+		From("history_ledgers").
+		Where("id < ?", int64(droppedBefore)<<32))
+	if err != nil {
+		return errors.Wrap(err, "Error loading closing times of ledgers")
+	}
+
+	for _, table := range partitionedHistoryTables {
+		for _, partition := range dropped[table] {
+			_, err = tx.ExecRaw(fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", table, partition))
+			if err != nil {
+				return errors.Wrapf(err, "could not detach partition %s", partition)
+			}
+			_, err = tx.ExecRaw(fmt.Sprintf("DROP TABLE %s", partition))
+			if err != nil {
+				return errors.Wrapf(err, "could not drop partition %s", partition)
+			}
+		}
+	}
+
+	// See DeleteRangeAll.
+	if closedAt.First != nil {
+		err = tx.RebuildTradeRollups(*closedAt.First, *closedAt.Last)
+		if err != nil {
+			return errors.Wrap(err, "Error rebuilding history_trade_rollups")
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "could not commit transaction")
+}
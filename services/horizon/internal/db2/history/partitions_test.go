@@ -0,0 +1,65 @@
+package history
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestParseHistoryPartitionBound(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+
+	from, to, err := parseHistoryPartitionBound("FOR VALUES FROM (MINVALUE) TO ('429496729600000')")
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(uint32(0), from)
+	tt.Assert.Equal(uint32(100000), to)
+
+	from, to, err = parseHistoryPartitionBound("FOR VALUES FROM (429496729600000) TO (MAXVALUE)")
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(uint32(100000), from)
+	tt.Assert.Equal(uint32(math.MaxUint32), to)
+
+	_, _, err = parseHistoryPartitionBound("FOR VALUES IN (1)")
+	tt.Assert.EqualError(err, "unexpected partition bound: FOR VALUES IN (1)")
+}
+
+func TestHistoryPartitions(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	partitioned, err := q.HistoryPartitioned()
+	tt.Assert.NoError(err)
+	if !partitioned {
+		t.Skip("history tables are only partitioned on postgres 11 and newer")
+	}
+
+	tt.Assert.NoError(q.EnsureHistoryPartitions(150000, 250000))
+	// creating existing partitions is a no-op
+	tt.Assert.NoError(q.EnsureHistoryPartitions(1, 200000))
+
+	for _, table := range partitionedHistoryTables {
+		partitions, err := q.HistoryPartitions(table)
+		tt.Assert.NoError(err)
+		tt.Assert.Equal([]HistoryPartition{
+			{Name: table + "_p0", FromLedger: 0, ToLedger: 100000},
+			{Name: table + "_p100000", FromLedger: 100000, ToLedger: 200000},
+			{Name: table + "_p200000", FromLedger: 200000, ToLedger: 300000},
+		}, partitions)
+	}
+
+	// the first call drops p0 only since p100000 holds ledger 199999
+	tt.Assert.NoError(q.DropHistoryPartitionsBefore(199999))
+	tt.Assert.NoError(q.DropHistoryPartitionsBefore(200000))
+
+	for _, table := range partitionedHistoryTables {
+		partitions, err := q.HistoryPartitions(table)
+		tt.Assert.NoError(err)
+		tt.Assert.Equal([]HistoryPartition{
+			{Name: table + "_p200000", FromLedger: 200000, ToLedger: 300000},
+		}, partitions)
+	}
+}
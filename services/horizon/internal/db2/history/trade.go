@@ -3,6 +3,7 @@ package history
 import (
 	"fmt"
 	"math"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/stellar/go/services/horizon/internal/db2"
@@ -103,6 +104,23 @@ func (q *TradesQ) ForAccount(aid string) *TradesQ {
 	return q
 }
 
+// ClosedWithin filters the query to only trades in ledgers closed within the
+// time range `r`.
+func (q *TradesQ) ClosedWithin(r TimeRange) *TradesQ {
+	if q.Err != nil || (r.Start.IsNil() && r.End.IsNil()) {
+		return q
+	}
+
+	predicate, err := q.parent.closedWithinPredicate(r, "htrd.history_operation_id")
+	if err != nil {
+		q.Err = err
+		return q
+	}
+	q.sql = q.sql.Where(predicate)
+
+	return q
+}
+
 // Page specifies the paging constraints for the query being built by `q`.
 func (q *TradesQ) Page(page db2.PageQuery) *TradesQ {
 	if q.Err != nil {
@@ -227,4 +245,5 @@ type QTrades interface {
 	QCreateAccountsHistory
 	NewTradeBatchInsertBuilder(maxBatchSize int) TradeBatchInsertBuilder
 	CreateAssets(assets []xdr.Asset, maxBatchSize int) (map[string]Asset, error)
+	RebuildTradeRollups(from, to time.Time) error
 }
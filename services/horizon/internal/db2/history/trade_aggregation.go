@@ -14,6 +14,8 @@ import (
 // AllowedResolutions is the set of trade aggregation time windows allowed to be used as the
 // `resolution` parameter.
 var AllowedResolutions = map[time.Duration]struct{}{
+	time.Second * 5:    {}, //5 seconds
+	time.Second * 15:   {}, //15 seconds
 	time.Minute:        {}, //1 minute
 	time.Minute * 5:    {}, //5 minutes
 	time.Minute * 15:   {}, //15 minutes
@@ -22,6 +24,14 @@ var AllowedResolutions = map[time.Duration]struct{}{
 	time.Hour * 24 * 7: {}, //week
 }
 
+// rollupResolutions is the set of resolutions whose buckets are aggregated
+// during ingestion into the `history_trade_rollups` table instead of being
+// computed on the fly.
+var rollupResolutions = []time.Duration{
+	time.Second * 5,
+	time.Second * 15,
+}
+
 // StrictResolutionFiltering represents a simple feature flag to determine whether only
 // predetermined resolutions of trade aggregations are allowed.
 var StrictResolutionFiltering = true
@@ -122,6 +132,10 @@ func (q *TradeAggregationsQ) GetSql() sq.SelectBuilder {
 	var orderPreserved bool
 	orderPreserved, q.baseAssetID, q.counterAssetID = getCanonicalAssetOrder(q.baseAssetID, q.counterAssetID)
 
+	if q.offset == 0 && isRollupResolution(q.resolution) {
+		return q.getRollupSql(orderPreserved)
+	}
+
 	var bucketSQL sq.SelectBuilder
 	if orderPreserved {
 		bucketSQL = bucketTrades(q.resolution, q.offset)
@@ -195,3 +209,125 @@ func reverseBucketTrades(resolution int64, offset int64) sq.SelectBuilder {
 		"ARRAY[price_d, price_n] as price",
 	)
 }
+
+// getRollupSql generates a sql statement reading the pre-aggregated buckets
+// of the query's resolution from the `history_trade_rollups` table.
+func (q *TradeAggregationsQ) getRollupSql(orderPreserved bool) sq.SelectBuilder {
+	var sql sq.SelectBuilder
+	if orderPreserved {
+		sql = sq.Select(
+			"timestamp",
+			"count",
+			"base_volume",
+			"counter_volume",
+			"counter_volume/base_volume as avg",
+			"high",
+			"low",
+			"open",
+			"close",
+		)
+	} else {
+		sql = sq.Select(
+			"timestamp",
+			"count",
+			"counter_volume as base_volume",
+			"base_volume as counter_volume",
+			"base_volume/counter_volume as avg",
+			"ARRAY[low[2], low[1]] as high",
+			"ARRAY[high[2], high[1]] as low",
+			"ARRAY[open[2], open[1]] as open",
+			"ARRAY[close[2], close[1]] as close",
+		)
+	}
+
+	sql = sql.From("history_trade_rollups").
+		Where(sq.Eq{
+			"base_asset_id":    q.baseAssetID,
+			"counter_asset_id": q.counterAssetID,
+			"resolution":       q.resolution,
+		}).
+		Where(sq.GtOrEq{"timestamp": q.startTime.ToInt64()})
+	if !q.endTime.IsNil() {
+		sql = sql.Where(sq.Lt{"timestamp": q.endTime.ToInt64()})
+	}
+
+	return sql.
+		Limit(q.pagingParams.Limit).
+		OrderBy("timestamp " + q.pagingParams.Order)
+}
+
+// RebuildTradeRollups recomputes the `history_trade_rollups` buckets of all
+// asset pairs covering the time range between `from` and `to` (inclusive) from
+// the rows of the `history_trades` table. It is idempotent, buckets left
+// without trades are removed. Transactions rebuilding overlapping ranges
+// concurrently, like parallel reingestion workers and live ingestion, update
+// the buckets inserted by each other instead of failing on their primary key.
+func (q *Q) RebuildTradeRollups(from, to time.Time) error {
+	for _, d := range rollupResolutions {
+		resolution := int64(d / time.Millisecond)
+		start := strtime.MillisFromSeconds(from.Unix()).RoundDown(resolution)
+		end := strtime.MillisFromSeconds(to.Unix()).RoundDown(resolution) + strtime.MillisFromInt64(resolution)
+
+		_, err := q.Exec(sq.Delete("history_trade_rollups").
+			Where(sq.Eq{"resolution": resolution}).
+			Where(sq.GtOrEq{"timestamp": start.ToInt64()}).
+			Where(sq.Lt{"timestamp": end.ToInt64()}))
+		if err != nil {
+			return errors.Wrap(err, "could not clear trade rollups")
+		}
+
+		bucketSQL := bucketTrades(resolution, 0).
+			From("history_trades").
+			Where(sq.GtOrEq{"ledger_closed_at": start.ToTime()}).
+			Where(sq.Lt{"ledger_closed_at": end.ToTime()}).
+			OrderBy("history_operation_id", "\"order\"")
+
+		sql, args, err := sq.Select(
+			fmt.Sprintf("%d", resolution),
+			"timestamp",
+			"base_asset_id",
+			"counter_asset_id",
+			"count(*)",
+			"sum(base_amount)",
+			"sum(counter_amount)",
+			"max_price(price)",
+			"min_price(price)",
+			"first(price)",
+			"last(price)",
+		).
+			FromSelect(bucketSQL, "htrd").
+			GroupBy("base_asset_id", "counter_asset_id", "timestamp").
+			ToSql()
+		if err != nil {
+			return errors.Wrap(err, "could not build trade rollups query")
+		}
+
+		_, err = q.ExecRaw(`INSERT INTO history_trade_rollups
+			(resolution, timestamp, base_asset_id, counter_asset_id, count,
+			base_volume, counter_volume, high, low, open, close) `+sql+`
+			ON CONFLICT (base_asset_id, counter_asset_id, resolution, timestamp) DO UPDATE SET
+			count = EXCLUDED.count,
+			base_volume = EXCLUDED.base_volume,
+			counter_volume = EXCLUDED.counter_volume,
+			high = EXCLUDED.high,
+			low = EXCLUDED.low,
+			open = EXCLUDED.open,
+			close = EXCLUDED.close`, args...)
+		if err != nil {
+			return errors.Wrap(err, "could not insert trade rollups")
+		}
+	}
+
+	return nil
+}
+
+// isRollupResolution returns true if the buckets of the given resolution (in
+// milliseconds) are stored in the `history_trade_rollups` table.
+func isRollupResolution(resolution int64) bool {
+	for _, d := range rollupResolutions {
+		if int64(d/time.Millisecond) == resolution {
+			return true
+		}
+	}
+	return false
+}
@@ -95,6 +95,23 @@ func (q *TransactionsQ) ForLedger(seq int32) *TransactionsQ {
 	return q
 }
 
+// ClosedWithin filters the query to only transactions in ledgers closed
+// within the time range `r`.
+func (q *TransactionsQ) ClosedWithin(r TimeRange) *TransactionsQ {
+	if q.Err != nil || (r.Start.IsNil() && r.End.IsNil()) {
+		return q
+	}
+
+	predicate, err := q.parent.closedWithinPredicate(r, "ht.id")
+	if err != nil {
+		q.Err = err
+		return q
+	}
+	q.sql = q.sql.Where(predicate)
+
+	return q
+}
+
 // IncludeFailed changes the query to include failed transactions.
 func (q *TransactionsQ) IncludeFailed() *TransactionsQ {
 	q.includeFailed = true
@@ -225,6 +225,16 @@ type TransactionWithoutLedger struct {
 	InnerSignatures      pq.StringArray `db:"inner_signatures"`
 }
 
+// TransactionFromLedger returns the history row of `transaction`, included in
+// ledger `sequence` closed at `closeTime`, without inserting it.
+func TransactionFromLedger(transaction io.LedgerTransaction, sequence uint32, closeTime time.Time) (Transaction, error) {
+	row, err := transactionToRow(transaction, sequence)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return Transaction{LedgerCloseTime: closeTime.UTC(), TransactionWithoutLedger: row}, nil
+}
+
 func transactionToRow(transaction io.LedgerTransaction, sequence uint32) (TransactionWithoutLedger, error) {
 	envelopeBase64, err := xdr.MarshalBase64(transaction.Envelope)
 	if err != nil {
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+)
+
+// TenantUsage is the number of requests served to a tenant, an API key or a
+// remote ip address, and the number of bytes sent in their responses.
+type TenantUsage struct {
+	Tenant   string `db:"tenant"`
+	Requests int64  `db:"requests"`
+	Bytes    int64  `db:"bytes"`
+	// RateLimited is the number of requests rejected by the rate limiter.
+	RateLimited int64 `db:"rate_limited"`
+	// Endpoints maps the route patterns to their number of requests.
+	Endpoints map[string]int64 `db:"-"`
+}
+
+// addUsageSuffix merges the usage of a tenant with the usage already recorded
+// for the same period, summing the requests of every endpoint.
+const addUsageSuffix = `ON CONFLICT (period_start, tenant) DO UPDATE SET
+	requests = horizon_usage.requests + EXCLUDED.requests,
+	bytes = horizon_usage.bytes + EXCLUDED.bytes,
+	rate_limited = horizon_usage.rate_limited + EXCLUDED.rate_limited,
+	endpoints = (
+		SELECT COALESCE(jsonb_object_agg(key, requests), '{}'::jsonb) FROM (
+			SELECT key, SUM(value::bigint) AS requests FROM (
+				SELECT * FROM jsonb_each_text(horizon_usage.endpoints)
+				UNION ALL
+				SELECT * FROM jsonb_each_text(EXCLUDED.endpoints)
+			) AS merged GROUP BY key
+		) AS summed
+	)`
+
+// AddUsage adds `usages` to the usage recorded for the period starting at
+// `periodStart`.
+func (q *Q) AddUsage(periodStart time.Time, usages []TenantUsage, batchSize int) error {
+	builder := &db.BatchInsertBuilder{
+		Table:        q.GetTable("horizon_usage"),
+		MaxBatchSize: batchSize,
+		Suffix:       addUsageSuffix,
+	}
+
+	// sort tenants to prevent deadlocks between instances flushing their
+	// usage concurrently
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Tenant < usages[j].Tenant
+	})
+	for _, usage := range usages {
+		endpoints, err := json.Marshal(usage.Endpoints)
+		if err != nil {
+			return errors.Wrap(err, "could not encode endpoints")
+		}
+		err = builder.Row(map[string]interface{}{
+			"period_start": periodStart.UTC(),
+			"tenant":       usage.Tenant,
+			"requests":     usage.Requests,
+			"bytes":        usage.Bytes,
+			"rate_limited": usage.RateLimited,
+			"endpoints":    string(endpoints),
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not insert horizon_usage row")
+		}
+	}
+
+	return errors.Wrap(builder.Exec(), "could not exec horizon_usage insert builder")
+}
+
+// GetTopUsage returns the usage of the `limit` tenants which sent the most
+// requests in the periods starting in [from, to), the heaviest first.
+// Endpoints are set to the requests of every endpoint used by the tenants.
+func (q *Q) GetTopUsage(from, to time.Time, limit uint64) ([]TenantUsage, error) {
+	var usages []TenantUsage
+	err := q.SelectRaw(
+		&usages,
+		`SELECT tenant, SUM(requests) AS requests, SUM(bytes) AS bytes, SUM(rate_limited) AS rate_limited
+		FROM horizon_usage WHERE period_start >= $1 AND period_start < $2
+		GROUP BY tenant ORDER BY requests DESC, tenant LIMIT $3`,
+		from.UTC(), to.UTC(), limit,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get top usage")
+	}
+	if len(usages) == 0 {
+		return usages, nil
+	}
+
+	tenants := make([]string, 0, len(usages))
+	byTenant := map[string]*TenantUsage{}
+	for i := range usages {
+		usages[i].Endpoints = map[string]int64{}
+		tenants = append(tenants, usages[i].Tenant)
+		byTenant[usages[i].Tenant] = &usages[i]
+	}
+
+	var endpoints []struct {
+		Tenant   string `db:"tenant"`
+		Endpoint string `db:"endpoint"`
+		Requests int64  `db:"requests"`
+	}
+	err = q.SelectRaw(
+		&endpoints,
+		`SELECT tenant, e.key AS endpoint, SUM(e.value::bigint) AS requests
+		FROM horizon_usage, jsonb_each_text(endpoints) AS e
+		WHERE period_start >= $1 AND period_start < $2 AND tenant = ANY($3)
+		GROUP BY tenant, e.key`,
+		from.UTC(), to.UTC(), pq.Array(tenants),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get top usage endpoints")
+	}
+	for _, endpoint := range endpoints {
+		byTenant[endpoint.Tenant].Endpoints[endpoint.Endpoint] = endpoint.Requests
+	}
+	return usages, nil
+}
+
+// DeleteUsageBefore deletes the usage recorded for the periods starting
+// before `before`.
+func (q *Q) DeleteUsageBefore(before time.Time) (int64, error) {
+	result, err := q.ExecRaw(`DELETE FROM horizon_usage WHERE period_start < $1`, before.UTC())
+	if err != nil {
+		return 0, errors.Wrap(err, "could not delete usage")
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,54 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestUsage(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	period := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	tt.Assert.NoError(q.AddUsage(period, []TenantUsage{
+		{Tenant: "1.2.3.4", Requests: 3, Bytes: 300, Endpoints: map[string]int64{"/ledgers": 3}},
+		{Tenant: "key", Requests: 2, Bytes: 100, RateLimited: 1, Endpoints: map[string]int64{"/accounts/{account_id}": 2}},
+	}, 100))
+	// usage of the same period is merged
+	tt.Assert.NoError(q.AddUsage(period, []TenantUsage{
+		{Tenant: "key", Requests: 4, Bytes: 50, Endpoints: map[string]int64{"/accounts/{account_id}": 1, "/ledgers": 3}},
+	}, 100))
+	next := period.Add(time.Hour)
+	tt.Assert.NoError(q.AddUsage(next, []TenantUsage{
+		{Tenant: "1.2.3.4", Requests: 1, Bytes: 10, Endpoints: map[string]int64{"/ledgers": 1}},
+	}, 100))
+
+	usages, err := q.GetTopUsage(period, next.Add(time.Hour), 10)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]TenantUsage{
+		{
+			Tenant: "key", Requests: 6, Bytes: 150, RateLimited: 1,
+			Endpoints: map[string]int64{"/accounts/{account_id}": 3, "/ledgers": 3},
+		},
+		{
+			Tenant: "1.2.3.4", Requests: 4, Bytes: 310,
+			Endpoints: map[string]int64{"/ledgers": 4},
+		},
+	}, usages)
+
+	usages, err = q.GetTopUsage(next, next.Add(time.Hour), 1)
+	tt.Assert.NoError(err)
+	tt.Assert.Len(usages, 1)
+	tt.Assert.Equal("1.2.3.4", usages[0].Tenant)
+
+	deleted, err := q.DeleteUsageBefore(next)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(int64(2), deleted)
+	usages, err = q.GetTopUsage(period, next, 10)
+	tt.Assert.NoError(err)
+	tt.Assert.Empty(usages)
+}
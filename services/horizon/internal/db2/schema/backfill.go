@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// Backfill is an online data migration. Schema migrations which would rewrite
+// large tables (for example to populate a new column) only change the schema
+// and register a Backfill which populates the existing rows in small batches
+// while horizon keeps running.
+//
+// Between the schema migration and the completion of the backfill (the
+// dual-write window) horizon must write both the old and the new
+// representation of the rows it inserts and must not read the new one, see
+// history.Q.BackfillCompleted.
+type Backfill struct {
+	// ID identifies the backfill in the horizon_backfills table. It must never
+	// change once released.
+	ID string
+	// Table is the table whose rows are backfilled.
+	Table string
+	// Key is the bigint column, usually the primary key, by which the rows of
+	// Table are split into batches.
+	Key string
+	// Query backfills the rows whose Key is in the range [$1, $2). It must be
+	// idempotent since a batch is run again if it was interrupted.
+	Query string
+}
+
+// Backfills are the backfills run by `horizon db migrate up`, in order.
+var Backfills = []Backfill{}
+
+// RunBackfills runs the pending `backfills` in batches of `batchSize` keys,
+// pausing for `delay` between two batches. Every batch is committed with its
+// progress so an interrupted backfill resumes where it stopped. It returns the
+// number of backfills completed by this call.
+func RunBackfills(db *sql.DB, backfills []Backfill, batchSize int64, delay time.Duration) (int, error) {
+	if batchSize <= 0 {
+		return 0, errors.New("batch size must be greater than 0")
+	}
+
+	completed := 0
+	for _, backfill := range backfills {
+		done, err := runBackfill(db, backfill, batchSize, delay)
+		if err != nil {
+			return completed, errors.Wrapf(err, "could not run backfill %s", backfill.ID)
+		}
+		if done {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+// runBackfill runs `backfill` to its end. It returns false when the backfill
+// had already been completed.
+func runBackfill(db *sql.DB, backfill Backfill, batchSize int64, delay time.Duration) (bool, error) {
+	table := pq.QuoteIdentifier(backfill.Table)
+	key := pq.QuoteIdentifier(backfill.Key)
+	localLog := log.WithField("backfill", backfill.ID)
+
+	_, err := db.Exec(
+		fmt.Sprintf(
+			"INSERT INTO horizon_backfills (id, next_key) SELECT $1, COALESCE(min(%s), 0) FROM %s ON CONFLICT (id) DO NOTHING",
+			key, table,
+		),
+		backfill.ID,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, "could not insert backfill")
+	}
+
+	var completed bool
+	err = db.QueryRow(
+		"SELECT completed_at IS NOT NULL FROM horizon_backfills WHERE id = $1",
+		backfill.ID,
+	).Scan(&completed)
+	if err != nil {
+		return false, errors.Wrap(err, "could not load backfill")
+	}
+	if completed {
+		return false, nil
+	}
+
+	localLog.Info("Running backfill")
+	for {
+		done, err := runBackfillBatch(db, backfill, table, key, batchSize)
+		if err != nil {
+			return false, err
+		}
+		if done {
+			localLog.Info("Backfill completed")
+			return true, nil
+		}
+
+		time.Sleep(delay)
+	}
+}
+
+// runBackfillBatch runs the next batch of `backfill` and records its progress
+// in a single transaction. Locking the horizon_backfills row prevents two
+// concurrent runs from processing the same batch. It returns true when the
+// backfill is complete.
+func runBackfillBatch(db *sql.DB, backfill Backfill, table, key string, batchSize int64) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, errors.Wrap(err, "could not begin transaction")
+	}
+	defer tx.Rollback()
+
+	var nextKey int64
+	var completed bool
+	err = tx.QueryRow(
+		"SELECT next_key, completed_at IS NOT NULL FROM horizon_backfills WHERE id = $1 FOR UPDATE",
+		backfill.ID,
+	).Scan(&nextKey, &completed)
+	if err != nil {
+		return false, errors.Wrap(err, "could not load backfill progress")
+	}
+	if completed {
+		return true, nil
+	}
+
+	// Rows inserted during the backfill are dual-written so the backfill is
+	// complete once it passes the greatest key.
+	var maxKey sql.NullInt64
+	err = tx.QueryRow(fmt.Sprintf("SELECT max(%s) FROM %s", key, table)).Scan(&maxKey)
+	if err != nil {
+		return false, errors.Wrap(err, "could not load greatest key")
+	}
+
+	if !maxKey.Valid || nextKey > maxKey.Int64 {
+		_, err = tx.Exec("UPDATE horizon_backfills SET completed_at = now() WHERE id = $1", backfill.ID)
+		if err != nil {
+			return false, errors.Wrap(err, "could not complete backfill")
+		}
+		return true, errors.Wrap(tx.Commit(), "could not commit transaction")
+	}
+
+	end := nextKey + batchSize
+	if _, err = tx.Exec(backfill.Query, nextKey, end); err != nil {
+		return false, errors.Wrapf(err, "could not backfill keys [%d, %d)", nextKey, end)
+	}
+	_, err = tx.Exec("UPDATE horizon_backfills SET next_key = $2 WHERE id = $1", backfill.ID, end)
+	if err != nil {
+		return false, errors.Wrap(err, "could not update backfill progress")
+	}
+
+	log.WithField("backfill", backfill.ID).
+		WithField("from", nextKey).
+		WithField("to", end).
+		Debug("Backfilled batch")
+	return false, errors.Wrap(tx.Commit(), "could not commit transaction")
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/support/db/dbtest"
+)
+
+func TestRunBackfills(t *testing.T) {
+	tdb := dbtest.Postgres(t)
+	defer tdb.Close()
+	db := tdb.Open()
+	defer db.Close()
+
+	_, err := Migrate(db.DB, MigrateUp, 0)
+	assert.NoError(t, err)
+
+	db.MustExec(`CREATE TABLE backfill_test (id bigint PRIMARY KEY, amount int, double_amount int)`)
+	db.MustExec(`INSERT INTO backfill_test (id, amount) SELECT i, i FROM generate_series(3, 12) i`)
+
+	backfills := []Backfill{
+		{
+			ID:    "backfill_test_double_amount",
+			Table: "backfill_test",
+			Key:   "id",
+			Query: `UPDATE backfill_test SET double_amount = 2 * amount WHERE id >= $1 AND id < $2`,
+		},
+	}
+
+	_, err = RunBackfills(db.DB, backfills, 0, 0)
+	assert.EqualError(t, err, "batch size must be greater than 0")
+
+	completed, err := RunBackfills(db.DB, backfills, 4, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, completed)
+
+	var missing int
+	assert.NoError(t, db.Get(&missing, `SELECT count(*) FROM backfill_test WHERE double_amount IS DISTINCT FROM 2 * amount`))
+	assert.Equal(t, 0, missing)
+
+	var nextKey int64
+	assert.NoError(t, db.Get(&nextKey, `SELECT next_key FROM horizon_backfills WHERE id = 'backfill_test_double_amount'`))
+	assert.Equal(t, int64(15), nextKey)
+
+	// Completed backfills are not run again.
+	completed, err = RunBackfills(db.DB, backfills, 4, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, completed)
+}
+
+func TestRunBackfillsEmptyTable(t *testing.T) {
+	tdb := dbtest.Postgres(t)
+	defer tdb.Close()
+	db := tdb.Open()
+	defer db.Close()
+
+	_, err := Migrate(db.DB, MigrateUp, 0)
+	assert.NoError(t, err)
+
+	db.MustExec(`CREATE TABLE backfill_test (id bigint PRIMARY KEY)`)
+
+	completed, err := RunBackfills(db.DB, []Backfill{
+		{
+			ID:    "backfill_test_empty",
+			Table: "backfill_test",
+			Key:   "id",
+			Query: `SELECT $1::bigint, $2::bigint`,
+		},
+	}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, completed)
+}
@@ -30,7 +30,14 @@
 // migrations/35_drop_participant_id.sql (306B)
 // migrations/36_deleted_offers.sql (956B)
 // migrations/37_add_tx_set_operation_count_to_ledgers.sql (176B)
+// migrations/38_ledgers_closed_at_sequence_index.sql (451B)
+// migrations/39_trade_aggregation_rollups.sql (1.597kB)
 // migrations/3_use_sequence_in_history_accounts.sql (447B)
+// migrations/40_accounts_home_domain_index.sql (364B)
+// migrations/41_partition_history_tables.sql (9.414kB)
+// migrations/42_create_backfills_table.sql (362B)
+// migrations/43_create_jobs_table.sql (762B)
+// migrations/44_create_usage_table.sql (604B)
 // migrations/4_add_protocol_version.sql (188B)
 // migrations/5_create_trades_table.sql (1.1kB)
 // migrations/6_create_assets_table.sql (366B)
@@ -706,6 +713,46 @@ func migrations37_add_tx_set_operation_count_to_ledgersSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations38_ledgers_closed_at_sequence_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x90\xcd\x6e\xc2\x30\x10\x84\xef\x7e\x8a\x39\x82\x4a\x78\x81\x9c\x50\x13\x55\x5c\x42\x45\x89\xc4\xcd\x32\xc9\x92\x58\x32\xbb\xc5\x76\x09\x79\xfb\xfe\x44\x58\xa2\x27\xb8\xad\x34\x3b\x33\x9f\x26\xcb\xf0\x72\xb2\x9d\x37\x91\x50\x7f\x2a\x95\x65\x58\x39\x27\x43\x80\xa7\x20\xee\x62\xb9\x83\x41\xe3\x24\x50\xab\x4d\x44\xb4\x27\x82\x37\xdc\x11\xa2\xfc\x28\xd3\x29\x47\x38\x6a\x3b\xf2\x08\x74\xfe\x22\x6e\x28\xfc\x26\x0d\x36\xf6\x30\x0c\xcb\x2d\x5d\x21\xec\x46\x84\xc6\xf0\x52\xbd\x6e\xcb\xd5\xae\xc4\xba\x2a\xca\xfd\xa4\xea\xde\x86\x28\x7e\xd4\x53\x4e\xd0\xc2\x3a\xb5\x6a\xc3\xad\xbe\x25\x63\x53\xe1\xdf\x33\xea\x8f\x75\xf5\x86\x43\xf4\x44\x98\x25\xdb\x22\xd1\xcc\x73\x55\x6c\x37\xef\x0f\x17\xe6\x7f\x43\xa4\x61\x0a\x19\x58\x3d\x0b\xfd\x38\xe7\xb3\x74\x77\x73\xe4\xea\x1b\xec\x4c\x41\x80\xc3\x01\x00\x00")
+
+func migrations38_ledgers_closed_at_sequence_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations38_ledgers_closed_at_sequence_indexSql,
+		"migrations/38_ledgers_closed_at_sequence_index.sql",
+	)
+}
+
+func migrations38_ledgers_closed_at_sequence_indexSql() (*asset, error) {
+	bytes, err := migrations38_ledgers_closed_at_sequence_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/38_ledgers_closed_at_sequence_index.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6b, 0x5c, 0xcc, 0xef, 0xb7, 0xe0, 0x38, 0xd5, 0x60, 0xa7, 0x1b, 0xba, 0xc8, 0x50, 0xce, 0x1e, 0x56, 0xd0, 0x1a, 0x78, 0x68, 0xe0, 0x2c, 0xa4, 0x68, 0x14, 0xf2, 0x6e, 0x4, 0x51, 0x77, 0xe0}}
+	return a, nil
+}
+
+var _migrations39_trade_aggregation_rollupsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x55\x4d\x6f\xa3\x30\x10\xbd\xfb\x57\x8c\x7a\x82\x2e\xe9\xa6\x87\x3d\xf5\x44\x13\xb7\x8a\x36\x85\xc8\x49\x56\x1b\x55\x15\xe2\xc3\x21\x56\x01\x23\xdb\xf4\xe3\xdf\xaf\xed\x04\x4a\xd2\x92\x1e\x96\x83\x65\x7b\x66\xde\xcc\x3c\x3f\x9b\xd1\x08\x7e\x94\x2c\x17\xb1\xa2\xb0\xae\x11\x1a\x8d\x60\x21\xe8\x28\xce\x73\x41\x73\xbd\x99\x81\x12\x71\x46\x21\x69\xd2\x67\xaa\x24\x6c\xb9\x00\xb5\xa3\x20\x9b\x64\x54\xb2\xaa\xd1\x61\x7b\x87\x36\x82\xf1\xca\x80\x08\x2a\x79\xd1\x98\x95\xbc\x02\xc2\x5f\x25\xc4\x82\xc2\x33\xad\x15\xb0\xca\x22\xa4\x71\xc5\x2b\x96\xc6\x05\x24\xb1\xa4\x3f\x53\xde\x54\x8a\x0a\xe0\x22\x33\xe3\xd6\x80\xec\x98\x54\x5c\xbc\x47\x36\x83\x46\xa8\x32\x8b\x22\x68\xd2\xb0\x42\x41\xd6\x08\x56\xe5\x1a\x2f\xa7\xd2\x64\xba\x42\x13\x82\xfd\x15\x86\x95\x7f\x3b\xc7\xc7\xd1\x91\xe0\x45\xd1\xd4\x12\x1c\x04\xfa\xfb\x28\x0f\x12\x96\xb3\x4a\x41\x10\xae\x20\x58\xcf\xe7\x9e\xb5\x5f\x28\x56\x6a\xd0\xb8\xac\x2f\xbe\x76\x30\x35\x47\xb1\x94\x54\x45\x2c\x3b\x75\x01\x82\xef\x30\xc1\xc1\x04\x2f\xbb\x2a\xac\xaf\x74\x58\xe6\xee\x01\x0e\xfd\xfe\x3f\xc6\x99\xfa\x5e\x74\x8f\x25\x85\x4a\x0f\x82\xa5\x27\x1e\x6d\x01\x67\x9d\x76\x2c\xdf\xb5\xa6\xc7\xa7\x13\x63\xc1\x5f\x07\x6d\xbc\xa6\xd5\xa0\x31\x2d\xb8\xa4\x83\xd6\x05\x99\x3d\xf8\x64\x03\xbf\xf1\x06\x9c\x23\x9e\xbd\x4f\xac\x79\xbd\x93\xf4\xfa\xa7\xe6\x22\xf7\x06\xb5\x7a\x98\x05\x53\xfc\x17\x76\x4a\x64\xad\x0c\xa2\xe4\x3d\xfa\x88\x8c\xb4\xb2\x22\x13\x0b\x61\x30\x20\x9b\xf5\x72\x16\xdc\x43\xa2\x04\xa5\xe0\x0c\xe5\xd4\x19\x67\xc1\x12\x93\x95\xce\xb8\x0a\xbf\x46\x42\x4b\x3c\xc7\x93\xd5\x50\xdd\x1e\x7c\xdb\xb1\xdd\x71\x2e\x0f\x1a\x90\x4d\x79\x20\xa9\x34\xfb\xae\x67\x77\xba\xa8\xc3\xa6\x75\x2d\xe3\xb7\xa8\xd6\x8c\x53\xc7\x8e\xda\x55\xdf\xe0\x93\x9d\x2d\x13\x52\x75\xab\x22\xee\x16\xe8\x8e\x84\x0f\x87\xdb\xd3\xb6\x70\xd5\x6b\xc2\x1a\xcc\x97\xb1\x17\x27\x35\x71\x0e\x7d\xd3\x9d\xa7\xca\xa1\x35\x4f\x77\xb0\x15\xbc\x84\x82\x66\xb9\x2e\xcb\x0a\x20\x8b\x62\xe5\xc2\x25\x5c\x8f\xc7\x63\x17\x62\x79\x10\xb2\x4e\xdb\x07\x36\x1e\xfd\x35\xf8\xcb\x23\xbe\xba\xbc\x2d\xdb\x5a\x79\xc2\x3e\x43\x96\xac\x0b\xfb\x9e\x7c\xa6\xb5\x47\xd9\x20\xc7\x1d\x7f\x1f\x49\x7c\x42\xfc\xcd\xa3\x65\x24\xd2\x07\xb7\x9f\x64\x4f\xa6\x28\x3b\xb7\x8e\x96\xa9\xe3\xb7\xcb\x03\xe7\x8f\x3f\x5f\xeb\xbb\xec\xfc\x32\xed\xea\xf5\xb5\x9d\xb8\x26\x54\xf4\x14\xe5\x5a\x88\x90\x4c\x31\x81\xdb\xcd\xf9\xae\x90\x6b\x45\x8d\xee\x49\xb8\x5e\x18\xef\xbe\xa8\xbe\xd5\x51\x8f\xc5\x1b\xfb\xf0\x77\x3f\x82\x29\x7f\xad\x10\x9a\x92\x70\x71\xee\x29\xbd\x41\xff\x00\x5c\x75\x58\x33\x3d\x06\x00\x00")
+
+func migrations39_trade_aggregation_rollupsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations39_trade_aggregation_rollupsSql,
+		"migrations/39_trade_aggregation_rollups.sql",
+	)
+}
+
+func migrations39_trade_aggregation_rollupsSql() (*asset, error) {
+	bytes, err := migrations39_trade_aggregation_rollupsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/39_trade_aggregation_rollups.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x96, 0xc9, 0x13, 0x87, 0x56, 0xd7, 0xe, 0x4a, 0x11, 0xa4, 0x3b, 0x3, 0xfa, 0x60, 0xa9, 0xb9, 0xc9, 0x88, 0xfb, 0x4f, 0xae, 0x71, 0x60, 0x60, 0xd8, 0x58, 0x6e, 0x23, 0xb3, 0x8f, 0x73, 0x1d}}
+	return a, nil
+}
+
 var _migrations3_use_sequence_in_history_accountsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x91\x4d\x6b\xb3\x40\x14\x85\xf7\xf3\x2b\xce\x2e\xca\xfb\x66\x91\x6d\x5c\x4d\xc6\x1b\x22\x8c\x63\x3b\x5e\xdb\x64\x25\xa2\x43\x3a\x90\x6a\xeb\xd8\xaf\x7f\x5f\x48\xd3\x0f\x08\x6d\xa1\xcb\x73\x78\xe0\x39\xdc\x3b\x9f\xe3\xdf\xad\xdf\x8f\xcd\xe4\x50\xdd\x09\x65\x49\x32\xa1\xa4\xcb\x8a\x8c\x22\xdc\xf8\x30\x0d\xe3\x4b\xdd\xb4\xed\xf0\xd0\x4f\xa1\xf6\x5d\x1d\xdc\xbd\x00\x80\x92\xa5\x65\x5c\x67\xbc\xc1\xe2\x58\x64\x46\x59\xca\xc9\x30\x56\xbb\x53\x65\x0a\xe4\x99\xb9\x92\xba\xa2\x8f\x2c\xb7\x9f\x59\x49\xb5\x21\x2c\x12\x51\x92\x26\xc5\x08\x6e\x7a\x6c\x0e\xd1\xec\x1b\xef\xec\x3f\xa2\x13\x99\xcb\x6d\xe4\xbb\x18\x6b\x5b\xe4\x67\x33\xe3\x38\x11\x52\x33\x59\xb0\x5c\x69\x42\x61\xf4\xee\x0c\xc2\x1b\xa1\x0a\x5d\xe5\x06\xbe\x43\x49\x8c\x94\xd6\xb2\xd2\x8c\xde\x3d\xff\xbc\x64\xb9\x1c\xdd\xbe\x3d\x34\x21\xc4\x89\x10\x5f\xcf\x98\x0e\x4f\xfd\x1f\xec\xa9\x2d\x2e\xde\xf5\x89\x38\xa6\xdf\xde\x90\x88\xd7\x00\x00\x00\xff\xff\x55\xe2\xdd\x2c\xbf\x01\x00\x00")
 
 func migrations3_use_sequence_in_history_accountsSqlBytes() ([]byte, error) {
@@ -726,6 +773,106 @@ func migrations3_use_sequence_in_history_accountsSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations40_accounts_home_domain_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x8f\xbd\x0e\x82\x30\x14\x46\xf7\x3e\xc5\x37\x6a\xb4\xbe\x00\x13\x4a\x63\x58\xc0\x20\x24\x6e\x4d\x43\x2b\x6d\x02\x2d\x81\x12\x7c\x7c\x7f\x03\xc6\x41\x9d\xee\x70\x73\x72\xbe\x43\x29\x56\x8d\xa9\x3a\xe1\x15\x8a\x96\x10\x4a\x11\xd6\xb5\x1b\x7b\xb4\xa2\x32\xb6\x82\xd7\x9d\x1b\x2a\x7d\xbb\x0a\xa2\x2c\xdd\x60\x7d\x0f\x77\x86\x80\x76\x8d\x82\x74\x8d\x30\x16\xa3\xf1\x1a\xc2\xc2\x58\xa9\x2e\xe8\x4b\x61\x37\x64\x97\xb1\x30\x67\x88\x93\x88\x9d\x26\x94\xdf\x29\xfe\xa4\xb8\xb0\x92\xbf\x1e\xdc\x48\xa4\xc9\x6c\x28\x8e\x71\xb2\xc7\x36\xcf\x18\xc3\xe2\x8d\x59\x63\x06\x96\x01\x89\xb2\xf4\xf0\xc5\x10\x3c\x82\xa6\xc0\xc8\x8d\x96\xfc\xde\xf5\xd7\x90\xdf\xf2\x8f\xbc\x80\x5c\x01\x08\xc4\xac\x4b\x6c\x01\x00\x00")
+
+func migrations40_accounts_home_domain_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations40_accounts_home_domain_indexSql,
+		"migrations/40_accounts_home_domain_index.sql",
+	)
+}
+
+func migrations40_accounts_home_domain_indexSql() (*asset, error) {
+	bytes, err := migrations40_accounts_home_domain_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/40_accounts_home_domain_index.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4, 0x74, 0x59, 0xe8, 0xc7, 0xca, 0x8c, 0x22, 0x6c, 0x5f, 0xb1, 0xf9, 0x5f, 0x0, 0x76, 0x69, 0x60, 0xd9, 0x8, 0x11, 0xb2, 0xf2, 0xad, 0x1a, 0xd8, 0xc5, 0xd8, 0x1c, 0x55, 0xd2, 0xa5, 0xf7}}
+	return a, nil
+}
+
+var _migrations41_partition_history_tablesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x59\x5b\x77\xda\x48\x12\x7e\xf7\xaf\xe8\x07\xfb\x48\x4a\x08\x19\x67\xcf\xcc\x03\x4e\x66\x8f\x02\xb2\xc3\x0e\x06\xaf\x80\x49\x72\x92\x2c\x47\x48\x0d\x68\x2c\x24\x45\x6a\x7c\xd9\x9d\xfd\xef\x5b\xd5\x17\xa9\x75\x01\xdb\x99\xcc\x0e\x0f\x36\x6a\x75\xd7\xf5\xab\xea\xaa\xe2\xc5\x0b\xf2\x7c\x1b\xae\x33\x8f\x51\x32\x4f\x8f\x8e\x5e\xbc\x20\xfd\x24\xbe\xa1\x19\xcb\x09\xdb\x50\xb2\x09\x73\x96\x64\xf7\x84\x79\xcb\x88\xc2\x52\xa2\xbe\xa5\x5e\xc6\x42\x16\x26\x31\x0d\xc8\xf2\x9e\x64\x5e\xbc\x86\xd5\x64\x45\x4e\x7f\xc0\x0f\x12\x8a\x68\xb0\xa6\x19\x2c\xc6\x9c\x14\x4b\xc2\x00\x37\xc0\xf7\x30\x23\x59\x72\x9b\x77\x48\x4e\x29\x09\x96\xaf\x5e\x4a\x36\x2f\x0b\xaa\x79\x77\x9d\x74\xc9\xbb\x24\x0b\xff\x9d\xc4\x48\xcc\xcf\x28\xc8\x28\x84\x2a\x77\x21\xbd\x98\xde\x16\xac\xbc\x38\xe0\x3b\x60\x73\x4a\x33\x12\x64\x49\xda\x72\x04\xe9\xed\xe2\x8c\x32\x2f\x44\xf1\xd5\xe1\x30\xce\x19\xf5\xb8\x8c\x01\x8d\x28\x0b\xe3\xb5\x26\x6c\x17\x4e\xe1\xc1\x2b\x45\x09\x5f\x67\xf4\xeb\x2e\xcc\xd0\x1c\x49\xce\xd6\xf8\xe5\xf4\xb4\x4b\x26\x31\x49\xa2\x00\xf8\xe7\x34\xbb\x41\xca\x5c\x7d\x61\x37\x2f\xa3\xc0\x70\xc5\x84\x0c\xfe\x06\xed\x16\x28\xda\x33\xd8\x47\xef\xc0\x16\x9c\x36\x30\x25\x4b\xea\x27\x5b\xca\x09\xac\xc2\x2c\x67\xa5\x22\x28\x26\x05\xea\xd2\x37\xc4\x7c\xcd\xff\xff\xbc\x48\x7f\xb0\x90\xd6\xed\x26\xf4\x37\x64\x03\x82\x00\xd3\x28\xe2\x24\x94\xa6\x4b\xba\x4a\x32\x41\x35\xa6\x77\x8c\x6c\x77\x11\x0b\x53\x20\x52\xf8\x8f\x78\x2b\x06\x0a\xc0\x0e\xee\x49\x34\x3d\x93\xc7\xbb\xc4\x26\xfe\x86\xfa\xd7\xc4\x07\x73\xb2\x0c\x8c\x08\x14\x3c\xe6\x6f\xa4\xc1\xc8\x32\xd9\xc5\x41\x2e\x7d\xad\x49\x1c\xe6\x48\xcc\x0b\x02\x30\xfa\x6d\xc8\x36\xc9\x8e\x91\x1b\x2f\x0a\x03\x8f\xbf\x46\xe7\xc9\x47\x04\x95\x90\xd1\x63\xcc\x13\x94\x43\xd6\x91\x4a\xe5\xbe\x17\xe7\x4a\x36\xa1\x7d\x12\xfb\x14\xe0\x04\xf0\xdc\x78\xac\x72\x88\x04\x09\x98\x3d\x4e\x18\x3f\x86\x0b\xde\x1a\x44\xd6\x4d\x2e\x02\x40\x48\xc8\x77\x22\x12\xbc\x98\xf5\xc0\xbf\xb1\xb7\x45\x3a\x28\x5b\x49\x55\xf3\x27\xf3\xae\x29\x57\xcb\xee\xf7\x9d\xe9\x94\x38\x1f\xfa\xa3\xf9\x74\xf8\xab\x43\xa2\xc4\xbf\x56\xe0\xdf\x2a\xd1\xd1\xfd\x1b\x1a\x05\xe0\x7b\x16\x0a\xa7\x94\xdc\xc1\xd5\xdb\x90\xe5\x1d\x24\x07\xaa\x84\x18\x52\x85\x65\xf4\x68\xa4\x71\x90\x26\x60\x76\x01\xa7\x25\x72\x42\x93\x6e\xc2\x88\xd6\xb0\xc6\x49\x81\xde\x71\x09\xb2\x79\x1c\x7e\xdd\x51\xa0\x1e\xd0\x3b\x11\xb2\x7a\x2c\xcb\xa3\xdb\x1d\xf8\x1b\xfc\x8b\x21\x52\xf3\xe2\x35\xbd\xef\xa2\xd9\x04\x82\xeb\xb4\xa4\x88\x0b\x2d\xf8\x73\x08\x12\xca\xfd\x23\x16\x17\x1b\x2f\xdf\xa0\x4e\x48\x21\xcd\xe8\x4d\x98\xec\xf2\x45\xe5\x9d\x00\x67\x26\x20\x00\x08\x05\x85\x25\x66\xb8\x30\x45\x22\x51\x88\xee\x28\x72\x9a\x95\x94\x08\x0b\x29\x24\xcb\xc2\x35\x3c\x0b\xec\xe6\x02\x28\xb0\xfd\x9e\x80\xaf\xef\x95\x26\x9e\x9f\x25\x79\x81\x2d\x2d\x1d\xf1\xd4\x58\xa4\xca\x29\x83\xbf\x5b\x1a\xb3\xb7\x74\x1d\xc6\x47\x7d\xd7\xb1\x67\x0e\x39\x9f\x8f\xfb\xb3\xe1\x64\x0c\x51\xc7\xf3\xd6\xa2\x38\xbf\x50\x32\x71\xf3\x9a\x6c\x09\xae\x07\xb5\x3a\x25\x87\x05\x98\x55\xae\xed\x52\xc8\x5d\x0b\x1e\x44\x64\x19\x02\x7d\x66\x11\xd7\x99\xcd\xdd\xf1\x94\xdc\x60\x12\xb5\xa7\xe4\xf8\xf8\x68\xe0\xf4\x47\xb6\xeb\x1c\x11\xf8\x44\x74\xed\xf9\xe2\x3c\xe9\xbd\x21\x48\xff\xf7\xdf\x89\x01\xb9\xc0\x38\xe3\x1b\x80\xfa\x02\xf0\x1b\xef\xb6\x24\xdf\x42\x3a\x00\xa2\xe2\x05\x77\xdd\x02\x50\x8e\x58\x86\xe3\x9f\xbe\xe8\xeb\x01\x5d\xb5\x2f\x6f\x13\x1e\xa3\x49\x12\x51\x2f\x56\x2f\xb9\xc8\x0b\x91\x1b\x94\x28\x98\xeb\x50\xdf\x0e\xf9\xf1\x07\x4b\x09\x25\x94\x93\xa2\xad\xae\x21\xcc\xfc\x24\x0b\xd4\xa3\xe2\x88\xc7\x8d\xff\xfc\x57\x6e\xcb\x95\xc9\xf9\xdb\xb3\xa3\xb7\xce\xc5\x70\xcc\xdf\x38\x1f\x9c\xfe\x1c\xcc\x0f\x60\x81\x34\x64\x1a\xf6\x68\xe6\xb8\x64\x66\xbf\x1d\x39\xe4\x64\x08\xa6\x1b\xdb\x97\x0e\x99\x4d\xe0\xc1\xe8\x10\x2e\x8b\xb0\x97\x75\xd6\x76\x9e\xaf\xe1\xc7\x90\x6e\x2d\x28\x99\xa3\xe1\x2f\xfc\xcb\x70\x0c\x41\x3e\x18\x8e\x2f\xc8\xc0\x39\xb7\xe7\xa3\xd9\x54\x5b\xea\x4f\xc6\xd3\x99\x6b\x0f\xc7\x95\xd5\xe9\x6c\xe2\xda\x17\x8e\x45\xae\x6c\x77\x36\xe4\x28\x79\xfb\x91\xb8\xf6\xf8\xc2\x21\xe6\xc9\xd0\x32\x3a\x05\x5f\x4d\xc2\x1a\x40\xf8\x16\x90\x9a\xff\x07\x3c\x62\x78\x84\x6b\x1e\x92\x22\x15\x88\xdc\xba\xf4\xd0\x01\x49\x35\x6e\x55\x6c\xf3\x54\x29\x2e\x49\xbc\x60\x30\x47\x48\x6a\x22\xbf\xe1\x3e\xc8\x3d\x8c\xaf\x9e\x4f\x5c\xf4\x8f\x34\x34\x7e\xa6\xce\xc8\xe9\xcf\x30\x35\x20\x66\x40\xbe\xf5\x62\x4d\xd9\xa2\xbc\x0a\x00\x32\x26\x80\xd4\x42\x94\xc2\xf7\xe2\xe0\xb9\x3b\xb9\xc4\xdd\xe5\xce\xe2\xd5\xfb\x77\x8e\xeb\x20\xc9\x8c\x42\xf6\x27\x6f\xa4\xf2\xbd\x5e\x46\xd7\x7e\xe4\x41\x3c\xda\xe3\x01\xcf\x46\xf7\x29\x85\xd7\xc6\xca\xe0\x67\x47\x93\xc9\x55\x41\xe4\x01\x14\x0c\xdc\xc9\x95\xe6\x1a\x81\x05\x65\xe4\xd5\x75\x57\x2a\x24\x21\xa1\x80\x08\x08\xc4\x7f\x00\xdb\x3d\x64\xed\xc1\xa0\x4a\x95\x9c\xe4\x0a\x64\x25\x55\xfe\x1d\x8c\xa1\x00\x07\xda\xa0\xec\xd2\x91\x7b\xf1\xf7\x20\xaf\xfe\x3b\xa7\xff\x0b\xc2\x87\x0c\xa7\x64\x3c\x99\x91\xf1\x7c\x34\xe2\xc6\x82\xa5\xd7\x20\x8a\xc5\x57\x7f\xb5\x47\xc3\x81\x06\x30\xa5\xb6\x16\xaf\x35\xa0\x35\x1e\xb5\x9c\xa4\x40\xf8\x08\xa3\x73\xc6\x18\x42\xfb\x0c\xaf\x49\xf0\x50\x30\xd6\x8d\x31\x9b\xd9\xfd\x77\x5a\x34\xc1\x1a\xa2\x15\x58\xce\x9d\xa9\x40\x9b\x79\x39\x1c\xf3\x67\x0b\xa3\xdf\x04\x73\xec\x8b\xb2\x6f\x53\xef\x20\xa6\xaa\xaa\xa9\x68\x72\x50\xe8\x32\x95\x0d\xc7\xc4\x76\x5d\xfb\x23\x47\x59\x2b\x9c\x8b\xbd\xad\xd0\x91\x37\x9e\x76\xff\xb6\xc7\xfc\x35\xa5\xa9\xac\x65\x79\xaa\xef\xf0\x7d\xb0\x41\x9d\x29\x32\x4a\xad\xc8\xc4\xa4\xc2\x8b\x20\x20\x55\xd6\x40\x22\x47\x60\x5d\x73\xa4\xe5\x04\x79\xbf\x80\x25\x26\xda\x7d\x73\xa4\x07\x3f\x2c\x65\xe1\x72\xc7\x04\x3f\x11\xf7\xb8\x76\x28\xee\x91\x0c\xf0\x87\xd7\x15\x4c\xb6\x5b\x80\x97\xd7\x89\x87\x59\x70\x97\xae\x32\x48\x19\x24\x0f\x55\xc2\x8b\x92\x24\x2d\x1a\x09\x6c\x1b\xd0\x00\x9a\x06\x85\xf1\xbd\x2c\xf3\x40\xfe\xf5\xda\xf4\xbb\x20\x1b\xb2\xef\xf5\xf0\xda\xb1\x3a\x2d\x7b\x64\x0a\xe4\x22\x60\xf6\x0b\xbb\xfc\x2b\x57\xca\x6a\x3d\xc1\x77\x84\xb9\xac\x39\x50\x49\x0c\x53\x53\xbb\xa4\xdf\xc0\xea\x47\x22\x36\xc2\x72\xaf\xa7\x6e\xed\x4f\x5f\x2c\xcb\xe2\x24\xb9\x9d\xb5\xeb\xbb\xa3\xdd\xd9\x9d\xea\x45\x5d\xf1\x01\x7f\x43\x42\xbe\xf6\x8f\x09\x20\x10\x93\x32\xb7\xb6\x4f\x20\x8e\xfc\x6e\xc2\x7d\xa1\x6b\xa1\x79\x8b\x2f\xef\xf3\x57\x89\x73\x12\x23\xb6\x4f\x49\xb7\x0b\x79\xdb\x83\x8a\xd2\xa7\xa6\xd0\x3f\xa2\xf1\x9a\x6d\xcc\x8a\xe0\xa7\x56\x87\x40\x75\xf0\x88\x7c\x3e\x1c\x0f\x9c\x0f\x2d\xb7\xba\x46\xee\x53\xfc\xa5\x23\xca\x8e\xfa\xe2\x4f\x65\x05\x62\xa8\x98\xe4\x96\x3c\xaf\x9a\x0b\x36\x93\xd9\x3b\xa7\xbc\xf4\x24\xd0\x9a\xd5\xee\x93\x2a\xe7\x3a\x39\x9e\x58\xf3\xa4\xc4\xef\xfe\xf8\x85\xbe\x44\x32\xc7\x26\x85\xe5\x75\x52\x7e\x12\xed\xb6\x71\xd9\xff\x36\xab\x75\x92\x63\x0c\x49\xdf\x43\x07\xb8\x0b\x23\x26\x9b\x93\x3a\xb1\x46\x0e\xa8\xc5\x3d\x36\x62\x2d\x62\xd7\xc9\xb4\x6a\xc1\xfb\x3b\x21\x90\x6e\x4d\xa5\x39\x67\xbd\x97\x10\x12\xb8\xa6\x29\x74\x70\x39\xb9\xa5\x51\x24\xe8\x40\xad\x0d\x35\xb2\x17\x61\xb5\x11\xc6\xd5\x8d\xe0\x02\xaf\x69\xab\x2d\xcf\xbd\x00\x2c\xce\x33\x48\x6e\xe3\xb2\x09\xeb\x56\xb6\x2b\x10\x02\xc0\xe9\x5d\xba\xc8\x68\x1a\x79\x80\xe4\xca\x1e\xfc\x94\x91\x87\x40\x6b\xbc\x36\x30\xb0\x3e\x4f\x9f\x13\xe8\x0c\xa1\x16\x34\x3f\xdf\x3e\xb7\xc8\x67\xd3\xec\x3e\xb3\x3e\x5b\xc7\x46\xf3\x80\x02\x3d\x9e\x9b\x8c\x47\x1f\x11\xf1\xe2\xec\xe7\x53\x38\xfe\xaa\x43\x78\xc9\x28\x2e\xb1\x4a\x5a\xb4\x2a\xb4\xb4\x72\xe6\x29\xea\xd4\xde\x57\xb5\x23\xc6\xbf\x4c\x59\x19\xcf\xc7\xc3\x7f\xce\x1d\x11\x94\x16\x2a\x08\x22\x29\xd1\x41\x50\x79\x1f\xb6\x44\xe2\x8f\x3f\x95\xbd\x00\x08\x6d\xe8\x69\xf2\xbb\x18\x6d\x8f\xbd\x5a\xab\xea\x47\x99\x6c\x4f\x1a\x6a\x29\x43\x1e\x9b\x8d\x1a\x36\x38\xcc\xb8\x3f\xb9\xbc\x74\xa0\xc8\x00\x1e\x05\x77\xa8\xfa\x4e\x46\x2d\xfc\x0c\x19\x5b\x72\x86\x92\x6a\x23\xab\x1e\xd8\x15\xb8\x56\x5c\xaa\x71\x76\x46\x53\xe7\x31\x90\xa9\x43\xa2\xea\x2b\x0d\x06\x4d\x04\x0b\xd8\x5a\xff\x37\x3b\xd7\xb2\x7e\xc1\x10\x6e\xdd\xe1\x79\xbd\xaa\x82\x6f\x67\x47\xc7\xc7\x64\x04\x8d\xd9\x1c\xfa\x35\x92\x46\xe9\x3a\xff\x1a\x9d\xb5\xb7\xff\x0e\xd4\x8b\x87\x06\x03\x83\x49\xbd\x53\x6f\x36\xf7\x7a\x2b\x0b\x37\x91\xbf\xcb\xa0\xe0\x62\x8b\x9c\x32\x1c\x03\x9a\x86\x18\x22\x2e\x70\x90\x88\x80\x85\xea\xc0\xb0\x7a\x3d\x1c\xba\xbd\x26\xa7\x7c\x5a\x57\xbd\xac\xc4\xa8\xa0\x54\x0c\x95\xd4\xcb\x34\xd3\xec\x4f\xec\x91\x33\xed\x3b\xe6\xa5\xfd\xc1\x54\xd3\x19\x71\x05\xbf\x94\xf3\x3f\x8b\x3c\x87\x5b\x99\x3c\x53\xe3\x40\x5e\x6c\xe8\xb2\xf3\x6a\xa2\x36\x69\x39\x6b\x68\x08\xfd\x93\xfe\xf8\xfa\x35\xf9\xdb\x2b\x29\xcd\x95\xe3\x42\x9d\x70\xf9\xe0\xac\xc4\xa8\x31\x01\xb7\x1b\x61\x60\x54\x6a\x76\xe9\xd6\x27\x93\x84\x3e\x34\xce\x3d\x9f\x4f\x78\xbe\x27\xdd\x04\x28\x78\xdf\x9d\x2a\x5d\xad\xa8\xcf\x38\xc9\x06\xa3\xc5\x77\x34\x49\x40\x1f\xcf\x43\xaf\xc1\x1f\x1e\x06\xc6\xc9\x6d\x51\x1a\x41\x15\x43\xc2\x40\xfc\x04\xe0\x2d\x93\x1b\x51\x3f\x34\x26\x69\xb5\x59\x1e\xef\xa7\xe4\x44\xcf\x2c\xc7\x62\x6a\xb6\x87\x93\xb1\xd5\x31\xa7\x54\x06\x95\x0c\x2c\xe7\xc3\x70\x3a\x9b\x92\xea\x6d\x27\x63\xe2\xb4\x15\xcf\x95\x9d\xb2\xfc\x05\x10\xff\x4c\xc6\xce\xfb\x2e\xce\xe1\x20\xba\x9a\x97\xa7\x0a\x28\xa8\x8f\x71\x5f\xf1\x38\x71\x1b\x5b\xf5\x99\xa7\xd8\xad\xaf\xb4\x1c\x68\x9d\x96\x8a\x93\x6d\xaf\xaa\x17\x5b\xf1\x64\x35\xab\x5b\x68\x60\xa7\x8e\xf4\xe0\x02\xe8\x44\x62\x22\x2d\x32\xf6\xa5\x33\x9d\x62\x2e\x7c\x53\x64\xe7\x60\x97\x46\xa1\x8f\x09\x4f\x70\x23\x27\xf8\xf3\x49\xdd\x7e\x80\x16\xdd\x02\x7b\x92\x6f\x99\xb3\xf8\xf8\xa2\x48\x5c\xe2\x0b\xb8\xb3\x25\x19\xeb\x58\x99\xb9\xc3\x8b\x0b\xb8\x2b\xda\xc7\xbe\x05\x0b\xfb\x5c\x5c\x28\x53\xc7\x85\x4b\xd4\x25\xf3\x2b\x3e\x99\x98\x9c\xb7\xcf\xa7\x3b\xed\xb6\x6e\x42\xb2\x1c\x70\x01\x51\xde\xde\xbb\x93\xf7\xc5\x75\x76\xe5\x4e\xfa\xce\x60\x0e\xc8\x39\x8c\xe4\xe2\xe6\x39\x74\xd1\xf0\x79\xc3\xe3\x87\xcc\x7c\x98\x2c\xfe\xca\x31\xf2\x59\xf5\xb2\x1a\x40\xe5\xfb\x4d\x73\xed\x5d\xfc\xd0\x64\xfb\xc1\x89\x35\x94\x11\xd8\x1f\x35\x06\xd6\xb8\x2c\x27\xbe\x61\x70\x57\x9b\x0c\x57\x1e\x8b\xc9\xc8\x53\xe7\xc5\xe8\x28\xa4\xdd\x98\x6a\x56\x22\xa2\xe8\xfc\x51\x78\x3e\xc4\xab\xbc\xee\xdb\xb5\x42\x09\x3f\x90\x23\xc6\x24\x59\xfe\x06\x85\x51\xee\x67\x61\x8a\x06\x32\x79\x43\x0d\xf9\x54\xb5\xd9\x06\x34\xba\x38\x47\x3e\x58\xa4\x9d\x18\x0d\xe2\x18\xb3\x24\xdf\x2d\x73\x96\x99\x0f\xf3\x40\x30\xf3\x46\xfb\x70\x2d\x28\xee\xf9\x06\x2f\x2c\x03\xc9\xc1\xb9\x46\xb5\x78\x83\x70\xde\x33\xee\xd5\xa7\x0d\xf8\x79\xca\xc4\x41\x9a\xb4\x3e\x75\x00\xb0\x94\x23\x87\xe6\x20\x58\xc3\x05\x00\x42\x7b\x02\x80\xd5\xab\xd8\xe0\x0e\xc7\xb2\xb2\x7e\x35\x79\x9d\x6a\xfd\x7d\x6f\x1d\xbb\xa7\x84\xad\xcd\xe4\xfe\xa2\x99\x79\xc5\x2a\x8f\x19\x98\x1f\xb2\xd3\x5f\x31\xed\xfe\x33\x7e\x6d\xd1\xba\x44\x9e\x59\xb8\xac\x87\x26\xbc\xf2\x7e\xe0\xe5\x2e\xb0\x96\x9e\x7b\x26\x3c\xc1\x7b\x8e\x92\xce\x1e\x12\x3c\x4b\x2b\x2d\x8c\x43\x3b\x0f\xff\x50\x55\x61\xf4\xd0\xfc\x58\x73\xdf\xd3\xc6\xc8\x7f\x4a\xc3\x53\x69\x68\x70\xb4\xa9\x6a\xaf\x6a\xb9\x55\xa4\x01\x01\x66\x91\x05\x1a\xf5\x7e\x0d\xd5\x80\xf7\x6b\x48\x06\xb8\x33\x35\xac\x47\xb6\x3e\xf5\x32\x78\xff\x05\xd6\x60\xbf\xa7\x90\x7e\x04\x85\x4a\x77\xf1\xed\x64\xb4\x66\xe2\xdb\x89\xa8\xde\xe1\x0f\x69\x83\x8d\xc1\x1f\xa9\x54\x0e\x94\x0d\x58\x32\x9c\xd5\x8e\x95\x35\xfb\x43\xb5\xd3\xff\x00\x51\xbd\xfe\x7f\xc6\x24\x00\x00")
+
+func migrations41_partition_history_tablesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations41_partition_history_tablesSql,
+		"migrations/41_partition_history_tables.sql",
+	)
+}
+
+func migrations41_partition_history_tablesSql() (*asset, error) {
+	bytes, err := migrations41_partition_history_tablesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/41_partition_history_tables.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0x82, 0x9d, 0x84, 0x3, 0x8c, 0xfd, 0xe2, 0x4f, 0x13, 0x9b, 0xe, 0x89, 0xd3, 0xb0, 0x80, 0xb5, 0x51, 0x68, 0xa5, 0x67, 0x43, 0x98, 0x97, 0x51, 0xed, 0x12, 0xa2, 0xe3, 0x96, 0xc4, 0xe6}}
+	return a, nil
+}
+
+var _migrations42_create_backfills_tableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x50\x4b\x6f\x82\x40\x10\xbe\xef\xaf\xf8\x8e\x9a\x8a\xa6\x9e\x9a\x78\xa2\x95\x43\x53\xaa\x86\xe0\xc1\x93\x2e\x30\xc2\x44\xd8\x25\xbb\x4b\x2d\xfd\xf5\xdd\x42\xf5\xd4\xce\x65\x9e\xdf\x23\x13\x04\x78\x68\xb8\x34\xd2\x11\xf6\xad\x10\x41\x80\x4a\x1b\xfe\xd2\xea\x98\xc9\xfc\x72\xe6\xba\xb6\x70\xc6\x97\x3e\x55\x84\xd6\xe8\xd2\x90\xb5\xd0\xe7\xa1\xd7\xaa\x66\x45\x28\xa4\x93\x18\x79\x58\x2b\x0b\xd3\x29\x64\xfd\x0f\xdb\xe9\x97\x0e\x45\x86\x9b\x50\xd7\x9e\x66\xb0\xe4\x61\xd9\x72\x61\xf3\x8a\x1a\xb9\xb8\xa9\xcd\x4b\x3d\x17\x2f\x49\x14\xa6\x11\xd2\xf0\x39\x8e\xfe\xf0\x33\x11\xf0\xc1\x05\xf2\x4a\x7a\x6b\x8e\x0c\x3e\xa4\xe9\x59\x95\x93\xc7\xe5\xd3\x14\x9b\x6d\x8a\xcd\x3e\x8e\xb1\x4b\x5e\xdf\xc3\xe4\x80\xb7\xe8\x30\x1b\x30\x8a\x3e\xdd\xf1\x42\x3d\x32\x2e\x59\xb9\xfb\xe5\xb8\xcd\x75\xd3\xd6\xe4\xa8\x38\x4a\x07\xc7\x0d\x59\x27\x9b\x16\x57\x76\x95\xee\xc6\x09\xbc\x13\x12\xd3\xd5\xf0\xa9\xfb\xe7\xd6\xfa\xaa\x84\x58\x27\xdb\xdd\x7f\x9e\x57\xe2\x1b\xc8\xc2\xde\x5c\x6a\x01\x00\x00")
+
+func migrations42_create_backfills_tableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations42_create_backfills_tableSql,
+		"migrations/42_create_backfills_table.sql",
+	)
+}
+
+func migrations42_create_backfills_tableSql() (*asset, error) {
+	bytes, err := migrations42_create_backfills_tableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/42_create_backfills_table.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5b, 0x9e, 0x2b, 0x24, 0x2e, 0x81, 0x3e, 0xb8, 0x36, 0x3e, 0x3e, 0x1e, 0x90, 0x2, 0x9a, 0xc5, 0x78, 0xf5, 0xff, 0x6c, 0x94, 0x5f, 0x2f, 0x87, 0x1f, 0x3a, 0xf2, 0x9c, 0x1c, 0x17, 0xb8, 0xf3}}
+	return a, nil
+}
+
+var _migrations43_create_jobs_tableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x92\x4d\x4f\xc2\x40\x10\x86\xef\xfb\x2b\xe6\x26\x46\xaa\x31\x31\x5c\x38\xa1\x54\x43\xc4\x42\x10\x12\x3d\x35\xbb\xed\xd0\xae\xb6\xbb\x75\x76\x2b\xe2\xaf\x77\x4a\xf1\x03\x31\xc1\xbd\xed\xcc\x33\xef\x7c\x06\x01\x9c\x94\x3a\x23\xe9\x11\x16\x95\x10\x41\x00\xb9\x25\xfd\x6e\x4d\xfc\x64\x95\xe3\x4f\x91\x3a\xf0\x39\x42\x61\x4d\x16\x50\x6d\x8c\x36\x19\xc8\xb4\xd4\x06\x6c\x85\x1c\xa8\xad\x71\xc0\x0e\x60\x4b\x03\x2a\x99\x3c\x67\x64\x6b\x93\x36\x6a\x6a\xbd\x31\x6e\x45\x99\x71\x5e\x9a\x04\x1d\xb8\x5c\x52\x23\xd5\x78\x53\xe9\xa5\x92\x0e\xbb\xe0\x10\x99\xf1\x48\x46\x16\x67\x4d\x05\xa7\xe2\x6a\x16\x0e\xe6\x21\xcc\x07\x97\xe3\x70\xb7\xb8\x8e\x00\x7e\x3a\x05\xa5\x33\x87\xa4\x65\x01\xd1\x64\x0e\xd1\x62\x3c\x86\xe9\x6c\x74\x37\x98\x3d\xc2\x6d\xf8\xd8\xdd\x60\x7e\x5d\x21\x24\x9c\x54\x26\x2c\x0f\xaf\x92\xd6\x9c\xbe\xd3\xbb\x38\xfe\x0a\x6a\xc1\x8a\x91\xd2\xc1\x93\xb3\x46\xfd\x72\x71\xed\xbe\x76\x7f\xa8\x9c\xf7\xf6\x54\xc8\x66\x84\x8e\x67\x87\x6f\xfe\xbb\xac\x61\x78\x3d\x58\x8c\xe7\x70\x74\xd4\x62\x48\x64\x69\xc3\xb4\xff\xa4\x19\x4e\x11\x13\xbe\xd4\xe8\x3c\x72\x6b\xd6\x16\x28\xcd\xbe\xc2\x52\x16\x3c\xb0\x36\x88\x90\xf7\x97\xc6\xd2\x83\xd7\x25\xc7\xc9\xb2\x82\x95\xf6\xb9\xad\x5b\x0b\xf0\xc8\x70\xbf\x17\x3a\x1c\xd4\xb2\x39\x32\xab\x38\xc9\xbf\xe8\xa5\x36\xda\xe5\x07\xa5\xc5\x71\x5f\x7c\x2e\x77\x14\x0d\xc3\x87\x9d\xe5\xc6\x6a\x1d\x6f\xc7\x3d\x89\x76\xd7\xbe\xb8\x1f\x45\x37\xa0\x3c\xf1\xad\x74\x5a\xa6\xcb\x57\xd0\xc8\x05\x3f\xce\x79\x68\x57\x46\x88\xe1\x6c\x32\xfd\xe3\x76\xfa\xe2\x03\x8f\x95\x97\xea\xfa\x02\x00\x00")
+
+func migrations43_create_jobs_tableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations43_create_jobs_tableSql,
+		"migrations/43_create_jobs_table.sql",
+	)
+}
+
+func migrations43_create_jobs_tableSql() (*asset, error) {
+	bytes, err := migrations43_create_jobs_tableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/43_create_jobs_table.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1a, 0xfc, 0xd, 0x28, 0x8b, 0x5e, 0xf0, 0xb6, 0x4c, 0x77, 0xe3, 0x87, 0x7b, 0x18, 0x30, 0x96, 0x4, 0x2, 0xda, 0x72, 0x7, 0xeb, 0xf8, 0xab, 0x0, 0xc, 0x19, 0x90, 0x6c, 0xa7, 0xfe, 0x77}}
+	return a, nil
+}
+
+var _migrations44_create_usage_tableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x52\x3b\x4f\xc3\x30\x10\xde\xfd\x2b\x6e\x4c\x44\x02\x12\x12\x2c\x9d\x02\xcd\x50\x51\xda\x2a\x6a\x87\x4e\xd5\xa5\x3e\x5c\x43\x63\x07\xdb\x69\x15\x7e\x3d\x97\x10\x15\x14\x15\x6f\xfe\xee\xf1\x3d\xec\x34\x85\x9b\x4a\x2b\x87\x81\x60\x53\x0b\x91\xa6\x70\xb0\x4e\x7f\x59\xb3\x6b\x3c\x2a\xe2\xdb\x51\x7a\x08\x07\x02\x47\x9f\x0d\xf9\xe0\xc1\x93\x3b\x91\x84\xb2\xed\xe1\xa1\x1d\xb4\xf1\x01\xcd\x9e\xb8\x7e\x40\xa7\x8d\xea\x76\x75\x0d\x12\x03\x96\xe8\x29\x01\x54\xca\x91\x62\x2a\x09\x35\x39\x9e\x6c\x1c\xa0\xf9\xb9\x04\x32\x68\x02\x44\xd9\x6a\x06\x1f\xd4\x82\x75\x4c\x58\x59\x96\xa5\xeb\x6e\x13\x4a\xe9\xc8\xfb\x38\x61\x7a\xc6\x4c\x20\x67\xf0\x78\xd7\x8b\xbc\x15\xcf\x45\x9e\xad\x73\x58\x67\x4f\xf3\x7c\x64\x20\x12\xc0\x87\x29\xb4\x95\x3b\x96\xe8\x02\x04\x5d\xb1\x11\xac\x6a\x38\xeb\xc0\x2a\x7e\x10\xe0\x19\x82\xc5\x72\x0d\x8b\xcd\x7c\x9e\xf4\x63\x83\xaa\x3d\x3b\xc2\x3d\x53\xc2\x09\x5d\xcb\xde\xa2\xfb\x87\xc7\x78\xd4\x7b\xc9\xa7\xd4\x8a\xf5\x8d\xaa\x65\x1b\xe8\x9f\x52\x17\xfe\xee\xa8\x2b\xdd\x05\x73\xb5\x83\xfd\x93\x91\xb5\xe5\x8a\x87\x0a\xeb\xe1\x41\x58\x39\x41\x8d\xa1\xcb\x82\x21\xdb\xa1\xda\x81\x69\xaa\x92\xa5\xda\xb7\x8b\xa4\x7e\xc9\xef\x86\x77\x6f\x4d\x39\xa2\x58\x15\xb3\xd7\xac\xd8\xc2\x4b\xbe\x85\xe8\x6f\x5a\xc9\x10\x42\x2c\xe2\x49\xff\x3f\x2e\xff\x65\x6a\xcf\x46\x88\x69\xb1\x5c\x5d\x0b\x7e\x22\xbe\x01\x62\x2a\x41\x11\x5c\x02\x00\x00")
+
+func migrations44_create_usage_tableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations44_create_usage_tableSql,
+		"migrations/44_create_usage_table.sql",
+	)
+}
+
+func migrations44_create_usage_tableSql() (*asset, error) {
+	bytes, err := migrations44_create_usage_tableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/44_create_usage_table.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x62, 0xfb, 0xa8, 0xe5, 0x43, 0xed, 0x3, 0xb7, 0xc0, 0xef, 0xa2, 0x8a, 0x11, 0x5c, 0x7, 0xb7, 0xae, 0xf3, 0xb0, 0xe, 0x20, 0x45, 0xc8, 0xda, 0x94, 0x29, 0x12, 0xb, 0xd7, 0xae, 0x85, 0x98}}
+	return a, nil
+}
+
 var _migrations4_add_protocol_versionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xcd\xb1\x0a\xc2\x30\x10\x06\xe0\x3d\x4f\xf1\xef\x52\x70\xef\x14\x4d\x9d\xce\x44\x4a\x32\x38\x15\xd1\xa3\x06\x6a\xae\x5c\x82\xe2\xdb\xbb\xba\x88\x4f\xf0\x75\x1d\x36\x8f\x3c\xeb\xa5\x31\xd2\x6a\x2c\xc5\x61\x44\xb4\x3b\x1a\x10\x3c\x9d\x71\xcf\xb5\x89\xbe\xa7\x85\x6f\x33\x6b\x85\x01\xac\x73\xd8\x07\x4a\x47\x8f\x55\xa5\xc9\x55\x96\xe9\xc9\x5a\xb3\x14\xe4\xd2\x78\x66\x85\x1b\x0e\x36\x51\xc4\x16\x3e\x44\xf8\x44\xd4\x1b\xf3\x6d\x39\x79\x95\xff\x9a\x1b\xc3\xe9\x97\xd5\x9b\x4f\x00\x00\x00\xff\xff\x83\xbb\x30\x2e\xbc\x00\x00\x00")
 
 func migrations4_add_protocol_versionSqlBytes() ([]byte, error) {
@@ -987,7 +1134,14 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/35_drop_participant_id.sql":                   migrations35_drop_participant_idSql,
 	"migrations/36_deleted_offers.sql":                        migrations36_deleted_offersSql,
 	"migrations/37_add_tx_set_operation_count_to_ledgers.sql": migrations37_add_tx_set_operation_count_to_ledgersSql,
+	"migrations/38_ledgers_closed_at_sequence_index.sql":      migrations38_ledgers_closed_at_sequence_indexSql,
+	"migrations/39_trade_aggregation_rollups.sql":             migrations39_trade_aggregation_rollupsSql,
 	"migrations/3_use_sequence_in_history_accounts.sql":       migrations3_use_sequence_in_history_accountsSql,
+	"migrations/40_accounts_home_domain_index.sql":            migrations40_accounts_home_domain_indexSql,
+	"migrations/41_partition_history_tables.sql":              migrations41_partition_history_tablesSql,
+	"migrations/42_create_backfills_table.sql":                migrations42_create_backfills_tableSql,
+	"migrations/43_create_jobs_table.sql":                     migrations43_create_jobs_tableSql,
+	"migrations/44_create_usage_table.sql":                    migrations44_create_usage_tableSql,
 	"migrations/4_add_protocol_version.sql":                   migrations4_add_protocol_versionSql,
 	"migrations/5_create_trades_table.sql":                    migrations5_create_trades_tableSql,
 	"migrations/6_create_assets_table.sql":                    migrations6_create_assets_tableSql,
@@ -1069,7 +1223,14 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"35_drop_participant_id.sql":                   &bintree{migrations35_drop_participant_idSql, map[string]*bintree{}},
 		"36_deleted_offers.sql":                        &bintree{migrations36_deleted_offersSql, map[string]*bintree{}},
 		"37_add_tx_set_operation_count_to_ledgers.sql": &bintree{migrations37_add_tx_set_operation_count_to_ledgersSql, map[string]*bintree{}},
+		"38_ledgers_closed_at_sequence_index.sql":      &bintree{migrations38_ledgers_closed_at_sequence_indexSql, map[string]*bintree{}},
+		"39_trade_aggregation_rollups.sql":             &bintree{migrations39_trade_aggregation_rollupsSql, map[string]*bintree{}},
 		"3_use_sequence_in_history_accounts.sql":       &bintree{migrations3_use_sequence_in_history_accountsSql, map[string]*bintree{}},
+		"40_accounts_home_domain_index.sql":            &bintree{migrations40_accounts_home_domain_indexSql, map[string]*bintree{}},
+		"41_partition_history_tables.sql":              &bintree{migrations41_partition_history_tablesSql, map[string]*bintree{}},
+		"42_create_backfills_table.sql":                &bintree{migrations42_create_backfills_tableSql, map[string]*bintree{}},
+		"43_create_jobs_table.sql":                     &bintree{migrations43_create_jobs_tableSql, map[string]*bintree{}},
+		"44_create_usage_table.sql":                    &bintree{migrations44_create_usage_tableSql, map[string]*bintree{}},
 		"4_add_protocol_version.sql":                   &bintree{migrations4_add_protocol_versionSql, map[string]*bintree{}},
 		"5_create_trades_table.sql":                    &bintree{migrations5_create_trades_tableSql, map[string]*bintree{}},
 		"6_create_assets_table.sql":                    &bintree{migrations6_create_assets_tableSql, map[string]*bintree{}},
@@ -64,6 +64,21 @@ func Migrate(db *sql.DB, dir MigrateDir, count int) (int, error) {
 	}
 }
 
+// IsEmpty returns true when the current schema of the database holds no
+// table besides the migrations table, i.e. when it has never been
+// initialized.
+func IsEmpty(db *sql.DB) (bool, error) {
+	var tables int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM pg_tables
+		WHERE schemaname = current_schema() AND tablename <> 'gorp_migrations'`,
+	).Scan(&tables)
+	if err != nil {
+		return false, err
+	}
+	return tables == 0, nil
+}
+
 // GetMigrationsUp returns a list of names of any migrations needed in the
 // "up" direction (more recent schema versions).
 func GetMigrationsUp(dbUrl string) (migrationIds []string) {
@@ -23,6 +23,25 @@ func TestInit(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestIsEmpty(t *testing.T) {
+	tdb := dbtest.Postgres(t)
+	defer tdb.Close()
+	db := tdb.Open()
+
+	defer db.Close()
+
+	empty, err := IsEmpty(db.DB)
+	assert.NoError(t, err)
+	assert.True(t, empty)
+
+	_, err = Migrate(db.DB, MigrateUp, 0)
+	assert.NoError(t, err)
+
+	empty, err = IsEmpty(db.DB)
+	assert.NoError(t, err)
+	assert.False(t, empty)
+}
+
 func TestGeneratedAssets(t *testing.T) {
 	generatedAssets := &assetfs.AssetFS{Asset: Asset, AssetDir: AssetDir, AssetInfo: AssetInfo}
 	if !supportHttp.EqualFileSystems(http.Dir("."), generatedAssets, "migrations") {
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- Allows resolving a closed_at time range to a range of ledger sequences
+-- with an index only scan.
+CREATE INDEX index_history_ledgers_on_closed_at_and_sequence ON history_ledgers USING btree (closed_at, sequence);
+DROP INDEX index_history_ledgers_on_closed_at;
+
+-- +migrate Down
+
+CREATE INDEX index_history_ledgers_on_closed_at ON history_ledgers USING btree (closed_at);
+DROP INDEX index_history_ledgers_on_closed_at_and_sequence;
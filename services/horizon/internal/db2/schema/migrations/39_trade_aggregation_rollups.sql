@@ -0,0 +1,39 @@
+-- +migrate Up
+
+-- Pre-aggregated trade buckets for the sub-minute trade aggregation
+-- resolutions. Rows are kept in the canonical base/counter order of
+-- history_trades and are rebuilt during ingestion.
+CREATE TABLE history_trade_rollups (
+    resolution bigint NOT NULL,
+    "timestamp" bigint NOT NULL,
+    base_asset_id bigint NOT NULL REFERENCES history_assets(id),
+    counter_asset_id bigint NOT NULL REFERENCES history_assets(id),
+    count bigint NOT NULL,
+    base_volume numeric NOT NULL,
+    counter_volume numeric NOT NULL,
+    high numeric[] NOT NULL,
+    low numeric[] NOT NULL,
+    open numeric[] NOT NULL,
+    close numeric[] NOT NULL,
+    PRIMARY KEY (base_asset_id, counter_asset_id, resolution, "timestamp")
+);
+
+CREATE INDEX htrd_rollups_by_resolution_and_time ON history_trade_rollups USING btree (resolution, "timestamp");
+
+INSERT INTO history_trade_rollups
+SELECT resolution, "timestamp", base_asset_id, counter_asset_id, count(*),
+    sum(base_amount), sum(counter_amount),
+    max_price(price), min_price(price), first(price), last(price)
+FROM (
+    SELECT r.resolution,
+        div(cast((extract(epoch from ledger_closed_at) * 1000) as bigint), r.resolution) * r.resolution AS "timestamp",
+        history_operation_id, "order", base_asset_id, base_amount, counter_asset_id, counter_amount,
+        ARRAY[price_n, price_d] AS price
+    FROM history_trades, (VALUES (5000), (15000)) AS r(resolution)
+    ORDER BY history_operation_id, "order"
+) htrd
+GROUP BY resolution, base_asset_id, counter_asset_id, "timestamp";
+
+-- +migrate Down
+
+DROP TABLE history_trade_rollups;
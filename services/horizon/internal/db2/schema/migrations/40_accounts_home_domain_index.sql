@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- Allows paging through the accounts of a home domain with an index scan.
+CREATE INDEX accounts_home_domain_and_account_id ON accounts USING BTREE (home_domain, account_id);
+DROP INDEX accounts_home_domain;
+
+-- +migrate Down
+
+CREATE INDEX accounts_home_domain ON accounts USING BTREE (home_domain);
+DROP INDEX accounts_home_domain_and_account_id;
@@ -0,0 +1,231 @@
+-- +migrate Up
+
+-- Converts the history tables to tables partitioned by ranges of 100000
+-- ledgers on the toid of their rows, see db2/history/partitions.go. Horizon
+-- creates the partitions of new ledgers and the reaper drops the partitions of
+-- unretained ledgers instead of deleting their rows.
+--
+-- Partitioning requires postgres 11. On older servers the tables are left
+-- unchanged.
+--
+-- The existing rows become the first partition of every table (<table>_p0)
+-- which holds all the ledgers before the next multiple of 100000 after the
+-- latest ledger. A check constraint matching the bounds of the partition is
+-- added without validation and validated before attaching it, which scans the
+-- table once, so that attaching it does not scan it again.
+--
+-- The migration is not instant: renaming and attaching the tables takes
+-- ACCESS EXCLUSIVE locks on them, which are held until the migration commits,
+-- so ingestion and the history endpoints are blocked while the tables are
+-- scanned.
+--
+-- Unique indexes of partitioned tables must contain the partition key. The
+-- unique indexes of history_ledgers on sequence, ledger_hash and
+-- previous_ledger_hash are therefore extended with the id of the ledgers, and
+-- the history_ledgers_unique trigger checks that they stay unique across the
+-- partitions.
+
+-- +migrate StatementBegin
+CREATE FUNCTION horizon_partition_history_table(tbl text, partition_key text, upper_bound bigint) RETURNS void AS $$
+DECLARE
+    legacy text := tbl || '_p0';
+    key_attnum smallint;
+    index_names text[];
+    index_defs text[];
+    index_demoted boolean[];
+    bound_check text := left(tbl, 50) || '_p0_bound';
+    fk record;
+    fks text[] := '{}';
+    statement text;
+BEGIN
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', tbl, legacy);
+    EXECUTE format(
+        'CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING STORAGE) PARTITION BY RANGE (%I)',
+        tbl, legacy, partition_key
+    );
+
+    -- foreign keys are added back to the partitioned table once the rows are
+    -- attached to it
+    FOR fk IN
+        SELECT conname, pg_get_constraintdef(oid) AS def
+        FROM pg_constraint
+        WHERE conrelid = legacy::regclass AND contype = 'f'
+    LOOP
+        EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', legacy, fk.conname);
+        fks := fks || format('ALTER TABLE %I ADD CONSTRAINT %I %s', tbl, fk.conname, fk.def);
+    END LOOP;
+
+    EXECUTE format(
+        'ALTER TABLE %I ADD CONSTRAINT %I CHECK (%I IS NOT NULL AND %I < %s) NOT VALID',
+        legacy, bound_check, partition_key, partition_key, upper_bound
+    );
+    EXECUTE format('ALTER TABLE %I VALIDATE CONSTRAINT %I', legacy, bound_check);
+    EXECUTE format(
+        'ALTER TABLE %I ATTACH PARTITION %I FOR VALUES FROM (MINVALUE) TO (%s)',
+        tbl, legacy, upper_bound
+    );
+    EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', legacy, bound_check);
+
+    FOREACH statement IN ARRAY fks LOOP
+        EXECUTE statement;
+    END LOOP;
+
+    -- the indexes of the partitioned table keep their names, the ones of the
+    -- first partition are renamed and attached to them
+    SELECT attnum INTO key_attnum
+    FROM pg_attribute
+    WHERE attrelid = legacy::regclass AND attname = partition_key;
+
+    -- the indexes are loaded upfront since the loop creates new ones
+    SELECT
+        array_agg(c.relname::text),
+        array_agg(pg_get_indexdef(i.indexrelid)),
+        array_agg(i.indisunique AND NOT (key_attnum = ANY (i.indkey::smallint[])))
+    INTO index_names, index_defs, index_demoted
+    FROM pg_index i
+    JOIN pg_class c ON c.oid = i.indexrelid
+    WHERE i.indrelid = legacy::regclass;
+
+    FOR n IN 1 .. coalesce(array_length(index_names, 1), 0) LOOP
+        EXECUTE format('ALTER INDEX %I RENAME TO %I', index_names[n], left(index_names[n], 60) || '_p0');
+
+        IF index_demoted[n] THEN
+            -- unique indexes of partitioned tables must contain the partition
+            -- key, so the index of the partitioned table is unique on its
+            -- columns and the partition key. The same index is built on the
+            -- first partition and attached to it, so the index of the
+            -- partitioned table is valid. The unique index of the first
+            -- partition is kept as well. The original definition is kept in a
+            -- comment for the down migration.
+            EXECUTE regexp_replace(
+                index_defs[n],
+                ' ON \S+ USING (\w+) \((.*)\)$',
+                format(' ON ONLY %I USING \1 (\2, %I)', tbl, partition_key)
+            );
+            EXECUTE regexp_replace(
+                regexp_replace(index_defs[n], '^(CREATE UNIQUE INDEX) \S+', format('\1 %I', left(index_names[n], 56) || '_p0_key')),
+                ' ON \S+ USING (\w+) \((.*)\)$',
+                format(' ON %I USING \1 (\2, %I)', legacy, partition_key)
+            );
+            EXECUTE format('ALTER INDEX %I ATTACH PARTITION %I', index_names[n], left(index_names[n], 56) || '_p0_key');
+            EXECUTE format('COMMENT ON INDEX %I IS %L', index_names[n], 'unique before partitioning: ' || index_defs[n]);
+        ELSE
+            EXECUTE regexp_replace(index_defs[n], ' ON \S+ USING ', format(' ON ONLY %I USING ', tbl));
+            EXECUTE format('ALTER INDEX %I ATTACH PARTITION %I', index_names[n], left(index_names[n], 60) || '_p0');
+        END IF;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+DO $$
+DECLARE
+    upper_bound bigint;
+BEGIN
+    IF current_setting('server_version_num')::int < 110000 THEN
+        RETURN;
+    END IF;
+
+    SELECT ((COALESCE(MAX(sequence), 0) / 100000) + 1) * 100000 INTO upper_bound FROM history_ledgers;
+    upper_bound := upper_bound << 32;
+
+    PERFORM horizon_partition_history_table('history_ledgers', 'id', upper_bound);
+    PERFORM horizon_partition_history_table('history_transactions', 'id', upper_bound);
+    PERFORM horizon_partition_history_table('history_operations', 'id', upper_bound);
+    PERFORM horizon_partition_history_table('history_effects', 'history_operation_id', upper_bound);
+    PERFORM horizon_partition_history_table('history_trades', 'history_operation_id', upper_bound);
+
+    -- the unique indexes of history_ledgers now contain its id, see above
+    CREATE FUNCTION history_ledgers_check_unique() RETURNS trigger AS $f$
+    BEGIN
+        IF EXISTS (
+            SELECT 1 FROM history_ledgers
+            WHERE id <> NEW.id AND (
+                sequence = NEW.sequence OR
+                ledger_hash = NEW.ledger_hash OR
+                previous_ledger_hash = NEW.previous_ledger_hash
+            )
+        ) THEN
+            RAISE unique_violation USING MESSAGE = format('duplicate ledger %s in history_ledgers', NEW.sequence);
+        END IF;
+        RETURN NULL;
+    END;
+    $f$ LANGUAGE plpgsql;
+
+    CREATE TRIGGER history_ledgers_unique
+        AFTER INSERT OR UPDATE OF sequence, ledger_hash, previous_ledger_hash ON history_ledgers
+        FOR EACH ROW EXECUTE PROCEDURE history_ledgers_check_unique();
+END;
+$$;
+-- +migrate StatementEnd
+
+DROP FUNCTION horizon_partition_history_table(text, text, bigint);
+
+-- +migrate Down
+
+-- +migrate StatementBegin
+CREATE FUNCTION horizon_unpartition_history_table(tbl text) RETURNS void AS $$
+DECLARE
+    plain text := tbl || '_plain';
+    idx record;
+    fk record;
+    statements text[] := '{}';
+    statement text;
+BEGIN
+    FOR idx IN
+        SELECT
+            c.relname AS name,
+            CASE
+                WHEN obj_description(c.oid, 'pg_class') LIKE 'unique before partitioning: %'
+                THEN substr(obj_description(c.oid, 'pg_class'), length('unique before partitioning: ') + 1)
+                ELSE pg_get_indexdef(i.indexrelid)
+            END AS def
+        FROM pg_index i
+        JOIN pg_class c ON c.oid = i.indexrelid
+        WHERE i.indrelid = tbl::regclass
+    LOOP
+        statements := statements || regexp_replace(idx.def, ' ON (ONLY )?\S+ USING ', format(' ON %I USING ', tbl));
+    END LOOP;
+
+    FOR fk IN
+        SELECT conname, pg_get_constraintdef(oid) AS def
+        FROM pg_constraint
+        WHERE conrelid = tbl::regclass AND contype = 'f'
+    LOOP
+        statements := statements || format('ALTER TABLE %I ADD CONSTRAINT %I %s', tbl, fk.conname, fk.def);
+    END LOOP;
+
+    EXECUTE format(
+        'CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING STORAGE)',
+        plain, tbl
+    );
+    EXECUTE format('INSERT INTO %I SELECT * FROM %I', plain, tbl);
+    EXECUTE format('DROP TABLE %I', tbl);
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', plain, tbl);
+
+    FOREACH statement IN ARRAY statements LOOP
+        EXECUTE statement;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_class WHERE oid = 'history_ledgers'::regclass AND relkind = 'p') THEN
+        RETURN;
+    END IF;
+
+    PERFORM horizon_unpartition_history_table('history_ledgers');
+    PERFORM horizon_unpartition_history_table('history_transactions');
+    PERFORM horizon_unpartition_history_table('history_operations');
+    PERFORM horizon_unpartition_history_table('history_effects');
+    PERFORM horizon_unpartition_history_table('history_trades');
+END;
+$$;
+-- +migrate StatementEnd
+
+DROP FUNCTION horizon_unpartition_history_table(text);
+DROP FUNCTION IF EXISTS history_ledgers_check_unique();
@@ -0,0 +1,13 @@
+-- +migrate Up
+
+-- horizon_backfills tracks the progress of the online data migrations run by
+-- `horizon db migrate up`, see db2/schema/backfill.go.
+CREATE TABLE horizon_backfills (
+    id character varying(128) NOT NULL PRIMARY KEY,
+    next_key bigint NOT NULL,
+    completed_at timestamp without time zone
+);
+
+-- +migrate Down
+
+DROP TABLE horizon_backfills;
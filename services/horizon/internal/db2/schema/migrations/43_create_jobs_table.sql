@@ -0,0 +1,23 @@
+-- +migrate Up
+
+-- horizon_jobs holds the long-running admin operations run in the background
+-- by the horizon instances sharing the database, see internal/jobs.
+CREATE TABLE horizon_jobs (
+    id bigserial NOT NULL PRIMARY KEY,
+    type character varying(64) NOT NULL,
+    params jsonb NOT NULL,
+    status character varying(16) NOT NULL,
+    progress text NOT NULL DEFAULT '',
+    error text,
+    cancel_requested boolean NOT NULL DEFAULT false,
+    created_at timestamp without time zone NOT NULL,
+    started_at timestamp without time zone,
+    heartbeat_at timestamp without time zone,
+    finished_at timestamp without time zone
+);
+
+CREATE INDEX horizon_jobs_by_status ON horizon_jobs USING btree (status, id);
+
+-- +migrate Down
+
+DROP TABLE horizon_jobs;
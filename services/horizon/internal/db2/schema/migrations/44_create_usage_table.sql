@@ -0,0 +1,19 @@
+-- +migrate Up
+
+-- horizon_usage holds the requests served by the horizon instances sharing
+-- the database, aggregated per hour and per tenant (API key or remote ip
+-- address), see internal/usage.
+CREATE TABLE horizon_usage (
+    period_start timestamp without time zone NOT NULL,
+    tenant character varying(256) NOT NULL,
+    requests bigint NOT NULL,
+    bytes bigint NOT NULL,
+    rate_limited bigint NOT NULL,
+    -- endpoints maps the route patterns to their number of requests
+    endpoints jsonb NOT NULL,
+    PRIMARY KEY (period_start, tenant)
+);
+
+-- +migrate Down
+
+DROP TABLE horizon_usage;
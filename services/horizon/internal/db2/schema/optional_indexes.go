@@ -0,0 +1,244 @@
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/lib/pq"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// OptionalIndex is an index which is not created by the schema migrations
+// because it only speeds up query patterns which Horizon itself does not run,
+// but which are common in queries run by operators directly against the
+// database. Every index costs disk space and slows ingestion down so operators
+// only enable the ones they need, see IndexReport.
+type OptionalIndex struct {
+	// Name is the name of the optional index, used to enable it.
+	Name string
+	// Index is the name of the index in the database.
+	Index string
+	// Table is the indexed table.
+	Table string
+	// Definition is the part of the CREATE INDEX statement following the
+	// table name.
+	Definition string
+	// Description describes the query pattern sped up by the index.
+	Description string
+	// Pattern matches the normalized statements of pg_stat_statements
+	// which would use the index.
+	Pattern *regexp.Regexp
+}
+
+// OptionalIndexes are the optional indexes which can be enabled with
+// `horizon db migrate up --optional-indexes`.
+var OptionalIndexes = []OptionalIndex{
+	{
+		Name:        "transaction_memos",
+		Index:       "htx_by_memo",
+		Table:       "history_transactions",
+		Definition:  "USING btree (memo_type, memo) WHERE memo IS NOT NULL",
+		Description: "transactions by memo, used to match payments to deposits",
+		Pattern:     regexp.MustCompile(`(?is)history_transactions.*\bmemo\s*(=|in\b)`),
+	},
+	{
+		Name:        "operation_assets",
+		Index:       "hop_by_asset",
+		Table:       "history_operations",
+		Definition:  "USING btree ((details->>'asset_code'), (details->>'asset_issuer'), id)",
+		Description: "operations by asset, for example the payments of an asset",
+		Pattern:     regexp.MustCompile(`(?is)history_operations.*details\s*->>\s*'asset_(code|issuer)'`),
+	},
+	{
+		Name:        "transaction_times",
+		Index:       "htx_by_created_at",
+		Table:       "history_transactions",
+		Definition:  "USING btree (created_at)",
+		Description: "transactions by time range",
+		Pattern:     regexp.MustCompile(`(?is)history_transactions.*\bcreated_at\s*(<|>|between\b)`),
+	},
+}
+
+// FindOptionalIndex returns the optional index called `name`.
+func FindOptionalIndex(name string) (OptionalIndex, bool) {
+	for _, index := range OptionalIndexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+	return OptionalIndex{}, false
+}
+
+// CreateOptionalIndexes creates the optional `indexes` which do not exist yet.
+// Indexes of regular tables are built concurrently so they do not block
+// ingestion. Postgres cannot build indexes of partitioned tables concurrently
+// so writes to those tables are blocked while their indexes are built. It
+// returns the number of indexes created.
+func CreateOptionalIndexes(db *sql.DB, indexes []OptionalIndex) (int, error) {
+	created := 0
+	for _, index := range indexes {
+		exists, err := indexExists(db, index.Index)
+		if err != nil {
+			return created, errors.Wrapf(err, "could not check index %s", index.Index)
+		}
+		if exists {
+			continue
+		}
+
+		var partitioned bool
+		err = db.QueryRow(
+			"SELECT relkind = 'p' FROM pg_class WHERE oid = to_regclass($1)",
+			index.Table,
+		).Scan(&partitioned)
+		if err != nil {
+			return created, errors.Wrapf(err, "could not load table %s", index.Table)
+		}
+
+		create := "CREATE INDEX CONCURRENTLY"
+		if partitioned {
+			create = "CREATE INDEX"
+		}
+		log.WithField("index", index.Index).Info("Creating optional index")
+		// An interrupted concurrent build leaves an invalid index behind
+		// which must be dropped before retrying.
+		_, err = db.Exec("DROP INDEX IF EXISTS " + pq.QuoteIdentifier(index.Index))
+		if err != nil {
+			return created, errors.Wrapf(err, "could not drop invalid index %s", index.Index)
+		}
+		_, err = db.Exec(fmt.Sprintf(
+			"%s %s ON %s %s",
+			create,
+			pq.QuoteIdentifier(index.Index),
+			pq.QuoteIdentifier(index.Table),
+			index.Definition,
+		))
+		if err != nil {
+			return created, errors.Wrapf(err, "could not create index %s", index.Index)
+		}
+		created++
+	}
+	return created, nil
+}
+
+// DropOptionalIndexes drops the optional `indexes` which exist. It returns the
+// number of indexes dropped.
+func DropOptionalIndexes(db *sql.DB, indexes []OptionalIndex) (int, error) {
+	dropped := 0
+	for _, index := range indexes {
+		exists, err := indexExists(db, index.Index)
+		if err != nil {
+			return dropped, errors.Wrapf(err, "could not check index %s", index.Index)
+		}
+		if !exists {
+			continue
+		}
+
+		_, err = db.Exec("DROP INDEX " + pq.QuoteIdentifier(index.Index))
+		if err != nil {
+			return dropped, errors.Wrapf(err, "could not drop index %s", index.Index)
+		}
+		dropped++
+	}
+	return dropped, nil
+}
+
+// indexExists returns true when the index `name` exists and is valid.
+func indexExists(db *sql.DB, name string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_index WHERE indexrelid = to_regclass($1) AND indisvalid)",
+		name,
+	).Scan(&exists)
+	return exists, err
+}
+
+// IndexAdvice reports how much the statements recorded by pg_stat_statements
+// could benefit from an optional index.
+type IndexAdvice struct {
+	Index   OptionalIndex
+	Enabled bool
+	// Statements is the number of distinct statements matching the index.
+	Statements int
+	// Calls is the number of times the matching statements were run.
+	Calls int64
+	// TotalTimeMs is the total time spent running the matching statements.
+	TotalTimeMs float64
+}
+
+// Recommended returns true when the index is disabled while matching
+// statements were run.
+func (a IndexAdvice) Recommended() bool {
+	return !a.Enabled && a.Calls > 0
+}
+
+// IndexReport compares the statements recorded by the pg_stat_statements
+// extension with the optional indexes. The extension must be installed in the
+// database.
+func IndexReport(db *sql.DB) ([]IndexAdvice, error) {
+	var installed bool
+	err := db.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')",
+	).Scan(&installed)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load extensions")
+	}
+	if !installed {
+		return nil, errors.New(
+			"the pg_stat_statements extension is not installed, " +
+				"add it to shared_preload_libraries and run CREATE EXTENSION pg_stat_statements",
+		)
+	}
+
+	// total_time was renamed total_exec_time in Postgres 13.
+	var version int
+	if err = db.QueryRow("SHOW server_version_num").Scan(&version); err != nil {
+		return nil, errors.Wrap(err, "could not load server version")
+	}
+	totalTime := "total_time"
+	if version >= 130000 {
+		totalTime = "total_exec_time"
+	}
+
+	report := make([]IndexAdvice, len(OptionalIndexes))
+	for i, index := range OptionalIndexes {
+		report[i].Index = index
+		report[i].Enabled, err = indexExists(db, index.Index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not check index %s", index.Index)
+		}
+	}
+
+	rows, err := db.Query(fmt.Sprintf(
+		"SELECT query, calls, %s FROM pg_stat_statements WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())",
+		totalTime,
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load statements")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var query string
+		var calls int64
+		var totalTimeMs float64
+		if err = rows.Scan(&query, &calls, &totalTimeMs); err != nil {
+			return nil, errors.Wrap(err, "could not scan statement")
+		}
+		for i := range report {
+			report[i].add(query, calls, totalTimeMs)
+		}
+	}
+	return report, errors.Wrap(rows.Err(), "could not load statements")
+}
+
+// add accounts for the statement `query` if it matches the index.
+func (a *IndexAdvice) add(query string, calls int64, totalTimeMs float64) {
+	if !a.Index.Pattern.MatchString(query) {
+		return
+	}
+	a.Statements++
+	a.Calls += calls
+	a.TotalTimeMs += totalTimeMs
+}
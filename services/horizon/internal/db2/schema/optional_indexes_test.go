@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/support/db/dbtest"
+)
+
+func TestIndexAdviceAdd(t *testing.T) {
+	memos, ok := FindOptionalIndex("transaction_memos")
+	assert.True(t, ok)
+	times, ok := FindOptionalIndex("transaction_times")
+	assert.True(t, ok)
+	assets, ok := FindOptionalIndex("operation_assets")
+	assert.True(t, ok)
+	_, ok = FindOptionalIndex("unknown")
+	assert.False(t, ok)
+
+	statements := []struct {
+		query string
+		calls int64
+	}{
+		{"SELECT * FROM history_transactions WHERE memo = $1", 3},
+		{"SELECT memo_type, memo FROM history_transactions WHERE id = $1", 5},
+		{"SELECT count(*) FROM history_transactions WHERE created_at BETWEEN $1 AND $2", 7},
+		{"SELECT * FROM history_operations WHERE details->>'asset_code' = $1", 11},
+	}
+
+	advices := []*IndexAdvice{{Index: memos}, {Index: times}, {Index: assets, Enabled: true}}
+	for _, statement := range statements {
+		for _, advice := range advices {
+			advice.add(statement.query, statement.calls, 2)
+		}
+	}
+
+	assert.Equal(t, 1, advices[0].Statements)
+	assert.Equal(t, int64(3), advices[0].Calls)
+	assert.Equal(t, float64(2), advices[0].TotalTimeMs)
+	assert.True(t, advices[0].Recommended())
+	assert.Equal(t, int64(7), advices[1].Calls)
+	assert.Equal(t, int64(11), advices[2].Calls)
+	assert.False(t, advices[2].Recommended())
+}
+
+func TestCreateAndDropOptionalIndexes(t *testing.T) {
+	tdb := dbtest.Postgres(t)
+	defer tdb.Close()
+	db := tdb.Open()
+	defer db.Close()
+
+	_, err := Migrate(db.DB, MigrateUp, 0)
+	assert.NoError(t, err)
+
+	created, err := CreateOptionalIndexes(db.DB, OptionalIndexes)
+	assert.NoError(t, err)
+	assert.Equal(t, len(OptionalIndexes), created)
+
+	for _, index := range OptionalIndexes {
+		exists, err := indexExists(db.DB, index.Index)
+		assert.NoError(t, err)
+		assert.True(t, exists, index.Index)
+	}
+
+	// Existing indexes are not created again.
+	created, err = CreateOptionalIndexes(db.DB, OptionalIndexes)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+
+	dropped, err := DropOptionalIndexes(db.DB, OptionalIndexes[:1])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+
+	exists, err := indexExists(db.DB, OptionalIndexes[0].Index)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stellar/go/exp/ingest/io"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/toid"
 	"github.com/stellar/go/support/errors"
 	logpkg "github.com/stellar/go/support/log"
@@ -312,6 +313,12 @@ func (r resumeState) run(s *System) (transition, error) {
 		return start(), errors.New("unexpected latestSuccessfullyProcessedLedger value")
 	}
 
+	// Partitions are created ahead so that it is rarely done between ledgers.
+	nextLedger := r.latestSuccessfullyProcessedLedger + 1
+	if err := s.ensureHistoryPartitions(nextLedger, nextLedger+history.HistoryPartitionSize); err != nil {
+		return retryResume(r), err
+	}
+
 	if err := s.historyQ.Begin(); err != nil {
 		return retryResume(r),
 			errors.Wrap(err, "Error starting a transaction")
@@ -442,6 +449,10 @@ func (h historyRangeState) run(s *System) (transition, error) {
 		return start(), errors.Errorf("invalid range: [%d, %d]", h.fromLedger, h.toLedger)
 	}
 
+	if err := s.ensureHistoryPartitions(h.fromLedger, h.toLedger); err != nil {
+		return start(), err
+	}
+
 	if err := s.historyQ.Begin(); err != nil {
 		return start(), errors.Wrap(err, "Error starting a transaction")
 	}
@@ -466,7 +477,7 @@ func (h historyRangeState) run(s *System) (transition, error) {
 	}
 
 	for cur := h.fromLedger; cur <= h.toLedger; cur++ {
-		if err = runTransactionProcessorsOnLedger(s, cur); err != nil {
+		if _, err = runTransactionProcessorsOnLedger(s, cur); err != nil {
 			return start(), err
 		}
 	}
@@ -478,7 +489,7 @@ func (h historyRangeState) run(s *System) (transition, error) {
 	return start(), nil
 }
 
-func runTransactionProcessorsOnLedger(s *System, ledger uint32) error {
+func runTransactionProcessorsOnLedger(s *System, ledger uint32) (io.StatsLedgerTransactionProcessorResults, error) {
 	log.WithFields(logpkg.F{
 		"sequence": ledger,
 		"state":    false,
@@ -489,7 +500,7 @@ func runTransactionProcessorsOnLedger(s *System, ledger uint32) error {
 
 	ledgerTransactionStats, err := s.runner.RunTransactionProcessorsOnLedger(ledger)
 	if err != nil {
-		return errors.Wrap(err, fmt.Sprintf("error processing ledger sequence=%d", ledger))
+		return ledgerTransactionStats, errors.Wrap(err, fmt.Sprintf("error processing ledger sequence=%d", ledger))
 	}
 
 	log.
@@ -502,7 +513,7 @@ func runTransactionProcessorsOnLedger(s *System, ledger uint32) error {
 			"commit":   false,
 		}).
 		Info("Processed ledger")
-	return nil
+	return ledgerTransactionStats, nil
 }
 
 type reingestHistoryRangeState struct {
@@ -540,7 +551,7 @@ func (h reingestHistoryRangeState) ingestRange(s *System, fromLedger, toLedger u
 	}
 
 	for cur := fromLedger; cur <= toLedger; cur++ {
-		if err = runTransactionProcessorsOnLedger(s, cur); err != nil {
+		if _, err = runTransactionProcessorsOnLedger(s, cur); err != nil {
 			return err
 		}
 	}
@@ -572,6 +583,10 @@ func (h reingestHistoryRangeState) run(s *System) (transition, error) {
 		"duration": time.Since(startTime).Seconds(),
 	}).Info("Range ready")
 
+	if err := s.ensureHistoryPartitions(h.fromLedger, h.toLedger); err != nil {
+		return stop(), err
+	}
+
 	if h.force {
 		if err := s.historyQ.Begin(); err != nil {
 			return stop(), errors.Wrap(err, "Error starting a transaction")
@@ -628,6 +643,89 @@ func (h reingestHistoryRangeState) run(s *System) (transition, error) {
 	return stop(), nil
 }
 
+type dryRunRangeState struct {
+	fromLedger uint32
+	toLedger   uint32
+	report     *DryRunReport
+}
+
+func (d dryRunRangeState) String() string {
+	return fmt.Sprintf(
+		"dryRunRange(fromLedger=%d, toLedger=%d)",
+		d.fromLedger,
+		d.toLedger,
+	)
+}
+
+// dryRunRangeState runs the history processors on a range of ledgers like
+// reingestHistoryRangeState with force, in a transaction which is rolled back
+// instead of committed. It does not acquire the ingestion lock so it can run
+// against the database of a live instance.
+func (d dryRunRangeState) run(s *System) (transition, error) {
+	if d.fromLedger == 0 || d.toLedger == 0 ||
+		d.fromLedger > d.toLedger {
+		return stop(), errors.Errorf("invalid range: [%d, %d]", d.fromLedger, d.toLedger)
+	}
+
+	err := s.ledgerBackend.PrepareRange(d.fromLedger, d.toLedger)
+	if err != nil {
+		return stop(), errors.Wrap(err, "error preparing range")
+	}
+
+	// partitions are created outside of the transaction, they are empty
+	// when the range was not ingested
+	if err = s.ensureHistoryPartitions(d.fromLedger, d.toLedger); err != nil {
+		return stop(), err
+	}
+
+	if err = s.historyQ.Begin(); err != nil {
+		return stop(), errors.Wrap(err, "Error starting a transaction")
+	}
+	// discards all the writes of the dry run
+	defer s.historyQ.Rollback()
+
+	start, end, err := toid.LedgerRangeInclusive(
+		int32(d.fromLedger),
+		int32(d.toLedger),
+	)
+	if err != nil {
+		return stop(), errors.Wrap(err, "Invalid range")
+	}
+
+	// the rows of the range already ingested are deleted so that they can be
+	// inserted again
+	startTime := time.Now()
+	if err = s.historyQ.DeleteRangeAll(start, end); err != nil {
+		return stop(), errors.Wrap(err, "error in DeleteRangeAll")
+	}
+	d.report.DeleteDuration = time.Since(startTime)
+
+	startTime = time.Now()
+	for cur := d.fromLedger; cur <= d.toLedger; cur++ {
+		stats, err := runTransactionProcessorsOnLedger(s, cur)
+		if err != nil {
+			return stop(), err
+		}
+		d.report.Ledgers++
+		d.report.Transactions += stats.Transactions
+		d.report.Operations += stats.Operations
+	}
+	d.report.Duration = time.Since(startTime)
+
+	d.report.InsertedRows, err = s.historyQ.GetInsertedRowCounts()
+	if err != nil {
+		return stop(), err
+	}
+	d.report.ProcessorDurations = map[string]time.Duration{}
+	for name, timer := range s.Metrics.ProcessorsRunDuration {
+		if timer.Count() > 0 {
+			d.report.ProcessorDurations[name] = time.Duration(timer.Mean())
+		}
+	}
+
+	return stop(), nil
+}
+
 type waitForCheckpointState struct{}
 
 func (waitForCheckpointState) String() string {
@@ -644,14 +742,18 @@ type verifyRangeState struct {
 	fromLedger  uint32
 	toLedger    uint32
 	verifyState bool
+	// resume continues an interrupted verification of the range from the
+	// last ledger ingested in the database.
+	resume bool
 }
 
 func (v verifyRangeState) String() string {
 	return fmt.Sprintf(
-		"verifyRange(fromLedger=%d, toLedger=%d, verifyState=%t)",
+		"verifyRange(fromLedger=%d, toLedger=%d, verifyState=%t, resume=%t)",
 		v.fromLedger,
 		v.toLedger,
 		v.verifyState,
+		v.resume,
 	)
 }
 
@@ -661,6 +763,10 @@ func (v verifyRangeState) run(s *System) (transition, error) {
 		return stop(), errors.Errorf("invalid range: [%d, %d]", v.fromLedger, v.toLedger)
 	}
 
+	if err := s.ensureHistoryPartitions(v.fromLedger, v.toLedger); err != nil {
+		return stop(), err
+	}
+
 	if err := s.historyQ.Begin(); err != nil {
 		err = errors.Wrap(err, "Error starting a transaction")
 		return stop(), err
@@ -674,35 +780,56 @@ func (v verifyRangeState) run(s *System) (transition, error) {
 		return stop(), err
 	}
 
-	if lastIngestedLedger != 0 {
+	// Every ledger is committed with the last ingested ledger so an
+	// interrupted verification can be resumed from it.
+	if lastIngestedLedger != 0 && !v.resume {
 		err = errors.New("Database not empty")
 		return stop(), err
 	}
 
-	log.WithFields(logpkg.F{
-		"ledger": v.fromLedger,
-	}).Info("Processing state")
-	startTime := time.Now()
+	if lastIngestedLedger != 0 {
+		if lastIngestedLedger < v.fromLedger || lastIngestedLedger > v.toLedger {
+			err = errors.Errorf(
+				"cannot resume verification, last ingested ledger %d is outside of the range",
+				lastIngestedLedger,
+			)
+			return stop(), err
+		}
 
-	stats, err := s.runner.RunHistoryArchiveIngestion(v.fromLedger)
-	if err != nil {
-		err = errors.Wrap(err, "Error ingesting history archive")
-		return stop(), err
-	}
+		if err = s.historyQ.Rollback(); err != nil {
+			return stop(), errors.Wrap(err, "Error rolling back transaction")
+		}
 
-	if err = s.completeIngestion(v.fromLedger); err != nil {
-		return stop(), err
-	}
+		log.WithFields(logpkg.F{
+			"ledger": lastIngestedLedger,
+		}).Info("Resuming verification")
+	} else {
+		log.WithFields(logpkg.F{
+			"ledger": v.fromLedger,
+		}).Info("Processing state")
+		startTime := time.Now()
 
-	log.
-		WithFields(stats.Map()).
-		WithFields(logpkg.F{
-			"ledger":   v.fromLedger,
-			"duration": time.Since(startTime).Seconds(),
-		}).
-		Info("Processed state")
+		stats, err := s.runner.RunHistoryArchiveIngestion(v.fromLedger)
+		if err != nil {
+			err = errors.Wrap(err, "Error ingesting history archive")
+			return stop(), err
+		}
+
+		if err = s.completeIngestion(v.fromLedger); err != nil {
+			return stop(), err
+		}
+
+		log.
+			WithFields(stats.Map()).
+			WithFields(logpkg.F{
+				"ledger":   v.fromLedger,
+				"duration": time.Since(startTime).Seconds(),
+			}).
+			Info("Processed state")
+		lastIngestedLedger = v.fromLedger
+	}
 
-	for sequence := v.fromLedger + 1; sequence <= v.toLedger; sequence++ {
+	for sequence := lastIngestedLedger + 1; sequence <= v.toLedger; sequence++ {
 		log.WithFields(logpkg.F{
 			"sequence": sequence,
 			"state":    true,
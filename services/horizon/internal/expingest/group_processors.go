@@ -1,8 +1,15 @@
 package expingest
 
 import (
+	"context"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
 	"github.com/stellar/go/exp/ingest/io"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type horizonChangeProcessor interface {
@@ -31,6 +38,32 @@ func (g groupChangeProcessors) Commit() error {
 	return nil
 }
 
+// timedChangeProcessor measures the time spent in the wrapped processor
+// while processing a ledger and reports it to `timer` on Commit. When `ctx` is
+// traced, the commit is recorded as a span named after the processor.
+type timedChangeProcessor struct {
+	horizonChangeProcessor
+	timer   metrics.Timer
+	elapsed time.Duration
+	ctx     context.Context
+	name    string
+}
+
+func (p *timedChangeProcessor) ProcessChange(change io.Change) error {
+	start := time.Now()
+	err := p.horizonChangeProcessor.ProcessChange(change)
+	p.elapsed += time.Since(start)
+	return err
+}
+
+func (p *timedChangeProcessor) Commit() error {
+	start := time.Now()
+	err := p.horizonChangeProcessor.Commit()
+	p.timer.Update(p.elapsed + time.Since(start))
+	traceCommit(p.ctx, p.name, start, p.elapsed, err)
+	return err
+}
+
 type groupTransactionProcessors []horizonTransactionProcessor
 
 func (g groupTransactionProcessors) ProcessTransaction(tx io.LedgerTransaction) error {
@@ -50,3 +83,39 @@ func (g groupTransactionProcessors) Commit() error {
 	}
 	return nil
 }
+
+// timedTransactionProcessor measures the time spent in the wrapped processor
+// while processing a ledger and reports it to `timer` on Commit. When `ctx` is
+// traced, the commit is recorded as a span named after the processor.
+type timedTransactionProcessor struct {
+	horizonTransactionProcessor
+	timer   metrics.Timer
+	elapsed time.Duration
+	ctx     context.Context
+	name    string
+}
+
+func (p *timedTransactionProcessor) ProcessTransaction(tx io.LedgerTransaction) error {
+	start := time.Now()
+	err := p.horizonTransactionProcessor.ProcessTransaction(tx)
+	p.elapsed += time.Since(start)
+	return err
+}
+
+func (p *timedTransactionProcessor) Commit() error {
+	start := time.Now()
+	err := p.horizonTransactionProcessor.Commit()
+	p.timer.Update(p.elapsed + time.Since(start))
+	traceCommit(p.ctx, p.name, start, p.elapsed, err)
+	return err
+}
+
+// traceCommit records the span of the commit of processor `name`, which
+// started at `start`. The changes are processed one at a time in between the
+// other processors so the time spent processing them is an attribute.
+func traceCommit(ctx context.Context, name string, start time.Time, elapsed time.Duration, err error) {
+	span := tracing.StartChildAt(ctx, "processor "+name, trace.SpanKindInternal, start)
+	span.SetAttributes(attribute.Float64("processing_ms", elapsed.Seconds()*1000))
+	tracing.SetError(span, err)
+	span.End()
+}
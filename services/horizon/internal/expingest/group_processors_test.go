@@ -4,7 +4,9 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/rcrowley/go-metrics"
 	"github.com/stellar/go/exp/ingest/io"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -182,3 +184,20 @@ func (s *GroupTransactionProcessorsTestSuiteLedger) TestCommitSucceeds() {
 	err := s.processors.Commit()
 	s.Assert().NoError(err)
 }
+
+func TestTimedTransactionProcessor(t *testing.T) {
+	transaction := io.LedgerTransaction{}
+	processor := &mockHorizonTransactionProcessor{}
+	defer processor.AssertExpectations(t)
+	processor.On("ProcessTransaction", transaction).Return(nil).Twice()
+	processor.On("Commit").Return(nil).Once()
+
+	timer := metrics.NewTimer()
+	timed := &timedTransactionProcessor{horizonTransactionProcessor: processor, timer: timer}
+	assert.NoError(t, timed.ProcessTransaction(transaction))
+	assert.NoError(t, timed.ProcessTransaction(transaction))
+	assert.Equal(t, int64(0), timer.Count())
+
+	assert.NoError(t, timed.Commit())
+	assert.Equal(t, int64(1), timer.Count())
+}
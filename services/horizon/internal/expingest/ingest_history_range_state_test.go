@@ -366,6 +366,33 @@ func (s *ReingestHistoryRangeStateTestSuite) TestSuccessOneLedger() {
 	s.Assert().NoError(err)
 }
 
+func (s *ReingestHistoryRangeStateTestSuite) TestReingestWhileLeaderHoldsLock() {
+	// another instance is the ingestion leader: reingestion must not wait for
+	// the leadership
+	elector := &mockLeaderElector{}
+	elector.On("tryAcquire", s.system.ctx).Return(false, nil)
+	s.system.leaderElection = elector
+
+	s.historyQ.On("GetLastLedgerExpIngestNonBlocking").Return(uint32(0), nil).Once()
+	s.historyQ.On("GetTx").Return(&sqlx.Tx{}).Once()
+
+	toidFrom := toid.New(100, 0, 0)
+	toidTo := toid.New(101, 0, 0)
+	s.historyQ.On(
+		"DeleteRangeAll", toidFrom.ToInt64(), toidTo.ToInt64(),
+	).Return(nil).Once()
+
+	s.runner.On("RunTransactionProcessorsOnLedger", uint32(100)).Return(io.StatsLedgerTransactionProcessorResults{}, nil).Once()
+	s.historyQ.On("Commit").Return(nil).Once()
+
+	*s.ledgerBackend = mockLedgerBackend{}
+	s.ledgerBackend.On("PrepareRange", uint32(100), uint32(100)).Return(nil).Once()
+
+	err := s.system.ReingestRange(100, 100, false)
+	s.Assert().NoError(err)
+	elector.AssertNotCalled(s.T(), "tryAcquire", s.system.ctx)
+}
+
 func (s *ReingestHistoryRangeStateTestSuite) TestGetLastLedgerExpIngestError() {
 	s.historyQ.On("GetLastLedgerExpIngest").Return(uint32(0), errors.New("my error")).Once()
 
@@ -393,3 +420,81 @@ func (s *ReingestHistoryRangeStateTestSuite) TestReingestRangeForce() {
 	err := s.system.ReingestRange(100, 200, true)
 	s.Assert().NoError(err)
 }
+
+func TestDryRunRangeStateTestSuite(t *testing.T) {
+	suite.Run(t, new(DryRunRangeStateTestSuite))
+}
+
+type DryRunRangeStateTestSuite struct {
+	suite.Suite
+	historyQ      *mockDBQ
+	ledgerBackend *mockLedgerBackend
+	runner        *mockProcessorsRunner
+	system        *System
+}
+
+func (s *DryRunRangeStateTestSuite) SetupTest() {
+	s.historyQ = &mockDBQ{}
+	s.ledgerBackend = &mockLedgerBackend{}
+	s.runner = &mockProcessorsRunner{}
+	s.system = &System{
+		ctx:           context.Background(),
+		historyQ:      s.historyQ,
+		ledgerBackend: s.ledgerBackend,
+		runner:        s.runner,
+	}
+
+	s.historyQ.On("GetTx").Return(nil).Once()
+	s.ledgerBackend.On("PrepareRange", uint32(100), uint32(101)).Return(nil).Once()
+}
+
+func (s *DryRunRangeStateTestSuite) TearDownTest() {
+	t := s.T()
+	s.historyQ.AssertExpectations(t)
+	s.ledgerBackend.AssertExpectations(t)
+	s.runner.AssertExpectations(t)
+}
+
+func (s *DryRunRangeStateTestSuite) TestInvalidRange() {
+	*s.historyQ = mockDBQ{}
+	*s.ledgerBackend = mockLedgerBackend{}
+	s.historyQ.On("GetTx").Return(nil)
+
+	_, err := s.system.DryRunRange(100, 99)
+	s.Assert().EqualError(err, "invalid range: [100, 99]")
+}
+
+func (s *DryRunRangeStateTestSuite) TestRunTransactionProcessorsOnLedgerReturnsError() {
+	s.historyQ.On("Begin").Return(nil).Once()
+	s.historyQ.On("Rollback").Return(nil).Once()
+	s.historyQ.On(
+		"DeleteRangeAll", toid.New(100, 0, 0).ToInt64(), toid.New(102, 0, 0).ToInt64(),
+	).Return(nil).Once()
+	s.runner.On("RunTransactionProcessorsOnLedger", uint32(100)).
+		Return(io.StatsLedgerTransactionProcessorResults{}, errors.New("my error")).Once()
+
+	_, err := s.system.DryRunRange(100, 101)
+	s.Assert().EqualError(err, "error processing ledger sequence=100: my error")
+}
+
+func (s *DryRunRangeStateTestSuite) TestSuccess() {
+	s.historyQ.On("Begin").Return(nil).Once()
+	// the writes are rolled back, never committed
+	s.historyQ.On("Rollback").Return(nil).Once()
+	s.historyQ.On(
+		"DeleteRangeAll", toid.New(100, 0, 0).ToInt64(), toid.New(102, 0, 0).ToInt64(),
+	).Return(nil).Once()
+	s.runner.On("RunTransactionProcessorsOnLedger", uint32(100)).
+		Return(io.StatsLedgerTransactionProcessorResults{Transactions: 2, Operations: 5}, nil).Once()
+	s.runner.On("RunTransactionProcessorsOnLedger", uint32(101)).
+		Return(io.StatsLedgerTransactionProcessorResults{Transactions: 1, Operations: 1}, nil).Once()
+	s.historyQ.On("GetInsertedRowCounts").
+		Return(map[string]int64{"history_ledgers": 2, "history_operations": 6}, nil).Once()
+
+	report, err := s.system.DryRunRange(100, 101)
+	s.Assert().NoError(err)
+	s.Assert().Equal(int64(2), report.Ledgers)
+	s.Assert().Equal(int64(3), report.Transactions)
+	s.Assert().Equal(int64(6), report.Operations)
+	s.Assert().Equal(map[string]int64{"history_ledgers": 2, "history_operations": 6}, report.InsertedRows)
+}
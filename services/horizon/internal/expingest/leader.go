@@ -0,0 +1,85 @@
+package expingest
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// leaderElectionRetryInterval is the time instances which are not the
+// ingestion leader wait before trying to get elected again.
+const leaderElectionRetryInterval = 5 * time.Second
+
+type leaderElector interface {
+	// tryAcquire returns true when the instance has been elected.
+	tryAcquire(ctx context.Context) (bool, error)
+	// check returns an error when the instance may no longer be the leader.
+	check(ctx context.Context) error
+	release()
+}
+
+// leaderElection elects, among the Horizon instances sharing a database, the
+// single instance running the ingestion state machine. The leader holds a
+// session level advisory lock on a dedicated connection so the lock is
+// released, and another instance gets elected, as soon as the leader dies or
+// loses its connection.
+type leaderElection struct {
+	db   *sql.DB
+	conn *sql.Conn
+}
+
+func (e *leaderElection) tryAcquire(ctx context.Context) (bool, error) {
+	if e.conn == nil {
+		conn, err := e.db.Conn(ctx)
+		if err != nil {
+			return false, errors.Wrap(err, "could not open connection")
+		}
+		e.conn = conn
+	}
+
+	var acquired bool
+	err := e.conn.QueryRowContext(
+		ctx,
+		"SELECT pg_try_advisory_lock(hashtext('horizon_ingestion_leader'))",
+	).Scan(&acquired)
+	if err != nil {
+		e.closeConn()
+		return false, errors.Wrap(err, "could not acquire advisory lock")
+	}
+	return acquired, nil
+}
+
+// check pings the connection holding the lock. Session level locks are only
+// released explicitly or when the session ends so the lock is held as long as
+// the connection is alive.
+func (e *leaderElection) check(ctx context.Context) error {
+	if e.conn == nil {
+		return errors.New("no connection")
+	}
+
+	if err := e.conn.PingContext(ctx); err != nil {
+		e.closeConn()
+		return errors.Wrap(err, "connection holding the advisory lock is broken")
+	}
+	return nil
+}
+
+func (e *leaderElection) release() {
+	if e.conn == nil {
+		return
+	}
+	// The connection goes back to the pool so the lock must be released
+	// explicitly. If it fails the connection is broken and the lock is gone.
+	e.conn.ExecContext(
+		context.Background(),
+		"SELECT pg_advisory_unlock_all()",
+	)
+	e.closeConn()
+}
+
+func (e *leaderElection) closeConn() {
+	e.conn.Close()
+	e.conn = nil
+}
@@ -14,10 +14,12 @@ import (
 	ingesterrors "github.com/stellar/go/exp/ingest/errors"
 	"github.com/stellar/go/exp/ingest/ledgerbackend"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/expingest/processors"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/historyarchive"
 	logpkg "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
 )
 
 const (
@@ -52,15 +54,58 @@ const (
 var log = logpkg.DefaultLogger.WithField("service", "expingest")
 
 type Config struct {
+	// CoreSession is the stellar-core database session used to read ledgers.
+	// It is not required when StellarCorePath or LedgerMetaArchiveURL is set.
 	CoreSession       *db.Session
 	StellarCoreURL    string
 	StellarCoreCursor string
-	StellarCorePath   string
 	NetworkPassphrase string
 
-	HistorySession           *db.Session
+	// StellarCorePath is the path to the stellar-core binary. When set, ledgers
+	// are read from a captive stellar-core instead of the core database.
+	StellarCorePath string
+	// CaptiveCoreConfigAppendPath is an optional file appended to the config
+	// of captive stellar-core.
+	CaptiveCoreConfigAppendPath string
+	// CaptiveCoreStoragePath is the directory in which captive stellar-core
+	// stores its buckets and temporary files. Defaults to os.TempDir().
+	CaptiveCoreStoragePath string
+	// LedgerMetaArchiveURL is the URL of a meta archive, see
+	// ledgerbackend.MetaArchiveBackend. When set, ledgers are read from the
+	// LedgerCloseMeta exported to it instead of stellar-core. It is only
+	// supported by ReingestRange, live ingestion needs stellar-core.
+	LedgerMetaArchiveURL string
+
+	HistorySession *db.Session
+	// LeaderElectionSession, when set, makes the instances sharing the
+	// horizon database elect a single leader running the ingestion, see
+	// leaderElection. It needs a dedicated connection to the horizon database
+	// and is only used by Run.
+	LeaderElectionSession    *db.Session
 	HistoryArchiveURL        string
 	DisableStateVerification bool
+	// StateVerificationCheckpointFrequency runs the state verification every
+	// StateVerificationCheckpointFrequency checkpoints. Every checkpoint is
+	// verified when it is 0 or 1.
+	StateVerificationCheckpointFrequency uint32
+	// StateVerificationEntryTypes restricts the state verification to ledger
+	// entries of these types. All types are verified when empty.
+	StateVerificationEntryTypes []xdr.LedgerEntryType
+
+	// HistoryAllowlistAccounts and HistoryAllowlistAssets restrict the history
+	// (transactions, operations, effects, trades and participants) ingested
+	// to the transactions involving these accounts and assets, see
+	// processors.HistoryFilter. All the history is ingested when both are
+	// empty. The ledger state is always ingested in full.
+	HistoryAllowlistAccounts []string
+	HistoryAllowlistAssets   []xdr.Asset
+
+	// StateOnly ingests the ledger state and the ledgers but none of the
+	// history (transactions, operations, effects, trades and participants).
+	// The transactions of every ingested ledger are handed to
+	// RecentTransactions instead, when it is set.
+	StateOnly          bool
+	RecentTransactions processors.RecentTransactionsStore
 
 	// MaxStreamRetries determines how many times the reader will retry when encountering
 	// errors while streaming xdr bucket entries from the history archive.
@@ -93,6 +138,10 @@ type System struct {
 		// StateVerifyTimer exposes timing metrics about the rate and
 		// duration of state verification.
 		StateVerifyTimer metrics.Timer
+
+		// ProcessorsRunDuration exposes timing metrics about the time spent
+		// in every processor per ledger, by processor name.
+		ProcessorsRunDuration map[string]metrics.Timer
 	}
 
 	ctx    context.Context
@@ -103,6 +152,13 @@ type System struct {
 	historyQ history.IngestionQ
 	runner   ProcessorRunnerInterface
 
+	// historyPartitions creates the partitions of the history tables before
+	// ledgers are ingested. It is nil in tests. The partitions of the ledgers
+	// in [partitionedFrom, partitionedUntil) are known to exist.
+	historyPartitions historyPartitionsCreator
+	partitionedFrom   uint32
+	partitionedUntil  uint32
+
 	ledgerBackend  ledgerbackend.LedgerBackend
 	historyAdapter adapters.HistoryArchiveAdapterInterface
 
@@ -118,6 +174,19 @@ type System struct {
 	stateVerificationErrors  int
 	stateVerificationRunning bool
 	disableStateVerification bool
+
+	// paused is true when ingestion has been paused using Pause. The state
+	// machine does not enter a new state until Resume is called.
+	pausedMutex sync.Mutex
+	paused      bool
+
+	// leaderElection is nil when every instance runs the state machine.
+	// electLeader is true while the state machine is run by Run: reingestion
+	// and verification run alongside the leader and are never elected.
+	// leading is true while this instance is the elected leader.
+	leaderElection leaderElector
+	electLeader    bool
+	leading        bool
 }
 
 func NewSystem(config Config) (*System, error) {
@@ -134,17 +203,29 @@ func NewSystem(config Config) (*System, error) {
 		return nil, errors.Wrap(err, "error creating history archive")
 	}
 
-	coreSession := config.CoreSession.Clone()
-	coreSession.Ctx = ctx
-
 	var ledgerBackend ledgerbackend.LedgerBackend
-	if len(config.StellarCorePath) > 0 {
-		ledgerBackend = ledgerbackend.NewCaptive(
-			config.StellarCorePath,
-			config.NetworkPassphrase,
-			[]string{config.HistoryArchiveURL},
-		)
+	if len(config.LedgerMetaArchiveURL) > 0 {
+		ledgerBackend, err = ledgerbackend.NewMetaArchiveBackendFromURL(config.LedgerMetaArchiveURL)
+		if err != nil {
+			cancel()
+			return nil, errors.Wrap(err, "error creating ledger backend")
+		}
+	} else if len(config.StellarCorePath) > 0 {
+		ledgerBackend = ledgerbackend.NewCaptiveFromConfig(ledgerbackend.CaptiveCoreConfig{
+			ExecutablePath:    config.StellarCorePath,
+			NetworkPassphrase: config.NetworkPassphrase,
+			HistoryURLs:       []string{config.HistoryArchiveURL},
+			ConfigAppendPath:  config.CaptiveCoreConfigAppendPath,
+			StoragePath:       config.CaptiveCoreStoragePath,
+		})
 	} else {
+		if config.CoreSession == nil {
+			cancel()
+			return nil, errors.New("core session is required when captive core is disabled")
+		}
+		coreSession := config.CoreSession.Clone()
+		coreSession.Ctx = ctx
+
 		ledgerBackend, err = ledgerbackend.NewDatabaseBackendFromSession(coreSession)
 		if err != nil {
 			cancel()
@@ -152,6 +233,22 @@ func NewSystem(config Config) (*System, error) {
 		}
 	}
 
+	var historyFilter *processors.HistoryFilter
+	if len(config.HistoryAllowlistAccounts) > 0 || len(config.HistoryAllowlistAssets) > 0 {
+		if config.StateOnly {
+			cancel()
+			return nil, errors.New("history allowlists cannot be used when ingesting state only")
+		}
+		historyFilter, err = processors.NewHistoryFilter(
+			config.HistoryAllowlistAccounts,
+			config.HistoryAllowlistAssets,
+		)
+		if err != nil {
+			cancel()
+			return nil, errors.Wrap(err, "error creating history filter")
+		}
+	}
+
 	historyQ := &history.Q{config.HistorySession.Clone()}
 	historyQ.Ctx = ctx
 
@@ -164,28 +261,62 @@ func NewSystem(config Config) (*System, error) {
 		ledgerBackend:            ledgerBackend,
 		config:                   config,
 		historyQ:                 historyQ,
+		historyPartitions:        &history.Q{config.HistorySession.Clone()},
 		disableStateVerification: config.DisableStateVerification,
 		maxStreamRetries:         config.MaxStreamRetries,
 		stellarCoreClient: &stellarcore.Client{
 			URL: config.StellarCoreURL,
 		},
-		runner: &ProcessorRunner{
-			ctx:            ctx,
-			config:         config,
-			historyQ:       historyQ,
-			historyAdapter: historyAdapter,
-			ledgerBackend:  ledgerBackend,
-		},
+	}
+
+	if config.LeaderElectionSession != nil {
+		system.leaderElection = &leaderElection{db: config.LeaderElectionSession.DB.DB}
 	}
 
 	system.initMetrics()
+	system.runner = &ProcessorRunner{
+		ctx:             ctx,
+		config:          config,
+		historyQ:        historyQ,
+		historyAdapter:  historyAdapter,
+		ledgerBackend:   ledgerBackend,
+		historyFilter:   historyFilter,
+		processorTimers: system.Metrics.ProcessorsRunDuration,
+	}
 	return system, nil
 }
 
+type historyPartitionsCreator interface {
+	EnsureHistoryPartitions(fromLedger, toLedger uint32) error
+}
+
+// ensureHistoryPartitions creates the missing partitions of the history tables
+// for the ledgers in [fromLedger, toLedger]. It must be called outside of the
+// ingestion transaction.
+func (s *System) ensureHistoryPartitions(fromLedger, toLedger uint32) error {
+	if s.historyPartitions == nil {
+		return nil
+	}
+	if fromLedger >= s.partitionedFrom && toLedger < s.partitionedUntil {
+		return nil
+	}
+
+	err := s.historyPartitions.EnsureHistoryPartitions(fromLedger, toLedger)
+	if err != nil {
+		return errors.Wrap(err, "error creating history partitions")
+	}
+	s.partitionedFrom, s.partitionedUntil = fromLedger, toLedger+1
+	return nil
+}
+
 func (s *System) initMetrics() {
 	s.Metrics.LedgerIngestionTimer = metrics.NewTimer()
 	s.Metrics.LedgerInMemoryIngestionTimer = metrics.NewTimer()
 	s.Metrics.StateVerifyTimer = metrics.NewTimer()
+	s.Metrics.ProcessorsRunDuration = map[string]metrics.Timer{}
+	for _, name := range processorNames {
+		s.Metrics.ProcessorsRunDuration[name] = metrics.NewTimer()
+	}
 }
 
 // Run starts ingestion system. Ingestion system supports distributed ingestion
@@ -205,6 +336,10 @@ func (s *System) initMetrics() {
 // one instance will be able to acquire it. This happens in both initial processing
 // and ledger processing. So this solves 3a and 3b in both 1a and 1b.
 //
+// When Config.LeaderElectionSession is set, the instances also elect a leader
+// holding a Postgres advisory lock and the other instances do not run the state
+// machine at all until the leader dies, see waitForLeadership.
+//
 // Finally, 1a and 1b are tricky because we need to keep the latest version
 // of order book graph in memory of each Horizon instance. To solve this:
 // * For state init:
@@ -218,6 +353,8 @@ func (s *System) initMetrics() {
 //   * If instances is a NOT leader, it runs ledger pipeline without updating a
 //     a database so order book graph is updated but database is not overwritten.
 func (s *System) Run() {
+	s.electLeader = true
+	defer func() { s.electLeader = false }()
 	s.runStateMachine(startState{})
 }
 
@@ -238,12 +375,16 @@ func (s *System) StressTest(numTransactions, changesPerTransaction int) error {
 }
 
 // VerifyRange runs the ingestion pipeline on the range of ledgers. When
-// verifyState is true it verifies the state when ingestion is complete.
-func (s *System) VerifyRange(fromLedger, toLedger uint32, verifyState bool) error {
+// verifyState is true it verifies the state when ingestion is complete. When
+// resume is true and the database already contains ledgers of the range (from
+// an interrupted verification), ingestion continues after the last ingested
+// ledger.
+func (s *System) VerifyRange(fromLedger, toLedger uint32, verifyState, resume bool) error {
 	return s.runStateMachine(verifyRangeState{
 		fromLedger:  fromLedger,
 		toLedger:    toLedger,
 		verifyState: verifyState,
+		resume:      resume,
 	})
 }
 
@@ -257,13 +398,51 @@ func (s *System) ReingestRange(fromLedger, toLedger uint32, force bool) error {
 	})
 }
 
+// DryRunReport is the result of an ingestion dry run on a range of ledgers.
+type DryRunReport struct {
+	Ledgers      int64
+	Transactions int64
+	Operations   int64
+	// Duration is the time spent running the processors on the range.
+	Duration time.Duration
+	// DeleteDuration is the time spent deleting the history rows of the range
+	// which were already ingested.
+	DeleteDuration time.Duration
+	// ProcessorDurations is the mean time spent in every processor per
+	// ledger, by processor name.
+	ProcessorDurations map[string]time.Duration
+	// InsertedRows is the number of rows which were inserted in every table
+	// before the writes were discarded, by table name.
+	InsertedRows map[string]int64
+}
+
+// DryRunRange runs the history processors on the range of ledgers and
+// discards their writes, to measure the performance of the ingestion on real
+// ledgers without modifying the database. The history rows of the range
+// already in the database are deleted and inserted again in a transaction
+// which is rolled back.
+func (s *System) DryRunRange(fromLedger, toLedger uint32) (DryRunReport, error) {
+	report := &DryRunReport{}
+	err := s.runStateMachine(dryRunRangeState{
+		fromLedger: fromLedger,
+		toLedger:   toLedger,
+		report:     report,
+	})
+	return *report, err
+}
+
 func (s *System) runStateMachine(cur stateMachineNode) error {
 	defer func() {
 		s.wg.Wait()
+		if s.leading {
+			s.leaderElection.release()
+			s.leading = false
+		}
 	}()
 
 	log.WithFields(logpkg.F{"current_state": cur}).Info("Ingestion system initial state")
 
+	ran := false
 	for {
 		// Every node in the state machine is responsible for
 		// creating and disposing its own transaction.
@@ -273,7 +452,24 @@ func (s *System) runStateMachine(cur stateMachineNode) error {
 			panic("unexpected transaction")
 		}
 
+		if !s.waitWhilePaused() {
+			log.Info("Received shut down signal...")
+			return nil
+		}
+
+		ready, elected := s.waitForLeadership()
+		if !ready {
+			log.Info("Received shut down signal...")
+			return nil
+		}
+		if elected && ran {
+			// The instance lost its leadership while running the state
+			// machine and the new leader may have ingested ledgers meanwhile.
+			cur = startState{}
+		}
+
 		next, err := cur.run(s)
+		ran = true
 		if err != nil {
 			logger := log.WithFields(logpkg.F{
 				"error":         err,
@@ -310,6 +506,88 @@ func (s *System) runStateMachine(cur stateMachineNode) error {
 	}
 }
 
+// Pause stops the ingestion system from entering new states until Resume is
+// called. The state which is currently running is completed first.
+func (s *System) Pause() {
+	s.pausedMutex.Lock()
+	defer s.pausedMutex.Unlock()
+	if !s.paused {
+		log.Info("Pausing ingestion system...")
+	}
+	s.paused = true
+}
+
+// Resume resumes the ingestion system paused using Pause.
+func (s *System) Resume() {
+	s.pausedMutex.Lock()
+	defer s.pausedMutex.Unlock()
+	if s.paused {
+		log.Info("Resuming ingestion system...")
+	}
+	s.paused = false
+}
+
+// Paused returns true if the ingestion system has been paused.
+func (s *System) Paused() bool {
+	s.pausedMutex.Lock()
+	defer s.pausedMutex.Unlock()
+	return s.paused
+}
+
+// waitWhilePaused blocks while the ingestion system is paused. It returns
+// false if the system was shut down in the meantime.
+func (s *System) waitWhilePaused() bool {
+	for s.Paused() {
+		select {
+		case <-s.ctx.Done():
+			return false
+		case <-time.After(time.Second):
+		}
+	}
+	return true
+}
+
+// waitForLeadership blocks until this instance is the elected ingestion
+// leader. It returns false as its first value when the system is shut down
+// meanwhile. The second value is true when the instance has just been elected.
+// Systems without leader election, and state machines not run by Run, are
+// always ready.
+func (s *System) waitForLeadership() (bool, bool) {
+	if s.leaderElection == nil || !s.electLeader {
+		return true, false
+	}
+
+	if s.leading {
+		err := s.leaderElection.check(s.ctx)
+		if err == nil {
+			return true, false
+		}
+		log.WithField("err", err).Error("Lost ingestion leadership")
+		s.leading = false
+	}
+
+	for waiting := false; ; waiting = true {
+		acquired, err := s.leaderElection.tryAcquire(s.ctx)
+		if err != nil && !isCancelledError(err) {
+			log.WithField("err", err).Warn("Error in ingestion leader election")
+		}
+		if acquired {
+			log.Info("Elected ingestion leader")
+			s.leading = true
+			return true, true
+		}
+		if !waiting {
+			log.Info("Another instance is the ingestion leader, waiting...")
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return false, false
+		case <-time.After(leaderElectionRetryInterval):
+		}
+	}
+}
+
 func (s *System) maybeVerifyState(lastIngestedLedger uint32) {
 	stateInvalid, err := s.historyQ.GetExpStateInvalid()
 	if err != nil && !isCancelledError(err) {
@@ -319,7 +597,8 @@ func (s *System) maybeVerifyState(lastIngestedLedger uint32) {
 	// Run verification routine only when...
 	if !stateInvalid && // state has not been proved to be invalid...
 		!s.disableStateVerification && // state verification is not disabled...
-		historyarchive.IsCheckpoint(lastIngestedLedger) { // it's a checkpoint ledger.
+		historyarchive.IsCheckpoint(lastIngestedLedger) && // it's a checkpoint ledger...
+		s.stateVerificationDue(lastIngestedLedger) { // and its turn has come.
 		s.wg.Add(1)
 		go func() {
 			defer s.wg.Done()
@@ -348,6 +627,17 @@ func (s *System) maybeVerifyState(lastIngestedLedger uint32) {
 	}
 }
 
+// stateVerificationDue returns true when the state must be verified at
+// checkpoint ledger `checkpoint` according to
+// Config.StateVerificationCheckpointFrequency.
+func (s *System) stateVerificationDue(checkpoint uint32) bool {
+	frequency := s.config.StateVerificationCheckpointFrequency
+	if frequency <= 1 {
+		return true
+	}
+	return ((checkpoint+1)/historyarchive.CheckpointFreq)%frequency == 0
+}
+
 func (s *System) incrementStateVerificationErrors() int {
 	s.stateVerificationMutex.Lock()
 	defer s.stateVerificationMutex.Unlock()
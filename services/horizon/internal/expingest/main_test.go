@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stellar/go/exp/ingest/adapters"
@@ -75,6 +76,18 @@ func TestCheckVerifyStateVersion(t *testing.T) {
 	)
 }
 
+func TestStateVerificationDue(t *testing.T) {
+	system := &System{}
+	assert.True(t, system.stateVerificationDue(63))
+	assert.True(t, system.stateVerificationDue(127))
+
+	system.config.StateVerificationCheckpointFrequency = 4
+	assert.False(t, system.stateVerificationDue(63))
+	assert.False(t, system.stateVerificationDue(127))
+	assert.True(t, system.stateVerificationDue(255))
+	assert.True(t, system.stateVerificationDue(511))
+}
+
 func TestNewSystem(t *testing.T) {
 	config := Config{
 		CoreSession: &db.Session{
@@ -100,6 +113,26 @@ func TestNewSystem(t *testing.T) {
 	assert.Equal(t, system.ctx, system.runner.(*ProcessorRunner).ctx)
 }
 
+func TestNewSystemCaptiveCore(t *testing.T) {
+	config := Config{
+		HistorySession: &db.Session{
+			DB:  &sqlx.DB{},
+			Ctx: context.Background(),
+		},
+		HistoryArchiveURL: "https://history.stellar.org/prd/core-live/core_live_001",
+	}
+
+	// the core database is required without captive core
+	_, err := NewSystem(config)
+	assert.EqualError(t, err, "core session is required when captive core is disabled")
+
+	config.StellarCorePath = "/usr/bin/stellar-core"
+	config.CaptiveCoreStoragePath = "/var/lib/horizon"
+	system, err := NewSystem(config)
+	assert.NoError(t, err)
+	assert.NotNil(t, system.ledgerBackend)
+}
+
 func TestStateMachineRunReturnsUnexpectedTransaction(t *testing.T) {
 	historyQ := &mockDBQ{}
 	system := &System{
@@ -145,6 +178,77 @@ func TestContextCancel(t *testing.T) {
 	assert.NoError(t, system.runStateMachine(startState{}))
 }
 
+func TestPausedSystemDoesNotRunStates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	historyQ := &mockDBQ{}
+	system := &System{
+		historyQ: historyQ,
+		ctx:      ctx,
+	}
+
+	historyQ.On("GetTx").Return(nil).Once()
+
+	system.Pause()
+	assert.True(t, system.Paused())
+	cancel()
+	assert.NoError(t, system.runStateMachine(startState{}))
+	historyQ.AssertExpectations(t)
+
+	system.Resume()
+	assert.False(t, system.Paused())
+}
+
+func TestWaitForLeadership(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	elector := &mockLeaderElector{}
+	defer mock.AssertExpectationsForObjects(t, elector)
+	system := &System{ctx: ctx, leaderElection: elector, electLeader: true}
+
+	elector.On("tryAcquire", ctx).Return(true, nil).Once()
+	ready, elected := system.waitForLeadership()
+	assert.True(t, ready)
+	assert.True(t, elected)
+
+	// the leader keeps its leadership while its lock is held
+	elector.On("check", ctx).Return(nil).Once()
+	ready, elected = system.waitForLeadership()
+	assert.True(t, ready)
+	assert.False(t, elected)
+
+	// and is elected again after losing it
+	elector.On("check", ctx).Return(errors.New("broken connection")).Once()
+	elector.On("tryAcquire", ctx).Return(true, nil).Once()
+	ready, elected = system.waitForLeadership()
+	assert.True(t, ready)
+	assert.True(t, elected)
+
+	// other instances wait until shut down
+	system.leading = false
+	cancel()
+	elector.On("tryAcquire", ctx).Return(false, nil).Once()
+	ready, _ = system.waitForLeadership()
+	assert.False(t, ready)
+}
+
+func TestStateMachineReleasesLeadership(t *testing.T) {
+	historyQ := &mockDBQ{}
+	elector := &mockLeaderElector{}
+	defer mock.AssertExpectationsForObjects(t, historyQ, elector)
+	system := &System{
+		ctx:            context.Background(),
+		historyQ:       historyQ,
+		leaderElection: elector,
+		electLeader:    true,
+	}
+
+	historyQ.On("GetTx").Return(nil).Once()
+	elector.On("tryAcquire", system.ctx).Return(true, nil).Once()
+	elector.On("release").Once()
+
+	assert.EqualError(t, system.runStateMachine(stopState{}), "Cannot run terminal state")
+	assert.False(t, system.leading)
+}
+
 // TestStateMachineRunReturnsErrorWhenNextStateIsShutdownWithError checks if the
 // state that goes to shutdownState and returns an error will make `run` function
 // return that error. This is essential because some commands rely on this to return
@@ -333,6 +437,11 @@ func (m *mockDBQ) DeleteRangeAll(start, end int64) error {
 	return args.Error(0)
 }
 
+func (m *mockDBQ) GetInsertedRowCounts() (map[string]int64, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
 // Methods from interfaces duplicating methods:
 
 func (m *mockDBQ) NewTransactionParticipantsBatchInsertBuilder(maxBatchSize int) history.TransactionParticipantsBatchInsertBuilder {
@@ -355,6 +464,11 @@ func (m *mockDBQ) CreateAssets(assets []xdr.Asset, batchSize int) (map[string]hi
 	return args.Get(0).(map[string]history.Asset), args.Error(1)
 }
 
+func (m *mockDBQ) RebuildTradeRollups(from, to time.Time) error {
+	args := m.Called(from, to)
+	return args.Error(0)
+}
+
 type mockLedgerBackend struct {
 	mock.Mock
 }
@@ -433,3 +547,23 @@ func (m *mockStellarCoreClient) SetCursor(ctx context.Context, id string, cursor
 }
 
 var _ stellarCoreClient = (*mockStellarCoreClient)(nil)
+
+type mockLeaderElector struct {
+	mock.Mock
+}
+
+func (m *mockLeaderElector) tryAcquire(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockLeaderElector) check(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockLeaderElector) release() {
+	m.Called()
+}
+
+var _ leaderElector = (*mockLeaderElector)(nil)
@@ -0,0 +1,312 @@
+package expingest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/historyarchive"
+	logpkg "github.com/stellar/go/support/log"
+)
+
+const (
+	// maxReingestRetries is the number of times a failed sub-range is retried
+	// by ParallelSystems before giving up.
+	maxReingestRetries = 3
+	// reingestRetryDelay is the time a worker waits before retrying a failed
+	// sub-range.
+	reingestRetryDelay = 5 * time.Second
+)
+
+// errReingestStopped is reported for sub-ranges which were not reingested
+// because another sub-range failed.
+var errReingestStopped = errors.New("reingestion stopped")
+
+// rangeReingester reingests ranges of ledgers. It is implemented by *System.
+type rangeReingester interface {
+	ReingestRange(fromLedger, toLedger uint32, force bool) error
+	Shutdown()
+}
+
+type ledgerRange struct {
+	from uint32
+	to   uint32
+}
+
+func (r ledgerRange) String() string {
+	return fmt.Sprintf("[%d, %d]", r.from, r.to)
+}
+
+type rangeJob struct {
+	ledgerRange
+	attempt int
+}
+
+type rangeResult struct {
+	job rangeJob
+	err error
+}
+
+// ParallelSystems reingests a range of ledgers by splitting it into sub-ranges
+// which are reingested concurrently by several workers. Every worker has its
+// own ingestion system, and so its own ledger backend.
+type ParallelSystems struct {
+	config        Config
+	workerCount   uint
+	retryDelay    time.Duration
+	systemFactory func(Config) (rangeReingester, error)
+}
+
+// NewParallelSystems returns a ParallelSystems running `workerCount` workers
+// configured with `config`.
+func NewParallelSystems(config Config, workerCount uint) (*ParallelSystems, error) {
+	if workerCount < 1 {
+		return nil, errors.New("workerCount must be > 0")
+	}
+
+	return &ParallelSystems{
+		config:      config,
+		workerCount: workerCount,
+		retryDelay:  reingestRetryDelay,
+		systemFactory: func(c Config) (rangeReingester, error) {
+			return NewSystem(c)
+		},
+	}, nil
+}
+
+// splitRange splits [fromLedger, toLedger] into consecutive sub-ranges of at
+// most `jobSize` ledgers. The range is split in at least `workerCount`
+// sub-ranges so that all the workers are busy.
+func splitRange(fromLedger, toLedger, jobSize uint32, workerCount uint) []ledgerRange {
+	total := uint64(toLedger-fromLedger) + 1
+	size := (total + uint64(workerCount) - 1) / uint64(workerCount)
+	if jobSize > 0 && uint64(jobSize) < size {
+		size = uint64(jobSize)
+	}
+
+	var ranges []ledgerRange
+	for from := uint64(fromLedger); from <= uint64(toLedger); from += size {
+		to := from + size - 1
+		if to > uint64(toLedger) {
+			to = uint64(toLedger)
+		}
+		ranges = append(ranges, ledgerRange{from: uint32(from), to: uint32(to)})
+	}
+	return ranges
+}
+
+// ReingestRange reingests [fromLedger, toLedger] in sub-ranges of at most
+// `jobSize` ledgers. A failed sub-range is retried up to maxReingestRetries
+// times, possibly by another worker. When a sub-range cannot be reingested no
+// new sub-ranges are started and the error is returned once the running ones
+// complete.
+func (ps *ParallelSystems) ReingestRange(fromLedger, toLedger, jobSize uint32) error {
+	if fromLedger == 0 || toLedger == 0 || fromLedger > toLedger {
+		return errors.Errorf("invalid range: [%d, %d]", fromLedger, toLedger)
+	}
+
+	ranges := splitRange(fromLedger, toLedger, jobSize, ps.workerCount)
+	workerCount := ps.workerCount
+	if uint(len(ranges)) < workerCount {
+		workerCount = uint(len(ranges))
+	}
+
+	// jobs can hold every sub-range so the coordinator never blocks when
+	// queueing or requeueing them.
+	jobs := make(chan rangeJob, len(ranges))
+	results := make(chan rangeResult, len(ranges))
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var systems []rangeReingester
+	for i := uint(0); i < workerCount; i++ {
+		system, err := ps.systemFactory(ps.config)
+		if err != nil {
+			for _, s := range systems {
+				s.Shutdown()
+			}
+			return errors.Wrap(err, "error creating ingestion system")
+		}
+		systems = append(systems, system)
+
+		wg.Add(1)
+		go func(system rangeReingester) {
+			defer wg.Done()
+			ps.runWorker(system, jobs, results, stop)
+		}(system)
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+		for _, system := range systems {
+			system.Shutdown()
+		}
+	}()
+
+	for _, r := range ranges {
+		jobs <- rangeJob{ledgerRange: r}
+	}
+
+	var firstErr error
+	completed := 0
+	for pending := len(ranges); pending > 0; {
+		result := <-results
+		if result.err == nil {
+			pending--
+			completed++
+			log.WithFields(logpkg.F{
+				"range":     result.job.String(),
+				"completed": completed,
+				"total":     len(ranges),
+			}).Info("Reingested sub-range")
+			continue
+		}
+
+		if firstErr == nil &&
+			errors.Cause(result.err) != ErrReingestRangeConflict &&
+			result.job.attempt < maxReingestRetries {
+			log.WithFields(logpkg.F{
+				"range":   result.job.String(),
+				"attempt": result.job.attempt + 1,
+				"err":     result.err,
+			}).Warn("Error reingesting sub-range, retrying")
+			result.job.attempt++
+			jobs <- result.job
+			continue
+		}
+
+		pending--
+		if firstErr == nil && result.err != errReingestStopped {
+			firstErr = errors.Wrapf(result.err, "error reingesting sub-range %s", result.job.String())
+			close(stop)
+		}
+	}
+
+	return firstErr
+}
+
+// runWorker reingests the sub-ranges received from `jobs` with `system` until
+// `jobs` is closed. Sub-ranges received after `stop` is closed are skipped.
+func (ps *ParallelSystems) runWorker(
+	system rangeReingester,
+	jobs <-chan rangeJob,
+	results chan<- rangeResult,
+	stop <-chan struct{},
+) {
+	for job := range jobs {
+		select {
+		case <-stop:
+			results <- rangeResult{job: job, err: errReingestStopped}
+			continue
+		default:
+		}
+
+		if job.attempt > 0 {
+			time.Sleep(ps.retryDelay)
+		}
+		err := system.ReingestRange(job.from, job.to, false)
+		results <- rangeResult{job: job, err: err}
+	}
+}
+
+// rangeVerifier verifies ranges of ledgers. It is implemented by *System.
+type rangeVerifier interface {
+	VerifyRange(fromLedger, toLedger uint32, verifyState, resume bool) error
+	Shutdown()
+}
+
+// splitVerifyRange splits [fromLedger, toLedger] into at most `count`
+// sub-ranges bounded by checkpoints. Consecutive sub-ranges share their
+// boundary checkpoint: the state of the checkpoint is verified at the end of a
+// sub-range and loaded from the history archive at the start of the next one.
+func splitVerifyRange(fromLedger, toLedger uint32, count int) []ledgerRange {
+	// checkpoints strictly between fromLedger and toLedger
+	first := historyarchive.NextCheckpoint(fromLedger + 1)
+	inner := 0
+	if first < toLedger {
+		inner = int((toLedger-1-first)/historyarchive.CheckpointFreq) + 1
+	}
+
+	boundaries := count - 1
+	if boundaries > inner {
+		boundaries = inner
+	}
+
+	var ranges []ledgerRange
+	from := fromLedger
+	for i := 1; i <= boundaries; i++ {
+		index := i*(inner+1)/(boundaries+1) - 1
+		to := first + uint32(index)*historyarchive.CheckpointFreq
+		ranges = append(ranges, ledgerRange{from: from, to: to})
+		from = to
+	}
+	return append(ranges, ledgerRange{from: from, to: toLedger})
+}
+
+// VerifyRangeParallel verifies [fromLedger, toLedger] with one worker per
+// config, see System.VerifyRange. The range is split into sub-ranges bounded
+// by checkpoints so, with verifyState, the state of every boundary checkpoint
+// is verified. Every config must use its own horizon database.
+func VerifyRangeParallel(configs []Config, fromLedger, toLedger uint32, verifyState, resume bool) error {
+	return verifyRangeParallel(configs, fromLedger, toLedger, verifyState, resume,
+		func(c Config) (rangeVerifier, error) {
+			return NewSystem(c)
+		},
+	)
+}
+
+func verifyRangeParallel(
+	configs []Config,
+	fromLedger, toLedger uint32,
+	verifyState, resume bool,
+	systemFactory func(Config) (rangeVerifier, error),
+) error {
+	if len(configs) == 0 {
+		return errors.New("at least one config is required")
+	}
+	if fromLedger == 0 || toLedger == 0 || fromLedger > toLedger {
+		return errors.Errorf("invalid range: [%d, %d]", fromLedger, toLedger)
+	}
+
+	ranges := splitVerifyRange(fromLedger, toLedger, len(configs))
+	systems := make([]rangeVerifier, 0, len(ranges))
+	defer func() {
+		for _, system := range systems {
+			system.Shutdown()
+		}
+	}()
+	for i := range ranges {
+		system, err := systemFactory(configs[i])
+		if err != nil {
+			return errors.Wrap(err, "error creating ingestion system")
+		}
+		systems = append(systems, system)
+	}
+
+	errs := make([]error, len(ranges))
+	var wg sync.WaitGroup
+	for i := range ranges {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := ranges[i]
+			log.WithField("range", r.String()).Info("Verifying sub-range")
+			errs[i] = systems[i].VerifyRange(r.from, r.to, verifyState, resume)
+			if errs[i] != nil {
+				log.WithField("range", r.String()).WithField("err", errs[i]).
+					Error("Error verifying sub-range")
+			} else {
+				log.WithField("range", r.String()).Info("Verified sub-range")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return errors.Wrapf(err, "error verifying sub-range %s", ranges[i].String())
+		}
+	}
+	return nil
+}
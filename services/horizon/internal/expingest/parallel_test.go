@@ -0,0 +1,165 @@
+package expingest
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockRangeReingester struct {
+	mock.Mock
+}
+
+func (m *mockRangeReingester) ReingestRange(fromLedger, toLedger uint32, force bool) error {
+	args := m.Called(fromLedger, toLedger, force)
+	return args.Error(0)
+}
+
+func (m *mockRangeReingester) Shutdown() {
+	m.Called()
+}
+
+func TestSplitRange(t *testing.T) {
+	assert.Equal(t,
+		[]ledgerRange{{1, 25}, {26, 50}, {51, 75}, {76, 100}},
+		splitRange(1, 100, 0, 4),
+	)
+	assert.Equal(t,
+		[]ledgerRange{{1, 10}, {11, 20}, {21, 30}, {31, 34}},
+		splitRange(1, 34, 10, 2),
+	)
+	assert.Equal(t,
+		[]ledgerRange{{5, 5}, {6, 6}},
+		splitRange(5, 6, 100, 4),
+	)
+}
+
+// newTestParallelSystems returns a ParallelSystems whose workers share
+// `system`.
+func newTestParallelSystems(t *testing.T, system rangeReingester, workerCount uint) *ParallelSystems {
+	ps, err := NewParallelSystems(Config{}, workerCount)
+	assert.NoError(t, err)
+	ps.retryDelay = 0
+	ps.systemFactory = func(Config) (rangeReingester, error) {
+		return system, nil
+	}
+	return ps
+}
+
+func TestParallelReingestRange(t *testing.T) {
+	var lock sync.Mutex
+	var reingested []ledgerRange
+	system := &mockRangeReingester{}
+	system.On("ReingestRange", mock.Anything, mock.Anything, false).Run(func(args mock.Arguments) {
+		lock.Lock()
+		defer lock.Unlock()
+		reingested = append(reingested, ledgerRange{args.Get(0).(uint32), args.Get(1).(uint32)})
+	}).Return(nil)
+	system.On("Shutdown").Return().Times(3)
+
+	err := newTestParallelSystems(t, system, 3).ReingestRange(1, 50, 10)
+	assert.NoError(t, err)
+
+	sort.Slice(reingested, func(i, j int) bool {
+		return reingested[i].from < reingested[j].from
+	})
+	assert.Equal(t, []ledgerRange{{1, 10}, {11, 20}, {21, 30}, {31, 40}, {41, 50}}, reingested)
+	system.AssertExpectations(t)
+}
+
+func TestParallelReingestRangeRetries(t *testing.T) {
+	system := &mockRangeReingester{}
+	system.On("ReingestRange", uint32(1), uint32(10), false).Return(nil).Once()
+	system.On("ReingestRange", uint32(11), uint32(20), false).Return(errors.New("core crashed")).Twice()
+	system.On("ReingestRange", uint32(11), uint32(20), false).Return(nil).Once()
+	system.On("Shutdown").Return()
+
+	err := newTestParallelSystems(t, system, 2).ReingestRange(1, 20, 0)
+	assert.NoError(t, err)
+	system.AssertExpectations(t)
+}
+
+func TestParallelReingestRangeFailure(t *testing.T) {
+	system := &mockRangeReingester{}
+	system.On("ReingestRange", uint32(1), uint32(10), false).
+		Return(errors.New("core crashed")).Times(maxReingestRetries + 1)
+	system.On("Shutdown").Return()
+
+	err := newTestParallelSystems(t, system, 1).ReingestRange(1, 10, 0)
+	assert.EqualError(t, err, "error reingesting sub-range [1, 10]: core crashed")
+	system.AssertExpectations(t)
+}
+
+func TestParallelReingestRangeConflict(t *testing.T) {
+	system := &mockRangeReingester{}
+	system.On("ReingestRange", uint32(1), uint32(10), false).
+		Return(ErrReingestRangeConflict).Once()
+	system.On("Shutdown").Return()
+
+	err := newTestParallelSystems(t, system, 1).ReingestRange(1, 10, 0)
+	assert.Equal(t, ErrReingestRangeConflict, errors.Cause(err))
+	system.AssertExpectations(t)
+}
+
+func TestParallelReingestRangeInvalid(t *testing.T) {
+	_, err := NewParallelSystems(Config{}, 0)
+	assert.EqualError(t, err, "workerCount must be > 0")
+
+	system := &mockRangeReingester{}
+	err = newTestParallelSystems(t, system, 2).ReingestRange(10, 1, 0)
+	assert.EqualError(t, err, "invalid range: [10, 1]")
+	system.AssertNotCalled(t, "ReingestRange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+type mockRangeVerifier struct {
+	mock.Mock
+}
+
+func (m *mockRangeVerifier) VerifyRange(fromLedger, toLedger uint32, verifyState, resume bool) error {
+	args := m.Called(fromLedger, toLedger, verifyState, resume)
+	return args.Error(0)
+}
+
+func (m *mockRangeVerifier) Shutdown() {
+	m.Called()
+}
+
+func TestSplitVerifyRange(t *testing.T) {
+	assert.Equal(t,
+		[]ledgerRange{{63, 255}, {255, 511}, {511, 767}, {767, 1023}},
+		splitVerifyRange(63, 1023, 4),
+	)
+	assert.Equal(t,
+		[]ledgerRange{{1, 63}, {63, 127}},
+		splitVerifyRange(1, 127, 4),
+	)
+	assert.Equal(t,
+		[]ledgerRange{{127, 191}},
+		splitVerifyRange(127, 191, 3),
+	)
+	assert.Equal(t,
+		[]ledgerRange{{63, 500}},
+		splitVerifyRange(63, 500, 1),
+	)
+}
+
+func TestVerifyRangeParallel(t *testing.T) {
+	system := &mockRangeVerifier{}
+	system.On("VerifyRange", uint32(1), uint32(63), true, true).Return(nil).Once()
+	system.On("VerifyRange", uint32(63), uint32(127), true, true).
+		Return(errors.New("state is invalid")).Once()
+	system.On("Shutdown").Return().Twice()
+
+	err := verifyRangeParallel(
+		[]Config{{}, {}, {}}, 1, 127, true, true,
+		func(Config) (rangeVerifier, error) {
+			return system, nil
+		},
+	)
+	assert.EqualError(t, err, "error verifying sub-range [63, 127]: state is invalid")
+	system.AssertExpectations(t)
+}
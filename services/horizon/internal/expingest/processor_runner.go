@@ -5,13 +5,17 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/rcrowley/go-metrics"
 	"github.com/stellar/go/exp/ingest/adapters"
 	"github.com/stellar/go/exp/ingest/io"
 	"github.com/stellar/go/exp/ingest/ledgerbackend"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/expingest/processors"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/tracing"
 	"github.com/stellar/go/xdr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ingestionSource int
@@ -23,6 +27,23 @@ const (
 	logFrequency         = 100000
 )
 
+// processorNames are the names of the processors timed by
+// System.Metrics.ProcessorsRunDuration.
+var processorNames = []string{
+	"account_data",
+	"accounts",
+	"offers",
+	"asset_stats",
+	"signers",
+	"trust_lines",
+	"effects",
+	"ledgers",
+	"operations",
+	"trades",
+	"participants",
+	"transactions",
+}
+
 type horizonTransactionProcessor interface {
 	io.LedgerTransactionProcessor
 	// TODO maybe rename to Flush()
@@ -45,6 +66,46 @@ func (statsLedgerTransactionProcessor) Commit() error {
 	return nil
 }
 
+// filteredTransactionProcessor forwards to the wrapped processor only the
+// transactions matching the history filter.
+type filteredTransactionProcessor struct {
+	horizonTransactionProcessor
+	filter *ledgerHistoryFilter
+}
+
+func (p *filteredTransactionProcessor) ProcessTransaction(transaction io.LedgerTransaction) error {
+	match, err := p.filter.match(transaction)
+	if err != nil || !match {
+		return err
+	}
+	return p.horizonTransactionProcessor.ProcessTransaction(transaction)
+}
+
+// ledgerHistoryFilter matches the transactions of a ledger against a history
+// filter. The result for the last transaction is kept since all the filtered
+// processors check it in turn.
+type ledgerHistoryFilter struct {
+	filter   *processors.HistoryFilter
+	sequence uint32
+
+	checked bool
+	index   uint32
+	matched bool
+}
+
+func (f *ledgerHistoryFilter) match(transaction io.LedgerTransaction) (bool, error) {
+	if f.checked && f.index == transaction.Index {
+		return f.matched, nil
+	}
+
+	matched, err := f.filter.Match(f.sequence, transaction)
+	if err != nil {
+		return false, errors.Wrap(err, "error filtering transaction")
+	}
+	f.checked, f.index, f.matched = true, transaction.Index, matched
+	return matched, nil
+}
+
 type ProcessorRunnerInterface interface {
 	SetLedgerBackend(ledgerBackend ledgerbackend.LedgerBackend)
 	SetHistoryAdapter(historyAdapter adapters.HistoryArchiveAdapterInterface)
@@ -69,6 +130,15 @@ type ProcessorRunner struct {
 	historyAdapter adapters.HistoryArchiveAdapterInterface
 	ledgerBackend  ledgerbackend.LedgerBackend
 	logMemoryStats bool
+	// historyFilter restricts the ingested history rows to the transactions
+	// it matches. All the history is ingested when nil.
+	historyFilter *processors.HistoryFilter
+	// processorTimers measure the time spent in every processor per ledger,
+	// by processor name. Processors are not timed when nil.
+	processorTimers map[string]metrics.Timer
+	// traceCtx holds the span of the ledger being ingested, the parent of
+	// the spans of the processors.
+	traceCtx context.Context
 }
 
 func (s *ProcessorRunner) SetLedgerBackend(ledgerBackend ledgerbackend.LedgerBackend) {
@@ -96,15 +166,25 @@ func (s *ProcessorRunner) buildChangeProcessor(
 		StatsChangeProcessor: changeStats,
 	}
 
+	// Only the processing of ledgers is timed, history archive ingestion
+	// would skew the metrics.
+	timed := func(name string, p horizonChangeProcessor) horizonChangeProcessor {
+		timer := s.processorTimers[name]
+		if timer == nil || source != ledgerSource {
+			return p
+		}
+		return &timedChangeProcessor{horizonChangeProcessor: p, timer: timer, ctx: s.traceCtx, name: name}
+	}
+
 	useLedgerCache := source == ledgerSource
 	return groupChangeProcessors{
 		statsChangeProcessor,
-		processors.NewAccountDataProcessor(s.historyQ),
-		processors.NewAccountsProcessor(s.historyQ),
-		processors.NewOffersProcessor(s.historyQ, sequence),
-		processors.NewAssetStatsProcessor(s.historyQ, useLedgerCache),
-		processors.NewSignersProcessor(s.historyQ, useLedgerCache),
-		processors.NewTrustLinesProcessor(s.historyQ),
+		timed("account_data", processors.NewAccountDataProcessor(s.historyQ)),
+		timed("accounts", processors.NewAccountsProcessor(s.historyQ)),
+		timed("offers", processors.NewOffersProcessor(s.historyQ, sequence)),
+		timed("asset_stats", processors.NewAssetStatsProcessor(s.historyQ, useLedgerCache)),
+		timed("signers", processors.NewSignersProcessor(s.historyQ, useLedgerCache)),
+		timed("trust_lines", processors.NewTrustLinesProcessor(s.historyQ)),
 	}
 }
 
@@ -117,14 +197,48 @@ func (s *ProcessorRunner) buildTransactionProcessor(
 	}
 
 	sequence := uint32(ledger.Header.LedgerSeq)
+
+	timed := func(name string, p horizonTransactionProcessor) horizonTransactionProcessor {
+		timer := s.processorTimers[name]
+		if timer == nil {
+			return p
+		}
+		return &timedTransactionProcessor{horizonTransactionProcessor: p, timer: timer, ctx: s.traceCtx, name: name}
+	}
+
+	// In state only mode the ledgers are the only history rows ingested, the
+	// transactions are only kept in memory.
+	if s.config.StateOnly {
+		group := groupTransactionProcessors{
+			statsLedgerTransactionProcessor,
+			timed("ledgers", processors.NewLedgerProcessor(s.historyQ, ledger, CurrentVersion)),
+		}
+		if s.config.RecentTransactions != nil {
+			group = append(group, processors.NewRecentTransactionsProcessor(s.config.RecentTransactions, ledger))
+		}
+		return group
+	}
+
+	// Ledgers are always ingested, the other history rows only for the
+	// transactions matching the history filter.
+	filtered := func(p horizonTransactionProcessor) horizonTransactionProcessor {
+		return p
+	}
+	if s.historyFilter != nil {
+		filter := &ledgerHistoryFilter{filter: s.historyFilter, sequence: sequence}
+		filtered = func(p horizonTransactionProcessor) horizonTransactionProcessor {
+			return &filteredTransactionProcessor{horizonTransactionProcessor: p, filter: filter}
+		}
+	}
+
 	return groupTransactionProcessors{
 		statsLedgerTransactionProcessor,
-		processors.NewEffectProcessor(s.historyQ, sequence),
-		processors.NewLedgerProcessor(s.historyQ, ledger, CurrentVersion),
-		processors.NewOperationProcessor(s.historyQ, sequence),
-		processors.NewTradeProcessor(s.historyQ, ledger),
-		processors.NewParticipantsProcessor(s.historyQ, sequence),
-		processors.NewTransactionProcessor(s.historyQ, sequence),
+		timed("effects", filtered(processors.NewEffectProcessor(s.historyQ, sequence))),
+		timed("ledgers", processors.NewLedgerProcessor(s.historyQ, ledger, CurrentVersion)),
+		timed("operations", filtered(processors.NewOperationProcessor(s.historyQ, sequence))),
+		timed("trades", filtered(processors.NewTradeProcessor(s.historyQ, ledger))),
+		timed("participants", filtered(processors.NewParticipantsProcessor(s.historyQ, sequence))),
+		timed("transactions", filtered(processors.NewTransactionProcessor(s.historyQ, sequence))),
 	}
 }
 
@@ -243,7 +357,27 @@ func (s *ProcessorRunner) runChangeProcessorOnLedger(
 	return nil
 }
 
+// startSpan starts the span `name` of the processing of `ledger`, which is
+// the parent of the spans of the processors until the returned function ends
+// it.
+func (s *ProcessorRunner) startSpan(name string, ledger uint32) func() {
+	parent := s.traceCtx
+	if parent == nil {
+		parent = s.ctx
+	}
+	ctx, span := tracing.Start(parent, name, trace.SpanKindInternal)
+	span.SetAttributes(attribute.Int64("ledger", int64(ledger)))
+
+	previous := s.traceCtx
+	s.traceCtx = ctx
+	return func() {
+		s.traceCtx = previous
+		span.End()
+	}
+}
+
 func (s *ProcessorRunner) RunTransactionProcessorsOnLedger(ledger uint32) (io.StatsLedgerTransactionProcessorResults, error) {
+	defer s.startSpan("ingest transactions", ledger)()
 	ledgerTransactionStats := io.StatsLedgerTransactionProcessor{}
 
 	transactionReader, err := io.NewLedgerTransactionReader(s.ledgerBackend, s.config.NetworkPassphrase, ledger)
@@ -266,6 +400,7 @@ func (s *ProcessorRunner) RunTransactionProcessorsOnLedger(ledger uint32) (io.St
 }
 
 func (s *ProcessorRunner) RunAllProcessorsOnLedger(sequence uint32) (io.StatsChangeProcessorResults, io.StatsLedgerTransactionProcessorResults, error) {
+	defer s.startSpan("ingest ledger", sequence)()
 	changeStats := io.StatsChangeProcessor{}
 	var statsLedgerTransactionProcessorResults io.StatsLedgerTransactionProcessorResults
 
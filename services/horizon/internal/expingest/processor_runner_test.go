@@ -259,6 +259,53 @@ func TestProcessorRunnerBuildTransactionProcessor(t *testing.T) {
 	assert.IsType(t, &processors.TransactionProcessor{}, processor.(groupTransactionProcessors)[6])
 }
 
+func TestProcessorRunnerBuildTimedProcessors(t *testing.T) {
+	q := &mockDBQ{}
+	defer mock.AssertExpectationsForObjects(t, q)
+
+	q.MockQOffers.On("NewOffersBatchInsertBuilder", 100000).
+		Return(&history.MockOffersBatchInsertBuilder{}).Twice()
+	q.MockQData.On("NewAccountDataBatchInsertBuilder", 100000).
+		Return(&history.MockAccountDataBatchInsertBuilder{}).Twice()
+	q.MockQSigners.On("NewAccountSignersBatchInsertBuilder", 100000).
+		Return(&history.MockAccountSignersBatchInsertBuilder{}).Twice()
+	q.MockQOperations.On("NewOperationBatchInsertBuilder", 100000).
+		Return(&history.MockOperationsBatchInsertBuilder{}).Once()
+	q.MockQTransactions.On("NewTransactionBatchInsertBuilder", 100000).
+		Return(&history.MockTransactionsBatchInsertBuilder{}).Once()
+
+	system := &System{}
+	system.initMetrics()
+	runner := ProcessorRunner{
+		historyQ:        q,
+		processorTimers: system.Metrics.ProcessorsRunDuration,
+	}
+
+	changeProcessor := runner.buildChangeProcessor(&io.StatsChangeProcessor{}, ledgerSource, 123)
+	assert.IsType(t, &statsChangeProcessor{}, changeProcessor.(groupChangeProcessors)[0])
+	for _, p := range changeProcessor.(groupChangeProcessors)[1:] {
+		assert.IsType(t, &timedChangeProcessor{}, p)
+	}
+
+	// history archive ingestion is not timed
+	changeProcessor = runner.buildChangeProcessor(&io.StatsChangeProcessor{}, historyArchiveSource, 123)
+	assert.IsType(t, &processors.AccountDataProcessor{}, changeProcessor.(groupChangeProcessors)[1])
+
+	transactionProcessor := runner.buildTransactionProcessor(
+		&io.StatsLedgerTransactionProcessor{},
+		xdr.LedgerHeaderHistoryEntry{},
+	)
+	assert.IsType(t, &statsLedgerTransactionProcessor{}, transactionProcessor.(groupTransactionProcessors)[0])
+	for _, p := range transactionProcessor.(groupTransactionProcessors)[1:] {
+		assert.IsType(t, &timedTransactionProcessor{}, p)
+	}
+	assert.IsType(
+		t,
+		&processors.EffectProcessor{},
+		transactionProcessor.(groupTransactionProcessors)[1].(*timedTransactionProcessor).horizonTransactionProcessor,
+	)
+}
+
 func TestProcessorRunnerRunAllProcessorsOnLedger(t *testing.T) {
 	maxBatchSize := 100000
 
@@ -332,3 +379,67 @@ func TestProcessorRunnerRunAllProcessorsOnLedger(t *testing.T) {
 	_, _, err := runner.RunAllProcessorsOnLedger(63)
 	assert.NoError(t, err)
 }
+
+func TestProcessorRunnerBuildTransactionProcessorWithHistoryFilter(t *testing.T) {
+	maxBatchSize := 100000
+
+	q := &mockDBQ{}
+	defer mock.AssertExpectationsForObjects(t, q)
+
+	q.MockQOperations.On("NewOperationBatchInsertBuilder", maxBatchSize).
+		Return(&history.MockOperationsBatchInsertBuilder{}).Twice() // Twice = with/without failed
+	q.MockQTransactions.On("NewTransactionBatchInsertBuilder", maxBatchSize).
+		Return(&history.MockTransactionsBatchInsertBuilder{}).Twice()
+
+	filter, err := processors.NewHistoryFilter(
+		[]string{"GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY"}, nil,
+	)
+	assert.NoError(t, err)
+	runner := ProcessorRunner{
+		config:        Config{},
+		historyQ:      q,
+		historyFilter: filter,
+	}
+
+	stats := &io.StatsLedgerTransactionProcessor{}
+	ledger := xdr.LedgerHeaderHistoryEntry{}
+	processor := runner.buildTransactionProcessor(stats, ledger)
+	assert.IsType(t, groupTransactionProcessors{}, processor)
+
+	group := processor.(groupTransactionProcessors)
+	assert.IsType(t, &statsLedgerTransactionProcessor{}, group[0])
+	assert.IsType(t, &processors.LedgersProcessor{}, group[2])
+	for _, i := range []int{1, 3, 4, 5, 6} {
+		assert.IsType(t, &filteredTransactionProcessor{}, group[i])
+	}
+	assert.IsType(t, &processors.EffectProcessor{}, group[1].(*filteredTransactionProcessor).horizonTransactionProcessor)
+	assert.IsType(t, &processors.TransactionProcessor{}, group[6].(*filteredTransactionProcessor).horizonTransactionProcessor)
+}
+
+type recentTransactionsStore struct{}
+
+func (recentTransactionsStore) AddLedger(uint32, []history.Transaction) {}
+
+func TestProcessorRunnerBuildTransactionProcessorStateOnly(t *testing.T) {
+	q := &mockDBQ{}
+	defer mock.AssertExpectationsForObjects(t, q)
+
+	runner := ProcessorRunner{
+		config:   Config{StateOnly: true},
+		historyQ: q,
+	}
+
+	stats := &io.StatsLedgerTransactionProcessor{}
+	ledger := xdr.LedgerHeaderHistoryEntry{}
+	processor := runner.buildTransactionProcessor(stats, ledger)
+	group := processor.(groupTransactionProcessors)
+	assert.Len(t, group, 2)
+	assert.IsType(t, &statsLedgerTransactionProcessor{}, group[0])
+	assert.IsType(t, &processors.LedgersProcessor{}, group[1])
+
+	runner.config.RecentTransactions = recentTransactionsStore{}
+	processor = runner.buildTransactionProcessor(stats, ledger)
+	group = processor.(groupTransactionProcessors)
+	assert.Len(t, group, 3)
+	assert.IsType(t, &processors.RecentTransactionsProcessor{}, group[2])
+}
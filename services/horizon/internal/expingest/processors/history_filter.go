@@ -0,0 +1,89 @@
+package processors
+
+import (
+	"github.com/stellar/go/exp/ingest/io"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// HistoryFilter selects the transactions whose history (transactions,
+// operations, effects, trades and participants) is ingested. A transaction
+// matches the filter when one of its participants is an allowlisted account or
+// when it changes a trust line or an offer of an allowlisted asset.
+type HistoryFilter struct {
+	accounts map[string]bool
+	assets   map[string]bool
+}
+
+// NewHistoryFilter returns a filter matching the transactions of `accounts`
+// and `assets`. The native asset cannot be allowlisted since almost all
+// transactions involve it.
+func NewHistoryFilter(accounts []string, assets []xdr.Asset) (*HistoryFilter, error) {
+	filter := &HistoryFilter{
+		accounts: map[string]bool{},
+		assets:   map[string]bool{},
+	}
+
+	for _, account := range accounts {
+		var aid xdr.AccountId
+		if err := aid.SetAddress(account); err != nil {
+			return nil, errors.Wrapf(err, "invalid account %s", account)
+		}
+		filter.accounts[aid.Address()] = true
+	}
+
+	for _, asset := range assets {
+		if asset.Type == xdr.AssetTypeAssetTypeNative {
+			return nil, errors.New("the native asset cannot be allowlisted")
+		}
+		filter.assets[asset.String()] = true
+	}
+
+	return filter, nil
+}
+
+// Match returns true when the history of `transaction`, included in ledger
+// `sequence`, must be ingested.
+func (f *HistoryFilter) Match(sequence uint32, transaction io.LedgerTransaction) (bool, error) {
+	if len(f.assets) > 0 {
+		changes, err := transaction.GetChanges()
+		if err != nil {
+			return false, errors.Wrap(err, "could not load transaction changes")
+		}
+		for _, change := range changes {
+			if f.matchEntry(change.Pre) || f.matchEntry(change.Post) {
+				return true, nil
+			}
+		}
+	}
+
+	if len(f.accounts) > 0 {
+		participants, err := participantsForTransaction(sequence, transaction)
+		if err != nil {
+			return false, errors.Wrap(err, "could not determine transaction participants")
+		}
+		for _, participant := range participants {
+			if f.accounts[participant.Address()] {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (f *HistoryFilter) matchEntry(entry *xdr.LedgerEntry) bool {
+	if entry == nil {
+		return false
+	}
+
+	switch entry.Data.Type {
+	case xdr.LedgerEntryTypeTrustline:
+		return f.assets[entry.Data.MustTrustLine().Asset.String()]
+	case xdr.LedgerEntryTypeOffer:
+		offer := entry.Data.MustOffer()
+		return f.assets[offer.Selling.String()] || f.assets[offer.Buying.String()]
+	default:
+		return false
+	}
+}
@@ -0,0 +1,70 @@
+package processors
+
+import (
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHistoryFilter(t *testing.T) {
+	_, err := NewHistoryFilter([]string{"GABC"}, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid account GABC")
+	}
+
+	_, err = NewHistoryFilter(nil, []xdr.Asset{xdr.MustNewNativeAsset()})
+	assert.EqualError(t, err, "the native asset cannot be allowlisted")
+}
+
+func TestHistoryFilterMatch(t *testing.T) {
+	usd := xdr.MustNewCreditAsset("USD", trustLineIssuer.Address())
+	eur := xdr.MustNewCreditAsset("EUR", trustLineIssuer.Address())
+	holder := xdr.MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB")
+
+	// createTransaction uses GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY
+	// as source account.
+	transaction := createTransaction(true, 1)
+	transaction.Meta = xdr.TransactionMeta{
+		V: 1,
+		V1: &xdr.TransactionMetaV1{
+			Operations: []xdr.OperationMeta{{
+				Changes: xdr.LedgerEntryChanges{
+					{
+						Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+						Created: &xdr.LedgerEntry{
+							Data: xdr.LedgerEntryData{
+								Type: xdr.LedgerEntryTypeTrustline,
+								TrustLine: &xdr.TrustLineEntry{
+									AccountId: holder,
+									Asset:     usd,
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		accounts []string
+		assets   []xdr.Asset
+		expected bool
+	}{
+		{"asset of a changed trust line", nil, []xdr.Asset{eur, usd}, true},
+		{"other asset", nil, []xdr.Asset{eur}, false},
+		{"source account", []string{"GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY"}, nil, true},
+		{"other account", []string{holder.Address()}, []xdr.Asset{eur}, false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			filter, err := NewHistoryFilter(testCase.accounts, testCase.assets)
+			assert.NoError(t, err)
+
+			match, err := filter.Match(20, transaction)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, match)
+		})
+	}
+}
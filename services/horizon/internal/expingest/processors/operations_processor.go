@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/stellar/go/amount"
 	"github.com/stellar/go/exp/ingest/io"
@@ -103,6 +104,15 @@ func (operation *transactionOperationWrapper) SourceAccount() *xdr.AccountId {
 	return &sa
 }
 
+// sourceAccountMuxed returns the operation's source account preserving its
+// multiplexing id, if any.
+func (operation *transactionOperationWrapper) sourceAccountMuxed() xdr.MuxedAccount {
+	if operation.operation.SourceAccount != nil {
+		return *operation.operation.SourceAccount
+	}
+	return operation.transaction.Envelope.SourceAccount()
+}
+
 // OperationType returns the operation type.
 func (operation *transactionOperationWrapper) OperationType() xdr.OperationType {
 	return operation.operation.Body.Type
@@ -119,6 +129,7 @@ func (operation *transactionOperationWrapper) OperationResult() *xdr.OperationRe
 func (operation *transactionOperationWrapper) Details() map[string]interface{} {
 	details := map[string]interface{}{}
 	source := operation.SourceAccount()
+	muxedAccountDetails(details, operation.sourceAccountMuxed(), "source_account_")
 
 	switch operation.OperationType() {
 	case xdr.OperationTypeCreateAccount:
@@ -131,6 +142,8 @@ func (operation *transactionOperationWrapper) Details() map[string]interface{} {
 		details["from"] = source.Address()
 		accid := op.Destination.ToAccountId()
 		details["to"] = accid.Address()
+		muxedAccountDetails(details, operation.sourceAccountMuxed(), "from_")
+		muxedAccountDetails(details, op.Destination, "to_")
 		details["amount"] = amount.String(op.Amount)
 		assetDetails(details, op.Asset, "")
 	case xdr.OperationTypePathPaymentStrictReceive:
@@ -138,6 +151,8 @@ func (operation *transactionOperationWrapper) Details() map[string]interface{} {
 		details["from"] = source.Address()
 		accid := op.Destination.ToAccountId()
 		details["to"] = accid.Address()
+		muxedAccountDetails(details, operation.sourceAccountMuxed(), "from_")
+		muxedAccountDetails(details, op.Destination, "to_")
 
 		details["amount"] = amount.String(op.DestAmount)
 		details["source_amount"] = amount.String(0)
@@ -162,6 +177,8 @@ func (operation *transactionOperationWrapper) Details() map[string]interface{} {
 		details["from"] = source.Address()
 		accid := op.Destination.ToAccountId()
 		details["to"] = accid.Address()
+		muxedAccountDetails(details, operation.sourceAccountMuxed(), "from_")
+		muxedAccountDetails(details, op.Destination, "to_")
 
 		details["amount"] = amount.String(0)
 		details["source_amount"] = amount.String(op.SendAmount)
@@ -291,6 +308,16 @@ func (operation *transactionOperationWrapper) Details() map[string]interface{} {
 }
 
 // assetDetails sets the details for `a` on `result` using keys with `prefix`
+// muxedAccountDetails adds the muxed address and the multiplexing id of a
+// muxed account using the given prefix. Nothing is added for plain accounts.
+func muxedAccountDetails(result map[string]interface{}, a xdr.MuxedAccount, prefix string) {
+	if a.Type != xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+		return
+	}
+	result[prefix+"muxed"] = a.Address()
+	result[prefix+"muxed_id"] = strconv.FormatUint(uint64(a.MustMed25519().Id), 10)
+}
+
 func assetDetails(result map[string]interface{}, a xdr.Asset, prefix string) error {
 	var (
 		assetType string
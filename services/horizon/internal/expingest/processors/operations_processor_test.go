@@ -168,9 +168,20 @@ func TestTransactionOperationWrapper_Details(t *testing.T) {
 		ledgerSequence: uint32(56),
 	}
 	assert.Equal(t, wrapper.Details(), map[string]interface{}{
-		"amount":     "0.0000100",
-		"asset_type": "native",
-		"from":       "GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY",
-		"to":         "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2",
+		"amount":      "0.0000100",
+		"asset_type":  "native",
+		"from":        "GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY",
+		"to":          "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2",
+		"to_muxed":    "MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK",
+		"to_muxed_id": "16045690984833335023",
 	})
+
+	tx.Envelope.V1.Tx.SourceAccount = muxed
+	wrapper.transaction = tx
+	details := wrapper.Details()
+	assert.Equal(t, "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", details["from"])
+	assert.Equal(t, "MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK", details["from_muxed"])
+	assert.Equal(t, "16045690984833335023", details["from_muxed_id"])
+	assert.Equal(t, "MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK", details["source_account_muxed"])
+	assert.Equal(t, "16045690984833335023", details["source_account_muxed_id"])
 }
@@ -0,0 +1,53 @@
+package processors
+
+import (
+	"time"
+
+	"github.com/stellar/go/exp/ingest/io"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// RecentTransactionsStore keeps the transactions of the latest ingested
+// ledgers in memory.
+type RecentTransactionsStore interface {
+	AddLedger(sequence uint32, transactions []history.Transaction)
+}
+
+// RecentTransactionsProcessor hands the transactions of a ledger to a
+// RecentTransactionsStore instead of inserting them in the history tables. It
+// is used when the history is not ingested so that the results of submitted
+// transactions can still be found.
+type RecentTransactionsProcessor struct {
+	store        RecentTransactionsStore
+	sequence     uint32
+	closeTime    time.Time
+	transactions []history.Transaction
+}
+
+func NewRecentTransactionsProcessor(
+	store RecentTransactionsStore,
+	ledger xdr.LedgerHeaderHistoryEntry,
+) *RecentTransactionsProcessor {
+	return &RecentTransactionsProcessor{
+		store:     store,
+		sequence:  uint32(ledger.Header.LedgerSeq),
+		closeTime: time.Unix(int64(ledger.Header.ScpValue.CloseTime), 0).UTC(),
+	}
+}
+
+func (p *RecentTransactionsProcessor) ProcessTransaction(transaction io.LedgerTransaction) error {
+	row, err := history.TransactionFromLedger(transaction, p.sequence, p.closeTime)
+	if err != nil {
+		return errors.Wrap(err, "Error building transaction row")
+	}
+
+	p.transactions = append(p.transactions, row)
+	return nil
+}
+
+func (p *RecentTransactionsProcessor) Commit() error {
+	p.store.AddLedger(p.sequence, p.transactions)
+	return nil
+}
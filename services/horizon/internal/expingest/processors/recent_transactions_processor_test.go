@@ -0,0 +1,56 @@
+package processors
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recentTransactionsStore struct {
+	sequence     uint32
+	transactions []history.Transaction
+}
+
+func (s *recentTransactionsStore) AddLedger(sequence uint32, transactions []history.Transaction) {
+	s.sequence = sequence
+	s.transactions = transactions
+}
+
+func TestRecentTransactionsProcessor(t *testing.T) {
+	store := &recentTransactionsStore{}
+	processor := NewRecentTransactionsProcessor(store, xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq: xdr.Uint32(20),
+			ScpValue:  xdr.StellarValue{CloseTime: 1000},
+		},
+	})
+
+	first := createTransaction(true, 1)
+	first.Index = 1
+	first.Result.TransactionHash = xdr.Hash{1}
+	first.Result.Result.Result.Results = &[]xdr.OperationResult{}
+	first.Meta = xdr.TransactionMeta{V: 1, V1: &xdr.TransactionMetaV1{}}
+	second := createTransaction(false, 2)
+	second.Index = 2
+	second.Result.TransactionHash = xdr.Hash{2}
+	second.Result.Result.Result.Results = &[]xdr.OperationResult{}
+	second.Meta = xdr.TransactionMeta{V: 1, V1: &xdr.TransactionMetaV1{}}
+
+	require.NoError(t, processor.ProcessTransaction(first))
+	require.NoError(t, processor.ProcessTransaction(second))
+	assert.Empty(t, store.transactions)
+
+	require.NoError(t, processor.Commit())
+	assert.Equal(t, uint32(20), store.sequence)
+	require.Len(t, store.transactions, 2)
+	assert.Equal(t, hex.EncodeToString(first.Result.TransactionHash[:]), store.transactions[0].TransactionHash)
+	assert.True(t, store.transactions[0].Successful)
+	assert.Equal(t, int32(20), store.transactions[1].LedgerSequence)
+	assert.False(t, store.transactions[1].Successful)
+	assert.Equal(t, time.Unix(1000, 0).UTC(), store.transactions[1].LedgerCloseTime)
+}
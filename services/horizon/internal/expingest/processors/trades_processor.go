@@ -81,6 +81,11 @@ func (p *TradeProcessor) Commit() error {
 		if err = batch.Exec(); err != nil {
 			return errors.Wrap(err, "Error flushing operation batch")
 		}
+
+		closeTime := time.Unix(int64(p.ledger.Header.ScpValue.CloseTime), 0).UTC()
+		if err = p.tradesQ.RebuildTradeRollups(closeTime, closeTime); err != nil {
+			return errors.Wrap(err, "Error rebuilding trade rollups")
+		}
 	}
 
 	return nil
@@ -516,6 +516,8 @@ func (s *TradeProcessorTestSuiteLedger) TestIngestTradesSucceeds() {
 	}
 
 	s.mockBatchInsertBuilder.On("Exec").Return(nil).Once()
+	s.mockQ.On("RebuildTradeRollups", inserts[0].LedgerCloseTime, inserts[0].LedgerCloseTime).
+		Return(nil).Once()
 
 	for _, tx := range s.txs {
 		err := s.processor.ProcessTransaction(tx)
@@ -644,6 +646,28 @@ func (s *TradeProcessorTestSuiteLedger) TestBatchExecError() {
 	s.Assert().EqualError(err, "Error flushing operation batch: exec error")
 }
 
+func (s *TradeProcessorTestSuiteLedger) TestRebuildTradeRollupsError() {
+	insert := s.mockReadTradeTransactions(s.processor.ledger)
+
+	s.mockQ.On("CreateAccounts", mock.AnythingOfType("[]string"), maxBatchSize).
+		Return(s.unmuxedAccountToID, nil).Once()
+	s.mockQ.On("CreateAssets", mock.AnythingOfType("[]xdr.Asset"), maxBatchSize).
+		Return(s.assetToID, nil).Once()
+	s.mockBatchInsertBuilder.On("Add", mock.AnythingOfType("[]history.InsertTrade")).
+		Return(nil).Times(len(insert))
+	s.mockBatchInsertBuilder.On("Exec").Return(nil).Once()
+	s.mockQ.On("RebuildTradeRollups", insert[0].LedgerCloseTime, insert[0].LedgerCloseTime).
+		Return(fmt.Errorf("rollups error")).Once()
+
+	for _, tx := range s.txs {
+		err := s.processor.ProcessTransaction(tx)
+		s.Assert().NoError(err)
+	}
+
+	err := s.processor.Commit()
+	s.Assert().EqualError(err, "Error rebuilding trade rollups: rollups error")
+}
+
 func (s *TradeProcessorTestSuiteLedger) TestIgnoreCheckIfSmallLedger() {
 	insert := s.mockReadTradeTransactions(s.processor.ledger)
 
@@ -668,6 +692,8 @@ func (s *TradeProcessorTestSuiteLedger) TestIgnoreCheckIfSmallLedger() {
 	s.mockBatchInsertBuilder.On("Add", mock.AnythingOfType("[]history.InsertTrade")).
 		Return(nil).Times(len(insert))
 	s.mockBatchInsertBuilder.On("Exec").Return(nil).Once()
+	s.mockQ.On("RebuildTradeRollups", insert[0].LedgerCloseTime, insert[0].LedgerCloseTime).
+		Return(nil).Once()
 
 	for _, tx := range s.txs {
 		err := s.processor.ProcessTransaction(tx)
@@ -0,0 +1,239 @@
+package expingest
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/lib/pq"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/historyarchive"
+	logpkg "github.com/stellar/go/support/log"
+)
+
+// snapshotTables are the state tables included in a snapshot.
+var snapshotTables = []string{
+	"accounts",
+	"accounts_data",
+	"accounts_signers",
+	"exp_asset_stats",
+	"offers",
+	"trust_lines",
+}
+
+// snapshotNull is the representation of NULL values in snapshot files.
+const snapshotNull = `\N`
+
+// SnapshotManifest describes a snapshot of the state tables at a checkpoint
+// ledger. A snapshot of checkpoint C published at URL U consists of
+// U/C/manifest.json and one U/C/<table>.csv.gz file per state table, in the
+// format produced by:
+//
+//	COPY <table> TO STDOUT WITH (FORMAT csv, HEADER, NULL '\N')
+type SnapshotManifest struct {
+	CheckpointLedger  uint32   `json:"checkpoint_ledger"`
+	IngestVersion     int      `json:"ingest_version"`
+	NetworkPassphrase string   `json:"network_passphrase"`
+	Tables            []string `json:"tables"`
+}
+
+func (m SnapshotManifest) validate(checkpoint uint32, networkPassphrase string) error {
+	if m.CheckpointLedger != checkpoint {
+		return errors.Errorf("snapshot is for checkpoint %d instead of %d", m.CheckpointLedger, checkpoint)
+	}
+	if m.IngestVersion != CurrentVersion {
+		return errors.Errorf(
+			"snapshot was created by ingestion version %d, current version is %d",
+			m.IngestVersion, CurrentVersion,
+		)
+	}
+	if m.NetworkPassphrase != networkPassphrase {
+		return errors.Errorf("snapshot is for network %q", m.NetworkPassphrase)
+	}
+
+	tables := map[string]bool{}
+	for _, table := range m.Tables {
+		tables[table] = true
+	}
+	for _, table := range snapshotTables {
+		if !tables[table] {
+			return errors.Errorf("snapshot is missing table %s", table)
+		}
+	}
+	return nil
+}
+
+// RestoreSnapshot initializes the state tables of an empty horizon database
+// from the snapshot of `checkpoint` published at `snapshotURL` (a file, http
+// or https URL) instead of ingesting the checkpoint from the history archives.
+// Ingestion then resumes from the ledger following the checkpoint. The
+// restored state is checked by the state verifier like an ingested one.
+func RestoreSnapshot(session *db.Session, snapshotURL string, checkpoint uint32, networkPassphrase string) error {
+	if !historyarchive.IsCheckpoint(checkpoint) {
+		return errors.Errorf("%d is not a checkpoint ledger", checkpoint)
+	}
+
+	source, err := newSnapshotSource(snapshotURL, checkpoint)
+	if err != nil {
+		return err
+	}
+
+	var manifest SnapshotManifest
+	err = source.read("manifest.json", func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&manifest)
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not load snapshot manifest")
+	}
+	if err = manifest.validate(checkpoint, networkPassphrase); err != nil {
+		return errors.Wrap(err, "invalid snapshot")
+	}
+
+	historyQ := &history.Q{session.Clone()}
+	if err = historyQ.Begin(); err != nil {
+		return errors.Wrap(err, "Error starting a transaction")
+	}
+	defer historyQ.Rollback()
+
+	// This will get the value `FOR UPDATE`, blocking ingestion.
+	lastIngestedLedger, err := historyQ.GetLastLedgerExpIngest()
+	if err != nil {
+		return errors.Wrap(err, getLastIngestedErrMsg)
+	}
+	if lastIngestedLedger != 0 {
+		return errors.Errorf(
+			"the database already contains the state of ledger %d, a snapshot can only be restored into an empty database",
+			lastIngestedLedger,
+		)
+	}
+
+	if err = historyQ.TruncateExpingestStateTables(); err != nil {
+		return errors.Wrap(err, "Error clearing ingest tables")
+	}
+
+	for _, table := range snapshotTables {
+		var rows int
+		err = source.read(table+".csv.gz", func(r io.Reader) error {
+			rows, err = copySnapshotTable(historyQ, table, r)
+			return err
+		})
+		if err != nil {
+			return errors.Wrapf(err, "could not restore table %s", table)
+		}
+		log.WithFields(logpkg.F{"table": table, "rows": rows}).Info("Restored table")
+	}
+
+	if err = historyQ.UpdateExpStateInvalid(false); err != nil {
+		return errors.Wrap(err, updateExpStateInvalidErrMsg)
+	}
+	if err = historyQ.UpdateExpIngestVersion(manifest.IngestVersion); err != nil {
+		return errors.Wrap(err, "Error updating expingest version")
+	}
+	if err = historyQ.UpdateLastLedgerExpIngest(checkpoint); err != nil {
+		return errors.Wrap(err, updateLastLedgerExpIngestErrMsg)
+	}
+	return errors.Wrap(historyQ.Commit(), commitErrMsg)
+}
+
+// copySnapshotTable copies the gzipped csv `r` into `table`. The header of the
+// file lists the columns of its rows.
+func copySnapshotTable(historyQ *history.Q, table string, r io.Reader) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not decompress file")
+	}
+	defer gz.Close()
+
+	reader := csv.NewReader(gz)
+	columns, err := reader.Read()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not read header")
+	}
+
+	stmt, err := historyQ.GetTx().Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return 0, errors.Wrap(err, "could not prepare copy")
+	}
+	defer stmt.Close()
+
+	rows := 0
+	values := make([]interface{}, len(columns))
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, errors.Wrapf(err, "could not read row %d", rows+1)
+		}
+
+		for i, value := range record {
+			if value == snapshotNull {
+				values[i] = nil
+			} else {
+				values[i] = value
+			}
+		}
+		if _, err = stmt.Exec(values...); err != nil {
+			return rows, errors.Wrapf(err, "could not copy row %d", rows+1)
+		}
+		rows++
+	}
+
+	// flushes the copied rows
+	if _, err = stmt.Exec(); err != nil {
+		return rows, errors.Wrap(err, "could not copy rows")
+	}
+	return rows, nil
+}
+
+// snapshotSource reads the files of a snapshot.
+type snapshotSource struct {
+	base *url.URL
+}
+
+func newSnapshotSource(snapshotURL string, checkpoint uint32) (snapshotSource, error) {
+	base, err := url.Parse(snapshotURL)
+	if err != nil {
+		return snapshotSource{}, errors.Wrap(err, "invalid snapshot url")
+	}
+	if base.Scheme != "file" && base.Scheme != "http" && base.Scheme != "https" {
+		return snapshotSource{}, errors.Errorf("unsupported snapshot url scheme %q", base.Scheme)
+	}
+
+	base.Path = path.Join(base.Path, fmt.Sprintf("%d", checkpoint))
+	return snapshotSource{base: base}, nil
+}
+
+// read passes the content of the snapshot file `name` to `fn`.
+func (s snapshotSource) read(name string, fn func(io.Reader) error) error {
+	if s.base.Scheme == "file" {
+		f, err := os.Open(filepath.Join(filepath.FromSlash(s.base.Path), name))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return fn(f)
+	}
+
+	u := *s.base
+	u.Path = path.Join(u.Path, name)
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %s fetching %s", resp.Status, u.String())
+	}
+	return fn(resp.Body)
+}
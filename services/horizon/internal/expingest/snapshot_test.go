@@ -0,0 +1,85 @@
+package expingest
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotManifestValidate(t *testing.T) {
+	manifest := SnapshotManifest{
+		CheckpointLedger:  63,
+		IngestVersion:     CurrentVersion,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		Tables:            snapshotTables,
+	}
+	assert.NoError(t, manifest.validate(63, network.TestNetworkPassphrase))
+
+	assert.EqualError(
+		t,
+		manifest.validate(127, network.TestNetworkPassphrase),
+		"snapshot is for checkpoint 63 instead of 127",
+	)
+	assert.EqualError(
+		t,
+		manifest.validate(63, network.PublicNetworkPassphrase),
+		`snapshot is for network "Test SDF Network ; September 2015"`,
+	)
+
+	manifest.Tables = snapshotTables[1:]
+	assert.EqualError(
+		t,
+		manifest.validate(63, network.TestNetworkPassphrase),
+		"snapshot is missing table accounts",
+	)
+
+	manifest.Tables = snapshotTables
+	manifest.IngestVersion = CurrentVersion - 1
+	assert.Error(t, manifest.validate(63, network.TestNetworkPassphrase))
+}
+
+func TestSnapshotSource(t *testing.T) {
+	_, err := newSnapshotSource("s3://bucket/snapshots", 63)
+	assert.EqualError(t, err, `unsupported snapshot url scheme "s3"`)
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "63"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "63", "manifest.json"), []byte("{}"), 0644))
+
+	readAll := func(source snapshotSource, name string) (string, error) {
+		var content []byte
+		err := source.read(name, func(r io.Reader) error {
+			var err error
+			content, err = ioutil.ReadAll(r)
+			return err
+		})
+		return string(content), err
+	}
+
+	source, err := newSnapshotSource("file://"+filepath.ToSlash(dir), 63)
+	assert.NoError(t, err)
+	content, err := readAll(source, "manifest.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", content)
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	source, err = newSnapshotSource(server.URL+"/", 63)
+	assert.NoError(t, err)
+	content, err = readAll(source, "manifest.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", content)
+
+	_, err = readAll(source, "accounts.csv.gz")
+	assert.Error(t, err)
+}
@@ -269,3 +269,36 @@ func (s *VerifyRangeStateTestSuite) TestSuccessWithVerify() {
 	)
 	clonedQ.AssertExpectations(s.T())
 }
+
+func (s *VerifyRangeStateTestSuite) TestResume() {
+	s.historyQ.On("Begin").Return(nil).Once()
+	s.historyQ.On("GetLastLedgerExpIngest").Return(uint32(150), nil).Once()
+	s.historyQ.On("Rollback").Return(nil).Once()
+
+	for i := uint32(151); i <= 200; i++ {
+		s.historyQ.On("Begin").Return(nil).Once()
+		s.runner.On("RunAllProcessorsOnLedger", i).Return(ingestio.StatsChangeProcessorResults{},
+			ingestio.StatsLedgerTransactionProcessorResults{}, nil).Once()
+		s.historyQ.On("UpdateLastLedgerExpIngest", i).Return(nil).Once()
+		s.historyQ.On("Commit").Return(nil).Once()
+	}
+
+	next, err := verifyRangeState{fromLedger: 100, toLedger: 200, resume: true}.run(s.system)
+	s.Assert().NoError(err)
+	s.Assert().Equal(
+		transition{node: stopState{}, sleepDuration: 0},
+		next,
+	)
+}
+
+func (s *VerifyRangeStateTestSuite) TestResumeOutsideOfRange() {
+	s.historyQ.On("Begin").Return(nil).Once()
+	s.historyQ.On("GetLastLedgerExpIngest").Return(uint32(63), nil).Once()
+
+	next, err := verifyRangeState{fromLedger: 100, toLedger: 200, resume: true}.run(s.system)
+	s.Assert().EqualError(err, "cannot resume verification, last ingested ledger 63 is outside of the range")
+	s.Assert().Equal(
+		transition{node: stopState{}, sleepDuration: 0},
+		next,
+	)
+}
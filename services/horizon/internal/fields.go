@@ -0,0 +1,143 @@
+package horizon
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/services/horizon/internal/render"
+)
+
+// fieldsParam is the query param listing the fields a client wants in the
+// response. Nested fields are selected with dots, e.g. `balances.balance`.
+const fieldsParam = "fields"
+
+// fieldsMiddleware prunes the successful JSON responses of requests which
+// carry a `fields` query param down to the requested fields. For pages, the
+// selection applies to each of the embedded records while the links of the
+// page are kept so that clients can still paginate. Streams are not pruned.
+func fieldsMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := parseFields(r.URL.Query().Get(fieldsParam))
+		streaming := strings.Contains(r.Header.Get("Accept"), render.MimeEventStream)
+		if len(fields) == 0 || streaming {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		fw := &fieldsResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(fw, r)
+
+		body := fw.body.Bytes()
+		if fw.status == http.StatusOK && strings.Contains(w.Header().Get("Content-Type"), "json") {
+			if pruned, err := selectFields(body, fields); err == nil {
+				body = pruned
+			}
+		}
+
+		w.WriteHeader(fw.status)
+		w.Write(body)
+	})
+}
+
+// fieldsResponseWriter buffers a response so that it can be pruned once the
+// handler is done with it.
+type fieldsResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *fieldsResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *fieldsResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// parseFields splits a comma separated list of fields into their paths.
+func parseFields(param string) [][]string {
+	var fields [][]string
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		fields = append(fields, strings.Split(field, "."))
+	}
+	return fields
+}
+
+// selectFields prunes the json document `doc` down to `fields`. When `doc` is
+// a HAL page, the fields are selected from each of its records.
+func selectFields(doc []byte, fields [][]string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	// keep numbers as they were rendered, without going through float64
+	decoder.UseNumber()
+
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	embedded, isPage := object["_embedded"].(map[string]interface{})
+	if records, ok := embedded["records"].([]interface{}); isPage && ok {
+		for i, record := range records {
+			if recordObject, ok := record.(map[string]interface{}); ok {
+				records[i] = pickFields(recordObject, fields)
+			}
+		}
+	} else {
+		object = pickFields(object, fields)
+	}
+
+	return json.MarshalIndent(object, "", "  ")
+}
+
+// pickFields returns a copy of `src` with only the given fields.
+func pickFields(src map[string]interface{}, fields [][]string) map[string]interface{} {
+	dst := map[string]interface{}{}
+	for _, path := range fields {
+		pickField(dst, src, path)
+	}
+	return dst
+}
+
+// pickField copies the field at `path` from `src` into `dst`. Fields nested in
+// arrays of objects are selected from each of the objects.
+func pickField(dst, src map[string]interface{}, path []string) {
+	value, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = value
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		nested, ok := dst[path[0]].(map[string]interface{})
+		if !ok {
+			nested = map[string]interface{}{}
+			dst[path[0]] = nested
+		}
+		pickField(nested, value, path[1:])
+	case []interface{}:
+		nested, ok := dst[path[0]].([]interface{})
+		if !ok {
+			nested = make([]interface{}, len(value))
+			for i := range nested {
+				nested[i] = map[string]interface{}{}
+			}
+			dst[path[0]] = nested
+		}
+		for i, element := range value {
+			if elementObject, ok := element.(map[string]interface{}); ok {
+				pickField(nested[i].(map[string]interface{}), elementObject, path[1:])
+			}
+		}
+	}
+}
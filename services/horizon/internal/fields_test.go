@@ -0,0 +1,95 @@
+package horizon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectFields(t *testing.T) {
+	fields := parseFields("id, balances.balance,missing,,thresholds.low_threshold")
+	assert.Equal(t, [][]string{
+		{"id"},
+		{"balances", "balance"},
+		{"missing"},
+		{"thresholds", "low_threshold"},
+	}, fields)
+
+	account := `{
+		"id": "GA",
+		"sequence": "123",
+		"balances": [
+			{"balance": "10.0000000", "asset_type": "native"},
+			{"balance": "1.0000000", "asset_type": "credit_alphanum4"}
+		],
+		"thresholds": {"low_threshold": 1, "med_threshold": 2}
+	}`
+
+	t.Run("object", func(t *testing.T) {
+		pruned, err := selectFields([]byte(account), fields)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"id": "GA",
+			"balances": [{"balance": "10.0000000"}, {"balance": "1.0000000"}],
+			"thresholds": {"low_threshold": 1}
+		}`, string(pruned))
+	})
+
+	t.Run("page", func(t *testing.T) {
+		page := `{
+			"_links": {"next": {"href": "/accounts?cursor=GA"}},
+			"_embedded": {"records": [` + account + `]}
+		}`
+		pruned, err := selectFields([]byte(page), [][]string{{"id"}})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"_links": {"next": {"href": "/accounts?cursor=GA"}},
+			"_embedded": {"records": [{"id": "GA"}]}
+		}`, string(pruned))
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := selectFields([]byte("not json"), fields)
+		assert.Error(t, err)
+	})
+}
+
+func TestFieldsMiddleware(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	w := ht.Get("/ledgers/2?fields=sequence,hash")
+	if ht.Assert.Equal(200, w.Code) {
+		var ledger map[string]interface{}
+		ht.Require.NoError(json.Unmarshal(w.Body.Bytes(), &ledger))
+		ht.Assert.Len(ledger, 2)
+		ht.Assert.Equal(float64(2), ledger["sequence"])
+		ht.Assert.Contains(ledger, "hash")
+	}
+
+	w = ht.Get("/ledgers?fields=sequence&limit=2")
+	if ht.Assert.Equal(200, w.Code) {
+		var page struct {
+			Links    map[string]interface{} `json:"_links"`
+			Embedded struct {
+				Records []map[string]interface{} `json:"records"`
+			} `json:"_embedded"`
+		}
+		ht.Require.NoError(json.Unmarshal(w.Body.Bytes(), &page))
+		ht.Assert.Contains(page.Links, "next")
+		ht.Assert.Len(page.Embedded.Records, 2)
+		for _, record := range page.Embedded.Records {
+			ht.Assert.Len(record, 1)
+			ht.Assert.Contains(record, "sequence")
+		}
+	}
+
+	// errors are not pruned
+	w = ht.Get("/ledgers/100?fields=sequence")
+	if ht.Assert.Equal(404, w.Code) {
+		var p map[string]interface{}
+		ht.Require.NoError(json.Unmarshal(w.Body.Bytes(), &p))
+		ht.Assert.Contains(p, "title")
+	}
+}
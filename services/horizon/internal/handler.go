@@ -21,7 +21,6 @@ import (
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/render/sse"
 	"github.com/stellar/go/services/horizon/internal/toid"
-	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
@@ -82,7 +81,7 @@ func (we *web) streamHandler(jfn interface{}, sfn streamFunc, params interface{}
 		stream := sse.NewStream(ctx, w)
 		var oldHash [32]byte
 		for {
-			lastLedgerState := ledger.CurrentState()
+			lastLedgerState := ledger.FromContext(ctx).CurrentState()
 
 			// Rate limit the request if it's a call to stream since it queries the DB every second. See
 			// https://github.com/stellar/go/issues/715 for more details.
@@ -154,7 +153,7 @@ func (we *web) streamHandler(jfn interface{}, sfn streamFunc, params interface{}
 						return
 					}
 
-					currentLedgerState := ledger.CurrentState()
+					currentLedgerState := ledger.FromContext(ctx).CurrentState()
 					if currentLedgerState.HistoryLatest >= lastLedgerState.HistoryLatest+1 {
 						newLedgers <- true
 						return
@@ -206,7 +205,7 @@ func (we *web) streamIndexActionHandler(jfn interface{}, sfn streamFunc) http.Ha
 			return
 		}
 
-		err = validateCursorWithinHistory(params.PagingParams)
+		err = validateCursorWithinHistory(ctx, params.PagingParams)
 		if err != nil {
 			problem.Render(ctx, w, err)
 			return
@@ -255,12 +254,13 @@ func getAccountID(r *http.Request, key string, required bool) (string, error) {
 		return val, nil
 	}
 
-	if _, err = strkey.Decode(strkey.VersionByteAccountID, val); err != nil {
+	address, err := actions.ResolveAccountAddress(val)
+	if err != nil {
 		// TODO: add errInvalidValue
 		return "", problem.MakeInvalidFieldProblem(key, errors.New("invalid address"))
 	}
 
-	return val, nil
+	return address, nil
 }
 
 // getShowActionQueryParams gets the available query params for all non-indexable endpoints.
@@ -308,18 +308,24 @@ func getIndexActionQueryParams(r *http.Request) (*indexActionQueryParams, error)
 		return nil, errors.Wrap(err, "getting include_failed param")
 	}
 
+	closedWithin, err := getTimeRange(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting time range")
+	}
+
 	return &indexActionQueryParams{
 		AccountID:        addr,
 		LedgerID:         lid,
 		PagingParams:     pq,
 		IncludeFailedTxs: includeFailedTx,
+		ClosedWithin:     closedWithin,
 	}, nil
 }
 
 // validateCursorWithinHistory first checks whether the cursor in the page
 // param is valid basesd on the order then verifies whether the cursor is
 // within history.
-func validateCursorWithinHistory(pq db2.PageQuery) error {
+func validateCursorWithinHistory(ctx context.Context, pq db2.PageQuery) error {
 	// an ascending query should never return a gone response:  An ascending query
 	// prior to known history should return results at the beginning of history,
 	// and an ascending query beyond the end of history should not error out but
@@ -343,7 +349,7 @@ func validateCursorWithinHistory(pq db2.PageQuery) error {
 		return problem.MakeInvalidFieldProblem(actions.ParamCursor, errors.New("invalid value"))
 	}
 
-	elder := toid.New(ledger.CurrentState().HistoryElder, 0, 0)
+	elder := toid.New(ledger.FromContext(ctx).CurrentState().HistoryElder, 0, 0)
 	if cursor <= elder.ToInt64() {
 		return &hProblem.BeforeHistory
 	}
@@ -3,10 +3,13 @@ package httpx
 import (
 	"context"
 	"net/url"
+
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
 )
 
 // BaseURL returns the "base" url for this request, defined as a url containing
-// the Host and Scheme portions of the request uri.
+// the Host and Scheme portions of the request uri and the path prefix under
+// which horizon is mounted, if any.
 func BaseURL(ctx context.Context) *url.URL {
 	r := RequestFromContext(ctx)
 	if r == nil {
@@ -26,5 +29,19 @@ func BaseURL(ctx context.Context) *url.URL {
 	return &url.URL{
 		Scheme: scheme,
 		Host:   r.Host,
+		Path:   PathPrefixFromContext(ctx),
 	}
 }
+
+// WithPathPrefix returns a copy of ctx recording the path prefix under which
+// horizon is mounted.
+func WithPathPrefix(ctx context.Context, prefix string) context.Context {
+	return context.WithValue(ctx, &horizonContext.PathPrefixContextKey, prefix)
+}
+
+// PathPrefixFromContext returns the path prefix recorded in ctx, or an empty
+// string if horizon is served from the root path.
+func PathPrefixFromContext(ctx context.Context) string {
+	prefix, _ := ctx.Value(&horizonContext.PathPrefixContextKey).(string)
+	return prefix
+}
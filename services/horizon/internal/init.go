@@ -2,7 +2,12 @@ package horizon
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/getsentry/raven-go"
 	"github.com/rcrowley/go-metrics"
@@ -11,86 +16,247 @@ import (
 	"github.com/stellar/go/services/horizon/internal/db2/core"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/expingest"
+	"github.com/stellar/go/services/horizon/internal/paths"
 	"github.com/stellar/go/services/horizon/internal/simplepath"
 	"github.com/stellar/go/services/horizon/internal/txsub"
 	results "github.com/stellar/go/services/horizon/internal/txsub/results/db"
 	"github.com/stellar/go/services/horizon/internal/txsub/sequence"
 	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/tracing"
 )
 
-func mustNewDBSession(databaseURL string, maxIdle, maxOpen int) *db.Session {
-	session, err := db.Open("postgres", databaseURL)
+func mustNewDBSession(databaseURL string, maxIdle, maxOpen int, maxLifetime time.Duration) *db.Session {
+	session, err := newDBSession(databaseURL, maxIdle, maxOpen, maxLifetime)
 	if err != nil {
 		log.Fatalf("cannot open Horizon DB: %v", err)
 	}
+	return session
+}
+
+func newDBSession(databaseURL string, maxIdle, maxOpen int, maxLifetime time.Duration) (*db.Session, error) {
+	session, err := db.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, err
+	}
 
 	session.DB.SetMaxIdleConns(maxIdle)
 	session.DB.SetMaxOpenConns(maxOpen)
-	return session
+	session.DB.SetConnMaxLifetime(maxLifetime)
+	return session, nil
 }
 
-func mustInitHorizonDB(app *App) {
-	maxIdle := app.config.HorizonDBMaxIdleConnections
-	maxOpen := app.config.HorizonDBMaxOpenConnections
-	if app.config.Ingest {
+// withStatementTimeout returns `databaseURL` with the statement_timeout
+// parameter set to `timeout`. Postgres then aborts the queries of the
+// connections which run longer. A zero timeout leaves the URL unchanged.
+func withStatementTimeout(databaseURL string, timeout time.Duration) (string, error) {
+	if timeout == 0 {
+		return databaseURL, nil
+	}
+
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse database url")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		// key=value connection string
+		return fmt.Sprintf("%s statement_timeout=%d", databaseURL, timeout.Milliseconds()), nil
+	}
+
+	query := u.Query()
+	query.Set("statement_timeout", strconv.FormatInt(timeout.Milliseconds(), 10))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// mustNewAPIDBSession opens a session used by the API and the other
+// non-ingestion tasks, whose queries are subject to Config.DBStatementTimeout.
+func mustNewAPIDBSession(app *App, databaseURL string, maxIdle, maxOpen int) *db.Session {
+	databaseURL, err := withStatementTimeout(databaseURL, app.config.DBStatementTimeout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return mustNewDBSession(databaseURL, maxIdle, maxOpen, app.config.DBConnectionMaxLifetime)
+}
+
+// apiDBPoolSize returns the size of the connection pools of the API to the
+// horizon and stellar-core databases, which share the configured number of
+// connections with ingestion.
+func (a *App) apiDBPoolSize(maxIdle, maxOpen int, database string) (int, int, error) {
+	if a.config.Ingest {
 		maxIdle -= expingest.MaxDBConnections
 		maxOpen -= expingest.MaxDBConnections
 		if maxIdle <= 0 {
-			log.Fatalf("max idle connections to horizon db must be greater than %d", expingest.MaxDBConnections)
+			return 0, 0, errors.Errorf("max idle connections to %s db must be greater than %d", database, expingest.MaxDBConnections)
 		}
 		if maxOpen <= 0 {
-			log.Fatalf("max open connections to horizon db must be greater than %d", expingest.MaxDBConnections)
+			return 0, 0, errors.Errorf("max open connections to %s db must be greater than %d", database, expingest.MaxDBConnections)
 		}
 	}
+	return maxIdle, maxOpen, nil
+}
 
-	app.historyQ = &history.Q{mustNewDBSession(
+func mustInitHorizonDB(app *App) {
+	maxIdle, maxOpen, err := app.apiDBPoolSize(
+		app.config.HorizonDBMaxIdleConnections,
+		app.config.HorizonDBMaxOpenConnections,
+		"horizon",
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.historyQ = &history.Q{mustNewAPIDBSession(
+		app,
 		app.config.DatabaseURL,
 		maxIdle,
 		maxOpen,
 	)}
 }
 
+// mustInitReadReplicas opens the read replicas of the horizon database, if any.
+// Every replica has a connection pool of the size of the horizon database one.
+func mustInitReadReplicas(app *App) {
+	if len(app.config.ReadReplicaDatabaseURLs) == 0 {
+		return
+	}
+
+	var sessions []*db.Session
+	for _, databaseURL := range app.config.ReadReplicaDatabaseURLs {
+		sessions = append(sessions, mustNewAPIDBSession(
+			app,
+			databaseURL,
+			app.config.HorizonDBMaxIdleConnections,
+			app.config.HorizonDBMaxOpenConnections,
+		))
+	}
+	app.readReplicas = newReadReplicas(sessions, int32(app.config.ReadReplicaMaxLag))
+}
+
 func mustInitCoreDB(app *App) {
-	maxIdle := app.config.CoreDBMaxIdleConnections
-	maxOpen := app.config.CoreDBMaxOpenConnections
-	if app.config.Ingest {
-		maxIdle -= expingest.MaxDBConnections
-		maxOpen -= expingest.MaxDBConnections
-		if maxIdle <= 0 {
-			log.Fatalf("max idle connections to stellar-core db must be greater than %d", expingest.MaxDBConnections)
-		}
-		if maxOpen <= 0 {
-			log.Fatalf("max open connections to stellar-core db must be greater than %d", expingest.MaxDBConnections)
-		}
+	// API only instances and captive core ingestion do not need a stellar-core
+	// database
+	if !app.config.UsesStellarCoreDB() {
+		return
 	}
 
-	app.coreQ = &core.Q{mustNewDBSession(
+	maxIdle, maxOpen, err := app.apiDBPoolSize(
+		app.config.CoreDBMaxIdleConnections,
+		app.config.CoreDBMaxOpenConnections,
+		"stellar-core",
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.coreQ = &core.Q{mustNewAPIDBSession(
+		app,
 		app.config.StellarCoreDatabaseURL,
 		maxIdle,
 		maxOpen,
 	)}
 }
 
-func initExpIngester(app *App) {
-	var err error
-	app.expingester, err = expingest.NewSystem(expingest.Config{
-		CoreSession: mustNewDBSession(
-			app.config.StellarCoreDatabaseURL, expingest.MaxDBConnections, expingest.MaxDBConnections,
-		),
-		HistorySession: mustNewDBSession(
-			app.config.DatabaseURL, expingest.MaxDBConnections, expingest.MaxDBConnections,
-		),
+// newExpIngestConfig returns the configuration of an ingestion system using
+// the app's databases, stellar-core and history archive. The ingestion system
+// gets its own connections to the databases, which must be closed with
+// closeExpIngestConfig once it is done.
+func newExpIngestConfig(app *App) (expingest.Config, error) {
+	historySession, err := newDBSession(
+		app.config.DatabaseURL,
+		expingest.MaxDBConnections,
+		expingest.MaxDBConnections,
+		app.config.DBConnectionMaxLifetime,
+	)
+	if err != nil {
+		return expingest.Config{}, errors.Wrap(err, "cannot open Horizon DB")
+	}
+
+	config := expingest.Config{
+		HistorySession:    historySession,
 		NetworkPassphrase: app.config.NetworkPassphrase,
 		// TODO:
 		// Use the first archive for now. We don't have a mechanism to
 		// use multiple archives at the same time currently.
-		HistoryArchiveURL:        app.config.HistoryArchiveURLs[0],
-		StellarCoreURL:           app.config.StellarCoreURL,
-		StellarCoreCursor:        app.config.CursorName,
-		MaxStreamRetries:         3,
-		DisableStateVerification: app.config.IngestDisableStateVerification,
-	})
+		HistoryArchiveURL:                    app.config.HistoryArchiveURLs[0],
+		StellarCoreURL:                       app.config.StellarCoreURL,
+		StellarCoreCursor:                    app.config.CursorName,
+		MaxStreamRetries:                     3,
+		DisableStateVerification:             app.config.IngestDisableStateVerification,
+		StateVerificationCheckpointFrequency: uint32(app.config.IngestStateVerificationCheckpointFrequency),
+		StateVerificationEntryTypes:          app.config.IngestStateVerificationEntryTypes,
+		HistoryAllowlistAccounts:             app.config.HistoryAllowlistAccounts,
+		HistoryAllowlistAssets:               app.config.HistoryAllowlistAssets,
+		StateOnly:                            app.config.StateOnly,
+	}
+	if app.recentTransactions != nil {
+		config.RecentTransactions = app.recentTransactions
+	}
+
+	if app.config.EnableCaptiveCoreIngestion {
+		config.StellarCorePath = app.config.StellarCoreBinaryPath
+		config.CaptiveCoreConfigAppendPath = app.config.CaptiveCoreConfigAppendPath
+		config.CaptiveCoreStoragePath = app.config.CaptiveCoreStoragePath
+	} else {
+		config.CoreSession, err = newDBSession(
+			app.config.StellarCoreDatabaseURL,
+			expingest.MaxDBConnections,
+			expingest.MaxDBConnections,
+			app.config.DBConnectionMaxLifetime,
+		)
+		if err != nil {
+			historySession.Close()
+			return expingest.Config{}, errors.Wrap(err, "cannot open Stellar Core DB")
+		}
+	}
+	return config, nil
+}
+
+// closeExpIngestConfig closes the connections to the databases opened by
+// newExpIngestConfig.
+func closeExpIngestConfig(config expingest.Config) {
+	for _, session := range []*db.Session{
+		config.HistorySession,
+		config.CoreSession,
+		config.LeaderElectionSession,
+	} {
+		if session != nil {
+			session.Close()
+		}
+	}
+}
+
+// newExpIngestSystem returns an ingestion system used to reingest ledgers on
+// demand, and a function closing its connections to the databases which must
+// be called once the system is done.
+func newExpIngestSystem(app *App) (*expingest.System, func(), error) {
+	config, err := newExpIngestConfig(app)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	system, err := expingest.NewSystem(config)
+	if err != nil {
+		closeExpIngestConfig(config)
+		return nil, nil, err
+	}
+	return system, func() {
+		system.Shutdown()
+		closeExpIngestConfig(config)
+	}, nil
+}
+
+func initExpIngester(app *App) {
+	config, err := newExpIngestConfig(app)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// only the live ingester elects a leader, see expingest.Config
+	config.LeaderElectionSession = mustNewDBSession(
+		app.config.DatabaseURL, 1, 1, app.config.DBConnectionMaxLifetime,
+	)
+	app.expingester, err = expingest.NewSystem(config)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -104,6 +270,14 @@ func initPathFinder(app *App) {
 	)
 
 	app.paths = simplepath.NewInMemoryFinder(orderBookGraph)
+	if app.config.PathCacheSize > 0 {
+		app.pathCache = paths.NewCachedFinder(
+			app.paths,
+			orderBookGraph.LastLedger,
+			int(app.config.PathCacheSize),
+		)
+		app.paths = app.pathCache
+	}
 }
 
 // initSentry initialized the default sentry client with the configured DSN
@@ -119,6 +293,26 @@ func initSentry(app *App) {
 	}
 }
 
+// initTracing installs the global tracer provider, exporting spans to the
+// configured OpenTelemetry collector.
+func initTracing(app *App) {
+	if app.config.TracingOTLPURL == "" {
+		return
+	}
+
+	log.WithFields(log.F{
+		"url":          app.config.TracingOTLPURL,
+		"sample_ratio": app.config.TracingSampleRatio,
+	}).Info("Initializing tracing")
+	provider, err := tracing.Init(
+		context.Background(), "horizon", app.config.TracingOTLPURL, app.config.TracingSampleRatio,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.tracerProvider = provider
+}
+
 // initLogglyLog attaches a loggly hook to our logging system.
 func initLogglyLog(app *App) {
 	if app.config.LogglyToken == "" {
@@ -146,6 +340,10 @@ func initDbMetrics(app *App) {
 	app.horizonConnGauge = metrics.NewGauge()
 	app.coreConnGauge = metrics.NewGauge()
 	app.goroutineGauge = metrics.NewGauge()
+	app.ingestLagGauge = metrics.NewGauge()
+	app.ingestLagSecondsGauge = metrics.NewGauge()
+	app.historyGapsGauge = metrics.NewGauge()
+	app.historyMissingGauge = metrics.NewGauge()
 	app.metrics.Register("history.latest_ledger", app.historyLatestLedgerGauge)
 	app.metrics.Register("history.elder_ledger", app.historyElderLedgerGauge)
 	app.metrics.Register("stellar_core.latest_ledger", app.coreLatestLedgerGauge)
@@ -153,6 +351,62 @@ func initDbMetrics(app *App) {
 	app.metrics.Register("history.open_connections", app.horizonConnGauge)
 	app.metrics.Register("stellar_core.open_connections", app.coreConnGauge)
 	app.metrics.Register("goroutines", app.goroutineGauge)
+	app.metrics.Register("ingest.lag_ledgers", app.ingestLagGauge)
+	app.metrics.Register("ingest.lag_seconds", app.ingestLagSecondsGauge)
+	app.metrics.Register("history.gaps", app.historyGapsGauge)
+	app.metrics.Register("history.missing_ledgers", app.historyMissingGauge)
+
+	app.horizonDBPool = newDBPoolMetrics(app.metrics, "history")
+	app.coreDBPool = newDBPoolMetrics(app.metrics, "stellar_core")
+	if app.readReplicas != nil {
+		for i := range app.readReplicas.replicas {
+			app.replicaDBPools = append(
+				app.replicaDBPools,
+				newDBPoolMetrics(app.metrics, fmt.Sprintf("history.read_replica_%d", i)),
+			)
+		}
+	}
+}
+
+// dbPoolMetrics exposes the statistics of a database connection pool.
+type dbPoolMetrics struct {
+	inUse             metrics.Gauge
+	idle              metrics.Gauge
+	maxOpen           metrics.Gauge
+	waitCount         metrics.Gauge
+	waitDuration      metrics.GaugeFloat64
+	maxIdleClosed     metrics.Gauge
+	maxLifetimeClosed metrics.Gauge
+}
+
+func newDBPoolMetrics(registry metrics.Registry, prefix string) dbPoolMetrics {
+	m := dbPoolMetrics{
+		inUse:             metrics.NewGauge(),
+		idle:              metrics.NewGauge(),
+		maxOpen:           metrics.NewGauge(),
+		waitCount:         metrics.NewGauge(),
+		waitDuration:      metrics.NewGaugeFloat64(),
+		maxIdleClosed:     metrics.NewGauge(),
+		maxLifetimeClosed: metrics.NewGauge(),
+	}
+	registry.Register(prefix+".in_use_connections", m.inUse)
+	registry.Register(prefix+".idle_connections", m.idle)
+	registry.Register(prefix+".max_open_connections", m.maxOpen)
+	registry.Register(prefix+".wait_count", m.waitCount)
+	registry.Register(prefix+".wait_duration_seconds", m.waitDuration)
+	registry.Register(prefix+".max_idle_closed_connections", m.maxIdleClosed)
+	registry.Register(prefix+".max_lifetime_closed_connections", m.maxLifetimeClosed)
+	return m
+}
+
+func (m dbPoolMetrics) update(stats sql.DBStats) {
+	m.inUse.Update(int64(stats.InUse))
+	m.idle.Update(int64(stats.Idle))
+	m.maxOpen.Update(int64(stats.MaxOpenConnections))
+	m.waitCount.Update(stats.WaitCount)
+	m.waitDuration.Update(stats.WaitDuration.Seconds())
+	m.maxIdleClosed.Update(stats.MaxIdleClosed)
+	m.maxLifetimeClosed.Update(stats.MaxLifetimeClosed)
 }
 
 // initIngestMetrics registers the metrics for the ingestion into the provided
@@ -164,6 +418,9 @@ func initIngestMetrics(app *App) {
 	app.metrics.Register("ingest.ledger_ingestion", app.expingester.Metrics.LedgerIngestionTimer)
 	app.metrics.Register("ingest.ledger_in_memory_ingestion", app.expingester.Metrics.LedgerInMemoryIngestionTimer)
 	app.metrics.Register("ingest.state_verify", app.expingester.Metrics.StateVerifyTimer)
+	for name, timer := range app.expingester.Metrics.ProcessorsRunDuration {
+		app.metrics.Register("ingest.processor."+name, timer)
+	}
 }
 
 func initTxSubMetrics(app *App) {
@@ -176,6 +433,57 @@ func initTxSubMetrics(app *App) {
 	app.metrics.Register("txsub.v1", app.submitter.Metrics.V1TransactionsMeter)
 	app.metrics.Register("txsub.feebump", app.submitter.Metrics.FeeBumpTransactionsMeter)
 	app.metrics.Register("txsub.total", app.submitter.Metrics.SubmissionTimer)
+	app.metrics.Register("txsub.rejected", app.submitter.Metrics.RejectedSubmissionsMeter)
+	app.metrics.Register("txsub.circuit_breaker_open", app.submitter.Metrics.CircuitBreakerOpenGauge)
+}
+
+// initReapMetrics registers the metrics for the reaper into the provided
+// app's metrics registry.
+func initReapMetrics(app *App) {
+	app.metrics.Register("history.reap.deleted_ledgers", app.reaper.Metrics.DeletedLedgersCounter)
+	app.metrics.Register("history.reap.remaining_ledgers", app.reaper.Metrics.RemainingLedgersGauge)
+	app.metrics.Register("history.reap.batch", app.reaper.Metrics.BatchTimer)
+}
+
+// initCDCMetrics registers the metrics for the change-data-capture publisher
+// into the provided app's metrics registry.
+func initCDCMetrics(app *App) {
+	if app.cdc == nil {
+		return
+	}
+	app.metrics.Register("cdc.published_ledgers", app.cdc.Metrics.PublishedLedgersCounter)
+	app.metrics.Register("cdc.lag_ledgers", app.cdc.Metrics.LagGauge)
+	app.metrics.Register("cdc.publish", app.cdc.Metrics.PublishTimer)
+}
+
+// initMaintenanceMetrics registers the metrics for the database maintenance
+// into the provided app's metrics registry.
+func initMaintenanceMetrics(app *App) {
+	if app.maintenance == nil {
+		return
+	}
+	app.metrics.Register("db.maintenance.runs", app.maintenance.Metrics.RunsCounter)
+	app.metrics.Register("db.maintenance.vacuum", app.maintenance.Metrics.VacuumTimer)
+	app.metrics.Register("db.maintenance.bloated_indexes", app.maintenance.Metrics.BloatedIndexesGauge)
+	app.metrics.Register("db.maintenance.index_bloat_bytes", app.maintenance.Metrics.IndexBloatBytesGauge)
+}
+
+// initJobsMetrics registers the metrics for the background jobs into the
+// provided app's metrics registry.
+func initJobsMetrics(app *App) {
+	app.metrics.Register("jobs.running", app.jobs.Metrics.RunningJobsGauge)
+	app.metrics.Register("jobs.duration", app.jobs.Metrics.JobTimer)
+	app.metrics.Register("jobs.failed", app.jobs.Metrics.FailedJobsCounter)
+}
+
+// initUsageMetrics registers the metrics for the request accounting into
+// the provided app's metrics registry.
+func initUsageMetrics(app *App) {
+	if app.usage == nil {
+		return
+	}
+	app.metrics.Register("usage.flush", app.usage.Metrics.FlushTimer)
+	app.metrics.Register("usage.tenants", app.usage.Metrics.TenantsGauge)
 }
 
 // initWebMetrics registers the metrics for the web server into the provided
@@ -184,6 +492,8 @@ func initWebMetrics(app *App) {
 	app.metrics.Register("requests.total", app.web.requestTimer)
 	app.metrics.Register("requests.succeeded", app.web.successMeter)
 	app.metrics.Register("requests.failed", app.web.failureMeter)
+	app.metrics.Register("requests.rate_limited", app.web.rateLimitedMeter)
+	app.metrics.Register("requests.sse_connections", app.web.sseConnections)
 }
 
 func initSubmissionSystem(app *App) {
@@ -219,13 +529,21 @@ func initSubmissionSystem(app *App) {
 	// To fix this skip checking Stellar-Core DB for transaction results if
 	// Horizon is ingesting failed transactions.
 
+	// When the history is not ingested the results are only known by the
+	// instance running the ingestion, from the transactions it ingested.
+	var resultProvider txsub.ResultProvider = &results.DB{
+		History: &history.Q{Session: app.HorizonSession(context.Background())},
+	}
+	if app.recentTransactions != nil {
+		resultProvider = app.recentTransactions
+	}
+
 	app.submitter = &txsub.System{
 		Pending:         txsub.NewDefaultSubmissionList(),
 		Submitter:       txsub.NewDefaultSubmitter(http.DefaultClient, app.config.StellarCoreURL),
 		SubmissionQueue: sequence.NewManager(),
-		Results: &results.DB{
-			History: &history.Q{Session: app.HorizonSession(context.Background())},
-		},
-		Sequences: &history.Q{Session: app.HorizonSession(context.Background())},
+		Results:         resultProvider,
+		Sequences:       &history.Q{Session: app.HorizonSession(context.Background())},
+		CircuitBreaker:  txsub.NewCircuitBreaker(),
 	}
 }
@@ -0,0 +1,39 @@
+package horizon
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStatementTimeout(t *testing.T) {
+	databaseURL, err := withStatementTimeout("postgres://localhost/horizon?sslmode=disable", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres://localhost/horizon?sslmode=disable", databaseURL)
+
+	databaseURL, err = withStatementTimeout("postgres://localhost/horizon?sslmode=disable", 30*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres://localhost/horizon?sslmode=disable&statement_timeout=30000", databaseURL)
+
+	databaseURL, err = withStatementTimeout("dbname=horizon sslmode=disable", 500*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, "dbname=horizon sslmode=disable statement_timeout=500", databaseURL)
+}
+
+func TestNewExpIngestSystemUnreachableDB(t *testing.T) {
+	// a closed listener gives an address refusing connections
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	app := &App{config: Config{
+		DatabaseURL:        "postgres://" + addr + "/horizon?sslmode=disable",
+		HistoryArchiveURLs: []string{"http://localhost/archive"},
+	}}
+	_, _, err = newExpIngestSystem(app)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot open Horizon DB")
+}
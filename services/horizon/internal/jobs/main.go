@@ -0,0 +1,116 @@
+// Package jobs contains the background jobs subsystem of horizon. It runs the
+// long-running operations requested by operators on the admin port, like
+// reingesting a range of ledgers, rebuilding the trade aggregations or
+// reaping the history.
+//
+// Jobs are persisted in the horizon database so that they can be tracked and
+// cancelled from any instance sharing the database. A job is run by a single
+// instance, which records a heartbeat while running it. When the instance
+// stops, the job is resumed from the start by the next instance polling for
+// jobs, so job handlers must be idempotent.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// DefaultPollInterval is the default time between two polls for
+	// pending jobs.
+	DefaultPollInterval = 5 * time.Second
+	// DefaultHeartbeatInterval is the default time between two heartbeats
+	// of a running job, which is also how long it takes to notice that the
+	// cancellation of the job was requested.
+	DefaultHeartbeatInterval = 5 * time.Second
+	// DefaultStaleTimeout is the default time after which a running job
+	// without heartbeat is considered abandoned by its instance and is
+	// resumed by another one.
+	DefaultStaleTimeout = time.Minute
+)
+
+// ErrUnknownType is returned when submitting a job of a type without
+// handler.
+var ErrUnknownType = errors.New("unknown job type")
+
+// Handler runs a job given its JSON encoded parameters. It must return when
+// `ctx` is cancelled, which happens when the job is cancelled or horizon
+// shuts down. `progress` records a description of the progress of the job.
+type Handler func(ctx context.Context, params string, progress func(string)) error
+
+// System represents the background jobs subsystem of horizon.
+type System struct {
+	HistoryQ          *history.Q
+	PollInterval      time.Duration
+	HeartbeatInterval time.Duration
+	StaleTimeout      time.Duration
+
+	Metrics struct {
+		// RunningJobsGauge is the number of jobs run by this instance.
+		RunningJobsGauge metrics.Gauge
+
+		// JobTimer exposes timing metrics about the jobs finished by this
+		// instance.
+		JobTimer metrics.Timer
+
+		// FailedJobsCounter counts the jobs which failed on this instance.
+		FailedJobsCounter metrics.Counter
+	}
+
+	handlers map[string]Handler
+	now      func() time.Time
+}
+
+// New initializes the background jobs subsystem storing the jobs in the
+// database of `dbSession`. Handlers must be registered before it runs.
+func New(dbSession *db.Session) *System {
+	s := &System{
+		HistoryQ:          &history.Q{dbSession},
+		PollInterval:      DefaultPollInterval,
+		HeartbeatInterval: DefaultHeartbeatInterval,
+		StaleTimeout:      DefaultStaleTimeout,
+		handlers:          map[string]Handler{},
+		now:               time.Now,
+	}
+	s.Metrics.RunningJobsGauge = metrics.NewGauge()
+	s.Metrics.JobTimer = metrics.NewTimer()
+	s.Metrics.FailedJobsCounter = metrics.NewCounter()
+	return s
+}
+
+// Register sets the handler running the jobs of type `jobType`. Only the jobs
+// of registered types are run by this instance.
+func (s *System) Register(jobType string, handler Handler) {
+	s.handlers[jobType] = handler
+}
+
+// Types returns the registered job types, sorted.
+func (s *System) Types() []string {
+	types := make([]string, 0, len(s.handlers))
+	for jobType := range s.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Submit adds a pending job of type `jobType` with `params` encoded in JSON.
+// It is run by the next instance polling for jobs.
+func (s *System) Submit(jobType string, params interface{}) (history.Job, error) {
+	if _, ok := s.handlers[jobType]; !ok {
+		return history.Job{}, ErrUnknownType
+	}
+
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return history.Job{}, errors.Wrap(err, "could not encode job parameters")
+	}
+	return s.HistoryQ.InsertJob(jobType, string(encoded), s.now())
+}
@@ -0,0 +1,158 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	herrors "github.com/stellar/go/services/horizon/internal/errors"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// Run polls for pending jobs every PollInterval until `ctx` is cancelled and
+// runs them one at a time. The job running when `ctx` is cancelled is made
+// pending again so that another instance resumes it.
+func (s *System) Run(ctx context.Context) {
+	log.WithField("types", s.Types()).Info("Starting background jobs runner")
+	for {
+		if err := s.runOnce(ctx); err != nil {
+			log.WithField("err", err).Error("Error running background jobs")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.PollInterval):
+		}
+	}
+}
+
+// runOnce runs the claimable jobs until there is none left.
+func (s *System) runOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		now := s.now()
+		job, ok, err := s.HistoryQ.ClaimJob(s.Types(), now.Add(-s.StaleTimeout), now)
+		if err != nil || !ok {
+			return err
+		}
+		if err = s.runJob(ctx, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runJob runs a claimed job and records how it finished.
+func (s *System) runJob(ctx context.Context, job history.Job) error {
+	logger := log.WithFields(log.F{"job_id": job.ID, "job_type": job.Type})
+	if job.HeartbeatAt.Valid && job.StartedAt.Valid && job.HeartbeatAt.Time.After(job.StartedAt.Time) {
+		logger.Info("Resuming abandoned background job")
+	} else {
+		logger.Info("Starting background job")
+	}
+
+	s.Metrics.RunningJobsGauge.Update(1)
+	defer s.Metrics.RunningJobsGauge.Update(0)
+	startTime := time.Now()
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	heartbeat := &heartbeat{}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.sendHeartbeats(job.ID, heartbeat, cancel, done)
+	}()
+
+	jobErr := s.callHandler(jobCtx, job, heartbeat.setProgress)
+	close(done)
+	wg.Wait()
+
+	switch {
+	case heartbeat.cancelled:
+		logger.Info("Background job cancelled")
+		return s.HistoryQ.FinishJob(job.ID, history.JobCancelled, nil, s.now())
+	case ctx.Err() != nil:
+		logger.Info("Shutting down, releasing background job")
+		return s.HistoryQ.ReleaseJob(job.ID)
+	case jobErr != nil:
+		logger.WithField("err", jobErr).Error("Background job failed")
+		s.Metrics.FailedJobsCounter.Inc(1)
+		s.Metrics.JobTimer.UpdateSince(startTime)
+		return s.HistoryQ.FinishJob(job.ID, history.JobFailed, jobErr, s.now())
+	default:
+		logger.WithField("duration", time.Since(startTime).Seconds()).Info("Background job succeeded")
+		s.Metrics.JobTimer.UpdateSince(startTime)
+		return s.HistoryQ.FinishJob(job.ID, history.JobSucceeded, nil, s.now())
+	}
+}
+
+// callHandler runs the handler of `job`, turning panics into errors.
+func (s *System) callHandler(ctx context.Context, job history.Job, progress func(string)) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = herrors.FromPanic(rec)
+			herrors.ReportToSentry(err, nil)
+		}
+	}()
+
+	handler, ok := s.handlers[job.Type]
+	if !ok {
+		return errors.Wrap(ErrUnknownType, job.Type)
+	}
+	return handler(ctx, job.Params, progress)
+}
+
+// sendHeartbeats records the heartbeat and progress of the job `id` every
+// HeartbeatInterval until `done` is closed, and cancels the job when its
+// cancellation is requested.
+func (s *System) sendHeartbeats(id int64, h *heartbeat, cancel func(), done <-chan struct{}) {
+	ticker := time.NewTicker(s.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		cancelRequested, err := s.HistoryQ.UpdateJobHeartbeat(id, h.getProgress(), s.now())
+		if err != nil {
+			log.WithFields(log.F{"job_id": id, "err": err}).Warn("Error updating background job heartbeat")
+			continue
+		}
+		if cancelRequested {
+			// instances do not claim jobs whose cancellation was requested
+			// so the heartbeats can stop
+			h.cancelled = true
+			cancel()
+			return
+		}
+	}
+}
+
+// heartbeat holds the state of a running job shared by its handler and the
+// heartbeats.
+type heartbeat struct {
+	mutex    sync.Mutex
+	progress string
+	// cancelled is only set by the heartbeats, it must be read once they
+	// stopped
+	cancelled bool
+}
+
+func (h *heartbeat) setProgress(progress string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.progress = progress
+}
+
+func (h *heartbeat) getProgress() string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.progress
+}
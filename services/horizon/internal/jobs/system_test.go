@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestRunOnce(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	sys := New(tt.HorizonSession())
+	var ran []string
+	sys.Register("succeed", func(ctx context.Context, params string, progress func(string)) error {
+		ran = append(ran, params)
+		return nil
+	})
+	sys.Register("fail", func(ctx context.Context, params string, progress func(string)) error {
+		return errors.New("boom")
+	})
+
+	_, err := sys.Submit("unknown", nil)
+	tt.Assert.Equal(ErrUnknownType, err)
+	succeeded, err := sys.Submit("succeed", map[string]int{"from": 2})
+	tt.Require.NoError(err)
+	failed, err := sys.Submit("fail", nil)
+	tt.Require.NoError(err)
+
+	tt.Require.NoError(sys.runOnce(context.Background()))
+	tt.Assert.Equal([]string{`{"from":2}`}, ran)
+
+	job, err := sys.HistoryQ.GetJob(succeeded.ID)
+	tt.Require.NoError(err)
+	tt.Assert.Equal(history.JobSucceeded, job.Status)
+	job, err = sys.HistoryQ.GetJob(failed.ID)
+	tt.Require.NoError(err)
+	tt.Assert.Equal(history.JobFailed, job.Status)
+	tt.Assert.Equal("boom", job.Error.String)
+	tt.Assert.Equal(int64(1), sys.Metrics.FailedJobsCounter.Count())
+}
+
+func TestRunJobCancelled(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	sys := New(tt.HorizonSession())
+	sys.HeartbeatInterval = 10 * time.Millisecond
+	started := make(chan struct{})
+	sys.Register("wait", func(ctx context.Context, params string, progress func(string)) error {
+		progress("waiting")
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	submitted, err := sys.Submit("wait", nil)
+	tt.Require.NoError(err)
+
+	go func() {
+		<-started
+		_, err := (&history.Q{tt.HorizonSession()}).RequestJobCancellation(submitted.ID, time.Now())
+		tt.Assert.NoError(err)
+	}()
+	tt.Require.NoError(sys.runOnce(context.Background()))
+
+	job, err := sys.HistoryQ.GetJob(submitted.ID)
+	tt.Require.NoError(err)
+	tt.Assert.Equal(history.JobCancelled, job.Status)
+	tt.Assert.Equal("waiting", job.Progress)
+}
+
+func TestRunJobReleasedOnShutdown(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	sys := New(tt.HorizonSession())
+	ctx, cancel := context.WithCancel(context.Background())
+	sys.Register("wait", func(ctx context.Context, params string, progress func(string)) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	submitted, err := sys.Submit("wait", nil)
+	tt.Require.NoError(err)
+	tt.Require.NoError(sys.runOnce(ctx))
+
+	// the job is resumed by the next instance
+	job, err := sys.HistoryQ.GetJob(submitted.ID)
+	tt.Require.NoError(err)
+	tt.Assert.Equal(history.JobPending, job.Status)
+	tt.Assert.True(job.StartedAt.Valid)
+}
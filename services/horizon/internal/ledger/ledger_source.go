@@ -28,9 +28,15 @@ type HistoryDBSource struct {
 
 // NewHistoryDBSource constructs a new instance of HistoryDBSource
 func NewHistoryDBSource(updateFrequency time.Duration) *HistoryDBSource {
+	return NewHistoryDBSourceFromStore(updateFrequency, defaultStore)
+}
+
+// NewHistoryDBSourceFromStore constructs a new instance of HistoryDBSource
+// which reads the ledger state from the given store.
+func NewHistoryDBSourceFromStore(updateFrequency time.Duration, store *Store) *HistoryDBSource {
 	return &HistoryDBSource{
 		updateFrequency: updateFrequency,
-		currentState:    CurrentState,
+		currentState:    store.CurrentState,
 		closedLock:      sync.Mutex{},
 	}
 }
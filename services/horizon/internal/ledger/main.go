@@ -6,7 +6,9 @@
 package ledger
 
 import (
+	"context"
 	"sync"
+	"time"
 )
 
 // State represents a snapshot of both horizon's and stellar-core's view of the
@@ -16,22 +18,64 @@ type State struct {
 	HistoryLatest    int32  `db:"history_latest"`
 	HistoryElder     int32  `db:"history_elder"`
 	ExpHistoryLatest uint32 `db:"exp_history_latest"`
+	// HistoryLatestClosedAt is the close time of the HistoryLatest ledger.
+	HistoryLatestClosedAt time.Time `db:"history_latest_closed_at"`
+}
+
+// Store holds a cached snapshot of the ledger state. A horizon process serving
+// more than one network keeps a separate Store for every network.
+type Store struct {
+	lock    sync.RWMutex
+	current State
 }
 
 // CurrentState returns the cached snapshot of ledger state
-func CurrentState() State {
-	lock.RLock()
-	ret := current
-	lock.RUnlock()
+func (s *Store) CurrentState() State {
+	s.lock.RLock()
+	ret := s.current
+	s.lock.RUnlock()
 	return ret
 }
 
+// SetState updates the cached snapshot of the ledger state
+func (s *Store) SetState(next State) {
+	s.lock.Lock()
+	s.current = next
+	s.lock.Unlock()
+}
+
+// DefaultStore returns the process wide store used by CurrentState and
+// SetState.
+func DefaultStore() *Store {
+	return defaultStore
+}
+
+// CurrentState returns the cached snapshot of ledger state
+func CurrentState() State {
+	return defaultStore.CurrentState()
+}
+
 // SetState updates the cached snapshot of the ledger state
 func SetState(next State) {
-	lock.Lock()
-	current = next
-	lock.Unlock()
+	defaultStore.SetState(next)
+}
+
+type storeContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the given store.
+func NewContext(ctx context.Context, s *Store) context.Context {
+	return context.WithValue(ctx, storeContextKey{}, s)
+}
+
+// FromContext returns the store carried by ctx or the default store if ctx
+// does not carry one.
+func FromContext(ctx context.Context) *Store {
+	if ctx != nil {
+		if s, ok := ctx.Value(storeContextKey{}).(*Store); ok {
+			return s
+		}
+	}
+	return defaultStore
 }
 
-var current State
-var lock sync.RWMutex
+var defaultStore = &Store{}
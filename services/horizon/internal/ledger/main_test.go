@@ -0,0 +1,25 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != DefaultStore() {
+		t.Error("FromContext should return the default store when ctx carries none")
+	}
+
+	store := &Store{}
+	store.SetState(State{HistoryLatest: 10})
+	ctx := NewContext(context.Background(), store)
+	if FromContext(ctx) != store {
+		t.Error("FromContext should return the store carried by ctx")
+	}
+	if got := FromContext(ctx).CurrentState().HistoryLatest; got != 10 {
+		t.Errorf("HistoryLatest = %d, want 10", got)
+	}
+	if got := CurrentState().HistoryLatest; got == 10 {
+		t.Error("setting the state of a store should not change the default store")
+	}
+}
@@ -0,0 +1,144 @@
+// Package maintenance contains the database maintenance subsystem of horizon.
+// During a daily low-traffic window it vacuums and analyzes the hot history
+// tables, which autovacuum struggles to keep up with on busy databases, and
+// estimates the bloat of their indexes so that operators know when to rebuild
+// them.
+//
+// Horizon instances sharing a database record when the maintenance last
+// started in the database, so that it runs once per window.
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// DefaultCheckInterval is the default time between two checks of the
+	// maintenance window.
+	DefaultCheckInterval = time.Minute
+	// DefaultIndexBloatThreshold is the default estimated fraction of wasted
+	// space above which an index is reported as bloated.
+	DefaultIndexBloatThreshold = 0.5
+	// minBloatedIndexSize is the size below which indexes are never reported
+	// as bloated, the estimate being too rough for small indexes.
+	minBloatedIndexSize = 10 * 1024 * 1024
+)
+
+// Window is a daily time window, in UTC. It can span midnight.
+type Window struct {
+	// Start and End are offsets from midnight.
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseWindow parses a window of the form "HH:MM-HH:MM", in UTC.
+func ParseWindow(value string) (Window, error) {
+	var startHour, startMinute, endHour, endMinute int
+	_, err := fmt.Sscanf(value, "%d:%d-%d:%d", &startHour, &startMinute, &endHour, &endMinute)
+	if err != nil {
+		return Window{}, errors.Errorf("invalid maintenance window %q, expected HH:MM-HH:MM", value)
+	}
+	for _, hour := range []int{startHour, endHour} {
+		if hour < 0 || hour > 23 {
+			return Window{}, errors.Errorf("invalid maintenance window %q, invalid hour %d", value, hour)
+		}
+	}
+	for _, minute := range []int{startMinute, endMinute} {
+		if minute < 0 || minute > 59 {
+			return Window{}, errors.Errorf("invalid maintenance window %q, invalid minute %d", value, minute)
+		}
+	}
+
+	window := Window{
+		Start: time.Duration(startHour)*time.Hour + time.Duration(startMinute)*time.Minute,
+		End:   time.Duration(endHour)*time.Hour + time.Duration(endMinute)*time.Minute,
+	}
+	if window.Start == window.End {
+		return Window{}, errors.Errorf("invalid maintenance window %q, it is empty", value)
+	}
+	return window, nil
+}
+
+// Opening returns the time the occurrence of the window containing `t`
+// opened, and false when `t` is outside the window.
+func (w Window) Opening(t time.Time) (time.Time, bool) {
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := t.Sub(midnight)
+
+	switch {
+	case w.Start < w.End && offset >= w.Start && offset < w.End:
+		return midnight.Add(w.Start), true
+	case w.Start > w.End && offset >= w.Start:
+		return midnight.Add(w.Start), true
+	case w.Start > w.End && offset < w.End:
+		// the window opened the day before
+		return midnight.AddDate(0, 0, -1).Add(w.Start), true
+	}
+	return time.Time{}, false
+}
+
+// Closing returns the time the occurrence of the window opened at `opening`
+// closes.
+func (w Window) Closing(opening time.Time) time.Time {
+	length := w.End - w.Start
+	if length < 0 {
+		length += 24 * time.Hour
+	}
+	return opening.Add(length)
+}
+
+// System represents the database maintenance subsystem of horizon.
+type System struct {
+	HistoryQ *history.Q
+	Window   Window
+	// Tables are the tables vacuumed and analyzed, in order.
+	Tables []string
+	// IndexBloatThreshold is the estimated fraction of wasted space above
+	// which an index is reported as bloated.
+	IndexBloatThreshold float64
+	CheckInterval       time.Duration
+
+	Metrics struct {
+		// RunsCounter counts the maintenance runs started by this instance.
+		RunsCounter metrics.Counter
+
+		// VacuumTimer exposes timing metrics about the vacuum and analysis
+		// of a table.
+		VacuumTimer metrics.Timer
+
+		// BloatedIndexesGauge is the number of indexes found bloated by the
+		// last index bloat check.
+		BloatedIndexesGauge metrics.Gauge
+
+		// IndexBloatBytesGauge is the estimated number of bytes wasted by
+		// the bloated indexes found by the last index bloat check.
+		IndexBloatBytesGauge metrics.Gauge
+	}
+
+	now func() time.Time
+}
+
+// New initializes the database maintenance subsystem vacuuming the hot
+// history tables of the database of `dbSession` during `window`.
+func New(dbSession *db.Session, window Window) *System {
+	s := &System{
+		HistoryQ:            &history.Q{dbSession},
+		Window:              window,
+		Tables:              history.HotHistoryTables,
+		IndexBloatThreshold: DefaultIndexBloatThreshold,
+		CheckInterval:       DefaultCheckInterval,
+		now:                 time.Now,
+	}
+	s.Metrics.RunsCounter = metrics.NewCounter()
+	s.Metrics.VacuumTimer = metrics.NewTimer()
+	s.Metrics.BloatedIndexesGauge = metrics.NewGauge()
+	s.Metrics.IndexBloatBytesGauge = metrics.NewGauge()
+	return s
+}
@@ -0,0 +1,51 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindow(t *testing.T) {
+	window, err := ParseWindow("02:30-05:00")
+	require.NoError(t, err)
+	assert.Equal(t, Window{Start: 2*time.Hour + 30*time.Minute, End: 5 * time.Hour}, window)
+
+	for value, expected := range map[string]string{
+		"2am-5am":     `invalid maintenance window "2am-5am", expected HH:MM-HH:MM`,
+		"24:00-05:00": `invalid maintenance window "24:00-05:00", invalid hour 24`,
+		"02:00-05:60": `invalid maintenance window "02:00-05:60", invalid minute 60`,
+		"02:00-02:00": `invalid maintenance window "02:00-02:00", it is empty`,
+	} {
+		_, err = ParseWindow(value)
+		assert.EqualError(t, err, expected)
+	}
+}
+
+func TestWindowOpening(t *testing.T) {
+	day := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	window := Window{Start: 2 * time.Hour, End: 5 * time.Hour}
+	opening, open := window.Opening(day.Add(3 * time.Hour))
+	assert.True(t, open)
+	assert.Equal(t, day.Add(2*time.Hour), opening)
+	assert.Equal(t, day.Add(5*time.Hour), window.Closing(opening))
+	_, open = window.Opening(day.Add(5 * time.Hour))
+	assert.False(t, open)
+	_, open = window.Opening(day.Add(time.Hour))
+	assert.False(t, open)
+
+	// windows spanning midnight
+	window = Window{Start: 23 * time.Hour, End: time.Hour}
+	opening, open = window.Opening(day.Add(23*time.Hour + 30*time.Minute))
+	assert.True(t, open)
+	assert.Equal(t, day.Add(23*time.Hour), opening)
+	assert.Equal(t, day.Add(25*time.Hour), window.Closing(opening))
+	opening, open = window.Opening(day.Add(30 * time.Minute))
+	assert.True(t, open)
+	assert.Equal(t, day.Add(-time.Hour), opening)
+	_, open = window.Opening(day.Add(12 * time.Hour))
+	assert.False(t, open)
+}
@@ -0,0 +1,130 @@
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// Run checks the maintenance window every CheckInterval until `ctx` is
+// cancelled, and runs the maintenance when the window opens unless another
+// instance already ran it.
+func (s *System) Run(ctx context.Context) {
+	log.WithFields(log.F{
+		"window_start": s.Window.Start,
+		"window_end":   s.Window.End,
+		"tables":       s.Tables,
+	}).Info("Starting database maintenance scheduler")
+	for {
+		if err := s.runOnce(ctx); err != nil {
+			log.WithField("err", err).Error("Error running database maintenance")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.CheckInterval):
+		}
+	}
+}
+
+// runOnce runs the maintenance if the window is open and the maintenance has
+// not started since it opened. Tables are not vacuumed once the window
+// closes, the remaining ones are vacuumed during the next window.
+func (s *System) runOnce(ctx context.Context) error {
+	opening, open := s.Window.Opening(s.now())
+	if !open {
+		return nil
+	}
+	started, err := s.claimRun(opening)
+	if err != nil || !started {
+		return err
+	}
+	s.Metrics.RunsCounter.Inc(1)
+	closing := s.Window.Closing(opening)
+
+	log.WithField("tables", s.Tables).Info("Database maintenance started")
+	for _, table := range s.Tables {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !s.now().Before(closing) {
+			log.WithField("table", table).Warn("Maintenance window closed, skipping the remaining tables")
+			return nil
+		}
+
+		startTime := time.Now()
+		if err = s.HistoryQ.VacuumAnalyze(table); err != nil {
+			return err
+		}
+		s.Metrics.VacuumTimer.UpdateSince(startTime)
+		log.WithFields(log.F{
+			"table":    table,
+			"duration": time.Since(startTime).Seconds(),
+		}).Info("Vacuumed and analyzed table")
+	}
+
+	if err = s.checkIndexBloat(); err != nil {
+		return err
+	}
+	log.Info("Database maintenance finished")
+	return nil
+}
+
+// claimRun records that the maintenance started unless it already started
+// since `opening`. It returns true when this instance should run it.
+func (s *System) claimRun(opening time.Time) (bool, error) {
+	q := &history.Q{s.HistoryQ.Clone()}
+	if err := q.Begin(); err != nil {
+		return false, errors.Wrap(err, "could not begin transaction")
+	}
+	defer q.Rollback()
+
+	lastRun, err := q.GetLastMaintenanceRun()
+	if err != nil {
+		return false, errors.Wrap(err, "could not load last maintenance run")
+	}
+	if !lastRun.Before(opening) {
+		return false, nil
+	}
+
+	if err = q.UpdateLastMaintenanceRun(s.now()); err != nil {
+		return false, errors.Wrap(err, "could not update last maintenance run")
+	}
+	if err = q.Commit(); err != nil {
+		return false, errors.Wrap(err, "could not commit transaction")
+	}
+	return true, nil
+}
+
+// checkIndexBloat logs the indexes of the maintained tables whose estimated
+// bloat is above IndexBloatThreshold and updates the bloat metrics.
+func (s *System) checkIndexBloat() error {
+	indexes, err := s.HistoryQ.EstimateIndexBloat(s.Tables)
+	if err != nil {
+		return err
+	}
+
+	var bloated, wasted int64
+	for _, index := range indexes {
+		ratio := index.BloatRatio()
+		if index.Size < minBloatedIndexSize || ratio < s.IndexBloatThreshold {
+			continue
+		}
+		bloated++
+		wasted += index.Size - index.ExpectedSize
+		log.WithFields(log.F{
+			"table":         index.Table,
+			"index":         index.Index,
+			"size":          index.Size,
+			"expected_size": index.ExpectedSize,
+			"bloat_ratio":   ratio,
+		}).Warn("Index is bloated, consider rebuilding it")
+	}
+	s.Metrics.BloatedIndexesGauge.Update(bloated)
+	s.Metrics.IndexBloatBytesGauge.Update(wasted)
+	return nil
+}
@@ -0,0 +1,44 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestRunOnce(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	day := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	now := day.Add(time.Hour)
+	sys := New(tt.HorizonSession(), Window{Start: 2 * time.Hour, End: 5 * time.Hour})
+	sys.now = func() time.Time { return now }
+
+	// nothing runs outside of the window
+	tt.Require.NoError(sys.runOnce(context.Background()))
+	tt.Assert.Equal(int64(0), sys.Metrics.RunsCounter.Count())
+
+	now = day.Add(3 * time.Hour)
+	tt.Require.NoError(sys.runOnce(context.Background()))
+	tt.Assert.Equal(int64(1), sys.Metrics.RunsCounter.Count())
+	tt.Assert.Equal(int64(len(sys.Tables)), sys.Metrics.VacuumTimer.Count())
+	lastRun, err := sys.HistoryQ.GetLastMaintenanceRun()
+	tt.Require.NoError(err)
+	tt.Assert.True(now.Equal(lastRun))
+
+	// the maintenance runs once per window, even with several instances
+	other := New(tt.HorizonSession(), sys.Window)
+	other.now = sys.now
+	now = day.Add(4 * time.Hour)
+	tt.Require.NoError(sys.runOnce(context.Background()))
+	tt.Require.NoError(other.runOnce(context.Background()))
+	tt.Assert.Equal(int64(1), sys.Metrics.RunsCounter.Count())
+	tt.Assert.Equal(int64(0), other.Metrics.RunsCounter.Count())
+
+	now = day.AddDate(0, 0, 1).Add(2 * time.Hour)
+	tt.Require.NoError(other.runOnce(context.Background()))
+	tt.Assert.Equal(int64(1), other.Metrics.RunsCounter.Count())
+}
@@ -20,10 +20,16 @@ import (
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/render"
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/services/horizon/internal/usage"
 	"github.com/stellar/go/support/db"
 	supportErrors "github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/support/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // appContextMiddleware adds the "app" context into every request, so that subsequence appContextMiddleware
@@ -37,6 +43,36 @@ func appContextMiddleware(app *App) func(http.Handler) http.Handler {
 	}
 }
 
+// ledgerStateMiddleware adds the ledger state cache of the network being
+// served into every request, see ledger.FromContext.
+func ledgerStateMiddleware(store *ledger.Store) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := ledger.NewContext(r.Context(), store)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// pathPrefixMiddleware records the path prefix under which the router is
+// mounted so that links in responses are built relative to it.
+func pathPrefixMiddleware(prefix string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := httpx.WithPathPrefix(r.Context(), prefix)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// historyNotIngestedMiddleware rejects the requests to the history endpoints
+// when the history is not ingested, see Config.StateOnly.
+func historyNotIngestedMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		problem.Render(r.Context(), w, hProblem.HistoryNotIngested)
+	})
+}
+
 // requestCacheHeadersMiddleware adds caching headers to each response.
 func requestCacheHeadersMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -74,28 +110,37 @@ func newWrapResponseWriter(w http.ResponseWriter, r *http.Request) middleware.Wr
 	return mw
 }
 
-// loggerMiddleware logs http requests and resposnes to the logging subsytem of horizon.
-func loggerMiddleware(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		mw := newWrapResponseWriter(w, r)
+// loggerMiddleware logs http requests and resposnes to the logging subsytem of horizon,
+// along with the number of SQL queries they ran and the time spent running them.
+// Non-streaming requests slower than slowRequestThreshold are also logged with their
+// slowest queries, unless the threshold is zero.
+func loggerMiddleware(slowRequestThreshold time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			mw := newWrapResponseWriter(w, r)
 
-		logger := log.WithField("req", middleware.GetReqID(ctx))
-		ctx = log.Set(ctx, logger)
+			logger := log.WithField("req", middleware.GetReqID(ctx))
+			ctx = log.Set(ctx, logger)
+			ctx, queryStats := db.WithQueryStats(ctx)
 
-		// Checking `Accept` header from user request because if the streaming connection
-		// is reset before sending the first event no Content-Type header is sent in a response.
-		acceptHeader := r.Header.Get("Accept")
-		streaming := strings.Contains(acceptHeader, render.MimeEventStream)
+			// Checking `Accept` header from user request because if the streaming connection
+			// is reset before sending the first event no Content-Type header is sent in a response.
+			acceptHeader := r.Header.Get("Accept")
+			streaming := strings.Contains(acceptHeader, render.MimeEventStream)
 
-		logStartOfRequest(ctx, r, streaming)
-		then := time.Now()
+			logStartOfRequest(ctx, r, streaming)
+			then := time.Now()
 
-		h.ServeHTTP(mw, r.WithContext(ctx))
+			h.ServeHTTP(mw, r.WithContext(ctx))
 
-		duration := time.Since(then)
-		logEndOfRequest(ctx, r, duration, mw, streaming)
-	})
+			duration := time.Since(then)
+			logEndOfRequest(ctx, r, duration, mw, streaming, queryStats)
+			if slowRequestThreshold > 0 && !streaming && duration >= slowRequestThreshold {
+				logSlowRequest(ctx, r, duration, queryStats)
+			}
+		})
+	}
 }
 
 // timeoutMiddleware ensures the request is terminated after the given timeout
@@ -159,7 +204,7 @@ func logStartOfRequest(ctx context.Context, r *http.Request, streaming bool) {
 	}).Info("Starting request")
 }
 
-func logEndOfRequest(ctx context.Context, r *http.Request, duration time.Duration, mw middleware.WrapResponseWriter, streaming bool) {
+func logEndOfRequest(ctx context.Context, r *http.Request, duration time.Duration, mw middleware.WrapResponseWriter, streaming bool, queryStats *db.QueryStats) {
 	routePattern := chi.RouteContext(r.Context()).RoutePattern()
 	// Can be empty when request did not reached the final route (ex. blocked by
 	// a middleware). More info: https://github.com/go-chi/chi/issues/270
@@ -186,21 +231,62 @@ func logEndOfRequest(ctx context.Context, r *http.Request, duration time.Duratio
 		"method":         r.Method,
 		"path":           r.URL.String(),
 		"route":          routePattern,
+		"sql_count":      queryStats.Count(),
+		"sql_duration":   queryStats.Duration().Seconds(),
 		"status":         mw.Status(),
 		"streaming":      streaming,
 		"referer":        referer,
 	}).Info("Finished request")
 }
 
+func logSlowRequest(ctx context.Context, r *http.Request, duration time.Duration, queryStats *db.QueryStats) {
+	slowest := queryStats.Slowest()
+	queries := make([]log.F, 0, len(slowest))
+	for _, query := range slowest {
+		queries = append(queries, log.F{
+			"sql":      query.SQL,
+			"duration": query.Duration.Seconds(),
+		})
+	}
+
+	log.Ctx(ctx).WithFields(log.F{
+		"duration":     duration.Seconds(),
+		"method":       r.Method,
+		"path":         r.URL.String(),
+		"route":        routePattern(r),
+		"sql_count":    queryStats.Count(),
+		"sql_duration": queryStats.Duration().Seconds(),
+		"queries":      queries,
+	}).Warn("Slow request")
+}
+
 func firstXForwardedFor(r *http.Request) string {
 	return strings.TrimSpace(strings.SplitN(r.Header.Get("X-Forwarded-For"), ",", 2)[0])
 }
 
+// RateLimitMiddleware rate limits requests by remote ip address. Requests of
+// the rate limit groups which have their own quota are counted separately from
+// all other requests.
 func (w *web) RateLimitMiddleware(next http.Handler) http.Handler {
-	if w.rateLimiter == nil {
-		return next
+	var defaultHandler http.Handler = next
+	if w.rateLimiter != nil {
+		defaultHandler = w.rateLimiter.RateLimit(next)
+	}
+	if len(w.routeRateLimiters) == 0 {
+		return defaultHandler
+	}
+
+	groupHandlers := map[string]http.Handler{}
+	for group, rateLimiter := range w.routeRateLimiters {
+		groupHandlers[group] = rateLimiter.RateLimit(next)
 	}
-	return w.rateLimiter.RateLimit(next)
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if h, ok := groupHandlers[rateLimitGroup(r, w.pathPrefix)]; ok {
+			h.ServeHTTP(rw, r)
+			return
+		}
+		defaultHandler.ServeHTTP(rw, r)
+	})
 }
 
 // recoverMiddleware helps the server recover from panics. It ensures that
@@ -227,9 +313,16 @@ func requestMetricsMiddleware(h http.Handler) http.Handler {
 		app := AppFromContext(r.Context())
 		mw := newWrapResponseWriter(w, r)
 
-		app.web.requestTimer.Time(func() {
-			h.ServeHTTP(mw.(http.ResponseWriter), r)
-		})
+		if render.Negotiate(r) == render.MimeEventStream {
+			app.web.sseConnections.Inc(1)
+			defer app.web.sseConnections.Dec(1)
+		}
+
+		then := time.Now()
+		h.ServeHTTP(mw.(http.ResponseWriter), r)
+		duration := time.Since(then)
+		app.web.requestTimer.Update(duration)
+		app.web.routeTimer.With(routePattern(r), routeMethod(r)).Update(duration)
 
 		if 200 <= mw.Status() && mw.Status() < 400 {
 			// a success is in [200, 400)
@@ -241,28 +334,112 @@ func requestMetricsMiddleware(h http.Handler) http.Handler {
 	})
 }
 
+// usageMiddleware accounts every request to its tenant: the API key found in
+// the `keyHeader` header, or the remote ip address of the request when there
+// is none.
+func usageMiddleware(recorder *usage.System, keyHeader string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mw := newWrapResponseWriter(w, r)
+			h.ServeHTTP(mw.(http.ResponseWriter), r)
+
+			tenant := "ip:" + remoteAddrIP(r)
+			if keyHeader != "" {
+				if key := r.Header.Get(keyHeader); key != "" {
+					tenant = "key:" + key
+				}
+			}
+			recorder.Record(
+				tenant,
+				r.Method+" "+routePattern(r),
+				int64(mw.BytesWritten()),
+				mw.Status() == http.StatusTooManyRequests,
+			)
+		})
+	}
+}
+
+// tracingMiddleware records a span for every request, continuing the trace of
+// the incoming traceparent header if any. The spans of the database queries
+// and of the path finding run by the request are its children.
+func tracingMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, r.Method, trace.SpanKindServer)
+		defer span.End()
+		r = r.WithContext(ctx)
+		if !span.IsRecording() {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		mw := newWrapResponseWriter(w, r)
+		h.ServeHTTP(mw.(http.ResponseWriter), r)
+
+		route := routePattern(r)
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", route),
+			attribute.String("http.target", r.URL.RequestURI()),
+			attribute.Int("http.status_code", mw.Status()),
+		)
+		if mw.Status() >= 500 {
+			tracing.SetError(span, supportErrors.Errorf("status %d", mw.Status()))
+		}
+	})
+}
+
+// routePattern returns the pattern of the route which handled the request,
+// e.g. /accounts/{account_id}. Requests which were not routed are reported as
+// "unknown" to keep the number of distinct values low.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || len(rctx.RoutePatterns) == 0 {
+		return "unknown"
+	}
+
+	pattern := strings.Join(rctx.RoutePatterns, "")
+	pattern = strings.Replace(pattern, "/*/", "/", -1)
+	if pattern != "/" {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return pattern
+}
+
+// routeMethod returns the method of the request. Methods which are not
+// standard HTTP methods are reported as "other" because clients can send any
+// method, which would add distinct values without limit.
+func routeMethod(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodConnect,
+		http.MethodOptions, http.MethodTrace:
+		return r.Method
+	default:
+		return "other"
+	}
+}
+
 // NewHistoryMiddleware adds session to the request context and ensures Horizon
 // is not in a stale state, which is when the difference between latest core
-// ledger and latest history ledger is higher than the given threshold
-func NewHistoryMiddleware(staleThreshold int32, session *db.Session) func(http.Handler) http.Handler {
+// ledger and latest history ledger is higher than the given threshold. When
+// `replicas` is not nil the session of a fresh read replica is used instead.
+func NewHistoryMiddleware(staleThreshold int32, session *db.Session, replicas *readReplicas) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if staleThreshold > 0 {
-				ls := ledger.CurrentState()
+				ls := ledger.FromContext(r.Context()).CurrentState()
 				isStale := (ls.CoreLatest - ls.HistoryLatest) > int32(staleThreshold)
 				if isStale {
-					err := hProblem.StaleHistory
-					err.Extras = map[string]interface{}{
-						"history_latest_ledger": ls.HistoryLatest,
-						"core_latest_ledger":    ls.CoreLatest,
-					}
+					err := hProblem.NewStaleHistory(ls.HistoryLatest, ls.CoreLatest)
 					problem.Render(r.Context(), w, err)
 					return
 				}
 			}
 
-			requestSession := session.Clone()
+			requestSession := replicas.session(session).Clone()
 			requestSession.Ctx = r.Context()
 			h.ServeHTTP(w, r.WithContext(
 				context.WithValue(
@@ -281,7 +458,10 @@ func NewHistoryMiddleware(staleThreshold int32, session *db.Session) func(http.H
 // has been verified and is correct (Otherwise returns `500 Internal Server Error` to prevent
 // returning invalid data to the user)
 type StateMiddleware struct {
-	HorizonSession      *db.Session
+	HorizonSession *db.Session
+	// ReadReplicas, when set, serve the queries instead of HorizonSession
+	// while they are fresh.
+	ReadReplicas        *readReplicas
 	NoStateVerification bool
 }
 
@@ -316,7 +496,7 @@ func ingestionStatus(q *history.Q) (uint32, bool, error) {
 // WrapFunc executes the middleware on a given HTTP handler function
 func (m *StateMiddleware) WrapFunc(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		session := m.HorizonSession.Clone()
+		session := m.ReadReplicas.session(m.HorizonSession).Clone()
 		q := &history.Q{session}
 		sseRequest := render.Negotiate(r) == render.MimeEventStream
 
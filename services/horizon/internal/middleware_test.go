@@ -1,11 +1,14 @@
 package horizon
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stellar/go/services/horizon/internal/actions"
 	horizonContext "github.com/stellar/go/services/horizon/internal/context"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
@@ -14,10 +17,18 @@ import (
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/test"
 	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/support/tracing"
 	"github.com/stellar/go/xdr"
 	"github.com/stellar/throttled"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type RateLimitMiddlewareTestSuite struct {
@@ -96,6 +107,29 @@ func (suite *RateLimitMiddlewareTestSuite) TestRateLimit_RemoteAddr() {
 	assert.Equal(suite.T(), 429, w.Code)
 }
 
+// Includes the rate limit in the extras of the 429 problem.
+func (suite *RateLimitMiddlewareTestSuite) TestRateLimit_ProblemExtras() {
+	for i := 0; i < 10; i++ {
+		w := suite.rh.Get("/")
+		assert.Equal(suite.T(), 200, w.Code)
+	}
+
+	w := suite.rh.Get("/")
+	assert.Equal(suite.T(), 429, w.Code)
+
+	var p problem.P
+	assert.NoError(suite.T(), json.Unmarshal(w.Body.Bytes(), &p))
+	rateLimit, ok := p.Extras["rate_limit"].(map[string]interface{})
+	if assert.True(suite.T(), ok) {
+		assert.Equal(suite.T(), float64(10), rateLimit["limit"])
+		assert.Equal(suite.T(), float64(0), rateLimit["remaining"])
+		assert.Contains(suite.T(), rateLimit, "reset")
+	}
+	retryAfter, ok := p.Extras["retry_after"].(float64)
+	assert.True(suite.T(), ok)
+	assert.True(suite.T(), retryAfter > 0)
+}
+
 // Restrict based upon X-Forwarded-For correctly.
 func (suite *RateLimitMiddlewareTestSuite) TestRateLimit_XForwardedFor() {
 	for i := 0; i < 10; i++ {
@@ -123,6 +157,38 @@ func TestRateLimitMiddlewareTestSuite(t *testing.T) {
 	suite.Run(t, new(RateLimitMiddlewareTestSuite))
 }
 
+func TestHistoryNotIngestedMiddleware(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	c := NewTestConfig()
+	c.StateOnly = true
+	app := NewApp(c)
+	defer app.Close()
+	rh := NewRequestHelper(app)
+
+	for _, path := range []string{
+		"/transactions",
+		"/operations",
+		"/payments",
+		"/effects",
+		"/trades",
+		"/trade_aggregations",
+		"/accounts/GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2/transactions",
+		"/ledgers/1/transactions",
+		"/ledgers/1/operations",
+	} {
+		w := rh.Get(path)
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+		assert.Contains(t, w.Body.String(), "history_not_ingested", path)
+	}
+
+	w := rh.Get("/ledgers")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = rh.Get("/fee_stats")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestStateMiddleware(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -331,7 +397,7 @@ func TestCheckHistoryStaleMiddleware(t *testing.T) {
 				HistoryLatest: testCase.historyLatest,
 			}
 			ledger.SetState(state)
-			historyMiddleware := NewHistoryMiddleware(testCase.staleThreshold, tt.HorizonSession())
+			historyMiddleware := NewHistoryMiddleware(testCase.staleThreshold, tt.HorizonSession(), nil)
 			handler := historyMiddleware(http.HandlerFunc(endpoint))
 			w := httptest.NewRecorder()
 			handler.ServeHTTP(w, request)
@@ -339,3 +405,92 @@ func TestCheckHistoryStaleMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracing.SetTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.NeverSample())),
+	))
+	defer tracing.SetTracerProvider(noop.NewTracerProvider())
+
+	var requestSpan trace.Span
+	handler := tracingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestSpan = trace.SpanFromContext(r.Context())
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	request, err := http.NewRequest("GET", "http://localhost/paths", nil)
+	assert.NoError(t, err)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, request)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	// The trace of the caller is sampled even though new traces are not.
+	if assert.NotNil(t, requestSpan) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", requestSpan.SpanContext().TraceID().String())
+		assert.True(t, requestSpan.SpanContext().IsSampled())
+	}
+
+	// Requests without traceparent header start new traces.
+	request.Header.Del("traceparent")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	assert.False(t, requestSpan.SpanContext().IsSampled())
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "GET unknown", spans[0].Name())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+	}
+}
+
+func TestRouteMethod(t *testing.T) {
+	for _, method := range []string{"GET", "POST", "OPTIONS"} {
+		request, err := http.NewRequest(method, "http://localhost/paths", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, method, routeMethod(request))
+	}
+
+	// arbitrary methods are reported as a single value
+	request, err := http.NewRequest("RANDOM123", "http://localhost/paths", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "other", routeMethod(request))
+}
+
+func TestLoggerMiddlewareSlowRequest(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+
+	handler := loggerMiddleware(time.Nanosecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := &db.Session{DB: tt.HorizonDB, Ctx: r.Context()}
+		var n int
+		tt.Assert.NoError(session.GetRaw(&n, "SELECT 1 FROM pg_sleep(0.01)"))
+		tt.Assert.NoError(session.GetRaw(&n, "SELECT 1"))
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	done := log.DefaultLogger.StartTest(logrus.InfoLevel)
+	request, err := http.NewRequest("GET", "http://localhost/ledgers", nil)
+	tt.Assert.NoError(err)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	logged := done()
+
+	tt.Assert.Len(logged, 3)
+	tt.Assert.Equal("Finished request", logged[1].Message)
+	tt.Assert.Equal(2, logged[1].Data["sql_count"])
+	tt.Assert.True(logged[1].Data["sql_duration"].(float64) >= 0.01)
+
+	tt.Assert.Equal("Slow request", logged[2].Message)
+	tt.Assert.Equal(logrus.WarnLevel, logged[2].Level)
+	queries := logged[2].Data["queries"].([]log.F)
+	tt.Assert.Len(queries, 2)
+	tt.Assert.Equal("SELECT 1 FROM pg_sleep(0.01)", queries[0]["sql"])
+	tt.Assert.Equal("SELECT 1", queries[1]["sql"])
+
+	// Streaming requests are never slow.
+	done = log.DefaultLogger.StartTest(logrus.InfoLevel)
+	request.Header.Set("Accept", "text/event-stream")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	tt.Assert.Len(done(), 2)
+}
@@ -0,0 +1,112 @@
+package horizon
+
+import (
+	"net/http"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/effects"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/services/horizon/internal/actions"
+	"github.com/stellar/go/services/horizon/internal/openapi"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+// ledgersQuery documents the query params of the ledgers endpoint, which is
+// not served by a handler with a query struct.
+type ledgersQuery struct {
+	StartTime int64 `schema:"start_time" valid:"-"`
+	EndTime   int64 `schema:"end_time" valid:"-"`
+}
+
+// transactionsQuery documents the query params of the transactions endpoints,
+// which are not served by handlers with a query struct.
+type transactionsQuery struct {
+	IncludeFailed bool  `schema:"include_failed" valid:"-"`
+	StartTime     int64 `schema:"start_time" valid:"-"`
+	EndTime       int64 `schema:"end_time" valid:"-"`
+}
+
+// ledgerEstimatesQuery documents the query params of the ledger estimates
+// endpoint, which are read one by one by its handler.
+type ledgerEstimatesQuery struct {
+	Sequence int64  `schema:"sequence" valid:"-"`
+	ClosedAt string `schema:"closed_at" valid:"-"`
+}
+
+// ledgerEntriesQuery documents the query params of the ledger entries
+// endpoint, which are read one by one by its handler.
+type ledgerEntriesQuery struct {
+	Key string `schema:"key" valid:"-"`
+}
+
+// openAPIEndpoints lists the public endpoints described in the OpenAPI
+// document served at /openapi.json. Keep it in sync with mustInstallActions,
+// TestOpenAPIEndpointsMatchRouter checks that every public route is listed.
+var openAPIEndpoints = []openapi.Endpoint{
+	{Method: http.MethodGet, Path: "/", Summary: "Horizon and network details", Response: horizon.Root{}},
+
+	{Method: http.MethodGet, Path: "/accounts", Summary: "List accounts", Query: actions.AccountsQuery{}, Paginated: true, Response: horizon.Account{}, Collection: true},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}", Summary: "Account details", Streamable: true, Response: horizon.Account{}},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/data", Summary: "Data entries of an account", Query: actions.AccountDataQuery{}, Paginated: true, Response: horizon.AccountDataEntry{}, Collection: true},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/data/{key}", Summary: "Account data entry", Response: horizon.AccountData{}},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/offers", Summary: "Offers of an account", Query: actions.AccountOffersQuery{}, Paginated: true, Streamable: true, Response: horizon.Offer{}, Collection: true},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/transactions", Summary: "Transactions of an account", Query: transactionsQuery{}, Paginated: true, Streamable: true, Response: horizon.Transaction{}, Collection: true},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/operations", Summary: "Operations of an account", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/payments", Summary: "Payments of an account", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/effects", Summary: "Effects of an account", Query: actions.EffectsQuery{}, Paginated: true, Streamable: true, Response: effects.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/accounts/{account_id}/trades", Summary: "Trades of an account", Paginated: true, Streamable: true, Response: horizon.Trade{}, Collection: true},
+
+	{Method: http.MethodGet, Path: "/offers", Summary: "List offers", Query: actions.OffersQuery{}, Paginated: true, Response: horizon.Offer{}, Collection: true},
+	{Method: http.MethodGet, Path: "/offers/{id}", Summary: "Offer details", Response: horizon.Offer{}},
+	{Method: http.MethodGet, Path: "/offers/{offer_id}/trades", Summary: "Trades of an offer", Paginated: true, Streamable: true, Response: horizon.Trade{}, Collection: true},
+
+	{Method: http.MethodGet, Path: "/assets", Summary: "List assets", Paginated: true, Response: horizon.AssetStat{}, Collection: true},
+	{Method: http.MethodGet, Path: "/ledger_entries", Summary: "Ledger entry with the given base64 encoded LedgerKey", Query: ledgerEntriesQuery{}, Response: horizon.LedgerEntry{}},
+	{Method: http.MethodGet, Path: "/order_book", Summary: "Order book of an asset pair", Streamable: true, Response: horizon.OrderBookSummary{}},
+	{Method: http.MethodGet, Path: "/paths", Summary: "Find strict receive payment paths, alias of /paths/strict-receive", Query: StrictReceivePathsQuery{}, Response: horizon.Path{}, Collection: true},
+	{Method: http.MethodGet, Path: "/paths/strict-receive", Summary: "Find strict receive payment paths", Query: StrictReceivePathsQuery{}, Response: horizon.Path{}, Collection: true},
+	{Method: http.MethodGet, Path: "/paths/strict-send", Summary: "Find strict send payment paths", Query: FindFixedPathsQuery{}, Response: horizon.Path{}, Collection: true},
+
+	{Method: http.MethodGet, Path: "/ledgers", Summary: "List ledgers", Query: ledgersQuery{}, Paginated: true, Streamable: true, Response: horizon.Ledger{}, Collection: true},
+	{Method: http.MethodGet, Path: "/ledgers/{ledger_id}", Summary: "Ledger details", Response: horizon.Ledger{}},
+	{Method: http.MethodGet, Path: "/ledgers/{ledger_id}/transactions", Summary: "Transactions of a ledger", Query: transactionsQuery{}, Paginated: true, Streamable: true, Response: horizon.Transaction{}, Collection: true},
+	{Method: http.MethodGet, Path: "/ledgers/{ledger_id}/operations", Summary: "Operations of a ledger", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/ledgers/{ledger_id}/payments", Summary: "Payments of a ledger", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/ledgers/{ledger_id}/effects", Summary: "Effects of a ledger", Query: actions.EffectsQuery{}, Paginated: true, Streamable: true, Response: effects.Base{}, Collection: true},
+
+	{Method: http.MethodGet, Path: "/transactions", Summary: "List transactions", Query: transactionsQuery{}, Paginated: true, Streamable: true, Response: horizon.Transaction{}, Collection: true},
+	{Method: http.MethodPost, Path: "/transactions", Summary: "Submit a transaction", Response: horizon.Transaction{}},
+	{Method: http.MethodGet, Path: "/transactions/{tx_id}", Summary: "Transaction details", Response: horizon.Transaction{}},
+	{Method: http.MethodGet, Path: "/transactions/{tx_id}/operations", Summary: "Operations of a transaction", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/transactions/{tx_id}/payments", Summary: "Payments of a transaction", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/transactions/{tx_id}/effects", Summary: "Effects of a transaction", Query: actions.EffectsQuery{}, Paginated: true, Streamable: true, Response: effects.Base{}, Collection: true},
+
+	{Method: http.MethodGet, Path: "/operations", Summary: "List operations", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/operations/{id}", Summary: "Operation details", Response: operations.Base{}},
+	{Method: http.MethodGet, Path: "/operations/{op_id}/effects", Summary: "Effects of an operation", Query: actions.EffectsQuery{}, Paginated: true, Streamable: true, Response: effects.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/payments", Summary: "List payments", Query: actions.OperationsQuery{}, Paginated: true, Streamable: true, Response: operations.Base{}, Collection: true},
+	{Method: http.MethodGet, Path: "/effects", Summary: "List effects", Query: actions.EffectsQuery{}, Paginated: true, Streamable: true, Response: effects.Base{}, Collection: true},
+
+	{Method: http.MethodGet, Path: "/trades", Summary: "List trades", Paginated: true, Streamable: true, Response: horizon.Trade{}, Collection: true},
+	{Method: http.MethodGet, Path: "/trade_aggregations", Summary: "Trade aggregations of an asset pair", Paginated: true, Response: horizon.TradeAggregation{}, Collection: true},
+	{Method: http.MethodGet, Path: "/fee_stats", Summary: "Fee statistics", Response: horizon.FeeStats{}},
+	{Method: http.MethodGet, Path: "/ledger_estimates", Summary: "Estimate the sequence or the close time of a ledger", Query: ledgerEstimatesQuery{}, Response: horizon.LedgerEstimate{}},
+	{Method: http.MethodGet, Path: "/health", Summary: "Health of the instance", Response: Health{}},
+}
+
+// openAPIHandler serves the OpenAPI document describing horizon's endpoints.
+// The document is generated once, when the handler is created.
+func openAPIHandler(version string) (http.HandlerFunc, error) {
+	doc, err := openapi.Generate(openapi.Info{
+		Title:       "Horizon",
+		Description: "Horizon is the client facing API server for the Stellar network.",
+		Version:     version,
+	}, openAPIEndpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		httpjson.Render(w, doc, httpjson.JSON)
+	}, nil
+}
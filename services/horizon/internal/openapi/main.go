@@ -0,0 +1,501 @@
+// Package openapi generates an OpenAPI 3 document describing horizon's
+// endpoints. Parameters are derived from the query structs used by the actions
+// (their `schema` and `valid` tags) and response schemas from the resources
+// defined in the protocols/horizon package.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Version is the version of the OpenAPI specification the generated documents
+// conform to.
+const Version = "3.0.3"
+
+// Document is the root object of an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem describes the operations available on a single path keyed by the
+// lower case http method.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	OperationID string              `json:"operationId"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a single path or query parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// Response describes a single response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType provides the schema of a response for a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of the OpenAPI schema object used by horizon.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Components holds the reusable schemas referenced from the operations.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Endpoint describes a single route of horizon.
+type Endpoint struct {
+	// Method is the http method of the route, e.g. http.MethodGet.
+	Method string
+	// Path is the route in chi syntax, e.g. /accounts/{account_id}.
+	Path    string
+	Summary string
+	// Query is the query struct used by the action to load its parameters or
+	// nil if the route has no query parameters.
+	Query interface{}
+	// Paginated adds the cursor, limit and order parameters.
+	Paginated bool
+	// Streamable marks routes which can be streamed using server-sent events.
+	Streamable bool
+	// Response is the resource rendered by the route.
+	Response interface{}
+	// Collection is true if the route renders a page of Response records.
+	Collection bool
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{([a-z_]+)(?::[^}]*)?\}`)
+
+// Generate builds an OpenAPI document describing the given endpoints.
+func Generate(info Info, endpoints []Endpoint) (Document, error) {
+	g := &generator{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+	doc := Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+	}
+
+	for _, endpoint := range endpoints {
+		path, op, err := g.operation(endpoint)
+		if err != nil {
+			return Document{}, errors.Wrapf(err, "error describing %s %s", endpoint.Method, endpoint.Path)
+		}
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+		method := strings.ToLower(endpoint.Method)
+		if _, ok := item[method]; ok {
+			return Document{}, errors.Errorf("duplicate endpoint %s %s", endpoint.Method, path)
+		}
+		item[method] = op
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc, nil
+}
+
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func (g *generator) operation(endpoint Endpoint) (string, *Operation, error) {
+	// strip regexp constraints from the path parameters
+	path := pathParamRegexp.ReplaceAllString(endpoint.Path, "{$1}")
+
+	op := &Operation{
+		Summary:     endpoint.Summary,
+		OperationID: operationID(endpoint.Method, path),
+		Responses:   map[string]Response{},
+	}
+
+	inPath := map[string]bool{}
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(endpoint.Path, -1) {
+		inPath[match[1]] = true
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if endpoint.Query != nil {
+		params, err := queryParameters(reflect.TypeOf(endpoint.Query))
+		if err != nil {
+			return "", nil, err
+		}
+		for _, param := range params {
+			if !inPath[param.Name] {
+				op.Parameters = append(op.Parameters, param)
+			}
+		}
+	}
+
+	if endpoint.Paginated {
+		op.Parameters = append(op.Parameters,
+			Parameter{Name: "cursor", In: "query", Schema: &Schema{Type: "string"}},
+			Parameter{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Format: "int64"}},
+			Parameter{Name: "order", In: "query", Schema: &Schema{Type: "string", Enum: []string{"asc", "desc"}}},
+		)
+	}
+
+	response := Response{Description: "Success"}
+	if endpoint.Response != nil {
+		schema := g.schema(reflect.TypeOf(endpoint.Response))
+		if endpoint.Collection {
+			schema = pageSchema(schema)
+		}
+		response.Content = map[string]MediaType{
+			"application/hal+json": {Schema: schema},
+		}
+		if endpoint.Streamable {
+			response.Content["text/event-stream"] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+	}
+	op.Responses["200"] = response
+	op.Responses["default"] = Response{
+		Description: "Error",
+		Content: map[string]MediaType{
+			"application/problem+json": {Schema: problemSchema},
+		},
+	}
+
+	return path, op, nil
+}
+
+// operationID builds a stable identifier from the method and the path, e.g.
+// getAccountsAccountIdOffers.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '_' || r == '-' || r == '.'
+	}) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	if path == "/" {
+		id += "Root"
+	}
+	return id
+}
+
+// queryParameters returns the parameters of a query struct based on the
+// `schema` and `valid` tags of its fields.
+func queryParameters(t reflect.Type) ([]Parameter, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, errors.Errorf("query must be a struct, got %s", t)
+	}
+
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("schema")
+		if field.Anonymous && name == "" {
+			embedded, err := queryParameters(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, embedded...)
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+
+		// slices are decoded from repeated params, e.g. ?type=a&type=b
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+		}
+		schema, err := primitiveSchema(fieldType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid field %s", field.Name)
+		}
+
+		valid := field.Tag.Get("valid")
+		if enum := enumValues(valid); len(enum) > 0 {
+			schema.Enum = enum
+		}
+		if fieldType != field.Type {
+			schema = &Schema{Type: "array", Items: schema}
+		}
+
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: isRequired(valid),
+			Schema:   schema,
+		})
+	}
+	return params, nil
+}
+
+// isRequired follows the semantics of govalidator: fields with validators are
+// required unless marked as optional.
+func isRequired(valid string) bool {
+	if valid == "" || valid == "-" {
+		return false
+	}
+	for _, option := range strings.Split(valid, ",") {
+		if option == "optional" {
+			return false
+		}
+	}
+	return true
+}
+
+var inValidatorRegexp = regexp.MustCompile(`in\(([^)]*)\)`)
+
+func enumValues(valid string) []string {
+	match := inValidatorRegexp.FindStringSubmatch(valid)
+	if match == nil {
+		return nil
+	}
+	return strings.Split(match[1], "|")
+}
+
+func primitiveSchema(t reflect.Type) (*Schema, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	}
+	return nil, errors.Errorf("unsupported parameter type %s", t)
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schema returns the schema of a resource type. Named structs are added to
+// the components and referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		s := g.schema(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(jsonMarshalerType) && t.Kind() != reflect.Struct:
+		// custom marshalers of primitives are rendered as strings
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := g.componentName(t)
+		if _, ok := g.schemas[name]; !ok {
+			// reserve the name before recursing to support recursive types
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	}
+
+	s, err := primitiveSchema(t)
+	if err != nil {
+		return &Schema{}
+	}
+	return s
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(s, t)
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(s, embedded)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schema(field.Type)
+		for _, option := range parts[1:] {
+			if option == "string" {
+				fieldSchema = &Schema{Type: "string"}
+			}
+		}
+		s.Properties[name] = fieldSchema
+	}
+}
+
+// componentName returns a unique name for a named type. Types of the horizon
+// protocol package keep their name, others are prefixed with their package
+// name, e.g. OperationsPayment.
+func (g *generator) componentName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+	if pkg != "horizon" {
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + strings.ToUpper(name[:1]) + name[1:]
+	}
+
+	unique := name
+	for i := 2; g.taken(unique); i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[t] = unique
+	return unique
+}
+
+func (g *generator) taken(name string) bool {
+	for _, n := range g.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func pageSchema(record *Schema) *Schema {
+	link := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"href":      {Type: "string"},
+			"templated": {Type: "boolean"},
+		},
+	}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"_links": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"self": link,
+					"next": link,
+					"prev": link,
+				},
+			},
+			"_embedded": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"records": {Type: "array", Items: record},
+				},
+			},
+		},
+	}
+}
+
+var problemSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"type":   {Type: "string"},
+		"title":  {Type: "string"},
+		"status": {Type: "integer", Format: "int32"},
+		"detail": {Type: "string"},
+		"extras": {Type: "object"},
+	},
+}
+
+// SortedPaths returns the paths of the document in lexical order.
+func (d Document) SortedPaths() []string {
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
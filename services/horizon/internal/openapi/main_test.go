@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type embeddedQuery struct {
+	Selling string `schema:"selling" valid:"assetType,optional"`
+}
+
+type testQuery struct {
+	embeddedQuery `valid:"-"`
+	AccountID     string   `schema:"account_id" valid:"accountID,optional"`
+	Amount        string   `schema:"amount" valid:"amount"`
+	Join          string   `schema:"join" valid:"in(transactions),optional"`
+	Limit         uint64   `schema:"max" valid:"-"`
+	Types         []string `schema:"type" valid:"-"`
+	ignored       string
+}
+
+type testLink struct {
+	Href string `json:"href"`
+}
+
+type testResource struct {
+	Links struct {
+		Self testLink `json:"self"`
+	} `json:"_links"`
+	ID        string     `json:"id"`
+	Sequence  int64      `json:"sequence,string"`
+	ClosedAt  time.Time  `json:"closed_at"`
+	Balances  []testLink `json:"balances"`
+	Parent    *testResource
+	Internal  string `json:"-"`
+	unexposed string
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(Info{Title: "Test", Version: "1.0"}, []Endpoint{
+		{
+			Method:     http.MethodGet,
+			Path:       "/accounts/{account_id:\\w+}/things",
+			Summary:    "List things",
+			Query:      testQuery{},
+			Paginated:  true,
+			Streamable: true,
+			Response:   testResource{},
+			Collection: true,
+		},
+		{
+			Method:   http.MethodGet,
+			Path:     "/things/{id}",
+			Response: &testResource{},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, []string{"/accounts/{account_id}/things", "/things/{id}"}, doc.SortedPaths())
+
+	op := doc.Paths["/accounts/{account_id}/things"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "getAccountsAccountIdThings", op.OperationID)
+
+	params := map[string]Parameter{}
+	for _, param := range op.Parameters {
+		params[param.Name] = param
+	}
+	assert.Len(t, params, 9)
+	assert.Equal(t, "path", params["account_id"].In)
+	assert.True(t, params["account_id"].Required)
+	assert.Equal(t, "query", params["selling"].In)
+	assert.False(t, params["selling"].Required)
+	assert.True(t, params["amount"].Required)
+	assert.Equal(t, []string{"transactions"}, params["join"].Schema.Enum)
+	assert.Equal(t, "integer", params["max"].Schema.Type)
+	assert.Equal(t, "array", params["type"].Schema.Type)
+	assert.Equal(t, "string", params["type"].Schema.Items.Type)
+	assert.Equal(t, []string{"asc", "desc"}, params["order"].Schema.Enum)
+	assert.Contains(t, params, "cursor")
+	assert.Contains(t, params, "limit")
+
+	content := op.Responses["200"].Content
+	assert.Contains(t, content, "text/event-stream")
+	page := content["application/hal+json"].Schema
+	records := page.Properties["_embedded"].Properties["records"]
+	assert.Equal(t, "#/components/schemas/OpenapiTestResource", records.Items.Ref)
+
+	resource := doc.Components.Schemas["OpenapiTestResource"]
+	require.NotNil(t, resource)
+	assert.Equal(t, "string", resource.Properties["sequence"].Type)
+	assert.Equal(t, "date-time", resource.Properties["closed_at"].Format)
+	assert.Equal(t, "#/components/schemas/OpenapiTestLink", resource.Properties["balances"].Items.Ref)
+	assert.Equal(t, "#/components/schemas/OpenapiTestResource", resource.Properties["Parent"].Ref)
+	assert.Equal(t, "#/components/schemas/OpenapiTestLink", resource.Properties["_links"].Properties["self"].Ref)
+	assert.NotContains(t, resource.Properties, "Internal")
+	assert.NotContains(t, resource.Properties, "unexposed")
+
+	show := doc.Paths["/things/{id}"]["get"]
+	assert.Equal(t, "#/components/schemas/OpenapiTestResource", show.Responses["200"].Content["application/hal+json"].Schema.Ref)
+}
+
+func TestGenerateDuplicateEndpoint(t *testing.T) {
+	_, err := Generate(Info{}, []Endpoint{
+		{Method: http.MethodGet, Path: "/things/{id}"},
+		{Method: http.MethodGet, Path: "/things/{id:\\d+}"},
+	})
+	assert.EqualError(t, err, "duplicate endpoint GET /things/{id}")
+}
+
+func TestGenerateInvalidQuery(t *testing.T) {
+	_, err := Generate(Info{}, []Endpoint{
+		{Method: http.MethodGet, Path: "/things", Query: struct {
+			Things map[string]string `schema:"things"`
+		}{}},
+	})
+	assert.Error(t, err)
+}
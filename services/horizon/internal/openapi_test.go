@@ -0,0 +1,55 @@
+package horizon
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+)
+
+// undocumentedRoutes are the public routes which are not described in the
+// OpenAPI document.
+var undocumentedRoutes = map[string]bool{
+	// the document itself
+	"/openapi.json": true,
+	// redirects to the configured friendbot, which has its own API
+	"/friendbot": true,
+}
+
+// routeParamRegexp matches the regexps of route params, e.g. `:\w+` in
+// {account_id:\w+}.
+var routeParamRegexp = regexp.MustCompile(`\{(\w+):[^}]*\}`)
+
+func TestOpenAPIEndpointsMatchRouter(t *testing.T) {
+	w := mustInitWeb(context.Background(), &history.Q{}, time.Second, 0)
+	w.mustInstallActions(Config{}, nil, nil, metrics.NewRegistry())
+
+	documented := map[string]bool{}
+	for _, endpoint := range openAPIEndpoints {
+		documented[endpoint.Method+" "+endpoint.Path] = true
+	}
+
+	routes := 0
+	err := chi.Walk(w.router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		path := routeParamRegexp.ReplaceAllString(route, "{$1}")
+		if path != "/" {
+			path = strings.TrimSuffix(path, "/")
+		}
+		routes++
+		if !undocumentedRoutes[path] {
+			assert.True(t, documented[method+" "+path], "%s %s is missing from openAPIEndpoints", method, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, routes)
+}
@@ -48,34 +48,64 @@ type State struct {
 	LedgerCapacityUsage string
 }
 
+// Store holds a cached snapshot of the operation fee state. A horizon process
+// serving more than one network keeps a separate Store for every network.
+type Store struct {
+	lock    sync.RWMutex
+	current State
+	present bool
+}
+
 // CurrentState returns the cached snapshot of operation fee state and a boolean indicating
 // if the cache has been populated
-func CurrentState() (State, bool) {
-	lock.RLock()
-	ret := current
-	ok := present
-	lock.RUnlock()
+func (s *Store) CurrentState() (State, bool) {
+	s.lock.RLock()
+	ret := s.current
+	ok := s.present
+	s.lock.RUnlock()
 	return ret, ok
 }
 
 // SetState updates the cached snapshot of the operation fee state
-func SetState(next State) {
-	lock.Lock()
+func (s *Store) SetState(next State) {
+	s.lock.Lock()
 	// in case of one query taking longer than another, this makes
 	// sure we don't overwrite the latest fee stats with old stats
-	if current.LastLedger < next.LastLedger {
-		current = next
+	if s.current.LastLedger < next.LastLedger {
+		s.current = next
 	}
-	present = true
-	lock.Unlock()
+	s.present = true
+	s.lock.Unlock()
+}
+
+// Reset clears the cached snapshot of the operation fee state
+func (s *Store) Reset() {
+	s.lock.Lock()
+	s.current = State{}
+	s.present = false
+	s.lock.Unlock()
+}
+
+// DefaultStore returns the process wide store used by CurrentState and
+// SetState.
+func DefaultStore() *Store {
+	return defaultStore
+}
+
+// CurrentState returns the cached snapshot of operation fee state and a boolean indicating
+// if the cache has been populated
+func CurrentState() (State, bool) {
+	return defaultStore.CurrentState()
+}
+
+// SetState updates the cached snapshot of the operation fee state
+func SetState(next State) {
+	defaultStore.SetState(next)
 }
 
 // ResetState is used only for testing purposes
 func ResetState() {
-	current = State{}
-	present = false
+	defaultStore.Reset()
 }
 
-var current State
-var present bool
-var lock sync.RWMutex
+var defaultStore = &Store{}
@@ -0,0 +1,188 @@
+package paths
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+var _ Finder = (*CachedFinder)(nil)
+
+// cacheRefreshFrequency is how often CachedFinder checks whether the order book
+// has changed.
+const cacheRefreshFrequency = time.Second
+
+// cachedQuery tracks how often a query is requested and holds its paths once
+// the query is among the most requested ones.
+type cachedQuery struct {
+	requests uint64
+	find     func() ([]Path, uint32, error)
+	cached   bool
+	paths    []Path
+	ledger   uint32
+}
+
+// CachedFinder is a Finder which precomputes the paths of the most frequently
+// requested queries every time the order book changes and serves those
+// queries from its cache. All other queries, as well as queries whose cached
+// paths are older than the order book, are passed to the wrapped Finder.
+//
+// Only queries which do not depend on the balances of a source account are
+// cached.
+type CachedFinder struct {
+	finder     Finder
+	lastLedger func() uint32
+	size       int
+
+	lock    sync.Mutex
+	queries map[string]*cachedQuery
+}
+
+// NewCachedFinder constructs a CachedFinder caching the paths of the `size`
+// most requested queries. `lastLedger` returns the last ledger applied to the
+// order book used by `finder`.
+func NewCachedFinder(finder Finder, lastLedger func() uint32, size int) *CachedFinder {
+	return &CachedFinder{
+		finder:     finder,
+		lastLedger: lastLedger,
+		size:       size,
+		queries:    map[string]*cachedQuery{},
+	}
+}
+
+// Find implements the Finder interface
+func (c *CachedFinder) Find(q Query, maxLength uint) ([]Path, uint32, error) {
+	find := func() ([]Path, uint32, error) {
+		return c.finder.Find(q, maxLength)
+	}
+	if q.ValidateSourceBalance || q.SourceAccount != nil {
+		return find()
+	}
+
+	key := []string{"find", q.DestinationAsset.String(), strconv.FormatInt(int64(q.DestinationAmount), 10)}
+	key = append(key, strconv.FormatUint(uint64(maxLength), 10))
+	for _, asset := range q.SourceAssets {
+		key = append(key, asset.String())
+	}
+	return c.get(strings.Join(key, "|"), find)
+}
+
+// FindFixedPaths implements the Finder interface
+func (c *CachedFinder) FindFixedPaths(
+	sourceAsset xdr.Asset,
+	amountToSpend xdr.Int64,
+	destinationAssets []xdr.Asset,
+	maxLength uint,
+) ([]Path, uint32, error) {
+	find := func() ([]Path, uint32, error) {
+		return c.finder.FindFixedPaths(sourceAsset, amountToSpend, destinationAssets, maxLength)
+	}
+
+	key := []string{"fixed", sourceAsset.String(), strconv.FormatInt(int64(amountToSpend), 10)}
+	key = append(key, strconv.FormatUint(uint64(maxLength), 10))
+	for _, asset := range destinationAssets {
+		key = append(key, asset.String())
+	}
+	return c.get(strings.Join(key, "|"), find)
+}
+
+// get records a request for the query identified by `key` and returns its
+// cached paths if they are up to date with the order book. Otherwise the paths
+// are computed with `find`.
+func (c *CachedFinder) get(key string, find func() ([]Path, uint32, error)) ([]Path, uint32, error) {
+	lastLedger := c.lastLedger()
+
+	c.lock.Lock()
+	query, ok := c.queries[key]
+	if !ok {
+		query = &cachedQuery{find: find}
+		c.queries[key] = query
+	}
+	query.requests++
+	if query.cached && query.ledger == lastLedger {
+		paths, ledger := query.paths, query.ledger
+		c.lock.Unlock()
+		return paths, ledger, nil
+	}
+	c.lock.Unlock()
+
+	return find()
+}
+
+// Refresh recomputes the paths of the most requested queries whose cached
+// paths are older than the order book and drops the paths of all other
+// queries. Request counts decay on every refresh so that the cache follows
+// changes in traffic, queries which are no longer requested are forgotten.
+func (c *CachedFinder) Refresh() {
+	lastLedger := c.lastLedger()
+
+	c.lock.Lock()
+	keys := make([]string, 0, len(c.queries))
+	for key, query := range c.queries {
+		if query.requests == 0 {
+			delete(c.queries, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.queries[keys[i]].requests > c.queries[keys[j]].requests
+	})
+
+	var stale []*cachedQuery
+	for i, key := range keys {
+		query := c.queries[key]
+		query.requests /= 2
+		if i >= c.size {
+			query.cached = false
+			query.paths = nil
+		} else if !query.cached || query.ledger != lastLedger {
+			stale = append(stale, query)
+		}
+	}
+	c.lock.Unlock()
+
+	for _, query := range stale {
+		// the queries are computed without holding the lock so that requests
+		// are not blocked by the refresh
+		paths, ledger, err := query.find()
+
+		c.lock.Lock()
+		if err != nil {
+			query.cached = false
+			query.paths = nil
+		} else {
+			query.cached = true
+			query.paths = paths
+			query.ledger = ledger
+		}
+		c.lock.Unlock()
+	}
+}
+
+// Run refreshes the cache every time the order book changes until `ctx` is
+// cancelled.
+func (c *CachedFinder) Run(ctx context.Context) {
+	ticker := time.NewTicker(cacheRefreshFrequency)
+	defer ticker.Stop()
+
+	var refreshedLedger uint32
+	for {
+		select {
+		case <-ticker.C:
+			if lastLedger := c.lastLedger(); lastLedger != refreshedLedger {
+				c.Refresh()
+				refreshedLedger = lastLedger
+			}
+		case <-ctx.Done():
+			log.Info("shutting down path cache")
+			return
+		}
+	}
+}
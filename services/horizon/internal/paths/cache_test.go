@@ -0,0 +1,95 @@
+package paths
+
+import (
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedFinder(t *testing.T) {
+	native := xdr.MustNewNativeAsset()
+	usd := xdr.MustNewCreditAsset("USD", "GDUKMGUGDZQK6YHYA5Z6AY2G4XDSZPSZ3SW5UN3ARVMO6QSRDWP5YLEX")
+	eur := xdr.MustNewCreditAsset("EUR", "GDUKMGUGDZQK6YHYA5Z6AY2G4XDSZPSZ3SW5UN3ARVMO6QSRDWP5YLEX")
+
+	popular := Query{
+		DestinationAsset:    usd,
+		DestinationAmount:   100,
+		SourceAssets:        []xdr.Asset{native},
+		SourceAssetBalances: []xdr.Int64{0},
+	}
+	rare := Query{
+		DestinationAsset:    eur,
+		DestinationAmount:   100,
+		SourceAssets:        []xdr.Asset{native},
+		SourceAssetBalances: []xdr.Int64{0},
+	}
+	popularPaths := []Path{{Source: native, SourceAmount: 10, Destination: usd, DestinationAmount: 100}}
+	rarePaths := []Path{{Source: native, SourceAmount: 20, Destination: eur, DestinationAmount: 100}}
+
+	ledger := uint32(2)
+	finder := &MockFinder{}
+	cache := NewCachedFinder(finder, func() uint32 { return ledger }, 1)
+
+	finder.On("Find", popular, uint(3)).Return(popularPaths, ledger, nil).Times(3)
+	finder.On("Find", rare, uint(3)).Return(rarePaths, ledger, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		paths, lastLedger, err := cache.Find(popular, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, popularPaths, paths)
+		assert.Equal(t, uint32(2), lastLedger)
+	}
+	_, _, err := cache.Find(rare, 3)
+	assert.NoError(t, err)
+
+	// only the most requested query is precomputed
+	cache.Refresh()
+	finder.AssertNumberOfCalls(t, "Find", 4)
+
+	paths, lastLedger, err := cache.Find(popular, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, popularPaths, paths)
+	assert.Equal(t, uint32(2), lastLedger)
+	finder.AssertNumberOfCalls(t, "Find", 4)
+
+	// cached paths are not served once the order book has changed
+	ledger = 3
+	finder.On("Find", popular, uint(3)).Return(popularPaths, ledger, nil).Twice()
+	_, lastLedger, err = cache.Find(popular, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), lastLedger)
+	finder.AssertNumberOfCalls(t, "Find", 5)
+
+	cache.Refresh()
+	finder.AssertNumberOfCalls(t, "Find", 6)
+	_, lastLedger, err = cache.Find(popular, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), lastLedger)
+	finder.AssertNumberOfCalls(t, "Find", 6)
+}
+
+func TestCachedFinderSkipsSourceAccountQueries(t *testing.T) {
+	native := xdr.MustNewNativeAsset()
+	account := xdr.MustAddress("GDUKMGUGDZQK6YHYA5Z6AY2G4XDSZPSZ3SW5UN3ARVMO6QSRDWP5YLEX")
+	query := Query{
+		DestinationAsset:      native,
+		DestinationAmount:     100,
+		SourceAssets:          []xdr.Asset{native},
+		SourceAssetBalances:   []xdr.Int64{1000},
+		ValidateSourceBalance: true,
+		SourceAccount:         &account,
+	}
+
+	finder := &MockFinder{}
+	cache := NewCachedFinder(finder, func() uint32 { return 2 }, 10)
+	finder.On("Find", query, uint(3)).Return([]Path{}, uint32(2), nil)
+
+	for i := 0; i < 3; i++ {
+		_, _, err := cache.Find(query, 3)
+		assert.NoError(t, err)
+		cache.Refresh()
+	}
+	finder.AssertNumberOfCalls(t, "Find", 3)
+	assert.Empty(t, cache.queries)
+}
@@ -0,0 +1,119 @@
+package horizon
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/throttled"
+)
+
+// Rate limit groups which can be given their own quota in the rate limit
+// config file. Requests which don't belong to the submission or path finding
+// groups belong to the reads group.
+const (
+	RateLimitGroupSubmission  = "submission"
+	RateLimitGroupPathFinding = "path_finding"
+	RateLimitGroupReads       = "reads"
+)
+
+// rateLimiterFactory creates the rate limiter of a rate limit group.
+type rateLimiterFactory func(group string, quota throttled.RateQuota) (throttled.RateLimiter, error)
+
+// newLocalRateLimiter returns a rate limiter counting the requests received
+// by this horizon instance only.
+func newLocalRateLimiter(group string, quota throttled.RateQuota) (throttled.RateLimiter, error) {
+	return throttled.NewGCRARateLimiter(LRUCacheSize, quota)
+}
+
+// rateLimitFileQuota is the quota of a rate limit group in the rate limit
+// config file.
+type rateLimitFileQuota struct {
+	PerHour int `toml:"per_hour"`
+	Burst   int `toml:"burst"`
+}
+
+// ReadRateLimitConfig reads the per route group rate limits from the TOML file
+// at `path`. Every group is a table with the number of requests allowed per
+// hour and per remote ip address and the number of requests allowed in a
+// burst, e.g.:
+//
+//	[submission]
+//	per_hour = 600
+//	burst = 10
+func ReadRateLimitConfig(path string) (map[string]throttled.RateQuota, error) {
+	var file map[string]rateLimitFileQuota
+	metadata, err := toml.DecodeFile(path, &file)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode rate limit config")
+	}
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		return nil, errors.Errorf("unknown rate limit config fields: %v", undecoded)
+	}
+
+	quotas := map[string]throttled.RateQuota{}
+	for group, quota := range file {
+		switch group {
+		case RateLimitGroupSubmission, RateLimitGroupPathFinding, RateLimitGroupReads:
+		default:
+			return nil, errors.Errorf("unknown rate limit group: %s", group)
+		}
+		if quota.PerHour <= 0 {
+			return nil, errors.Errorf("per_hour of rate limit group %s must be positive", group)
+		}
+		if quota.Burst < 0 {
+			return nil, errors.Errorf("burst of rate limit group %s must not be negative", group)
+		}
+
+		quotas[group] = throttled.RateQuota{
+			MaxRate:  throttled.PerHour(quota.PerHour),
+			MaxBurst: quota.Burst,
+		}
+	}
+	return quotas, nil
+}
+
+// rateLimitGroup returns the rate limit group of a request to the router
+// mounted under `pathPrefix`.
+func rateLimitGroup(r *http.Request, pathPrefix string) string {
+	path := strings.TrimPrefix(r.URL.Path, pathPrefix)
+	switch {
+	case r.Method == http.MethodPost && strings.TrimSuffix(path, "/") == "/transactions":
+		return RateLimitGroupSubmission
+	case path == "/paths" || strings.HasPrefix(path, "/paths/"):
+		return RateLimitGroupPathFinding
+	default:
+		return RateLimitGroupReads
+	}
+}
+
+// rateLimitExtras returns the problem extras of a rate limited request, built
+// from the rate limit headers set by the rate limiter: the number of seconds
+// to wait before retrying the request in `retry_after` and the limit, the
+// remaining requests and the seconds until the limit is reset in `rate_limit`.
+func rateLimitExtras(header http.Header) map[string]interface{} {
+	rateLimit := map[string]int{}
+	for field, name := range map[string]string{
+		"limit":     "X-RateLimit-Limit",
+		"remaining": "X-RateLimit-Remaining",
+		"reset":     "X-RateLimit-Reset",
+	} {
+		if value, err := strconv.Atoi(header.Get(name)); err == nil {
+			rateLimit[field] = value
+		}
+	}
+
+	extras := map[string]interface{}{}
+	if retryAfter, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		extras["retry_after"] = retryAfter
+	} else if reset, ok := rateLimit["reset"]; ok {
+		// waiting until the limit is reset is always enough
+		extras["retry_after"] = reset
+	}
+	if len(rateLimit) > 0 {
+		extras["rate_limit"] = rateLimit
+	}
+	return extras
+}
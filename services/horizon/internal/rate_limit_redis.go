@@ -0,0 +1,162 @@
+package horizon
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/redis"
+	"github.com/stellar/throttled"
+)
+
+// redisRateLimitTimeout is the time a request waits for redis before it is
+// rate limited by the local rate limiter instead.
+const redisRateLimitTimeout = time.Second
+
+// gcraScript implements the GCRA algorithm of throttled.GCRARateLimiter in
+// redis so that all the horizon instances using the same redis server count
+// the requests of a client together. The theoretical arrival time of the next
+// request of a key is stored in microseconds, using the clock of redis.
+//
+// ARGV are the emission interval and the delay variation tolerance in
+// microseconds and the quantity of the request. It returns whether the request
+// is limited, the time until the key is reset and the time to wait before
+// retrying, or -1.
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local emission_interval = tonumber(ARGV[1])
+local delay_variation_tolerance = tonumber(ARGV[2])
+local increment = emission_interval * tonumber(ARGV[3])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+  tat = now
+end
+local new_tat = tat + increment
+
+local diff = now - (new_tat - delay_variation_tolerance)
+if diff < 0 then
+  local retry_after = -1
+  if increment <= delay_variation_tolerance then
+    retry_after = -diff
+  end
+  return {1, tat - now, retry_after}
+end
+
+local ttl = new_tat - now
+redis.call('SET', KEYS[1], string.format('%.0f', new_tat), 'PX', math.ceil(ttl / 1000))
+return {0, ttl, -1}
+`)
+
+// redisRateLimiter is a rate limiter shared by all the horizon instances
+// using the same redis server. When redis cannot be reached requests are rate
+// limited by a local rate limiter with the same quota.
+type redisRateLimiter struct {
+	client                  redis.Client
+	keyPrefix               string
+	limit                   int
+	emissionInterval        time.Duration
+	delayVariationTolerance time.Duration
+	local                   throttled.RateLimiter
+	// failing is 1 while redis cannot be reached. It is accessed atomically.
+	failing int32
+}
+
+// newRedisRateLimiterFactory returns a factory of rate limiters counting the
+// requests of the clients in redis, under keys prefixed with `keyPrefix` and
+// their rate limit group.
+func newRedisRateLimiterFactory(client redis.Client, keyPrefix string) rateLimiterFactory {
+	return func(group string, quota throttled.RateQuota) (throttled.RateLimiter, error) {
+		return newRedisRateLimiter(client, keyPrefix+":"+group+":", quota)
+	}
+}
+
+// newRedisRateLimiter returns a rate limiter storing the state of the keys
+// prefixed with `keyPrefix` in redis.
+func newRedisRateLimiter(client redis.Client, keyPrefix string, quota throttled.RateQuota) (*redisRateLimiter, error) {
+	local, err := throttled.NewGCRARateLimiter(LRUCacheSize, quota)
+	if err != nil {
+		return nil, err
+	}
+
+	// throttled.Rate does not export its period. The time until a key is
+	// reset after its first request is the emission interval.
+	_, result, err := local.RateLimit("\x00emission_interval", 1)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute the emission interval")
+	}
+	if result.ResetAfter <= 0 {
+		return nil, errors.New("invalid rate quota")
+	}
+
+	return &redisRateLimiter{
+		client:                  client,
+		keyPrefix:               keyPrefix,
+		limit:                   quota.MaxBurst + 1,
+		emissionInterval:        result.ResetAfter,
+		delayVariationTolerance: result.ResetAfter * time.Duration(quota.MaxBurst+1),
+		local:                   local,
+	}, nil
+}
+
+// RateLimit implements throttled.RateLimiter.
+func (l *redisRateLimiter) RateLimit(key string, quantity int) (bool, throttled.RateLimitResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRateLimitTimeout)
+	defer cancel()
+
+	limited, ttl, retryAfter, err := l.run(ctx, key, quantity)
+	if err != nil {
+		if atomic.CompareAndSwapInt32(&l.failing, 0, 1) {
+			log.WithField("err", err).Error("Could not rate limit with redis, rate limiting with the local rate limiter")
+		}
+		return l.local.RateLimit(key, quantity)
+	}
+	if atomic.CompareAndSwapInt32(&l.failing, 1, 0) {
+		log.Info("Rate limiting with redis again")
+	}
+
+	result := throttled.RateLimitResult{
+		Limit:      l.limit,
+		ResetAfter: ttl,
+		RetryAfter: retryAfter,
+	}
+	if next := l.delayVariationTolerance - ttl; next > -l.emissionInterval {
+		result.Remaining = int(next / l.emissionInterval)
+	}
+	return limited, result, nil
+}
+
+func (l *redisRateLimiter) run(ctx context.Context, key string, quantity int) (bool, time.Duration, time.Duration, error) {
+	reply, err := gcraScript.Run(
+		ctx,
+		l.client,
+		[]string{l.keyPrefix + key},
+		l.emissionInterval.Microseconds(),
+		l.delayVariationTolerance.Microseconds(),
+		quantity,
+	)
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return false, 0, 0, errors.Errorf("unexpected reply %v", reply)
+	}
+	var integers [3]int64
+	for i, value := range values {
+		if integers[i], ok = value.(int64); !ok {
+			return false, 0, 0, errors.Errorf("unexpected reply %v", reply)
+		}
+	}
+
+	retryAfter := time.Duration(-1)
+	if integers[2] >= 0 {
+		retryAfter = time.Duration(integers[2]) * time.Microsecond
+	}
+	return integers[0] == 1, time.Duration(integers[1]) * time.Microsecond, retryAfter, nil
+}
@@ -0,0 +1,56 @@
+package horizon
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/support/redis"
+	"github.com/stellar/throttled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisRateLimiter(t *testing.T) {
+	client, err := redis.NewClient("redis://localhost")
+	require.NoError(t, err)
+
+	limiter, err := newRedisRateLimiter(client, "prefix:", throttled.RateQuota{
+		MaxRate:  throttled.PerHour(3600),
+		MaxBurst: 9,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10, limiter.limit)
+	assert.Equal(t, time.Second, limiter.emissionInterval)
+	assert.Equal(t, 10*time.Second, limiter.delayVariationTolerance)
+}
+
+func TestRedisRateLimiterFallsBackToLocal(t *testing.T) {
+	// a closed listener gives an address refusing connections
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	client, err := redis.NewClient("redis://" + addr)
+	require.NoError(t, err)
+	defer client.Close()
+
+	newRateLimiter := newRedisRateLimiterFactory(client, "horizon")
+	limiter, err := newRateLimiter(RateLimitGroupReads, throttled.RateQuota{
+		MaxRate:  throttled.PerHour(3600),
+		MaxBurst: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "horizon:reads:", limiter.(*redisRateLimiter).keyPrefix)
+
+	for i := 0; i < 2; i++ {
+		limited, _, err := limiter.RateLimit("127.0.0.1", 1)
+		require.NoError(t, err)
+		assert.False(t, limited)
+	}
+	limited, result, err := limiter.RateLimit("127.0.0.1", 1)
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.Equal(t, 2, result.Limit)
+}
@@ -0,0 +1,127 @@
+package horizon
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stellar/throttled"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRateLimitConfig(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "rate-limit-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestReadRateLimitConfig(t *testing.T) {
+	path := writeRateLimitConfig(t, `
+[submission]
+per_hour = 600
+burst = 10
+
+[path_finding]
+per_hour = 360
+`)
+	defer os.Remove(path)
+
+	quotas, err := ReadRateLimitConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]throttled.RateQuota{
+		RateLimitGroupSubmission:  {MaxRate: throttled.PerHour(600), MaxBurst: 10},
+		RateLimitGroupPathFinding: {MaxRate: throttled.PerHour(360), MaxBurst: 0},
+	}, quotas)
+
+	for _, content := range []string{
+		"[streams]\nper_hour = 1\n",
+		"[reads]\nper_hour = 0\n",
+		"[reads]\nper_hour = 10\nburst = -1\n",
+		"[reads]\nper_minute = 10\n",
+		"not toml",
+	} {
+		path := writeRateLimitConfig(t, content)
+		_, err := ReadRateLimitConfig(path)
+		assert.Error(t, err, content)
+		os.Remove(path)
+	}
+}
+
+func TestRateLimitGroup(t *testing.T) {
+	for _, tc := range []struct {
+		method   string
+		path     string
+		prefix   string
+		expected string
+	}{
+		{"POST", "/transactions", "", RateLimitGroupSubmission},
+		{"GET", "/transactions", "", RateLimitGroupReads},
+		{"GET", "/paths", "", RateLimitGroupPathFinding},
+		{"GET", "/paths/strict-send", "", RateLimitGroupPathFinding},
+		{"GET", "/ledgers", "", RateLimitGroupReads},
+		{"POST", "/testnet/transactions", "/testnet", RateLimitGroupSubmission},
+		{"GET", "/testnet/paths/strict-receive", "/testnet", RateLimitGroupPathFinding},
+	} {
+		r := httptest.NewRequest(tc.method, tc.path, nil)
+		assert.Equal(t, tc.expected, rateLimitGroup(r, tc.prefix), tc.method+" "+tc.path)
+	}
+}
+
+func TestRateLimitExtras(t *testing.T) {
+	header := http.Header{}
+	assert.Equal(t, map[string]interface{}{}, rateLimitExtras(header))
+
+	header.Set("X-RateLimit-Limit", "10")
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", "360")
+	assert.Equal(t, map[string]interface{}{
+		"retry_after": 360,
+		"rate_limit": map[string]int{
+			"limit":     10,
+			"remaining": 0,
+			"reset":     360,
+		},
+	}, rateLimitExtras(header))
+
+	header.Set("Retry-After", "12")
+	assert.Equal(t, 12, rateLimitExtras(header)["retry_after"])
+}
+
+func TestRateLimitMiddlewareRouteGroups(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	c := NewTestConfig()
+	c.RateQuota = &throttled.RateQuota{
+		MaxRate:  throttled.PerHour(10),
+		MaxBurst: 9,
+	}
+	c.RouteRateQuotas = map[string]throttled.RateQuota{
+		RateLimitGroupPathFinding: {MaxRate: throttled.PerHour(2), MaxBurst: 1},
+	}
+	app := NewApp(c)
+	defer app.Close()
+	rh := NewRequestHelper(app)
+
+	for i := 0; i < 2; i++ {
+		w := rh.Get("/paths/strict-send")
+		ht.Assert.NotEqual(429, w.Code)
+		ht.Assert.Equal("2", w.Header().Get("X-RateLimit-Limit"))
+	}
+	w := rh.Get("/paths/strict-send")
+	ht.Assert.Equal(429, w.Code)
+
+	// other requests are counted separately
+	w = rh.Get("/")
+	ht.Assert.Equal(200, w.Code)
+	ht.Assert.Equal("10", w.Header().Get("X-RateLimit-Limit"))
+	ht.Assert.Equal("9", w.Header().Get("X-RateLimit-Remaining"))
+}
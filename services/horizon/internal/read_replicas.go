@@ -0,0 +1,102 @@
+package horizon
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/log"
+)
+
+// readReplicas routes the read queries of API requests to read-only replicas
+// of the horizon database. A replica is only used while it lags at most maxLag
+// ledgers behind the primary database, as checked by update. When no replica
+// is fresh enough, queries are sent to the primary database.
+//
+// Ingestion, transaction submission and reaping always use the primary
+// database.
+type readReplicas struct {
+	replicas []*readReplica
+	maxLag   int32
+	next     uint32
+}
+
+type readReplica struct {
+	index   int
+	session *db.Session
+	// fresh is 1 when the replica can serve queries. It is accessed
+	// atomically.
+	fresh int32
+}
+
+func newReadReplicas(sessions []*db.Session, maxLag int32) *readReplicas {
+	r := &readReplicas{maxLag: maxLag}
+	for i, session := range sessions {
+		r.replicas = append(r.replicas, &readReplica{index: i, session: session})
+	}
+	return r
+}
+
+// session returns the session of a fresh replica, picked in a round-robin
+// fashion, or `primary` when there is none. It is safe to call on a nil
+// *readReplicas.
+func (r *readReplicas) session(primary *db.Session) *db.Session {
+	if r == nil {
+		return primary
+	}
+
+	n := len(r.replicas)
+	for i := 0; i < n; i++ {
+		replica := r.replicas[int(atomic.AddUint32(&r.next, 1)%uint32(n))]
+		if atomic.LoadInt32(&replica.fresh) == 1 {
+			return replica.session
+		}
+	}
+	return primary
+}
+
+// update compares the latest ledger of every replica with `primaryLatest`,
+// the latest ledger of the primary database, and marks the replicas lagging
+// more than maxLag ledgers behind (or which cannot be queried) as stale.
+func (r *readReplicas) update(ctx context.Context, primaryLatest int32) {
+	if r == nil {
+		return
+	}
+
+	for _, replica := range r.replicas {
+		session := replica.session.Clone()
+		session.Ctx = ctx
+
+		var latest int32
+		fresh := int32(0)
+		err := (&history.Q{session}).LatestLedger(&latest)
+		if err != nil {
+			log.WithField("replica", replica.index).WithField("err", err).
+				Warn("failed to load the latest ledger of read replica")
+		} else if primaryLatest-latest <= r.maxLag {
+			fresh = 1
+		}
+
+		if atomic.SwapInt32(&replica.fresh, fresh) != fresh {
+			entry := log.WithField("replica", replica.index).
+				WithField("lag", primaryLatest-latest)
+			if fresh == 1 {
+				entry.Info("read replica caught up, routing queries to it")
+			} else {
+				entry.Warn("read replica is stale, routing queries away from it")
+			}
+		}
+	}
+}
+
+// close closes the connections to the replicas.
+func (r *readReplicas) close() {
+	if r == nil {
+		return
+	}
+
+	for _, replica := range r.replicas {
+		replica.session.DB.Close()
+	}
+}
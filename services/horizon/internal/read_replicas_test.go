@@ -0,0 +1,37 @@
+package horizon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/db"
+)
+
+func TestReadReplicas(t *testing.T) {
+	tt := test.Start(t).Scenario("base")
+	defer tt.Finish()
+
+	primary := tt.HorizonSession()
+	// the replicas are the test database too, which contains ledgers 1-3
+	first := &db.Session{DB: primary.DB}
+	second := &db.Session{DB: primary.DB}
+	replicas := newReadReplicas([]*db.Session{first, second}, 1)
+
+	// replicas are stale until they are checked
+	tt.Assert.Equal(primary, replicas.session(primary))
+
+	replicas.update(context.Background(), 4)
+	picked := map[*db.Session]bool{}
+	for i := 0; i < 4; i++ {
+		picked[replicas.session(primary)] = true
+	}
+	tt.Assert.Equal(map[*db.Session]bool{first: true, second: true}, picked)
+
+	// lagging more than one ledger behind the primary
+	replicas.update(context.Background(), 5)
+	tt.Assert.Equal(primary, replicas.session(primary))
+
+	var none *readReplicas
+	tt.Assert.Equal(primary, none.session(primary))
+}
@@ -7,14 +7,46 @@ package reap
 import (
 	"time"
 
+	"github.com/rcrowley/go-metrics"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/support/db"
 )
 
+const (
+	// DefaultBatchSize is the default number of ledgers deleted at once.
+	DefaultBatchSize = 100
+	// DefaultBatchDelay is the default pause between the deletion of two
+	// batches of ledgers.
+	DefaultBatchDelay = 100 * time.Millisecond
+)
+
 // System represents the history reaping subsystem of horizon.
 type System struct {
 	HistoryQ       *history.Q
 	RetentionCount uint
+	// BatchSize is the number of ledgers deleted at once. Every batch is
+	// deleted in separate statements so that the history tables are not
+	// locked for long.
+	BatchSize uint
+	// BatchDelay is the pause between the deletion of two batches, limiting
+	// the I/O load of the reaper on the database.
+	BatchDelay time.Duration
+	// LedgerState is the ledger state cache the reaper consults. The default
+	// ledger store is used when nil.
+	LedgerState *ledger.Store
+
+	Metrics struct {
+		// DeletedLedgersCounter counts the ledgers deleted by the reaper.
+		DeletedLedgersCounter metrics.Counter
+
+		// RemainingLedgersGauge is the number of unretained ledgers which
+		// are still to be deleted by the running reaper.
+		RemainingLedgersGauge metrics.Gauge
+
+		// BatchTimer exposes timing metrics about the deletion of batches.
+		BatchTimer metrics.Timer
+	}
 
 	nextRun time.Time
 }
@@ -25,7 +57,12 @@ func New(retention uint, dbSession *db.Session) *System {
 	r := &System{
 		HistoryQ:       &history.Q{dbSession},
 		RetentionCount: retention,
+		BatchSize:      DefaultBatchSize,
+		BatchDelay:     DefaultBatchDelay,
 	}
+	r.Metrics.DeletedLedgersCounter = metrics.NewCounter()
+	r.Metrics.RemainingLedgersGauge = metrics.NewGauge()
+	r.Metrics.BatchTimer = metrics.NewTimer()
 
 	r.nextRun = time.Now().Add(1 * time.Hour)
 	return r
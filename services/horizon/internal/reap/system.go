@@ -17,7 +17,7 @@ func (r *System) DeleteUnretainedHistory() error {
 	}
 
 	var (
-		latest      = ledger.CurrentState()
+		latest      = r.ledgerState().CurrentState()
 		targetElder = (latest.HistoryLatest - int32(r.RetentionCount)) + 1
 	)
 
@@ -63,18 +63,74 @@ func (r *System) runOnce() {
 	}
 }
 
+// clearBefore deletes the ledgers before seq in batches of BatchSize ledgers,
+// pausing BatchDelay between batches.
 func (r *System) clearBefore(seq int32) error {
 	log.WithField("new_elder", seq).Info("reaper: clearing")
 
-	start, end, err := toid.LedgerRangeInclusive(1, seq-1)
+	// Partitions holding only unretained ledgers are dropped at once, the
+	// remaining rows are deleted below.
+	err := r.HistoryQ.DropHistoryPartitionsBefore(uint32(seq))
 	if err != nil {
 		return err
 	}
 
-	err = r.HistoryQ.DeleteRangeAll(start, end)
+	var elder int32
+	err = r.HistoryQ.ElderLedger(&elder)
 	if err != nil {
 		return err
 	}
+	if elder < 1 {
+		elder = 1
+	}
+
+	batchSize := int32(r.BatchSize)
+	if batchSize < 1 {
+		batchSize = DefaultBatchSize
+	}
+
+	defer r.Metrics.RemainingLedgersGauge.Update(0)
+	// The first batch starts at ledger 1 rather than at the elder ledger to
+	// also delete the rows left behind by an interrupted run.
+	from := int32(1)
+	for batchStart := elder; batchStart < seq; batchStart += batchSize {
+		to := batchStart + batchSize - 1
+		if to >= seq {
+			to = seq - 1
+		}
+		r.Metrics.RemainingLedgersGauge.Update(int64(seq - batchStart))
+
+		if from > 1 && r.BatchDelay > 0 {
+			time.Sleep(r.BatchDelay)
+		}
+
+		start, end, err := toid.LedgerRangeInclusive(from, to)
+		if err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		err = r.HistoryQ.DeleteRangeAll(start, end)
+		if err != nil {
+			return err
+		}
+		r.Metrics.BatchTimer.UpdateSince(startTime)
+		r.Metrics.DeletedLedgersCounter.Inc(int64(to - batchStart + 1))
+
+		log.WithFields(log.F{
+			"from":      batchStart,
+			"to":        to,
+			"remaining": seq - to - 1,
+		}).Debug("reaper: deleted batch")
+		from = to + 1
+	}
 
 	return nil
 }
+
+func (r *System) ledgerState() *ledger.Store {
+	if r.LedgerState == nil {
+		return ledger.DefaultStore()
+	}
+	return r.LedgerState
+}
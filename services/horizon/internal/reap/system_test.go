@@ -46,3 +46,36 @@ func TestDeleteUnretainedHistory(t *testing.T) {
 		tt.Assert.Equal(1, cur)
 	}
 }
+
+func TestDeleteUnretainedHistoryInBatches(t *testing.T) {
+	tt := test.Start(t).Scenario("kahuna")
+	defer tt.Finish()
+
+	db := tt.HorizonSession()
+
+	var (
+		latest int32
+		cur    int32
+	)
+	err := db.GetRaw(&latest, `SELECT MAX(sequence) FROM history_ledgers`)
+	tt.Require.NoError(err)
+
+	sys := New(10, db)
+	sys.BatchSize = 3
+	sys.BatchDelay = 0
+
+	tt.UpdateLedgerState()
+	err = sys.DeleteUnretainedHistory()
+	if tt.Assert.NoError(err) {
+		err = db.GetRaw(&cur, `SELECT COUNT(*) FROM history_ledgers`)
+		tt.Require.NoError(err)
+		tt.Assert.Equal(int32(10), cur)
+
+		err = db.GetRaw(&cur, `SELECT MIN(sequence) FROM history_ledgers`)
+		tt.Require.NoError(err)
+		tt.Assert.Equal(latest-9, cur)
+	}
+
+	tt.Assert.Equal(int64(latest-10), sys.Metrics.DeletedLedgersCounter.Count())
+	tt.Assert.Equal(int64(0), sys.Metrics.RemainingLedgersGauge.Value())
+}
@@ -0,0 +1,196 @@
+package horizon
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/throttled"
+)
+
+// ConfigLoader reads the configuration of horizon again, usually from the same
+// sources as when it started.
+type ConfigLoader func() (Config, error)
+
+// configReloader applies the settings which can be changed without restarting
+// horizon: the log level, the rate limits, the friendbot URL and the sizes of
+// the database connection pools. Only one reload runs at a time.
+type configReloader struct {
+	sync.Mutex
+	load ConfigLoader
+}
+
+var configReloadUnavailable = problem.P{
+	Type:   "config_reload_unavailable",
+	Title:  "Configuration Reload Unavailable",
+	Status: http.StatusConflict,
+	Detail: "The configuration of this horizon instance cannot be reloaded.",
+}
+
+// SetConfigLoader sets the function loading the configuration when horizon
+// receives SIGHUP or a reload request on the admin port.
+func (a *App) SetConfigLoader(load ConfigLoader) {
+	a.configReloader.Lock()
+	defer a.configReloader.Unlock()
+	a.configReloader.load = load
+}
+
+// ReloadConfig loads the configuration and applies its reloadable settings to
+// the app and the apps of its additional networks. The other settings are
+// ignored, they only change when horizon is restarted. Nothing is applied when
+// the configuration is invalid.
+func (a *App) ReloadConfig() error {
+	a.configReloader.Lock()
+	defer a.configReloader.Unlock()
+	if a.configReloader.load == nil {
+		return errors.New("no config loader")
+	}
+
+	next, err := a.configReloader.load()
+	if err != nil {
+		return errors.Wrap(err, "could not load config")
+	}
+
+	apps := append([]*App{a}, a.networks...)
+	for _, app := range apps {
+		if err = app.validateReloadableConfig(next); err != nil {
+			return err
+		}
+	}
+
+	log.DefaultLogger.SetLevel(next.LogLevel)
+	for _, app := range apps {
+		app.applyReloadableConfig(next)
+	}
+
+	log.WithFields(log.F{
+		"log_level":                       next.LogLevel.String(),
+		"horizon_db_max_open_connections": next.HorizonDBMaxOpenConnections,
+		"horizon_db_max_idle_connections": next.HorizonDBMaxIdleConnections,
+		"core_db_max_open_connections":    next.CoreDBMaxOpenConnections,
+		"core_db_max_idle_connections":    next.CoreDBMaxIdleConnections,
+	}).Info("Configuration reloaded")
+	return nil
+}
+
+// reloadConfigOnSignal reloads the configuration every time horizon receives
+// SIGHUP, until the app is closed.
+func (a *App) reloadConfigOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-signals:
+			log.Info("received SIGHUP, reloading configuration")
+			if err := a.ReloadConfig(); err != nil {
+				log.WithField("err", err).Error("Could not reload configuration")
+			}
+		}
+	}
+}
+
+// reloadConfigHandler reloads the configuration on a request of the admin
+// port.
+func (a *App) reloadConfigHandler(w http.ResponseWriter, r *http.Request) {
+	a.configReloader.Lock()
+	load := a.configReloader.load
+	a.configReloader.Unlock()
+	if load == nil {
+		problem.Render(r.Context(), w, configReloadUnavailable)
+		return
+	}
+
+	if err := a.ReloadConfig(); err != nil {
+		problem.Render(r.Context(), w, problem.MakeInvalidFieldProblem("config", err))
+		return
+	}
+	httpjson.Render(w, a.ingestionStatus("configuration reloaded"), httpjson.JSON)
+}
+
+func (a *App) validateReloadableConfig(next Config) error {
+	if _, _, err := a.apiDBPoolSize(next.HorizonDBMaxIdleConnections, next.HorizonDBMaxOpenConnections, "horizon"); err != nil {
+		return err
+	}
+	if a.coreQ != nil {
+		if _, _, err := a.apiDBPoolSize(next.CoreDBMaxIdleConnections, next.CoreDBMaxOpenConnections, "stellar-core"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *App) applyReloadableConfig(next Config) {
+	a.web.friendbotURL.set(next.FriendbotURL)
+	a.web.setRateQuotas(next.RateQuota, next.RouteRateQuotas)
+
+	maxIdle, maxOpen, _ := a.apiDBPoolSize(next.HorizonDBMaxIdleConnections, next.HorizonDBMaxOpenConnections, "horizon")
+	a.historyQ.Session.DB.SetMaxIdleConns(maxIdle)
+	a.historyQ.Session.DB.SetMaxOpenConns(maxOpen)
+	for _, replica := range a.readReplicas.replicas {
+		replica.session.DB.SetMaxIdleConns(next.HorizonDBMaxIdleConnections)
+		replica.session.DB.SetMaxOpenConns(next.HorizonDBMaxOpenConnections)
+	}
+	if a.coreQ != nil {
+		maxIdle, maxOpen, _ = a.apiDBPoolSize(next.CoreDBMaxIdleConnections, next.CoreDBMaxOpenConnections, "stellar-core")
+		a.coreQ.Session.DB.SetMaxIdleConns(maxIdle)
+		a.coreQ.Session.DB.SetMaxOpenConns(maxOpen)
+	}
+}
+
+// reloadableURL is a URL setting which can be changed while horizon is
+// running.
+type reloadableURL struct {
+	sync.RWMutex
+	url *url.URL
+}
+
+func (u *reloadableURL) get() *url.URL {
+	u.RLock()
+	defer u.RUnlock()
+	return u.url
+}
+
+func (u *reloadableURL) set(value *url.URL) {
+	u.Lock()
+	defer u.Unlock()
+	u.url = value
+}
+
+// reloadableRateLimiter is a rate limiter whose quota can be changed while
+// horizon is running. Changing the quota forgets the requests counted so far.
+type reloadableRateLimiter struct {
+	sync.RWMutex
+	group          string
+	newRateLimiter rateLimiterFactory
+	limiter        throttled.RateLimiter
+}
+
+// RateLimit implements throttled.RateLimiter.
+func (l *reloadableRateLimiter) RateLimit(key string, quantity int) (bool, throttled.RateLimitResult, error) {
+	l.RLock()
+	limiter := l.limiter
+	l.RUnlock()
+	return limiter.RateLimit(key, quantity)
+}
+
+func (l *reloadableRateLimiter) setQuota(quota throttled.RateQuota) error {
+	limiter, err := l.newRateLimiter(l.group, quota)
+	if err != nil {
+		return err
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.limiter = limiter
+	return nil
+}
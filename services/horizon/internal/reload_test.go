@@ -0,0 +1,53 @@
+package horizon
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/throttled"
+)
+
+func TestReloadConfig(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+	defer log.DefaultLogger.SetLevel(log.DefaultLogger.Logger.Level)
+
+	w := ht.Get("/friendbot")
+	ht.Assert.Equal(http.StatusNotFound, w.Code)
+	ht.Assert.EqualError(ht.App.ReloadConfig(), "no config loader")
+
+	friendbotURL, err := url.Parse("https://friendbot.stellar.org")
+	ht.Require.NoError(err)
+	next := NewTestConfig()
+	next.LogLevel = logrus.DebugLevel
+	next.FriendbotURL = friendbotURL
+	next.RateQuota = &throttled.RateQuota{MaxRate: throttled.PerHour(1), MaxBurst: 0}
+	next.HorizonDBMaxOpenConnections = 7
+	next.HorizonDBMaxIdleConnections = 3
+	ht.App.SetConfigLoader(func() (Config, error) {
+		return next, nil
+	})
+	ht.Require.NoError(ht.App.ReloadConfig())
+
+	ht.Assert.Equal(logrus.DebugLevel, log.DefaultLogger.Logger.Level)
+	ht.Assert.Equal(7, ht.App.historyQ.Session.DB.Stats().MaxOpenConnections)
+
+	w = ht.Get("/friendbot?addr=GABC")
+	ht.Assert.Equal(http.StatusTemporaryRedirect, w.Code)
+	ht.Assert.Equal("https://friendbot.stellar.org?addr=GABC", w.Header().Get("Location"))
+
+	// the quota of the reloaded config only allows one request per hour
+	w = ht.Get("/")
+	ht.Assert.Equal(http.StatusTooManyRequests, w.Code)
+
+	// nothing is applied when the config cannot be loaded
+	ht.App.SetConfigLoader(func() (Config, error) {
+		return Config{}, errors.New("invalid")
+	})
+	ht.Assert.EqualError(ht.App.ReloadConfig(), "could not load config: invalid")
+	ht.Assert.Equal(logrus.DebugLevel, log.DefaultLogger.Logger.Level)
+}
@@ -6,6 +6,15 @@ import (
 	"github.com/stellar/go/support/render/problem"
 )
 
+// Number of seconds clients are asked to wait before retrying requests which
+// failed with one of the 503 problems below. They are included in the
+// `retry_after` extra of the problems.
+const (
+	serviceUnavailableRetryAfter = 5
+	staleHistoryRetryAfter       = 10
+	overCapacityRetryAfter       = 60
+)
+
 // Well-known and reused problems below:
 var (
 	// ServiceUnavailable is a well-known problem type.  Use it as a shortcut
@@ -15,6 +24,9 @@ var (
 		Title:  "Service Unavailable",
 		Status: http.StatusServiceUnavailable,
 		Detail: "The request cannot be serviced at this time.",
+		Extras: map[string]interface{}{
+			"retry_after": serviceUnavailableRetryAfter,
+		},
 	}
 
 	// RateLimitExceeded is a well-known problem type.  Use it as a shortcut
@@ -57,6 +69,9 @@ var (
 		Status: http.StatusServiceUnavailable,
 		Detail: "This horizon server is currently overloaded.  Please wait for " +
 			"several minutes before trying your request again.",
+		Extras: map[string]interface{}{
+			"retry_after": overCapacityRetryAfter,
+		},
 	}
 
 	// Timeout is a well-known problem type.  Use it as a shortcut
@@ -102,6 +117,9 @@ var (
 			"when it can determine that the history database is lagging too far " +
 			"behind the connected instance of stellar-core.  If you operate this " +
 			"server, please ensure that the ingestion system is properly running.",
+		Extras: map[string]interface{}{
+			"retry_after": staleHistoryRetryAfter,
+		},
 	}
 
 	// StillIngesting is a well-known problem type.  Use it as a shortcut
@@ -112,5 +130,54 @@ var (
 		Status: http.StatusServiceUnavailable,
 		Detail: "Data cannot be presented because it's still being ingested. Please " +
 			"wait for several minutes before trying your request again.",
+		Extras: map[string]interface{}{
+			"retry_after": overCapacityRetryAfter,
+		},
+	}
+
+	// HistoryNotIngested is a well-known problem type.  Use it as a shortcut
+	// in your actions.
+	HistoryNotIngested = problem.P{
+		Type:   "history_not_ingested",
+		Title:  "History Not Ingested",
+		Status: http.StatusNotFound,
+		Detail: "This horizon instance is configured to only track the current " +
+			"state of the stellar network (accounts, offers, order book and " +
+			"paths). Transactions, operations, effects and trades are not " +
+			"recorded by this horizon instance.",
 	}
 )
+
+// NewStaleHistory returns a StaleHistory problem including the latest ledgers
+// of the history database and of stellar-core.
+func NewStaleHistory(historyLatestLedger, coreLatestLedger int32) problem.P {
+	p := StaleHistory
+	p.Extras = map[string]interface{}{
+		"history_latest_ledger": historyLatestLedger,
+		"core_latest_ledger":    coreLatestLedger,
+		"retry_after":           staleHistoryRetryAfter,
+	}
+	return p
+}
+
+// NewStellarCoreUnavailable returns a problem for the transactions which
+// are not submitted because stellar-core is unavailable. `coreState` is the
+// last state reported by stellar-core, empty when it could not be reached.
+func NewStellarCoreUnavailable(coreState, reason string, retryAfter int) problem.P {
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return problem.P{
+		Type:   "stellar_core_unavailable",
+		Title:  "Stellar Core Unavailable",
+		Status: http.StatusServiceUnavailable,
+		Detail: "The transaction was not submitted because the stellar-core " +
+			"instance of this horizon server is currently unavailable: " + reason +
+			". Resubmit the same transaction after `extras.retry_after` seconds.",
+		Extras: map[string]interface{}{
+			"core_state":  coreState,
+			"reason":      reason,
+			"retry_after": retryAfter,
+		},
+	}
+}
@@ -75,3 +75,16 @@ func TestMakeInvalidFieldProblem(t *testing.T) {
 	// it doesn't add keys to source problem
 	tt.Len(problem.BadRequest.Extras, 0)
 }
+
+func TestNewStaleHistory(t *testing.T) {
+	p := NewStaleHistory(10, 15)
+	assert.Equal(t, StaleHistory.Type, p.Type)
+	assert.Equal(t, map[string]interface{}{
+		"history_latest_ledger": int32(10),
+		"core_latest_ledger":    int32(15),
+		"retry_after":           staleHistoryRetryAfter,
+	}, p.Extras)
+
+	// the well-known problem is not modified
+	assert.Equal(t, map[string]interface{}{"retry_after": staleHistoryRetryAfter}, StaleHistory.Extras)
+}
@@ -1,6 +1,7 @@
 package sse
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/stellar/go/services/horizon/internal/ledger"
@@ -14,6 +15,8 @@ type LedgerSourceFactory interface {
 
 // StreamHandler represents a stream handling action
 type StreamHandler struct {
+	// AppCtx is cancelled when the streams must be closed, on shutdown.
+	AppCtx              context.Context
 	RateLimiter         *throttled.HTTPRateLimiter
 	LedgerSourceFactory LedgerSourceFactory
 }
@@ -37,6 +40,12 @@ func (handler StreamHandler) ServeStream(
 	ledgerSource := handler.LedgerSourceFactory.Get()
 	defer ledgerSource.Close()
 
+	// A nil channel never fires when there is no app context.
+	var appDone <-chan struct{}
+	if handler.AppCtx != nil {
+		appDone = handler.AppCtx.Done()
+	}
+
 	currentLedgerSequence := ledgerSource.CurrentLedger()
 	for {
 		// Rate limit the request if it's a call to stream since it queries the DB every second. See
@@ -83,6 +92,9 @@ func (handler StreamHandler) ServeStream(
 		case <-ctx.Done():
 			stream.Done()
 			return
+		case <-appDone:
+			stream.Done()
+			return
 		}
 	}
 }
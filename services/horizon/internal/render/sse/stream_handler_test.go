@@ -42,3 +42,28 @@ func TestSendByeByeOnContextDone(t *testing.T) {
 		t.Fatalf("expected '%v' but got '%v'", expected, got)
 	}
 }
+
+func TestSendByeByeOnAppContextDone(t *testing.T) {
+	ledgerSource := ledger.NewTestingSource(1)
+	appCtx, closeStreams := context.WithCancel(context.Background())
+	handler := StreamHandler{AppCtx: appCtx, LedgerSourceFactory: &testingFactory{ledgerSource}}
+
+	r, err := http.NewRequest("GET", "http://localhost", nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	w := httptest.NewRecorder()
+
+	handler.ServeStream(w, r, 10, func() ([]Event, error) {
+		closeStreams()
+		return []Event{}, nil
+	})
+
+	expected := "retry: 1000\nevent: open\ndata: \"hello\"\n\n" +
+		"retry: 10\nevent: close\ndata: \"byebye\"\n\n"
+
+	if got := w.Body.String(); got != expected {
+		t.Fatalf("expected '%v' but got '%v'", expected, got)
+	}
+}
@@ -0,0 +1,28 @@
+package resourceadapter
+
+import (
+	"context"
+	"net/url"
+	"unicode/utf8"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/httpx"
+	"github.com/stellar/go/support/render/hal"
+)
+
+// PopulateAccountDataEntry constructs a data entry response struct from a row
+// of the accounts_data table.
+func PopulateAccountDataEntry(ctx context.Context, dest *protocol.AccountDataEntry, row history.Data) {
+	dest.Name = row.Name
+	dest.Value = row.Value.Base64()
+	if utf8.Valid(row.Value) {
+		decoded := string(row.Value)
+		dest.DecodedValue = &decoded
+	}
+	dest.LastModifiedLedger = row.LastModifiedLedger
+	dest.PT = row.Name
+
+	lb := hal.LinkBuilder{httpx.BaseURL(ctx)}
+	dest.Links.Self = lb.Linkf("/accounts/%s/data/%s", row.AccountID, url.PathEscape(row.Name))
+}
@@ -0,0 +1,147 @@
+package resourceadapter
+
+import (
+	"context"
+	"encoding/base64"
+	"math/big"
+	"net/url"
+	"strconv"
+
+	"github.com/stellar/go/amount"
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/httpx"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/xdr"
+)
+
+// ledgerEntryTypeNames maps ledger entry types to the names used in the
+// `type` field of ledger entry resources.
+var ledgerEntryTypeNames = map[xdr.LedgerEntryType]string{
+	xdr.LedgerEntryTypeAccount:   "account",
+	xdr.LedgerEntryTypeTrustline: "trustline",
+	xdr.LedgerEntryTypeOffer:     "offer",
+	xdr.LedgerEntryTypeData:      "data",
+}
+
+// PopulateLedgerEntry fills out the resource's fields from a raw ledger entry.
+func PopulateLedgerEntry(ctx context.Context, dest *protocol.LedgerEntry, entry xdr.LedgerEntry) error {
+	var ok bool
+	dest.Type, ok = ledgerEntryTypeNames[entry.Data.Type]
+	if !ok {
+		return errors.Errorf("unknown ledger entry type: %d", entry.Data.Type)
+	}
+	dest.LastModifiedLedger = uint32(entry.LastModifiedLedgerSeq)
+
+	var err error
+	dest.KeyXDR, err = xdr.MarshalBase64(entry.LedgerKey())
+	if err != nil {
+		return errors.Wrap(err, "marshaling ledger key")
+	}
+	dest.EntryXDR, err = xdr.MarshalBase64(entry)
+	if err != nil {
+		return errors.Wrap(err, "marshaling ledger entry")
+	}
+
+	switch entry.Data.Type {
+	case xdr.LedgerEntryTypeAccount:
+		dest.Account = &protocol.LedgerEntryAccount{}
+		populateLedgerEntryAccount(dest.Account, entry.Data.MustAccount())
+	case xdr.LedgerEntryTypeTrustline:
+		dest.TrustLine = &protocol.LedgerEntryTrustLine{}
+		err = populateLedgerEntryTrustLine(dest.TrustLine, entry.Data.MustTrustLine())
+	case xdr.LedgerEntryTypeOffer:
+		dest.Offer = &protocol.LedgerEntryOffer{}
+		err = populateLedgerEntryOffer(dest.Offer, entry.Data.MustOffer())
+	case xdr.LedgerEntryTypeData:
+		data := entry.Data.MustData()
+		dest.Data = &protocol.LedgerEntryData{
+			AccountID: data.AccountId.Address(),
+			Name:      string(data.DataName),
+			Value:     base64.StdEncoding.EncodeToString(data.DataValue),
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	lb := hal.LinkBuilder{httpx.BaseURL(ctx)}
+	dest.Links.Self = lb.Linkf("/ledger_entries?key=%s", url.QueryEscape(dest.KeyXDR))
+	return nil
+}
+
+func populateLedgerEntryAccount(dest *protocol.LedgerEntryAccount, account xdr.AccountEntry) {
+	dest.AccountID = account.AccountId.Address()
+	dest.Balance = amount.String(account.Balance)
+	dest.Sequence = strconv.FormatInt(int64(account.SeqNum), 10)
+	dest.SubentryCount = uint32(account.NumSubEntries)
+	if account.InflationDest != nil {
+		dest.InflationDestination = account.InflationDest.Address()
+	}
+	dest.HomeDomain = string(account.HomeDomain)
+
+	dest.MasterKeyWeight = account.MasterKeyWeight()
+	dest.Thresholds.LowThreshold = account.ThresholdLow()
+	dest.Thresholds.MedThreshold = account.ThresholdMedium()
+	dest.Thresholds.HighThreshold = account.ThresholdHigh()
+
+	flags := xdr.AccountFlags(account.Flags)
+	dest.Flags.AuthRequired = flags.IsAuthRequired()
+	dest.Flags.AuthRevocable = flags.IsAuthRevocable()
+	dest.Flags.AuthImmutable = flags.IsAuthImmutable()
+
+	dest.Signers = make([]protocol.Signer, len(account.Signers))
+	for i, signer := range account.Signers {
+		dest.Signers[i].Weight = int32(signer.Weight)
+		dest.Signers[i].Key = signer.Key.Address()
+		dest.Signers[i].Type = protocol.MustKeyTypeFromAddress(dest.Signers[i].Key)
+	}
+
+	var liabilities xdr.Liabilities
+	if v1, ok := account.Ext.GetV1(); ok {
+		liabilities = v1.Liabilities
+	}
+	dest.BuyingLiabilities = amount.String(liabilities.Buying)
+	dest.SellingLiabilities = amount.String(liabilities.Selling)
+}
+
+func populateLedgerEntryTrustLine(dest *protocol.LedgerEntryTrustLine, trustLine xdr.TrustLineEntry) error {
+	dest.AccountID = trustLine.AccountId.Address()
+	err := trustLine.Asset.Extract(&dest.Asset.Type, &dest.Asset.Code, &dest.Asset.Issuer)
+	if err != nil {
+		return errors.Wrap(err, "extracting trust line asset")
+	}
+	dest.Balance = amount.String(trustLine.Balance)
+	dest.Limit = amount.String(trustLine.Limit)
+
+	flags := xdr.TrustLineFlags(trustLine.Flags)
+	dest.IsAuthorized = flags.IsAuthorized()
+	dest.IsAuthorizedToMaintainLiabilities = flags.IsAuthorizedToMaintainLiabilitiesFlag()
+
+	var liabilities xdr.Liabilities
+	if v1, ok := trustLine.Ext.GetV1(); ok {
+		liabilities = v1.Liabilities
+	}
+	dest.BuyingLiabilities = amount.String(liabilities.Buying)
+	dest.SellingLiabilities = amount.String(liabilities.Selling)
+	return nil
+}
+
+func populateLedgerEntryOffer(dest *protocol.LedgerEntryOffer, offer xdr.OfferEntry) error {
+	dest.Seller = offer.SellerId.Address()
+	dest.OfferID = int64(offer.OfferId)
+	err := offer.Selling.Extract(&dest.Selling.Type, &dest.Selling.Code, &dest.Selling.Issuer)
+	if err != nil {
+		return errors.Wrap(err, "extracting selling asset")
+	}
+	err = offer.Buying.Extract(&dest.Buying.Type, &dest.Buying.Code, &dest.Buying.Issuer)
+	if err != nil {
+		return errors.Wrap(err, "extracting buying asset")
+	}
+	dest.Amount = amount.String(offer.Amount)
+	dest.PriceR.N = int32(offer.Price.N)
+	dest.PriceR.D = int32(offer.Price.D)
+	dest.Price = big.NewRat(int64(offer.Price.N), int64(offer.Price.D)).FloatString(7)
+	dest.Passive = offer.Flags&xdr.Uint32(xdr.OfferEntryFlagsPassiveFlag) != 0
+	return nil
+}
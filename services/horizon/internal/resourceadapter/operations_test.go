@@ -130,6 +130,46 @@ func TestPopulateOperation_AllowTrust(t *testing.T) {
 	tt.Equal(false, rsp["authorize_to_maintain_liabilities"])
 }
 
+func TestPopulateOperation_MuxedPayment(t *testing.T) {
+	tt := assert.New(t)
+	ctx, _ := test.ContextWithLogBuffer()
+
+	details := `{
+		"amount":                  "10.0000000",
+		"asset_type":              "native",
+		"from":                    "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2",
+		"from_muxed":              "MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK",
+		"from_muxed_id":           "16045690984833335023",
+		"source_account_muxed":    "MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK",
+		"source_account_muxed_id": "16045690984833335023",
+		"to":                      "GDRW375MAYR46ODGF2WGANQC2RRZL7O246DYHHCGWTV2RE7IHE2QUQLD"
+	}`
+	operationsRow := history.Operation{
+		TransactionSuccessful: true,
+		Type:                  xdr.OperationTypePayment,
+		DetailsString:         null.StringFrom(details),
+		SourceAccount:         "GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2",
+	}
+	resource, err := NewOperation(ctx, operationsRow, "", nil, history.Ledger{})
+	tt.NoError(err)
+
+	payment := resource.(operations.Payment)
+	tt.Equal("GA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNKKH2", payment.SourceAccount)
+	tt.Equal("MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK", payment.SourceAccountMuxed)
+	tt.Equal(uint64(16045690984833335023), payment.SourceAccountMuxedID)
+	tt.Equal("MA5WBPYA5Y4WAEHXWR2UKO2UO4BUGHUQ74EUPKON2QHV4WRHOIRNLXVNX3X55LN654YYK", payment.FromMuxed)
+	tt.Equal(uint64(16045690984833335023), payment.FromMuxedID)
+	tt.Empty(payment.ToMuxed)
+	tt.Zero(payment.ToMuxedID)
+
+	data, err := json.Marshal(resource)
+	tt.NoError(err)
+	var rsp map[string]interface{}
+	tt.NoError(json.Unmarshal(data, &rsp))
+	tt.Equal("16045690984833335023", rsp["from_muxed_id"])
+	tt.NotContains(rsp, "to_muxed")
+}
+
 func getJSONResponse(details string) (rsp map[string]interface{}, err error) {
 	ctx, _ := test.ContextWithLogBuffer()
 	transactionRow := history.Transaction{
@@ -10,11 +10,12 @@ import (
 	"github.com/stellar/go/support/render/hal"
 )
 
-// Populate fills in the details
+// PopulateRoot fills in the details
 func PopulateRoot(
 	ctx context.Context,
 	dest *horizon.Root,
 	ledgerState ledger.State,
+	historyRetentionCount, staleThreshold uint,
 	hVersion, cVersion string,
 	passphrase string,
 	currentProtocolVersion int32,
@@ -31,6 +32,7 @@ func PopulateRoot(
 	dest.NetworkPassphrase = passphrase
 	dest.CurrentProtocolVersion = currentProtocolVersion
 	dest.CoreSupportedProtocolVersion = coreSupportedProtocolVersion
+	populateRootIngestion(&dest.Ingestion, ledgerState, historyRetentionCount, staleThreshold)
 
 	lb := hal.LinkBuilder{Base: httpx.BaseURL(ctx)}
 	if friendBotURL != nil {
@@ -68,3 +70,24 @@ func PopulateRoot(
 	dest.Links.Transaction = lb.Link("/transactions/{hash}")
 	dest.Links.Transactions = lb.PagedLink("/transactions")
 }
+
+func populateRootIngestion(
+	dest *horizon.RootIngestion,
+	ledgerState ledger.State,
+	historyRetentionCount, staleThreshold uint,
+) {
+	dest.CoreLatestLedger = ledgerState.CoreLatest
+	dest.HistoryLatestLedger = ledgerState.HistoryLatest
+	dest.ExpIngestLatestLedger = ledgerState.ExpHistoryLatest
+	dest.HistoryElderLedger = ledgerState.HistoryElder
+	dest.HistoryRetentionCount = historyRetentionCount
+	dest.StaleThreshold = staleThreshold
+
+	// core reporting ledger 0 means it is not synced yet, in which case
+	// horizon can't tell whether it is caught up
+	historyLag := int64(ledgerState.CoreLatest) - int64(ledgerState.HistoryLatest)
+	expIngestLag := int64(ledgerState.CoreLatest) - int64(ledgerState.ExpHistoryLatest)
+	dest.CaughtUp = ledgerState.CoreLatest > 0 &&
+		historyLag <= int64(staleThreshold) &&
+		expIngestLag <= int64(staleThreshold)
+}
@@ -23,6 +23,8 @@ func TestPopulateRoot(t *testing.T) {
 	PopulateRoot(context.Background(),
 		res,
 		ledger.State{CoreLatest: 1, HistoryLatest: 3, HistoryElder: 2},
+		0,
+		0,
 		"hVersion",
 		"cVersion",
 		"passphrase",
@@ -45,6 +47,8 @@ func TestPopulateRoot(t *testing.T) {
 	PopulateRoot(context.Background(),
 		res,
 		ledger.State{CoreLatest: 1, HistoryLatest: 3, HistoryElder: 2},
+		0,
+		0,
 		"hVersion",
 		"cVersion",
 		"passphrase",
@@ -66,6 +70,8 @@ func TestPopulateRoot(t *testing.T) {
 	PopulateRoot(context.Background(),
 		res,
 		ledger.State{CoreLatest: 1, HistoryLatest: 3, HistoryElder: 2},
+		0,
+		0,
 		"hVersion",
 		"cVersion",
 		"passphrase",
@@ -94,6 +100,33 @@ func TestPopulateRoot(t *testing.T) {
 	)
 }
 
+func TestPopulateRootIngestion(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		state    ledger.State
+		caughtUp bool
+	}{
+		{"caught up", ledger.State{CoreLatest: 12, HistoryLatest: 10, ExpHistoryLatest: 11}, true},
+		{"history behind", ledger.State{CoreLatest: 20, HistoryLatest: 10, ExpHistoryLatest: 19}, false},
+		{"experimental ingestion behind", ledger.State{CoreLatest: 20, HistoryLatest: 19, ExpHistoryLatest: 10}, false},
+		{"core not synced", ledger.State{}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var res horizon.RootIngestion
+			tc.state.HistoryElder = 5
+			populateRootIngestion(&res, tc.state, 100, 5)
+
+			assert.Equal(t, tc.state.CoreLatest, res.CoreLatestLedger)
+			assert.Equal(t, tc.state.HistoryLatest, res.HistoryLatestLedger)
+			assert.Equal(t, tc.state.ExpHistoryLatest, res.ExpIngestLatestLedger)
+			assert.Equal(t, int32(5), res.HistoryElderLedger)
+			assert.Equal(t, uint(100), res.HistoryRetentionCount)
+			assert.Equal(t, uint(5), res.StaleThreshold)
+			assert.Equal(t, tc.caughtUp, res.CaughtUp)
+		})
+	}
+}
+
 func urlMustParse(t *testing.T, s string) *url.URL {
 	if u, err := url.Parse(s); err != nil {
 		t.Fatalf("Unable to parse URL: %s/%v", s, err)
@@ -57,6 +57,8 @@ func PopulateTransaction(
 		dest.MaxFee = row.NewMaxFee.Int64
 		dest.FeeBumpTransaction = &protocol.FeeBumpTransaction{
 			Hash:       row.TransactionHash,
+			FeeAccount: row.FeeAccount.String,
+			MaxFee:     row.NewMaxFee.Int64,
 			Signatures: dest.Signatures,
 		}
 		dest.InnerTransaction = &protocol.InnerTransaction{
@@ -194,6 +194,8 @@ func TestFeeBumpTransaction(t *testing.T) {
 	assert.Equal(t, row.MaxFee, dest.InnerTransaction.MaxFee)
 	assert.Equal(t, []string{"d", "e", "f"}, dest.InnerTransaction.Signatures)
 	assert.Equal(t, row.TransactionHash, dest.FeeBumpTransaction.Hash)
+	assert.Equal(t, row.FeeAccount.String, dest.FeeBumpTransaction.FeeAccount)
+	assert.Equal(t, row.NewMaxFee.Int64, dest.FeeBumpTransaction.MaxFee)
 	assert.Equal(t, []string{"a", "b", "c"}, dest.FeeBumpTransaction.Signatures)
 	assert.Equal(t, "/transactions/"+row.InnerTransactionHash.String, dest.Links.Transaction.Href)
 }
@@ -72,7 +72,11 @@ func IngestTestTrade(
 		BuyerAccountID:     accounts[buyer.Address()],
 		SellerAccountID:    accounts[seller.Address()],
 	})
-	return batch.Exec()
+	if err = batch.Exec(); err != nil {
+		return err
+	}
+
+	return q.RebuildTradeRollups(timestamp.ToTime(), timestamp.ToTime())
 }
 
 //PopulateTestTrades generates and ingests trades between two assets according to given parameters
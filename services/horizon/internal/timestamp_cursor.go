@@ -0,0 +1,72 @@
+package horizon
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/actions"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/toid"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+	strtime "github.com/stellar/go/support/time"
+)
+
+// timestampCursorPrefix marks cursors which point at a wall-clock time instead
+// of a paging token, e.g. `closed_at:2020-03-01T10:00:00Z`.
+const timestampCursorPrefix = "closed_at:"
+
+// timestampCursorMiddleware resolves timestamp cursors on history endpoints to
+// the paging token of the first ledger closed at or after the given time, the
+// same way the `now` cursor is resolved to the latest ledger. Ascending pages
+// start with the records of that ledger, descending pages with the records
+// closed before it.
+func (w *web) timestampCursorMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		cursor := query.Get(actions.ParamCursor)
+		if !strings.HasPrefix(cursor, timestampCursorPrefix) {
+			h.ServeHTTP(rw, r)
+			return
+		}
+
+		closedAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(cursor, timestampCursorPrefix))
+		if err != nil {
+			problem.Render(r.Context(), rw, problem.MakeInvalidFieldProblem(
+				actions.ParamCursor,
+				errors.New("the time of a closed_at cursor must be a RFC3339 timestamp"),
+			))
+			return
+		}
+
+		session := w.readReplicas.session(w.historyQ.Session).Clone()
+		session.Ctx = r.Context()
+		sequence, err := timestampCursorLedger(&history.Q{session}, closedAt, ledger.FromContext(r.Context()).CurrentState().HistoryLatest)
+		if err != nil {
+			problem.Render(r.Context(), rw, err)
+			return
+		}
+
+		query.Set(actions.ParamCursor, toid.AfterLedger(sequence-1).String())
+		r.URL.RawQuery = query.Encode()
+		h.ServeHTTP(rw, r)
+	})
+}
+
+// timestampCursorLedger returns the first ledger closed at or after `closedAt`.
+// When no such ledger has been ingested yet, the ledger following
+// `historyLatest` is returned.
+func timestampCursorLedger(q *history.Q, closedAt time.Time, historyLatest int32) (int32, error) {
+	first, _, found, err := q.LedgerSequencesClosedWithin(history.TimeRange{
+		Start: strtime.MillisFromInt64(closedAt.UnixNano() / int64(time.Millisecond)),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "could not resolve closed_at cursor")
+	}
+	if !found {
+		return historyLatest + 1, nil
+	}
+	return first, nil
+}
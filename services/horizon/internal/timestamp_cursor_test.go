@@ -0,0 +1,35 @@
+package horizon
+
+import (
+	"testing"
+)
+
+func TestTimestampCursorMiddleware(t *testing.T) {
+	ht := StartHTTPTest(t, "base")
+	defer ht.Finish()
+
+	// ledger 2 closed at 2019-10-31T13:19:45Z, ledger 3 closed after it
+	w := ht.Get("/ledgers?cursor=closed_at:2019-10-31T13:19:45Z")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(2, w.Body)
+	}
+
+	w = ht.Get("/ledgers?cursor=closed_at:2019-10-31T13:19:45Z&order=desc")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(1, w.Body)
+	}
+
+	// ledger 1 has no transactions
+	w = ht.Get("/transactions?cursor=closed_at:2019-10-31T13:19:45Z")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(4, w.Body)
+	}
+
+	w = ht.Get("/transactions?cursor=closed_at:2100-01-01T00:00:00Z")
+	if ht.Assert.Equal(200, w.Code) {
+		ht.Assert.PageOf(0, w.Body)
+	}
+
+	w = ht.Get("/ledgers?cursor=closed_at:yesterday")
+	ht.Assert.Equal(400, w.Code)
+}
@@ -0,0 +1,170 @@
+package txsub
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/log"
+)
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failed
+	// submissions to stellar-core which open the circuit breaker.
+	DefaultFailureThreshold = 5
+	// DefaultOpenDuration is the default time the circuit breaker rejects
+	// submissions before letting a submission probe stellar-core.
+	DefaultOpenDuration = 5 * time.Second
+)
+
+// CoreUnavailableError is returned instead of submitting a transaction while
+// the circuit breaker is open.
+type CoreUnavailableError struct {
+	// CoreState is the last state reported by stellar-core, empty when it
+	// could not be reached.
+	CoreState string
+	Reason    string
+	// RetryAfter is the time after which the submission can be retried.
+	RetryAfter time.Duration
+}
+
+func (err *CoreUnavailableError) Error() string {
+	return fmt.Sprintf("stellar-core unavailable: %s", err.Reason)
+}
+
+// CircuitBreaker stops the submission of transactions to stellar-core while
+// it is not synced or while submissions fail, so that clients get an
+// immediate error instead of waiting for a submission which would time out.
+//
+// The breaker opens when stellar-core reports that it is not synced (see
+// UpdateCoreStatus) or after FailureThreshold consecutive failed submissions.
+// In the latter case a single submission probes stellar-core every
+// OpenDuration and the breaker closes once a probe succeeds. A submission
+// rejected by stellar-core (a FailedTransactionError) is not a failure.
+type CircuitBreaker struct {
+	FailureThreshold int
+	OpenDuration     time.Duration
+	Log              *log.Entry
+
+	mutex      sync.Mutex
+	coreSynced bool
+	coreState  string
+	failures   int
+	open       bool
+	openedAt   time.Time
+	probing    bool
+	probedAt   time.Time
+
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a closed circuit breaker with the default
+// thresholds.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: DefaultFailureThreshold,
+		OpenDuration:     DefaultOpenDuration,
+		Log:              log.DefaultLogger.WithField("service", "txsub.CircuitBreaker"),
+		coreSynced:       true,
+		now:              time.Now,
+	}
+}
+
+// Allow returns a CoreUnavailableError when a transaction must not be
+// submitted to stellar-core. Otherwise the result of the submission must be
+// passed to Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.coreSynced {
+		reason := "stellar-core is not synced"
+		if b.coreState == "" {
+			reason = "stellar-core cannot be reached"
+		}
+		return &CoreUnavailableError{
+			CoreState:  b.coreState,
+			Reason:     reason,
+			RetryAfter: b.OpenDuration,
+		}
+	}
+
+	if !b.open {
+		return nil
+	}
+
+	now := b.now()
+	retryAt := b.openedAt.Add(b.OpenDuration)
+	// a probe whose result was never recorded (e.g. because the request was
+	// cancelled) does not prevent new probes
+	if b.probing && now.Sub(b.probedAt) >= b.OpenDuration {
+		b.probing = false
+	}
+	if now.Before(retryAt) || b.probing {
+		retryAfter := retryAt.Sub(now)
+		if retryAfter <= 0 {
+			retryAfter = b.OpenDuration
+		}
+		return &CoreUnavailableError{
+			CoreState:  b.coreState,
+			Reason:     "submissions to stellar-core are failing",
+			RetryAfter: retryAfter,
+		}
+	}
+
+	b.probing = true
+	b.probedAt = now
+	return nil
+}
+
+// Record records the result of a submission allowed by Allow.
+func (b *CircuitBreaker) Record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, rejected := err.(*FailedTransactionError); err == nil || rejected {
+		if b.open {
+			b.Log.Info("Submissions to stellar-core succeed again, closing the circuit breaker")
+		}
+		b.failures = 0
+		b.open = false
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.probing || (!b.open && b.failures >= b.FailureThreshold) {
+		if !b.open {
+			b.Log.WithField("err", err).WithField("failures", b.failures).
+				Warn("Submissions to stellar-core are failing, opening the circuit breaker")
+		}
+		b.open = true
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// UpdateCoreStatus updates the state reported by stellar-core. `state` is
+// empty when stellar-core could not be reached. Submissions are rejected
+// while stellar-core is not synced.
+func (b *CircuitBreaker) UpdateCoreStatus(state string, synced bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if synced != b.coreSynced {
+		if synced {
+			b.Log.WithField("core_state", state).Info("stellar-core is synced, accepting submissions")
+		} else {
+			b.Log.WithField("core_state", state).Warn("stellar-core is not synced, rejecting submissions")
+		}
+	}
+	b.coreSynced = synced
+	b.coreState = state
+}
+
+// IsOpen returns true while submissions are rejected.
+func (b *CircuitBreaker) IsOpen() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.open || !b.coreSynced
+}
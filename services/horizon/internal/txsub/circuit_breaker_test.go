@@ -0,0 +1,71 @@
+package txsub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerCoreStatus(t *testing.T) {
+	breaker := NewCircuitBreaker()
+	assert.NoError(t, breaker.Allow())
+
+	breaker.UpdateCoreStatus("Catching up", false)
+	assert.True(t, breaker.IsOpen())
+	assert.Equal(t, &CoreUnavailableError{
+		CoreState:  "Catching up",
+		Reason:     "stellar-core is not synced",
+		RetryAfter: DefaultOpenDuration,
+	}, breaker.Allow())
+
+	breaker.UpdateCoreStatus("", false)
+	assert.EqualError(t, breaker.Allow(), "stellar-core unavailable: stellar-core cannot be reached")
+
+	breaker.UpdateCoreStatus("Synced!", true)
+	assert.False(t, breaker.IsOpen())
+	assert.NoError(t, breaker.Allow())
+}
+
+func TestCircuitBreakerFailures(t *testing.T) {
+	now := time.Date(2020, 6, 10, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker()
+	breaker.FailureThreshold = 2
+	breaker.now = func() time.Time { return now }
+	failure := errors.New("failed to submit")
+
+	// transactions rejected by stellar-core are not failures
+	breaker.Record(failure)
+	breaker.Record(&FailedTransactionError{"AAAAAAAAAAD////7AAAAAA=="})
+	breaker.Record(failure)
+	assert.NoError(t, breaker.Allow())
+
+	breaker.Record(failure)
+	assert.True(t, breaker.IsOpen())
+	err := breaker.Allow()
+	assert.Equal(t, &CoreUnavailableError{
+		Reason:     "submissions to stellar-core are failing",
+		RetryAfter: DefaultOpenDuration,
+	}, err)
+
+	// a single submission probes stellar-core once OpenDuration elapsed
+	now = now.Add(DefaultOpenDuration)
+	assert.NoError(t, breaker.Allow())
+	assert.Error(t, breaker.Allow())
+
+	// a failed probe opens the breaker again
+	breaker.Record(failure)
+	assert.Error(t, breaker.Allow())
+
+	// an unrecorded probe does not block the following ones
+	now = now.Add(DefaultOpenDuration)
+	assert.NoError(t, breaker.Allow())
+	now = now.Add(DefaultOpenDuration)
+	assert.NoError(t, breaker.Allow())
+
+	breaker.Record(nil)
+	assert.False(t, breaker.IsOpen())
+	assert.NoError(t, breaker.Allow())
+	assert.NoError(t, breaker.Allow())
+}
@@ -0,0 +1,75 @@
+package results
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/txsub"
+)
+
+// DefaultRecentLedgers is the default number of ledgers whose transactions
+// are kept by Recent, about ten minutes of ledgers.
+const DefaultRecentLedgers = 120
+
+// Recent provides transaction submission results from the transactions of the
+// latest ingested ledgers, kept in memory. It is used instead of DB when the
+// history is not ingested, in which case only the instance running the
+// ingestion knows the results.
+type Recent struct {
+	// MaxLedgers is the number of ledgers whose transactions are kept.
+	MaxLedgers int
+
+	mutex   sync.RWMutex
+	ledgers [][]string
+	byHash  map[string]history.Transaction
+}
+
+var _ txsub.ResultProvider = &Recent{}
+
+// NewRecent returns a Recent keeping the transactions of the latest
+// `maxLedgers` ledgers.
+func NewRecent(maxLedgers int) *Recent {
+	return &Recent{
+		MaxLedgers: maxLedgers,
+		byHash:     map[string]history.Transaction{},
+	}
+}
+
+// AddLedger adds the transactions of ledger `sequence`, evicting the
+// transactions of the oldest ledger when MaxLedgers ledgers are kept.
+func (rp *Recent) AddLedger(sequence uint32, transactions []history.Transaction) {
+	rp.mutex.Lock()
+	defer rp.mutex.Unlock()
+
+	if len(rp.ledgers) >= rp.MaxLedgers && len(rp.ledgers) > 0 {
+		for _, hash := range rp.ledgers[0] {
+			delete(rp.byHash, hash)
+		}
+		rp.ledgers = rp.ledgers[1:]
+	}
+
+	hashes := make([]string, 0, len(transactions))
+	for _, tx := range transactions {
+		hashes = append(hashes, tx.TransactionHash)
+		rp.byHash[tx.TransactionHash] = tx
+		// like DB, fee bump transactions are also found by inner hash
+		if tx.InnerTransactionHash.Valid {
+			hashes = append(hashes, tx.InnerTransactionHash.String)
+			rp.byHash[tx.InnerTransactionHash.String] = tx
+		}
+	}
+	rp.ledgers = append(rp.ledgers, hashes)
+}
+
+// ResultByHash implements txsub.ResultProvider
+func (rp *Recent) ResultByHash(ctx context.Context, hash string) txsub.Result {
+	rp.mutex.RLock()
+	tx, ok := rp.byHash[hash]
+	rp.mutex.RUnlock()
+
+	if !ok {
+		return txsub.Result{Err: txsub.ErrNoResults}
+	}
+	return txResultFromHistory(tx)
+}
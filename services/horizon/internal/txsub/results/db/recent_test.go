@@ -0,0 +1,63 @@
+package results
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/txsub"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recentTransaction(t *testing.T, hash string, code xdr.TransactionResultCode) history.Transaction {
+	result, err := xdr.MarshalBase64(xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{
+			Code:    code,
+			Results: &[]xdr.OperationResult{},
+		},
+	})
+	require.NoError(t, err)
+
+	tx := history.Transaction{}
+	tx.TransactionHash = hash
+	tx.TxResult = result
+	return tx
+}
+
+func TestRecentResultProvider(t *testing.T) {
+	ctx := context.Background()
+	rp := NewRecent(2)
+
+	feeBump := recentTransaction(t, "feebump", xdr.TransactionResultCodeTxSuccess)
+	feeBump.InnerTransactionHash = null.StringFrom("inner")
+	rp.AddLedger(1, []history.Transaction{
+		recentTransaction(t, "first", xdr.TransactionResultCodeTxSuccess),
+		feeBump,
+	})
+	rp.AddLedger(2, []history.Transaction{
+		recentTransaction(t, "failed", xdr.TransactionResultCodeTxFailed),
+	})
+
+	ret := rp.ResultByHash(ctx, "first")
+	require.NoError(t, ret.Err)
+	assert.Equal(t, "first", ret.Transaction.TransactionHash)
+
+	ret = rp.ResultByHash(ctx, "inner")
+	require.NoError(t, ret.Err)
+	assert.Equal(t, "feebump", ret.Transaction.TransactionHash)
+
+	ret = rp.ResultByHash(ctx, "failed")
+	assert.IsType(t, &txsub.FailedTransactionError{}, ret.Err)
+
+	ret = rp.ResultByHash(ctx, "unknown")
+	assert.Equal(t, txsub.ErrNoResults, ret.Err)
+
+	// the transactions of the oldest ledger are evicted
+	rp.AddLedger(3, nil)
+	assert.Equal(t, txsub.ErrNoResults, rp.ResultByHash(ctx, "first").Err)
+	assert.Equal(t, txsub.ErrNoResults, rp.ResultByHash(ctx, "inner").Err)
+	require.NoError(t, rp.ResultByHash(ctx, "failed").Err)
+}
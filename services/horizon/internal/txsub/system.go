@@ -27,7 +27,10 @@ type System struct {
 	Submitter         Submitter
 	SubmissionQueue   *sequence.Manager
 	SubmissionTimeout time.Duration
-	Log               *log.Entry
+	// CircuitBreaker rejects submissions while stellar-core is unavailable.
+	// Every submission is sent to stellar-core when nil.
+	CircuitBreaker *CircuitBreaker
+	Log            *log.Entry
 
 	Metrics struct {
 		// SubmissionTimer exposes timing metrics about the rate and latency of
@@ -61,6 +64,15 @@ type System struct {
 		// FeeBumpTransactionsMeter tracks the rate of fee bump transaction envelopes that
 		// have been submitted to this process
 		FeeBumpTransactionsMeter metrics.Meter
+
+		// RejectedSubmissionsMeter tracks the rate of submissions rejected
+		// without being sent to stellar-core because the circuit breaker is
+		// open
+		RejectedSubmissionsMeter metrics.Meter
+
+		// CircuitBreakerOpenGauge is 1 while the circuit breaker is open and
+		// 0 otherwise
+		CircuitBreakerOpenGauge metrics.Gauge
 	}
 }
 
@@ -98,6 +110,15 @@ func (sys *System) Submit(
 	}
 
 	// From now: r.Err == ErrNoResults
+	// fail fast rather than queueing submissions which would time out
+	if sys.CircuitBreaker != nil {
+		if err := sys.CircuitBreaker.Allow(); err != nil {
+			sys.Metrics.RejectedSubmissionsMeter.Mark(1)
+			sys.finish(ctx, hash, response, Result{Err: err})
+			return
+		}
+	}
+
 	sourceAccount := envelope.SourceAccount()
 	// The database doesn't (yet) store muxed accounts, so we query
 	// the corresponding AccountId
@@ -138,6 +159,11 @@ func (sys *System) Submit(
 
 		sr := sys.submitOnce(ctx, rawTx)
 		sys.updateTransactionTypeMetrics(envelope)
+		// submissions interrupted by the client say nothing about
+		// stellar-core
+		if sys.CircuitBreaker != nil && ctx.Err() == nil {
+			sys.CircuitBreaker.Record(sr.Err)
+		}
 
 		// if submission succeeded
 		if sr.Err == nil {
@@ -288,6 +314,11 @@ func (sys *System) Tick(ctx context.Context) {
 
 	sys.Metrics.OpenSubmissionsGauge.Update(int64(stillOpen))
 	sys.Metrics.BufferedSubmissionsGauge.Update(int64(sys.SubmissionQueue.Size()))
+	if sys.CircuitBreaker != nil && sys.CircuitBreaker.IsOpen() {
+		sys.Metrics.CircuitBreakerOpenGauge.Update(1)
+	} else {
+		sys.Metrics.CircuitBreakerOpenGauge.Update(0)
+	}
 }
 
 // Init initializes `sys`
@@ -303,6 +334,8 @@ func (sys *System) Init() {
 		sys.Metrics.V0TransactionsMeter = metrics.NewMeter()
 		sys.Metrics.V1TransactionsMeter = metrics.NewMeter()
 		sys.Metrics.FeeBumpTransactionsMeter = metrics.NewMeter()
+		sys.Metrics.RejectedSubmissionsMeter = metrics.NewMeter()
+		sys.Metrics.CircuitBreakerOpenGauge = metrics.NewGauge()
 
 		if sys.SubmissionTimeout == 0 {
 			// HTTP clients in SDKs usually timeout in 60 seconds. We want SubmissionTimeout
@@ -123,6 +123,23 @@ func (suite *SystemTestSuite) TestSubmit_Basic() {
 	assert.False(suite.T(), suite.submitter.WasSubmittedTo)
 }
 
+// Returns a CoreUnavailableError without submitting while the circuit breaker is open.
+func (suite *SystemTestSuite) TestSubmit_CircuitBreakerOpen() {
+	suite.system.CircuitBreaker = NewCircuitBreaker()
+	suite.system.CircuitBreaker.UpdateCoreStatus("Catching up", false)
+
+	r := <-suite.system.Submit(
+		suite.ctx,
+		suite.successTx.Transaction.TxEnvelope,
+		suite.successXDR,
+		suite.successTx.Transaction.TransactionHash,
+	)
+
+	assert.IsType(suite.T(), &CoreUnavailableError{}, r.Err)
+	assert.False(suite.T(), suite.submitter.WasSubmittedTo)
+	assert.Equal(suite.T(), int64(1), suite.system.Metrics.RejectedSubmissionsMeter.Count())
+}
+
 // Returns the error from submission if no result is found by hash and the suite.submitter returns an error.
 func (suite *SystemTestSuite) TestSubmit_NotFoundError() {
 	suite.submitter.R.Err = errors.New("busted for some reason")
@@ -6,11 +6,13 @@ import (
 
 	"github.com/stellar/go/services/horizon/internal/actions"
 	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/hchi"
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/toid"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/problem"
+	strtime "github.com/stellar/go/support/time"
 )
 
 // getCursor gets the param cursor from either the request URL or the request
@@ -22,7 +24,7 @@ func getCursor(r *http.Request) (string, error) {
 	}
 
 	if cursor == "now" {
-		cursor = toid.AfterLedger(ledger.CurrentState().HistoryLatest).String()
+		cursor = toid.AfterLedger(ledger.FromContext(r.Context()).CurrentState().HistoryLatest).String()
 	}
 
 	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
@@ -155,3 +157,41 @@ func getBoolParamFromURL(r *http.Request, key string) (bool, error) {
 
 	return false, problem.MakeInvalidFieldProblem(key, errors.New("invalid bool value"))
 }
+
+// getTimeMillisParamFromURL gets the time param, in milliseconds since epoch,
+// with the provided key.
+func getTimeMillisParamFromURL(r *http.Request, key string) (strtime.Millis, error) {
+	val, err := hchi.GetStringFromURL(r, key)
+	if err != nil {
+		return 0, errors.Wrapf(err, "loading %s from URL", key)
+	}
+	if val == "" {
+		return 0, nil
+	}
+
+	millis, err := strtime.MillisFromString(val)
+	if err != nil || millis < 0 {
+		return 0, problem.MakeInvalidFieldProblem(key, errors.New("invalid time value, expected milliseconds since epoch"))
+	}
+
+	return millis, nil
+}
+
+// getTimeRange gets the time range of ledger close times from the start_time
+// and end_time params.
+func getTimeRange(r *http.Request) (history.TimeRange, error) {
+	start, err := getTimeMillisParamFromURL(r, "start_time")
+	if err != nil {
+		return history.TimeRange{}, err
+	}
+	end, err := getTimeMillisParamFromURL(r, "end_time")
+	if err != nil {
+		return history.TimeRange{}, err
+	}
+
+	if !start.IsNil() && !end.IsNil() && end <= start {
+		return history.TimeRange{}, problem.MakeInvalidFieldProblem("end_time", errors.New("end_time must be after start_time"))
+	}
+
+	return history.TimeRange{Start: start, End: end}, nil
+}
@@ -0,0 +1,115 @@
+// Package usage contains the request accounting subsystem of horizon. It
+// aggregates the requests served to every tenant, an API key or a remote ip
+// address, in memory: the number of requests, of bytes sent and of requests
+// rejected by the rate limiter, and the requests of every endpoint.
+//
+// The aggregates are added to the hourly usage persisted in the horizon
+// database every FlushInterval, so the usage of all the instances sharing the
+// database can be queried together to identify abusive consumers and to plan
+// capacity.
+package usage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/db"
+)
+
+const (
+	// Period is the duration of the periods the usage is aggregated in.
+	Period = time.Hour
+	// DefaultFlushInterval is the default time between two flushes of the
+	// usage aggregated in memory to the database.
+	DefaultFlushInterval = time.Minute
+	// DefaultMaxTenants is the default number of tenants aggregated in
+	// memory between two flushes.
+	DefaultMaxTenants = 10000
+	// OtherTenant is the tenant the requests are accounted to once MaxTenants
+	// is reached, so that the memory used does not depend on the number of
+	// remote ip addresses.
+	OtherTenant = "other"
+
+	// maxTenantLength is the maximum length of the tenants persisted.
+	maxTenantLength = 256
+	// flushBatchSize is the number of tenants inserted per statement.
+	flushBatchSize = 1000
+)
+
+// System represents the request accounting subsystem of horizon.
+type System struct {
+	HistoryQ      *history.Q
+	FlushInterval time.Duration
+	MaxTenants    int
+	// Retention is how long the usage is kept in the database.
+	Retention time.Duration
+
+	Metrics struct {
+		// FlushTimer exposes timing metrics about the flushes of the usage.
+		FlushTimer metrics.Timer
+
+		// TenantsGauge is the number of tenants aggregated in memory.
+		TenantsGauge metrics.Gauge
+	}
+
+	mutex   sync.Mutex
+	periods map[time.Time]map[string]*history.TenantUsage
+	tenants int
+	now     func() time.Time
+}
+
+// New initializes the request accounting subsystem persisting the usage in
+// the database of `dbSession` for `retention`.
+func New(dbSession *db.Session, retention time.Duration) *System {
+	s := &System{
+		HistoryQ:      &history.Q{dbSession},
+		FlushInterval: DefaultFlushInterval,
+		MaxTenants:    DefaultMaxTenants,
+		Retention:     retention,
+		periods:       map[time.Time]map[string]*history.TenantUsage{},
+		now:           time.Now,
+	}
+	s.Metrics.FlushTimer = metrics.NewTimer()
+	s.Metrics.TenantsGauge = metrics.NewGauge()
+	return s
+}
+
+// Record accounts a request to `endpoint` which sent `bytes` to `tenant`.
+// It is safe for concurrent use.
+func (s *System) Record(tenant, endpoint string, bytes int64, rateLimited bool) {
+	if len(tenant) > maxTenantLength {
+		tenant = tenant[:maxTenantLength]
+	}
+	periodStart := s.now().UTC().Truncate(Period)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	usages, ok := s.periods[periodStart]
+	if !ok {
+		usages = map[string]*history.TenantUsage{}
+		s.periods[periodStart] = usages
+	}
+	usage, ok := usages[tenant]
+	if !ok {
+		if s.tenants >= s.MaxTenants {
+			tenant = OtherTenant
+			usage = usages[tenant]
+		}
+		if usage == nil {
+			usage = &history.TenantUsage{Tenant: tenant, Endpoints: map[string]int64{}}
+			usages[tenant] = usage
+			s.tenants++
+		}
+	}
+
+	usage.Requests++
+	usage.Bytes += bytes
+	if rateLimited {
+		usage.RateLimited++
+	}
+	usage.Endpoints[endpoint]++
+	s.Metrics.TenantsGauge.Update(int64(s.tenants))
+}
@@ -0,0 +1,67 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// Run flushes the usage aggregated in memory to the database every
+// FlushInterval until `ctx` is cancelled, and one last time before returning.
+func (s *System) Run(ctx context.Context) {
+	log.WithField("retention", s.Retention).Info("Starting request accounting")
+	ticker := time.NewTicker(s.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.flush(); err != nil {
+				log.WithField("err", err).Error("Error flushing usage")
+			}
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.flush(); err != nil {
+			log.WithField("err", err).Error("Error flushing usage")
+		}
+	}
+}
+
+// flush adds the usage aggregated in memory to the usage persisted and
+// deletes the usage older than Retention. The usage aggregated in memory is
+// dropped when it cannot be persisted, so that a database outage does not
+// exhaust the memory.
+func (s *System) flush() error {
+	s.mutex.Lock()
+	periods := s.periods
+	s.periods = map[time.Time]map[string]*history.TenantUsage{}
+	s.tenants = 0
+	s.Metrics.TenantsGauge.Update(0)
+	s.mutex.Unlock()
+
+	startTime := time.Now()
+	defer s.Metrics.FlushTimer.UpdateSince(startTime)
+
+	for periodStart, usages := range periods {
+		list := make([]history.TenantUsage, 0, len(usages))
+		for _, usage := range usages {
+			list = append(list, *usage)
+		}
+		if err := s.HistoryQ.AddUsage(periodStart, list, flushBatchSize); err != nil {
+			return errors.Wrapf(err, "could not add the usage of the period starting at %v", periodStart)
+		}
+	}
+
+	deleted, err := s.HistoryQ.DeleteUsageBefore(s.now().Add(-s.Retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.WithField("deleted", deleted).Debug("Deleted expired usage")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+)
+
+func TestRecord(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+
+	now := time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)
+	sys := New(nil, time.Hour)
+	sys.MaxTenants = 2
+	sys.now = func() time.Time { return now }
+
+	sys.Record("ip:1.2.3.4", "/ledgers", 100, false)
+	sys.Record("ip:1.2.3.4", "/ledgers", 50, false)
+	sys.Record("key:abc", "/accounts/{account_id}", 10, true)
+	// tenants above MaxTenants are accounted to the other tenant
+	sys.Record("ip:5.6.7.8", "/ledgers", 20, false)
+	sys.Record("ip:9.9.9.9", "/ledgers", 30, false)
+
+	usages := sys.periods[time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)]
+	tt.Assert.Len(usages, 3)
+	tt.Assert.Equal(history.TenantUsage{
+		Tenant: "ip:1.2.3.4", Requests: 2, Bytes: 150,
+		Endpoints: map[string]int64{"/ledgers": 2},
+	}, *usages["ip:1.2.3.4"])
+	tt.Assert.Equal(history.TenantUsage{
+		Tenant: "key:abc", Requests: 1, Bytes: 10, RateLimited: 1,
+		Endpoints: map[string]int64{"/accounts/{account_id}": 1},
+	}, *usages["key:abc"])
+	tt.Assert.Equal(history.TenantUsage{
+		Tenant: OtherTenant, Requests: 2, Bytes: 50,
+		Endpoints: map[string]int64{"/ledgers": 2},
+	}, *usages[OtherTenant])
+	tt.Assert.Equal(int64(3), sys.Metrics.TenantsGauge.Value())
+}
+
+func TestFlush(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+
+	now := time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC)
+	sys := New(tt.HorizonSession(), 24*time.Hour)
+	sys.now = func() time.Time { return now }
+
+	// usage older than the retention is deleted
+	tt.Require.NoError(sys.HistoryQ.AddUsage(now.Add(-48*time.Hour), []history.TenantUsage{
+		{Tenant: "ip:1.2.3.4", Requests: 1, Endpoints: map[string]int64{"/": 1}},
+	}, 10))
+
+	sys.Record("ip:1.2.3.4", "/ledgers", 100, false)
+	now = now.Add(time.Hour)
+	sys.Record("ip:1.2.3.4", "/ledgers", 100, false)
+	tt.Require.NoError(sys.flush())
+	tt.Assert.Empty(sys.periods)
+	tt.Assert.Equal(int64(0), sys.Metrics.TenantsGauge.Value())
+
+	sys.Record("ip:1.2.3.4", "/", 10, false)
+	tt.Require.NoError(sys.flush())
+
+	usages, err := sys.HistoryQ.GetTopUsage(now.Add(-72*time.Hour), now.Add(time.Hour), 10)
+	tt.Require.NoError(err)
+	tt.Assert.Equal([]history.TenantUsage{{
+		Tenant: "ip:1.2.3.4", Requests: 3, Bytes: 210,
+		Endpoints: map[string]int64{"/ledgers": 2, "/": 1},
+	}}, usages)
+}
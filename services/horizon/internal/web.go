@@ -23,7 +23,9 @@ import (
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/render/sse"
 	"github.com/stellar/go/services/horizon/internal/txsub/sequence"
+	"github.com/stellar/go/support/app"
 	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/throttled"
@@ -37,18 +39,31 @@ const (
 // Web contains the http server related fields for horizon: the router,
 // rate limiter, etc.
 type web struct {
-	appCtx             context.Context
-	router             *chi.Mux
-	internalRouter     *chi.Mux
-	rateLimiter        *throttled.HTTPRateLimiter
+	appCtx         context.Context
+	router         *chi.Mux
+	internalRouter *chi.Mux
+	rateLimiter    *throttled.HTTPRateLimiter
+	// routeRateLimiters are the rate limiters of the submission and path
+	// finding rate limit groups, when they have their own quota.
+	routeRateLimiters  map[string]*throttled.HTTPRateLimiter
+	friendbotURL       reloadableURL
 	sseUpdateFrequency time.Duration
 	staleThreshold     uint
+	ledgerState        *ledger.Store
+	// pathPrefix is the path under which the router is mounted when horizon
+	// serves more than one network.
+	pathPrefix string
 
 	historyQ *history.Q
-
-	requestTimer metrics.Timer
-	failureMeter metrics.Meter
-	successMeter metrics.Meter
+	// readReplicas serve the read queries of requests when configured.
+	readReplicas *readReplicas
+
+	requestTimer     metrics.Timer
+	routeTimer       *actions.LabeledTimer
+	failureMeter     metrics.Meter
+	successMeter     metrics.Meter
+	rateLimitedMeter metrics.Meter
+	sseConnections   metrics.Counter
 }
 
 func init() {
@@ -77,9 +92,13 @@ func mustInitWeb(ctx context.Context, hq *history.Q, updateFreq time.Duration, t
 		historyQ:           hq,
 		sseUpdateFrequency: updateFreq,
 		staleThreshold:     threshold,
+		ledgerState:        ledger.DefaultStore(),
 		requestTimer:       metrics.NewTimer(),
+		routeTimer:         actions.NewLabeledTimer("requests.duration_seconds", "route", "method"),
 		failureMeter:       metrics.NewMeter(),
 		successMeter:       metrics.NewMeter(),
+		rateLimitedMeter:   metrics.NewMeter(),
+		sseConnections:     metrics.NewCounter(),
 	}
 }
 
@@ -96,22 +115,27 @@ func (w *web) mustInstallMiddlewares(app *App, connTimeout time.Duration) {
 
 	//TODO: remove this middleware
 	r.Use(appContextMiddleware(app))
+	r.Use(ledgerStateMiddleware(w.ledgerState))
 
 	r.Use(requestCacheHeadersMiddleware)
 	r.Use(chimiddleware.RequestID)
 	r.Use(contextMiddleware)
+	if w.pathPrefix != "" {
+		r.Use(pathPrefixMiddleware(w.pathPrefix))
+	}
 	r.Use(xff.Handler)
-	r.Use(loggerMiddleware)
+	r.Use(loggerMiddleware(app.config.SlowRequestThreshold))
 	r.Use(timeoutMiddleware(connTimeout))
 	r.Use(requestMetricsMiddleware)
+	if app.usage != nil {
+		r.Use(usageMiddleware(app.usage, app.config.UsageAccountingKeyHeader))
+	}
+	r.Use(tracingMiddleware)
 	r.Use(recoverMiddleware)
-	r.Use(chimiddleware.Compress(flate.DefaultCompression, "application/hal+json"))
+	r.Use(compressMiddleware(flate.DefaultCompression))
+	r.Use(fieldsMiddleware)
 
-	c := cors.New(cors.Options{
-		AllowedOrigins: []string{"*"},
-		AllowedHeaders: []string{"*"},
-		ExposedHeaders: []string{"Date"},
-	})
+	c := cors.New(corsOptions(app.config))
 	r.Use(c.Handler)
 
 	r.Use(w.RateLimitMiddleware)
@@ -120,15 +144,16 @@ func (w *web) mustInstallMiddlewares(app *App, connTimeout time.Duration) {
 	w.internalRouter.Use(chimiddleware.StripSlashes)
 	w.internalRouter.Use(appContextMiddleware(app))
 	w.internalRouter.Use(chimiddleware.RequestID)
-	w.internalRouter.Use(loggerMiddleware)
+	w.internalRouter.Use(loggerMiddleware(app.config.SlowRequestThreshold))
 }
 
 type historyLedgerSourceFactory struct {
 	updateFrequency time.Duration
+	ledgerState     *ledger.Store
 }
 
 func (f historyLedgerSourceFactory) Get() ledger.Source {
-	return ledger.NewHistoryDBSource(f.updateFrequency)
+	return ledger.NewHistoryDBSourceFromStore(f.updateFrequency, f.ledgerState)
 }
 
 // mustInstallActions installs the routing configuration of horizon onto the
@@ -140,17 +165,36 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 
 	stateMiddleware := StateMiddleware{
 		HorizonSession: session,
+		ReadReplicas:   w.readReplicas,
 	}
 
 	r := w.router
 	r.Get("/", RootAction{}.Handle)
 
+	openAPI, err := openAPIHandler(app.Version())
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "generating the OpenAPI document"))
+	}
+	r.Get("/openapi.json", openAPI)
+
 	streamHandler := sse.StreamHandler{
-		RateLimiter:         w.rateLimiter,
-		LedgerSourceFactory: historyLedgerSourceFactory{updateFrequency: w.sseUpdateFrequency},
+		AppCtx:      w.appCtx,
+		RateLimiter: w.rateLimiter,
+		LedgerSourceFactory: historyLedgerSourceFactory{
+			updateFrequency: w.sseUpdateFrequency,
+			ledgerState:     w.ledgerState,
+		},
 	}
 
-	historyMiddleware := NewHistoryMiddleware(int32(w.staleThreshold), session)
+	historyMiddleware := NewHistoryMiddleware(int32(w.staleThreshold), session, w.readReplicas)
+	// requests to the history endpoints, except the ledgers, are rejected
+	// when the history is not ingested
+	historyIngested := func(h http.Handler) http.Handler {
+		return h
+	}
+	if config.StateOnly {
+		historyIngested = historyNotIngestedMiddleware
+	}
 
 	// State endpoints behind stateMiddleware
 	r.Group(func(r chi.Router) {
@@ -160,6 +204,7 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 			r.Method(http.MethodGet, "/", restPageHandler(actions.GetAccountsHandler{}))
 			r.Route("/{account_id}", func(r chi.Router) {
 				r.Get("/", w.streamShowActionHandler(w.getAccountInfo, true))
+				r.Method(http.MethodGet, "/data", restPageHandler(actions.GetAccountDataHandler{}))
 				r.Get("/data/{key}", DataShowAction{}.Handle)
 				r.Method(http.MethodGet, "/offers", streamableStatePageHandler(actions.GetAccountOffersHandler{}, streamHandler))
 			})
@@ -171,6 +216,7 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 		})
 
 		r.Method(http.MethodGet, "/assets", restPageHandler(actions.AssetStatsHandler{}))
+		r.Method(http.MethodGet, "/ledger_entries", objectActionHandler{actions.GetLedgerEntryHandler{}})
 
 		findPaths := FindPathsHandler{
 			staleThreshold:       config.StaleThreshold,
@@ -205,6 +251,8 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 	// need to use absolute routes here. Make sure we use regexp check here for
 	// emptiness. Without it, requesting `/accounts//payments` return all payments!
 	r.Group(func(r chi.Router) {
+		r.Use(historyIngested)
+		r.Use(w.timestampCursorMiddleware)
 		r.Get("/accounts/{account_id:\\w+}/transactions", w.streamIndexActionHandler(w.getTransactionPage, w.streamTransactions))
 		r.Get("/accounts/{account_id:\\w+}/trades", TradeIndexAction{}.Handle)
 		r.Group(func(r chi.Router) {
@@ -220,11 +268,13 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 	})
 	// ledger actions
 	r.Route("/ledgers", func(r chi.Router) {
+		r.Use(w.timestampCursorMiddleware)
 		r.Get("/", LedgerIndexAction{}.Handle)
 		r.Route("/{ledger_id}", func(r chi.Router) {
 			r.Get("/", LedgerShowAction{}.Handle)
-			r.Get("/transactions", w.streamIndexActionHandler(w.getTransactionPage, w.streamTransactions))
+			r.With(historyIngested).Get("/transactions", w.streamIndexActionHandler(w.getTransactionPage, w.streamTransactions))
 			r.Group(func(r chi.Router) {
+				r.Use(historyIngested)
 				r.Use(historyMiddleware)
 				r.Method(http.MethodGet, "/effects", streamableHistoryPageHandler(actions.GetEffectsHandler{}, streamHandler))
 				r.Method(http.MethodGet, "/operations", streamableHistoryPageHandler(actions.GetOperationsHandler{
@@ -239,6 +289,8 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 
 	// transaction history actions
 	r.Route("/transactions", func(r chi.Router) {
+		r.Use(historyIngested)
+		r.Use(w.timestampCursorMiddleware)
 		r.Get("/", w.streamIndexActionHandler(w.getTransactionPage, w.streamTransactions))
 		r.Route("/{tx_id}", func(r chi.Router) {
 			r.Get("/", showActionHandler(w.getTransactionResource))
@@ -257,6 +309,8 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 
 	// operation actions
 	r.Route("/operations", func(r chi.Router) {
+		r.Use(historyIngested)
+		r.Use(w.timestampCursorMiddleware)
 		r.With(historyMiddleware).Method(http.MethodGet, "/", streamableHistoryPageHandler(actions.GetOperationsHandler{
 			OnlyPayments: false,
 		}, streamHandler))
@@ -266,6 +320,8 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 	})
 
 	r.Group(func(r chi.Router) {
+		r.Use(historyIngested)
+		r.Use(w.timestampCursorMiddleware)
 		// payment actions
 		r.With(historyMiddleware).Method(http.MethodGet, "/payments", streamableHistoryPageHandler(actions.GetOperationsHandler{
 			OnlyPayments: true,
@@ -287,33 +343,62 @@ func (w *web) mustInstallActions(config Config, pathFinder paths.Finder, session
 
 	// Network state related endpoints
 	r.Get("/fee_stats", FeeStatsAction{}.Handle)
-
-	// friendbot
-	if config.FriendbotURL != nil {
-		redirectFriendbot := func(w http.ResponseWriter, r *http.Request) {
-			redirectURL := config.FriendbotURL.String() + "?" + r.URL.RawQuery
-			http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+	r.With(historyMiddleware).Method(http.MethodGet, "/ledger_estimates", objectActionHandler{actions.GetLedgerEstimateHandler{}})
+
+	// friendbot, the routes are always installed because the friendbot URL
+	// can be set when the configuration is reloaded
+	redirectFriendbot := func(rw http.ResponseWriter, r *http.Request) {
+		friendbotURL := w.friendbotURL.get()
+		if friendbotURL == nil {
+			NotFoundAction{}.Handle(rw, r)
+			return
 		}
-		r.Post("/friendbot", redirectFriendbot)
-		r.Get("/friendbot", redirectFriendbot)
+		redirectURL := friendbotURL.String() + "?" + r.URL.RawQuery
+		http.Redirect(rw, r, redirectURL, http.StatusTemporaryRedirect)
 	}
+	r.Post("/friendbot", redirectFriendbot)
+	r.Get("/friendbot", redirectFriendbot)
 
 	r.NotFound(NotFoundAction{}.Handle)
 
 	// internal
-	w.internalRouter.Get("/metrics", HandleMetrics(&actions.MetricsHandler{registry}))
+	w.internalRouter.Get("/metrics", HandleMetrics(&actions.MetricsHandler{
+		Metrics:       registry,
+		LabeledTimers: []*actions.LabeledTimer{w.routeTimer},
+	}))
 	w.internalRouter.Get("/debug/pprof/heap", pprof.Index)
 	w.internalRouter.Get("/debug/pprof/profile", pprof.Profile)
 }
 
-func maybeInitWebRateLimiter(rateQuota *throttled.RateQuota) *throttled.HTTPRateLimiter {
+// corsOptions builds the CORS policy of the public router from the config.
+// Origins and headers are not restricted unless configured.
+func corsOptions(config Config) cors.Options {
+	allowedOrigins := config.CORSAllowedOrigins
+	if len(allowedOrigins) == 0 {
+		allowedOrigins = []string{"*"}
+	}
+	allowedHeaders := config.CORSAllowedHeaders
+	if len(allowedHeaders) == 0 {
+		allowedHeaders = []string{"*"}
+	}
+
+	return cors.Options{
+		AllowedOrigins:   allowedOrigins,
+		AllowedHeaders:   allowedHeaders,
+		ExposedHeaders:   []string{"Date"},
+		AllowCredentials: config.CORSAllowCredentials,
+		MaxAge:           int(config.CORSMaxAge / time.Second),
+	}
+}
+
+func maybeInitWebRateLimiter(rateQuota *throttled.RateQuota, group string, newRateLimiter rateLimiterFactory) *throttled.HTTPRateLimiter {
 	// Disabled
 	if rateQuota == nil {
 		return nil
 	}
 
-	rateLimiter, err := throttled.NewGCRARateLimiter(LRUCacheSize, *rateQuota)
-	if err != nil {
+	rateLimiter := &reloadableRateLimiter{group: group, newRateLimiter: newRateLimiter}
+	if err := rateLimiter.setQuota(*rateQuota); err != nil {
 		log.Fatalf("unable to create RateLimiter: %v", err)
 	}
 
@@ -324,6 +409,45 @@ func maybeInitWebRateLimiter(rateQuota *throttled.RateQuota) *throttled.HTTPRate
 	}
 }
 
+// setRateQuotas changes the quotas of the rate limiters when the configuration
+// is reloaded. Enabling or disabling rate limiting, for all requests or for a
+// rate limit group, requires a restart.
+func (w *web) setRateQuotas(rateQuota *throttled.RateQuota, routeRateQuotas map[string]throttled.RateQuota) {
+	// streams and the groups without their own quota are rate limited with
+	// the quota of reads
+	defaultQuota := rateQuota
+	if quota, ok := routeRateQuotas[RateLimitGroupReads]; ok {
+		defaultQuota = &quota
+	}
+	setRateQuota(w.rateLimiter, defaultQuota, RateLimitGroupReads)
+
+	for group, rateLimiter := range w.routeRateLimiters {
+		var quota *throttled.RateQuota
+		if q, ok := routeRateQuotas[group]; ok {
+			quota = &q
+		}
+		setRateQuota(rateLimiter, quota, group)
+	}
+	for group := range routeRateQuotas {
+		if _, ok := w.routeRateLimiters[group]; !ok && group != RateLimitGroupReads {
+			log.WithField("group", group).Warn("Enabling or disabling rate limiting requires a restart")
+		}
+	}
+}
+
+func setRateQuota(rateLimiter *throttled.HTTPRateLimiter, quota *throttled.RateQuota, group string) {
+	if rateLimiter == nil || quota == nil {
+		if rateLimiter != nil || quota != nil {
+			log.WithField("group", group).Warn("Enabling or disabling rate limiting requires a restart")
+		}
+		return
+	}
+
+	if err := rateLimiter.RateLimiter.(*reloadableRateLimiter).setQuota(*quota); err != nil {
+		log.WithField("group", group).WithField("err", err).Error("Could not change rate limit quota")
+	}
+}
+
 type VaryByRemoteIP struct{}
 
 func (v VaryByRemoteIP) Key(r *http.Request) string {
@@ -343,36 +467,25 @@ func remoteAddrIP(r *http.Request) string {
 // horizonSession returns a new session that loads data from the horizon
 // database. The returned session is bound to `ctx`.
 func (w *web) horizonSession(ctx context.Context) (*db.Session, error) {
-	err := errorIfHistoryIsStale(w.isHistoryStale())
+	err := errorIfHistoryIsStale(w.ledgerState.CurrentState(), w.isHistoryStale())
 	if err != nil {
 		return nil, err
 	}
 
-	return &db.Session{DB: w.historyQ.Session.DB, Ctx: ctx}, nil
+	return &db.Session{DB: w.readReplicas.session(w.historyQ.Session).DB, Ctx: ctx}, nil
 }
 
 // isHistoryStale returns true if the latest history ledger is more than
 // `StaleThreshold` ledgers behind the latest core ledger
 func (w *web) isHistoryStale() bool {
-	if w.staleThreshold == 0 {
-		return false
-	}
-
-	ls := ledger.CurrentState()
-	return (ls.CoreLatest - ls.HistoryLatest) > int32(w.staleThreshold)
+	return isHistoryStale(w.ledgerState.CurrentState(), w.staleThreshold)
 }
 
 // errorIfHistoryIsStale returns a formatted error if isStale is true.
-func errorIfHistoryIsStale(isStale bool) error {
+func errorIfHistoryIsStale(ls ledger.State, isStale bool) error {
 	if !isStale {
 		return nil
 	}
 
-	ls := ledger.CurrentState()
-	err := hProblem.StaleHistory
-	err.Extras = map[string]interface{}{
-		"history_latest_ledger": ls.HistoryLatest,
-		"core_latest_ledger":    ls.CoreLatest,
-	}
-	return err
+	return hProblem.NewStaleHistory(ls.HistoryLatest, ls.CoreLatest)
 }
@@ -2,13 +2,7 @@
 set -e
 
 # Check if staticcheck is installed, if not install it.
-command -v staticcheck >/dev/null 2>&1 || (
-  dir=$(mktemp -d)
-  pushd $dir
-  go mod init tool
-  go get honnef.co/go/tools/cmd/staticcheck@2020.1.3
-  popd
-)
+command -v staticcheck >/dev/null 2>&1 || go install honnef.co/go/tools/cmd/staticcheck@2023.1.7
 
 printf "Running staticcheck...\n"
 
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"go/types"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/viper"
+	"github.com/stellar/go/support/errors"
+)
+
+// LoadFile sets the options which are set neither on the command line nor
+// with their environment variable to their value in the TOML file at `path`,
+// so that flags and environment variables override the file.
+//
+// Keys are option names, e.g. `db-url = "postgres://..."`, underscores can be
+// used instead of dashes. Tables group related options: a key of a table is
+// either the name of an option prefixed with the name of the table, e.g.
+// `storage-path` in `[captive-core]` sets `captive-core-storage-path`, or the
+// name of an option itself. Arrays set list options, which are comma
+// separated on the command line, and arrays of tables set options holding
+// JSON lists.
+//
+// The file can be loaded again to change the configuration, the options which
+// were removed from it are reset to the value of their flag.
+func (cos ConfigOptions) LoadFile(path string) error {
+	var file map[string]interface{}
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return errors.Wrap(err, "could not decode config file")
+	}
+
+	options := map[string]*ConfigOption{}
+	for _, co := range cos {
+		options[co.Name] = co
+	}
+
+	values := map[string]interface{}{}
+	if err := flattenFile("", file, options, values); err != nil {
+		return err
+	}
+
+	fileValues := map[string]interface{}{}
+	for name, value := range values {
+		co := options[name]
+		if (co.flag != nil && co.flag.Changed) || os.Getenv(co.EnvVar) != "" {
+			continue
+		}
+		v, err := co.fileValue(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value of %s in config file", name)
+		}
+		fileValues[name] = v
+	}
+
+	for _, co := range cos {
+		if v, ok := fileValues[co.Name]; ok {
+			viper.Set(co.Name, v)
+			co.fromFile = true
+		} else if co.fromFile && co.flag != nil {
+			viper.Set(co.Name, co.flag.Value.String())
+			co.fromFile = false
+		}
+	}
+	return nil
+}
+
+// flattenFile maps the keys of the table `section` of a config file to the
+// names of the options they set.
+func flattenFile(section string, table map[string]interface{}, options map[string]*ConfigOption, values map[string]interface{}) error {
+	for key, value := range table {
+		name := strings.Replace(key, "_", "-", -1)
+		if section != "" {
+			if _, ok := options[section+"-"+name]; ok {
+				name = section + "-" + name
+			}
+		}
+
+		_, isOption := options[name]
+		if subtable, ok := value.(map[string]interface{}); ok && !isOption {
+			if err := flattenFile(name, subtable, options, values); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !isOption {
+			if section != "" {
+				return errors.Errorf("unknown option %s in [%s] of config file", key, section)
+			}
+			return errors.Errorf("unknown option %s in config file", key)
+		}
+		if _, ok := values[name]; ok {
+			return errors.Errorf("option %s is set more than once in config file", name)
+		}
+		values[name] = value
+	}
+	return nil
+}
+
+// fileValue converts a value of a config file to the value of the option
+// stored in viper.
+func (co *ConfigOption) fileValue(value interface{}) (interface{}, error) {
+	switch co.OptType {
+	case types.String:
+		return fileString(value)
+	case types.Int, types.Uint, types.Uint32:
+		v, ok := value.(int64)
+		if !ok {
+			return nil, errors.Errorf("expected an integer, got %v", value)
+		}
+		if v < 0 && co.OptType != types.Int {
+			return nil, errors.Errorf("expected a positive integer, got %d", v)
+		}
+		return int(v), nil
+	case types.Bool:
+		v, ok := value.(bool)
+		if !ok {
+			return nil, errors.Errorf("expected a boolean, got %v", value)
+		}
+		return v, nil
+	default:
+		return nil, errors.New("Unexpected OptType")
+	}
+}
+
+func fileString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []map[string]interface{}:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", errors.Wrap(err, "could not encode tables")
+		}
+		return string(encoded), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := fileString(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", errors.Errorf("unexpected value %v", value)
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"go/types"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fileOptions struct {
+	URL         string
+	Port        uint
+	Ingest      bool
+	StoragePath string
+	ArchiveURLs string
+	Networks    string
+	Ratio       string
+	Overridden  string
+	FromEnv     string
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "config-*.toml")
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	return file.Name()
+}
+
+// Test that the options set neither with flags nor with env vars are set from
+// the config file.
+func TestConfigOptions_LoadFile(t *testing.T) {
+	opts := fileOptions{}
+	configOpts := ConfigOptions{
+		{Name: "file-db-url", OptType: types.String, ConfigKey: &opts.URL},
+		{Name: "file-port", OptType: types.Uint, ConfigKey: &opts.Port, FlagDefault: uint(8000)},
+		{Name: "file-ingest", OptType: types.Bool, ConfigKey: &opts.Ingest, FlagDefault: false},
+		{Name: "file-captive-core-storage-path", OptType: types.String, ConfigKey: &opts.StoragePath},
+		{Name: "file-archive-urls", OptType: types.String, ConfigKey: &opts.ArchiveURLs},
+		{Name: "file-networks", OptType: types.String, ConfigKey: &opts.Networks},
+		{Name: "file-ratio", OptType: types.String, ConfigKey: &opts.Ratio},
+		{Name: "file-overridden", OptType: types.String, ConfigKey: &opts.Overridden},
+		{Name: "file-from-env", OptType: types.String, ConfigKey: &opts.FromEnv},
+	}
+
+	path := writeConfigFile(t, `
+file_db_url = "postgres://localhost/horizon"
+file-port = 8001
+file-archive-urls = ["http://a", "http://b"]
+file-ratio = 0.5
+file-overridden = "file"
+file-from-env = "file"
+
+[file-captive-core]
+storage-path = "/var/lib/horizon"
+file-ingest = true
+
+[[file-networks]]
+path_prefix = "/testnet"
+`)
+	defer os.Remove(path)
+
+	var loadErr error
+	cmd := &cobra.Command{
+		Use: "doathing",
+		Run: func(_ *cobra.Command, _ []string) {
+			loadErr = configOpts.LoadFile(path)
+			configOpts.Require()
+			configOpts.SetValues()
+		},
+	}
+	require.NoError(t, configOpts.Init(cmd))
+
+	defer os.Setenv("FILE_FROM_ENV", os.Getenv("FILE_FROM_ENV"))
+	os.Setenv("FILE_FROM_ENV", "env")
+	cmd.SetArgs([]string{"--file-overridden", "flag"})
+	require.NoError(t, cmd.Execute())
+
+	require.NoError(t, loadErr)
+	assert.Equal(t, "postgres://localhost/horizon", opts.URL)
+	assert.Equal(t, uint(8001), opts.Port)
+	assert.True(t, opts.Ingest)
+	assert.Equal(t, "/var/lib/horizon", opts.StoragePath)
+	assert.Equal(t, "http://a,http://b", opts.ArchiveURLs)
+	assert.Equal(t, `[{"path_prefix":"/testnet"}]`, opts.Networks)
+	assert.Equal(t, "0.5", opts.Ratio)
+	assert.Equal(t, "flag", opts.Overridden)
+	assert.Equal(t, "env", opts.FromEnv)
+}
+
+func TestConfigOptions_LoadFileErrors(t *testing.T) {
+	var port uint
+	configOpts := ConfigOptions{
+		{Name: "errors-port", OptType: types.Uint, ConfigKey: &port, FlagDefault: uint(8000)},
+	}
+	require.NoError(t, configOpts.Init(&cobra.Command{Use: "doathing"}))
+
+	for content, expected := range map[string]string{
+		`errors-port = "8000"`:                "invalid value of errors-port in config file: expected an integer, got 8000",
+		`errors-port = -1`:                    "invalid value of errors-port in config file: expected a positive integer, got -1",
+		`unknown = 1`:                         "unknown option unknown in config file",
+		"[errors]\nunknown = 1":               "unknown option unknown in [errors] of config file",
+		"errors-port = 1\n[errors]\nport = 2": "option errors-port is set more than once in config file",
+	} {
+		path := writeConfigFile(t, content)
+		err := configOpts.LoadFile(path)
+		os.Remove(path)
+		if assert.Error(t, err, content) {
+			assert.Equal(t, expected, err.Error(), content)
+		}
+	}
+}
+
+// Test that the options removed from the config file are reset to their flag
+// value when it is loaded again.
+func TestConfigOptions_LoadFileAgain(t *testing.T) {
+	var value string
+	configOpts := ConfigOptions{
+		{Name: "reload-value", OptType: types.String, ConfigKey: &value, FlagDefault: "default"},
+	}
+	require.NoError(t, configOpts.Init(&cobra.Command{Use: "doathing"}))
+	configOpts.Require()
+
+	path := writeConfigFile(t, `reload-value = "file"`)
+	defer os.Remove(path)
+	require.NoError(t, configOpts.LoadFile(path))
+	configOpts.SetValues()
+	assert.Equal(t, "file", value)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(""), 0644))
+	require.NoError(t, configOpts.LoadFile(path))
+	configOpts.SetValues()
+	assert.Equal(t, "default", value)
+}
@@ -54,6 +54,7 @@ type ConfigOption struct {
 	CustomSetValue func(*ConfigOption) // Optional function for custom validation/transformation
 	ConfigKey      interface{}         // Pointer to the final key in the linked Config struct
 	flag           *pflag.Flag         // The persistent flag that the config option is attached to
+	fromFile       bool                // Whether the value of this option was set by LoadFile
 }
 
 // Init handles initialisation steps, including configuring and binding the env variable name.
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxRecordedQueries is the number of slowest queries kept by QueryStats.
+const maxRecordedQueries = 10
+
+// QueryRecord is a query run by a session and its duration.
+type QueryRecord struct {
+	SQL      string
+	Duration time.Duration
+}
+
+// QueryStats collects statistics about the queries run by the sessions whose
+// context holds it, for example the queries run while serving a request. It
+// is safe for concurrent use.
+type QueryStats struct {
+	mu       sync.Mutex
+	count    int
+	duration time.Duration
+	slowest  []QueryRecord
+}
+
+type queryStatsContextKey struct{}
+
+// WithQueryStats returns a copy of `ctx` collecting the statistics of the
+// queries run with it.
+func WithQueryStats(ctx context.Context) (context.Context, *QueryStats) {
+	stats := &QueryStats{}
+	return context.WithValue(ctx, queryStatsContextKey{}, stats), stats
+}
+
+// QueryStatsFromContext returns the query statistics of `ctx`, or nil.
+func QueryStatsFromContext(ctx context.Context) *QueryStats {
+	if ctx == nil {
+		return nil
+	}
+	stats, _ := ctx.Value(queryStatsContextKey{}).(*QueryStats)
+	return stats
+}
+
+func (s *QueryStats) record(query string, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	s.duration += duration
+
+	if len(s.slowest) < maxRecordedQueries {
+		s.slowest = append(s.slowest, QueryRecord{SQL: query, Duration: duration})
+		return
+	}
+	fastest := 0
+	for i, record := range s.slowest {
+		if record.Duration < s.slowest[fastest].Duration {
+			fastest = i
+		}
+	}
+	if duration > s.slowest[fastest].Duration {
+		s.slowest[fastest] = QueryRecord{SQL: query, Duration: duration}
+	}
+}
+
+// Count returns the number of queries run.
+func (s *QueryStats) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Duration returns the total time spent running queries.
+func (s *QueryStats) Duration() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.duration
+}
+
+// Slowest returns the slowest queries run, up to 10, slowest first.
+func (s *QueryStats) Slowest() []QueryRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slowest := append([]QueryRecord(nil), s.slowest...)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].Duration > slowest[j].Duration
+	})
+	return slowest
+}
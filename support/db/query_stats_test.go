@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryStats(t *testing.T) {
+	assert.Nil(t, QueryStatsFromContext(context.Background()))
+
+	ctx, stats := WithQueryStats(context.Background())
+	assert.Equal(t, stats, QueryStatsFromContext(ctx))
+
+	for i := 1; i <= 15; i++ {
+		stats.record(fmt.Sprintf("query %d", i), time.Duration(i%8)*time.Millisecond)
+	}
+
+	assert.Equal(t, 15, stats.Count())
+	assert.Equal(t, 56*time.Millisecond, stats.Duration())
+
+	slowest := stats.Slowest()
+	assert.Len(t, slowest, maxRecordedQueries)
+	assert.Equal(t, QueryRecord{SQL: "query 7", Duration: 7 * time.Millisecond}, slowest[0])
+	for i := 1; i < len(slowest); i++ {
+		assert.True(t, slowest[i-1].Duration >= slowest[i].Duration)
+	}
+	assert.Equal(t, 3*time.Millisecond, slowest[len(slowest)-1].Duration)
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/stellar/go/support/db/sqlutils"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Begin binds this session to a new transaction.
@@ -374,13 +377,28 @@ func (s *Session) conn() Conn {
 	return s.DB
 }
 
+// log logs the query, records it in the query statistics of the session's
+// context and, when the context is traced, records its span.
 func (s *Session) log(typ string, start time.Time, query string, args []interface{}) {
+	duration := time.Since(start)
 	log.
 		Ctx(s.logCtx()).
 		WithField("args", args).
 		WithField("sql", query).
-		WithField("dur", time.Since(start).String()).
+		WithField("dur", duration.String()).
 		Debugf("sql: %s", typ)
+
+	if stats := QueryStatsFromContext(s.Ctx); stats != nil {
+		stats.record(query, duration)
+	}
+
+	if span := tracing.StartChildAt(s.logCtx(), "sql "+typ, trace.SpanKindClient, start); span.IsRecording() {
+		span.SetAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", query),
+		)
+		span.End()
+	}
 }
 
 func (s *Session) logBegin() {
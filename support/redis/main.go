@@ -0,0 +1,49 @@
+// Package redis connects the stellar services to a Redis server with the
+// go-redis client, behind the small interface the services need.
+package redis
+
+import (
+	"context"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stellar/go/support/errors"
+)
+
+// Client is the part of a Redis client the stellar services need: running Lua
+// scripts. *goredis.Client implements it.
+type Client interface {
+	goredis.Scripter
+	Close() error
+}
+
+// NewClient returns a client of the server at `rawURL`, of the form
+// redis://[:password@]host[:port][/db] or rediss:// for TLS connections.
+func NewClient(rawURL string) (Client, error) {
+	options, err := goredis.ParseURL(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid redis url")
+	}
+	return goredis.NewClient(options), nil
+}
+
+// Script is a Lua script. It is sent to the server with EVALSHA, and with EVAL
+// when the server has not cached it yet.
+type Script struct {
+	script *goredis.Script
+}
+
+// NewScript returns the script with source `src`.
+func NewScript(src string) *Script {
+	return &Script{script: goredis.NewScript(src)}
+}
+
+// Run runs the script with the given keys and arguments and returns its reply:
+// an int64 for integers, a string for strings, an []interface{} for arrays and
+// nil for nil replies.
+func (s *Script) Run(ctx context.Context, c Client, keys []string, args ...interface{}) (interface{}, error) {
+	reply, err := s.script.Run(ctx, c, keys, args...).Result()
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	return reply, err
+}
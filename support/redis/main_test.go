@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScripter records the scripts it runs and replies to EVALSHA as a server
+// which has not cached the scripts yet.
+type fakeScripter struct {
+	commands [][]interface{}
+}
+
+func (f *fakeScripter) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *goredis.Cmd {
+	f.commands = append(f.commands, []interface{}{"EVAL", script, keys, args})
+	return goredis.NewCmdResult(int64(1), nil)
+}
+
+func (f *fakeScripter) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *goredis.Cmd {
+	f.commands = append(f.commands, []interface{}{"EVALSHA", sha1, keys, args})
+	return goredis.NewCmdResult(nil, errors.New("NOSCRIPT No matching script. Please use EVAL."))
+}
+
+func (f *fakeScripter) ScriptExists(ctx context.Context, hashes ...string) *goredis.BoolSliceCmd {
+	return goredis.NewBoolSliceResult(make([]bool, len(hashes)), nil)
+}
+
+func (f *fakeScripter) ScriptLoad(ctx context.Context, script string) *goredis.StringCmd {
+	return goredis.NewStringResult("", nil)
+}
+
+func (f *fakeScripter) Close() error {
+	return nil
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient("redis://:secret@localhost:6379/2")
+	require.NoError(t, err)
+	options := client.(*goredis.Client).Options()
+	assert.Equal(t, "localhost:6379", options.Addr)
+	assert.Equal(t, "secret", options.Password)
+	assert.Equal(t, 2, options.DB)
+	assert.Nil(t, options.TLSConfig)
+
+	client, err = NewClient("rediss://redis.example.com:6380")
+	require.NoError(t, err)
+	options = client.(*goredis.Client).Options()
+	assert.Equal(t, "redis.example.com:6380", options.Addr)
+	assert.NotNil(t, options.TLSConfig)
+
+	_, err = NewClient("http://localhost")
+	assert.Error(t, err)
+	_, err = NewClient("redis://localhost/db")
+	assert.Error(t, err)
+}
+
+func TestScriptRun(t *testing.T) {
+	script := NewScript("return 1")
+	client := &fakeScripter{}
+	reply, err := script.Run(context.Background(), client, []string{"key"}, "arg")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), reply)
+
+	// the script is sent with EVAL when the server has not cached it
+	if assert.Len(t, client.commands, 2) {
+		assert.Equal(t, "EVALSHA", client.commands[0][0])
+		assert.Equal(t, []interface{}{"EVAL", "return 1", []string{"key"}, []interface{}{"arg"}}, client.commands[1])
+	}
+}
@@ -41,7 +41,7 @@ func (lb *LinkBuilder) Linkf(format string, args ...interface{}) Link {
 }
 
 // expandLink takes an href and resolves it against the LinkBuilders base url,
-// if set. NOTE: this method panics if the input href cannot be parsed. It is
+// if set. Absolute paths are prefixed with the path of the base url. NOTE: this method panics if the input href cannot be parsed. It is
 // meant to be used by developer author ed links, not with external data.
 func (lb *LinkBuilder) expandLink(href string) string {
 	if lb.Base == nil {
@@ -59,6 +59,12 @@ func (lb *LinkBuilder) expandLink(href string) string {
 		if u.Scheme == "" {
 			u.Scheme = lb.Base.Scheme
 		}
+
+		// keep the path prefix of the base url, e.g. when horizon is mounted
+		// under a subpath.
+		if basePath := strings.TrimSuffix(lb.Base.Path, "/"); basePath != "" && strings.HasPrefix(u.Path, "/") {
+			u.Path = basePath + u.Path
+		}
 	}
 
 	//HACK: replace the encoded path with the un-encoded path, which preserves
@@ -27,6 +27,12 @@ func TestLinkBuilder(t *testing.T) {
 
 	// Regression: ensure that parameters are not escaped
 	check("/accounts/{id}", "https://stellar.org", "https://stellar.org/accounts/{id}")
+
+	// Path prefix of the base url is preserved
+	check("/root", "https://stellar.org/testnet", "https://stellar.org/testnet/root")
+	check("/root", "https://stellar.org/testnet/", "https://stellar.org/testnet/root")
+	check("/accounts/{id}", "https://stellar.org/testnet", "https://stellar.org/testnet/accounts/{id}")
+	check("https://else.org/root", "https://stellar.org/testnet", "https://else.org/root")
 }
 
 func mustParseURL(base string) *url.URL {
@@ -0,0 +1,88 @@
+// Package tracing configures OpenTelemetry tracing for the stellar services
+// and helps recording spans with the global tracer provider. Spans are
+// exported by the OTLP/HTTP exporter of the OpenTelemetry SDK and the W3C
+// trace context is propagated, so the traces of the services can be mixed
+// with the ones of any OpenTelemetry instrumentation.
+//
+// Until Init is called the global tracer provider is the no-op provider of
+// OpenTelemetry and the spans started with the package are not recorded.
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer of the package.
+const instrumentationName = "github.com/stellar/go"
+
+// Init installs a global tracer provider exporting the spans of the service
+// `serviceName` to the OTLP/HTTP traces endpoint `otlpURL`, e.g.
+// http://localhost:4318/v1/traces. New traces are sampled with the ratio
+// `sampleRatio`, the traces of other processes are sampled when their parent
+// span is. The provider must be shut down to export the last spans.
+func Init(ctx context.Context, serviceName, otlpURL string, sampleRatio float64) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(otlpURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating OTLP exporter")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	SetTracerProvider(provider)
+	return provider, nil
+}
+
+// SetTracerProvider installs `provider` as the global tracer provider, and
+// the W3C trace context as the global propagator.
+func SetTracerProvider(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Start starts a span with the global tracer provider. The span is the child
+// of the span of `ctx`, or the root of a new trace. It returns a copy of `ctx`
+// holding the new span. The span must be ended.
+func Start(ctx context.Context, name string, kind trace.SpanKind) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind))
+}
+
+// StartChildAt starts a span at `start` only when `ctx` holds a recording
+// span, so that low level operations, like database queries, are traced as
+// part of a request but never start their own traces. It returns a
+// non-recording span otherwise.
+func StartChildAt(ctx context.Context, name string, kind trace.SpanKind, start time.Time) trace.Span {
+	if ctx == nil || !trace.SpanFromContext(ctx).IsRecording() {
+		return trace.SpanFromContext(context.Background())
+	}
+	_, span := otel.Tracer(instrumentationName).Start(
+		ctx, name, trace.WithSpanKind(kind), trace.WithTimestamp(start),
+	)
+	return span
+}
+
+// SetError records `err` on `span` and marks it as failed. It does nothing
+// when err is nil.
+func SetError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestStartDisabled(t *testing.T) {
+	ctx, span := Start(context.Background(), "request", trace.SpanKindServer)
+	assert.False(t, span.IsRecording())
+	assert.False(t, StartChildAt(ctx, "sql select", trace.SpanKindClient, time.Now()).IsRecording())
+	span.End()
+}
+
+func TestStart(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	SetTracerProvider(provider)
+	defer SetTracerProvider(noop.NewTracerProvider())
+
+	ctx, parent := Start(context.Background(), "request", trace.SpanKindServer)
+	require.True(t, parent.IsRecording())
+
+	start := time.Unix(10, 0)
+	child := StartChildAt(ctx, "sql select", trace.SpanKindClient, start)
+	require.True(t, child.IsRecording())
+	SetError(child, errors.New("failed"))
+	SetError(child, nil)
+	child.End()
+	parent.End()
+
+	assert.False(t, StartChildAt(context.Background(), "sql select", trace.SpanKindClient, start).IsRecording())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "sql select", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, start, spans[0].StartTime())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "failed", spans[0].Status().Description)
+	assert.Equal(t, "request", spans[1].Name())
+}
+
+func TestStartNotSampled(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithSampler(sdktrace.NeverSample()),
+	)
+	SetTracerProvider(provider)
+	defer SetTracerProvider(noop.NewTracerProvider())
+
+	ctx, span := Start(context.Background(), "request", trace.SpanKindServer)
+	assert.False(t, span.SpanContext().IsSampled())
+	assert.False(t, StartChildAt(ctx, "sql select", trace.SpanKindClient, time.Now()).IsRecording())
+	span.End()
+
+	assert.Empty(t, recorder.Ended())
+}
@@ -1,12 +1,14 @@
 package txnbuild
 
 import (
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
 
 // AccountMerge represents the Stellar merge account operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
+// The destination can be an account (G...) or a muxed account (M...) address.
 type AccountMerge struct {
 	Destination   string
 	SourceAccount Account
@@ -39,8 +41,7 @@ func (am *AccountMerge) FromXDR(xdrOp xdr.Operation) error {
 
 	am.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
 	if xdrOp.Body.Destination != nil {
-		aid := xdrOp.Body.Destination.ToAccountId()
-		am.Destination = aid.Address()
+		am.Destination = xdrOp.Body.Destination.Address()
 	}
 
 	return nil
@@ -49,7 +50,13 @@ func (am *AccountMerge) FromXDR(xdrOp xdr.Operation) error {
 // Validate for AccountMerge validates the required struct fields. It returns an error if any of the fields are
 // invalid. Otherwise, it returns nil.
 func (am *AccountMerge) Validate() error {
-	_, err := xdr.AddressToAccountId(am.Destination)
+	var err error
+	// account addresses are decoded as before to keep reporting strkey errors
+	if version, _ := strkey.Version(am.Destination); version == strkey.VersionByteMuxedAccount {
+		_, err = xdr.AddressToMuxedAccount(am.Destination)
+	} else {
+		_, err = xdr.AddressToAccountId(am.Destination)
+	}
 	if err != nil {
 		return NewValidationError("Destination", err.Error())
 	}
@@ -0,0 +1,287 @@
+package txnbuild
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// signaturesField is the field of the differences between the signatures of
+// envelopes returned by DiffEnvelopes.
+const signaturesField = "Signatures"
+
+// EnvelopeDifference is a difference between two transaction envelopes,
+// returned by DiffEnvelopes.
+type EnvelopeDifference struct {
+	// Field is the path of the field which differs, e.g.
+	// "V1.Tx.Operations[0].Body.PaymentOp.Amount", or "Signatures" for a
+	// signature which only one of the envelopes has.
+	Field string
+	// A and B are the values of the field in the first and the second
+	// envelope, or "<missing>" when the field or the signature is missing from
+	// an envelope.
+	A string
+	B string
+}
+
+// String returns the difference formatted as "field: a != b".
+func (d EnvelopeDifference) String() string {
+	return fmt.Sprintf("%s: %s != %s", d.Field, d.A, d.B)
+}
+
+// IsSignature returns true when the difference is a signature which only one
+// of the envelopes has.
+func (d EnvelopeDifference) IsSignature() bool {
+	return d.Field == signaturesField
+}
+
+// DiffEnvelopes compares the transaction envelopes `a` and `b` field by field
+// and returns their differences. The envelopes are compared in a canonical
+// form: v0 envelopes are compared as the equivalent v1 envelopes, which have
+// the same hash, and the order of the signatures, including the signatures of
+// the inner transaction of fee bump envelopes, is ignored. A co-signing
+// service can check that a partner did not alter a transaction, but only
+// added signatures, by checking that all the differences between the
+// envelope it sent and the envelope it received are signatures of the latter.
+func DiffEnvelopes(a, b xdr.TransactionEnvelope) ([]EnvelopeDifference, error) {
+	canonicalA, signaturesA, err := canonicalEnvelope(a)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid first envelope")
+	}
+	canonicalB, signaturesB, err := canonicalEnvelope(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid second envelope")
+	}
+
+	diffs := diffValues("", reflect.ValueOf(canonicalA), reflect.ValueOf(canonicalB), nil)
+	for _, signature := range subtractSignatures(signaturesA, signaturesB) {
+		diffs = append(diffs, EnvelopeDifference{
+			Field: signaturesField,
+			A:     formatSignature(signature),
+			B:     "<missing>",
+		})
+	}
+	for _, signature := range subtractSignatures(signaturesB, signaturesA) {
+		diffs = append(diffs, EnvelopeDifference{
+			Field: signaturesField,
+			A:     "<missing>",
+			B:     formatSignature(signature),
+		})
+	}
+	return diffs, nil
+}
+
+// EqualEnvelopes returns true when the transaction envelopes `a` and `b`
+// contain the same transaction and the same signatures, in any order. See
+// DiffEnvelopes.
+func EqualEnvelopes(a, b xdr.TransactionEnvelope) (bool, error) {
+	diffs, err := DiffEnvelopes(a, b)
+	if err != nil {
+		return false, err
+	}
+	return len(diffs) == 0, nil
+}
+
+// canonicalEnvelope returns a copy of `e` without its signatures, where a v0
+// envelope is converted to the equivalent v1 envelope and the signatures of the
+// inner transaction of a fee bump envelope are sorted, and the signatures of
+// `e`.
+func canonicalEnvelope(e xdr.TransactionEnvelope) (xdr.TransactionEnvelope, []xdr.DecoratedSignature, error) {
+	var signatures []xdr.DecoratedSignature
+	switch e.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		signatures = e.V1.Signatures
+	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
+		signatures = e.V0.Signatures
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		signatures = e.FeeBump.Signatures
+	default:
+		return xdr.TransactionEnvelope{}, nil, errors.Errorf("invalid transaction type: %d", e.Type)
+	}
+
+	// the envelope is cloned with its signatures, cloneEnvelope would otherwise
+	// clear the signatures of `e` which shares them with the caller
+	clone, err := cloneEnvelope(e, signatures)
+	if err != nil {
+		return xdr.TransactionEnvelope{}, nil, err
+	}
+	switch clone.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		clone.V1.Signatures = nil
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		clone.FeeBump.Signatures = nil
+	}
+	if clone.Type == xdr.EnvelopeTypeEnvelopeTypeTxFeeBump {
+		// the signatures of the inner transaction are compared in a
+		// deterministic order since they are compared field by field
+		innerSignatures := clone.FeeBump.Tx.InnerTx.V1.Signatures
+		sort.Slice(innerSignatures, func(i, j int) bool {
+			return compareSignatures(innerSignatures[i], innerSignatures[j]) < 0
+		})
+	}
+	if clone.Type != xdr.EnvelopeTypeEnvelopeTypeTxV0 {
+		return clone, signatures, nil
+	}
+
+	tx := clone.V0.Tx
+	sourceAccount, err := xdr.NewMuxedAccount(xdr.CryptoKeyTypeKeyTypeEd25519, tx.SourceAccountEd25519)
+	if err != nil {
+		return xdr.TransactionEnvelope{}, nil, errors.Wrap(err, "could not convert v0 envelope")
+	}
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: sourceAccount,
+				Fee:           tx.Fee,
+				SeqNum:        tx.SeqNum,
+				TimeBounds:    tx.TimeBounds,
+				Memo:          tx.Memo,
+				Operations:    tx.Operations,
+			},
+		},
+	}, signatures, nil
+}
+
+// diffValues appends to `diffs` the differences between the XDR values `a`
+// and `b`, of the same type, at the path `field`.
+func diffValues(field string, a, b reflect.Value, diffs []EnvelopeDifference) []EnvelopeDifference {
+	if formattedA, ok := formatLeaf(a); ok {
+		formattedB, _ := formatLeaf(b)
+		if formattedA != formattedB {
+			diffs = append(diffs, EnvelopeDifference{Field: field, A: formattedA, B: formattedB})
+		}
+		return diffs
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				diffs = append(diffs, EnvelopeDifference{Field: field, A: formatValue(a), B: formatValue(b)})
+			}
+			return diffs
+		}
+		return diffValues(field, a.Elem(), b.Elem(), diffs)
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			structField := a.Type().Field(i)
+			if structField.PkgPath != "" {
+				continue
+			}
+			name := structField.Name
+			if field != "" {
+				name = field + "." + name
+			}
+			diffs = diffValues(name, a.Field(i), b.Field(i), diffs)
+		}
+		return diffs
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			name := fmt.Sprintf("%s[%d]", field, i)
+			switch {
+			case i >= a.Len():
+				diffs = append(diffs, EnvelopeDifference{Field: name, A: "<missing>", B: formatValue(b.Index(i))})
+			case i >= b.Len():
+				diffs = append(diffs, EnvelopeDifference{Field: name, A: formatValue(a.Index(i)), B: "<missing>"})
+			default:
+				diffs = diffValues(name, a.Index(i), b.Index(i), diffs)
+			}
+		}
+		return diffs
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			diffs = append(diffs, EnvelopeDifference{Field: field, A: formatValue(a), B: formatValue(b)})
+		}
+		return diffs
+	}
+}
+
+// formatLeaf formats the XDR values which are compared as a whole: accounts,
+// signer keys and assets in their string form, and bytes in hexadecimal.
+func formatLeaf(v reflect.Value) (string, bool) {
+	switch value := v.Interface().(type) {
+	case xdr.AccountId:
+		address, err := value.GetAddress()
+		return address, err == nil
+	case xdr.MuxedAccount:
+		address, err := value.GetAddress()
+		return address, err == nil
+	case xdr.SignerKey:
+		address, err := value.GetAddress()
+		return address, err == nil
+	case xdr.Asset:
+		return value.String(), true
+	}
+
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8 {
+		if v.Kind() == reflect.Array {
+			// arrays are not addressable when they are fields of values
+			// which are not addressable, so they are copied to slices
+			raw := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(raw), v)
+			return fmt.Sprintf("%x", raw), true
+		}
+		return fmt.Sprintf("%x", v.Bytes()), true
+	}
+	return "", false
+}
+
+// formatValue formats the XDR value `v`, in base 64 when it is not a leaf.
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	if formatted, ok := formatLeaf(v); ok {
+		return formatted
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array:
+		if encoded, err := xdr.MarshalBase64(v.Interface()); err == nil {
+			return encoded
+		}
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// subtractSignatures returns the signatures of `a` which are not in `b`, as
+// multisets: a signature which is twice in `a` and once in `b` is returned once.
+func subtractSignatures(a, b []xdr.DecoratedSignature) []xdr.DecoratedSignature {
+	var result []xdr.DecoratedSignature
+	matched := make([]bool, len(b))
+	for _, signature := range a {
+		found := false
+		for i, other := range b {
+			if !matched[i] && compareSignatures(signature, other) == 0 {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, signature)
+		}
+	}
+	return result
+}
+
+// compareSignatures compares the signatures `a` and `b` by hint, then by
+// signature bytes.
+func compareSignatures(a, b xdr.DecoratedSignature) int {
+	if c := bytes.Compare(a.Hint[:], b.Hint[:]); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.Signature, b.Signature)
+}
+
+func formatSignature(signature xdr.DecoratedSignature) string {
+	return fmt.Sprintf("%x:%s", signature.Hint, base64.StdEncoding.EncodeToString(signature.Signature))
+}
@@ -0,0 +1,155 @@
+package txnbuild
+
+import (
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDiffTransaction(t *testing.T, amount string, memo Memo) *Transaction {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations: []Operation{&Payment{
+				Destination: newKeypair1().Address(),
+				Amount:      amount,
+				Asset:       NativeAsset{},
+			}},
+			BaseFee:    MinBaseFee,
+			Memo:       memo,
+			Timebounds: NewTimebounds(0, 1000),
+		},
+	)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestDiffEnvelopesEqual(t *testing.T) {
+	tx := newDiffTransaction(t, "10", MemoText("hello"))
+	v0, err := tx.TxEnvelope()
+	require.NoError(t, err)
+
+	// v0 envelopes are compared as the equivalent v1 envelopes
+	convertToV1Tx(tx)
+	v1, err := tx.TxEnvelope()
+	require.NoError(t, err)
+	equal, err := EqualEnvelopes(v0, v1)
+	require.NoError(t, err)
+	assert.True(t, equal)
+
+	// the order of the signatures is ignored
+	signedA, err := tx.Sign(network.TestNetworkPassphrase, newKeypair0(), newKeypair2())
+	require.NoError(t, err)
+	signedB, err := tx.Sign(network.TestNetworkPassphrase, newKeypair2(), newKeypair0())
+	require.NoError(t, err)
+	envelopeA, err := signedA.TxEnvelope()
+	require.NoError(t, err)
+	envelopeB, err := signedB.TxEnvelope()
+	require.NoError(t, err)
+	diffs, err := DiffEnvelopes(envelopeA, envelopeB)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	// the order of the signatures of inner transactions is ignored as well
+	feeBumpA, err := NewFeeBumpTransaction(FeeBumpTransactionParams{
+		Inner:      signedA,
+		FeeAccount: newKeypair1().Address(),
+		BaseFee:    MinBaseFee,
+	})
+	require.NoError(t, err)
+	feeBumpB, err := NewFeeBumpTransaction(FeeBumpTransactionParams{
+		Inner:      signedB,
+		FeeAccount: newKeypair1().Address(),
+		BaseFee:    MinBaseFee,
+	})
+	require.NoError(t, err)
+	envelopeA, err = feeBumpA.TxEnvelope()
+	require.NoError(t, err)
+	envelopeB, err = feeBumpB.TxEnvelope()
+	require.NoError(t, err)
+	diffs, err = DiffEnvelopes(envelopeA, envelopeB)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
+
+func TestDiffEnvelopesFields(t *testing.T) {
+	a, err := newDiffTransaction(t, "10", MemoText("hello")).TxEnvelope()
+	require.NoError(t, err)
+	b, err := newDiffTransaction(t, "20", MemoID(1)).TxEnvelope()
+	require.NoError(t, err)
+
+	diffs, err := DiffEnvelopes(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, []EnvelopeDifference{
+		{Field: "V1.Tx.Memo.Type", A: "MemoTypeMemoText", B: "MemoTypeMemoId"},
+		{Field: "V1.Tx.Memo.Text", A: "hello", B: "<nil>"},
+		{Field: "V1.Tx.Memo.Id", A: "<nil>", B: "1"},
+		{Field: "V1.Tx.Operations[0].Body.PaymentOp.Amount", A: "100000000", B: "200000000"},
+	}, diffs)
+	assert.Equal(t, "V1.Tx.Operations[0].Body.PaymentOp.Amount: 100000000 != 200000000", diffs[3].String())
+
+	equal, err := EqualEnvelopes(a, b)
+	require.NoError(t, err)
+	assert.False(t, equal)
+}
+
+func TestDiffEnvelopesSignatures(t *testing.T) {
+	tx := newDiffTransaction(t, "10", MemoText("hello"))
+	signed, err := tx.Sign(network.TestNetworkPassphrase, newKeypair0())
+	require.NoError(t, err)
+	cosigned, err := signed.Sign(network.TestNetworkPassphrase, newKeypair1())
+	require.NoError(t, err)
+
+	a, err := signed.TxEnvelope()
+	require.NoError(t, err)
+	b, err := cosigned.TxEnvelope()
+	require.NoError(t, err)
+	diffs, err := DiffEnvelopes(a, b)
+	require.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.True(t, diffs[0].IsSignature())
+		assert.Equal(t, "<missing>", diffs[0].A)
+		assert.Contains(t, diffs[0].B, ":")
+	}
+
+	diffs, err = DiffEnvelopes(b, a)
+	require.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.True(t, diffs[0].IsSignature())
+		assert.Equal(t, "<missing>", diffs[0].B)
+	}
+}
+
+func TestDiffEnvelopesDuplicateSignatures(t *testing.T) {
+	tx := newDiffTransaction(t, "10", MemoText("hello"))
+	signed, err := tx.Sign(network.TestNetworkPassphrase, newKeypair0())
+	require.NoError(t, err)
+	a, err := signed.TxEnvelope()
+	require.NoError(t, err)
+	b, err := signed.TxEnvelope()
+	require.NoError(t, err)
+	b.V0.Signatures = append(b.V0.Signatures, b.V0.Signatures[0])
+
+	// a signature which is twice in an envelope and once in the other one is
+	// a difference
+	diffs, err := DiffEnvelopes(a, b)
+	require.NoError(t, err)
+	if assert.Len(t, diffs, 1) {
+		assert.True(t, diffs[0].IsSignature())
+		assert.Equal(t, "<missing>", diffs[0].A)
+		assert.Equal(t, formatSignature(b.V0.Signatures[0]), diffs[0].B)
+	}
+}
+
+func TestDiffEnvelopesInvalidType(t *testing.T) {
+	a, err := newDiffTransaction(t, "10", MemoText("hello")).TxEnvelope()
+	require.NoError(t, err)
+	_, err = DiffEnvelopes(a, xdr.TransactionEnvelope{Type: 100})
+	assert.EqualError(t, err, "invalid second envelope: invalid transaction type: 100")
+}
@@ -2,7 +2,11 @@ package txnbuild
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+
 	"github.com/stellar/go/amount"
+	pricepkg "github.com/stellar/go/price"
 	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 )
@@ -69,6 +73,56 @@ func validateAmount(n interface{}) error {
 	return nil
 }
 
+// validatePositiveAmount checks if the provided value is a valid stellar amount greater than zero, as required by
+// the amounts of operations which cannot be zero, like the amount of payments. It returns an error if not.
+func validatePositiveAmount(value string) error {
+	err := validateAmount(value)
+	if err != nil {
+		return err
+	}
+
+	stellarAmount, err := amount.ParseInt64(value)
+	if err != nil {
+		return err
+	}
+	if stellarAmount == 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	return nil
+}
+
+// maxPrice is the greatest price of offers, whose numerator and denominator are int32 values.
+var maxPrice = big.NewRat(math.MaxInt32, 1)
+
+// validatePrice checks if the provided value is a valid price for offers: a stellar amount greater than zero which
+// can be represented as a fraction of int32 values. It returns an error if not.
+func validatePrice(price string) error {
+	err := validateAmount(price)
+	if err != nil {
+		return err
+	}
+
+	r, ok := new(big.Rat).SetString(price)
+	if !ok {
+		return errors.Errorf("cannot parse price: %s", price)
+	}
+	if r.Sign() == 0 {
+		return errors.New("price must be greater than zero")
+	}
+	if r.Cmp(maxPrice) > 0 {
+		return errors.Errorf("price %s is greater than the maximum price %d", price, math.MaxInt32)
+	}
+
+	xdrPrice, err := pricepkg.Parse(price)
+	if err != nil {
+		return err
+	}
+	if xdrPrice.N <= 0 || xdrPrice.D <= 0 {
+		return errors.Errorf("price %s cannot be represented as a fraction of int32 values", price)
+	}
+	return nil
+}
+
 // validateAllowTrustAsset checks if the provided asset is valid for use in AllowTrust operation.
 // It returns an error if the asset is invalid.
 // The asset must be non native (XLM) with a valid asset code.
@@ -136,7 +190,7 @@ func validatePassiveOffer(buying, selling Asset, offerAmount, price string) erro
 		return NewValidationError("Amount", err.Error())
 	}
 
-	err = validateAmount(price)
+	err = validatePrice(price)
 	if err != nil {
 		return NewValidationError("Price", err.Error())
 	}
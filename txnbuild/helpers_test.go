@@ -208,6 +208,44 @@ func TestValidateAmountInvalidValue(t *testing.T) {
 	require.EqualError(t, err, expectedErrMsg, "should be a valid stellar amount")
 }
 
+func TestValidatePositiveAmount(t *testing.T) {
+	err := validatePositiveAmount("0.0000001")
+	assert.NoError(t, err)
+
+	err = validatePositiveAmount("922337203685.4775807")
+	assert.NoError(t, err)
+
+	err = validatePositiveAmount("0")
+	require.EqualError(t, err, "amount must be greater than zero")
+
+	err = validatePositiveAmount("-1")
+	require.EqualError(t, err, "amount can not be negative")
+
+	err = validatePositiveAmount("0.00000001")
+	require.EqualError(t, err, "more than 7 significant digits: 0.00000001")
+
+	err = validatePositiveAmount("922337203685.4775808")
+	require.EqualError(t, err, `amount outside bounds of int64: 922337203685.4775808: strconv.ParseInt: parsing "9223372036854775808": value out of range`)
+}
+
+func TestValidatePrice(t *testing.T) {
+	for _, price := range []string{"0.0000001", "0.01", "1", "1.5", "2147483647"} {
+		assert.NoError(t, validatePrice(price), price)
+	}
+
+	err := validatePrice("0")
+	require.EqualError(t, err, "price must be greater than zero")
+
+	err = validatePrice("-1")
+	require.EqualError(t, err, "amount can not be negative")
+
+	err = validatePrice("0.00000001")
+	require.EqualError(t, err, "more than 7 significant digits: 0.00000001")
+
+	err = validatePrice("2147483648")
+	require.EqualError(t, err, "price 2147483648 is greater than the maximum price 2147483647")
+}
+
 func TestValidateAllowTrustAsset(t *testing.T) {
 	err := validateAllowTrustAsset(nil)
 	assert.Error(t, err)
@@ -119,6 +119,42 @@ func TestManageBuyOfferValidatePrice(t *testing.T) {
 	}
 }
 
+func TestManageBuyOfferValidatePriceLimits(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	sourceAccount := NewSimpleAccount(kp1.Address(), int64(41137196761092))
+
+	for _, testCase := range []struct {
+		price    string
+		expected string
+	}{
+		{"0", "price must be greater than zero"},
+		{"2147483648", "price 2147483648 is greater than the maximum price 2147483647"},
+	} {
+		buyOffer := ManageBuyOffer{
+			Selling: CreditAsset{"ABCD", kp0.Address()},
+			Buying:  NativeAsset{},
+			Amount:  "10",
+			Price:   testCase.price,
+			OfferID: 0,
+		}
+
+		_, err := NewTransaction(
+			TransactionParams{
+				SourceAccount:        &sourceAccount,
+				IncrementSequenceNum: false,
+				Operations:           []Operation{&buyOffer},
+				BaseFee:              MinBaseFee,
+				Timebounds:           NewInfiniteTimeout(),
+			},
+		)
+		if assert.Error(t, err) {
+			expected := "validation failed for *txnbuild.ManageBuyOffer operation: Field: Price, Error: " + testCase.expected
+			assert.Contains(t, err.Error(), expected)
+		}
+	}
+}
+
 func TestManageBuyOfferValidateOfferID(t *testing.T) {
 	kp0 := newKeypair0()
 	kp1 := newKeypair1()
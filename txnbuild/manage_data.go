@@ -45,7 +45,9 @@ func (md *ManageData) FromXDR(xdrOp xdr.Operation) error {
 	md.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
 	md.Name = string(result.DataName)
 	if result.DataValue != nil {
-		md.Value = *result.DataValue
+		// empty data values are decoded as nil slices, which would clear the
+		// data entry instead of setting it to an empty value
+		md.Value = append([]byte{}, *result.DataValue...)
 	} else {
 		md.Value = nil
 	}
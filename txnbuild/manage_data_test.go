@@ -120,6 +120,7 @@ func TestManageDataRoundTrip(t *testing.T) {
 			op := tx.Operations()[0].(*ManageData)
 			assert.Equal(t, manageData.Name, op.Name)
 			assert.Len(t, op.Value, len(manageData.Value))
+			assert.Equal(t, manageData.Value == nil, op.Value == nil)
 			if len(manageData.Value) > 0 {
 				assert.Equal(t, manageData.Value, op.Value)
 			}
@@ -1,6 +1,7 @@
 package txnbuild
 
 import (
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
 
@@ -54,17 +55,19 @@ func operationFromXDR(xdrOp xdr.Operation) (Operation, error) {
 		newOp = &ManageBuyOffer{}
 	case xdr.OperationTypePathPaymentStrictSend:
 		newOp = &PathPaymentStrictSend{}
+	default:
+		return nil, errors.Errorf("unknown operation type: %d", xdrOp.Body.Type)
 	}
 
 	err := newOp.FromXDR(xdrOp)
 	return newOp, err
 }
 
-// accountFromXDR returns a txnbuild Account from a XDR Account.
+// accountFromXDR returns a txnbuild Account from a XDR Account. The account ID
+// of muxed accounts is their M... address.
 func accountFromXDR(account *xdr.MuxedAccount) Account {
 	if account != nil {
-		aid := account.ToAccountId()
-		return &SimpleAccount{AccountID: aid.Address()}
+		return &SimpleAccount{AccountID: account.Address()}
 	}
 	return nil
 }
@@ -12,8 +12,12 @@ import (
 // which functions identically.
 type PathPayment = PathPaymentStrictReceive
 
+// maxPathLength is the maximum number of assets of the path of path payments.
+const maxPathLength = 5
+
 // PathPaymentStrictReceive represents the Stellar path_payment_strict_receive operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
+// The destination can be an account (G...) or a muxed account (M...) address.
 type PathPaymentStrictReceive struct {
 	SendAsset     Asset
 	SendMax       string
@@ -100,8 +104,7 @@ func (pp *PathPaymentStrictReceive) FromXDR(xdrOp xdr.Operation) error {
 	}
 
 	pp.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
-	destAID := result.Destination.ToAccountId()
-	pp.Destination = destAID.Address()
+	pp.Destination = result.Destination.Address()
 	pp.DestAmount = amount.String(result.DestAmount)
 	pp.SendMax = amount.String(result.SendMax)
 
@@ -132,7 +135,7 @@ func (pp *PathPaymentStrictReceive) FromXDR(xdrOp xdr.Operation) error {
 // Validate for PathPaymentStrictReceive validates the required struct fields. It returns an error if any
 // of the fields are invalid. Otherwise, it returns nil.
 func (pp *PathPaymentStrictReceive) Validate() error {
-	_, err := xdr.AddressToAccountId(pp.Destination)
+	_, err := xdr.AddressToMuxedAccount(pp.Destination)
 	if err != nil {
 		return NewValidationError("Destination", err.Error())
 	}
@@ -157,6 +160,10 @@ func (pp *PathPaymentStrictReceive) Validate() error {
 		return NewValidationError("DestAmount", err.Error())
 	}
 
+	if len(pp.Path) > maxPathLength {
+		return NewValidationError("Path", "maximum length is 5 assets")
+	}
+
 	return nil
 }
 
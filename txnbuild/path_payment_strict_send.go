@@ -8,6 +8,7 @@ import (
 
 // PathPaymentStrictSend represents the Stellar path_payment_strict_send operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
+// The destination can be an account (G...) or a muxed account (M...) address.
 type PathPaymentStrictSend struct {
 	SendAsset     Asset
 	SendAmount    string
@@ -94,8 +95,7 @@ func (pp *PathPaymentStrictSend) FromXDR(xdrOp xdr.Operation) error {
 	}
 
 	pp.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
-	destAID := result.Destination.ToAccountId()
-	pp.Destination = destAID.Address()
+	pp.Destination = result.Destination.Address()
 	pp.SendAmount = amount.String(result.SendAmount)
 	pp.DestMin = amount.String(result.DestMin)
 
@@ -126,7 +126,7 @@ func (pp *PathPaymentStrictSend) FromXDR(xdrOp xdr.Operation) error {
 // Validate for PathPaymentStrictSend validates the required struct fields. It returns an error if any
 // of the fields are invalid. Otherwise, it returns nil.
 func (pp *PathPaymentStrictSend) Validate() error {
-	_, err := xdr.AddressToAccountId(pp.Destination)
+	_, err := xdr.AddressToMuxedAccount(pp.Destination)
 	if err != nil {
 		return NewValidationError("Destination", err.Error())
 	}
@@ -151,6 +151,10 @@ func (pp *PathPaymentStrictSend) Validate() error {
 		return NewValidationError("DestMin", err.Error())
 	}
 
+	if len(pp.Path) > maxPathLength {
+		return NewValidationError("Path", "maximum length is 5 assets")
+	}
+
 	return nil
 }
 
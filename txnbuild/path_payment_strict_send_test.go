@@ -156,3 +156,33 @@ func TestPathPaymentStrictSendValidateDestAmount(t *testing.T) {
 		assert.Contains(t, err.Error(), expected)
 	}
 }
+
+func TestPathPaymentStrictSendValidatePath(t *testing.T) {
+	kp0 := newKeypair0()
+	kp2 := newKeypair2()
+	sourceAccount := NewSimpleAccount(kp2.Address(), int64(187316408680450))
+
+	abcdAsset := CreditAsset{"ABCD", kp0.Address()}
+	pathPayment := PathPaymentStrictSend{
+		SendAsset:   NativeAsset{},
+		SendAmount:  "10",
+		Destination: kp2.Address(),
+		DestAsset:   CreditAsset{"ABCD", kp0.Address()},
+		DestMin:     "1",
+		Path:        []Asset{abcdAsset, abcdAsset, abcdAsset, abcdAsset, abcdAsset, abcdAsset},
+	}
+
+	_, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: false,
+			Operations:           []Operation{&pathPayment},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	if assert.Error(t, err) {
+		expected := "validation failed for *txnbuild.PathPaymentStrictSend operation: Field: Path, Error: maximum length is 5 assets"
+		assert.Contains(t, err.Error(), expected)
+	}
+}
@@ -156,3 +156,33 @@ func TestPathPaymentValidateDestAmount(t *testing.T) {
 		assert.Contains(t, err.Error(), expected)
 	}
 }
+
+func TestPathPaymentValidatePath(t *testing.T) {
+	kp0 := newKeypair0()
+	kp2 := newKeypair2()
+	sourceAccount := NewSimpleAccount(kp2.Address(), int64(187316408680450))
+
+	abcdAsset := CreditAsset{"ABCD", kp0.Address()}
+	pathPayment := PathPayment{
+		SendAsset:   NativeAsset{},
+		SendMax:     "10",
+		Destination: kp2.Address(),
+		DestAsset:   CreditAsset{"ABCD", kp0.Address()},
+		DestAmount:  "1",
+		Path:        []Asset{abcdAsset, abcdAsset, abcdAsset, abcdAsset, abcdAsset, abcdAsset},
+	}
+
+	_, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: false,
+			Operations:           []Operation{&pathPayment},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	if assert.Error(t, err) {
+		expected := "validation failed for *txnbuild.PathPaymentStrictReceive operation: Field: Path, Error: maximum length is 5 assets"
+		assert.Contains(t, err.Error(), expected)
+	}
+}
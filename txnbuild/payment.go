@@ -8,6 +8,7 @@ import (
 
 // Payment represents the Stellar payment operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
+// The destination can be an account (G...) or a muxed account (M...) address.
 type Payment struct {
 	Destination   string
 	Amount        string
@@ -60,8 +61,7 @@ func (p *Payment) FromXDR(xdrOp xdr.Operation) error {
 	}
 
 	p.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
-	destAID := result.Destination.ToAccountId()
-	p.Destination = destAID.Address()
+	p.Destination = result.Destination.Address()
 	p.Amount = amount.String(result.Amount)
 
 	asset, err := assetFromXDR(result.Asset)
@@ -76,7 +76,7 @@ func (p *Payment) FromXDR(xdrOp xdr.Operation) error {
 // Validate for Payment validates the required struct fields. It returns an error if any
 // of the fields are invalid. Otherwise, it returns nil.
 func (p *Payment) Validate() error {
-	_, err := xdr.AddressToAccountId(p.Destination)
+	_, err := xdr.AddressToMuxedAccount(p.Destination)
 	if err != nil {
 		return NewValidationError("Destination", err.Error())
 	}
@@ -86,7 +86,7 @@ func (p *Payment) Validate() error {
 		return NewValidationError("Asset", err.Error())
 	}
 
-	err = validateAmount(p.Amount)
+	err = validatePositiveAmount(p.Amount)
 	if err != nil {
 		return NewValidationError("Amount", err.Error())
 	}
@@ -56,6 +56,31 @@ func TestPaymentValidateAmount(t *testing.T) {
 	}
 }
 
+func TestPaymentValidateZeroAmount(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639898))
+
+	payment := Payment{
+		Destination: "GB7BDSZU2Y27LYNLALKKALB52WS2IZWYBDGY6EQBLEED3TJOCVMZRH7H",
+		Amount:      "0",
+		Asset:       NativeAsset{},
+	}
+
+	_, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: false,
+			Operations:           []Operation{&payment},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	if assert.Error(t, err) {
+		expected := "validation failed for *txnbuild.Payment operation: Field: Amount, Error: amount must be greater than zero"
+		assert.Contains(t, err.Error(), expected)
+	}
+}
+
 func TestPaymentValidateAsset(t *testing.T) {
 	kp0 := newKeypair0()
 	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639898))
@@ -1,6 +1,10 @@
 package txnbuild
 
 import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -20,6 +24,9 @@ const AuthRevocable = AccountFlag(xdr.AccountFlagsAuthRevocableFlag)
 // set, and prevents the account from ever being merged (deleted).
 const AuthImmutable = AccountFlag(xdr.AccountFlagsAuthImmutableFlag)
 
+// allAccountFlags is the mask of the account flags known to the protocol.
+const allAccountFlags = AuthRequired | AuthRevocable | AuthImmutable
+
 // Threshold is the datatype for MasterWeight, Signer.Weight, and Thresholds. Each is a number
 // between 0-255 inclusive.
 type Threshold uint8
@@ -47,6 +54,40 @@ func NewInflationDestination(ai string) *string {
 	return &ai
 }
 
+// NewHashXSigner returns a signer of type hashX whose signature is `preimage`. Transactions
+// are signed by this signer with SignHashX(preimage). See
+// https://www.stellar.org/developers/guides/concepts/multi-sig.html#hashx.
+func NewHashXSigner(preimage []byte, weight Threshold) (*Signer, error) {
+	if maxSize := xdr.Signature(preimage).XDRMaxSize(); len(preimage) > maxSize {
+		return nil, errors.Errorf("preimage cannot be more than %d bytes", maxSize)
+	}
+
+	preimageHash := sha256.Sum256(preimage)
+	address, err := strkey.Encode(strkey.VersionByteHashX, preimageHash[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode hashX signer")
+	}
+	return &Signer{Address: address, Weight: weight}, nil
+}
+
+// NewPreAuthTxSigner returns a signer of type preAuthTx which authorizes the transaction tx,
+// to be submitted later to the network with the passphrase `network`. tx must be built
+// with the sequence number it will have when it is submitted, and it does not need to be
+// signed. The signer is removed from the account once tx is applied. See
+// https://www.stellar.org/developers/guides/concepts/multi-sig.html#pre-authorized-transaction.
+func NewPreAuthTxSigner(tx *Transaction, network string, weight Threshold) (*Signer, error) {
+	txHash, err := tx.Hash(network)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash transaction")
+	}
+
+	address, err := strkey.Encode(strkey.VersionByteHashTx, txHash[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode preAuthTx signer")
+	}
+	return &Signer{Address: address, Weight: weight}, nil
+}
+
 // SetOptions represents the Stellar set options operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type SetOptions struct {
@@ -63,6 +104,60 @@ type SetOptions struct {
 	SourceAccount        Account
 }
 
+// AddSigner sets the signer of the operation, which adds `address` to the signers of the account
+// with the weight `weight`, or updates its weight if it is already a signer of the account. A
+// SetOptions operation changes a single signer, so AddSigner and RemoveSigner replace the signer
+// set before. It returns the operation, so that calls can be chained.
+func (so *SetOptions) AddSigner(address string, weight Threshold) *SetOptions {
+	so.Signer = &Signer{Address: address, Weight: weight}
+	return so
+}
+
+// RemoveSigner sets the signer of the operation, which removes `address` from the signers of the
+// account. A SetOptions operation changes a single signer, so AddSigner and RemoveSigner replace
+// the signer set before. It returns the operation, so that calls can be chained.
+func (so *SetOptions) RemoveSigner(address string) *SetOptions {
+	so.Signer = &Signer{Address: address, Weight: 0}
+	return so
+}
+
+// SetMasterWeight sets the weight of the master key of the account. A weight of 0 disables the
+// master key. It returns the operation, so that calls can be chained.
+func (so *SetOptions) SetMasterWeight(weight Threshold) *SetOptions {
+	so.MasterWeight = NewThreshold(weight)
+	return so
+}
+
+// SetThresholds sets the low, medium and high thresholds of the account. It returns the
+// operation, so that calls can be chained.
+func (so *SetOptions) SetThresholds(low, medium, high Threshold) *SetOptions {
+	so.LowThreshold = NewThreshold(low)
+	so.MediumThreshold = NewThreshold(medium)
+	so.HighThreshold = NewThreshold(high)
+	return so
+}
+
+// SetHomeDomain sets the home domain of the account. It returns the operation, so that calls can
+// be chained.
+func (so *SetOptions) SetHomeDomain(homeDomain string) *SetOptions {
+	so.HomeDomain = NewHomeDomain(homeDomain)
+	return so
+}
+
+// EnableFlags adds `flags`, e.g. AuthRequired, to the flags set on the account. It returns the
+// operation, so that calls can be chained.
+func (so *SetOptions) EnableFlags(flags ...AccountFlag) *SetOptions {
+	so.SetFlags = append(so.SetFlags, flags...)
+	return so
+}
+
+// DisableFlags adds `flags`, e.g. AuthRevocable, to the flags cleared on the account. It returns
+// the operation, so that calls can be chained.
+func (so *SetOptions) DisableFlags(flags ...AccountFlag) *SetOptions {
+	so.ClearFlags = append(so.ClearFlags, flags...)
+	return so
+}
+
 // BuildXDR for SetOptions returns a fully configured XDR Operation.
 func (so *SetOptions) BuildXDR() (xdr.Operation, error) {
 	err := so.handleInflation()
@@ -313,8 +408,29 @@ func (so *SetOptions) FromXDR(xdrOp xdr.Operation) error {
 // Validate for SetOptions validates the required struct fields. It returns an error if any
 // of the fields are invalid. Otherwise, it returns nil.
 func (so *SetOptions) Validate() error {
-	// skipping checks here because the individual methods above already check for required fields.
-	// Refactoring is out of the scope of this issue(https://github.com/stellar/go/issues/1041) so will leave as is for now.
+	// the other fields are checked when building the XDR operation
+	for _, flag := range so.SetFlags {
+		if flag&^allAccountFlags != 0 {
+			return NewValidationError("SetFlags", fmt.Sprintf("unknown flag %d", flag))
+		}
+		for _, clearFlag := range so.ClearFlags {
+			if flag&clearFlag != 0 {
+				return NewValidationError("SetFlags", fmt.Sprintf("flag %d cannot be both set and cleared", flag&clearFlag))
+			}
+		}
+	}
+	for _, flag := range so.ClearFlags {
+		if flag&^allAccountFlags != 0 {
+			return NewValidationError("ClearFlags", fmt.Sprintf("unknown flag %d", flag))
+		}
+	}
+
+	if so.Signer != nil {
+		var signerKey xdr.SignerKey
+		if err := signerKey.SetAddress(so.Signer.Address); err != nil {
+			return NewValidationError("Signer", err.Error())
+		}
+	}
 	return nil
 }
 
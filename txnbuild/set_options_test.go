@@ -1,8 +1,12 @@
 package txnbuild
 
 import (
+	"crypto/sha256"
+	"strings"
 	"testing"
 
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 )
@@ -136,3 +140,100 @@ func TestEmptyHomeDomainOK(t *testing.T) {
 	assert.Equal(t, string(*options.xdrOp.HomeDomain), "", "empty string home domain is set")
 
 }
+
+func TestNewHashXSigner(t *testing.T) {
+	preimage := []byte("this is a preimage for hashx transactions on the stellar network")
+	preimageHash := sha256.Sum256(preimage)
+	expected, err := strkey.Encode(strkey.VersionByteHashX, preimageHash[:])
+	assert.NoError(t, err)
+
+	signer, err := NewHashXSigner(preimage, Threshold(1))
+	assert.NoError(t, err)
+	assert.Equal(t, &Signer{Address: expected, Weight: Threshold(1)}, signer)
+
+	_, err = NewHashXSigner([]byte(strings.Repeat("a", 65)), Threshold(1))
+	assert.EqualError(t, err, "preimage cannot be more than 64 bytes")
+}
+
+func TestNewPreAuthTxSigner(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(4353383146192898))
+
+	txFuture, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&Inflation{}},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	txFutureHash, err := txFuture.Hash(network.TestNetworkPassphrase)
+	assert.NoError(t, err)
+	expected, err := strkey.Encode(strkey.VersionByteHashTx, txFutureHash[:])
+	assert.NoError(t, err)
+
+	signer, err := NewPreAuthTxSigner(txFuture, network.TestNetworkPassphrase, Threshold(2))
+	assert.NoError(t, err)
+	assert.Equal(t, &Signer{Address: expected, Weight: Threshold(2)}, signer)
+
+	// the signer round trips through the XDR of a set options operation
+	setOptions := SetOptions{Signer: signer}
+	xdrOp, err := setOptions.BuildXDR()
+	assert.NoError(t, err)
+	assert.Equal(t, xdr.SignerKeyTypeSignerKeyTypePreAuthTx, xdrOp.Body.SetOptionsOp.Signer.Key.Type)
+	var parsed SetOptions
+	assert.NoError(t, parsed.FromXDR(xdrOp))
+	assert.Equal(t, signer, parsed.Signer)
+}
+
+func TestSetOptionsHelpers(t *testing.T) {
+	kp1 := newKeypair1()
+
+	op := (&SetOptions{}).
+		SetMasterWeight(0).
+		SetThresholds(1, 2, 3).
+		SetHomeDomain("stellar.org").
+		EnableFlags(AuthRequired, AuthRevocable).
+		DisableFlags(AuthImmutable).
+		AddSigner(kp1.Address(), 2)
+	assert.Equal(t, SetOptions{
+		MasterWeight:    NewThreshold(0),
+		LowThreshold:    NewThreshold(1),
+		MediumThreshold: NewThreshold(2),
+		HighThreshold:   NewThreshold(3),
+		HomeDomain:      NewHomeDomain("stellar.org"),
+		SetFlags:        []AccountFlag{AuthRequired, AuthRevocable},
+		ClearFlags:      []AccountFlag{AuthImmutable},
+		Signer:          &Signer{Address: kp1.Address(), Weight: 2},
+	}, *op)
+	assert.NoError(t, op.Validate())
+
+	op.RemoveSigner(kp1.Address())
+	assert.Equal(t, &Signer{Address: kp1.Address(), Weight: 0}, op.Signer)
+	assert.NoError(t, op.Validate())
+}
+
+func TestSetOptionsValidate(t *testing.T) {
+	op := (&SetOptions{}).EnableFlags(AuthRequired, AuthRevocable).DisableFlags(AuthRevocable)
+	assert.EqualError(t, op.Validate(), "Field: SetFlags, Error: flag 2 cannot be both set and cleared")
+
+	op = (&SetOptions{}).EnableFlags(AccountFlag(8))
+	assert.EqualError(t, op.Validate(), "Field: SetFlags, Error: unknown flag 8")
+
+	op = (&SetOptions{}).DisableFlags(AuthRequired | AccountFlag(16))
+	assert.EqualError(t, op.Validate(), "Field: ClearFlags, Error: unknown flag 17")
+
+	op = (&SetOptions{}).AddSigner("GABC", 1)
+	assert.Error(t, op.Validate())
+	_, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &SimpleAccount{AccountID: newKeypair0().Address()},
+			Operations:    []Operation{op},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.Contains(t, err.Error(), "validation failed for *txnbuild.SetOptions operation: Field: Signer")
+}
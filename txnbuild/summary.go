@@ -0,0 +1,320 @@
+package txnbuild
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionSummary is a human-readable summary of a transaction, to display
+// it to users before they sign it. Its String method renders it as text.
+type TransactionSummary struct {
+	// SourceAccount is the account of the transaction, whose sequence number
+	// is consumed.
+	SourceAccount string
+	Sequence      int64
+	// FeeAccount is the account paying the fee of fee bump transactions. It is
+	// empty for other transactions.
+	FeeAccount string
+	// MaxFee is the maximum fee of the transaction, in stroops.
+	MaxFee     int64
+	Memo       string
+	Timebounds Timebounds
+	Operations []OperationSummary
+	// Signers are the accounts whose signature is required by the
+	// transaction: its source account, the source accounts of its operations
+	// and the fee account of fee bump transactions.
+	Signers []string
+	// Signatures is the number of signatures already attached.
+	Signatures int
+}
+
+// OperationSummary is a human-readable summary of an operation.
+type OperationSummary struct {
+	// Type is the name of the type of the operation, e.g. "payment".
+	Type string
+	// SourceAccount is the source account of the operation. It is empty when
+	// the source account of the transaction is used.
+	SourceAccount string
+	// Description describes the effect of the operation, e.g.
+	// "pay 10.0000000 XLM to GB7B...".
+	Description string
+}
+
+// SummarizeTransaction returns the summary of tx, built locally or decoded with
+// TransactionFromXDR.
+func SummarizeTransaction(tx *Transaction) TransactionSummary {
+	summary := TransactionSummary{
+		SourceAccount: tx.sourceAccount.AccountID,
+		Sequence:      tx.envelope.SeqNum(),
+		MaxFee:        tx.maxFee,
+		Memo:          summarizeMemo(tx.memo),
+		Timebounds:    tx.timebounds,
+		Signatures:    len(tx.signatures),
+	}
+	summary.Signers = appendSigner(summary.Signers, tx.sourceAccount.AccountID)
+	for _, op := range tx.operations {
+		opSummary := summarizeOperation(op)
+		summary.Operations = append(summary.Operations, opSummary)
+		if opSummary.SourceAccount != "" {
+			summary.Signers = appendSigner(summary.Signers, opSummary.SourceAccount)
+		}
+	}
+	return summary
+}
+
+// SummarizeFeeBumpTransaction returns the summary of the fee bump transaction
+// tx, built locally or decoded with TransactionFromXDR. The operations and
+// signers are the ones of the inner transaction, along with the fee account.
+func SummarizeFeeBumpTransaction(tx *FeeBumpTransaction) TransactionSummary {
+	summary := SummarizeTransaction(tx.inner)
+	summary.FeeAccount = tx.feeAccount
+	summary.MaxFee = tx.maxFee
+	summary.Signers = appendSigner(summary.Signers, tx.feeAccount)
+	summary.Signatures = len(tx.signatures)
+	return summary
+}
+
+// String renders the summary as text, one field per line.
+func (s TransactionSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Source account: %s\n", s.SourceAccount)
+	fmt.Fprintf(&b, "Sequence number: %d\n", s.Sequence)
+	if s.FeeAccount != "" {
+		fmt.Fprintf(&b, "Fee account: %s\n", s.FeeAccount)
+	}
+	fmt.Fprintf(&b, "Max fee: %s XLM\n", amount.StringFromInt64(s.MaxFee))
+	if s.Memo != "" {
+		fmt.Fprintf(&b, "Memo: %s\n", s.Memo)
+	}
+	fmt.Fprintf(&b, "Valid: %s\n", summarizeTimebounds(s.Timebounds))
+	fmt.Fprintf(&b, "Operations:\n")
+	for i, op := range s.Operations {
+		if op.SourceAccount != "" {
+			fmt.Fprintf(&b, "  %d. %s: %s (source account %s)\n", i+1, op.Type, op.Description, op.SourceAccount)
+		} else {
+			fmt.Fprintf(&b, "  %d. %s: %s\n", i+1, op.Type, op.Description)
+		}
+	}
+	fmt.Fprintf(&b, "Signers required: %s\n", strings.Join(s.Signers, ", "))
+	fmt.Fprintf(&b, "Signatures: %d\n", s.Signatures)
+	return b.String()
+}
+
+// appendSigner appends the account signing for `address` to signers, unless
+// it is already present. Muxed accounts are signed for by their underlying
+// account.
+func appendSigner(signers []string, address string) []string {
+	if muxedAccount, err := xdr.AddressToMuxedAccount(address); err == nil {
+		accountID := muxedAccount.ToAccountId()
+		address = accountID.Address()
+	}
+	for _, signer := range signers {
+		if signer == address {
+			return signers
+		}
+	}
+	return append(signers, address)
+}
+
+func summarizeMemo(memo Memo) string {
+	switch memo := memo.(type) {
+	case MemoText:
+		return fmt.Sprintf("text %q", string(memo))
+	case MemoID:
+		return fmt.Sprintf("id %d", uint64(memo))
+	case MemoHash:
+		return "hash " + hex.EncodeToString(memo[:])
+	case MemoReturn:
+		return "return " + hex.EncodeToString(memo[:])
+	default:
+		return ""
+	}
+}
+
+func summarizeTimebounds(tb Timebounds) string {
+	from := "any time"
+	if tb.MinTime > 0 {
+		from = time.Unix(tb.MinTime, 0).UTC().Format(time.RFC3339)
+	}
+	if tb.MaxTime == TimeoutInfinite {
+		return "from " + from + ", without expiration"
+	}
+	return "from " + from + " until " + time.Unix(tb.MaxTime, 0).UTC().Format(time.RFC3339)
+}
+
+func summarizeAsset(asset Asset) string {
+	if asset == nil {
+		return "unknown asset"
+	}
+	if asset.IsNative() {
+		return "XLM"
+	}
+	if asset.GetIssuer() == "" {
+		return asset.GetCode()
+	}
+	return asset.GetCode() + ":" + asset.GetIssuer()
+}
+
+func summarizePath(path []Asset) string {
+	if len(path) == 0 {
+		return ""
+	}
+	assets := make([]string, len(path))
+	for i, asset := range path {
+		assets[i] = summarizeAsset(asset)
+	}
+	return " through " + strings.Join(assets, ", ")
+}
+
+func summarizeFlags(flags []AccountFlag) string {
+	names := make([]string, len(flags))
+	for i, flag := range flags {
+		switch flag {
+		case AuthRequired:
+			names[i] = "auth required"
+		case AuthRevocable:
+			names[i] = "auth revocable"
+		case AuthImmutable:
+			names[i] = "auth immutable"
+		default:
+			names[i] = fmt.Sprintf("flag %d", flag)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// summarizeOperation returns the summary of op. Operations of unknown types
+// are described by their Go type.
+func summarizeOperation(op Operation) OperationSummary {
+	var summary OperationSummary
+	if source := op.GetSourceAccount(); source != nil {
+		summary.SourceAccount = source.GetAccountID()
+	}
+
+	switch op := op.(type) {
+	case *CreateAccount:
+		summary.Type = "create_account"
+		summary.Description = fmt.Sprintf("create account %s with a starting balance of %s XLM", op.Destination, op.Amount)
+	case *Payment:
+		summary.Type = "payment"
+		summary.Description = fmt.Sprintf("pay %s %s to %s", op.Amount, summarizeAsset(op.Asset), op.Destination)
+	case *PathPaymentStrictReceive:
+		summary.Type = "path_payment_strict_receive"
+		summary.Description = fmt.Sprintf(
+			"pay %s %s to %s, sending at most %s %s%s",
+			op.DestAmount, summarizeAsset(op.DestAsset), op.Destination,
+			op.SendMax, summarizeAsset(op.SendAsset), summarizePath(op.Path),
+		)
+	case *PathPaymentStrictSend:
+		summary.Type = "path_payment_strict_send"
+		summary.Description = fmt.Sprintf(
+			"send %s %s to %s, who receives at least %s %s%s",
+			op.SendAmount, summarizeAsset(op.SendAsset), op.Destination,
+			op.DestMin, summarizeAsset(op.DestAsset), summarizePath(op.Path),
+		)
+	case *ManageSellOffer:
+		summary.Type = "manage_sell_offer"
+		summary.Description = summarizeOffer("sell", op.OfferID, op.Amount, op.Selling, op.Buying, op.Price)
+	case *ManageBuyOffer:
+		summary.Type = "manage_buy_offer"
+		summary.Description = summarizeOffer("buy", op.OfferID, op.Amount, op.Buying, op.Selling, op.Price)
+	case *CreatePassiveSellOffer:
+		summary.Type = "create_passive_sell_offer"
+		summary.Description = "passive " + summarizeOffer("sell", 0, op.Amount, op.Selling, op.Buying, op.Price)
+	case *SetOptions:
+		summary.Type = "set_options"
+		summary.Description = summarizeSetOptions(op)
+	case *ChangeTrust:
+		summary.Type = "change_trust"
+		if op.Limit == "0" || op.Limit == "0.0000000" {
+			summary.Description = fmt.Sprintf("remove trustline to %s", summarizeAsset(op.Line))
+		} else {
+			summary.Description = fmt.Sprintf("trust %s up to %s", summarizeAsset(op.Line), op.Limit)
+		}
+	case *AllowTrust:
+		summary.Type = "allow_trust"
+		switch {
+		case op.Authorize:
+			summary.Description = fmt.Sprintf("authorize %s to hold %s", op.Trustor, summarizeAsset(op.Type))
+		case op.AuthorizeToMaintainLiabilities:
+			summary.Description = fmt.Sprintf("authorize %s to maintain liabilities in %s", op.Trustor, summarizeAsset(op.Type))
+		default:
+			summary.Description = fmt.Sprintf("revoke the authorization of %s to hold %s", op.Trustor, summarizeAsset(op.Type))
+		}
+	case *AccountMerge:
+		summary.Type = "account_merge"
+		summary.Description = fmt.Sprintf("merge the account into %s", op.Destination)
+	case *Inflation:
+		summary.Type = "inflation"
+		summary.Description = "run inflation"
+	case *ManageData:
+		summary.Type = "manage_data"
+		if op.Value == nil {
+			summary.Description = fmt.Sprintf("delete data entry %q", op.Name)
+		} else {
+			summary.Description = fmt.Sprintf("set data entry %q to %q", op.Name, string(op.Value))
+		}
+	case *BumpSequence:
+		summary.Type = "bump_sequence"
+		summary.Description = fmt.Sprintf("bump sequence number to %d", op.BumpTo)
+	default:
+		summary.Type = fmt.Sprintf("%T", op)
+		summary.Description = "unknown operation"
+	}
+	return summary
+}
+
+func summarizeOffer(side string, offerID int64, offerAmount string, asset, counterAsset Asset, price string) string {
+	switch {
+	case offerID == 0:
+		return fmt.Sprintf("offer to %s %s %s for %s at price %s", side, offerAmount, summarizeAsset(asset), summarizeAsset(counterAsset), price)
+	case offerAmount == "0" || offerAmount == "0.0000000":
+		return fmt.Sprintf("delete offer %d", offerID)
+	default:
+		return fmt.Sprintf("update offer %d to %s %s %s for %s at price %s", offerID, side, offerAmount, summarizeAsset(asset), summarizeAsset(counterAsset), price)
+	}
+}
+
+func summarizeSetOptions(op *SetOptions) string {
+	var changes []string
+	if op.InflationDestination != nil {
+		changes = append(changes, "set inflation destination to "+*op.InflationDestination)
+	}
+	if len(op.SetFlags) > 0 {
+		changes = append(changes, "set flags "+summarizeFlags(op.SetFlags))
+	}
+	if len(op.ClearFlags) > 0 {
+		changes = append(changes, "clear flags "+summarizeFlags(op.ClearFlags))
+	}
+	if op.MasterWeight != nil {
+		changes = append(changes, fmt.Sprintf("set master key weight to %d", *op.MasterWeight))
+	}
+	if op.LowThreshold != nil {
+		changes = append(changes, fmt.Sprintf("set low threshold to %d", *op.LowThreshold))
+	}
+	if op.MediumThreshold != nil {
+		changes = append(changes, fmt.Sprintf("set medium threshold to %d", *op.MediumThreshold))
+	}
+	if op.HighThreshold != nil {
+		changes = append(changes, fmt.Sprintf("set high threshold to %d", *op.HighThreshold))
+	}
+	if op.HomeDomain != nil {
+		changes = append(changes, fmt.Sprintf("set home domain to %q", *op.HomeDomain))
+	}
+	if op.Signer != nil {
+		if op.Signer.Weight == 0 {
+			changes = append(changes, "remove signer "+op.Signer.Address)
+		} else {
+			changes = append(changes, fmt.Sprintf("set signer %s with weight %d", op.Signer.Address, op.Signer.Weight))
+		}
+	}
+	if len(changes) == 0 {
+		return "no change"
+	}
+	return strings.Join(changes, ", ")
+}
@@ -0,0 +1,93 @@
+package txnbuild
+
+import (
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeTransaction(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	muxedAccount := "MAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH4AAAAAAAAAAWFYJAM"
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations: []Operation{
+				&Payment{Destination: kp1.Address(), Amount: "10", Asset: NativeAsset{}},
+				&ChangeTrust{
+					Line:          CreditAsset{"ABCD", kp1.Address()},
+					Limit:         "1000",
+					SourceAccount: &SimpleAccount{AccountID: muxedAccount},
+				},
+			},
+			BaseFee:    MinBaseFee,
+			Memo:       MemoText("invoice 42"),
+			Timebounds: NewTimebounds(1577836800, 1577840400),
+		},
+	)
+	assert.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.NoError(t, err)
+
+	summary := SummarizeTransaction(tx)
+	assert.Equal(t, kp0.Address(), summary.SourceAccount)
+	assert.Equal(t, int64(9605939170639898), summary.Sequence)
+	assert.Equal(t, int64(200), summary.MaxFee)
+	assert.Equal(t, []OperationSummary{
+		{
+			Type:        "payment",
+			Description: "pay 10 XLM to " + kp1.Address(),
+		},
+		{
+			Type:          "change_trust",
+			SourceAccount: muxedAccount,
+			Description:   "trust ABCD:" + kp1.Address() + " up to 1000",
+		},
+	}, summary.Operations)
+	assert.Equal(t, []string{kp0.Address(), kp1.Address()}, summary.Signers)
+	assert.Equal(t, 1, summary.Signatures)
+
+	expected := "Source account: " + kp0.Address() + "\n" +
+		"Sequence number: 9605939170639898\n" +
+		"Max fee: 0.0000200 XLM\n" +
+		"Memo: text \"invoice 42\"\n" +
+		"Valid: from 2020-01-01T00:00:00Z until 2020-01-01T01:00:00Z\n" +
+		"Operations:\n" +
+		"  1. payment: pay 10 XLM to " + kp1.Address() + "\n" +
+		"  2. change_trust: trust ABCD:" + kp1.Address() + " up to 1000 (source account " + muxedAccount + ")\n" +
+		"Signers required: " + kp0.Address() + ", " + kp1.Address() + "\n" +
+		"Signatures: 1\n"
+	assert.Equal(t, expected, summary.String())
+
+	// transactions decoded from XDR are summarized with their decoded amounts
+	txeB64, err := tx.Base64()
+	assert.NoError(t, err)
+	parsed, err := TransactionFromXDR(txeB64)
+	assert.NoError(t, err)
+	parsedTx, ok := parsed.Transaction()
+	assert.True(t, ok)
+	parsedSummary := SummarizeTransaction(parsedTx)
+	assert.Equal(t, summary.Signers, parsedSummary.Signers)
+	assert.Equal(t, "pay 10.0000000 XLM to "+kp1.Address(), parsedSummary.Operations[0].Description)
+
+	convertToV1Tx(tx)
+	feeBump, err := NewFeeBumpTransaction(
+		FeeBumpTransactionParams{
+			Inner:      tx,
+			FeeAccount: kp1.Address(),
+			BaseFee:    MinBaseFee,
+		},
+	)
+	assert.NoError(t, err)
+	feeBumpSummary := SummarizeFeeBumpTransaction(feeBump)
+	assert.Equal(t, kp1.Address(), feeBumpSummary.FeeAccount)
+	assert.Equal(t, int64(300), feeBumpSummary.MaxFee)
+	assert.Equal(t, summary.Operations, feeBumpSummary.Operations)
+	assert.Equal(t, []string{kp0.Address(), kp1.Address()}, feeBumpSummary.Signers)
+	assert.Equal(t, 0, feeBumpSummary.Signatures)
+}
@@ -62,6 +62,23 @@ func NewTimeout(timeout int64) Timebounds {
 	return Timebounds{0, time.Now().UTC().Unix() + timeout, true}
 }
 
+// NewTimeoutWithSkew is like NewTimeout, but the MaxTime is extended by 'maxSkew' seconds, so that the
+// transaction does not fail with tx_too_late when the system clock is behind the clock of the network by up to
+// 'maxSkew' seconds.
+// A Transaction cannot be built unless a Timebounds object is provided through a factory method.
+func NewTimeoutWithSkew(timeout, maxSkew int64) Timebounds {
+	return Timebounds{0, time.Now().UTC().Unix() + timeout + maxSkew, true}
+}
+
+// ValidFromLedgerCloseTime is a factory method that sets the MaxTime to be the duration in seconds specified by
+// 'timeout' after 'closeTime', the close time of the latest ledger of the network, instead of the system time
+// which may be inaccurate. The close time of the latest ledger is returned by horizonclient's
+// Client.LatestLedgerCloseTime.
+// A Transaction cannot be built unless a Timebounds object is provided through a factory method.
+func ValidFromLedgerCloseTime(closeTime time.Time, timeout int64) Timebounds {
+	return Timebounds{0, closeTime.UTC().Unix() + timeout, true}
+}
+
 // NewInfiniteTimeout is a factory method that sets the MaxTime to a value representing an indefinite
 // upper time bound. This is rarely needed, but is helpful for certain smart contracts, and for
 // deterministic testing. A Transaction cannot be built unless a Timebounds object is provided through
@@ -2,6 +2,7 @@ package txnbuild
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,3 +50,22 @@ func TestSetTimeout(t *testing.T) {
 		assert.NotNil(t, tb.MaxTime)
 	}
 }
+
+func TestSetTimeoutWithSkew(t *testing.T) {
+	before := time.Now().UTC().Unix()
+	tb := NewTimeoutWithSkew(300, 60)
+	after := time.Now().UTC().Unix()
+	if assert.NoError(t, tb.Validate()) {
+		assert.Equal(t, int64(0), tb.MinTime)
+		assert.True(t, tb.MaxTime >= before+360 && tb.MaxTime <= after+360)
+	}
+}
+
+func TestValidFromLedgerCloseTime(t *testing.T) {
+	closeTime := time.Date(2019, 5, 16, 7, 48, 28, 0, time.UTC)
+	tb := ValidFromLedgerCloseTime(closeTime, 300)
+	if assert.NoError(t, tb.Validate()) {
+		assert.Equal(t, int64(0), tb.MinTime)
+		assert.Equal(t, closeTime.Unix()+300, tb.MaxTime)
+	}
+}
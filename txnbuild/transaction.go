@@ -31,6 +31,12 @@ import (
 // MinBaseFee is the minimum transaction fee for the Stellar network.
 const MinBaseFee = 100
 
+// MaxOperations is the maximum number of operations of a transaction.
+const MaxOperations = xdr.MaxOpsPerTx
+
+// MaxSignatures is the maximum number of signatures of a transaction.
+const MaxSignatures = 20
+
 // Account represents the aspects of a Stellar account necessary to construct transactions. See
 // https://www.stellar.org/developers/guides/concepts/accounts.html
 type Account interface {
@@ -51,8 +57,15 @@ func concatSignatures(
 	e xdr.TransactionEnvelope,
 	networkStr string,
 	signatures []xdr.DecoratedSignature,
-	kps ...*keypair.Full,
+	signers ...TransactionSigner,
 ) ([]xdr.DecoratedSignature, error) {
+	// Refuse to sign with an unregistered passphrase, it may be mistyped and
+	// the signatures would not be valid on the network the transaction is
+	// intended for
+	if err := network.ValidatePassphrase(networkStr); err != nil {
+		return nil, errors.Wrap(err, "invalid network passphrase")
+	}
+
 	// Hash the transaction
 	h, err := network.HashTransactionInEnvelope(e, networkStr)
 	if err != nil {
@@ -62,20 +75,34 @@ func concatSignatures(
 	extended := make(
 		[]xdr.DecoratedSignature,
 		len(signatures),
-		len(signatures)+len(kps),
+		len(signatures)+len(signers),
 	)
 	copy(extended, signatures)
 	// Sign the hash
-	for _, kp := range kps {
-		sig, err := kp.SignDecorated(h[:])
+	for _, signer := range signers {
+		sig, err := signer.SignDecorated(h[:])
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to sign transaction")
 		}
+		if hint := signer.Hint(); sig.Hint != xdr.SignatureHint(hint) {
+			return nil, errors.Errorf("signature hint %x does not match signer hint %x", sig.Hint, hint)
+		}
 		extended = append(extended, sig)
 	}
+	if err := checkSignaturesLimit(extended); err != nil {
+		return nil, err
+	}
 	return extended, nil
 }
 
+func keypairSigners(kps []*keypair.Full) []TransactionSigner {
+	signers := make([]TransactionSigner, len(kps))
+	for i, kp := range kps {
+		signers[i] = kp
+	}
+	return signers
+}
+
 func concatSignatureBase64(e xdr.TransactionEnvelope, signatures []xdr.DecoratedSignature, networkStr, publicKey, signature string) ([]xdr.DecoratedSignature, error) {
 	if signature == "" {
 		return nil, errors.New("signature not presented")
@@ -108,6 +135,9 @@ func concatSignatureBase64(e xdr.TransactionEnvelope, signatures []xdr.Decorated
 		Signature: xdr.Signature(sigBytes),
 	})
 
+	if err := checkSignaturesLimit(extended); err != nil {
+		return nil, err
+	}
 	return extended, nil
 }
 
@@ -131,7 +161,7 @@ func stringsToKP(keys ...string) ([]*keypair.Full, error) {
 func concatHashX(signatures []xdr.DecoratedSignature, preimage []byte) ([]xdr.DecoratedSignature, error) {
 	if maxSize := xdr.Signature(preimage).XDRMaxSize(); len(preimage) > maxSize {
 		return nil, errors.Errorf(
-			"preimage cannnot be more than %d bytes", maxSize,
+			"preimage cannot be more than %d bytes", maxSize,
 		)
 	}
 	extended := make(
@@ -150,7 +180,76 @@ func concatHashX(signatures []xdr.DecoratedSignature, preimage []byte) ([]xdr.De
 		Hint:      xdr.SignatureHint(hint),
 		Signature: xdr.Signature(preimage),
 	}
-	return append(extended, sig), nil
+	extended = append(extended, sig)
+	if err := checkSignaturesLimit(extended); err != nil {
+		return nil, err
+	}
+	return extended, nil
+}
+
+func concatDecoratedSignatures(
+	signatures []xdr.DecoratedSignature,
+	skipDuplicates bool,
+	decorated ...xdr.DecoratedSignature,
+) ([]xdr.DecoratedSignature, error) {
+	extended := make(
+		[]xdr.DecoratedSignature,
+		len(signatures),
+		len(signatures)+len(decorated),
+	)
+	copy(extended, signatures)
+
+	for _, sig := range decorated {
+		duplicate := false
+		for _, existing := range extended {
+			if existing.Hint != sig.Hint {
+				continue
+			}
+			// a key produces a single signature for a given transaction, so
+			// two different signatures with the same hint cannot both be valid
+			if !bytes.Equal(existing.Signature, sig.Signature) {
+				return nil, errors.Errorf("conflicting signatures with hint %x", sig.Hint)
+			}
+			duplicate = true
+			break
+		}
+		if duplicate {
+			if skipDuplicates {
+				continue
+			}
+			return nil, errors.Errorf("duplicate signature with hint %x", sig.Hint)
+		}
+		extended = append(extended, sig)
+	}
+	if err := checkSignaturesLimit(extended); err != nil {
+		return nil, err
+	}
+	return extended, nil
+}
+
+// checkSignaturesLimit returns an error when a transaction with the signatures
+// `signatures` would have more signatures than allowed by the protocol.
+func checkSignaturesLimit(signatures []xdr.DecoratedSignature) error {
+	if len(signatures) > MaxSignatures {
+		return errors.Errorf(
+			"transaction has %d signatures, more than the maximum of %d", len(signatures), MaxSignatures,
+		)
+	}
+	return nil
+}
+
+// sameTransaction returns true if the envelopes `a` and `b` contain the same
+// transaction, regardless of their signatures.
+func sameTransaction(a, b xdr.TransactionEnvelope) (bool, error) {
+	aBytes, err := marshallBinary(a, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get XDR bytestring")
+	}
+	bBytes, err := marshallBinary(b, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get XDR bytestring")
+	}
+	return bytes.Equal(aBytes, bBytes), nil
 }
 
 func marshallBinary(e xdr.TransactionEnvelope, signatures []xdr.DecoratedSignature) ([]byte, error) {
@@ -262,8 +361,17 @@ func (t *Transaction) HashHex(network string) (string, error) {
 
 // Sign returns a new Transaction instance which extends the current instance
 // with additional signatures derived from the given list of keypair instances.
+// Signing fails when the network passphrase is empty or is not the passphrase
+// of a registered network, see network.ValidatePassphrase.
 func (t *Transaction) Sign(network string, kps ...*keypair.Full) (*Transaction, error) {
-	extendedSignatures, err := concatSignatures(t.envelope, network, t.signatures, kps...)
+	return t.SignWith(network, keypairSigners(kps)...)
+}
+
+// SignWith returns a new Transaction instance which extends the current instance
+// with additional signatures produced by the given signers, e.g. hardware wallets or
+// KMS keys which do not expose their seed.
+func (t *Transaction) SignWith(network string, signers ...TransactionSigner) (*Transaction, error) {
+	extendedSignatures, err := concatSignatures(t.envelope, network, t.signatures, signers...)
 	if err != nil {
 		return nil, err
 	}
@@ -313,6 +421,89 @@ func (t *Transaction) AddSignatureBase64(network, publicKey, signature string) (
 	return newTx, nil
 }
 
+// AddSignatureDecorated returns a new Transaction instance which extends the current instance
+// with the given decorated signatures, e.g. detached signatures collected from signers
+// which do not share the same process. Signatures which are already attached to the
+// transaction, or whose hint matches the hint of a different attached signature, are
+// rejected. The signatures are not verified.
+func (t *Transaction) AddSignatureDecorated(signature ...xdr.DecoratedSignature) (*Transaction, error) {
+	extendedSignatures, err := concatDecoratedSignatures(t.signatures, false, signature...)
+	if err != nil {
+		return nil, err
+	}
+
+	newTx := new(Transaction)
+	*newTx = *t
+	newTx.signatures = extendedSignatures
+	return newTx, nil
+}
+
+// MergeSignatures returns a new Transaction instance which extends the current instance
+// with the signatures of the given copies of the same transaction, e.g. copies which
+// were signed by different parties. Signatures which are already attached to the
+// transaction are skipped. An error is returned if one of the copies is a different
+// transaction, or if two different signatures have the same hint.
+func (t *Transaction) MergeSignatures(others ...*Transaction) (*Transaction, error) {
+	extendedSignatures := t.signatures
+	for _, other := range others {
+		same, err := sameTransaction(t.envelope, other.envelope)
+		if err != nil {
+			return nil, err
+		}
+		if !same {
+			return nil, errors.New("cannot merge the signatures of a different transaction")
+		}
+
+		extendedSignatures, err = concatDecoratedSignatures(extendedSignatures, true, other.signatures...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newTx := new(Transaction)
+	*newTx = *t
+	newTx.signatures = extendedSignatures
+	return newTx, nil
+}
+
+// ClearSignatures returns a new Transaction instance which is identical to the current
+// instance without its signatures, e.g. to export the unsigned transaction to signers.
+func (t *Transaction) ClearSignatures() *Transaction {
+	newTx := new(Transaction)
+	*newTx = *t
+	newTx.signatures = nil
+	return newTx
+}
+
+// CloneWithFee returns a new unsigned Transaction instance which is identical to the current
+// instance except for its base fee, e.g. to submit again with a higher fee a transaction whose
+// fee is below the surge pricing fee. Both transactions have the same sequence number, so at most
+// one of them can be included in a ledger.
+func (t *Transaction) CloneWithFee(baseFee int64) (*Transaction, error) {
+	return t.rebuild(baseFee, t.timebounds)
+}
+
+// CloneWithTimebounds returns a new unsigned Transaction instance which is identical to the current
+// instance except for its time bounds, e.g. to submit again a transaction which expired before being
+// included in a ledger. Both transactions have the same sequence number, so at most one of them can
+// be included in a ledger.
+func (t *Transaction) CloneWithTimebounds(timebounds Timebounds) (*Transaction, error) {
+	return t.rebuild(t.baseFee, timebounds)
+}
+
+func (t *Transaction) rebuild(baseFee int64, timebounds Timebounds) (*Transaction, error) {
+	sourceAccount := t.sourceAccount
+	return NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    append([]Operation(nil), t.operations...),
+			BaseFee:       baseFee,
+			Memo:          t.memo,
+			Timebounds:    timebounds,
+		},
+	)
+}
+
 // TxEnvelope returns the a xdr.TransactionEnvelope instance which is
 // equivalent to this transaction.
 func (t *Transaction) TxEnvelope() (xdr.TransactionEnvelope, error) {
@@ -375,8 +566,17 @@ func (t *FeeBumpTransaction) HashHex(network string) (string, error) {
 
 // Sign returns a new FeeBumpTransaction instance which extends the current instance
 // with additional signatures derived from the given list of keypair instances.
+// Signing fails when the network passphrase is empty or is not the passphrase
+// of a registered network, see network.ValidatePassphrase.
 func (t *FeeBumpTransaction) Sign(network string, kps ...*keypair.Full) (*FeeBumpTransaction, error) {
-	extendedSignatures, err := concatSignatures(t.envelope, network, t.signatures, kps...)
+	return t.SignWith(network, keypairSigners(kps)...)
+}
+
+// SignWith returns a new FeeBumpTransaction instance which extends the current instance
+// with additional signatures produced by the given signers, e.g. hardware wallets or
+// KMS keys which do not expose their seed.
+func (t *FeeBumpTransaction) SignWith(network string, signers ...TransactionSigner) (*FeeBumpTransaction, error) {
+	extendedSignatures, err := concatSignatures(t.envelope, network, t.signatures, signers...)
 	if err != nil {
 		return nil, err
 	}
@@ -426,6 +626,60 @@ func (t *FeeBumpTransaction) AddSignatureBase64(network, publicKey, signature st
 	return newTx, nil
 }
 
+// AddSignatureDecorated returns a new FeeBumpTransaction instance which extends the current instance
+// with the given decorated signatures, e.g. detached signatures collected from signers
+// which do not share the same process. Signatures which are already attached to the
+// transaction, or whose hint matches the hint of a different attached signature, are
+// rejected. The signatures are not verified.
+func (t *FeeBumpTransaction) AddSignatureDecorated(signature ...xdr.DecoratedSignature) (*FeeBumpTransaction, error) {
+	extendedSignatures, err := concatDecoratedSignatures(t.signatures, false, signature...)
+	if err != nil {
+		return nil, err
+	}
+
+	newTx := new(FeeBumpTransaction)
+	*newTx = *t
+	newTx.signatures = extendedSignatures
+	return newTx, nil
+}
+
+// MergeSignatures returns a new FeeBumpTransaction instance which extends the current instance
+// with the signatures of the given copies of the same transaction, e.g. copies which
+// were signed by different parties. Signatures which are already attached to the
+// transaction are skipped. An error is returned if one of the copies is a different
+// transaction, or if two different signatures have the same hint.
+func (t *FeeBumpTransaction) MergeSignatures(others ...*FeeBumpTransaction) (*FeeBumpTransaction, error) {
+	extendedSignatures := t.signatures
+	for _, other := range others {
+		same, err := sameTransaction(t.envelope, other.envelope)
+		if err != nil {
+			return nil, err
+		}
+		if !same {
+			return nil, errors.New("cannot merge the signatures of a different transaction")
+		}
+
+		extendedSignatures, err = concatDecoratedSignatures(extendedSignatures, true, other.signatures...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newTx := new(FeeBumpTransaction)
+	*newTx = *t
+	newTx.signatures = extendedSignatures
+	return newTx, nil
+}
+
+// ClearSignatures returns a new FeeBumpTransaction instance which is identical to the current
+// instance without its signatures, e.g. to export the unsigned transaction to signers.
+func (t *FeeBumpTransaction) ClearSignatures() *FeeBumpTransaction {
+	newTx := new(FeeBumpTransaction)
+	*newTx = *t
+	newTx.signatures = nil
+	return newTx
+}
+
 // TxEnvelope returns the a xdr.TransactionEnvelope instance which is
 // equivalent to this transaction.
 func (t *FeeBumpTransaction) TxEnvelope() (xdr.TransactionEnvelope, error) {
@@ -495,7 +749,7 @@ func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope) (*GenericTransacti
 		if err != nil {
 			return newTx, errors.New("could not parse inner transaction")
 		}
-		feeBumpAccount := xdrEnv.FeeBumpAccount().ToAccountId()
+		feeBumpAccount := xdrEnv.FeeBumpAccount()
 		newTx.feeBump = &FeeBumpTransaction{
 			envelope: xdrEnv,
 			// A fee-bump transaction has an effective number of operations equal to one plus the
@@ -511,7 +765,7 @@ func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope) (*GenericTransacti
 		return newTx, nil
 	}
 
-	sourceAccount := xdrEnv.SourceAccount().ToAccountId()
+	sourceAccount := xdrEnv.SourceAccount()
 
 	totalFee := int64(xdrEnv.Fee())
 	baseFee := totalFee
@@ -554,6 +808,78 @@ func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope) (*GenericTransacti
 	return newTx, nil
 }
 
+// RoundTripCheck checks that no information of tx is lost when it is encoded
+// to XDR and decoded with TransactionFromXDR. The decoded source account,
+// operations, memo, time bounds and base fee are used to build a new
+// transaction, and an error is returned if its envelope, signatures included,
+// differs from the envelope of tx. The envelope type and the total fee of tx,
+// which NewTransaction does not take as parameters, are kept in the new
+// envelope, and the envelopes are compared with DiffEnvelopes.
+func RoundTripCheck(tx *Transaction) error {
+	txeB64, err := tx.Base64()
+	if err != nil {
+		return errors.Wrap(err, "could not encode transaction")
+	}
+
+	parsed, err := TransactionFromXDR(txeB64)
+	if err != nil {
+		return errors.Wrap(err, "could not decode transaction")
+	}
+	decoded, ok := parsed.Transaction()
+	if !ok {
+		return errors.New("transaction was decoded as a fee bump transaction")
+	}
+
+	sourceAccount := decoded.SourceAccount()
+	rebuilt, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    decoded.Operations(),
+			BaseFee:       decoded.BaseFee(),
+			Memo:          decoded.Memo(),
+			Timebounds:    decoded.Timebounds(),
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not build decoded transaction")
+	}
+
+	rebuiltEnvelope := rebuilt.envelope
+	if decoded.envelope.Type == xdr.EnvelopeTypeEnvelopeTypeTx && rebuiltEnvelope.Type == xdr.EnvelopeTypeEnvelopeTypeTxV0 {
+		rebuiltEnvelope, _, err = canonicalEnvelope(rebuiltEnvelope)
+		if err != nil {
+			return errors.Wrap(err, "could not convert decoded transaction to a v1 envelope")
+		}
+	}
+	switch rebuiltEnvelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
+		rebuiltEnvelope.V0.Tx.Fee = xdr.Uint32(decoded.MaxFee())
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		rebuiltEnvelope.V1.Tx.Fee = xdr.Uint32(decoded.MaxFee())
+	}
+	rebuiltEnvelope, err = cloneEnvelope(rebuiltEnvelope, decoded.Signatures())
+	if err != nil {
+		return errors.Wrap(err, "could not encode decoded transaction")
+	}
+
+	originalEnvelope, err := tx.TxEnvelope()
+	if err != nil {
+		return errors.Wrap(err, "could not encode transaction")
+	}
+	diffs, err := DiffEnvelopes(originalEnvelope, rebuiltEnvelope)
+	if err != nil {
+		return errors.Wrap(err, "could not compare transactions")
+	}
+	if len(diffs) > 0 {
+		lost := make([]string, len(diffs))
+		for i, diff := range diffs {
+			lost[i] = diff.String()
+		}
+		return errors.Errorf("transaction was rebuilt differently after decoding: %s", strings.Join(lost, ", "))
+	}
+	return nil
+}
+
 // TransactionParams is a container for parameters
 // which are used to construct new Transaction instances
 type TransactionParams struct {
@@ -595,12 +921,13 @@ func NewTransaction(params TransactionParams) (*Transaction, error) {
 		signatures: nil,
 	}
 
-	accountID, err := xdr.AddressToAccountId(tx.sourceAccount.AccountID)
+	sourceAccount, err := xdr.AddressToMuxedAccount(tx.sourceAccount.AccountID)
 	if err != nil {
 		return nil, errors.Wrap(err, "account id is not valid")
 	}
 
-	sourceAccountEd25519, ok := accountID.GetEd25519()
+	sourceAccountID := sourceAccount.ToAccountId()
+	sourceAccountEd25519, ok := sourceAccountID.GetEd25519()
 	if !ok {
 		return nil, errors.New("invalid account id")
 	}
@@ -613,6 +940,11 @@ func NewTransaction(params TransactionParams) (*Transaction, error) {
 	if len(tx.operations) == 0 {
 		return nil, errors.New("transaction has no operations")
 	}
+	if len(tx.operations) > MaxOperations {
+		return nil, errors.Errorf(
+			"transaction has %d operations, more than the maximum of %d", len(tx.operations), MaxOperations,
+		)
+	}
 
 	// check if maxFee fits in a uint32
 	// 64 bit fees are only available in fee bump transactions
@@ -646,6 +978,11 @@ func NewTransaction(params TransactionParams) (*Transaction, error) {
 	}
 
 	// Handle the memo, if one is present
+	if text, ok := tx.memo.(MemoText); ok && len(text) > MemoTextMaxLength {
+		return nil, errors.Errorf(
+			"memo text has %d bytes, more than the maximum of %d", len(text), MemoTextMaxLength,
+		)
+	}
 	if tx.memo != nil {
 		xdrMemo, err := tx.memo.ToXDR()
 		if err != nil {
@@ -666,6 +1003,23 @@ func NewTransaction(params TransactionParams) (*Transaction, error) {
 		envelope.V0.Tx.Operations = append(envelope.V0.Tx.Operations, xdrOperation)
 	}
 
+	// only v1 envelopes can have a muxed source account
+	if sourceAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+		envelope = xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: sourceAccount,
+					Fee:           envelope.V0.Tx.Fee,
+					SeqNum:        envelope.V0.Tx.SeqNum,
+					TimeBounds:    envelope.V0.Tx.TimeBounds,
+					Memo:          envelope.V0.Tx.Memo,
+					Operations:    envelope.V0.Tx.Operations,
+				},
+			},
+		}
+	}
+
 	tx.envelope = envelope
 	return tx, nil
 }
@@ -711,7 +1065,7 @@ func NewFeeBumpTransaction(params FeeBumpTransactionParams) (*FeeBumpTransaction
 		)
 	}
 
-	accountID, err := xdr.AddressToAccountId(tx.feeAccount)
+	feeAccount, err := xdr.AddressToMuxedAccount(tx.feeAccount)
 	if err != nil {
 		return tx, errors.Wrap(err, "fee account is not a valid address")
 	}
@@ -728,7 +1082,7 @@ func NewFeeBumpTransaction(params FeeBumpTransactionParams) (*FeeBumpTransaction
 		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
 		FeeBump: &xdr.FeeBumpTransactionEnvelope{
 			Tx: xdr.FeeBumpTransaction{
-				FeeSource: accountID.ToMuxedAccount(),
+				FeeSource: feeAccount,
 				Fee:       xdr.Int64(tx.maxFee),
 				InnerTx: xdr.FeeBumpTransactionInnerTx{
 					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
@@ -0,0 +1,17 @@
+package txnbuild
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionSigner signs transaction hashes with a key. It is implemented by
+// *keypair.Full, and can be implemented by keys whose seed is never exposed to
+// the process, like the keys of Ledger or Trezor devices, HSMs and cloud KMS.
+// See Transaction.SignWith.
+type TransactionSigner interface {
+	// Hint returns the last 4 bytes of the public key of the signer.
+	Hint() [4]byte
+	// SignDecorated signs the transaction hash txHash and returns the
+	// signature decorated with the hint of the signer.
+	SignDecorated(txHash []byte) (xdr.DecoratedSignature, error)
+}
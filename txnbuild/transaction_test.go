@@ -3,6 +3,7 @@ package txnbuild
 import (
 	"crypto/sha256"
 	"encoding/base64"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1317,6 +1319,327 @@ func TestFromXDR(t *testing.T) {
 	assert.Equal(t, "value", string(op2.Value), "Value should match")
 }
 
+func TestMuxedAccounts(t *testing.T) {
+	kp0 := newKeypair0()
+	muxedSource := "MDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKAAAAAAAAAAE2J43I"
+	muxedDestination := "MAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH4AAAAAAAAAAWFYJAM"
+	sourceAccount := NewSimpleAccount(muxedSource, int64(9605939170639897))
+
+	payment := Payment{
+		Destination:   muxedDestination,
+		Amount:        "10",
+		Asset:         NativeAsset{},
+		SourceAccount: &SimpleAccount{AccountID: muxedDestination},
+	}
+	accountMerge := AccountMerge{
+		Destination: muxedSource,
+	}
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&payment, &accountMerge},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.NoError(t, err)
+
+	// muxed accounts cannot be encoded in v0 envelopes
+	env, err := tx.TxEnvelope()
+	assert.NoError(t, err)
+	assert.Equal(t, xdr.EnvelopeTypeEnvelopeTypeTx, env.Type)
+	txSource := env.SourceAccount()
+	assert.Equal(t, muxedSource, txSource.Address())
+
+	b64, err := tx.Base64()
+	assert.NoError(t, err)
+	parsed, err := TransactionFromXDR(b64)
+	assert.NoError(t, err)
+	newTx, ok := parsed.Transaction()
+	assert.True(t, ok)
+
+	assert.Equal(t, muxedSource, newTx.SourceAccount().AccountID, "source accounts should match")
+	assert.Equal(t, int64(9605939170639898), newTx.SourceAccount().Sequence, "Sequence number should match")
+	assert.Len(t, newTx.Signatures(), 1)
+	if assert.Len(t, newTx.Operations(), 2) {
+		paymentOp, ok := newTx.Operations()[0].(*Payment)
+		assert.True(t, ok)
+		assert.Equal(t, muxedDestination, paymentOp.SourceAccount.GetAccountID(), "Operation source should match")
+		assert.Equal(t, muxedDestination, paymentOp.Destination, "Operation destination should match")
+		mergeOp, ok := newTx.Operations()[1].(*AccountMerge)
+		assert.True(t, ok)
+		assert.Equal(t, muxedSource, mergeOp.Destination, "Operation destination should match")
+	}
+}
+
+func TestRoundTripCheck(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	issuedAsset := CreditAsset{"ABCD", kp0.Address()}
+	operations := []Operation{
+		&CreateAccount{Destination: kp1.Address(), Amount: "10"},
+		&Payment{
+			Destination:   kp1.Address(),
+			Amount:        "10",
+			Asset:         issuedAsset,
+			SourceAccount: &SimpleAccount{AccountID: kp1.Address()},
+		},
+		&PathPaymentStrictReceive{
+			SendAsset:   NativeAsset{},
+			SendMax:     "10",
+			Destination: kp1.Address(),
+			DestAsset:   issuedAsset,
+			DestAmount:  "1",
+			Path:        []Asset{CreditAsset{"ABCDEFGH", kp1.Address()}},
+		},
+		&PathPaymentStrictSend{
+			SendAsset:   issuedAsset,
+			SendAmount:  "1",
+			Destination: kp1.Address(),
+			DestAsset:   NativeAsset{},
+			DestMin:     "10",
+			Path:        []Asset{},
+		},
+		&ManageSellOffer{Selling: issuedAsset, Buying: NativeAsset{}, Amount: "100", Price: "0.01"},
+		&ManageBuyOffer{Selling: NativeAsset{}, Buying: issuedAsset, Amount: "100", Price: "1.5", OfferID: 2},
+		&CreatePassiveSellOffer{Selling: issuedAsset, Buying: NativeAsset{}, Amount: "100", Price: "0.3333333"},
+		&SetOptions{
+			InflationDestination: NewInflationDestination(kp1.Address()),
+			SetFlags:             []AccountFlag{AuthRequired, AuthRevocable},
+			ClearFlags:           []AccountFlag{AuthImmutable},
+			MasterWeight:         NewThreshold(10),
+			LowThreshold:         NewThreshold(1),
+			MediumThreshold:      NewThreshold(2),
+			HighThreshold:        NewThreshold(3),
+			HomeDomain:           NewHomeDomain("stellar.org"),
+			Signer:               &Signer{Address: kp1.Address(), Weight: 4},
+		},
+		&ChangeTrust{Line: issuedAsset, Limit: "1000"},
+		&AllowTrust{Trustor: kp1.Address(), Type: issuedAsset, AuthorizeToMaintainLiabilities: true},
+		&AccountMerge{Destination: kp1.Address()},
+		&Inflation{SourceAccount: &SimpleAccount{AccountID: kp1.Address()}},
+		&ManageData{Name: "empty", Value: []byte{}},
+		&ManageData{Name: "cleared"},
+		&BumpSequence{BumpTo: 100},
+	}
+
+	for _, memo := range []Memo{
+		nil,
+		MemoText("round trip"),
+		MemoID(1234),
+		MemoHash{1, 2, 3},
+		MemoReturn{4, 5, 6},
+	} {
+		for _, timebounds := range []Timebounds{NewInfiniteTimeout(), NewTimebounds(1000, 2000)} {
+			sourceAccount := NewSimpleAccount(kp0.Address(), 9605939170639897)
+			tx, err := NewTransaction(
+				TransactionParams{
+					SourceAccount:        &sourceAccount,
+					IncrementSequenceNum: true,
+					Operations:           operations,
+					BaseFee:              MinBaseFee,
+					Memo:                 memo,
+					Timebounds:           timebounds,
+				},
+			)
+			assert.NoError(t, err)
+			tx, err = tx.Sign(network.TestNetworkPassphrase, kp0, kp1)
+			assert.NoError(t, err)
+
+			assert.NoError(t, RoundTripCheck(tx), "memo %v, timebounds %v", memo, timebounds)
+		}
+	}
+
+	// v1 envelopes with an account source are not rebuilt as v0 envelopes
+	sourceAccount := NewSimpleAccount(kp0.Address(), 9605939170639897)
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    operations,
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	convertToV1Tx(tx)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.NoError(t, err)
+	assert.NoError(t, RoundTripCheck(tx))
+
+	// fees which are not a multiple of the number of operations are kept
+	tx.envelope.V1.Tx.Fee = xdr.Uint32(len(operations)*MinBaseFee + 1)
+	assert.NoError(t, RoundTripCheck(tx))
+
+	_, err = operationFromXDR(xdr.Operation{Body: xdr.OperationBody{Type: 100}})
+	assert.EqualError(t, err, "unknown operation type: 100")
+}
+
+func TestMergeSignatures(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	unsigned, err := tx.Base64()
+	assert.NoError(t, err)
+
+	// every party signs its own copy of the exported transaction
+	signedCopy := func(kp *keypair.Full) *Transaction {
+		parsed, parseErr := TransactionFromXDR(unsigned)
+		assert.NoError(t, parseErr)
+		copyTx, ok := parsed.Transaction()
+		assert.True(t, ok)
+		copyTx, parseErr = copyTx.Sign(network.TestNetworkPassphrase, kp)
+		assert.NoError(t, parseErr)
+		return copyTx
+	}
+	copy0 := signedCopy(kp0)
+	copy1 := signedCopy(kp1)
+
+	merged, err := copy0.MergeSignatures(copy1, copy0)
+	assert.NoError(t, err)
+	assert.Len(t, merged.Signatures(), 2)
+	assert.Len(t, copy0.Signatures(), 1)
+
+	signed, err := tx.Sign(network.TestNetworkPassphrase, kp0, kp1)
+	assert.NoError(t, err)
+	expected, err := signed.Base64()
+	assert.NoError(t, err)
+	actual, err := merged.Base64()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	actual, err = merged.ClearSignatures().Base64()
+	assert.NoError(t, err)
+	assert.Equal(t, unsigned, actual)
+	assert.Len(t, merged.Signatures(), 2)
+
+	otherAccount := NewSimpleAccount(kp0.Address(), 2)
+	other, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &otherAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	other, err = other.Sign(network.TestNetworkPassphrase, kp1)
+	assert.NoError(t, err)
+	_, err = copy0.MergeSignatures(other)
+	assert.EqualError(t, err, "cannot merge the signatures of a different transaction")
+}
+
+func TestAddSignatureDecorated(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	signed, err := tx.Sign(network.TestNetworkPassphrase, kp0, kp1)
+	assert.NoError(t, err)
+	signatures := signed.Signatures()
+
+	withSignatures, err := tx.AddSignatureDecorated(signatures...)
+	assert.NoError(t, err)
+	assert.Equal(t, signatures, withSignatures.Signatures())
+	assert.Empty(t, tx.Signatures())
+
+	_, err = withSignatures.AddSignatureDecorated(signatures[1])
+	assert.EqualError(t, err, fmt.Sprintf("duplicate signature with hint %x", signatures[1].Hint))
+
+	_, err = tx.AddSignatureDecorated(signatures[0], signatures[0])
+	assert.EqualError(t, err, fmt.Sprintf("duplicate signature with hint %x", signatures[0].Hint))
+
+	conflicting := xdr.DecoratedSignature{
+		Hint:      signatures[0].Hint,
+		Signature: signatures[1].Signature,
+	}
+	_, err = withSignatures.AddSignatureDecorated(conflicting)
+	assert.EqualError(t, err, fmt.Sprintf("conflicting signatures with hint %x", signatures[0].Hint))
+}
+
+// deviceSigner is a TransactionSigner which does not expose its seed, like
+// a hardware wallet.
+type deviceSigner struct {
+	address string
+	hint    [4]byte
+	sign    func(txHash []byte) ([]byte, error)
+}
+
+func (s deviceSigner) Hint() [4]byte {
+	return s.hint
+}
+
+func (s deviceSigner) SignDecorated(txHash []byte) (xdr.DecoratedSignature, error) {
+	signature, err := s.sign(txHash)
+	if err != nil {
+		return xdr.DecoratedSignature{}, err
+	}
+	kp := keypair.MustParseAddress(s.address)
+	return xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(kp.Hint()),
+		Signature: xdr.Signature(signature),
+	}, nil
+}
+
+func TestSignWith(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+
+	device := deviceSigner{address: kp1.Address(), hint: kp1.Hint(), sign: kp1.Sign}
+	signedWith, err := tx.SignWith(network.TestNetworkPassphrase, kp0, device)
+	assert.NoError(t, err)
+	signed, err := tx.Sign(network.TestNetworkPassphrase, kp0, kp1)
+	assert.NoError(t, err)
+	expected, err := signed.Base64()
+	assert.NoError(t, err)
+	actual, err := signedWith.Base64()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	device.hint = kp0.Hint()
+	_, err = tx.SignWith(network.TestNetworkPassphrase, device)
+	assert.EqualError(t, err, fmt.Sprintf("signature hint %x does not match signer hint %x", kp1.Hint(), kp0.Hint()))
+
+	device.sign = func([]byte) ([]byte, error) {
+		return nil, errors.New("device disconnected")
+	}
+	_, err = tx.SignWith(network.TestNetworkPassphrase, device)
+	assert.EqualError(t, err, "failed to sign transaction: device disconnected")
+}
+
 func TestBuild(t *testing.T) {
 	kp0 := newKeypair0()
 	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
@@ -2945,3 +3268,163 @@ func TestVerifyTxSignatureInvalid(t *testing.T) {
 		assert.Contains(t, err.Error(), "transaction not signed by GATBMIXTHXYKSUZSZUEJKACZ2OS2IYUWP2AIF3CA32PIDLJ67CH6Y5UY")
 	}
 }
+
+func TestCloneWithFee(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&Inflation{}, &BumpSequence{BumpTo: 10}},
+			BaseFee:              MinBaseFee,
+			Memo:                 MemoText("retry"),
+			Timebounds:           NewTimebounds(0, 1000),
+		},
+	)
+	assert.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.NoError(t, err)
+
+	bumped, err := tx.CloneWithFee(1000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), bumped.BaseFee())
+	assert.Equal(t, int64(2000), bumped.MaxFee())
+	assert.Equal(t, tx.SourceAccount(), bumped.SourceAccount())
+	assert.Equal(t, int64(2), bumped.SourceAccount().Sequence)
+	assert.Equal(t, tx.Operations(), bumped.Operations())
+	assert.Equal(t, tx.Memo(), bumped.Memo())
+	assert.Equal(t, tx.Timebounds(), bumped.Timebounds())
+	assert.Empty(t, bumped.Signatures())
+
+	// the original transaction is not modified
+	assert.Equal(t, int64(MinBaseFee), tx.BaseFee())
+	assert.Len(t, tx.Signatures(), 1)
+
+	_, err = tx.CloneWithFee(MinBaseFee - 1)
+	assert.EqualError(t, err, "base fee cannot be lower than network minimum of 100")
+}
+
+func TestCloneWithTimebounds(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       MinBaseFee,
+			Memo:          MemoID(1),
+			Timebounds:    NewTimebounds(0, 1000),
+		},
+	)
+	assert.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.NoError(t, err)
+
+	extended, err := tx.CloneWithTimebounds(NewTimebounds(0, 2000))
+	assert.NoError(t, err)
+	assert.Equal(t, NewTimebounds(0, 2000), extended.Timebounds())
+	assert.Equal(t, tx.BaseFee(), extended.BaseFee())
+	assert.Equal(t, tx.SourceAccount(), extended.SourceAccount())
+	assert.Equal(t, tx.Operations(), extended.Operations())
+	assert.Equal(t, tx.Memo(), extended.Memo())
+	assert.Empty(t, extended.Signatures())
+	assert.Equal(t, NewTimebounds(0, 1000), tx.Timebounds())
+
+	_, err = tx.CloneWithTimebounds(Timebounds{MaxTime: 2000})
+	assert.EqualError(t, err, "invalid time bounds: timebounds must be constructed using NewTimebounds(), NewTimeout(), or NewInfiniteTimeout()")
+}
+
+func TestSignNetworkPassphrase(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+
+	_, err = tx.Sign("", kp0)
+	assert.EqualError(t, err, "invalid network passphrase: empty network passphrase")
+
+	_, err = tx.Sign("Test SDF Network ; September 2016", kp0)
+	assert.EqualError(t, err, `invalid network passphrase: network passphrase "Test SDF Network ; September 2016" is not registered and is close to the passphrase of network testnet, it must be registered with network.Register if it is not a typo`)
+
+	convertToV1Tx(tx)
+	feeBumpTx, err := NewFeeBumpTransaction(
+		FeeBumpTransactionParams{
+			Inner:      tx,
+			FeeAccount: newKeypair1().Address(),
+			BaseFee:    MinBaseFee,
+		},
+	)
+	assert.NoError(t, err)
+	_, err = feeBumpTx.Sign("Test SDF Network ; September 2016", newKeypair1())
+	assert.EqualError(t, err, `invalid network passphrase: network passphrase "Test SDF Network ; September 2016" is not registered and is close to the passphrase of network testnet, it must be registered with network.Register if it is not a typo`)
+
+	// standalone and private networks must be registered
+	standalone := "Standalone Network ; February 2017"
+	_, err = tx.Sign(standalone, kp0)
+	assert.EqualError(t, err, `invalid network passphrase: network passphrase "Standalone Network ; February 2017" is not registered, it must be registered with network.Register or allowed with network.AllowUnregistered`)
+	assert.NoError(t, network.Register("standalone", standalone))
+	signed, err := tx.Sign(standalone, kp0)
+	assert.NoError(t, err)
+	assert.Len(t, signed.Signatures(), 1)
+}
+
+func TestTransactionLimits(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+
+	operations := make([]Operation, MaxOperations+1)
+	for i := range operations {
+		operations[i] = &BumpSequence{BumpTo: int64(i)}
+	}
+	_, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    operations,
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.EqualError(t, err, "transaction has 101 operations, more than the maximum of 100")
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    operations[:MaxOperations],
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, tx.Operations(), MaxOperations)
+
+	for i := 0; i < MaxSignatures; i++ {
+		tx, err = tx.SignHashX([]byte{byte(i)})
+		assert.NoError(t, err)
+	}
+	_, err = tx.SignHashX([]byte{MaxSignatures})
+	assert.EqualError(t, err, "transaction has 21 signatures, more than the maximum of 20")
+	_, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.EqualError(t, err, "transaction has 21 signatures, more than the maximum of 20")
+
+	_, err = NewTransaction(
+		TransactionParams{
+			SourceAccount: &sourceAccount,
+			Operations:    []Operation{&BumpSequence{BumpTo: 1}},
+			BaseFee:       MinBaseFee,
+			Memo:          MemoText("this memo text is longer than 28 bytes"),
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.EqualError(t, err, "memo text has 38 bytes, more than the maximum of 28")
+}
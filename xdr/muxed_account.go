@@ -1,12 +1,22 @@
 package xdr
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 
 	"github.com/stellar/go/strkey"
 )
 
+// AddressToMuxedAccount returns a MuxedAccount for a given address string.
+// Both account (G...) and muxed account (M...) addresses are accepted.
+func AddressToMuxedAccount(address string) (MuxedAccount, error) {
+	result := MuxedAccount{}
+	err := result.SetAddress(address)
+
+	return result, err
+}
+
 // SetAddress modifies the receiver, setting it's value to the MuxedAccount form
 // of the provided address.
 func (m *MuxedAccount) SetAddress(address string) error {
@@ -27,12 +37,59 @@ func (m *MuxedAccount) SetAddress(address string) error {
 		copy(ui[:], raw)
 		*m, err = NewMuxedAccount(CryptoKeyTypeKeyTypeEd25519, ui)
 		return err
+	case 69:
+		raw, err := strkey.Decode(strkey.VersionByteMuxedAccount, address)
+		if err != nil {
+			return err
+		}
+		if len(raw) != 40 {
+			return errors.New("invalid muxed address")
+		}
+		var muxed MuxedAccountMed25519
+		copy(muxed.Ed25519[:], raw[:32])
+		muxed.Id = Uint64(binary.BigEndian.Uint64(raw[32:]))
+		*m, err = NewMuxedAccount(CryptoKeyTypeKeyTypeMuxedEd25519, muxed)
+		return err
 	default:
 		return errors.New("invalid address")
 	}
 
 }
 
+// Address returns the strkey encoded form of this MuxedAccount. This method
+// will panic if the MuxedAccount is backed by a key of an unknown type.
+func (m MuxedAccount) Address() string {
+	address, err := m.GetAddress()
+	if err != nil {
+		panic(err)
+	}
+	return address
+}
+
+// GetAddress returns the strkey encoded form of this MuxedAccount: a G...
+// address for plain accounts and an M... address for multiplexed accounts.
+func (m MuxedAccount) GetAddress() (string, error) {
+	switch m.Type {
+	case CryptoKeyTypeKeyTypeEd25519:
+		ed, ok := m.GetEd25519()
+		if !ok {
+			return "", fmt.Errorf("Could not get Ed25519")
+		}
+		return strkey.Encode(strkey.VersionByteAccountID, ed[:])
+	case CryptoKeyTypeKeyTypeMuxedEd25519:
+		med, ok := m.GetMed25519()
+		if !ok {
+			return "", fmt.Errorf("Could not get Med25519")
+		}
+		raw := make([]byte, 40)
+		copy(raw, med.Ed25519[:])
+		binary.BigEndian.PutUint64(raw[32:], uint64(med.Id))
+		return strkey.Encode(strkey.VersionByteMuxedAccount, raw)
+	default:
+		return "", fmt.Errorf("Unknown muxed account type: %v", m.Type)
+	}
+}
+
 // ToAccountId transforms a MuxedAccount to an AccountId, dropping the
 // memo Id if necessary
 func (m MuxedAccount) ToAccountId() AccountId {
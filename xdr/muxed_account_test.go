@@ -49,3 +49,30 @@ var _ = Describe("xdr.MuxedAccount.ToAccountId()", func() {
 		Expect(aid.Address()).To(Equal("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"))
 	})
 })
+
+var _ = Describe("xdr.MuxedAccount#Get/SetAddress() with muxed addresses", func() {
+	It("round trips M-addresses", func() {
+		muxed, err := AddressToMuxedAccount("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(muxed.Type).To(Equal(CryptoKeyTypeKeyTypeMuxedEd25519))
+		Expect(muxed.Med25519.Id).To(Equal(Uint64(9223372036854775808)))
+		Expect(muxed.Address()).To(Equal("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"))
+
+		aid := muxed.ToAccountId()
+		Expect(aid.Address()).To(Equal("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"))
+
+		muxed.Med25519.Id = 0xcafebabe
+		Expect(muxed.Address()).To(Equal("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAAMV7V2XYONY"))
+	})
+
+	It("returns G-addresses for plain accounts", func() {
+		muxed, err := AddressToMuxedAccount("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(muxed.Address()).To(Equal("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"))
+	})
+
+	It("rejects invalid M-addresses", func() {
+		_, err := AddressToMuxedAccount("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLL")
+		Expect(err).Should(HaveOccurred())
+	})
+})